@@ -1,6 +1,7 @@
 package api
 
 import (
+	"crypto"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -45,6 +46,27 @@ func (a *AccountService) NewEAB(accMsg acme.Account, kid, hmacEncoded string) (a
 	return a.New(accMsg)
 }
 
+// UpdateEAB Binds an existing account to an External Account (ex: new EAB credentials replacing expired ones).
+// RFC 8555 only defines the External Account Binding at the creation of an account:
+// the update of the binding is only supported by some CAs.
+func (a *AccountService) UpdateEAB(accountURL, kid, hmacEncoded string) (acme.Account, error) {
+	if accountURL == "" {
+		return acme.Account{}, errors.New("account[update-eab]: empty URL")
+	}
+
+	hmac, err := decodeEABHmac(hmacEncoded)
+	if err != nil {
+		return acme.Account{}, err
+	}
+
+	eabJWS, err := a.core.signEABContent(accountURL, kid, hmac)
+	if err != nil {
+		return acme.Account{}, fmt.Errorf("acme: error signing eab content: %w", err)
+	}
+
+	return a.Update(accountURL, acme.Account{ExternalAccountBinding: eabJWS})
+}
+
 // Get Retrieves an account.
 func (a *AccountService) Get(accountURL string) (acme.Account, error) {
 	if accountURL == "" {
@@ -77,6 +99,69 @@ func (a *AccountService) Update(accountURL string, req acme.Account) (acme.Accou
 	return account, nil
 }
 
+// ListOrders Retrieves the URLs of the orders of an account.
+// The pages of the list are followed through the "next" links.
+func (a *AccountService) ListOrders(ordersURL string) ([]string, error) {
+	if ordersURL == "" {
+		return nil, errors.New("account[orders]: empty URL")
+	}
+
+	var orders []string
+
+	visited := make(map[string]struct{})
+
+	for uri := ordersURL; uri != ""; {
+		// Protects against a loop in the "next" links.
+		if _, ok := visited[uri]; ok {
+			break
+		}
+
+		visited[uri] = struct{}{}
+
+		var list acme.OrdersList
+
+		resp, err := a.core.postAsGet(uri, &list)
+		if err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, list.Orders...)
+
+		uri = getLink(resp.Header, "next")
+	}
+
+	return orders, nil
+}
+
+// ChangeKey Replaces the key of an account (key rollover).
+// The request is signed by the current key, and contains a JWS signed by the new key.
+// The new key is used for the next requests.
+// https://www.rfc-editor.org/rfc/rfc8555.html#section-7.3.5
+func (a *AccountService) ChangeKey(accountURL string, newKey crypto.PrivateKey) error {
+	if accountURL == "" {
+		return errors.New("account[key-change]: empty URL")
+	}
+
+	keyChangeURL := a.core.GetDirectory().KeyChangeURL
+	if keyChangeURL == "" {
+		return errors.New("account[key-change]: the ACME server doesn't support the key rollover")
+	}
+
+	content, err := a.core.signKeyChangeContent(keyChangeURL, accountURL, newKey)
+	if err != nil {
+		return fmt.Errorf("acme: error signing key change content: %w", err)
+	}
+
+	_, err = a.core.retrievablePost(keyChangeURL, content, nil)
+	if err != nil {
+		return err
+	}
+
+	a.core.jws.SetPrivateKey(newKey)
+
+	return nil
+}
+
 // Deactivate Deactivates an account.
 func (a *AccountService) Deactivate(accountURL string) error {
 	if accountURL == "" {
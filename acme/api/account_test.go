@@ -1,8 +1,17 @@
 package api
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"testing"
 
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/go-acme/lego/v4/platform/tester/servermock"
+	"github.com/go-jose/go-jose/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -33,3 +42,211 @@ func Test_decodeEABHmac(t *testing.T) {
 		})
 	}
 }
+
+func TestAccountService_ListOrders(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	server := tester.MockACMEServer().
+		Route("POST /orders",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				baseURL := fmt.Sprintf("https://%s", req.Context().Value(http.LocalAddrContextKey))
+
+				switch req.URL.Query().Get("cursor") {
+				case "":
+					rw.Header().Set("Link", fmt.Sprintf(`<%s/orders?cursor=2>;rel="next"`, baseURL))
+					servermock.JSONEncode(acme.OrdersList{Orders: []string{baseURL + "/order/1", baseURL + "/order/2"}}).ServeHTTP(rw, req)
+
+				case "2":
+					// loop on the first page.
+					rw.Header().Set("Link", fmt.Sprintf(`<%s/orders>;rel="next"`, baseURL))
+					servermock.JSONEncode(acme.OrdersList{Orders: []string{baseURL + "/order/3"}}).ServeHTTP(rw, req)
+
+				default:
+					http.NotFound(rw, req)
+				}
+			})).
+		BuildHTTPS(t)
+
+	core, err := New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	orders, err := core.Accounts.ListOrders(server.URL + "/orders")
+	require.NoError(t, err)
+
+	expected := []string{
+		server.URL + "/order/1",
+		server.URL + "/order/2",
+		server.URL + "/order/3",
+	}
+
+	assert.Equal(t, expected, orders)
+}
+
+func TestAccountService_ListOrders_emptyURL(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	core, err := New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	_, err = core.Accounts.ListOrders("")
+	require.EqualError(t, err, "account[orders]: empty URL")
+}
+
+func TestAccountService_UpdateEAB(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	hmacEncoded := "nKTo9Hu8fpCqWPXx-25LVbZrJWxcHISsr4qHrRR0j5U="
+
+	hmac, err := decodeEABHmac(hmacEncoded)
+	require.NoError(t, err)
+
+	server := tester.MockACMEServer().
+		Route("POST /account/1",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := readSignedBody(req, privateKey)
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				var account acme.Account
+
+				err = json.Unmarshal(body, &account)
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				eab, err := jose.ParseSigned(string(account.ExternalAccountBinding), []jose.SignatureAlgorithm{jose.HS256})
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				_, err = eab.Verify(hmac)
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusUnauthorized)
+					return
+				}
+
+				header := eab.Signatures[0].Protected
+
+				accountURL := fmt.Sprintf("https://%s/account/1", req.Context().Value(http.LocalAddrContextKey))
+
+				if header.KeyID != "kid-2" || header.ExtraHeaders["url"] != accountURL {
+					http.Error(rw, fmt.Sprintf("unexpected header: %s %v", header.KeyID, header.ExtraHeaders["url"]), http.StatusBadRequest)
+					return
+				}
+
+				servermock.JSONEncode(acme.Account{Status: acme.StatusValid}).ServeHTTP(rw, req)
+			})).
+		BuildHTTPS(t)
+
+	core, err := New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	account, err := core.Accounts.UpdateEAB(server.URL+"/account/1", "kid-2", hmacEncoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, acme.StatusValid, account.Status)
+}
+
+func TestAccountService_ChangeKey(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	newKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	server := tester.MockACMEServer().
+		Route("POST /keyChange",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				// The outer JWS is signed by the old key.
+				body, err := readSignedBody(req, oldKey)
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				inner, err := jose.ParseSigned(string(body), []jose.SignatureAlgorithm{jose.RS256})
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				// The inner JWS is signed by the new key, embedded in the header.
+				header := inner.Signatures[0].Protected
+
+				if header.JSONWebKey == nil || header.Nonce != "" {
+					http.Error(rw, "unexpected inner header", http.StatusBadRequest)
+					return
+				}
+
+				payload, err := inner.Verify(header.JSONWebKey)
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusUnauthorized)
+					return
+				}
+
+				var keyChange struct {
+					Account string          `json:"account"`
+					OldKey  jose.JSONWebKey `json:"oldKey"`
+				}
+
+				err = json.Unmarshal(payload, &keyChange)
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				accountURL := fmt.Sprintf("https://%s/account/1", req.Context().Value(http.LocalAddrContextKey))
+
+				if keyChange.Account != accountURL || !oldKey.PublicKey.Equal(keyChange.OldKey.Key) || !newKey.PublicKey.Equal(header.JSONWebKey.Key) {
+					http.Error(rw, "unexpected key change content", http.StatusBadRequest)
+					return
+				}
+
+				rw.WriteHeader(http.StatusOK)
+			})).
+		Route("POST /account/1",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				// The next requests are signed by the new key.
+				_, err := readSignedBody(req, newKey)
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusUnauthorized)
+					return
+				}
+
+				servermock.JSONEncode(acme.Account{Status: acme.StatusValid}).ServeHTTP(rw, req)
+			})).
+		BuildHTTPS(t)
+
+	core, err := New(server.Client(), "lego-test", server.URL+"/dir", server.URL+"/account/1", oldKey)
+	require.NoError(t, err)
+
+	err = core.Accounts.ChangeKey(server.URL+"/account/1", newKey)
+	require.NoError(t, err)
+
+	account, err := core.Accounts.Get(server.URL + "/account/1")
+	require.NoError(t, err)
+
+	assert.Equal(t, acme.StatusValid, account.Status)
+}
+
+func TestAccountService_ChangeKey_unsupported(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	core := newCore(nil, acme.Directory{}, "", privateKey, nil)
+
+	err = core.Accounts.ChangeKey("https://example.com/account/1", privateKey)
+	require.EqualError(t, err, "account[key-change]: the ACME server doesn't support the key rollover")
+
+	err = core.Accounts.ChangeKey("", privateKey)
+	require.EqualError(t, err, "account[key-change]: empty URL")
+}
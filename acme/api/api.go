@@ -24,6 +24,8 @@ type Core struct {
 	nonceManager *nonces.Manager
 	jws          *secure.JWS
 	directory    acme.Directory
+	strict       *strictValidator
+	rawJSON      bool
 	HTTPClient   *http.Client
 
 	common         service // Reuse a single struct instead of allocating one for each service on the heap.
@@ -43,6 +45,10 @@ func New(httpClient *http.Client, userAgent, caDirURL, kid string, privateKey cr
 		return nil, err
 	}
 
+	return newCore(doer, dir, kid, privateKey, httpClient), nil
+}
+
+func newCore(doer *sender.Doer, dir acme.Directory, kid string, privateKey crypto.PrivateKey, httpClient *http.Client) *Core {
 	nonceManager := nonces.NewManager(doer, dir.NewNonceURL)
 
 	jws := secure.NewJWS(privateKey, kid, nonceManager)
@@ -56,7 +62,7 @@ func New(httpClient *http.Client, userAgent, caDirURL, kid string, privateKey cr
 	c.Challenges = (*ChallengeService)(&c.common)
 	c.Orders = (*OrderService)(&c.common)
 
-	return c, nil
+	return c
 }
 
 // post performs an HTTP POST request and parses the response body as JSON,
@@ -117,7 +123,17 @@ func (a *Core) signedPost(uri string, content []byte, response any) (*http.Respo
 
 	signedBody := bytes.NewBufferString(signedContent.FullSerialize())
 
-	resp, err := a.doer.Post(uri, signedBody, "application/jose+json", response)
+	var raw json.RawMessage
+
+	target := response
+	if a.rawJSON && response != nil {
+		target = &raw
+	}
+
+	resp, err := a.doer.Post(uri, signedBody, "application/jose+json", target)
+	if err == nil && target == &raw {
+		err = decodeRaw(raw, response)
+	}
 
 	// nonceErr is ignored to keep the root error.
 	nonce, nonceErr := nonces.GetFromResponse(resp)
@@ -137,11 +153,25 @@ func (a *Core) signEABContent(newAccountURL, kid string, hmac []byte) ([]byte, e
 	return []byte(eabJWS.FullSerialize()), nil
 }
 
+func (a *Core) signKeyChangeContent(keyChangeURL, accountURL string, newKey crypto.PrivateKey) ([]byte, error) {
+	keyChangeJWS, err := a.jws.SignKeyChangeContent(keyChangeURL, accountURL, newKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(keyChangeJWS.FullSerialize()), nil
+}
+
 // GetKeyAuthorization Gets the key authorization.
 func (a *Core) GetKeyAuthorization(token string) (string, error) {
 	return a.jws.GetKeyAuthorization(token)
 }
 
+// GetKeyThumbprint Gets the JWK thumbprint (RFC 7638) of the account key, base64url-encoded.
+func (a *Core) GetKeyThumbprint() (string, error) {
+	return a.jws.GetKeyThumbprint()
+}
+
 func (a *Core) GetDirectory() acme.Directory {
 	return a.directory
 }
@@ -149,16 +179,12 @@ func (a *Core) GetDirectory() acme.Directory {
 func getDirectory(do *sender.Doer, caDirURL string) (acme.Directory, error) {
 	var dir acme.Directory
 	if _, err := do.Get(caDirURL, &dir); err != nil {
-		return dir, fmt.Errorf("get directory at '%s': %w", caDirURL, err)
+		return dir, wrapDirectoryError(caDirURL, err)
 	}
 
-	if dir.NewAccountURL == "" {
-		return dir, errors.New("directory missing new registration URL")
-	}
-
-	if dir.NewOrderURL == "" {
-		return dir, errors.New("directory missing new order URL")
-	}
+	return dir, validateDirectory(dir)
+}
 
-	return dir, nil
+func wrapDirectoryError(caDirURL string, err error) error {
+	return fmt.Errorf("get directory at '%s': %w", caDirURL, err)
 }
@@ -21,6 +21,8 @@ func (c *AuthorizationService) Get(authzURL string) (acme.Authorization, error)
 		return acme.Authorization{}, err
 	}
 
+	c.core.strict.checkAuthorization(authzURL, authz)
+
 	return authz, nil
 }
 
@@ -13,6 +13,9 @@ import (
 // maxBodySize is the maximum size of body that we will read.
 const maxBodySize = 1024 * 1024
 
+// ErrCertificateNotReady is returned when the certificate is not available yet at the certificate URL.
+var ErrCertificateNotReady = errors.New("certificate[get]: the certificate is not available yet")
+
 type CertificateService service
 
 // Get Returns the certificate and the issuer certificate.
@@ -74,6 +77,11 @@ func (c *CertificateService) get(certURL string, bundle bool) (*acme.RawCertific
 		return nil, resp.Header, err
 	}
 
+	// Some CAs answer with a 202 (or an empty body) right after the finalization of the order.
+	if resp.StatusCode == http.StatusAccepted || len(bytes.TrimSpace(data)) == 0 {
+		return nil, resp.Header, ErrCertificateNotReady
+	}
+
 	cert := c.getCertificateChain(data, bundle)
 
 	return cert, resp.Header, err
@@ -10,15 +10,20 @@ type ChallengeService service
 
 // New Creates a challenge.
 func (c *ChallengeService) New(chlgURL string) (acme.ExtendedChallenge, error) {
+	// Challenge initiation is done by sending a JWS payload containing the trivial JSON object `{}`.
+	// We use an empty struct instance as the postJSON payload here to achieve this result.
+	return c.NewWithPayload(chlgURL, struct{}{})
+}
+
+// NewWithPayload Creates a challenge with a challenge-specific response (e.g. the CSR of the onion-csr-01 challenge).
+func (c *ChallengeService) NewWithPayload(chlgURL string, payload any) (acme.ExtendedChallenge, error) {
 	if chlgURL == "" {
 		return acme.ExtendedChallenge{}, errors.New("challenge[new]: empty URL")
 	}
 
-	// Challenge initiation is done by sending a JWS payload containing the trivial JSON object `{}`.
-	// We use an empty struct instance as the postJSON payload here to achieve this result.
 	var chlng acme.ExtendedChallenge
 
-	resp, err := c.core.post(chlgURL, struct{}{}, &chlng)
+	resp, err := c.core.post(chlgURL, payload, &chlng)
 	if err != nil {
 		return acme.ExtendedChallenge{}, err
 	}
@@ -26,6 +31,8 @@ func (c *ChallengeService) New(chlgURL string) (acme.ExtendedChallenge, error) {
 	chlng.AuthorizationURL = getLink(resp.Header, "up")
 	chlng.RetryAfter = getRetryAfter(resp)
 
+	c.core.strict.checkChallenge(chlng.Challenge)
+
 	return chlng, nil
 }
 
@@ -45,5 +52,7 @@ func (c *ChallengeService) Get(chlgURL string) (acme.ExtendedChallenge, error) {
 	chlng.AuthorizationURL = getLink(resp.Header, "up")
 	chlng.RetryAfter = getRetryAfter(resp)
 
+	c.core.strict.checkChallenge(chlng.Challenge)
+
 	return chlng, nil
 }
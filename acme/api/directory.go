@@ -0,0 +1,153 @@
+package api
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api/internal/sender"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// directoryRetryTimeout the maximum duration of the retries of the directory fetching in the cached-directory mode.
+const directoryRetryTimeout = time.Minute
+
+// directories the directories fetched by the process, by URL.
+var directories = &directoryCache{entries: make(map[string]directoryCacheEntry)}
+
+type directoryCacheEntry struct {
+	directory acme.Directory
+	expires   time.Time
+}
+
+type directoryCache struct {
+	mu      sync.Mutex
+	entries map[string]directoryCacheEntry
+}
+
+func (c *directoryCache) load(caDirURL string) (directoryCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[caDirURL]
+
+	return entry, ok
+}
+
+func (c *directoryCache) store(caDirURL string, dir acme.Directory, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[caDirURL] = directoryCacheEntry{directory: dir, expires: time.Now().Add(ttl)}
+}
+
+// NewWithDirectoryCache is like New, but the directory is cached by the process during the TTL
+// (the clients created during the TTL don't fetch the directory).
+// The fetching of the directory is retried with a backoff on the network errors and on the 429 and 5xx responses,
+// and if the ACME server is still unreachable, the expired directory is used, if any.
+// A TTL lower than or equal to 0 is the same as New.
+func NewWithDirectoryCache(httpClient *http.Client, userAgent, caDirURL, kid string, privateKey crypto.PrivateKey, ttl time.Duration) (*Core, error) {
+	if ttl <= 0 {
+		return New(httpClient, userAgent, caDirURL, kid, privateKey)
+	}
+
+	doer := sender.NewDoer(httpClient, userAgent)
+
+	dir, err := getCachedDirectory(doer, caDirURL, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCore(doer, dir, kid, privateKey, httpClient), nil
+}
+
+// NewWithDirectory is like New, but the endpoints of the ACME server are provided instead of being discovered:
+// the directory is not fetched.
+// It's intended for the ACME servers exposing fixed endpoints without a directory document (ex: some embedded CAs).
+// The newNonce, newAccount, and newOrder URLs are required.
+func NewWithDirectory(httpClient *http.Client, userAgent string, dir acme.Directory, kid string, privateKey crypto.PrivateKey) (*Core, error) {
+	if dir.NewNonceURL == "" {
+		return nil, errors.New("directory missing new nonce URL")
+	}
+
+	err := validateDirectory(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCore(sender.NewDoer(httpClient, userAgent), dir, kid, privateKey, httpClient), nil
+}
+
+func getCachedDirectory(do *sender.Doer, caDirURL string, ttl time.Duration) (acme.Directory, error) {
+	entry, cached := directories.load(caDirURL)
+	if cached && time.Now().Before(entry.expires) {
+		return entry.directory, nil
+	}
+
+	dir, err := getDirectoryWithRetry(do, caDirURL)
+	if err != nil {
+		if cached {
+			log.Warnf("acme: the ACME server is unreachable, using the expired directory: %v", err)
+
+			return entry.directory, nil
+		}
+
+		return dir, err
+	}
+
+	directories.store(caDirURL, dir, ttl)
+
+	return dir, nil
+}
+
+func getDirectoryWithRetry(do *sender.Doer, caDirURL string) (acme.Directory, error) {
+	bo := backoff.NewExponentialBackOff()
+	bo.InitialInterval = time.Second
+	bo.MaxInterval = 10 * time.Second
+
+	operation := func() (acme.Directory, error) {
+		var dir acme.Directory
+
+		resp, err := do.Get(caDirURL, &dir)
+		if err != nil {
+			if resp == nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+				return dir, err
+			}
+
+			return dir, backoff.Permanent(err)
+		}
+
+		return dir, nil
+	}
+
+	notify := func(err error, duration time.Duration) {
+		log.Infof("acme: retry the fetching of the directory in %s: %v", duration, err)
+	}
+
+	dir, err := backoff.Retry(context.Background(), operation,
+		backoff.WithBackOff(bo),
+		backoff.WithMaxElapsedTime(directoryRetryTimeout),
+		backoff.WithNotify(notify))
+	if err != nil {
+		return dir, wrapDirectoryError(caDirURL, err)
+	}
+
+	return dir, validateDirectory(dir)
+}
+
+func validateDirectory(dir acme.Directory) error {
+	if dir.NewAccountURL == "" {
+		return errors.New("directory missing new registration URL")
+	}
+
+	if dir.NewOrderURL == "" {
+		return errors.New("directory missing new order URL")
+	}
+
+	return nil
+}
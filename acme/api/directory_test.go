@@ -0,0 +1,161 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupDirectoryServer(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if calls.Add(1) <= failures {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		servermock.JSONEncode(acme.Directory{
+			NewNonceURL:   "https://" + req.Host + "/nonce",
+			NewAccountURL: "https://" + req.Host + "/account",
+			NewOrderURL:   "https://" + req.Host + "/order",
+		}).ServeHTTP(rw, req)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func TestNewWithDirectoryCache(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	server, calls := setupDirectoryServer(t, 1)
+
+	core, err := NewWithDirectoryCache(server.Client(), "lego-test", server.URL+"/dir", "", privateKey, time.Hour)
+	require.NoError(t, err)
+
+	assert.Equal(t, server.URL+"/order", core.GetDirectory().NewOrderURL)
+
+	// The first fetching fails: the directory is fetched twice.
+	assert.EqualValues(t, 2, calls.Load())
+
+	_, err = NewWithDirectoryCache(server.Client(), "lego-test", server.URL+"/dir", "", privateKey, time.Hour)
+	require.NoError(t, err)
+
+	// The directory is cached.
+	assert.EqualValues(t, 2, calls.Load())
+}
+
+func TestNewWithDirectoryCache_expired(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+
+	caDirURL := server.URL + "/dir"
+
+	expected := acme.Directory{NewAccountURL: "https://example.com/account", NewOrderURL: "https://example.com/order"}
+
+	directories.store(caDirURL, expected, -time.Minute)
+
+	core, err := NewWithDirectoryCache(server.Client(), "lego-test", caDirURL, "", privateKey, time.Hour)
+	require.NoError(t, err)
+
+	// The ACME server fails: the expired directory is used.
+	assert.Equal(t, expected, core.GetDirectory())
+}
+
+func TestNewWithDirectoryCache_error(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+
+	_, err = NewWithDirectoryCache(server.Client(), "lego-test", server.URL+"/dir", "", privateKey, time.Hour)
+	require.ErrorContains(t, err, "get directory at '"+server.URL+"/dir'")
+}
+
+func TestNewWithDirectory(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	var calls atomic.Int32
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls.Add(1)
+
+		rw.Header().Set("Replay-Nonce", "12345")
+		rw.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	dir := acme.Directory{
+		NewNonceURL:   server.URL + "/nonce",
+		NewAccountURL: server.URL + "/account",
+		NewOrderURL:   server.URL + "/order",
+	}
+
+	core, err := NewWithDirectory(server.Client(), "lego-test", dir, "", privateKey)
+	require.NoError(t, err)
+
+	assert.Equal(t, dir, core.GetDirectory())
+
+	// The directory is not fetched.
+	assert.Zero(t, calls.Load())
+
+	nonce, err := core.nonceManager.Nonce()
+	require.NoError(t, err)
+
+	assert.Equal(t, "12345", nonce)
+	assert.EqualValues(t, 1, calls.Load())
+}
+
+func TestNewWithDirectory_error(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		dir      acme.Directory
+		expected string
+	}{
+		{
+			desc:     "missing new nonce URL",
+			dir:      acme.Directory{NewAccountURL: "https://example.com/account", NewOrderURL: "https://example.com/order"},
+			expected: "directory missing new nonce URL",
+		},
+		{
+			desc:     "missing new account URL",
+			dir:      acme.Directory{NewNonceURL: "https://example.com/nonce", NewOrderURL: "https://example.com/order"},
+			expected: "directory missing new registration URL",
+		},
+		{
+			desc:     "missing new order URL",
+			dir:      acme.Directory{NewNonceURL: "https://example.com/nonce", NewAccountURL: "https://example.com/account"},
+			expected: "directory missing new order URL",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewWithDirectory(http.DefaultClient, "lego-test", test.dir, "", privateKey)
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
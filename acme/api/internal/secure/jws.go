@@ -5,18 +5,25 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rsa"
-	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"sync"
 
 	"github.com/go-acme/lego/v4/acme/api/internal/nonces"
+	"github.com/go-acme/lego/v4/challenge"
 	jose "github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/cryptosigner"
 )
 
 // JWS Represents a JWS.
+// A JWS is safe for concurrent use.
 type JWS struct {
-	privKey crypto.PrivateKey
-	kid     string // Key identifier
-	nonces  *nonces.Manager
+	privKey   crypto.PrivateKey
+	privKeyMu sync.RWMutex
+	nonces    *nonces.Manager
+
+	kid   string // Key identifier
+	kidMu sync.RWMutex
 }
 
 // NewJWS Create a new JWS.
@@ -30,27 +37,43 @@ func NewJWS(privateKey crypto.PrivateKey, kid string, nonceManager *nonces.Manag
 
 // SetKid Sets a key identifier.
 func (j *JWS) SetKid(kid string) {
+	j.kidMu.Lock()
+	defer j.kidMu.Unlock()
+
 	j.kid = kid
 }
 
+func (j *JWS) getKid() string {
+	j.kidMu.RLock()
+	defer j.kidMu.RUnlock()
+
+	return j.kid
+}
+
+// SetPrivateKey Sets the private key (ex: after an account key rollover).
+func (j *JWS) SetPrivateKey(privateKey crypto.PrivateKey) {
+	j.privKeyMu.Lock()
+	defer j.privKeyMu.Unlock()
+
+	j.privKey = privateKey
+}
+
+func (j *JWS) getPrivateKey() crypto.PrivateKey {
+	j.privKeyMu.RLock()
+	defer j.privKeyMu.RUnlock()
+
+	return j.privKey
+}
+
 // SignContent Signs a content with the JWS.
 func (j *JWS) SignContent(url string, content []byte) (*jose.JSONWebSignature, error) {
-	var alg jose.SignatureAlgorithm
+	alg, key := signingKey(j.getPrivateKey())
 
-	switch k := j.privKey.(type) {
-	case *rsa.PrivateKey:
-		alg = jose.RS256
-	case *ecdsa.PrivateKey:
-		if k.Curve == elliptic.P256() {
-			alg = jose.ES256
-		} else if k.Curve == elliptic.P384() {
-			alg = jose.ES384
-		}
-	}
+	kid := j.getKid()
 
 	signKey := jose.SigningKey{
 		Algorithm: alg,
-		Key:       jose.JSONWebKey{Key: j.privKey, KeyID: j.kid},
+		Key:       jose.JSONWebKey{Key: key, KeyID: kid},
 	}
 
 	options := jose.SignerOptions{
@@ -60,7 +83,7 @@ func (j *JWS) SignContent(url string, content []byte) (*jose.JSONWebSignature, e
 		},
 	}
 
-	if j.kid == "" {
+	if kid == "" {
 		options.EmbedJWK = true
 	}
 
@@ -79,9 +102,7 @@ func (j *JWS) SignContent(url string, content []byte) (*jose.JSONWebSignature, e
 
 // SignEABContent Signs an external account binding content with the JWS.
 func (j *JWS) SignEABContent(url, kid string, hmac []byte) (*jose.JSONWebSignature, error) {
-	jwk := jose.JSONWebKey{Key: j.privKey}
-
-	jwkJSON, err := jwk.Public().MarshalJSON()
+	jwkJSON, err := publicJWK(j.getPrivateKey()).MarshalJSON()
 	if err != nil {
 		return nil, fmt.Errorf("acme: error encoding eab jwk key: %w", err)
 	}
@@ -108,27 +129,95 @@ func (j *JWS) SignEABContent(url, kid string, hmac []byte) (*jose.JSONWebSignatu
 	return signed, nil
 }
 
+// SignKeyChangeContent Signs the inner JWS of an account key rollover with the new key.
+// The payload contains the account URL and the current (old) key.
+// https://www.rfc-editor.org/rfc/rfc8555.html#section-7.3.5
+func (j *JWS) SignKeyChangeContent(url, accountURL string, newKey crypto.PrivateKey) (*jose.JSONWebSignature, error) {
+	payload, err := json.Marshal(struct {
+		Account string          `json:"account"`
+		OldKey  jose.JSONWebKey `json:"oldKey"`
+	}{
+		Account: accountURL,
+		OldKey:  publicJWK(j.getPrivateKey()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("acme: error encoding key change content: %w", err)
+	}
+
+	alg, key := signingKey(newKey)
+
+	// The inner JWS has no nonce and embeds the new key.
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: alg, Key: key},
+		&jose.SignerOptions{
+			EmbedJWK: true,
+			ExtraHeaders: map[jose.HeaderKey]any{
+				"url": url,
+			},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key change jose signer: %w", err)
+	}
+
+	signed, err := signer.Sign(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign key change content: %w", err)
+	}
+
+	return signed, nil
+}
+
 // GetKeyAuthorization Gets the key authorization for a token.
 func (j *JWS) GetKeyAuthorization(token string) (string, error) {
-	var publicKey crypto.PublicKey
+	return challenge.KeyAuthorization(j.getPrivateKey(), token)
+}
 
-	switch k := j.privKey.(type) {
-	case *ecdsa.PrivateKey:
-		publicKey = k.Public()
+// GetKeyThumbprint Gets the JWK thumbprint of the account key.
+func (j *JWS) GetKeyThumbprint() (string, error) {
+	return challenge.Thumbprint(j.getPrivateKey())
+}
+
+// signingKey returns the signature algorithm and the signing key of a private key.
+func signingKey(privateKey crypto.PrivateKey) (jose.SignatureAlgorithm, any) {
+	switch k := privateKey.(type) {
 	case *rsa.PrivateKey:
-		publicKey = k.Public()
-	}
+		return jose.RS256, k
+	case *ecdsa.PrivateKey:
+		if k.Curve == elliptic.P256() {
+			return jose.ES256, k
+		} else if k.Curve == elliptic.P384() {
+			return jose.ES384, k
+		}
 
-	// Generate the Key Authorization for the challenge
-	jwk := &jose.JSONWebKey{Key: publicKey}
+		return "", k
+	case crypto.Signer:
+		// The private key is not available (ex: a key stored inside a KMS or Vault).
+		opaque := cryptosigner.Opaque(k)
 
-	thumbBytes, err := jwk.Thumbprint(crypto.SHA256)
-	if err != nil {
-		return "", err
+		var alg jose.SignatureAlgorithm
+		if algs := opaque.Algs(); len(algs) > 0 {
+			alg = algs[0]
+		}
+
+		return alg, opaque
+	default:
+		return "", k
 	}
+}
 
-	// unpad the base64URL
-	keyThumb := base64.RawURLEncoding.EncodeToString(thumbBytes)
+// publicJWK returns the public JWK of a private key.
+func publicJWK(privateKey crypto.PrivateKey) jose.JSONWebKey {
+	var jwk jose.JSONWebKey
+
+	switch k := privateKey.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey:
+		jwk = jose.JSONWebKey{Key: k}
+	case crypto.Signer:
+		jwk = jose.JSONWebKey{Key: k.Public()}
+	default:
+		jwk = jose.JSONWebKey{Key: k}
+	}
 
-	return token + "." + keyThumb, nil
+	return jwk.Public()
 }
@@ -1,6 +1,11 @@
 package secure
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -10,8 +15,70 @@ import (
 	"github.com/go-acme/lego/v4/acme/api/internal/nonces"
 	"github.com/go-acme/lego/v4/acme/api/internal/sender"
 	"github.com/go-acme/lego/v4/platform/tester/servermock"
+	jose "github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// opaqueSigner hides the private key behind the crypto.Signer interface (ex: a key stored inside Vault).
+type opaqueSigner struct {
+	crypto.Signer
+}
+
+func TestJWS_SignContent_signer(t *testing.T) {
+	manager := servermock.NewBuilder(
+		func(server *httptest.Server) (*nonces.Manager, error) {
+			doer := sender.NewDoer(server.Client(), "lego-test")
+
+			return nonces.NewManager(doer, server.URL), nil
+		}).
+		Route("HEAD /", servermock.Noop().WithHeader("Replay-Nonce", "12345")).
+		BuildHTTPS(t)
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	j := NewJWS(opaqueSigner{Signer: privateKey}, "https://example.com/acct/1", manager)
+
+	signed, err := j.SignContent("https://example.com/new-order", []byte(`{"foo":"bar"}`))
+	require.NoError(t, err)
+
+	parsed, err := jose.ParseSigned(signed.FullSerialize(), []jose.SignatureAlgorithm{jose.ES256})
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://example.com/acct/1", parsed.Signatures[0].Header.KeyID)
+
+	payload, err := parsed.Verify(&privateKey.PublicKey)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"foo":"bar"}`, string(payload))
+}
+
+func TestJWS_SignEABContent_signer(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	j := NewJWS(opaqueSigner{Signer: privateKey}, "", nil)
+
+	hmac := []byte("aaaaaaaabbbbbbbbccccccccdddddddd")
+
+	signed, err := j.SignEABContent("https://example.com/new-account", "kid", hmac)
+	require.NoError(t, err)
+
+	parsed, err := jose.ParseSigned(signed.FullSerialize(), []jose.SignatureAlgorithm{jose.HS256})
+	require.NoError(t, err)
+
+	payload, err := parsed.Verify(hmac)
+	require.NoError(t, err)
+
+	var jwk jose.JSONWebKey
+
+	err = json.Unmarshal(payload, &jwk)
+	require.NoError(t, err)
+
+	assert.Equal(t, &privateKey.PublicKey, jwk.Key)
+}
+
 func TestNotHoldingLockWhileMakingHTTPRequests(t *testing.T) {
 	manager := servermock.NewBuilder(
 		func(server *httptest.Server) (*nonces.Manager, error) {
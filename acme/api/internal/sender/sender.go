@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/platform/errcode"
 )
 
 type RequestOption func(*http.Request) error
@@ -89,7 +90,7 @@ func (d *Doer) newRequest(method, uri string, body io.Reader, opts ...RequestOpt
 func (d *Doer) do(req *http.Request, response any) (*http.Response, error) {
 	resp, err := d.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, errcode.Wrap(errcode.CAUnreachable, err)
 	}
 
 	if err = checkError(req, resp); err != nil {
@@ -4,7 +4,9 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"maps"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/go-acme/lego/v4/acme"
@@ -24,6 +26,14 @@ type OrderOptions struct {
 	// order is intended to replace.
 	// - https://www.rfc-editor.org/rfc/rfc9773.html#section-5
 	ReplacesCertID string
+
+	// Identifiers the identifiers added to the identifiers of the domains,
+	// for the identifier types other than "dns" and "ip" (ex: TNAuthList, see challenge/tkauth01).
+	Identifiers []acme.Identifier
+
+	// MaxValidity the maximum validity of the certificates of the CA, used to check NotBefore and NotAfter.
+	// The maximum validity is not checked if it's 0.
+	MaxValidity time.Duration
 }
 
 type OrderService service
@@ -38,6 +48,13 @@ func (o *OrderService) NewWithOptions(domains []string, opts *OrderOptions) (acm
 	orderReq := acme.Order{Identifiers: createIdentifiers(domains)}
 
 	if opts != nil {
+		orderReq.Identifiers = append(orderReq.Identifiers, opts.Identifiers...)
+
+		err := checkValidity(opts.MaxValidity, opts.NotBefore, opts.NotAfter)
+		if err != nil {
+			return acme.ExtendedOrder{}, err
+		}
+
 		if !opts.NotAfter.IsZero() {
 			orderReq.NotAfter = opts.NotAfter.Format(time.RFC3339)
 		}
@@ -51,6 +68,11 @@ func (o *OrderService) NewWithOptions(domains []string, opts *OrderOptions) (acm
 		}
 
 		if opts.Profile != "" {
+			err := checkProfile(o.core.GetDirectory().Meta.Profiles, opts.Profile)
+			if err != nil {
+				return acme.ExtendedOrder{}, err
+			}
+
 			orderReq.Profile = opts.Profile
 		}
 	}
@@ -95,9 +117,13 @@ func (o *OrderService) NewWithOptions(domains []string, opts *OrderOptions) (acm
 				orderReq.Identifiers, order.Identifiers)
 	}
 
+	location := resp.Header.Get("Location")
+
+	o.core.strict.checkOrder(location, order)
+
 	return acme.ExtendedOrder{
 		Order:    order,
-		Location: resp.Header.Get("Location"),
+		Location: location,
 	}, nil
 }
 
@@ -114,7 +140,9 @@ func (o *OrderService) Get(orderURL string) (acme.ExtendedOrder, error) {
 		return acme.ExtendedOrder{}, err
 	}
 
-	return acme.ExtendedOrder{Order: order}, nil
+	o.core.strict.checkOrder(orderURL, order)
+
+	return acme.ExtendedOrder{Order: order, Location: orderURL}, nil
 }
 
 // UpdateForCSR Updates an order for a CSR.
@@ -125,14 +153,62 @@ func (o *OrderService) UpdateForCSR(orderURL string, csr []byte) (acme.ExtendedO
 
 	var order acme.Order
 
-	_, err := o.core.post(orderURL, csrMsg, &order)
+	resp, err := o.core.post(orderURL, csrMsg, &order)
 	if err != nil {
 		return acme.ExtendedOrder{}, err
 	}
 
+	o.core.strict.checkOrder(getLocation(resp), order)
+
 	if order.Status == acme.StatusInvalid {
 		return acme.ExtendedOrder{}, fmt.Errorf("invalid order: %w", order.Err())
 	}
 
 	return acme.ExtendedOrder{Order: order}, nil
 }
+
+// checkProfile checks that the profile is advertised by the CA (the profiles of the directory metadata).
+// The profiles are not checked if the CA doesn't advertise any profile.
+func checkProfile(profiles map[string]string, profile string) error {
+	if len(profiles) == 0 {
+		return nil
+	}
+
+	if _, ok := profiles[profile]; ok {
+		return nil
+	}
+
+	names := slices.Sorted(maps.Keys(profiles))
+
+	return fmt.Errorf("the profile %q is not offered by the CA (available profiles: %s)", profile, strings.Join(names, ", "))
+}
+
+// checkValidity checks the requested validity period (notBefore, notAfter) against the maximum validity of the CA.
+// The maximum validity is not checked if it's unknown (0).
+// The validity period starts now if notBefore is not defined.
+func checkValidity(maxValidity time.Duration, notBefore, notAfter time.Time) error {
+	if notAfter.IsZero() {
+		return nil
+	}
+
+	if !notBefore.IsZero() && !notAfter.After(notBefore) {
+		return fmt.Errorf("the requested notAfter (%s) must be after the requested notBefore (%s)",
+			notAfter.Format(time.RFC3339), notBefore.Format(time.RFC3339))
+	}
+
+	if maxValidity <= 0 {
+		return nil
+	}
+
+	start := notBefore
+	if start.IsZero() {
+		start = time.Now()
+	}
+
+	if notAfter.Sub(start) > maxValidity {
+		return fmt.Errorf("the requested validity period (%s) exceeds the maximum validity of the CA (%s)",
+			notAfter.Sub(start).Round(time.Second), maxValidity)
+	}
+
+	return nil
+}
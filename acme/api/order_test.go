@@ -87,6 +87,21 @@ func TestOrderService_NewWithOptions(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with additional identifiers",
+			opts: &OrderOptions{
+				Identifiers: []acme.Identifier{{Type: "TNAuthList", Value: "MAigBhYEMTIzNA"}},
+			},
+			expected: acme.ExtendedOrder{
+				Order: acme.Order{
+					Status: "valid",
+					Identifiers: []acme.Identifier{
+						{Type: "dns", Value: "example.com"},
+						{Type: "TNAuthList", Value: "MAigBhYEMTIzNA"},
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -101,6 +116,145 @@ func TestOrderService_NewWithOptions(t *testing.T) {
 	}
 }
 
+func TestOrderService_NewWithOptions_profile(t *testing.T) {
+	privateKey, errK := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, errK, "Could not generate test key")
+
+	server := tester.MockACMEServer().
+		Route("POST /newOrder",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := readSignedBody(req, privateKey)
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				order := acme.Order{}
+
+				err = json.Unmarshal(body, &order)
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				servermock.JSONEncode(acme.Order{
+					Status:      acme.StatusValid,
+					Identifiers: order.Identifiers,
+					Profile:     order.Profile,
+				}).ServeHTTP(rw, req)
+			})).
+		BuildHTTPS(t)
+
+	core, err := New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	// The CA doesn't advertise any profile: the profile is sent.
+	order, err := core.Orders.NewWithOptions([]string{"example.com"}, &OrderOptions{Profile: "shortlived"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "shortlived", order.Profile)
+
+	core.directory.Meta.Profiles = map[string]string{
+		"classic":    "The same profile you're accustomed to",
+		"tlsserver":  "https://letsencrypt.org/docs/profiles#tlsserver",
+		"shortlived": "https://letsencrypt.org/docs/profiles#shortlived",
+	}
+
+	order, err = core.Orders.NewWithOptions([]string{"example.com"}, &OrderOptions{Profile: "tlsserver"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "tlsserver", order.Profile)
+
+	_, err = core.Orders.NewWithOptions([]string{"example.com"}, &OrderOptions{Profile: "unknown"})
+	require.EqualError(t, err, `the profile "unknown" is not offered by the CA (available profiles: classic, shortlived, tlsserver)`)
+}
+
+func TestOrderService_NewWithOptions_validity(t *testing.T) {
+	privateKey, errK := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, errK, "Could not generate test key")
+
+	server := tester.MockACMEServer().
+		Route("POST /newOrder",
+			servermock.JSONEncode(acme.Order{
+				Status:      acme.StatusValid,
+				Identifiers: []acme.Identifier{{Type: "dns", Value: "example.com"}},
+			})).
+		BuildHTTPS(t)
+
+	core, err := New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	notBefore := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err = core.Orders.NewWithOptions([]string{"example.com"}, &OrderOptions{NotBefore: notBefore, NotAfter: notBefore.Add(7 * 24 * time.Hour), MaxValidity: 7 * 24 * time.Hour})
+	require.NoError(t, err)
+
+	_, err = core.Orders.NewWithOptions([]string{"example.com"}, &OrderOptions{NotBefore: notBefore, NotAfter: notBefore.Add(8 * 24 * time.Hour), MaxValidity: 7 * 24 * time.Hour})
+	require.EqualError(t, err, "the requested validity period (192h0m0s) exceeds the maximum validity of the CA (168h0m0s)")
+}
+
+func Test_checkValidity(t *testing.T) {
+	notBefore := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc        string
+		maxValidity time.Duration
+		notBefore   time.Time
+		notAfter    time.Time
+		expected    string
+	}{
+		{
+			desc: "no validity period",
+		},
+		{
+			desc:      "only notBefore",
+			notBefore: notBefore,
+		},
+		{
+			desc:      "no maximum validity",
+			notBefore: notBefore,
+			notAfter:  notBefore.Add(10 * 365 * 24 * time.Hour),
+		},
+		{
+			desc:      "notAfter before notBefore",
+			notBefore: notBefore,
+			notAfter:  notBefore.Add(-time.Hour),
+			expected:  "the requested notAfter (2024-12-31T23:00:00Z) must be after the requested notBefore (2025-01-01T00:00:00Z)",
+		},
+		{
+			desc:        "within the maximum validity",
+			maxValidity: time.Hour,
+			notBefore:   notBefore,
+			notAfter:    notBefore.Add(time.Hour),
+		},
+		{
+			desc:        "exceeds the maximum validity",
+			maxValidity: time.Hour,
+			notBefore:   notBefore,
+			notAfter:    notBefore.Add(2 * time.Hour),
+			expected:    "the requested validity period (2h0m0s) exceeds the maximum validity of the CA (1h0m0s)",
+		},
+		{
+			desc:        "starts now",
+			maxValidity: time.Hour,
+			notAfter:    time.Now().Add(30 * time.Minute),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkValidity(test.maxValidity, test.notBefore, test.notAfter)
+			if test.expected == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
 func readSignedBody(r *http.Request, privateKey *rsa.PrivateKey) ([]byte, error) {
 	reqBody, err := io.ReadAll(r.Body)
 	if err != nil {
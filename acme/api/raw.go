@@ -0,0 +1,50 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-acme/lego/v4/acme"
+)
+
+// SetRawJSON enables the copy of the raw JSON objects returned by the ACME server
+// into the Raw fields of the orders, authorizations, and challenges.
+// It is disabled by default: the raw JSON objects are not kept in memory.
+func (a *Core) SetRawJSON(enabled bool) {
+	a.rawJSON = enabled
+}
+
+// decodeRaw unmarshals the raw JSON object into the response,
+// and copies the raw JSON objects into the Raw fields of the ACME objects.
+func decodeRaw(raw json.RawMessage, response any) error {
+	err := json.Unmarshal(raw, response)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal %q to type %T: %w", raw, response, err)
+	}
+
+	switch r := response.(type) {
+	case *acme.Order:
+		r.Raw = raw
+
+	case *acme.ExtendedChallenge:
+		r.Raw = raw
+
+	case *acme.Authorization:
+		r.Raw = raw
+
+		var nested struct {
+			Challenges []json.RawMessage `json:"challenges"`
+		}
+
+		err = json.Unmarshal(raw, &nested)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal %q to type %T: %w", raw, response, err)
+		}
+
+		for i := range min(len(r.Challenges), len(nested.Challenges)) {
+			r.Challenges[i].Raw = nested.Challenges[i]
+		}
+	}
+
+	return nil
+}
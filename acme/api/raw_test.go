@@ -0,0 +1,80 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rawAuthorization = `{
+  "status": "invalid",
+  "identifier": {"type": "dns", "value": "example.com"},
+  "challenges": [
+    {
+      "type": "dns-01",
+      "url": "https://example.com/chall/1",
+      "status": "invalid",
+      "token": "abc",
+      "error": {"type": "urn:ietf:params:acme:error:dns", "detail": "oops"},
+      "x-vendor-hint": "foo"
+    }
+  ],
+  "x-vendor-extension": {"priority": "high"}
+}`
+
+func TestCore_SetRawJSON(t *testing.T) {
+	privateKey, errK := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, errK, "Could not generate test key")
+
+	server := tester.MockACMEServer().
+		Route("POST /authz/1",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Content-Type", "application/json")
+				_, _ = rw.Write([]byte(rawAuthorization))
+			})).
+		BuildHTTPS(t)
+
+	core, err := New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	authz, err := core.Authorizations.Get(server.URL + "/authz/1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "invalid", authz.Status)
+	assert.Nil(t, authz.Raw)
+	require.Len(t, authz.Challenges, 1)
+	assert.Nil(t, authz.Challenges[0].Raw)
+
+	core.SetRawJSON(true)
+
+	authz, err = core.Authorizations.Get(server.URL + "/authz/1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "invalid", authz.Status)
+	assert.JSONEq(t, rawAuthorization, string(authz.Raw))
+
+	extra := struct {
+		Extension struct {
+			Priority string `json:"priority"`
+		} `json:"x-vendor-extension"`
+	}{}
+
+	err = json.Unmarshal(authz.Raw, &extra)
+	require.NoError(t, err)
+
+	assert.Equal(t, "high", extra.Extension.Priority)
+
+	require.Len(t, authz.Challenges, 1)
+
+	chlg := authz.Challenges[0]
+	assert.Equal(t, "dns-01", chlg.Type)
+	assert.Contains(t, string(chlg.Raw), `"x-vendor-hint": "foo"`)
+	require.NotNil(t, chlg.Error)
+	assert.Equal(t, "oops", chlg.Error.Detail)
+}
@@ -0,0 +1,350 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// Object types used in the strict mode violations.
+const (
+	ObjectDirectory     = "directory"
+	ObjectOrder         = "order"
+	ObjectAuthorization = "authorization"
+	ObjectChallenge     = "challenge"
+)
+
+// A token MUST NOT contain any characters outside the base64url alphabet,
+// and MUST NOT include base64 padding characters ("=").
+// It MUST have at least 128 bits of entropy (22 base64url characters).
+// https://www.rfc-editor.org/rfc/rfc8555.html#section-8.3
+var tokenExpr = regexp.MustCompile(`^[A-Za-z0-9_-]{22,}$`)
+
+// tokenChallengeTypes the challenge types with a required token.
+var tokenChallengeTypes = []string{"http-01", "dns-01", "tls-alpn-01"}
+
+// Allowed status transitions.
+// https://www.rfc-editor.org/rfc/rfc8555.html#section-7.1.6
+var (
+	orderTransitions = map[string][]string{
+		acme.StatusPending:    {acme.StatusReady, acme.StatusInvalid},
+		acme.StatusReady:      {acme.StatusProcessing, acme.StatusValid, acme.StatusInvalid},
+		acme.StatusProcessing: {acme.StatusValid, acme.StatusInvalid},
+		acme.StatusValid:      {},
+		acme.StatusInvalid:    {},
+	}
+
+	authorizationTransitions = map[string][]string{
+		acme.StatusPending:     {acme.StatusValid, acme.StatusInvalid, acme.StatusExpired, acme.StatusDeactivated},
+		acme.StatusValid:       {acme.StatusExpired, acme.StatusDeactivated, acme.StatusRevoked},
+		acme.StatusInvalid:     {},
+		acme.StatusExpired:     {},
+		acme.StatusDeactivated: {},
+		acme.StatusRevoked:     {},
+	}
+
+	challengeTransitions = map[string][]string{
+		acme.StatusPending:    {acme.StatusProcessing, acme.StatusValid, acme.StatusInvalid},
+		acme.StatusProcessing: {acme.StatusValid, acme.StatusInvalid},
+		acme.StatusValid:      {},
+		acme.StatusInvalid:    {},
+	}
+)
+
+// maxTrackedStatuses the maximum number of objects whose status is tracked by the strict mode:
+// the oldest objects are forgotten, so a long-running process doesn't keep the status of every object ever seen.
+const maxTrackedStatuses = 10000
+
+// Violation a violation of RFC 8555 detected in a response of the ACME server.
+type Violation struct {
+	// Object the type of the ACME object (directory, order, authorization, challenge).
+	Object string
+	// URL the URL of the ACME object, if known.
+	URL string
+	// Message the description of the violation.
+	Message string
+}
+
+func (v Violation) String() string {
+	if v.URL == "" {
+		return fmt.Sprintf("%s: %s", v.Object, v.Message)
+	}
+
+	return fmt.Sprintf("%s [%s]: %s", v.Object, v.URL, v.Message)
+}
+
+// ViolationHandler handles the violations detected by the strict mode.
+type ViolationHandler func(violation Violation)
+
+// LogViolation logs the violation as a warning.
+func LogViolation(violation Violation) {
+	log.Warnf("acme: RFC 8555 violation: %s", violation)
+}
+
+// SetStrictMode enables the validation of the ACME server responses against RFC 8555
+// (required fields, URL formats, status transitions).
+// The violations are reported to the handler, they don't stop the process.
+// If the handler is nil, the violations are logged.
+// The status transitions are only checked for the last objects seen (see maxTrackedStatuses).
+func (a *Core) SetStrictMode(handler ViolationHandler) {
+	if handler == nil {
+		handler = LogViolation
+	}
+
+	a.strict = &strictValidator{
+		report:      handler,
+		statuses:    make(map[string]string),
+		maxStatuses: maxTrackedStatuses,
+	}
+
+	a.strict.checkDirectory(a.directory)
+}
+
+// strictValidator validates the ACME server responses against RFC 8555.
+type strictValidator struct {
+	report ViolationHandler
+
+	// statuses keeps the last known status of each object (the key is the object URL).
+	statuses   map[string]string
+	statusesMu sync.Mutex
+
+	// keys the keys of statuses, from the oldest to the newest.
+	keys []string
+	// maxStatuses the maximum number of tracked objects (0 means no limit).
+	maxStatuses int
+}
+
+func (s *strictValidator) checkDirectory(dir acme.Directory) {
+	if s == nil {
+		return
+	}
+
+	v := &violations{object: ObjectDirectory}
+
+	v.requireURL("newNonce", dir.NewNonceURL)
+	v.requireURL("newAccount", dir.NewAccountURL)
+	v.requireURL("newOrder", dir.NewOrderURL)
+	v.requireURL("revokeCert", dir.RevokeCertURL)
+	v.requireURL("keyChange", dir.KeyChangeURL)
+	v.optionalURL("newAuthz", dir.NewAuthzURL)
+	v.optionalURL("renewalInfo", dir.RenewalInfo)
+	v.optionalURL("meta.termsOfService", dir.Meta.TermsOfService)
+	v.optionalURL("meta.website", dir.Meta.Website)
+
+	s.flush(v)
+}
+
+func (s *strictValidator) checkOrder(orderURL string, order acme.Order) {
+	if s == nil {
+		return
+	}
+
+	v := &violations{object: ObjectOrder, url: orderURL}
+
+	v.requireStatus(order.Status, orderTransitions)
+
+	if len(order.Identifiers) == 0 {
+		v.add("the field 'identifiers' is required")
+	}
+
+	for _, identifier := range order.Identifiers {
+		v.checkIdentifier(identifier)
+	}
+
+	if len(order.Authorizations) == 0 {
+		v.add("the field 'authorizations' is required")
+	}
+
+	for _, authzURL := range order.Authorizations {
+		v.requireURL("authorizations", authzURL)
+	}
+
+	v.requireURL("finalize", order.Finalize)
+
+	if order.Status == acme.StatusPending || order.Status == acme.StatusValid {
+		v.requireField("expires", order.Expires)
+	}
+
+	v.checkTimestamp("expires", order.Expires)
+	v.checkTimestamp("notBefore", order.NotBefore)
+	v.checkTimestamp("notAfter", order.NotAfter)
+
+	if order.Status == acme.StatusValid {
+		v.requireURL("certificate", order.Certificate)
+	} else {
+		v.optionalURL("certificate", order.Certificate)
+	}
+
+	if order.Error != nil && order.Status != acme.StatusInvalid {
+		v.add(fmt.Sprintf("the field 'error' is present but the status is %q", order.Status))
+	}
+
+	// The finalize URL is used as key because it is always known, and it is unique per order.
+	s.checkTransition(v, order.Finalize, order.Status, orderTransitions)
+
+	s.flush(v)
+}
+
+func (s *strictValidator) checkAuthorization(authzURL string, authz acme.Authorization) {
+	if s == nil {
+		return
+	}
+
+	v := &violations{object: ObjectAuthorization, url: authzURL}
+
+	v.requireStatus(authz.Status, authorizationTransitions)
+	v.checkIdentifier(authz.Identifier)
+
+	if strings.HasPrefix(authz.Identifier.Value, "*.") {
+		v.add("the identifier value must not contain the wildcard prefix")
+	}
+
+	if authz.Status == acme.StatusValid && authz.Expires.IsZero() {
+		v.add("the field 'expires' is required when the status is valid")
+	}
+
+	if len(authz.Challenges) == 0 {
+		v.add("the field 'challenges' is required")
+	}
+
+	s.checkTransition(v, authzURL, authz.Status, authorizationTransitions)
+
+	s.flush(v)
+
+	for _, chlg := range authz.Challenges {
+		s.checkChallenge(chlg)
+	}
+}
+
+func (s *strictValidator) checkChallenge(chlg acme.Challenge) {
+	if s == nil {
+		return
+	}
+
+	v := &violations{object: ObjectChallenge, url: chlg.URL}
+
+	v.requireField("type", chlg.Type)
+	v.requireURL("url", chlg.URL)
+	v.requireStatus(chlg.Status, challengeTransitions)
+
+	if slices.Contains(tokenChallengeTypes, chlg.Type) && !tokenExpr.MatchString(chlg.Token) {
+		v.add(fmt.Sprintf("invalid token %q: it must contain at least 128 bits of entropy encoded with base64url without padding", chlg.Token))
+	}
+
+	if chlg.Status == acme.StatusValid && chlg.Validated.IsZero() {
+		v.add("the field 'validated' is required when the status is valid")
+	}
+
+	if chlg.Error != nil && chlg.Status != acme.StatusInvalid {
+		v.add(fmt.Sprintf("the field 'error' is present but the status is %q", chlg.Status))
+	}
+
+	s.checkTransition(v, chlg.URL, chlg.Status, challengeTransitions)
+
+	s.flush(v)
+}
+
+func (s *strictValidator) checkTransition(v *violations, key, status string, transitions map[string][]string) {
+	if key == "" || status == "" {
+		return
+	}
+
+	s.statusesMu.Lock()
+	previous, ok := s.statuses[key]
+	s.statuses[key] = status
+
+	if !ok {
+		s.keys = append(s.keys, key)
+
+		if s.maxStatuses > 0 && len(s.keys) > s.maxStatuses {
+			delete(s.statuses, s.keys[0])
+			s.keys = s.keys[1:]
+		}
+	}
+	s.statusesMu.Unlock()
+
+	if !ok || previous == status {
+		return
+	}
+
+	if !slices.Contains(transitions[previous], status) {
+		v.add(fmt.Sprintf("invalid status transition from %q to %q", previous, status))
+	}
+}
+
+func (s *strictValidator) flush(v *violations) {
+	for _, message := range v.messages {
+		s.report(Violation{Object: v.object, URL: v.url, Message: message})
+	}
+}
+
+type violations struct {
+	object   string
+	url      string
+	messages []string
+}
+
+func (v *violations) add(message string) {
+	v.messages = append(v.messages, message)
+}
+
+func (v *violations) requireField(name, value string) {
+	if value == "" {
+		v.add(fmt.Sprintf("the field '%s' is required", name))
+	}
+}
+
+func (v *violations) requireStatus(status string, transitions map[string][]string) {
+	if status == "" {
+		v.add("the field 'status' is required")
+		return
+	}
+
+	if _, ok := transitions[status]; !ok {
+		v.add(fmt.Sprintf("unknown status %q", status))
+	}
+}
+
+func (v *violations) requireURL(name, value string) {
+	if value == "" {
+		v.add(fmt.Sprintf("the field '%s' is required", name))
+		return
+	}
+
+	v.optionalURL(name, value)
+}
+
+func (v *violations) optionalURL(name, value string) {
+	if value == "" {
+		return
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		v.add(fmt.Sprintf("the field '%s' is not an absolute URL: %q", name, value))
+	}
+}
+
+func (v *violations) checkTimestamp(name, value string) {
+	if value == "" {
+		return
+	}
+
+	_, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		v.add(fmt.Sprintf("the field '%s' is not a RFC 3339 timestamp: %q", name, value))
+	}
+}
+
+func (v *violations) checkIdentifier(identifier acme.Identifier) {
+	if identifier.Type == "" || identifier.Value == "" {
+		v.add(fmt.Sprintf("invalid identifier %+v: the fields 'type' and 'value' are required", identifier))
+	}
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStrictValidator() (*strictValidator, *[]Violation) {
+	var reported []Violation
+
+	return &strictValidator{
+		report: func(violation Violation) {
+			reported = append(reported, violation)
+		},
+		statuses: make(map[string]string),
+	}, &reported
+}
+
+func TestStrictValidator_checkDirectory(t *testing.T) {
+	s, reported := newTestStrictValidator()
+
+	s.checkDirectory(acme.Directory{
+		NewNonceURL:   "https://example.com/nonce",
+		NewAccountURL: "https://example.com/account",
+		NewOrderURL:   "/order",
+		RevokeCertURL: "https://example.com/revoke",
+	})
+
+	expected := []Violation{
+		{Object: ObjectDirectory, Message: `the field 'newOrder' is not an absolute URL: "/order"`},
+		{Object: ObjectDirectory, Message: "the field 'keyChange' is required"},
+	}
+
+	assert.Equal(t, expected, *reported)
+}
+
+func TestStrictValidator_checkOrder(t *testing.T) {
+	s, reported := newTestStrictValidator()
+
+	order := acme.Order{
+		Status:         acme.StatusPending,
+		Expires:        "2025-01-01T00:00:00Z",
+		Identifiers:    []acme.Identifier{{Type: "dns", Value: "example.com"}},
+		Authorizations: []string{"https://example.com/authz/1"},
+		Finalize:       "https://example.com/finalize/1",
+	}
+
+	s.checkOrder("https://example.com/order/1", order)
+
+	assert.Empty(t, *reported)
+
+	order.Status = acme.StatusValid
+	order.Expires = "tomorrow"
+
+	s.checkOrder("https://example.com/order/1", order)
+
+	expected := []Violation{
+		{Object: ObjectOrder, URL: "https://example.com/order/1", Message: `the field 'expires' is not a RFC 3339 timestamp: "tomorrow"`},
+		{Object: ObjectOrder, URL: "https://example.com/order/1", Message: "the field 'certificate' is required"},
+		{Object: ObjectOrder, URL: "https://example.com/order/1", Message: `invalid status transition from "pending" to "valid"`},
+	}
+
+	assert.Equal(t, expected, *reported)
+}
+
+func TestStrictValidator_checkAuthorization(t *testing.T) {
+	s, reported := newTestStrictValidator()
+
+	s.checkAuthorization("https://example.com/authz/1", acme.Authorization{
+		Status:     acme.StatusValid,
+		Identifier: acme.Identifier{Type: "dns", Value: "*.example.com"},
+		Challenges: []acme.Challenge{{
+			Type:      "dns-01",
+			URL:       "https://example.com/chall/1",
+			Status:    acme.StatusValid,
+			Validated: time.Now(),
+			Token:     "short",
+		}},
+	})
+
+	expected := []Violation{
+		{Object: ObjectAuthorization, URL: "https://example.com/authz/1", Message: "the identifier value must not contain the wildcard prefix"},
+		{Object: ObjectAuthorization, URL: "https://example.com/authz/1", Message: "the field 'expires' is required when the status is valid"},
+		{Object: ObjectChallenge, URL: "https://example.com/chall/1", Message: `invalid token "short": it must contain at least 128 bits of entropy encoded with base64url without padding`},
+	}
+
+	assert.Equal(t, expected, *reported)
+}
+
+func TestStrictValidator_checkChallenge_transition(t *testing.T) {
+	s, reported := newTestStrictValidator()
+
+	chlg := acme.Challenge{
+		Type:   "http-01",
+		URL:    "https://example.com/chall/1",
+		Status: acme.StatusInvalid,
+		Token:  "LoqXcYV8q5ONbJQxbmR7SCTNo3tiAXDfowyjxAjEuX0",
+		Error:  &acme.ProblemDetails{Type: "urn:ietf:params:acme:error:connection"},
+	}
+
+	s.checkChallenge(chlg)
+
+	assert.Empty(t, *reported)
+
+	chlg.Status = acme.StatusPending
+	chlg.Error = nil
+
+	s.checkChallenge(chlg)
+
+	expected := []Violation{
+		{Object: ObjectChallenge, URL: "https://example.com/chall/1", Message: `invalid status transition from "invalid" to "pending"`},
+	}
+
+	assert.Equal(t, expected, *reported)
+}
+
+func TestStrictValidator_maxStatuses(t *testing.T) {
+	s, reported := newTestStrictValidator()
+	s.maxStatuses = 2
+
+	chlg := acme.Challenge{
+		Type:   "http-01",
+		Status: acme.StatusValid,
+		Token:  "LoqXcYV8q5ONbJQxbmR7SCTNo3tiAXDfowyjxAjEuX0",
+	}
+
+	for i := range 3 {
+		chlg.URL = fmt.Sprintf("https://example.com/chall/%d", i)
+		chlg.Validated = time.Now()
+
+		s.checkChallenge(chlg)
+	}
+
+	// The oldest object is forgotten.
+	assert.Equal(t, map[string]string{
+		"https://example.com/chall/1": acme.StatusValid,
+		"https://example.com/chall/2": acme.StatusValid,
+	}, s.statuses)
+	assert.Equal(t, []string{"https://example.com/chall/1", "https://example.com/chall/2"}, s.keys)
+	assert.Empty(t, *reported)
+}
+
+func TestStrictValidator_nil(t *testing.T) {
+	var s *strictValidator
+
+	assert.NotPanics(t, func() {
+		s.checkDirectory(acme.Directory{})
+		s.checkOrder("", acme.Order{})
+		s.checkAuthorization("", acme.Authorization{})
+		s.checkChallenge(acme.Challenge{})
+	})
+}
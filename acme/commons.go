@@ -128,6 +128,16 @@ type Account struct {
 	ExternalAccountBinding json.RawMessage `json:"externalAccountBinding,omitempty"`
 }
 
+// OrdersList the list of the orders of an account.
+// - https://www.rfc-editor.org/rfc/rfc8555.html#section-7.1.2.1
+type OrdersList struct {
+	// orders (required, array of string):
+	// An array of URLs, each identifying an order belonging to the account.
+	// The server SHOULD include pending orders and SHOULD NOT include orders that are invalid in the array of URLs.
+	// The server MAY return an incomplete list, along with a Link header field with a "next" link relation indicating where further entries can be acquired.
+	Orders []string `json:"orders"`
+}
+
 // ExtendedOrder a extended Order.
 type ExtendedOrder struct {
 	Order
@@ -199,6 +209,10 @@ type Order struct {
 	// previously-issued certificate which this order is intended to replace.
 	// - https://www.rfc-editor.org/rfc/rfc9773.html#section-5
 	Replaces string `json:"replaces,omitempty"`
+
+	// Raw contains the raw JSON object returned by the ACME server (including the fields unknown to lego).
+	// It is only filled when enabled on the API client (see api.Core.SetRawJSON).
+	Raw json.RawMessage `json:"-"`
 }
 
 func (r *Order) Err() error {
@@ -240,6 +254,10 @@ type Authorization struct {
 	// For authorizations created as a result of a newOrder request containing a DNS identifier
 	// with a value that contained a wildcard prefix this field MUST be present, and true.
 	Wildcard bool `json:"wildcard,omitempty"`
+
+	// Raw contains the raw JSON object returned by the ACME server (including the fields unknown to lego).
+	// It is only filled when enabled on the API client (see api.Core.SetRawJSON).
+	Raw json.RawMessage `json:"-"`
 }
 
 // ExtendedChallenge a extended Challenge.
@@ -293,6 +311,25 @@ type Challenge struct {
 
 	// https://www.rfc-editor.org/rfc/rfc8555.html#section-8.1
 	KeyAuthorization string `json:"keyAuthorization"`
+
+	// nonce (required for onion-csr-01, string):
+	// A Base64 encoded nonce, generated by the CA, to include in the CSR.
+	// https://www.rfc-editor.org/rfc/rfc9799.html#section-3.2
+	Nonce string `json:"nonce,omitempty"`
+
+	// tkauth-type (required for tkauth-01, string):
+	// The type of token to provide (ex: "atc").
+	// https://www.rfc-editor.org/rfc/rfc9447.html#section-3
+	TKAuthType string `json:"tkauth-type,omitempty"`
+
+	// token-authority (optional for tkauth-01, string):
+	// The URL of the token authority (ex: the STI-PA of the SHAKEN ecosystem).
+	// https://www.rfc-editor.org/rfc/rfc9447.html#section-3
+	TokenAuthority string `json:"token-authority,omitempty"`
+
+	// Raw contains the raw JSON object returned by the ACME server (including the fields unknown to lego).
+	// It is only filled when enabled on the API client (see api.Core.SetRawJSON).
+	Raw json.RawMessage `json:"-"`
 }
 
 func (c *Challenge) Err() error {
@@ -0,0 +1,21 @@
+package acme
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrder_MarshalJSON_noRaw(t *testing.T) {
+	order := Order{
+		Status: StatusPending,
+		Raw:    json.RawMessage(`{"foo":"bar"}`),
+	}
+
+	data, err := json.Marshal(order)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"status":"pending","identifiers":null}`, string(data))
+}
@@ -1,8 +1,12 @@
 package acme
 
 import (
+	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
+
+	"github.com/go-acme/lego/v4/platform/errcode"
 )
 
 // Errors types.
@@ -10,6 +14,11 @@ const (
 	errNS              = "urn:ietf:params:acme:error:"
 	BadNonceErr        = errNS + "badNonce"
 	AlreadyReplacedErr = errNS + "alreadyReplaced"
+	BadCSRErr          = errNS + "badCSR"
+	AlreadyRevokedErr  = errNS + "alreadyRevoked"
+
+	InvalidContactErr     = errNS + "invalidContact"
+	UnsupportedContactErr = errNS + "unsupportedContact"
 )
 
 // ProblemDetails the problem details object.
@@ -25,6 +34,26 @@ type ProblemDetails struct {
 	// additional values to have a better error message (Not defined by the RFC)
 	Method string `json:"method,omitempty"`
 	URL    string `json:"url,omitempty"`
+
+	// Raw contains the raw problem document returned by the ACME server (including the fields unknown to lego).
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON unmarshals the problem document and keeps a copy of the raw JSON object.
+func (p *ProblemDetails) UnmarshalJSON(data []byte) error {
+	type problemDetails ProblemDetails
+
+	var o problemDetails
+
+	err := json.Unmarshal(data, &o)
+	if err != nil {
+		return err
+	}
+
+	*p = ProblemDetails(o)
+	p.Raw = slices.Clone(data)
+
+	return nil
 }
 
 func (p *ProblemDetails) Error() string {
@@ -49,6 +78,52 @@ func (p *ProblemDetails) Error() string {
 	return msg.String()
 }
 
+// ErrorCode returns the stable error code matching the type of the problem.
+// https://www.rfc-editor.org/rfc/rfc8555.html#section-6.7
+func (p *ProblemDetails) ErrorCode() errcode.Code {
+	switch strings.TrimPrefix(p.Type, errNS) {
+	case "rateLimited":
+		return errcode.CARateLimit
+	case "badNonce":
+		return errcode.CABadNonce
+	case "badCSR":
+		return errcode.CABadCSR
+	case "unauthorized":
+		return errcode.CAUnauthorized
+	case "rejectedIdentifier", "unsupportedIdentifier":
+		return errcode.CARejectedIdentifier
+	case "caa":
+		return errcode.CACAA
+	case "dns":
+		return errcode.CADNS
+	case "connection", "tls":
+		return errcode.CAConnection
+	case "incorrectResponse":
+		return errcode.CAIncorrectResponse
+	case "accountDoesNotExist":
+		return errcode.CAAccountNotFound
+	case "externalAccountRequired":
+		return errcode.CAExternalAccountRequired
+	case "alreadyReplaced":
+		return errcode.CAAlreadyReplaced
+	case "userActionRequired":
+		return errcode.CAUserActionRequired
+	case "serverInternal":
+		return errcode.CAServerError
+	case "compound":
+		for _, sub := range p.SubProblems {
+			code := (&ProblemDetails{Type: sub.Type}).ErrorCode()
+			if code != errcode.CAError {
+				return code
+			}
+		}
+
+		return errcode.CAError
+	default:
+		return errcode.CAError
+	}
+}
+
 // SubProblem a "subproblems".
 // - https://www.rfc-editor.org/rfc/rfc8555.html#section-6.7.1
 type SubProblem struct {
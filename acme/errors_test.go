@@ -0,0 +1,70 @@
+package acme
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/go-acme/lego/v4/platform/errcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblemDetails_ErrorCode(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		problem  *ProblemDetails
+		expected errcode.Code
+	}{
+		{
+			desc:     "rate limit",
+			problem:  &ProblemDetails{Type: "urn:ietf:params:acme:error:rateLimited"},
+			expected: errcode.CARateLimit,
+		},
+		{
+			desc:     "CAA",
+			problem:  &ProblemDetails{Type: "urn:ietf:params:acme:error:caa"},
+			expected: errcode.CACAA,
+		},
+		{
+			desc: "compound",
+			problem: &ProblemDetails{
+				Type: "urn:ietf:params:acme:error:compound",
+				SubProblems: []SubProblem{
+					{Type: "urn:ietf:params:acme:error:example"},
+					{Type: "urn:ietf:params:acme:error:dns"},
+				},
+			},
+			expected: errcode.CADNS,
+		},
+		{
+			desc:     "unknown type",
+			problem:  &ProblemDetails{Type: "urn:example:error"},
+			expected: errcode.CAError,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.problem.ErrorCode())
+		})
+	}
+}
+
+func TestProblemDetails_ErrorCode_wrapped(t *testing.T) {
+	err := fmt.Errorf("context: %w", &NonceError{ProblemDetails: &ProblemDetails{Type: BadNonceErr}})
+
+	assert.Equal(t, errcode.CABadNonce, errcode.Of(err))
+}
+
+func TestProblemDetails_UnmarshalJSON_raw(t *testing.T) {
+	data := `{"type": "urn:ietf:params:acme:error:dns", "detail": "oops", "x-vendor-code": 42}`
+
+	var problem ProblemDetails
+
+	err := json.Unmarshal([]byte(data), &problem)
+	require.NoError(t, err)
+
+	assert.Equal(t, "oops", problem.Detail)
+	assert.JSONEq(t, data, string(problem.Raw))
+}
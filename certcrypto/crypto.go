@@ -99,6 +99,9 @@ func ParsePEMPrivateKey(key []byte) (crypto.PrivateKey, error) {
 		return nil, fmt.Errorf("unknown PEM header %q", keyBlockDER.Type)
 	}
 
+	// The decoded DER is a copy of the key material: the parsed key doesn't reference it.
+	defer Zeroize(keyBlockDER.Bytes)
+
 	if key, err := x509.ParsePKCS1PrivateKey(keyBlockDER.Bytes); err == nil {
 		return key, nil
 	}
@@ -122,17 +125,17 @@ func ParsePEMPrivateKey(key []byte) (crypto.PrivateKey, error) {
 func GeneratePrivateKey(keyType KeyType) (crypto.PrivateKey, error) {
 	switch keyType {
 	case EC256:
-		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		return ecdsa.GenerateKey(elliptic.P256(), getRandom())
 	case EC384:
-		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		return ecdsa.GenerateKey(elliptic.P384(), getRandom())
 	case RSA2048:
-		return rsa.GenerateKey(rand.Reader, 2048)
+		return rsa.GenerateKey(getRandom(), 2048)
 	case RSA3072:
-		return rsa.GenerateKey(rand.Reader, 3072)
+		return rsa.GenerateKey(getRandom(), 3072)
 	case RSA4096:
-		return rsa.GenerateKey(rand.Reader, 4096)
+		return rsa.GenerateKey(getRandom(), 4096)
 	case RSA8192:
-		return rsa.GenerateKey(rand.Reader, 8192)
+		return rsa.GenerateKey(getRandom(), 8192)
 	}
 
 	return nil, fmt.Errorf("invalid KeyType: %s", keyType)
@@ -182,11 +185,27 @@ func CreateCSR(privateKey crypto.PrivateKey, opts CSROptions) ([]byte, error) {
 		})
 	}
 
-	return x509.CreateCertificateRequest(rand.Reader, &template, privateKey)
+	return x509.CreateCertificateRequest(getRandom(), &template, privateKey)
 }
 
+// PEMEncode encodes data (private key, CSR, or DER certificate) to PEM.
+// Returns nil if the type of data is not supported (e.g. a crypto.Signer without exportable private key).
+//
+// For the private keys, the intermediate DER buffer is zeroed after the encoding.
 func PEMEncode(data any) []byte {
-	return pem.EncodeToMemory(PEMBlock(data))
+	block := PEMBlock(data)
+	if block == nil {
+		return nil
+	}
+
+	encoded := pem.EncodeToMemory(block)
+
+	switch data.(type) {
+	case *ecdsa.PrivateKey, *rsa.PrivateKey:
+		Zeroize(block.Bytes)
+	}
+
+	return encoded
 }
 
 func PEMBlock(data any) *pem.Block {
@@ -207,6 +226,12 @@ func PEMBlock(data any) *pem.Block {
 	return pemBlock
 }
 
+// Zeroize overwrites b with zeros.
+// It's intended to wipe the temporary buffers containing key material (PEM, DER) after use.
+func Zeroize(b []byte) {
+	clear(b)
+}
+
 func pemDecode(data []byte) (*pem.Block, error) {
 	pemBlock, _ := pem.Decode(data)
 	if pemBlock == nil {
@@ -325,7 +350,7 @@ func GeneratePemCert(privateKey *rsa.PrivateKey, domain string, extensions []pki
 func generateDerCert(privateKey *rsa.PrivateKey, expiration time.Time, domain string, extensions []pkix.Extension) ([]byte, error) {
 	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
 
-	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	serialNumber, err := rand.Int(getRandom(), serialNumberLimit)
 	if err != nil {
 		return nil, err
 	}
@@ -354,5 +379,5 @@ func generateDerCert(privateKey *rsa.PrivateKey, expiration time.Time, domain st
 		template.DNSNames = []string{domain}
 	}
 
-	return x509.CreateCertificate(rand.Reader, &template, &template, &privateKey.PublicKey, privateKey)
+	return x509.CreateCertificate(getRandom(), &template, &template, &privateKey.PublicKey, privateKey)
 }
@@ -5,6 +5,7 @@ import (
 	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/x509"
 	"encoding/pem"
 	"testing"
 	"time"
@@ -143,6 +144,41 @@ func TestPEMEncode(t *testing.T) {
 	assert.Empty(t, p.Headers)
 }
 
+func TestPEMEncode_csr(t *testing.T) {
+	privateKey, err := GeneratePrivateKey(EC256)
+	require.NoError(t, err)
+
+	raw, err := GenerateCSR(privateKey, testDomain1, nil, false)
+	require.NoError(t, err)
+
+	csr, err := x509.ParseCertificateRequest(raw)
+	require.NoError(t, err)
+
+	data := PEMEncode(csr)
+	require.NotNil(t, data)
+
+	// The raw CSR is not zeroed.
+	assert.Equal(t, raw, csr.Raw)
+}
+
+func TestPEMEncode_unsupported(t *testing.T) {
+	privateKey, err := GeneratePrivateKey(EC256)
+	require.NoError(t, err)
+
+	// A signer without exportable private key (e.g. HSM, KMS).
+	signer := struct{ crypto.Signer }{privateKey.(crypto.Signer)}
+
+	assert.Nil(t, PEMEncode(signer))
+}
+
+func TestZeroize(t *testing.T) {
+	data := []byte("secret")
+
+	Zeroize(data)
+
+	assert.Equal(t, make([]byte, 6), data)
+}
+
 func TestParsePEMCertificate(t *testing.T) {
 	privateKey, err := GeneratePrivateKey(RSA2048)
 	require.NoError(t, err, "Error generating private key")
@@ -183,6 +219,11 @@ func TestParsePEMPrivateKey(t *testing.T) {
 	decodedRsaPrivateKey := decoded.(*rsa.PrivateKey)
 	require.True(t, decodedRsaPrivateKey.Equal(privateKey))
 
+	// The input is not modified, and the parsed key doesn't share the wiped buffers.
+	decoded, err = ParsePEMPrivateKey(pemPrivateKey)
+	require.NoError(t, err)
+	require.True(t, decoded.(*rsa.PrivateKey).Equal(privateKey))
+
 	// Decoding a PEM block that doesn't contain a private key should error
 	_, err = ParsePEMPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE"}))
 	require.Errorf(t, err, "Expected to return an error for non-private key input")
@@ -0,0 +1,101 @@
+package certcrypto
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pavlo-v-chernykh/keystore-go/v4"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// PKCS#12 encryption algorithms.
+const (
+	// PKCS12RC2 the legacy encryption (RC2-40 and 3DES, SHA-1): compatible with the old systems (Java < 8u301, Windows < Server 2019, etc.).
+	PKCS12RC2 = "RC2"
+	// PKCS12DES the legacy encryption (3DES, SHA-1).
+	PKCS12DES = "DES"
+	// PKCS12SHA256 the modern encryption (AES-256-CBC, PBKDF2 and SHA-256).
+	PKCS12SHA256 = "SHA256"
+	// PKCS12AES an alias of PKCS12SHA256.
+	PKCS12AES = "AES"
+)
+
+// JKSDefaultAlias the alias of the private key entry of a JKS keystore when no alias is provided.
+const JKSDefaultAlias = "lego"
+
+// EncodePKCS12 encodes a private key and a certificate chain (the leaf certificate first) into a PKCS#12 (PFX) file.
+// The algorithm is one of PKCS12RC2, PKCS12DES, PKCS12SHA256, or PKCS12AES.
+func EncodePKCS12(privateKey crypto.PrivateKey, chain []*x509.Certificate, algorithm, password string) ([]byte, error) {
+	if len(chain) == 0 {
+		return nil, errors.New("pkcs12: no certificate")
+	}
+
+	encoder, err := getPKCS12Encoder(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	return encoder.Encode(privateKey, chain[0], chain[1:], password)
+}
+
+// EncodeJKS encodes a private key and a certificate chain (the leaf certificate first) into a Java keystore (JKS) file.
+// The private key entry is named by the alias (JKSDefaultAlias if empty),
+// and the keystore and the entry are protected by the same password (at least 6 characters, like keytool).
+func EncodeJKS(privateKey crypto.PrivateKey, chain []*x509.Certificate, alias, password string) ([]byte, error) {
+	if len(chain) == 0 {
+		return nil, errors.New("jks: no certificate")
+	}
+
+	if alias == "" {
+		alias = JKSDefaultAlias
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("jks: marshal the private key: %w", err)
+	}
+
+	defer Zeroize(der)
+
+	entry := keystore.PrivateKeyEntry{
+		CreationTime: time.Now(),
+		PrivateKey:   der,
+	}
+
+	for _, cert := range chain {
+		entry.CertificateChain = append(entry.CertificateChain, keystore.Certificate{Type: "X509", Content: cert.Raw})
+	}
+
+	ks := keystore.New(keystore.WithMinPasswordLen(6))
+
+	err = ks.SetPrivateKeyEntry(alias, entry, []byte(password))
+	if err != nil {
+		return nil, fmt.Errorf("jks: %w", err)
+	}
+
+	var buf bytes.Buffer
+
+	err = ks.Store(&buf, []byte(password))
+	if err != nil {
+		return nil, fmt.Errorf("jks: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func getPKCS12Encoder(algorithm string) (*pkcs12.Encoder, error) {
+	switch algorithm {
+	case PKCS12SHA256, PKCS12AES:
+		return pkcs12.Modern2023, nil
+	case PKCS12DES:
+		return pkcs12.LegacyDES, nil
+	case PKCS12RC2:
+		return pkcs12.LegacyRC2, nil
+	default:
+		return nil, fmt.Errorf("pkcs12: unsupported algorithm: %s", algorithm)
+	}
+}
@@ -0,0 +1,112 @@
+package certcrypto
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/pavlo-v-chernykh/keystore-go/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func generateTestChain(t *testing.T) (*rsa.PrivateKey, []*x509.Certificate) {
+	t.Helper()
+
+	privateKey, err := GeneratePrivateKey(RSA2048)
+	require.NoError(t, err)
+
+	certBytes, err := generateDerCert(privateKey.(*rsa.PrivateKey), time.Now().Add(time.Hour), testDomain1, nil)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(certBytes)
+	require.NoError(t, err)
+
+	return privateKey.(*rsa.PrivateKey), []*x509.Certificate{cert}
+}
+
+func TestEncodePKCS12(t *testing.T) {
+	privateKey, chain := generateTestChain(t)
+
+	testCases := []string{PKCS12RC2, PKCS12DES, PKCS12SHA256, PKCS12AES}
+
+	for _, algorithm := range testCases {
+		t.Run(algorithm, func(t *testing.T) {
+			t.Parallel()
+
+			pfxData, err := EncodePKCS12(privateKey, chain, algorithm, "secret")
+			require.NoError(t, err)
+
+			key, cert, caCerts, err := pkcs12.DecodeChain(pfxData, "secret")
+			require.NoError(t, err)
+
+			assert.Equal(t, privateKey, key)
+			assert.Equal(t, chain[0].Raw, cert.Raw)
+			assert.Empty(t, caCerts)
+		})
+	}
+}
+
+func TestEncodePKCS12_unsupported(t *testing.T) {
+	privateKey, chain := generateTestChain(t)
+
+	_, err := EncodePKCS12(privateKey, chain, "ROT13", "secret")
+	require.EqualError(t, err, "pkcs12: unsupported algorithm: ROT13")
+}
+
+func TestEncodeJKS(t *testing.T) {
+	privateKey, chain := generateTestChain(t)
+
+	testCases := []struct {
+		desc     string
+		alias    string
+		expected string
+	}{
+		{
+			desc:     "default alias",
+			expected: JKSDefaultAlias,
+		},
+		{
+			desc:     "alias",
+			alias:    "tomcat",
+			expected: "tomcat",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			jksData, err := EncodeJKS(privateKey, chain, test.alias, "changeit")
+			require.NoError(t, err)
+
+			ks := keystore.New()
+
+			err = ks.Load(bytes.NewReader(jksData), []byte("changeit"))
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{test.expected}, ks.Aliases())
+
+			entry, err := ks.GetPrivateKeyEntry(test.expected, []byte("changeit"))
+			require.NoError(t, err)
+
+			key, err := x509.ParsePKCS8PrivateKey(entry.PrivateKey)
+			require.NoError(t, err)
+
+			assert.Equal(t, privateKey, key)
+
+			require.Len(t, entry.CertificateChain, 1)
+			assert.Equal(t, chain[0].Raw, entry.CertificateChain[0].Content)
+		})
+	}
+}
+
+func TestEncodeJKS_shortPassword(t *testing.T) {
+	privateKey, chain := generateTestChain(t)
+
+	_, err := EncodeJKS(privateKey, chain, "", "12345")
+	require.ErrorIs(t, err, keystore.ErrShortPassword)
+}
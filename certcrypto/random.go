@@ -0,0 +1,39 @@
+package certcrypto
+
+import (
+	"crypto/rand"
+	"io"
+	"sync"
+)
+
+var (
+	randomMu sync.RWMutex
+	random   io.Reader = rand.Reader
+)
+
+// SetRandom replaces the source of randomness used to generate the private keys, and to sign the CSRs and the certificates.
+// It returns a function restoring the previous source.
+//
+// This is for tests only (reproducible keys and CSRs, see the platform/tester package):
+// a predictable source must never be used outside the tests.
+func SetRandom(r io.Reader) (restore func()) {
+	randomMu.Lock()
+	defer randomMu.Unlock()
+
+	previous := random
+	random = r
+
+	return func() {
+		randomMu.Lock()
+		defer randomMu.Unlock()
+
+		random = previous
+	}
+}
+
+func getRandom() io.Reader {
+	randomMu.RLock()
+	defer randomMu.RUnlock()
+
+	return random
+}
@@ -0,0 +1,37 @@
+package certcrypto
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetRandom(t *testing.T) {
+	// A constant source: the generated keys are the same.
+	restore := SetRandom(bytes.NewReader(bytes.Repeat([]byte{1}, 1024)))
+
+	key, err := GeneratePrivateKey(EC256)
+
+	restore()
+
+	require.NoError(t, err)
+
+	restore = SetRandom(bytes.NewReader(bytes.Repeat([]byte{1}, 1024)))
+
+	other, err := GeneratePrivateKey(EC256)
+
+	restore()
+
+	require.NoError(t, err)
+
+	assert.True(t, key.(*ecdsa.PrivateKey).Equal(other))
+
+	// The default source is restored.
+	random, err := GeneratePrivateKey(EC256)
+	require.NoError(t, err)
+
+	assert.False(t, key.(*ecdsa.PrivateKey).Equal(random))
+}
@@ -0,0 +1,88 @@
+package certcrypto
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"time"
+
+	"golang.org/x/crypto/cryptobyte"
+)
+
+// oidSCTList the OID of the embedded SCT list extension (RFC 6962, section 3.3).
+var oidSCTList = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2}
+
+// SignedCertificateTimestamp a Signed Certificate Timestamp embedded in a certificate by the CA:
+// the promise of a Certificate Transparency log to publish the certificate.
+type SignedCertificateTimestamp struct {
+	// Version the version of the SCT (0: v1).
+	Version uint8
+	// LogID the SHA-256 hash of the public key of the log.
+	LogID []byte
+	// Timestamp the date of the SCT.
+	Timestamp time.Time
+	// Signature the signature of the log (DigitallySigned structure).
+	Signature []byte
+}
+
+// ExtractSCTs returns the SCTs embedded in the certificate, or nil if the certificate has no SCT list extension.
+// https://www.rfc-editor.org/rfc/rfc6962.html#section-3.3
+func ExtractSCTs(cert *x509.Certificate) ([]SignedCertificateTimestamp, error) {
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidSCTList) {
+			continue
+		}
+
+		// The extension value is an OCTET STRING containing the TLS-encoded SignedCertificateTimestampList.
+		var raw []byte
+
+		input := cryptobyte.String(ext.Value)
+		if !input.ReadASN1Bytes(&raw, 0x04) || !input.Empty() {
+			return nil, errors.New("invalid SCT list extension")
+		}
+
+		return parseSCTList(raw)
+	}
+
+	return nil, nil
+}
+
+func parseSCTList(raw []byte) ([]SignedCertificateTimestamp, error) {
+	var list cryptobyte.String
+
+	input := cryptobyte.String(raw)
+	if !input.ReadUint16LengthPrefixed(&list) || !input.Empty() {
+		return nil, errors.New("invalid SCT list")
+	}
+
+	var scts []SignedCertificateTimestamp
+
+	for !list.Empty() {
+		var item cryptobyte.String
+		if !list.ReadUint16LengthPrefixed(&item) {
+			return nil, errors.New("invalid SCT list entry")
+		}
+
+		var (
+			sct        SignedCertificateTimestamp
+			timestamp  uint64
+			extensions cryptobyte.String
+		)
+
+		if !item.ReadUint8(&sct.Version) ||
+			!item.ReadBytes(&sct.LogID, 32) ||
+			!item.ReadUint64(&timestamp) ||
+			!item.ReadUint16LengthPrefixed(&extensions) {
+			return nil, errors.New("invalid SCT")
+		}
+
+		// The remaining bytes are the DigitallySigned structure: hash algorithm, signature algorithm, signature.
+		sct.Signature = append([]byte(nil), item...)
+		sct.LogID = append([]byte(nil), sct.LogID...)
+		sct.Timestamp = time.UnixMilli(int64(timestamp)).UTC()
+
+		scts = append(scts, sct)
+	}
+
+	return scts, nil
+}
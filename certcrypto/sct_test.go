@@ -0,0 +1,93 @@
+package certcrypto
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/cryptobyte"
+)
+
+func TestExtractSCTs(t *testing.T) {
+	logID := bytes.Repeat([]byte{0xAB}, 32)
+	timestamp := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	list := cryptobyte.NewBuilder(nil)
+	list.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddUint16LengthPrefixed(func(b *cryptobyte.Builder) {
+			b.AddUint8(0)
+			b.AddBytes(logID)
+			b.AddUint64(uint64(timestamp.UnixMilli()))
+			b.AddUint16LengthPrefixed(func(*cryptobyte.Builder) {})
+			b.AddBytes([]byte{4, 3, 0, 2, 0xCA, 0xFE})
+		})
+	})
+
+	value := cryptobyte.NewBuilder(nil)
+	value.AddASN1OctetString(list.BytesOrPanic())
+
+	cert := createTestCertificate(t, pkix.Extension{Id: oidSCTList, Value: value.BytesOrPanic()})
+
+	scts, err := ExtractSCTs(cert)
+	require.NoError(t, err)
+
+	expected := []SignedCertificateTimestamp{{
+		Version:   0,
+		LogID:     logID,
+		Timestamp: timestamp,
+		Signature: []byte{4, 3, 0, 2, 0xCA, 0xFE},
+	}}
+
+	assert.Equal(t, expected, scts)
+}
+
+func TestExtractSCTs_none(t *testing.T) {
+	cert := createTestCertificate(t)
+
+	scts, err := ExtractSCTs(cert)
+	require.NoError(t, err)
+
+	assert.Nil(t, scts)
+}
+
+func TestExtractSCTs_invalid(t *testing.T) {
+	value := cryptobyte.NewBuilder(nil)
+	value.AddASN1OctetString([]byte{0x00, 0x10, 0x01})
+
+	cert := createTestCertificate(t, pkix.Extension{Id: oidSCTList, Value: value.BytesOrPanic()})
+
+	_, err := ExtractSCTs(cert)
+	require.EqualError(t, err, "invalid SCT list")
+}
+
+func createTestCertificate(t *testing.T, extensions ...pkix.Extension) *x509.Certificate {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:    big.NewInt(1),
+		Subject:         pkix.Name{CommonName: "example.com"},
+		DNSNames:        []string{"example.com"},
+		NotBefore:       time.Now(),
+		NotAfter:        time.Now().Add(time.Hour),
+		ExtraExtensions: extensions,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Public(), privateKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert
+}
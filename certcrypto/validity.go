@@ -0,0 +1,47 @@
+package certcrypto
+
+import (
+	"crypto/x509"
+	"time"
+)
+
+// DefaultClockSkew the default tolerance for the clock skew between the CA and the host.
+const DefaultClockSkew = 5 * time.Minute
+
+// Validity the validity period of a certificate.
+//
+// Some CAs don't backdate the certificates:
+// when the clock of the CA is ahead of the clock of the host, the notBefore of a new certificate is in the future.
+// The clock skew allows to tolerate this difference.
+type Validity struct {
+	NotBefore time.Time
+	NotAfter  time.Time
+
+	// Skew the tolerated clock skew.
+	Skew time.Duration
+}
+
+// NewValidity creates a Validity from a certificate.
+func NewValidity(cert *x509.Certificate, skew time.Duration) Validity {
+	return Validity{
+		NotBefore: cert.NotBefore,
+		NotAfter:  cert.NotAfter,
+		Skew:      max(skew, 0),
+	}
+}
+
+// EffectiveNotBefore returns the beginning of the validity period, including the clock skew tolerance.
+func (v Validity) EffectiveNotBefore() time.Time {
+	return v.NotBefore.Add(-v.Skew)
+}
+
+// IsValidAt reports whether the certificate is valid at t, including the clock skew tolerance.
+func (v Validity) IsValidAt(t time.Time) bool {
+	return !t.Before(v.EffectiveNotBefore()) && !t.After(v.NotAfter)
+}
+
+// StartsIn returns the duration before the notBefore of the certificate (0 if the certificate is already started).
+// It's the time to wait before the certificate is accepted by the clients without clock skew tolerance.
+func (v Validity) StartsIn(t time.Time) time.Duration {
+	return max(v.NotBefore.Sub(t), 0)
+}
@@ -0,0 +1,80 @@
+package certcrypto
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidity(t *testing.T) {
+	now := time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc            string
+		notBefore       time.Time
+		skew            time.Duration
+		expectedValid   bool
+		expectedStartIn time.Duration
+	}{
+		{
+			desc:          "started",
+			notBefore:     now.Add(-time.Hour),
+			skew:          DefaultClockSkew,
+			expectedValid: true,
+		},
+		{
+			desc:            "notBefore in the future, inside the skew",
+			notBefore:       now.Add(2 * time.Minute),
+			skew:            DefaultClockSkew,
+			expectedValid:   true,
+			expectedStartIn: 2 * time.Minute,
+		},
+		{
+			desc:            "notBefore in the future, outside the skew",
+			notBefore:       now.Add(10 * time.Minute),
+			skew:            DefaultClockSkew,
+			expectedStartIn: 10 * time.Minute,
+		},
+		{
+			desc:            "no skew",
+			notBefore:       now.Add(time.Second),
+			expectedStartIn: time.Second,
+		},
+		{
+			desc:            "negative skew",
+			notBefore:       now.Add(time.Second),
+			skew:            -time.Hour,
+			expectedStartIn: time.Second,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cert := &x509.Certificate{
+				NotBefore: test.notBefore,
+				NotAfter:  test.notBefore.Add(90 * 24 * time.Hour),
+			}
+
+			validity := NewValidity(cert, test.skew)
+
+			assert.Equal(t, test.expectedValid, validity.IsValidAt(now))
+			assert.Equal(t, test.expectedStartIn, validity.StartsIn(now))
+		})
+	}
+}
+
+func TestValidity_expired(t *testing.T) {
+	now := time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	validity := NewValidity(&x509.Certificate{
+		NotBefore: now.Add(-48 * time.Hour),
+		NotAfter:  now.Add(-time.Hour),
+	}, DefaultClockSkew)
+
+	assert.False(t, validity.IsValidAt(now))
+	assert.Zero(t, validity.StartsIn(now))
+}
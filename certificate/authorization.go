@@ -1,50 +1,76 @@
 package certificate
 
 import (
+	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/log"
 )
 
+// getAuthorizations gets the authorizations of the order.
+// The requests are spread over a bounded pool of workers (at most overallRequestLimit),
+// so a very large SAN order doesn't spawn one goroutine per authorization.
+// The authorizations are returned in the same order as the URLs of the order.
 func (c *Certifier) getAuthorizations(order acme.ExtendedOrder) ([]acme.Authorization, error) {
-	resc, errc := make(chan acme.Authorization), make(chan domainError)
+	authzURLs := order.Authorizations
 
-	delay := time.Second / time.Duration(c.overallRequestLimit)
+	results := make([]acme.Authorization, len(authzURLs))
+	succeeded := make([]bool, len(authzURLs))
 
-	for _, authzURL := range order.Authorizations {
-		time.Sleep(delay)
+	failures := newObtainError()
 
-		go func(authzURL string) {
-			authz, err := c.core.Authorizations.Get(authzURL)
-			if err != nil {
-				errc <- domainError{Domain: authz.Identifier.Value, Error: err}
-				return
-			}
+	var failuresMu sync.Mutex
 
-			resc <- authz
-		}(authzURL)
-	}
+	jobs := make(chan int)
 
-	var responses []acme.Authorization
+	var wg sync.WaitGroup
 
-	failures := newObtainError()
+	for range min(c.overallRequestLimit, len(authzURLs)) {
+		wg.Add(1)
 
-	for range len(order.Authorizations) {
-		select {
-		case res := <-resc:
-			responses = append(responses, res)
-		case err := <-errc:
-			failures.Add(err.Domain, err.Error)
-		}
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				authz, err := c.core.Authorizations.Get(authzURLs[i])
+				if err != nil {
+					// The authorization is unknown: the failure is identified by its URL.
+					failuresMu.Lock()
+					failures.Add(authzURLs[i], err)
+					failuresMu.Unlock()
+
+					continue
+				}
+
+				log.Infof("[%s] AuthURL: %s", authz.Identifier.Value, authzURLs[i])
+
+				results[i] = authz
+				succeeded[i] = true
+			}
+		}()
 	}
 
-	for i, auth := range order.Authorizations {
-		log.Infof("[%s] AuthURL: %s", order.Identifiers[i].Value, auth)
+	delay := time.Second / time.Duration(c.overallRequestLimit)
+
+	for i := range authzURLs {
+		time.Sleep(delay)
+
+		jobs <- i
 	}
 
-	close(resc)
-	close(errc)
+	close(jobs)
+
+	wg.Wait()
+
+	// Compacts the results in place to avoid a second allocation.
+	responses := results[:0]
+
+	for i, authz := range results {
+		if succeeded[i] {
+			responses = append(responses, authz)
+		}
+	}
 
 	return responses, failures.Join()
 }
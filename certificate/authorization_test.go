@@ -0,0 +1,150 @@
+package certificate
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertifier_getAuthorizations(t *testing.T) {
+	server := tester.MockACMEServer().
+		Route("POST /authz/{id}", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			id, err := strconv.Atoi(req.PathValue("id"))
+			if err != nil {
+				http.Error(rw, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if id%5 == 4 {
+				rw.Header().Set("Content-Type", "application/problem+json")
+				rw.WriteHeader(http.StatusNotFound)
+				_, _ = fmt.Fprint(rw, `{"type":"urn:ietf:params:acme:error:malformed","detail":"not found"}`)
+
+				return
+			}
+
+			_, _ = fmt.Fprintf(rw, `{"status":"pending","identifier":{"type":"dns","value":"%d.example.com"}}`, id)
+		})).
+		BuildHTTPS(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", key)
+	require.NoError(t, err)
+
+	certifier := NewCertifier(core, &resolverMock{}, CertifierOptions{KeyType: certcrypto.RSA2048, OverallRequestLimit: 100})
+
+	order := acme.ExtendedOrder{}
+
+	for i := range 20 {
+		order.Authorizations = append(order.Authorizations, fmt.Sprintf("%s/authz/%d", server.URL, i))
+	}
+
+	authz, err := certifier.getAuthorizations(order)
+	require.Error(t, err)
+
+	// The failures are identified by the URLs of the authorizations.
+	for _, id := range []int{4, 9, 14, 19} {
+		assert.ErrorContains(t, err, fmt.Sprintf("%s/authz/%d: ", server.URL, id))
+	}
+
+	var domains []string
+	for _, auth := range authz {
+		domains = append(domains, auth.Identifier.Value)
+	}
+
+	expected := []string{
+		"0.example.com", "1.example.com", "2.example.com", "3.example.com",
+		"5.example.com", "6.example.com", "7.example.com", "8.example.com",
+		"10.example.com", "11.example.com", "12.example.com", "13.example.com",
+		"15.example.com", "16.example.com", "17.example.com", "18.example.com",
+	}
+
+	assert.Equal(t, expected, domains)
+}
+
+type blockingResolverMock struct {
+	solving chan struct{}
+	release chan struct{}
+	cleaned atomic.Bool
+}
+
+func (r *blockingResolverMock) Solve(_ []acme.Authorization) error {
+	close(r.solving)
+	<-r.release
+
+	return errors.New("abandoned")
+}
+
+func (r *blockingResolverMock) CleanUpPending() {
+	r.cleaned.Store(true)
+}
+
+func TestCertifier_Abandon(t *testing.T) {
+	var authzCalls atomic.Int32
+
+	server := tester.MockACMEServer().
+		Route("POST /newOrder", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			serverURL := "https://" + req.Host
+
+			rw.Header().Set("Location", serverURL+"/order/1")
+			rw.WriteHeader(http.StatusCreated)
+
+			_, _ = fmt.Fprintf(rw, `{"status":"pending","identifiers":[{"type":"dns","value":"example.com"}],"authorizations":["%[1]s/authz/1"],"finalize":"%[1]s/finalize/1"}`, serverURL)
+		})).
+		Route("POST /authz/1", http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			authzCalls.Add(1)
+
+			_, _ = fmt.Fprint(rw, `{"status":"pending","identifier":{"type":"dns","value":"example.com"}}`)
+		})).
+		BuildHTTPS(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", key)
+	require.NoError(t, err)
+
+	resolver := &blockingResolverMock{
+		solving: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+
+	certifier := NewCertifier(core, resolver, CertifierOptions{KeyType: certcrypto.RSA2048})
+
+	done := make(chan error)
+
+	go func() {
+		_, errO := certifier.Obtain(ObtainRequest{Domains: []string{"example.com"}})
+		done <- errO
+	}()
+
+	<-resolver.solving
+
+	certifier.Abandon()
+
+	assert.True(t, resolver.cleaned.Load())
+
+	// 1 call to get the authorization before the resolution,
+	// 2 calls to get and to deactivate the pending authorization.
+	assert.Equal(t, int32(3), authzCalls.Load())
+
+	close(resolver.release)
+
+	require.Error(t, <-done)
+
+	assert.Empty(t, certifier.orders)
+}
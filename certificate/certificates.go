@@ -1,22 +1,27 @@
 package certificate
 
 import (
-	"bytes"
+	"context"
 	"crypto"
 	"crypto/x509"
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"io"
+	"maps"
 	"net/http"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/cenkalti/backoff/v5"
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/acme/api"
 	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/onioncsr01"
 	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/errcode"
 	"github.com/go-acme/lego/v4/platform/wait"
 	"golang.org/x/crypto/ocsp"
 	"golang.org/x/net/idna"
@@ -31,6 +36,10 @@ const (
 	// ZeroSSL has a limit of 7.
 	// https://help.zerossl.com/hc/en-us/articles/17864245480093-Advantages-over-Using-Let-s-Encrypt#h_01HT4Z1JCJFJQFJ1M3P7S085Q9
 	DefaultOverallRequestLimit = 18
+
+	// DefaultDownloadRetries is the number of retries of the certificate download
+	// when the certificate is not available yet at the certificate URL.
+	DefaultDownloadRetries = 5
 )
 
 // maxBodySize is the maximum size of body that we will read.
@@ -49,6 +58,9 @@ type Resource struct {
 	Certificate       []byte `json:"-"`
 	IssuerCertificate []byte `json:"-"`
 	CSR               []byte `json:"-"`
+
+	// The domains removed from the request because they are covered by a wildcard domain (ObtainRequest.CollapseIntoWildcards).
+	CollapsedDomains []string `json:"-"`
 }
 
 // ObtainRequest The request to obtain certificate.
@@ -64,12 +76,22 @@ type Resource struct {
 //
 // If `AlwaysDeactivateAuthorizations` is true, the authorizations are also relinquished if the obtain request was successful.
 // See https://datatracker.ietf.org/doc/html/rfc8555#section-7.5.2.
+//
+// If `OmitPrivateKey` is true, the private key is not PEM-encoded into the Resource:
+// the key is only held by the caller (ex: as a crypto.Signer), and `PrivateKey` is required.
+// A private key without exportable key material (ex: a crypto.Signer backed by an HSM) is never PEM-encoded.
+//
+// If `CollapseIntoWildcards` is true, the domains covered by a wildcard domain of the request
+// (ex: `www.example.com` with `*.example.com`) are removed from the order,
+// the removed domains are reported in `Resource.CollapsedDomains`.
 type ObtainRequest struct {
 	Domains        []string
 	PrivateKey     crypto.PrivateKey
 	MustStaple     bool
 	EmailAddresses []string
 
+	// The requested validity period of the certificate (optional, only honored by some CAs).
+	// The period is checked against the maximum validity of the CA (CertifierOptions.MaxValidity).
 	NotBefore      time.Time
 	NotAfter       time.Time
 	Bundle         bool
@@ -86,6 +108,15 @@ type ObtainRequest struct {
 	// order is intended to replace.
 	// - https://www.rfc-editor.org/rfc/rfc9773.html#section-5
 	ReplacesCertID string
+
+	OmitPrivateKey bool
+
+	CollapseIntoWildcards bool
+
+	// The keys of the hidden services signing the CA nonces of the onion-csr-01 challenges (.onion domains) of this request.
+	// If nil, the keys defined on the solver (resolver.SolverManager.SetOnionCSR01Provider) are used.
+	// - https://www.rfc-editor.org/rfc/rfc9799.html#section-3.2
+	OnionKeys onioncsr01.KeyProvider
 }
 
 // ObtainForCSRRequest The request to obtain a certificate matching the CSR passed into it.
@@ -94,11 +125,15 @@ type ObtainRequest struct {
 //
 // If `AlwaysDeactivateAuthorizations` is true, the authorizations are also relinquished if the obtain request was successful.
 // See https://datatracker.ietf.org/doc/html/rfc8555#section-7.5.2.
+//
+// If `OmitPrivateKey` is true, the private key is not PEM-encoded into the Resource.
 type ObtainForCSRRequest struct {
 	CSR *x509.CertificateRequest
 
 	PrivateKey crypto.PrivateKey
 
+	// The requested validity period of the certificate (optional, only honored by some CAs).
+	// The period is checked against the maximum validity of the CA (CertifierOptions.MaxValidity).
 	NotBefore      time.Time
 	NotAfter       time.Time
 	Bundle         bool
@@ -115,17 +150,47 @@ type ObtainForCSRRequest struct {
 	// order is intended to replace.
 	// - https://www.rfc-editor.org/rfc/rfc9773.html#section-5
 	ReplacesCertID string
+
+	OmitPrivateKey bool
+
+	// The keys of the hidden services signing the CA nonces of the onion-csr-01 challenges (.onion domains) of this request.
+	// If nil, the keys defined on the solver (resolver.SolverManager.SetOnionCSR01Provider) are used.
+	// - https://www.rfc-editor.org/rfc/rfc9799.html#section-3.2
+	OnionKeys onioncsr01.KeyProvider
 }
 
 type resolver interface {
 	Solve(authorizations []acme.Authorization) error
 }
 
+// onionResolver a resolver able to solve the onion-csr-01 challenges with the keys of the hidden services of a request.
+type onionResolver interface {
+	SolveWithOnionKeys(authorizations []acme.Authorization, keys onioncsr01.KeyProvider) error
+}
+
+// pendingCleaner a resolver able to clean up the challenges of the in-flight calls to Solve.
+type pendingCleaner interface {
+	CleanUpPending()
+}
+
 type CertifierOptions struct {
 	KeyType             certcrypto.KeyType
 	Timeout             time.Duration
 	OverallRequestLimit int
 	DisableCommonName   bool
+
+	// DownloadRetries the number of retries of the certificate download (404, 202),
+	// before fetching the order again to discover the certificate URL.
+	// 0 uses DefaultDownloadRetries, a negative value disables the retries.
+	DownloadRetries int
+	// DownloadRetryInterval the initial interval between the retries (exponential backoff).
+	// Defaults to 1 second.
+	DownloadRetryInterval time.Duration
+
+	// MaxValidity the maximum validity of the certificates of the CA (see lego.CAQuirks),
+	// the requested validity periods (NotBefore, NotAfter) are checked against it.
+	// 0 disables the check.
+	MaxValidity time.Duration
 }
 
 // Certifier A service to obtain/renew/revoke certificates.
@@ -134,6 +199,10 @@ type Certifier struct {
 	resolver            resolver
 	options             CertifierOptions
 	overallRequestLimit int
+
+	// orders keeps the in-flight orders (the key is the order URL).
+	orders   map[string]acme.ExtendedOrder
+	ordersMu sync.Mutex
 }
 
 // NewCertifier creates a Certifier.
@@ -142,6 +211,7 @@ func NewCertifier(core *api.Core, resolver resolver, options CertifierOptions) *
 		core:     core,
 		resolver: resolver,
 		options:  options,
+		orders:   make(map[string]acme.ExtendedOrder),
 	}
 
 	c.overallRequestLimit = options.OverallRequestLimit
@@ -161,8 +231,21 @@ func (c *Certifier) Obtain(request ObtainRequest) (*Resource, error) {
 		return nil, errors.New("no domains to obtain a certificate for")
 	}
 
+	if request.OmitPrivateKey && request.PrivateKey == nil {
+		return nil, errors.New("the private key is required when the private key is omitted from the resource")
+	}
+
 	domains := sanitizeDomain(request.Domains)
 
+	var collapsed []string
+	if request.CollapseIntoWildcards {
+		domains, collapsed = collapseIntoWildcards(domains)
+		if len(collapsed) > 0 {
+			log.Infof("[%s] acme: Domains covered by a wildcard removed from the order: %s",
+				strings.Join(domains, ", "), strings.Join(collapsed, ", "))
+		}
+	}
+
 	if request.Bundle {
 		log.Infof("[%s] acme: Obtaining bundled SAN certificate", strings.Join(domains, ", "))
 	} else {
@@ -174,6 +257,7 @@ func (c *Certifier) Obtain(request ObtainRequest) (*Resource, error) {
 		NotAfter:       request.NotAfter,
 		Profile:        request.Profile,
 		ReplacesCertID: request.ReplacesCertID,
+		MaxValidity:    c.options.MaxValidity,
 	}
 
 	order, err := c.core.Orders.NewWithOptions(domains, orderOpts)
@@ -181,6 +265,9 @@ func (c *Certifier) Obtain(request ObtainRequest) (*Resource, error) {
 		return nil, err
 	}
 
+	c.trackOrder(order)
+	defer c.untrackOrder(order)
+
 	authz, err := c.getAuthorizations(order)
 	if err != nil {
 		// If any challenge fails, return. Do not generate partial SAN certificates.
@@ -188,7 +275,7 @@ func (c *Certifier) Obtain(request ObtainRequest) (*Resource, error) {
 		return nil, err
 	}
 
-	err = c.resolver.Solve(authz)
+	err = c.solve(authz, request.OnionKeys)
 	if err != nil {
 		// If any challenge fails, return. Do not generate partial SAN certificates.
 		c.deactivateAuthorizations(order, request.AlwaysDeactivateAuthorizations)
@@ -206,6 +293,10 @@ func (c *Certifier) Obtain(request ObtainRequest) (*Resource, error) {
 		}
 	}
 
+	if cert != nil {
+		cert.CollapsedDomains = collapsed
+	}
+
 	if request.AlwaysDeactivateAuthorizations {
 		c.deactivateAuthorizations(order, true)
 	}
@@ -242,6 +333,7 @@ func (c *Certifier) ObtainForCSR(request ObtainForCSRRequest) (*Resource, error)
 		NotAfter:       request.NotAfter,
 		Profile:        request.Profile,
 		ReplacesCertID: request.ReplacesCertID,
+		MaxValidity:    c.options.MaxValidity,
 	}
 
 	order, err := c.core.Orders.NewWithOptions(domains, orderOpts)
@@ -249,6 +341,9 @@ func (c *Certifier) ObtainForCSR(request ObtainForCSRRequest) (*Resource, error)
 		return nil, err
 	}
 
+	c.trackOrder(order)
+	defer c.untrackOrder(order)
+
 	authz, err := c.getAuthorizations(order)
 	if err != nil {
 		// If any challenge fails, return. Do not generate partial SAN certificates.
@@ -256,7 +351,7 @@ func (c *Certifier) ObtainForCSR(request ObtainForCSRRequest) (*Resource, error)
 		return nil, err
 	}
 
-	err = c.resolver.Solve(authz)
+	err = c.solve(authz, request.OnionKeys)
 	if err != nil {
 		// If any challenge fails, return. Do not generate partial SAN certificates.
 		c.deactivateAuthorizations(order, request.AlwaysDeactivateAuthorizations)
@@ -268,7 +363,7 @@ func (c *Certifier) ObtainForCSR(request ObtainForCSRRequest) (*Resource, error)
 	failures := newObtainError()
 
 	var privateKey []byte
-	if request.PrivateKey != nil {
+	if request.PrivateKey != nil && !request.OmitPrivateKey {
 		privateKey = certcrypto.PEMEncode(request.PrivateKey)
 	}
 
@@ -291,6 +386,51 @@ func (c *Certifier) ObtainForCSR(request ObtainForCSRRequest) (*Resource, error)
 	return cert, failures.Join()
 }
 
+// Abandon abandons the in-flight orders:
+// the challenges presented and not yet cleaned up are cleaned up, and the pending authorizations are deactivated.
+// It's intended to be called when the process is stopped (e.g. on SIGTERM) while certificates are obtained.
+func (c *Certifier) Abandon() {
+	if cleaner, ok := c.resolver.(pendingCleaner); ok {
+		cleaner.CleanUpPending()
+	}
+
+	c.ordersMu.Lock()
+	orders := slices.Collect(maps.Values(c.orders))
+	c.ordersMu.Unlock()
+
+	for _, order := range orders {
+		c.deactivateAuthorizations(order, false)
+	}
+}
+
+func (c *Certifier) trackOrder(order acme.ExtendedOrder) {
+	c.ordersMu.Lock()
+	defer c.ordersMu.Unlock()
+
+	c.orders[order.Location] = order
+}
+
+func (c *Certifier) untrackOrder(order acme.ExtendedOrder) {
+	c.ordersMu.Lock()
+	defer c.ordersMu.Unlock()
+
+	delete(c.orders, order.Location)
+}
+
+// solve solves the authorizations, with the keys of the hidden services of the request if any.
+func (c *Certifier) solve(authz []acme.Authorization, onionKeys onioncsr01.KeyProvider) error {
+	if onionKeys == nil {
+		return c.resolver.Solve(authz)
+	}
+
+	r, ok := c.resolver.(onionResolver)
+	if !ok {
+		return errors.New("the resolver doesn't support the keys of the hidden services of the request")
+	}
+
+	return r.SolveWithOnionKeys(authz, onionKeys)
+}
+
 func (c *Certifier) getForOrder(domains []string, order acme.ExtendedOrder, request ObtainRequest) (*Resource, error) {
 	privateKey := request.PrivateKey
 
@@ -338,13 +478,18 @@ func (c *Certifier) getForOrder(domains []string, order acme.ExtendedOrder, requ
 		return nil, err
 	}
 
-	return c.getForCSR(domains, order, request.Bundle, csr, certcrypto.PEMEncode(privateKey), request.PreferredChain)
+	var privateKeyPem []byte
+	if !request.OmitPrivateKey {
+		privateKeyPem = certcrypto.PEMEncode(privateKey)
+	}
+
+	return c.getForCSR(domains, order, request.Bundle, csr, privateKeyPem, request.PreferredChain)
 }
 
 func (c *Certifier) getForCSR(domains []string, order acme.ExtendedOrder, bundle bool, csr, privateKeyPem []byte, preferredChain string) (*Resource, error) {
 	respOrder, err := c.core.Orders.UpdateForCSR(order.Finalize, csr)
 	if err != nil {
-		return nil, err
+		return nil, diagnoseCSR(err, order.Identifiers, csr)
 	}
 
 	certRes := &Resource{
@@ -353,6 +498,8 @@ func (c *Certifier) getForCSR(domains []string, order acme.ExtendedOrder, bundle
 		PrivateKey: privateKeyPem,
 	}
 
+	respOrder.Location = order.Location
+
 	if respOrder.Status == acme.StatusValid {
 		// if the certificate is available right away, shortcut!
 		ok, errR := c.checkResponse(respOrder, certRes, bundle, preferredChain)
@@ -383,6 +530,9 @@ func (c *Certifier) getForCSR(domains []string, order acme.ExtendedOrder, bundle
 
 		return done, nil
 	})
+	if errors.Is(err, wait.ErrTimeLimitExceeded) {
+		err = errcode.Wrap(errcode.CACertificateTimeout, err)
+	}
 
 	return certRes, err
 }
@@ -401,16 +551,16 @@ func (c *Certifier) checkResponse(order acme.ExtendedOrder, certRes *Resource, b
 		return valid, err
 	}
 
-	certs, err := c.core.Certificates.GetAll(order.Certificate, bundle)
+	certURL, certs, err := c.downloadCertificates(order, bundle)
 	if err != nil {
 		return false, err
 	}
 
 	// Set the default certificate
-	certRes.IssuerCertificate = certs[order.Certificate].Issuer
-	certRes.Certificate = certs[order.Certificate].Cert
-	certRes.CertURL = order.Certificate
-	certRes.CertStableURL = order.Certificate
+	certRes.IssuerCertificate = certs[certURL].Issuer
+	certRes.Certificate = certs[certURL].Cert
+	certRes.CertURL = certURL
+	certRes.CertStableURL = certURL
 
 	if preferredChain == "" {
 		log.Infof("[%s] Server responded with a certificate.", certRes.Domain)
@@ -441,6 +591,85 @@ func (c *Certifier) checkResponse(order acme.ExtendedOrder, certRes *Resource, b
 	return true, nil
 }
 
+// downloadCertificates downloads the certificates of a valid order, and returns the URL of the default certificate.
+//
+// Some CAs return a 404 or a 202 at the certificate URL right after the finalization (eventual consistency):
+// the download is retried with a backoff, then the order is fetched again to discover the final certificate URL.
+func (c *Certifier) downloadCertificates(order acme.ExtendedOrder, bundle bool) (string, map[string]*acme.RawCertificate, error) {
+	certs, err := c.getCertificates(order.Certificate, bundle)
+	if err == nil || !isCertificateNotReady(err) || order.Location == "" {
+		return order.Certificate, certs, err
+	}
+
+	log.Infof("The certificate is not available at %s, fetching the order again.", order.Certificate)
+
+	ord, errO := c.core.Orders.Get(order.Location)
+	if errO != nil {
+		return "", nil, fmt.Errorf("%w (order: %w)", err, errO)
+	}
+
+	if ord.Certificate == "" || ord.Certificate == order.Certificate {
+		return "", nil, err
+	}
+
+	certs, err = c.getCertificates(ord.Certificate, bundle)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return ord.Certificate, certs, nil
+}
+
+// getCertificates downloads the certificates, the download is retried while the certificate is not available.
+func (c *Certifier) getCertificates(certURL string, bundle bool) (map[string]*acme.RawCertificate, error) {
+	retries := c.options.DownloadRetries
+	if retries == 0 {
+		retries = DefaultDownloadRetries
+	}
+
+	interval := c.options.DownloadRetryInterval
+	if interval <= 0 {
+		interval = 1 * time.Second
+	}
+
+	bo := backoff.NewExponentialBackOff()
+	bo.InitialInterval = interval
+	bo.MaxInterval = 10 * interval
+
+	var certs map[string]*acme.RawCertificate
+
+	operation := func() error {
+		var err error
+
+		certs, err = c.core.Certificates.GetAll(certURL, bundle)
+		if err != nil && !isCertificateNotReady(err) {
+			return backoff.Permanent(err)
+		}
+
+		return err
+	}
+
+	err := wait.Retry(context.Background(), operation,
+		backoff.WithBackOff(bo),
+		backoff.WithMaxTries(uint(max(retries, 0)+1)))
+	if err != nil {
+		return nil, err
+	}
+
+	return certs, nil
+}
+
+// isCertificateNotReady returns true if the certificate is not available yet (202, empty body, or 404).
+func isCertificateNotReady(err error) bool {
+	if errors.Is(err, api.ErrCertificateNotReady) {
+		return true
+	}
+
+	var problem *acme.ProblemDetails
+
+	return errors.As(err, &problem) && problem.HTTPStatus == http.StatusNotFound
+}
+
 // Revoke takes a PEM encoded certificate or bundle and tries to revoke it at the CA.
 func (c *Certifier) Revoke(cert []byte) error {
 	return c.RevokeWithReason(cert, nil)
@@ -588,76 +817,14 @@ func (c *Certifier) RenewWithOptions(certRes Resource, options *RenewOptions) (*
 // this function will try to get the issuer certificate from the IssuingCertificateURL in the certificate.
 //
 // If the []byte and/or ocsp.Response return values are nil, the OCSP status may be assumed OCSPUnknown.
+// See OCSPCache to reuse the responses until their refresh.
 func (c *Certifier) GetOCSP(bundle []byte) ([]byte, *ocsp.Response, error) {
 	certificates, err := certcrypto.ParsePEMBundle(bundle)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// We expect the certificate slice to be ordered downwards the chain.
-	// SRV CRT -> CA. We need to pull the leaf and issuer certs out of it,
-	// which should always be the first two certificates.
-	// If there's no OCSP server listed in the leaf cert, there's nothing to do.
-	// And if we have only one certificate so far, we need to get the issuer cert.
-
-	issuedCert := certificates[0]
-
-	if len(issuedCert.OCSPServer) == 0 {
-		return nil, nil, errors.New("no OCSP server specified in cert")
-	}
-
-	if len(certificates) == 1 {
-		// TODO: build fallback. If this fails, check the remaining array entries.
-		if len(issuedCert.IssuingCertificateURL) == 0 {
-			return nil, nil, errors.New("no issuing certificate URL")
-		}
-
-		resp, errC := c.core.HTTPClient.Get(issuedCert.IssuingCertificateURL[0])
-		if errC != nil {
-			return nil, nil, errC
-		}
-		defer resp.Body.Close()
-
-		issuerBytes, errC := io.ReadAll(http.MaxBytesReader(nil, resp.Body, maxBodySize))
-		if errC != nil {
-			return nil, nil, errC
-		}
-
-		issuerCert, errC := x509.ParseCertificate(issuerBytes)
-		if errC != nil {
-			return nil, nil, errC
-		}
-
-		// Insert it into the slice on position 0
-		// We want it ordered right SRV CRT -> CA
-		certificates = append(certificates, issuerCert)
-	}
-
-	issuerCert := certificates[1]
-
-	// Finally kick off the OCSP request.
-	ocspReq, err := ocsp.CreateRequest(issuedCert, issuerCert, nil)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	resp, err := c.core.HTTPClient.Post(issuedCert.OCSPServer[0], "application/ocsp-request", bytes.NewReader(ocspReq))
-	if err != nil {
-		return nil, nil, err
-	}
-	defer resp.Body.Close()
-
-	ocspResBytes, err := io.ReadAll(http.MaxBytesReader(nil, resp.Body, maxBodySize))
-	if err != nil {
-		return nil, nil, err
-	}
-
-	ocspRes, err := ocsp.ParseResponse(ocspResBytes, issuerCert)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return ocspResBytes, ocspRes, nil
+	return fetchOCSP(c.core.HTTPClient, certificates)
 }
 
 // Get attempts to fetch the certificate at the supplied URL.
@@ -737,3 +904,37 @@ func sanitizeDomain(domains []string) []string {
 
 	return sanitizedDomains
 }
+
+// collapseIntoWildcards removes the domains covered by a wildcard domain of the list.
+// A wildcard domain only covers one label (ex: `*.example.com` covers `www.example.com`,
+// but neither `example.com` nor `a.b.example.com`).
+// Returns the kept domains (in the original order), and the removed domains.
+func collapseIntoWildcards(domains []string) ([]string, []string) {
+	wildcards := make(map[string]struct{})
+
+	for _, domain := range domains {
+		if base, ok := strings.CutPrefix(strings.ToLower(domain), "*."); ok {
+			wildcards[base] = struct{}{}
+		}
+	}
+
+	if len(wildcards) == 0 {
+		return domains, nil
+	}
+
+	var kept, collapsed []string
+
+	for _, domain := range domains {
+		name := strings.ToLower(domain)
+
+		_, parent, found := strings.Cut(name, ".")
+		if _, covered := wildcards[parent]; found && covered && !strings.HasPrefix(name, "*.") {
+			collapsed = append(collapsed, domain)
+			continue
+		}
+
+		kept = append(kept, domain)
+	}
+
+	return kept, collapsed
+}
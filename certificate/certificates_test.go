@@ -5,11 +5,14 @@ import (
 	"crypto/rsa"
 	"fmt"
 	"net/http"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/acme/api"
 	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/challenge/onioncsr01"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/go-acme/lego/v4/platform/tester/servermock"
 	"github.com/stretchr/testify/assert"
@@ -320,6 +323,128 @@ func Test_checkResponse_alternate(t *testing.T) {
 	assert.Equal(t, issuerMock2, string(certRes.IssuerCertificate), "IssuerCertificate")
 }
 
+func Test_checkResponse_notReady(t *testing.T) {
+	var calls atomic.Int32
+
+	server := tester.MockACMEServer().
+		Route("POST /certificate",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if calls.Add(1) <= 2 {
+					rw.WriteHeader(http.StatusAccepted)
+					return
+				}
+
+				servermock.RawStringResponse(certResponseMock).ServeHTTP(rw, req)
+			})).
+		BuildHTTPS(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", key)
+	require.NoError(t, err)
+
+	certifier := NewCertifier(core, &resolverMock{}, CertifierOptions{
+		KeyType:               certcrypto.RSA2048,
+		DownloadRetryInterval: 10 * time.Millisecond,
+	})
+
+	order := acme.ExtendedOrder{
+		Order: acme.Order{
+			Status:      acme.StatusValid,
+			Certificate: server.URL + "/certificate",
+		},
+	}
+	certRes := &Resource{}
+
+	valid, err := certifier.checkResponse(order, certRes, true, "")
+	require.NoError(t, err)
+
+	assert.True(t, valid)
+	assert.EqualValues(t, 3, calls.Load())
+	assert.Equal(t, certResponseMock, string(certRes.Certificate), "Certificate")
+}
+
+func Test_checkResponse_notReady_noRetries(t *testing.T) {
+	var calls atomic.Int32
+
+	server := tester.MockACMEServer().
+		Route("POST /certificate",
+			http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				calls.Add(1)
+				rw.WriteHeader(http.StatusAccepted)
+			})).
+		BuildHTTPS(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", key)
+	require.NoError(t, err)
+
+	certifier := NewCertifier(core, &resolverMock{}, CertifierOptions{
+		KeyType:         certcrypto.RSA2048,
+		DownloadRetries: -1,
+	})
+
+	order := acme.ExtendedOrder{
+		Order: acme.Order{
+			Status:      acme.StatusValid,
+			Certificate: server.URL + "/certificate",
+		},
+	}
+
+	_, err = certifier.checkResponse(order, &Resource{}, true, "")
+	require.ErrorIs(t, err, api.ErrCertificateNotReady)
+
+	assert.EqualValues(t, 1, calls.Load())
+}
+
+func Test_checkResponse_orderFallback(t *testing.T) {
+	server := tester.MockACMEServer().
+		Route("POST /certificate",
+			servermock.RawStringResponse(`{"type":"urn:ietf:params:acme:error:malformed","detail":"Certificate not found","status":404}`).
+				WithStatusCode(http.StatusNotFound)).
+		Route("POST /order",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				servermock.JSONEncode(acme.Order{
+					Status:      acme.StatusValid,
+					Certificate: fmt.Sprintf("https://%s/certificate/final", req.Context().Value(http.LocalAddrContextKey)),
+				}).ServeHTTP(rw, req)
+			})).
+		Route("POST /certificate/final", servermock.RawStringResponse(certResponseMock)).
+		BuildHTTPS(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", key)
+	require.NoError(t, err)
+
+	certifier := NewCertifier(core, &resolverMock{}, CertifierOptions{
+		KeyType:               certcrypto.RSA2048,
+		DownloadRetries:       1,
+		DownloadRetryInterval: 10 * time.Millisecond,
+	})
+
+	order := acme.ExtendedOrder{
+		Order: acme.Order{
+			Status:      acme.StatusValid,
+			Certificate: server.URL + "/certificate",
+		},
+		Location: server.URL + "/order",
+	}
+	certRes := &Resource{}
+
+	valid, err := certifier.checkResponse(order, certRes, true, "")
+	require.NoError(t, err)
+
+	assert.True(t, valid)
+	assert.Equal(t, server.URL+"/certificate/final", certRes.CertURL)
+	assert.Equal(t, server.URL+"/certificate/final", certRes.CertStableURL)
+	assert.Equal(t, certResponseMock, string(certRes.Certificate), "Certificate")
+}
+
 func Test_Get(t *testing.T) {
 	server := tester.MockACMEServer().
 		Route("POST /acme/cert/test-cert", servermock.RawStringResponse(certResponseMock)).
@@ -346,6 +471,21 @@ func Test_Get(t *testing.T) {
 	assert.Equal(t, issuerMock, string(certRes.IssuerCertificate), "IssuerCertificate")
 }
 
+func TestCertifier_Obtain_omitPrivateKeyWithoutKey(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", key)
+	require.NoError(t, err)
+
+	certifier := NewCertifier(core, &resolverMock{}, CertifierOptions{KeyType: certcrypto.RSA2048})
+
+	_, err = certifier.Obtain(ObtainRequest{Domains: []string{"acme.wtf"}, OmitPrivateKey: true})
+	require.EqualError(t, err, "the private key is required when the private key is omitted from the resource")
+}
+
 func Test_checkOrderStatus(t *testing.T) {
 	testCases := []struct {
 		desc       string
@@ -391,6 +531,70 @@ func Test_checkOrderStatus(t *testing.T) {
 	}
 }
 
+func Test_collapseIntoWildcards(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		domains           []string
+		expected          []string
+		expectedCollapsed []string
+	}{
+		{
+			desc:     "no wildcard",
+			domains:  []string{"example.com", "www.example.com"},
+			expected: []string{"example.com", "www.example.com"},
+		},
+		{
+			desc:              "subdomains",
+			domains:           []string{"example.com", "www.example.com", "*.example.com", "API.example.com"},
+			expected:          []string{"example.com", "*.example.com"},
+			expectedCollapsed: []string{"www.example.com", "API.example.com"},
+		},
+		{
+			desc:     "only one label",
+			domains:  []string{"*.example.com", "a.b.example.com", "a.example.org"},
+			expected: []string{"*.example.com", "a.b.example.com", "a.example.org"},
+		},
+		{
+			desc:              "nested wildcards",
+			domains:           []string{"*.example.com", "*.b.example.com", "a.b.example.com"},
+			expected:          []string{"*.example.com", "*.b.example.com"},
+			expectedCollapsed: []string{"a.b.example.com"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			domains, collapsed := collapseIntoWildcards(test.domains)
+
+			assert.Equal(t, test.expected, domains)
+			assert.Equal(t, test.expectedCollapsed, collapsed)
+		})
+	}
+}
+
+func TestCertifier_solve_onionKeys(t *testing.T) {
+	keys := onioncsr01.Keys{}
+
+	onionResolver := &onionResolverMock{}
+
+	certifier := NewCertifier(nil, onionResolver, CertifierOptions{})
+
+	err := certifier.solve(nil, keys)
+	require.NoError(t, err)
+
+	assert.Equal(t, keys, onionResolver.keys)
+
+	certifier = NewCertifier(nil, &resolverMock{}, CertifierOptions{})
+
+	err = certifier.solve(nil, keys)
+	require.EqualError(t, err, "the resolver doesn't support the keys of the hidden services of the request")
+
+	err = certifier.solve(nil, nil)
+	require.NoError(t, err)
+}
+
 type resolverMock struct {
 	error error
 }
@@ -398,3 +602,15 @@ type resolverMock struct {
 func (r *resolverMock) Solve(_ []acme.Authorization) error {
 	return r.error
 }
+
+type onionResolverMock struct {
+	resolverMock
+
+	keys onioncsr01.KeyProvider
+}
+
+func (r *onionResolverMock) SolveWithOnionKeys(_ []acme.Authorization, keys onioncsr01.KeyProvider) error {
+	r.keys = keys
+
+	return r.error
+}
@@ -1,8 +1,18 @@
 package certificate
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/go-acme/lego/v4/acme"
 )
 
 type obtainError struct {
@@ -27,8 +37,8 @@ func (e *obtainError) Join() error {
 	}
 
 	var err error
-	for d, e := range e.data {
-		err = errors.Join(err, fmt.Errorf("%s: %w", d, e))
+	for _, d := range slices.Sorted(maps.Keys(e.data)) {
+		err = errors.Join(err, fmt.Errorf("%s: %w", d, e.data[d]))
 	}
 
 	return fmt.Errorf("error: one or more domains had a problem:\n%w", err)
@@ -38,3 +48,124 @@ type domainError struct {
 	Domain string
 	Error  error
 }
+
+// CSRError explains why the CA rejected the CSR of an order (`badCSR`),
+// by comparing the CSR with the identifiers of the order.
+type CSRError struct {
+	*acme.ProblemDetails
+
+	// Missing the identifiers of the order missing from the CSR.
+	Missing []string
+	// Extra the names of the CSR not requested by the order.
+	Extra []string
+	// Reasons the other problems of the CSR (ex: unsupported key).
+	Reasons []string
+}
+
+func (e *CSRError) Error() string {
+	var parts []string
+
+	if len(e.Missing) > 0 {
+		parts = append(parts, "missing SAN(s) requested by the order: "+strings.Join(e.Missing, ", "))
+	}
+
+	if len(e.Extra) > 0 {
+		parts = append(parts, "extra SAN(s) not requested by the order: "+strings.Join(e.Extra, ", "))
+	}
+
+	parts = append(parts, e.Reasons...)
+
+	return fmt.Sprintf("the CSR was rejected: %s: %v", strings.Join(parts, "; "), e.ProblemDetails)
+}
+
+func (e *CSRError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// diagnoseCSR explains a `badCSR` error returned by the CA when finalizing an order.
+// The original error is returned if the error is not a `badCSR` error, or if no local problem is found.
+func diagnoseCSR(err error, identifiers []acme.Identifier, rawCSR []byte) error {
+	var problem *acme.ProblemDetails
+	if !errors.As(err, &problem) || problem.Type != acme.BadCSRErr {
+		return err
+	}
+
+	csr, errP := x509.ParseCertificateRequest(rawCSR)
+	if errP != nil {
+		return err
+	}
+
+	csrErr := &CSRError{ProblemDetails: problem}
+
+	names := make(map[string]struct{})
+	for _, name := range csr.DNSNames {
+		names[strings.ToLower(name)] = struct{}{}
+	}
+
+	for _, ip := range csr.IPAddresses {
+		names[ip.String()] = struct{}{}
+	}
+
+	expected := make(map[string]struct{})
+
+	for _, ident := range identifiers {
+		value := strings.ToLower(ident.Value)
+		if ip := net.ParseIP(value); ip != nil {
+			value = ip.String()
+		}
+
+		expected[value] = struct{}{}
+
+		if _, ok := names[value]; !ok {
+			csrErr.Missing = append(csrErr.Missing, value)
+		}
+	}
+
+	for name := range names {
+		if _, ok := expected[name]; !ok {
+			csrErr.Extra = append(csrErr.Extra, name)
+		}
+	}
+
+	slices.Sort(csrErr.Extra)
+
+	if cn := strings.ToLower(csr.Subject.CommonName); cn != "" {
+		if _, ok := names[cn]; !ok {
+			csrErr.Reasons = append(csrErr.Reasons, fmt.Sprintf("the common name %q is not in the SANs", cn))
+		}
+	}
+
+	if reason := checkCSRKey(csr.PublicKey); reason != "" {
+		csrErr.Reasons = append(csrErr.Reasons, reason)
+	}
+
+	if errS := csr.CheckSignature(); errS != nil {
+		csrErr.Reasons = append(csrErr.Reasons, fmt.Sprintf("invalid signature: %v", errS))
+	}
+
+	if len(csrErr.Missing) == 0 && len(csrErr.Extra) == 0 && len(csrErr.Reasons) == 0 {
+		return err
+	}
+
+	return csrErr
+}
+
+// checkCSRKey checks the public key of a CSR against the keys commonly accepted by the CAs.
+func checkCSRKey(publicKey any) string {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		if size := key.N.BitLen(); size < 2048 || size > 4096 {
+			return fmt.Sprintf("unsupported RSA key size: %d bits (the CAs usually accept 2048 to 4096 bits)", size)
+		}
+
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() && key.Curve != elliptic.P384() {
+			return fmt.Sprintf("unsupported ECDSA curve: %s (the CAs usually accept P-256 and P-384)", key.Curve.Params().Name)
+		}
+
+	default:
+		return fmt.Sprintf("unsupported key type: %T (the CAs usually accept RSA and ECDSA keys)", publicKey)
+	}
+
+	return ""
+}
@@ -1,9 +1,17 @@
 package certificate
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
 	"errors"
 	"testing"
 
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -67,3 +75,142 @@ func Test_obtainError_Join_same_domain(t *testing.T) {
 	ca := &CarrotError{}
 	require.ErrorAs(t, err, &ca)
 }
+
+func Test_diagnoseCSR(t *testing.T) {
+	keyP256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	keyP521, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+
+	_, keyEd25519, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	identifiers := []acme.Identifier{
+		{Type: "dns", Value: "example.com"},
+		{Type: "dns", Value: "www.example.com"},
+		{Type: "ip", Value: "192.0.2.1"},
+	}
+
+	testCases := []struct {
+		desc     string
+		key      crypto.PrivateKey
+		options  certcrypto.CSROptions
+		expected *CSRError
+	}{
+		{
+			desc:    "missing SAN",
+			key:     keyP256,
+			options: certcrypto.CSROptions{Domain: "example.com", SAN: []string{"example.com", "192.0.2.1"}},
+			expected: &CSRError{
+				Missing: []string{"www.example.com"},
+			},
+		},
+		{
+			desc:    "extra SAN",
+			key:     keyP256,
+			options: certcrypto.CSROptions{SAN: []string{"example.com", "WWW.example.com", "192.0.2.1", "foo.example.com"}},
+			expected: &CSRError{
+				Extra: []string{"foo.example.com"},
+			},
+		},
+		{
+			desc:    "common name not in the SANs",
+			key:     keyP256,
+			options: certcrypto.CSROptions{Domain: "foo.example.com", SAN: []string{"example.com", "www.example.com", "192.0.2.1"}},
+			expected: &CSRError{
+				Reasons: []string{`the common name "foo.example.com" is not in the SANs`},
+			},
+		},
+		{
+			desc:    "unsupported curve",
+			key:     keyP521,
+			options: certcrypto.CSROptions{SAN: []string{"example.com", "www.example.com", "192.0.2.1"}},
+			expected: &CSRError{
+				Reasons: []string{"unsupported ECDSA curve: P-521 (the CAs usually accept P-256 and P-384)"},
+			},
+		},
+		{
+			desc:    "unsupported key type",
+			key:     keyEd25519,
+			options: certcrypto.CSROptions{SAN: []string{"example.com", "www.example.com", "192.0.2.1"}},
+			expected: &CSRError{
+				Reasons: []string{"unsupported key type: ed25519.PublicKey (the CAs usually accept RSA and ECDSA keys)"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			csr, err := certcrypto.CreateCSR(test.key, test.options)
+			require.NoError(t, err)
+
+			problem := &acme.ProblemDetails{Type: acme.BadCSRErr, Detail: "Error finalizing order", HTTPStatus: 400}
+
+			err = diagnoseCSR(problem, identifiers, csr)
+
+			var csrErr *CSRError
+			require.ErrorAs(t, err, &csrErr)
+
+			assert.Equal(t, test.expected.Missing, csrErr.Missing)
+			assert.Equal(t, test.expected.Extra, csrErr.Extra)
+			assert.Equal(t, test.expected.Reasons, csrErr.Reasons)
+
+			require.ErrorIs(t, err, problem)
+		})
+	}
+}
+
+func Test_diagnoseCSR_message(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	csr, err := certcrypto.CreateCSR(key, certcrypto.CSROptions{SAN: []string{"example.com", "foo.example.com"}})
+	require.NoError(t, err)
+
+	problem := &acme.ProblemDetails{Type: acme.BadCSRErr, Detail: "Error finalizing order", HTTPStatus: 400}
+
+	err = diagnoseCSR(problem, []acme.Identifier{{Type: "dns", Value: "example.com"}, {Type: "dns", Value: "www.example.com"}}, csr)
+
+	require.EqualError(t, err, "the CSR was rejected: missing SAN(s) requested by the order: www.example.com; "+
+		"extra SAN(s) not requested by the order: foo.example.com: "+
+		"acme: error: 400 :: urn:ietf:params:acme:error:badCSR :: Error finalizing order")
+}
+
+func Test_diagnoseCSR_unchanged(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	csr, err := certcrypto.CreateCSR(key, certcrypto.CSROptions{SAN: []string{"example.com"}})
+	require.NoError(t, err)
+
+	identifiers := []acme.Identifier{{Type: "dns", Value: "example.com"}}
+
+	testCases := []struct {
+		desc string
+		err  error
+	}{
+		{
+			desc: "not a problem",
+			err:  errors.New("timeout"),
+		},
+		{
+			desc: "other problem",
+			err:  &acme.ProblemDetails{Type: "urn:ietf:params:acme:error:serverInternal"},
+		},
+		{
+			desc: "no local problem",
+			err:  &acme.ProblemDetails{Type: acme.BadCSRErr},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Same(t, test.err, diagnoseCSR(test.err, identifiers, csr))
+		})
+	}
+}
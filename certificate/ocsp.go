@@ -0,0 +1,222 @@
+package certificate
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/log"
+	"golang.org/x/crypto/ocsp"
+)
+
+// ocspDefaultRefresh the refresh interval of the OCSP responses without NextUpdate.
+const ocspDefaultRefresh = time.Hour
+
+var defaultOCSPCache = NewOCSPCache(&http.Client{Timeout: 30 * time.Second})
+
+// GetOCSPForCert is like Certifier.GetOCSP, with a shared OCSPCache:
+// the responses are fetched, cached, and refreshed when needed.
+func GetOCSPForCert(bundle []byte) ([]byte, *ocsp.Response, error) {
+	return defaultOCSPCache.GetOCSPForCert(bundle)
+}
+
+// OCSPCache fetches, caches, and refreshes the OCSP responses of certificates,
+// to staple them (tls.Certificate.OCSPStaple) without requesting the OCSP responder at each handshake.
+//
+// A response is refreshed at the middle of its validity period (between ThisUpdate and NextUpdate).
+// If the refresh fails, the cached response is used until its NextUpdate.
+// An OCSPCache is safe for concurrent use.
+type OCSPCache struct {
+	client *http.Client
+
+	entries map[[sha256.Size]byte]*ocspEntry
+	mu      sync.Mutex
+
+	now func() time.Time
+}
+
+type ocspEntry struct {
+	raw       []byte
+	response  *ocsp.Response
+	refreshAt time.Time
+}
+
+// NewOCSPCache creates an OCSPCache, the OCSP responders are requested with the HTTP client.
+func NewOCSPCache(client *http.Client) *OCSPCache {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &OCSPCache{
+		client:  client,
+		entries: make(map[[sha256.Size]byte]*ocspEntry),
+		now:     time.Now,
+	}
+}
+
+// GetOCSPForCert takes a PEM encoded cert or cert bundle returning the raw OCSP response,
+// the parsed response, and an error, if any (see Certifier.GetOCSP).
+// The cached response is returned until its refresh.
+func (c *OCSPCache) GetOCSPForCert(bundle []byte) ([]byte, *ocsp.Response, error) {
+	certificates, err := certcrypto.ParsePEMBundle(bundle)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return c.get(certificates)
+}
+
+// Staple defines the OCSP response of the certificate (tls.Certificate.OCSPStaple).
+// The chain of the certificate (tls.Certificate.Certificate) must contain the leaf certificate first.
+func (c *OCSPCache) Staple(cert *tls.Certificate) error {
+	if cert == nil || len(cert.Certificate) == 0 {
+		return errors.New("no certificate")
+	}
+
+	var certificates []*x509.Certificate
+
+	for _, raw := range cert.Certificate {
+		x509Cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+
+		certificates = append(certificates, x509Cert)
+	}
+
+	raw, _, err := c.get(certificates)
+	if err != nil {
+		return err
+	}
+
+	cert.OCSPStaple = raw
+
+	return nil
+}
+
+func (c *OCSPCache) get(certificates []*x509.Certificate) ([]byte, *ocsp.Response, error) {
+	key := sha256.Sum256(certificates[0].Raw)
+
+	now := c.now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if ok && now.Before(entry.refreshAt) {
+		return entry.raw, entry.response, nil
+	}
+
+	raw, response, err := fetchOCSP(c.client, certificates)
+	if err != nil {
+		if ok && (entry.response.NextUpdate.IsZero() || now.Before(entry.response.NextUpdate)) {
+			log.Warnf("OCSP: refresh failed, the cached response is used: %v", err)
+
+			return entry.raw, entry.response, nil
+		}
+
+		return nil, nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = &ocspEntry{raw: raw, response: response, refreshAt: ocspRefreshTime(now, response)}
+	c.removeExpired(now)
+	c.mu.Unlock()
+
+	return raw, response, nil
+}
+
+// removeExpired removes the responses after their NextUpdate (e.g. the responses of the replaced certificates).
+func (c *OCSPCache) removeExpired(now time.Time) {
+	for key, entry := range c.entries {
+		if !entry.response.NextUpdate.IsZero() && now.After(entry.response.NextUpdate) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// ocspRefreshTime returns the time of the refresh of the response: the middle of its validity period.
+func ocspRefreshTime(now time.Time, response *ocsp.Response) time.Time {
+	if response.NextUpdate.IsZero() || !response.NextUpdate.After(response.ThisUpdate) {
+		return now.Add(ocspDefaultRefresh)
+	}
+
+	return response.ThisUpdate.Add(response.NextUpdate.Sub(response.ThisUpdate) / 2)
+}
+
+// fetchOCSP requests the OCSP response of the first certificate of the chain.
+// If the chain only contains the issued certificate,
+// the issuer certificate is fetched from the IssuingCertificateURL of the certificate.
+func fetchOCSP(client *http.Client, certificates []*x509.Certificate) ([]byte, *ocsp.Response, error) {
+	// We expect the certificate slice to be ordered downwards the chain.
+	// SRV CRT -> CA. We need to pull the leaf and issuer certs out of it,
+	// which should always be the first two certificates.
+	// If there's no OCSP server listed in the leaf cert, there's nothing to do.
+	// And if we have only one certificate so far, we need to get the issuer cert.
+
+	issuedCert := certificates[0]
+
+	if len(issuedCert.OCSPServer) == 0 {
+		return nil, nil, errors.New("no OCSP server specified in cert")
+	}
+
+	if len(certificates) == 1 {
+		// TODO: build fallback. If this fails, check the remaining array entries.
+		if len(issuedCert.IssuingCertificateURL) == 0 {
+			return nil, nil, errors.New("no issuing certificate URL")
+		}
+
+		resp, errC := client.Get(issuedCert.IssuingCertificateURL[0])
+		if errC != nil {
+			return nil, nil, errC
+		}
+		defer resp.Body.Close()
+
+		issuerBytes, errC := io.ReadAll(http.MaxBytesReader(nil, resp.Body, maxBodySize))
+		if errC != nil {
+			return nil, nil, errC
+		}
+
+		issuerCert, errC := x509.ParseCertificate(issuerBytes)
+		if errC != nil {
+			return nil, nil, errC
+		}
+
+		// Insert it into the slice on position 0
+		// We want it ordered right SRV CRT -> CA
+		certificates = append(certificates, issuerCert)
+	}
+
+	issuerCert := certificates[1]
+
+	// Finally kick off the OCSP request.
+	ocspReq, err := ocsp.CreateRequest(issuedCert, issuerCert, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := client.Post(issuedCert.OCSPServer[0], "application/ocsp-request", bytes.NewReader(ocspReq))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	ocspResBytes, err := io.ReadAll(http.MaxBytesReader(nil, resp.Body, maxBodySize))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ocspRes, err := ocsp.ParseResponse(ocspResBytes, issuerCert)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ocspResBytes, ocspRes, nil
+}
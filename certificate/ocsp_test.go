@@ -0,0 +1,208 @@
+package certificate
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+type ocspResponder struct {
+	issuer    *x509.Certificate
+	issuerKey crypto.Signer
+
+	thisUpdate time.Time
+	validity   time.Duration
+
+	requests atomic.Int32
+	fail     atomic.Bool
+}
+
+func (r *ocspResponder) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	r.requests.Add(1)
+
+	if r.fail.Load() {
+		http.Error(rw, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	raw, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ocspReq, err := ocsp.ParseRequest(raw)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response, err := ocsp.CreateResponse(r.issuer, r.issuer, ocsp.Response{
+		Status:       ocsp.Good,
+		SerialNumber: ocspReq.SerialNumber,
+		ThisUpdate:   r.thisUpdate,
+		NextUpdate:   r.thisUpdate.Add(r.validity),
+	}, r.issuerKey)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	_, _ = rw.Write(response)
+}
+
+// setupOCSP creates a responder, and a PEM bundle (leaf and issuer) with the URL of the responder.
+func setupOCSP(t *testing.T, thisUpdate time.Time) (*ocspResponder, []*x509.Certificate, []byte) {
+	t.Helper()
+
+	issuerKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	issuerTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             thisUpdate.Add(-time.Hour),
+		NotAfter:              thisUpdate.Add(365 * 24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+
+	issuerDER, err := x509.CreateCertificate(rand.Reader, issuerTemplate, issuerTemplate, issuerKey.Public(), issuerKey)
+	require.NoError(t, err)
+
+	issuer, err := x509.ParseCertificate(issuerDER)
+	require.NoError(t, err)
+
+	responder := &ocspResponder{issuer: issuer, issuerKey: issuerKey, thisUpdate: thisUpdate, validity: 4 * 24 * time.Hour}
+
+	server := httptest.NewServer(responder)
+	t.Cleanup(server.Close)
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    thisUpdate.Add(-time.Hour),
+		NotAfter:     thisUpdate.Add(90 * 24 * time.Hour),
+		OCSPServer:   []string{server.URL},
+	}
+
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, issuer, leafKey.Public(), issuerKey)
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(leafDER)
+	require.NoError(t, err)
+
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDER})
+	bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: issuerDER})...)
+
+	return responder, []*x509.Certificate{leaf, issuer}, bundle
+}
+
+func TestOCSPCache_GetOCSPForCert(t *testing.T) {
+	thisUpdate := time.Now().Truncate(time.Second)
+
+	responder, _, bundle := setupOCSP(t, thisUpdate)
+
+	cache := NewOCSPCache(nil)
+
+	now := thisUpdate
+	cache.now = func() time.Time { return now }
+
+	raw, response, err := cache.GetOCSPForCert(bundle)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, raw)
+	assert.Equal(t, ocsp.Good, response.Status)
+	assert.Equal(t, int32(1), responder.requests.Load())
+
+	// Cached until the middle of the validity period.
+	now = thisUpdate.Add(47 * time.Hour)
+
+	_, _, err = cache.GetOCSPForCert(bundle)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), responder.requests.Load())
+
+	// Refreshed after the middle of the validity period.
+	now = thisUpdate.Add(49 * time.Hour)
+
+	_, _, err = cache.GetOCSPForCert(bundle)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), responder.requests.Load())
+}
+
+func TestOCSPCache_GetOCSPForCert_refreshError(t *testing.T) {
+	thisUpdate := time.Now().Truncate(time.Second)
+
+	responder, _, bundle := setupOCSP(t, thisUpdate)
+
+	cache := NewOCSPCache(nil)
+
+	now := thisUpdate
+	cache.now = func() time.Time { return now }
+
+	raw, _, err := cache.GetOCSPForCert(bundle)
+	require.NoError(t, err)
+
+	responder.fail.Store(true)
+
+	// The refresh fails: the cached response is still valid.
+	now = thisUpdate.Add(72 * time.Hour)
+
+	cached, _, err := cache.GetOCSPForCert(bundle)
+	require.NoError(t, err)
+
+	assert.Equal(t, raw, cached)
+
+	// The cached response is expired.
+	now = thisUpdate.Add(5 * 24 * time.Hour)
+
+	_, _, err = cache.GetOCSPForCert(bundle)
+	require.Error(t, err)
+}
+
+func TestOCSPCache_Staple(t *testing.T) {
+	responder, certificates, _ := setupOCSP(t, time.Now().Truncate(time.Second))
+
+	cert := &tls.Certificate{Certificate: [][]byte{certificates[0].Raw, certificates[1].Raw}}
+
+	err := NewOCSPCache(nil).Staple(cert)
+	require.NoError(t, err)
+
+	response, err := ocsp.ParseResponse(cert.OCSPStaple, responder.issuer)
+	require.NoError(t, err)
+
+	assert.Equal(t, ocsp.Good, response.Status)
+}
+
+func Test_ocspRefreshTime(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	response := &ocsp.Response{ThisUpdate: now, NextUpdate: now.Add(4 * 24 * time.Hour)}
+	assert.Equal(t, now.Add(2*24*time.Hour), ocspRefreshTime(now, response))
+
+	// Without NextUpdate.
+	assert.Equal(t, now.Add(time.Hour), ocspRefreshTime(now, &ocsp.Response{ThisUpdate: now}))
+}
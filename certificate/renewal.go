@@ -8,6 +8,8 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/go-acme/lego/v4/acme"
@@ -84,6 +86,12 @@ func (c *Certifier) GetRenewalInfo(req RenewalInfoRequest) (*RenewalInfoResponse
 	}
 	defer resp.Body.Close()
 
+	// A problem document (or any error response) must not be read as a renewal window:
+	// the zero window would trigger an immediate renewal.
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("renewalInfo: unexpected status code: %d", resp.StatusCode)
+	}
+
 	var info RenewalInfoResponse
 
 	err = json.NewDecoder(resp.Body).Decode(&info)
@@ -92,7 +100,7 @@ func (c *Certifier) GetRenewalInfo(req RenewalInfoRequest) (*RenewalInfoResponse
 	}
 
 	if retry := resp.Header.Get("Retry-After"); retry != "" {
-		info.RetryAfter, err = time.ParseDuration(retry + "s")
+		info.RetryAfter, err = parseRetryAfter(retry, time.Now())
 		if err != nil {
 			return nil, err
 		}
@@ -101,6 +109,21 @@ func (c *Certifier) GetRenewalInfo(req RenewalInfoRequest) (*RenewalInfoResponse
 	return &info, nil
 }
 
+// parseRetryAfter parses the value of the Retry-After header: a number of seconds, or an HTTP-date.
+// https://www.rfc-editor.org/rfc/rfc9110.html#section-10.2.3
+func parseRetryAfter(value string, now time.Time) (time.Duration, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid Retry-After header: %q", value)
+	}
+
+	return max(date.Sub(now), 0), nil
+}
+
 // MakeARICertID constructs a certificate identifier as described in RFC 9773, section 4.1.
 func MakeARICertID(leaf *x509.Certificate) (string, error) {
 	if leaf == nil {
@@ -104,6 +104,16 @@ func TestCertifier_GetRenewalInfo_errors(t *testing.T) {
 				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 			},
 		},
+		{
+			desc:    "API problem",
+			request: RenewalInfoRequest{leaf},
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				// A JSON problem document must not be read as an empty renewal window.
+				w.Header().Set("Content-Type", "application/problem+json")
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"type":"urn:ietf:params:acme:error:malformed","detail":"unknown certificate"}`))
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -132,6 +142,58 @@ func TestCertifier_GetRenewalInfo_errors(t *testing.T) {
 	}
 }
 
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc     string
+		value    string
+		expected time.Duration
+		err      string
+	}{
+		{
+			desc:     "seconds",
+			value:    "21600",
+			expected: 6 * time.Hour,
+		},
+		{
+			desc:     "HTTP-date",
+			value:    "Wed, 01 Jan 2025 06:00:00 GMT",
+			expected: 6 * time.Hour,
+		},
+		{
+			desc:     "HTTP-date in the past",
+			value:    "Tue, 31 Dec 2024 23:00:00 GMT",
+			expected: 0,
+		},
+		{
+			desc:  "negative",
+			value: "-10",
+			err:   `invalid Retry-After header: "-10"`,
+		},
+		{
+			desc:  "invalid",
+			value: "foo",
+			err:   `invalid Retry-After header: "foo"`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			retryAfter, err := parseRetryAfter(test.value, now)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, retryAfter)
+		})
+	}
+}
+
 func TestRenewalInfoResponse_ShouldRenew(t *testing.T) {
 	now := time.Now().UTC()
 
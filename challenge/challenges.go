@@ -20,6 +20,12 @@ const (
 
 	// TLSALPN01 is the "tls-alpn-01" ACME challenge https://www.rfc-editor.org/rfc/rfc8737.html
 	TLSALPN01 = Type("tls-alpn-01")
+
+	// OnionCSR01 is the "onion-csr-01" ACME challenge https://www.rfc-editor.org/rfc/rfc9799.html
+	OnionCSR01 = Type("onion-csr-01")
+
+	// TKAuth01 is the "tkauth-01" ACME challenge https://www.rfc-editor.org/rfc/rfc9447.html
+	TKAuth01 = Type("tkauth-01")
 )
 
 func (t Type) String() string {
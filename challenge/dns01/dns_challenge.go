@@ -1,18 +1,24 @@
 package dns01
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/acme/api"
 	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/errcode"
+	"github.com/go-acme/lego/v4/platform/redact"
 	"github.com/go-acme/lego/v4/platform/wait"
 	"github.com/miekg/dns"
 )
@@ -46,22 +52,37 @@ func CondOption(condition bool, opt ChallengeOption) ChallengeOption {
 
 // Challenge implements the dns-01 challenge.
 type Challenge struct {
-	core       *api.Core
-	validate   ValidateFunc
-	provider   challenge.Provider
-	preCheck   preCheck
-	dnsTimeout time.Duration
+	core     *api.Core
+	validate ValidateFunc
+	provider challenge.Provider
+	preCheck preCheck
+	resolver *resolver
+
+	// the records created by this client, the cleanups only delete these records.
+	manifest *Manifest
+
+	// the delay before the first propagation check (the polling interval by default).
+	initialDelay time.Duration
+
+	// the structured logger of the events of the challenge (optional).
+	logger log.StructuredLogger
+
+	// the longest timeout of the records of the order, by challenge token (see PrepareTimeouts).
+	orderTimeouts   map[string]time.Duration
+	orderTimeoutsMu sync.Mutex
 }
 
 func NewChallenge(core *api.Core, validate ValidateFunc, provider challenge.Provider, opts ...ChallengeOption) *Challenge {
 	chlg := &Challenge{
-		core:       core,
-		validate:   validate,
-		provider:   provider,
-		preCheck:   newPreCheck(),
-		dnsTimeout: 10 * time.Second,
+		core:     core,
+		validate: validate,
+		provider: provider,
+		preCheck: newPreCheck(),
+		resolver: &resolver{},
 	}
 
+	chlg.preCheck.resolver = chlg.resolver
+
 	for _, opt := range opts {
 		err := opt(chlg)
 		if err != nil {
@@ -69,39 +90,90 @@ func NewChallenge(core *api.Core, validate ValidateFunc, provider challenge.Prov
 		}
 	}
 
+	chlg.preCheck.logger = chlg.logger
+
 	return chlg
 }
 
 // PreSolve just submits the txt record to the dns provider.
 // It does not validate record propagation, or do anything at all with the acme server.
 func (c *Challenge) PreSolve(authz acme.Authorization) error {
+	return c.PreSolveContext(context.Background(), authz)
+}
+
+// PreSolveContext is like PreSolve, the context cancels the creation of the record
+// if the provider implements challenge.ProviderContext.
+func (c *Challenge) PreSolveContext(ctx context.Context, authz acme.Authorization) error {
+	domain := challenge.GetTargetedDomain(authz)
+
+	item, err := c.prepare(authz)
+	if err != nil {
+		return err
+	}
+
+	unlock := c.lockZones(zoneLockItem{domain: item.Domain, keyAuth: item.KeyAuth})
+
+	start := time.Now()
+
+	err = challenge.Present(c.withLogger(ctx), c.provider, item.Domain, item.Token, item.KeyAuth)
+
+	unlock()
+
+	c.logProviderCall("present", []string{item.Domain}, start, err)
+
+	if err != nil {
+		return redact.Error(errcode.Wrap(errcode.DNSProvider, fmt.Errorf("[%s] acme: error presenting token: %w", domain, err)))
+	}
+
+	return nil
+}
+
+// prepare generates the key authorization of the challenge, checks the record against the transformation of the challenge records,
+// and adds the record to the manifest.
+func (c *Challenge) prepare(authz acme.Authorization) (challenge.BatchItem, error) {
 	domain := challenge.GetTargetedDomain(authz)
 	log.Infof("[%s] acme: Preparing to solve DNS-01", domain)
 
 	chlng, err := challenge.FindChallenge(challenge.DNS01, authz)
 	if err != nil {
-		return err
+		return challenge.BatchItem{}, err
 	}
 
 	if c.provider == nil {
-		return fmt.Errorf("[%s] acme: no DNS Provider configured", domain)
+		return challenge.BatchItem{}, fmt.Errorf("[%s] acme: no DNS Provider configured", domain)
 	}
 
 	// Generate the Key Authorization for the challenge
 	keyAuth, err := c.core.GetKeyAuthorization(chlng.Token)
 	if err != nil {
-		return err
+		return challenge.BatchItem{}, err
 	}
 
-	err = c.provider.Present(authz.Identifier.Value, chlng.Token, keyAuth)
+	err = c.resolver.challengeTransform().check(authz.Identifier.Value)
 	if err != nil {
-		return fmt.Errorf("[%s] acme: error presenting token: %w", domain, err)
+		return challenge.BatchItem{}, fmt.Errorf("[%s] acme: %w", domain, err)
 	}
 
-	return nil
+	// The record is added to the manifest before its creation: a crash cannot leave an unknown record.
+	err = c.addToManifest(authz.Identifier.Value, keyAuth)
+	if err != nil {
+		return challenge.BatchItem{}, fmt.Errorf("[%s] acme: %w", domain, err)
+	}
+
+	return challenge.BatchItem{Domain: authz.Identifier.Value, Token: chlng.Token, KeyAuth: keyAuth}, nil
 }
 
 func (c *Challenge) Solve(authz acme.Authorization) error {
+	start := time.Now()
+
+	err := c.solve(authz)
+
+	c.logSolve(challenge.GetTargetedDomain(authz), start, err)
+
+	return err
+}
+
+func (c *Challenge) solve(authz acme.Authorization) error {
 	domain := challenge.GetTargetedDomain(authz)
 	log.Infof("[%s] acme: Trying to solve DNS-01", domain)
 
@@ -116,31 +188,54 @@ func (c *Challenge) Solve(authz acme.Authorization) error {
 		return err
 	}
 
-	info := GetChallengeInfo(authz.Identifier.Value, keyAuth)
+	info := c.resolver.getChallengeInfo(authz.Identifier.Value, keyAuth)
 
-	var timeout, interval time.Duration
+	timeout, interval := c.recordTimeout(info.EffectiveFQDN)
 
-	switch provider := c.provider.(type) {
-	case challenge.ProviderTimeout:
-		timeout, interval = provider.Timeout()
-	default:
-		timeout, interval = DefaultPropagationTimeout, DefaultPollingInterval
+	if orderTimeout, ok := c.popOrderTimeout(chlng.Token); ok {
+		timeout = max(timeout, orderTimeout)
 	}
 
-	log.Infof("[%s] acme: Checking DNS record propagation. [nameservers=%s]", domain, strings.Join(recursiveNameservers, ","))
+	if confirmer, ok := c.propagationConfirmer(); ok {
+		log.Infof("[%s] acme: Waiting for the DNS provider to confirm the record propagation.", domain)
+
+		if c.initialDelay > 0 {
+			time.Sleep(c.initialDelay)
+		}
 
-	time.Sleep(interval)
+		err = wait.For("propagation", timeout, interval, func() (bool, error) {
+			confirmed, errC := confirmer.ConfirmPropagation(authz.Identifier.Value, chlng.Token, keyAuth)
 
-	err = wait.For("propagation", timeout, interval, func() (bool, error) {
-		stop, errP := c.preCheck.call(domain, info.EffectiveFQDN, info.Value)
-		if !stop || errP != nil {
-			log.Infof("[%s] acme: Waiting for DNS record propagation.", domain)
+			c.logPropagationCheck(domain, info.EffectiveFQDN, confirmed && errC == nil, errC)
+
+			return confirmed, errC
+		})
+		if err != nil {
+			return redact.Error(wrapPropagationError(err))
 		}
+	} else {
+		log.Infof("[%s] acme: Checking DNS record propagation. [nameservers=%s]", domain, strings.Join(c.resolver.propagationNSs(), ","))
 
-		return stop, errP
-	})
-	if err != nil {
-		return err
+		if c.initialDelay > 0 {
+			time.Sleep(c.initialDelay)
+		} else {
+			time.Sleep(interval)
+		}
+
+		err = wait.For("propagation", timeout, interval, func() (bool, error) {
+			stop, errP := c.preCheck.call(domain, info.EffectiveFQDN, info.Value)
+
+			c.logPropagationCheck(domain, info.EffectiveFQDN, stop && errP == nil, errP)
+
+			if !stop || errP != nil {
+				log.Infof("[%s] acme: Waiting for DNS record propagation.", domain)
+			}
+
+			return stop, errP
+		})
+		if err != nil {
+			return wrapPropagationError(err)
+		}
 	}
 
 	chlng.KeyAuthorization = keyAuth
@@ -150,7 +245,8 @@ func (c *Challenge) Solve(authz acme.Authorization) error {
 
 // CleanUp cleans the challenge.
 func (c *Challenge) CleanUp(authz acme.Authorization) error {
-	log.Infof("[%s] acme: Cleaning DNS-01 challenge", challenge.GetTargetedDomain(authz))
+	domain := challenge.GetTargetedDomain(authz)
+	log.Infof("[%s] acme: Cleaning DNS-01 challenge", domain)
 
 	chlng, err := challenge.FindChallenge(challenge.DNS01, authz)
 	if err != nil {
@@ -162,7 +258,220 @@ func (c *Challenge) CleanUp(authz acme.Authorization) error {
 		return err
 	}
 
-	return c.provider.CleanUp(authz.Identifier.Value, chlng.Token, keyAuth)
+	err = c.checkManifest(authz.Identifier.Value, keyAuth)
+	if err != nil {
+		return fmt.Errorf("[%s] acme: %w", domain, err)
+	}
+
+	unlock := c.lockZones(zoneLockItem{domain: authz.Identifier.Value, keyAuth: keyAuth})
+
+	start := time.Now()
+
+	err = challenge.CleanUp(c.withLogger(context.Background()), c.provider, authz.Identifier.Value, chlng.Token, keyAuth)
+
+	unlock()
+
+	c.logProviderCall("cleanup", []string{authz.Identifier.Value}, start, err)
+
+	if err != nil {
+		fqdn := c.resolver.getChallengeInfo(authz.Identifier.Value, keyAuth).EffectiveFQDN
+
+		return redact.Error(fmt.Errorf("the record %s may remain: %w", fqdn, err))
+	}
+
+	return c.removeFromManifest(keyAuth)
+}
+
+// Batch reports whether the DNS provider is able to present (and to clean up) several challenges in a single call.
+func (c *Challenge) Batch() bool {
+	_, ok := c.provider.(challenge.ProviderBatch)
+
+	return ok
+}
+
+// PreSolveBatch submits the TXT records of all the authorizations to the DNS provider in a single call.
+// It does not validate record propagation, or do anything at all with the acme server.
+func (c *Challenge) PreSolveBatch(authzs []acme.Authorization) error {
+	return c.PreSolveBatchContext(context.Background(), authzs)
+}
+
+// PreSolveBatchContext is like PreSolveBatch, the context cancels the creation of the records
+// if the provider implements challenge.ProviderBatchContext.
+func (c *Challenge) PreSolveBatchContext(ctx context.Context, authzs []acme.Authorization) error {
+	provider, ok := c.provider.(challenge.ProviderBatch)
+	if !ok {
+		return errors.New("acme: the DNS provider doesn't support batch operations")
+	}
+
+	var items []challenge.BatchItem
+
+	for _, authz := range authzs {
+		item, err := c.prepare(authz)
+		if err != nil {
+			return err
+		}
+
+		items = append(items, item)
+	}
+
+	unlock := c.lockZones(batchZoneLockItems(items)...)
+
+	start := time.Now()
+
+	err := challenge.PresentBatch(c.withLogger(ctx), provider, items)
+
+	unlock()
+
+	c.logProviderCall("present", batchDomains(items), start, err)
+
+	if err != nil {
+		return redact.Error(errcode.Wrap(errcode.DNSProvider, fmt.Errorf("acme: error presenting tokens: %w", err)))
+	}
+
+	return nil
+}
+
+// CleanUpBatch cleans the challenges of all the authorizations in a single call.
+func (c *Challenge) CleanUpBatch(authzs []acme.Authorization) error {
+	provider, ok := c.provider.(challenge.ProviderBatch)
+	if !ok {
+		return errors.New("acme: the DNS provider doesn't support batch operations")
+	}
+
+	items, err := c.batchItems(authzs)
+	if err != nil {
+		return err
+	}
+
+	for _, authz := range authzs {
+		log.Infof("[%s] acme: Cleaning DNS-01 challenge", challenge.GetTargetedDomain(authz))
+	}
+
+	var errs []error
+
+	items = slices.DeleteFunc(items, func(item challenge.BatchItem) bool {
+		err = c.checkManifest(item.Domain, item.KeyAuth)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("[%s] acme: %w", item.Domain, err))
+			return true
+		}
+
+		return false
+	})
+
+	if len(items) == 0 {
+		return errors.Join(errs...)
+	}
+
+	unlock := c.lockZones(batchZoneLockItems(items)...)
+
+	start := time.Now()
+
+	err = challenge.CleanUpBatch(c.withLogger(context.Background()), provider, items)
+
+	unlock()
+
+	c.logProviderCall("cleanup", batchDomains(items), start, err)
+
+	if err != nil {
+		// The state of the records is unknown after a failed batch:
+		// the cleanup of each record is attempted, to not leave records behind.
+		log.Warnf("acme: the batch cleanup failed, cleaning up the records one by one: %v", redact.Error(err))
+
+		return redact.Error(errors.Join(append(errs, c.cleanUpItems(items)...)...))
+	}
+
+	for _, item := range items {
+		errs = append(errs, c.removeFromManifest(item.KeyAuth))
+	}
+
+	return errors.Join(errs...)
+}
+
+// cleanUpItems cleans up the records one by one.
+// Every record is attempted, even if the previous cleanups failed.
+func (c *Challenge) cleanUpItems(items []challenge.BatchItem) []error {
+	var errs []error
+
+	for _, item := range items {
+		unlock := c.lockZones(zoneLockItem{domain: item.Domain, keyAuth: item.KeyAuth})
+
+		start := time.Now()
+
+		err := challenge.CleanUp(c.withLogger(context.Background()), c.provider, item.Domain, item.Token, item.KeyAuth)
+
+		unlock()
+
+		c.logProviderCall("cleanup", []string{item.Domain}, start, err)
+
+		if err != nil {
+			fqdn := c.resolver.getChallengeInfo(item.Domain, item.KeyAuth).EffectiveFQDN
+
+			errs = append(errs, fmt.Errorf("[%s] acme: the record %s may remain: %w", item.Domain, fqdn, err))
+
+			continue
+		}
+
+		errs = append(errs, c.removeFromManifest(item.KeyAuth))
+	}
+
+	return errs
+}
+
+func (c *Challenge) batchItems(authzs []acme.Authorization) ([]challenge.BatchItem, error) {
+	var items []challenge.BatchItem
+
+	for _, authz := range authzs {
+		chlng, err := challenge.FindChallenge(challenge.DNS01, authz)
+		if err != nil {
+			return nil, err
+		}
+
+		keyAuth, err := c.core.GetKeyAuthorization(chlng.Token)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, challenge.BatchItem{
+			Domain:  authz.Identifier.Value,
+			Token:   chlng.Token,
+			KeyAuth: keyAuth,
+		})
+	}
+
+	return items, nil
+}
+
+func batchZoneLockItems(items []challenge.BatchItem) []zoneLockItem {
+	var lockItems []zoneLockItem
+
+	for _, item := range items {
+		lockItems = append(lockItems, zoneLockItem{domain: item.Domain, keyAuth: item.KeyAuth})
+	}
+
+	return lockItems
+}
+
+func batchDomains(items []challenge.BatchItem) []string {
+	var domains []string
+
+	for _, item := range items {
+		domains = append(domains, item.Domain)
+	}
+
+	return domains
+}
+
+// propagationConfirmer returns the provider if it's able to confirm the propagation of the records.
+// The pre-check explicitly configured takes precedence over the confirmation of the provider.
+func (c *Challenge) propagationConfirmer() (PropagationConfirmer, bool) {
+	if c.preCheck.customized() {
+		return nil, false
+	}
+
+	confirmer, ok := c.provider.(PropagationConfirmer)
+
+	return confirmer, ok
 }
 
 func (c *Challenge) Sequential() (bool, time.Duration) {
@@ -177,6 +486,22 @@ type sequential interface {
 	Sequential() time.Duration
 }
 
+// MaxConcurrency returns the maximum number of challenges presented concurrently with the provider
+// (see challenge.ProviderConcurrency), 0 means no limit.
+func (c *Challenge) MaxConcurrency() int {
+	return challenge.MaxConcurrency(c.provider)
+}
+
+// PropagationConfirmer is implemented by the DNS providers able to confirm by themselves
+// that a TXT record is published on all their authoritative nameservers
+// (e.g. the API reports the change as synchronized on all the edges).
+// The dns01 solver trusts the confirmation, and skips the propagation checks based on DNS queries.
+//
+// ConfirmPropagation is called until it returns true, an error, or the propagation timeout is reached.
+type PropagationConfirmer interface {
+	ConfirmPropagation(domain, token, keyAuth string) (bool, error)
+}
+
 // GetRecord returns a DNS record which will fulfill the `dns-01` challenge.
 //
 // Deprecated: use GetChallengeInfo instead.
@@ -200,30 +525,95 @@ type ChallengeInfo struct {
 
 // GetChallengeInfo returns information used to create a DNS record which will fulfill the `dns-01` challenge.
 func GetChallengeInfo(domain, keyAuth string) ChallengeInfo {
-	keyAuthShaBytes := sha256.Sum256([]byte(keyAuth))
-	// base64URL encoding without padding
-	value := base64.RawURLEncoding.EncodeToString(keyAuthShaBytes[:sha256.Size])
+	return (*resolver)(nil).getChallengeInfo(domain, keyAuth)
+}
 
-	ok, _ := strconv.ParseBool(os.Getenv("LEGO_DISABLE_CNAME_SUPPORT"))
+// ChallengeZoneInfo contains the information used by an external system to create the TXT record by itself.
+type ChallengeZoneInfo struct {
+	ChallengeInfo
+
+	// Zone is the zone apex of the EffectiveFQDN (i.e. the zone where the TXT record must be created).
+	Zone string
+
+	// SubDomain is the EffectiveFQDN relative to the Zone (i.e. `_acme-challenge.[sub]`),
+	// empty when the EffectiveFQDN is the zone apex.
+	SubDomain string
+
+	// TTL is the default TTL of the TXT record.
+	TTL int
+}
+
+// GetChallengeZoneInfo returns the information used to create a DNS record which will fulfill the `dns-01` challenge,
+// including the zone of the record after the CNAMEs resolutions.
+// It allows an external system to present the record by itself (i.e. the manual flow driven programmatically).
+func GetChallengeZoneInfo(domain, keyAuth string) (ChallengeZoneInfo, error) {
+	return (*resolver)(nil).getChallengeZoneInfo(domain, keyAuth)
+}
+
+func (rs *resolver) getChallengeZoneInfo(domain, keyAuth string) (ChallengeZoneInfo, error) {
+	info := rs.getChallengeInfo(domain, keyAuth)
+
+	soa, err := rs.lookupSoaByFqdn(info.EffectiveFQDN, rs.discoveryNSs())
+	if err != nil {
+		return ChallengeZoneInfo{}, fmt.Errorf("could not find zone: [fqdn=%s] %w", info.EffectiveFQDN, err)
+	}
+
+	zoneInfo := ChallengeZoneInfo{
+		ChallengeInfo: info,
+		Zone:          soa.zone,
+		TTL:           DefaultTTL,
+	}
 
+	// The EffectiveFQDN can be the zone apex itself (i.e. a CNAME to the apex of a zone).
+	if dns.Fqdn(info.EffectiveFQDN) != dns.Fqdn(soa.zone) {
+		zoneInfo.SubDomain, err = ExtractSubDomain(info.EffectiveFQDN, soa.zone)
+		if err != nil {
+			return ChallengeZoneInfo{}, err
+		}
+	}
+
+	return zoneInfo, nil
+}
+
+func (rs *resolver) getChallengeInfo(domain, keyAuth string) ChallengeInfo {
 	return ChallengeInfo{
-		Value:         value,
-		FQDN:          getChallengeFQDN(domain, false),
-		EffectiveFQDN: getChallengeFQDN(domain, !ok),
+		Value:         rs.challengeTransform().rewriteValue(challengeValue(keyAuth)),
+		FQDN:          rs.getChallengeFQDN(domain, false),
+		EffectiveFQDN: rs.getChallengeFQDN(domain, !cnameSupportDisabled()),
 	}
 }
 
-func getChallengeFQDN(domain string, followCNAME bool) string {
-	fqdn := fmt.Sprintf("_acme-challenge.%s.", domain)
+// cnameSupportDisabled reports whether the CNAMEs must not be followed (LEGO_DISABLE_CNAME_SUPPORT).
+func cnameSupportDisabled() bool {
+	ok, _ := strconv.ParseBool(os.Getenv("LEGO_DISABLE_CNAME_SUPPORT"))
+
+	return ok
+}
+
+// challengeValue returns the value of the TXT record.
+func challengeValue(keyAuth string) string {
+	keyAuthShaBytes := sha256.Sum256([]byte(keyAuth))
+
+	// base64URL encoding without padding
+	return base64.RawURLEncoding.EncodeToString(keyAuthShaBytes[:sha256.Size])
+}
+
+func (rs *resolver) getChallengeFQDN(domain string, followCNAME bool) string {
+	fqdn := rs.challengeTransform().rewriteFQDN(domain)
 
 	if !followCNAME {
 		return fqdn
 	}
 
+	return rs.followCNAMEs(fqdn)
+}
+
+// followCNAMEs returns the FQDN after the CNAMEs resolutions.
+func (rs *resolver) followCNAMEs(fqdn string) string {
 	// recursion counter so it doesn't spin out of control
 	for range 50 {
 		// Keep following CNAMEs
-		r, err := dnsQuery(fqdn, dns.TypeCNAME, recursiveNameservers, true)
+		r, err := rs.query(fqdn, dns.TypeCNAME, rs.discoveryNSs(), true)
 
 		if err != nil || r.Rcode != dns.RcodeSuccess {
 			// No more CNAME records to follow, exit
@@ -243,3 +633,11 @@ func getChallengeFQDN(domain string, followCNAME bool) string {
 
 	return fqdn
 }
+
+func wrapPropagationError(err error) error {
+	if errors.Is(err, wait.ErrTimeLimitExceeded) {
+		return errcode.Wrap(errcode.DNSPropagationTimeout, err)
+	}
+
+	return errcode.Wrap(errcode.DNSPropagation, err)
+}
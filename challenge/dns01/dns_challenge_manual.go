@@ -12,7 +12,6 @@ const (
 )
 
 // DNSProviderManual is an implementation of the ChallengeProvider interface.
-// TODO(ldez): move this to providers/dns/manual
 //
 // Deprecated: Use the manual.DNSProvider instead.
 type DNSProviderManual struct{}
@@ -26,15 +25,13 @@ func NewDNSProviderManual() (*DNSProviderManual, error) {
 
 // Present prints instructions for manually creating the TXT record.
 func (*DNSProviderManual) Present(domain, token, keyAuth string) error {
-	info := GetChallengeInfo(domain, keyAuth)
-
-	authZone, err := FindZoneByFqdn(info.EffectiveFQDN)
+	record, err := GetChallengeZoneInfo(domain, keyAuth)
 	if err != nil {
-		return fmt.Errorf("manual: could not find zone: %w", err)
+		return fmt.Errorf("manual: %w", err)
 	}
 
-	fmt.Printf("lego: Please create the following TXT record in your %s zone:\n", authZone)
-	fmt.Printf(dnsTemplate+"\n", info.EffectiveFQDN, DefaultTTL, info.Value)
+	fmt.Printf("lego: Please create the following TXT record in your %s zone:\n", record.Zone)
+	fmt.Printf(dnsTemplate+"\n", record.EffectiveFQDN, record.TTL, record.Value)
 	fmt.Printf("lego: Press 'Enter' when you are done\n")
 
 	_, err = bufio.NewReader(os.Stdin).ReadBytes('\n')
@@ -47,15 +44,13 @@ func (*DNSProviderManual) Present(domain, token, keyAuth string) error {
 
 // CleanUp prints instructions for manually removing the TXT record.
 func (*DNSProviderManual) CleanUp(domain, token, keyAuth string) error {
-	info := GetChallengeInfo(domain, keyAuth)
-
-	authZone, err := FindZoneByFqdn(info.EffectiveFQDN)
+	record, err := GetChallengeZoneInfo(domain, keyAuth)
 	if err != nil {
-		return fmt.Errorf("manual: could not find zone: %w", err)
+		return fmt.Errorf("manual: %w", err)
 	}
 
-	fmt.Printf("lego: You can now remove this TXT record from your %s zone:\n", authZone)
-	fmt.Printf(dnsTemplate+"\n", info.EffectiveFQDN, DefaultTTL, "...")
+	fmt.Printf("lego: You can now remove this TXT record from your %s zone:\n", record.Zone)
+	fmt.Printf(dnsTemplate+"\n", record.EffectiveFQDN, record.TTL, "...")
 
 	return nil
 }
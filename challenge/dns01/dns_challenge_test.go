@@ -1,17 +1,21 @@
 package dns01
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/acme/api"
 	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/errcode"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/go-acme/lego/v4/platform/tester/dnsmock"
+	"github.com/miekg/dns"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -32,6 +36,33 @@ func (p *providerTimeoutMock) Present(domain, token, keyAuth string) error { ret
 func (p *providerTimeoutMock) CleanUp(domain, token, keyAuth string) error { return p.cleanUp }
 func (p *providerTimeoutMock) Timeout() (time.Duration, time.Duration)     { return p.timeout, p.interval }
 
+type providerConfirmerMock struct {
+	providerTimeoutMock
+
+	confirmed bool
+	confirm   error
+}
+
+func (p *providerConfirmerMock) ConfirmPropagation(domain, token, keyAuth string) (bool, error) {
+	return p.confirmed, p.confirm
+}
+
+type providerBatchMock struct {
+	providerMock
+
+	presented, cleaned []challenge.BatchItem
+}
+
+func (p *providerBatchMock) PresentBatch(items []challenge.BatchItem) error {
+	p.presented = append(p.presented, items...)
+	return p.present
+}
+
+func (p *providerBatchMock) CleanUpBatch(items []challenge.BatchItem) error {
+	p.cleaned = append(p.cleaned, items...)
+	return p.cleanUp
+}
+
 func TestChallenge_PreSolve(t *testing.T) {
 	server := tester.MockACMEServer().BuildHTTPS(t)
 
@@ -176,6 +207,42 @@ func TestChallenge_Solve(t *testing.T) {
 				cleanUp: errors.New("OOPS"),
 			},
 		},
+		{
+			desc:     "propagation confirmed by the provider",
+			validate: func(_ *api.Core, _ string, _ acme.Challenge) error { return nil },
+			provider: &providerConfirmerMock{
+				providerTimeoutMock: providerTimeoutMock{
+					timeout:  2 * time.Second,
+					interval: 500 * time.Millisecond,
+				},
+				confirmed: true,
+			},
+		},
+		{
+			desc:     "propagation not confirmed by the provider",
+			validate: func(_ *api.Core, _ string, _ acme.Challenge) error { return nil },
+			provider: &providerConfirmerMock{
+				providerTimeoutMock: providerTimeoutMock{
+					timeout:  2 * time.Second,
+					interval: 500 * time.Millisecond,
+				},
+				confirm: errors.New("OOPS"),
+			},
+			expectError: true,
+		},
+		{
+			desc:     "preCheck takes precedence over the confirmation of the provider",
+			validate: func(_ *api.Core, _ string, _ acme.Challenge) error { return nil },
+			preCheck: func(_, _, _ string, _ PreCheckFunc) (bool, error) { return false, errors.New("OOPS") },
+			provider: &providerConfirmerMock{
+				providerTimeoutMock: providerTimeoutMock{
+					timeout:  2 * time.Second,
+					interval: 500 * time.Millisecond,
+				},
+				confirmed: true,
+			},
+			expectError: true,
+		},
 	}
 
 	for _, test := range testCases {
@@ -206,6 +273,102 @@ func TestChallenge_Solve(t *testing.T) {
 	}
 }
 
+func TestChallenge_errorCode(t *testing.T) {
+	useAsNameserver(t, dnsmock.NewServer().
+		Query("_acme-challenge.example.com. CNAME", dnsmock.Noop).
+		Build(t))
+
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	validate := func(_ *api.Core, _ string, _ acme.Challenge) error { return nil }
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{
+			Value: "example.com",
+		},
+		Challenges: []acme.Challenge{
+			{Type: challenge.DNS01.String()},
+		},
+	}
+
+	t.Run("present fail", func(t *testing.T) {
+		chlg := NewChallenge(core, validate, &providerMock{present: errors.New("OOPS")})
+
+		err := chlg.PreSolve(authz)
+		require.Error(t, err)
+
+		assert.Equal(t, errcode.DNSProvider, errcode.Of(err))
+	})
+
+	t.Run("propagation timeout", func(t *testing.T) {
+		provider := &providerTimeoutMock{
+			timeout:  1 * time.Second,
+			interval: 200 * time.Millisecond,
+		}
+
+		chlg := NewChallenge(core, validate, provider,
+			WrapPreCheck(func(_, _, _ string, _ PreCheckFunc) (bool, error) { return false, nil }))
+
+		err := chlg.Solve(authz)
+		require.Error(t, err)
+
+		assert.Equal(t, errcode.DNSPropagationTimeout, errcode.Of(err))
+	})
+}
+
+func TestChallenge_Solve_initialDelay(t *testing.T) {
+	useAsNameserver(t, dnsmock.NewServer().
+		Query("_acme-challenge.example.com. CNAME", dnsmock.Noop).
+		Build(t))
+
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	var checks int
+
+	chlg := NewChallenge(core,
+		func(_ *api.Core, _ string, _ acme.Challenge) error { return nil },
+		&providerTimeoutMock{timeout: time.Minute, interval: time.Minute},
+		WrapPreCheck(func(_, _, _ string, _ PreCheckFunc) (bool, error) {
+			checks++
+			return true, nil
+		}),
+		PropagationInitialDelay(10*time.Millisecond),
+	)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String()}},
+	}
+
+	start := time.Now()
+
+	err = chlg.Solve(authz)
+	require.NoError(t, err)
+
+	// The polling interval (1 minute) is not used before the first check.
+	assert.Less(t, time.Since(start), 30*time.Second)
+	assert.Equal(t, 1, checks)
+}
+
+func TestPropagationInitialDelay_negative(t *testing.T) {
+	chlg := &Challenge{}
+
+	err := PropagationInitialDelay(-time.Second)(chlg)
+	require.EqualError(t, err, "the initial delay of the propagation checks cannot be negative")
+}
+
 func TestChallenge_CleanUp(t *testing.T) {
 	server := tester.MockACMEServer().BuildHTTPS(t)
 
@@ -288,6 +451,204 @@ func TestChallenge_CleanUp(t *testing.T) {
 	}
 }
 
+func TestChallenge_Batch(t *testing.T) {
+	chlg := NewChallenge(nil, nil, &providerMock{})
+	assert.False(t, chlg.Batch())
+
+	chlg = NewChallenge(nil, nil, &providerBatchMock{})
+	assert.True(t, chlg.Batch())
+}
+
+func TestChallenge_PreSolveBatch(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	provider := &providerBatchMock{}
+
+	chlg := NewChallenge(core, nil, provider)
+
+	authzs := []acme.Authorization{
+		{
+			Identifier: acme.Identifier{Value: "example.com"},
+			Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "a"}},
+		},
+		{
+			Identifier: acme.Identifier{Value: "example.org"},
+			Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "b"}},
+		},
+	}
+
+	err = chlg.PreSolveBatch(authzs)
+	require.NoError(t, err)
+
+	require.Len(t, provider.presented, 2)
+	assert.Equal(t, "example.com", provider.presented[0].Domain)
+	assert.Equal(t, "a", provider.presented[0].Token)
+	assert.NotEmpty(t, provider.presented[0].KeyAuth)
+	assert.Equal(t, "example.org", provider.presented[1].Domain)
+	assert.Equal(t, "b", provider.presented[1].Token)
+
+	err = chlg.CleanUpBatch(authzs)
+	require.NoError(t, err)
+
+	assert.Equal(t, provider.presented, provider.cleaned)
+}
+
+func TestChallenge_PreSolveBatch_error(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	chlg := NewChallenge(core, nil, &providerBatchMock{providerMock: providerMock{present: errors.New("OOPS")}})
+
+	authzs := []acme.Authorization{{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String()}},
+	}}
+
+	err = chlg.PreSolveBatch(authzs)
+	require.EqualError(t, err, "acme: error presenting tokens: OOPS")
+
+	chlg = NewChallenge(core, nil, &providerMock{})
+
+	err = chlg.PreSolveBatch(authzs)
+	require.EqualError(t, err, "acme: the DNS provider doesn't support batch operations")
+}
+
+type providerBatchContextMock struct {
+	providerBatchMock
+
+	presentCtx context.Context
+}
+
+func (p *providerBatchContextMock) PresentBatchContext(ctx context.Context, items []challenge.BatchItem) error {
+	p.presentCtx = ctx
+
+	return p.PresentBatch(items)
+}
+
+func (p *providerBatchContextMock) CleanUpBatchContext(_ context.Context, items []challenge.BatchItem) error {
+	return p.CleanUpBatch(items)
+}
+
+func TestChallenge_PreSolveBatchContext(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	type ctxKey struct{}
+
+	provider := &providerBatchContextMock{}
+
+	chlg := NewChallenge(core, nil, provider)
+
+	authzs := []acme.Authorization{{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "a"}},
+	}}
+
+	err = chlg.PreSolveBatchContext(context.WithValue(t.Context(), ctxKey{}, "value"), authzs)
+	require.NoError(t, err)
+
+	require.Len(t, provider.presented, 1)
+
+	require.NotNil(t, provider.presentCtx)
+	assert.Equal(t, "value", provider.presentCtx.Value(ctxKey{}))
+}
+
+func TestChallenge_PreSolveBatch_transform(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	transform, err := NewChallengeTransform("_acme-challenge.{domain}.edge.example.net", "")
+	require.NoError(t, err)
+
+	provider := &providerBatchMock{}
+
+	chlg := NewChallenge(core, nil, provider, AddChallengeTransform(transform))
+
+	authzs := []acme.Authorization{{
+		// The rewritten FQDN exceeds the maximum length of a domain name.
+		Identifier: acme.Identifier{Value: strings.Repeat(strings.Repeat("a", 60)+".", 4) + "com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "a"}},
+	}}
+
+	err = chlg.PreSolveBatch(authzs)
+	require.Error(t, err)
+
+	assert.Empty(t, provider.presented)
+}
+
+type providerBatchCleanUpMock struct {
+	providerBatchMock
+
+	// the errors of CleanUp by domain.
+	cleanUpErrors   map[string]error
+	cleanedOneByOne []string
+}
+
+func (p *providerBatchCleanUpMock) CleanUp(domain, _, _ string) error {
+	p.cleanedOneByOne = append(p.cleanedOneByOne, domain)
+	return p.cleanUpErrors[domain]
+}
+
+func TestChallenge_CleanUpBatch_fallback(t *testing.T) {
+	useAsNameserver(t, dnsmock.NewServer().
+		Query("_acme-challenge.example.org. CNAME", dnsmock.Noop).
+		Build(t))
+
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	provider := &providerBatchCleanUpMock{
+		providerBatchMock: providerBatchMock{providerMock: providerMock{cleanUp: errors.New("batch error")}},
+		cleanUpErrors:     map[string]error{"example.org": errors.New("OOPS")},
+	}
+
+	chlg := NewChallenge(core, nil, provider)
+
+	authzs := []acme.Authorization{
+		{
+			Identifier: acme.Identifier{Value: "example.com"},
+			Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "a"}},
+		},
+		{
+			Identifier: acme.Identifier{Value: "example.org"},
+			Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "b"}},
+		},
+	}
+
+	err = chlg.CleanUpBatch(authzs)
+	require.EqualError(t, err, "[example.org] acme: the record _acme-challenge.example.org. may remain: OOPS")
+
+	// Each record is cleaned up, even after the failure of the batch.
+	assert.Len(t, provider.cleaned, 2)
+	assert.Equal(t, []string{"example.com", "example.org"}, provider.cleanedOneByOne)
+}
+
 func TestGetChallengeInfo(t *testing.T) {
 	useAsNameserver(t, dnsmock.NewServer().
 		Query("_acme-challenge.example.com. CNAME", dnsmock.Noop).
@@ -339,3 +700,61 @@ func TestGetChallengeInfo_CNAME_disabled(t *testing.T) {
 
 	assert.Equal(t, expected, info)
 }
+
+func TestGetChallengeZoneInfo(t *testing.T) {
+	useAsNameserver(t, dnsmock.NewServer().
+		Query("_acme-challenge.sub.example.com. CNAME", dnsmock.Noop).
+		Query("_acme-challenge.sub.example.com. SOA", dnsmock.Noop).
+		Query("sub.example.com. SOA", dnsmock.Noop).
+		Query("example.com. SOA", dnsmock.SOA("")).
+		Build(t))
+
+	zoneInfo, err := GetChallengeZoneInfo("sub.example.com", "123")
+	require.NoError(t, err)
+
+	expected := ChallengeZoneInfo{
+		ChallengeInfo: ChallengeInfo{
+			FQDN:          "_acme-challenge.sub.example.com.",
+			EffectiveFQDN: "_acme-challenge.sub.example.com.",
+			Value:         "pmWkWSBCL51Bfkhn79xPuKBKHz__H6B-mY6G9_eieuM",
+		},
+		Zone:      "example.com.",
+		SubDomain: "_acme-challenge.sub",
+		TTL:       DefaultTTL,
+	}
+
+	assert.Equal(t, expected, zoneInfo)
+}
+
+func TestGetChallengeZoneInfo_CNAME(t *testing.T) {
+	useAsNameserver(t, dnsmock.NewServer().
+		Query("_acme-challenge.example.com. CNAME", dnsmock.CNAME("example.org.")).
+		Query("example.org. CNAME", dnsmock.Noop).
+		Query("example.org. SOA", dnsmock.SOA("")).
+		Build(t))
+
+	zoneInfo, err := GetChallengeZoneInfo("example.com", "123")
+	require.NoError(t, err)
+
+	expected := ChallengeZoneInfo{
+		ChallengeInfo: ChallengeInfo{
+			FQDN:          "_acme-challenge.example.com.",
+			EffectiveFQDN: "example.org.",
+			Value:         "pmWkWSBCL51Bfkhn79xPuKBKHz__H6B-mY6G9_eieuM",
+		},
+		Zone: "example.org.",
+		TTL:  DefaultTTL,
+	}
+
+	assert.Equal(t, expected, zoneInfo)
+}
+
+func TestGetChallengeZoneInfo_noZone(t *testing.T) {
+	useAsNameserver(t, dnsmock.NewServer().
+		Query("_acme-challenge.example.com. CNAME", dnsmock.Noop).
+		Query("_acme-challenge.example.com. SOA", dnsmock.Error(dns.RcodeNameError)).
+		Build(t))
+
+	_, err := GetChallengeZoneInfo("example.com", "123")
+	require.ErrorContains(t, err, "could not find zone: [fqdn=_acme-challenge.example.com.]")
+}
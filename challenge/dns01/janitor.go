@@ -0,0 +1,276 @@
+package dns01
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ChallengeRecord a TXT record found in a DNS zone.
+type ChallengeRecord struct {
+	// ID the provider-specific identifier of the record.
+	ID string
+	// FQDN the full-qualified name of the record (i.e. `_acme-challenge.[domain].`).
+	FQDN string
+	// Value the value of the TXT record.
+	Value string
+	// CreatedAt the creation date of the record, zero if unknown.
+	CreatedAt time.Time
+}
+
+// ErrCleanupNotSupported the DNS provider doesn't implement RecordJanitor.
+var ErrCleanupNotSupported = errors.New("the DNS provider does not support cleanup")
+
+// RecordJanitor is implemented by the DNS providers able to list and delete the TXT records of a zone.
+// It is used to remove the challenge records left behind by a crash or a failed CleanUp.
+//
+// ListTXTRecords returns all the TXT records of the zone:
+// the challenge records targeted by a CNAME don't have a specific name.
+type RecordJanitor interface {
+	ListTXTRecords(zone string) ([]ChallengeRecord, error)
+	DeleteTXTRecord(zone string, record ChallengeRecord) error
+}
+
+// IsChallengeRecord reports whether a TXT record looks like a record created by lego:
+// its name starts with `_acme-challenge.` and its value is a base64url encoded SHA-256 digest without padding.
+func IsChallengeRecord(record ChallengeRecord) bool {
+	if !strings.HasPrefix(strings.ToLower(record.FQDN), "_acme-challenge.") {
+		return false
+	}
+
+	return isChallengeValue(record.Value)
+}
+
+// isChallengeValue reports whether a TXT value is a base64url encoded SHA-256 digest without padding.
+func isChallengeValue(value string) bool {
+	value = strings.Trim(value, `"`)
+
+	if len(value) != base64.RawURLEncoding.EncodedLen(sha256.Size) {
+		return false
+	}
+
+	_, err := base64.RawURLEncoding.DecodeString(value)
+
+	return err == nil
+}
+
+// JanitorOption an option of the stale records cleanup.
+type JanitorOption func(*janitorOptions)
+
+type janitorOptions struct {
+	manifest *Manifest
+}
+
+// OnlyManifestRecords restricts the cleanup to the records of the manifest (i.e. the records created by this client).
+// The creation date of the manifest is used when the DNS provider doesn't provide one.
+func OnlyManifestRecords(manifest *Manifest) JanitorOption {
+	return func(o *janitorOptions) {
+		o.manifest = manifest
+	}
+}
+
+// FindStaleRecords returns the challenge records of the zones created more than maxAge ago (the key is the zone).
+// The records without a known creation date are ignored.
+//
+// The CNAMEs are followed the same way as the challenges (unless LEGO_DISABLE_CNAME_SUPPORT is set):
+// the CNAME of `_acme-challenge.[zone]`, and of the records of the manifest inside the zone (see OnlyManifestRecords).
+// The records found at the targets of the CNAMEs are returned with the zones of the targets.
+func FindStaleRecords(janitor RecordJanitor, zones []string, maxAge time.Duration, opts ...JanitorOption) (map[string][]ChallengeRecord, error) {
+	options := &janitorOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	scan := &staleScan{
+		janitor:  janitor,
+		options:  options,
+		deadline: time.Now().Add(-maxAge),
+		listed:   make(map[string][]ChallengeRecord),
+		seen:     make(map[string]struct{}),
+		stale:    make(map[string][]ChallengeRecord),
+	}
+
+	for _, zone := range zones {
+		err := scan.zone(zone, IsChallengeRecord)
+		if err != nil {
+			return nil, err
+		}
+
+		if cnameSupportDisabled() {
+			continue
+		}
+
+		targets, err := cnameTargets(zone, options.manifest)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, target := range targets {
+			err = scan.zone(target.zone, func(record ChallengeRecord) bool {
+				return strings.EqualFold(ToFqdn(record.FQDN), target.fqdn) && isChallengeValue(record.Value)
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return scan.stale, nil
+}
+
+// CleanUpStaleRecords removes the challenge records of the zones created more than maxAge ago (see FindStaleRecords).
+// It returns the removed records.
+func CleanUpStaleRecords(janitor RecordJanitor, zones []string, maxAge time.Duration, opts ...JanitorOption) ([]ChallengeRecord, error) {
+	options := &janitorOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	stale, err := FindStaleRecords(janitor, zones, maxAge, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		removed []ChallengeRecord
+		errs    []error
+	)
+
+	for _, zone := range slices.Sorted(maps.Keys(stale)) {
+		for _, record := range stale[zone] {
+			err = janitor.DeleteTXTRecord(zone, record)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("delete TXT record %s (%s): %w", record.FQDN, record.ID, err))
+				continue
+			}
+
+			removed = append(removed, record)
+
+			if options.manifest != nil {
+				err = options.manifest.Remove(record.Value)
+				if err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+
+	return removed, errors.Join(errs...)
+}
+
+// cnameTarget the target of a CNAME of a challenge record.
+type cnameTarget struct {
+	fqdn string
+	zone string
+}
+
+// cnameTargets returns the targets of the CNAMEs of the challenge records of a zone:
+// `_acme-challenge.[zone]`, and the records of the manifest inside the zone.
+func cnameTargets(zone string, manifest *Manifest) ([]cnameTarget, error) {
+	rs := (*resolver)(nil)
+
+	fqdns := []string{rs.getChallengeFQDN(zone, false)}
+
+	if manifest != nil {
+		known, err := manifest.fqdns()
+		if err != nil {
+			return nil, err
+		}
+
+		suffix := "." + strings.ToLower(ToFqdn(zone))
+
+		for _, fqdn := range known {
+			if strings.HasSuffix(fqdn, suffix) && !slices.Contains(fqdns, fqdn) {
+				fqdns = append(fqdns, fqdn)
+			}
+		}
+	}
+
+	var targets []cnameTarget
+
+	for _, fqdn := range fqdns {
+		effective := rs.followCNAMEs(fqdn)
+		if strings.EqualFold(effective, fqdn) {
+			continue
+		}
+
+		targetZone, err := FindZoneByFqdn(effective)
+		if err != nil {
+			return nil, fmt.Errorf("could not find the zone of the CNAME target %s of %s: %w", effective, fqdn, err)
+		}
+
+		targets = append(targets, cnameTarget{fqdn: strings.ToLower(effective), zone: UnFqdn(targetZone)})
+	}
+
+	return targets, nil
+}
+
+// staleScan collects the stale records of the zones.
+type staleScan struct {
+	janitor  RecordJanitor
+	options  *janitorOptions
+	deadline time.Time
+
+	// listed the records of the zones already listed.
+	listed map[string][]ChallengeRecord
+	// seen the records already collected (the key is the zone and the ID of the record).
+	seen map[string]struct{}
+
+	stale map[string][]ChallengeRecord
+}
+
+// zone collects the stale records of a zone matching the filter.
+func (s *staleScan) zone(zone string, match func(ChallengeRecord) bool) error {
+	records, ok := s.listed[zone]
+	if !ok {
+		var err error
+
+		records, err = s.janitor.ListTXTRecords(zone)
+		if err != nil {
+			return fmt.Errorf("list TXT records of %s: %w", zone, err)
+		}
+
+		s.listed[zone] = records
+	}
+
+	for _, record := range records {
+		if !match(record) {
+			continue
+		}
+
+		if s.options.manifest != nil {
+			entry, ok, err := s.options.manifest.Lookup(record.Value)
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				continue
+			}
+
+			if record.CreatedAt.IsZero() {
+				record.CreatedAt = entry.CreatedAt
+			}
+		}
+
+		if record.CreatedAt.IsZero() || record.CreatedAt.After(s.deadline) {
+			continue
+		}
+
+		key := zone + "/" + record.ID
+		if _, ok := s.seen[key]; ok {
+			continue
+		}
+
+		s.seen[key] = struct{}{}
+
+		s.stale[zone] = append(s.stale[zone], record)
+	}
+
+	return nil
+}
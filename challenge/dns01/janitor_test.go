@@ -0,0 +1,253 @@
+package dns01
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/platform/tester/dnsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeJanitor struct {
+	records   map[string][]ChallengeRecord
+	deleteErr map[string]error
+
+	deleted []string
+}
+
+func (f *fakeJanitor) ListTXTRecords(zone string) ([]ChallengeRecord, error) {
+	records, ok := f.records[zone]
+	if !ok {
+		return nil, errors.New("unknown zone")
+	}
+
+	return records, nil
+}
+
+func (f *fakeJanitor) DeleteTXTRecord(_ string, record ChallengeRecord) error {
+	if err := f.deleteErr[record.ID]; err != nil {
+		return err
+	}
+
+	f.deleted = append(f.deleted, record.ID)
+
+	return nil
+}
+
+func TestIsChallengeRecord(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		record   ChallengeRecord
+		expected bool
+	}{
+		{
+			desc:     "lego record",
+			record:   ChallengeRecord{FQDN: "_acme-challenge.example.com.", Value: "LHDhK3oGRvkiefQnx7OOczTY5Tic_xZ6HcMOc_gmtoM"},
+			expected: true,
+		},
+		{
+			desc:     "quoted value",
+			record:   ChallengeRecord{FQDN: "_acme-challenge.example.com.", Value: `"LHDhK3oGRvkiefQnx7OOczTY5Tic_xZ6HcMOc_gmtoM"`},
+			expected: true,
+		},
+		{
+			desc:   "other name",
+			record: ChallengeRecord{FQDN: "example.com.", Value: "LHDhK3oGRvkiefQnx7OOczTY5Tic_xZ6HcMOc_gmtoM"},
+		},
+		{
+			desc:   "other value",
+			record: ChallengeRecord{FQDN: "_acme-challenge.example.com.", Value: "v=spf1 -all"},
+		},
+		{
+			desc:   "padded value",
+			record: ChallengeRecord{FQDN: "_acme-challenge.example.com.", Value: "LHDhK3oGRvkiefQnx7OOczTY5Tic/xZ6HcMOc/gmto="},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			assert.Equal(t, test.expected, IsChallengeRecord(test.record))
+		})
+	}
+}
+
+func TestCleanUpStaleRecords(t *testing.T) {
+	// No CNAME.
+	useAsNameserver(t, dnsmock.NewServer().Build(t))
+
+	value := GetChallengeInfo("example.com", "keyAuth").Value
+
+	janitor := &fakeJanitor{
+		records: map[string][]ChallengeRecord{
+			"example.com": {
+				{ID: "1", FQDN: "_acme-challenge.example.com.", Value: value, CreatedAt: time.Now().Add(-48 * time.Hour)},
+				{ID: "2", FQDN: "_acme-challenge.example.com.", Value: value, CreatedAt: time.Now().Add(-time.Minute)},
+				{ID: "3", FQDN: "_acme-challenge.example.com.", Value: value},
+				{ID: "4", FQDN: "_acme-challenge.example.com.", Value: "manual", CreatedAt: time.Now().Add(-48 * time.Hour)},
+			},
+			"example.org": {
+				{ID: "5", FQDN: "_acme-challenge.www.example.org.", Value: value, CreatedAt: time.Now().Add(-25 * time.Hour)},
+				{ID: "6", FQDN: "_acme-challenge.example.org.", Value: value, CreatedAt: time.Now().Add(-25 * time.Hour)},
+			},
+		},
+		deleteErr: map[string]error{"6": errors.New("boom")},
+	}
+
+	removed, err := CleanUpStaleRecords(janitor, []string{"example.com", "example.org"}, 24*time.Hour)
+	require.EqualError(t, err, "delete TXT record _acme-challenge.example.org. (6): boom")
+
+	require.Len(t, removed, 2)
+	assert.Equal(t, "1", removed[0].ID)
+	assert.Equal(t, "5", removed[1].ID)
+
+	assert.Equal(t, []string{"1", "5"}, janitor.deleted)
+}
+
+func TestCleanUpStaleRecords_unknownZone(t *testing.T) {
+	// No CNAME.
+	useAsNameserver(t, dnsmock.NewServer().Build(t))
+
+	janitor := &fakeJanitor{records: map[string][]ChallengeRecord{}}
+
+	_, err := CleanUpStaleRecords(janitor, []string{"example.com"}, time.Hour)
+	require.EqualError(t, err, "list TXT records of example.com: unknown zone")
+
+	assert.Empty(t, janitor.deleted)
+}
+
+func TestCleanUpStaleRecords_manifest(t *testing.T) {
+	// No CNAME.
+	useAsNameserver(t, dnsmock.NewServer().Build(t))
+
+	manifest := NewManifest(filepath.Join(t.TempDir(), "dns-challenges.json"), []byte("secret"))
+
+	owned := GetChallengeInfo("example.com", "keyAuth").Value
+	other := GetChallengeInfo("example.com", "other").Value
+
+	require.NoError(t, manifest.Add("_acme-challenge.example.com.", owned))
+
+	janitor := &fakeJanitor{
+		records: map[string][]ChallengeRecord{
+			"example.com": {
+				{ID: "1", FQDN: "_acme-challenge.example.com.", Value: owned, CreatedAt: time.Now().Add(-48 * time.Hour)},
+				{ID: "2", FQDN: "_acme-challenge.example.com.", Value: other, CreatedAt: time.Now().Add(-48 * time.Hour)},
+			},
+		},
+	}
+
+	removed, err := CleanUpStaleRecords(janitor, []string{"example.com"}, 24*time.Hour, OnlyManifestRecords(manifest))
+	require.NoError(t, err)
+
+	require.Len(t, removed, 1)
+	assert.Equal(t, "1", removed[0].ID)
+
+	_, ok, err := manifest.Lookup(owned)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestFindStaleRecords_manifestCreationDate(t *testing.T) {
+	// No CNAME.
+	useAsNameserver(t, dnsmock.NewServer().Build(t))
+
+	manifest := NewManifest(filepath.Join(t.TempDir(), "dns-challenges.json"), []byte("secret"))
+
+	value := GetChallengeInfo("example.com", "keyAuth").Value
+
+	require.NoError(t, manifest.Add("_acme-challenge.example.com.", value))
+
+	janitor := &fakeJanitor{
+		records: map[string][]ChallengeRecord{
+			"example.com": {
+				{ID: "1", FQDN: "_acme-challenge.example.com.", Value: value},
+			},
+		},
+	}
+
+	// The creation date of the manifest is used when the provider doesn't know it.
+	stale, err := FindStaleRecords(janitor, []string{"example.com"}, time.Hour, OnlyManifestRecords(manifest))
+	require.NoError(t, err)
+	assert.Empty(t, stale)
+
+	stale, err = FindStaleRecords(janitor, []string{"example.com"}, -time.Hour, OnlyManifestRecords(manifest))
+	require.NoError(t, err)
+	require.Len(t, stale["example.com"], 1)
+	assert.False(t, stale["example.com"][0].CreatedAt.IsZero())
+}
+
+func TestFindStaleRecords_CNAME(t *testing.T) {
+	useAsNameserver(t, dnsmock.NewServer().
+		Query("_acme-challenge.example.com. CNAME", dnsmock.CNAME("example.net.")).
+		Query("example.net. CNAME", dnsmock.Noop).
+		Query("example.net. SOA", dnsmock.SOA("")).
+		Build(t))
+
+	value := GetChallengeInfo("example.com", "keyAuth").Value
+
+	janitor := &fakeJanitor{
+		records: map[string][]ChallengeRecord{
+			"example.com": {},
+			"example.net": {
+				{ID: "1", FQDN: "example.net.", Value: value, CreatedAt: time.Now().Add(-48 * time.Hour)},
+				{ID: "2", FQDN: "www.example.net.", Value: value, CreatedAt: time.Now().Add(-48 * time.Hour)},
+				{ID: "3", FQDN: "example.net.", Value: "v=spf1 -all", CreatedAt: time.Now().Add(-48 * time.Hour)},
+			},
+		},
+	}
+
+	stale, err := FindStaleRecords(janitor, []string{"example.com"}, 24*time.Hour)
+	require.NoError(t, err)
+
+	expected := map[string][]ChallengeRecord{
+		"example.net": {{ID: "1", FQDN: "example.net.", Value: value, CreatedAt: janitor.records["example.net"][0].CreatedAt}},
+	}
+
+	assert.Equal(t, expected, stale)
+}
+
+func TestFindStaleRecords_CNAME_manifest(t *testing.T) {
+	useAsNameserver(t, dnsmock.NewServer().
+		Query("_acme-challenge.www.example.com. CNAME", dnsmock.CNAME("example.net.")).
+		Query("example.net. CNAME", dnsmock.Noop).
+		Query("example.net. SOA", dnsmock.SOA("")).
+		Build(t))
+
+	manifest := NewManifest(filepath.Join(t.TempDir(), "dns-challenges.json"), []byte("secret"))
+
+	value := GetChallengeInfo("www.example.com", "keyAuth").Value
+
+	require.NoError(t, manifest.Add("_acme-challenge.www.example.com.", value))
+
+	janitor := &fakeJanitor{
+		records: map[string][]ChallengeRecord{
+			"example.com": {},
+			"example.net": {
+				{ID: "1", FQDN: "example.net.", Value: value, CreatedAt: time.Now().Add(-48 * time.Hour)},
+			},
+		},
+	}
+
+	removed, err := CleanUpStaleRecords(janitor, []string{"example.com"}, 24*time.Hour, OnlyManifestRecords(manifest))
+	require.NoError(t, err)
+
+	require.Len(t, removed, 1)
+	assert.Equal(t, []string{"1"}, janitor.deleted)
+}
+
+func TestFindStaleRecords_CNAME_disabled(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	janitor := &fakeJanitor{
+		records: map[string][]ChallengeRecord{
+			"example.com": {},
+		},
+	}
+
+	stale, err := FindStaleRecords(janitor, []string{"example.com"}, 24*time.Hour)
+	require.NoError(t, err)
+
+	assert.Empty(t, stale)
+}
@@ -0,0 +1,121 @@
+package dns01
+
+import (
+	"context"
+	"path"
+	"reflect"
+	"time"
+
+	"github.com/go-acme/lego/v4/log"
+)
+
+// AddLogger defines the structured logger of the events of the challenge:
+// the calls to the DNS provider, the propagation checks, and the resolutions of the challenges.
+// The logger is also provided to the DNS provider through the context (see [log.FromContext]),
+// if the provider implements challenge.ProviderContext.
+func AddLogger(logger log.StructuredLogger) ChallengeOption {
+	return func(chlg *Challenge) error {
+		chlg.logger = logger
+
+		return nil
+	}
+}
+
+// SolveObserver is notified of the result of the resolution of each challenge (ex: metrics).
+// If the structured logger of the challenge (see AddLogger) implements SolveObserver,
+// ObserveSolve is called by the challenge when a challenge is solved or failed, in addition to the event.
+type SolveObserver interface {
+	ObserveSolve(provider, domain string, duration time.Duration, err error)
+}
+
+// withLogger adds the logger of the challenge to the context.
+func (c *Challenge) withLogger(ctx context.Context) context.Context {
+	return log.NewContext(ctx, c.logger)
+}
+
+// logProviderCall writes the event of a call to the DNS provider.
+func (c *Challenge) logProviderCall(operation string, domains []string, start time.Time, err error) {
+	if c.logger == nil {
+		return
+	}
+
+	args := []any{
+		"provider", providerName(c.provider),
+		"operation", operation,
+		"domains", domains,
+		"duration", time.Since(start),
+	}
+
+	if err != nil {
+		c.logger.Error("dns01: provider call failed", append(args, "error", err)...)
+
+		return
+	}
+
+	c.logger.Info("dns01: provider call", args...)
+}
+
+// logSolve writes the event of the resolution of a challenge: the propagation of the record and the validation by the CA.
+func (c *Challenge) logSolve(domain string, start time.Time, err error) {
+	if c.logger == nil {
+		return
+	}
+
+	duration := time.Since(start)
+
+	if observer, ok := c.logger.(SolveObserver); ok {
+		observer.ObserveSolve(providerName(c.provider), domain, duration, err)
+	}
+
+	args := []any{
+		"provider", providerName(c.provider),
+		"domain", domain,
+		"duration", duration,
+	}
+
+	if err != nil {
+		c.logger.Error("dns01: challenge failed", append(args, "error", err)...)
+
+		return
+	}
+
+	c.logger.Info("dns01: challenge solved", args...)
+}
+
+// logPropagationCheck writes the event of a propagation check.
+func (c *Challenge) logPropagationCheck(domain, fqdn string, propagated bool, err error) {
+	if c.logger == nil {
+		return
+	}
+
+	args := []any{
+		"provider", providerName(c.provider),
+		"domain", domain,
+		"fqdn", fqdn,
+		"propagated", propagated,
+	}
+
+	if err != nil {
+		args = append(args, "error", err)
+	}
+
+	c.logger.Debug("dns01: propagation check", args...)
+}
+
+// providerName returns the name of the package of the provider (ex: cloudflare).
+func providerName(provider any) string {
+	if provider == nil {
+		return ""
+	}
+
+	typ := reflect.TypeOf(provider)
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ.PkgPath() == "" {
+		return typ.String()
+	}
+
+	return path.Base(typ.PkgPath())
+}
@@ -0,0 +1,183 @@
+package dns01
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type loggedEvent struct {
+	level string
+	msg   string
+	args  []any
+}
+
+type recordLogger struct {
+	mu     sync.Mutex
+	events []loggedEvent
+}
+
+func (l *recordLogger) Debug(msg string, args ...any) { l.record("debug", msg, args) }
+func (l *recordLogger) Info(msg string, args ...any)  { l.record("info", msg, args) }
+func (l *recordLogger) Warn(msg string, args ...any)  { l.record("warn", msg, args) }
+func (l *recordLogger) Error(msg string, args ...any) { l.record("error", msg, args) }
+
+func (l *recordLogger) record(level, msg string, args []any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.events = append(l.events, loggedEvent{level: level, msg: msg, args: args})
+}
+
+type providerContextMock struct {
+	logger log.StructuredLogger
+	err    error
+}
+
+func (p *providerContextMock) Present(_, _, _ string) error { return errors.New("unexpected call") }
+func (p *providerContextMock) CleanUp(_, _, _ string) error { return errors.New("unexpected call") }
+
+func (p *providerContextMock) PresentContext(ctx context.Context, _, _, _ string) error {
+	p.logger, _ = log.FromContext(ctx)
+
+	return p.err
+}
+
+func (p *providerContextMock) CleanUpContext(ctx context.Context, _, _, _ string) error {
+	p.logger, _ = log.FromContext(ctx)
+
+	return p.err
+}
+
+func TestAddLogger(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	logger := &recordLogger{}
+
+	provider := &providerContextMock{err: errors.New("OOPS")}
+
+	chlg := NewChallenge(core, nil, provider, AddLogger(logger))
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "a"}},
+	}
+
+	err = chlg.PreSolve(authz)
+	require.Error(t, err)
+
+	assert.Same(t, logger, provider.logger)
+
+	require.Len(t, logger.events, 1)
+
+	event := logger.events[0]
+
+	assert.Equal(t, "error", event.level)
+	assert.Equal(t, "dns01: provider call failed", event.msg)
+	assert.Equal(t, []any{"provider", "dns01", "operation", "present", "domains", []string{"example.com"}}, event.args[:6])
+	assert.Equal(t, []any{"error", provider.err}, event.args[8:])
+}
+
+func TestChallenge_Solve_logger(t *testing.T) {
+	logger := &recordLogger{}
+
+	chlg := NewChallenge(nil, nil, &providerContextMock{}, AddLogger(logger))
+
+	// The authorization has no DNS-01 challenge.
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.HTTP01.String(), Token: "a"}},
+	}
+
+	err := chlg.Solve(authz)
+	require.Error(t, err)
+
+	require.Len(t, logger.events, 1)
+
+	event := logger.events[0]
+
+	assert.Equal(t, "error", event.level)
+	assert.Equal(t, "dns01: challenge failed", event.msg)
+	assert.Equal(t, []any{"provider", "dns01", "domain", "example.com", "duration"}, event.args[:5])
+	assert.Equal(t, []any{"error", err}, event.args[6:])
+}
+
+type observerLogger struct {
+	recordLogger
+
+	provider, domain string
+	err              error
+}
+
+func (l *observerLogger) ObserveSolve(provider, domain string, _ time.Duration, err error) {
+	l.provider = provider
+	l.domain = domain
+	l.err = err
+}
+
+func TestChallenge_Solve_observer(t *testing.T) {
+	logger := &observerLogger{}
+
+	chlg := NewChallenge(nil, nil, &providerContextMock{}, AddLogger(logger))
+
+	// The authorization has no DNS-01 challenge.
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.HTTP01.String(), Token: "a"}},
+	}
+
+	err := chlg.Solve(authz)
+	require.Error(t, err)
+
+	assert.Equal(t, "dns01", logger.provider)
+	assert.Equal(t, "example.com", logger.domain)
+	assert.Equal(t, err, logger.err)
+}
+
+func TestChallenge_noLogger(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	provider := &providerContextMock{}
+
+	chlg := NewChallenge(core, nil, provider)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "a"}},
+	}
+
+	err = chlg.PreSolve(authz)
+	require.NoError(t, err)
+
+	assert.Nil(t, provider.logger)
+}
+
+func Test_providerName(t *testing.T) {
+	assert.Equal(t, "dns01", providerName(&providerMock{}))
+	assert.Equal(t, "dns01", providerName(providerMock{}))
+	assert.Empty(t, providerName(nil))
+}
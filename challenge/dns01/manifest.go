@@ -0,0 +1,311 @@
+package dns01
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const manifestKeySize = 32
+
+// Manifest keeps track of the TXT records created by this client in a signed local file.
+//
+// The value of a challenge record cannot be tagged (its format is defined by the ACME protocol),
+// so the cleanups use the manifest to only delete the values created by this client,
+// and to protect the records of the other ACME clients (certbot, other lego instances) sharing a zone.
+type Manifest struct {
+	path string
+	key  []byte
+
+	mu sync.Mutex
+}
+
+// NewManifest creates a manifest stored in a file, the content of the file is signed with the key (HMAC-SHA256).
+func NewManifest(path string, key []byte) *Manifest {
+	return &Manifest{path: path, key: key}
+}
+
+// OpenManifest creates a manifest stored in a file.
+// The signature key is stored next to the file (`<path>.key`), and generated if it doesn't exist.
+func OpenManifest(path string) (*Manifest, error) {
+	keyPath := path + ".key"
+
+	key, err := os.ReadFile(keyPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		key = make([]byte, manifestKeySize)
+
+		_, err = rand.Read(key)
+		if err != nil {
+			return nil, fmt.Errorf("manifest: generate key: %w", err)
+		}
+
+		err = os.MkdirAll(filepath.Dir(keyPath), 0o700)
+		if err != nil {
+			return nil, fmt.Errorf("manifest: %w", err)
+		}
+
+		err = os.WriteFile(keyPath, key, 0o600)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("manifest: key: %w", err)
+	}
+
+	if len(key) < manifestKeySize {
+		return nil, fmt.Errorf("manifest: the key %s is too short", keyPath)
+	}
+
+	return NewManifest(path, key), nil
+}
+
+// Add records a TXT record created by this client.
+func (m *Manifest) Add(fqdn, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	records, err := m.load()
+	if err != nil {
+		return err
+	}
+
+	value = normalizeTXTValue(value)
+
+	records = slices.DeleteFunc(records, func(r manifestRecord) bool { return r.Value == value })
+
+	records = append(records, manifestRecord{
+		FQDN:      strings.ToLower(fqdn),
+		Value:     value,
+		CreatedAt: time.Now().UTC(),
+	})
+
+	return m.save(records)
+}
+
+// Remove removes a TXT record from the manifest (the record has been deleted).
+func (m *Manifest) Remove(value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	records, err := m.load()
+	if err != nil {
+		return err
+	}
+
+	value = normalizeTXTValue(value)
+
+	size := len(records)
+
+	records = slices.DeleteFunc(records, func(r manifestRecord) bool { return r.Value == value })
+
+	if len(records) == size {
+		return nil
+	}
+
+	return m.save(records)
+}
+
+// Lookup returns the TXT record of the manifest matching the value.
+// An error is returned if the signature of the manifest is invalid.
+func (m *Manifest) Lookup(value string) (ChallengeRecord, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	records, err := m.load()
+	if err != nil {
+		return ChallengeRecord{}, false, err
+	}
+
+	value = normalizeTXTValue(value)
+
+	for _, r := range records {
+		if r.Value == value {
+			return ChallengeRecord{FQDN: r.FQDN, Value: r.Value, CreatedAt: r.CreatedAt}, true, nil
+		}
+	}
+
+	return ChallengeRecord{}, false, nil
+}
+
+// fqdns returns the FQDNs of the TXT records of the manifest (before the CNAMEs resolutions).
+func (m *Manifest) fqdns() ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	records, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var fqdns []string
+
+	for _, r := range records {
+		if !slices.Contains(fqdns, r.FQDN) {
+			fqdns = append(fqdns, r.FQDN)
+		}
+	}
+
+	return fqdns, nil
+}
+
+func (m *Manifest) load() ([]manifestRecord, error) {
+	data, err := os.ReadFile(m.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("manifest: %w", err)
+	}
+
+	var file manifestFile
+
+	err = json.Unmarshal(data, &file)
+	if err != nil {
+		return nil, fmt.Errorf("manifest: %s: %w", m.path, err)
+	}
+
+	// The records are signed in their compact form, the file is indented.
+	raw := &bytes.Buffer{}
+
+	err = json.Compact(raw, file.Records)
+	if err != nil {
+		return nil, fmt.Errorf("manifest: %s: %w", m.path, err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(file.Signature)
+	if err != nil || !hmac.Equal(signature, m.sign(raw.Bytes())) {
+		return nil, fmt.Errorf("manifest: the signature of %s is invalid", m.path)
+	}
+
+	var records []manifestRecord
+
+	err = json.Unmarshal(raw.Bytes(), &records)
+	if err != nil {
+		return nil, fmt.Errorf("manifest: %s: %w", m.path, err)
+	}
+
+	return records, nil
+}
+
+func (m *Manifest) save(records []manifestRecord) error {
+	raw, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("manifest: %w", err)
+	}
+
+	data, err := json.MarshalIndent(manifestFile{
+		Records:   raw,
+		Signature: base64.RawURLEncoding.EncodeToString(m.sign(raw)),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("manifest: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(m.path), 0o700)
+	if err != nil {
+		return fmt.Errorf("manifest: %w", err)
+	}
+
+	// The file is replaced atomically: a crash cannot leave a truncated manifest.
+	tmp, err := os.CreateTemp(filepath.Dir(m.path), filepath.Base(m.path)+".*")
+	if err != nil {
+		return fmt.Errorf("manifest: %w", err)
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("manifest: %w", err)
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("manifest: %w", err)
+	}
+
+	err = os.Rename(tmp.Name(), m.path)
+	if err != nil {
+		return fmt.Errorf("manifest: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Manifest) sign(data []byte) []byte {
+	mac := hmac.New(sha256.New, m.key)
+	_, _ = mac.Write(data)
+
+	return mac.Sum(nil)
+}
+
+type manifestFile struct {
+	Records   json.RawMessage `json:"records"`
+	Signature string          `json:"signature"`
+}
+
+type manifestRecord struct {
+	FQDN      string    `json:"fqdn"`
+	Value     string    `json:"value"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func normalizeTXTValue(value string) string {
+	return strings.Trim(value, `"`)
+}
+
+// AddRecordManifest records the TXT records created by the challenge in the manifest,
+// and restricts the cleanups to the records of the manifest:
+// a record unknown to the manifest is not deleted.
+func AddRecordManifest(manifest *Manifest) ChallengeOption {
+	return func(chlg *Challenge) error {
+		chlg.manifest = manifest
+		return nil
+	}
+}
+
+func (c *Challenge) addToManifest(domain, keyAuth string) error {
+	if c.manifest == nil {
+		return nil
+	}
+
+	return c.manifest.Add(c.resolver.getChallengeFQDN(domain, false), challengeValue(keyAuth))
+}
+
+func (c *Challenge) checkManifest(domain, keyAuth string) error {
+	if c.manifest == nil {
+		return nil
+	}
+
+	_, ok, err := c.manifest.Lookup(challengeValue(keyAuth))
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("the TXT record of %s was not created by this client: the cleanup is skipped", domain)
+	}
+
+	return nil
+}
+
+func (c *Challenge) removeFromManifest(keyAuth string) error {
+	if c.manifest == nil {
+		return nil
+	}
+
+	return c.manifest.Remove(challengeValue(keyAuth))
+}
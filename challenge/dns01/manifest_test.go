@@ -0,0 +1,160 @@
+package dns01
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "dns-challenges.json")
+
+	manifest, err := OpenManifest(path)
+	require.NoError(t, err)
+
+	_, ok, err := manifest.Lookup("value")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	err = manifest.Add("_acme-challenge.Example.com.", "value")
+	require.NoError(t, err)
+
+	record, ok, err := manifest.Lookup(`"value"`)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	assert.Equal(t, "_acme-challenge.example.com.", record.FQDN)
+	assert.Equal(t, "value", record.Value)
+	assert.WithinDuration(t, time.Now(), record.CreatedAt, time.Minute)
+
+	// The key is reused by the next instances.
+	other, err := OpenManifest(path)
+	require.NoError(t, err)
+
+	_, ok, err = other.Lookup("value")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	err = other.Remove("value")
+	require.NoError(t, err)
+
+	_, ok, err = manifest.Lookup("value")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	info, err := os.Stat(path + ".key")
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestManifest_invalidSignature(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dns-challenges.json")
+
+	manifest := NewManifest(path, []byte("secret"))
+
+	err := manifest.Add("_acme-challenge.example.com.", "value")
+	require.NoError(t, err)
+
+	other := NewManifest(path, []byte("other"))
+
+	_, _, err = other.Lookup("value")
+	require.EqualError(t, err, "manifest: the signature of "+path+" is invalid")
+
+	err = other.Add("_acme-challenge.example.org.", "value")
+	require.Error(t, err)
+
+	err = other.Remove("value")
+	require.Error(t, err)
+}
+
+func TestChallenge_manifest(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	manifest := NewManifest(filepath.Join(t.TempDir(), "dns-challenges.json"), []byte("secret"))
+
+	chlg := NewChallenge(core, nil, &providerMock{}, AddRecordManifest(manifest))
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "a"}},
+	}
+
+	// Not created by this client.
+	err = chlg.CleanUp(authz)
+	require.EqualError(t, err, "[example.com] acme: the TXT record of example.com was not created by this client: the cleanup is skipped")
+
+	err = chlg.PreSolve(authz)
+	require.NoError(t, err)
+
+	keyAuth, err := core.GetKeyAuthorization("a")
+	require.NoError(t, err)
+
+	record, ok, err := manifest.Lookup(GetChallengeInfo("example.com", keyAuth).Value)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	assert.Equal(t, "_acme-challenge.example.com.", record.FQDN)
+
+	err = chlg.CleanUp(authz)
+	require.NoError(t, err)
+
+	_, ok, err = manifest.Lookup(record.Value)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestChallenge_manifest_batch(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	manifest := NewManifest(filepath.Join(t.TempDir(), "dns-challenges.json"), []byte("secret"))
+
+	provider := &providerBatchMock{}
+
+	chlg := NewChallenge(core, nil, provider, AddRecordManifest(manifest))
+
+	authzs := []acme.Authorization{
+		{
+			Identifier: acme.Identifier{Value: "example.com"},
+			Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "a"}},
+		},
+		{
+			Identifier: acme.Identifier{Value: "example.org"},
+			Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "b"}},
+		},
+	}
+
+	err = chlg.PreSolveBatch(authzs[:1])
+	require.NoError(t, err)
+
+	err = chlg.CleanUpBatch(authzs)
+	require.EqualError(t, err, "[example.org] acme: the TXT record of example.org was not created by this client: the cleanup is skipped")
+
+	require.Len(t, provider.cleaned, 1)
+	assert.Equal(t, "example.com", provider.cleaned[0].Domain)
+
+	_, ok, err := manifest.Lookup(GetChallengeInfo("example.com", provider.cleaned[0].KeyAuth).Value)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
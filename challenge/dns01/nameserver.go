@@ -4,9 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"net"
-	"os"
+	"net/url"
 	"slices"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -26,6 +25,34 @@ var defaultNameservers = []string{
 // recursiveNameservers are used to pre-check DNS propagation.
 var recursiveNameservers = getNameservers(defaultResolvConf, defaultNameservers)
 
+// discoveryNameservers are used to follow the CNAMEs, and to find the zones and the authoritative nameservers.
+// The recursive nameservers are used if empty.
+var discoveryNameservers []string
+
+// propagationNameservers are used to check the propagation of the TXT record.
+// The recursive nameservers are used if empty.
+var propagationNameservers []string
+
+// defaultsMu guards the package defaults (see the SetDefault* functions):
+// they can be changed while challenges are running.
+var defaultsMu sync.RWMutex
+
+// loadDefault reads a package default.
+func loadDefault[T any](v *T) T {
+	defaultsMu.RLock()
+	defer defaultsMu.RUnlock()
+
+	return *v
+}
+
+// storeDefault changes a package default.
+func storeDefault[T any](v *T, value T) {
+	defaultsMu.Lock()
+	defer defaultsMu.Unlock()
+
+	*v = value
+}
+
 // soaCacheEntry holds a cached SOA record (only selected fields).
 type soaCacheEntry struct {
 	zone      string    // zone apex (a domain name)
@@ -55,20 +82,181 @@ func ClearFqdnCache() {
 	})
 }
 
+// AddDNSTimeout defines the timeout of the DNS queries performed by the challenge.
+// The timeout only applies to this challenge instance (see SetDefaultDNSTimeout).
+//
+// Breaking change: the timeout is not used by the DNS providers anymore (ex: FindZoneByFqdn),
+// call SetDefaultDNSTimeout to use the same timeout in the DNS providers.
 func AddDNSTimeout(timeout time.Duration) ChallengeOption {
-	return func(_ *Challenge) error {
-		dnsTimeout = timeout
+	return func(chlg *Challenge) error {
+		chlg.resolver.timeout = timeout
 		return nil
 	}
 }
 
+// AddRecursiveNameservers defines the recursive nameservers used by the challenge
+// to follow the CNAMEs and to check the propagation of the TXT record.
+// The nameservers only apply to this challenge instance (see SetDefaultRecursiveNameservers).
+// The healthy nameservers are queried first, by latency (see AddWeightedRecursiveNameservers).
+//
+// Breaking change: the nameservers are not used by the DNS providers anymore (ex: FindZoneByFqdn),
+// call SetDefaultRecursiveNameservers to use the same nameservers in the DNS providers.
 func AddRecursiveNameservers(nameservers []string) ChallengeOption {
-	return func(_ *Challenge) error {
-		recursiveNameservers = ParseNameservers(nameservers)
+	return func(chlg *Challenge) error {
+		chlg.resolver.nameservers = ParseNameservers(nameservers)
+		chlg.resolver.weights = nil
+
+		return nil
+	}
+}
+
+// AddDiscoveryNameservers defines the recursive nameservers used by the challenge
+// to follow the CNAMEs, and to find the zone and the authoritative nameservers of the TXT record (ex: internal resolvers).
+// They take precedence over AddRecursiveNameservers for this phase (see SetDefaultDiscoveryNameservers).
+func AddDiscoveryNameservers(nameservers []string) ChallengeOption {
+	return func(chlg *Challenge) error {
+		chlg.resolver.discoveryNameservers = ParseNameservers(nameservers)
+		return nil
+	}
+}
+
+// AddPropagationNameservers defines the recursive nameservers used by the challenge
+// to check the propagation of the TXT record (ex: public resolvers).
+// They take precedence over AddRecursiveNameservers for this phase (see SetDefaultPropagationNameservers).
+func AddPropagationNameservers(nameservers []string) ChallengeOption {
+	return func(chlg *Challenge) error {
+		chlg.resolver.propagationNameservers = ParseNameservers(nameservers)
+		return nil
+	}
+}
+
+// AddDNSTransport defines the transport of the DNS queries performed by the challenge.
+// The transport only applies to this challenge instance (see SetDefaultDNSTransport).
+func AddDNSTransport(transport DNSTransport) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if _, err := ParseDNSTransport(string(transport)); err != nil {
+			return err
+		}
+
+		chlg.resolver.transport = transport
+
 		return nil
 	}
 }
 
+// SetDefaultDNSTimeout defines the default timeout of the DNS queries.
+// It is used by the package functions (ex: FindZoneByFqdn),
+// and by the challenges without a specific timeout (see AddDNSTimeout).
+func SetDefaultDNSTimeout(timeout time.Duration) {
+	storeDefault(&dnsTimeout, timeout)
+}
+
+// SetDefaultRecursiveNameservers defines the default recursive nameservers.
+// They are used by the package functions (ex: FindZoneByFqdn, GetChallengeInfo),
+// and by the challenges without specific nameservers (see AddRecursiveNameservers).
+func SetDefaultRecursiveNameservers(nameservers []string) {
+	storeDefault(&recursiveNameservers, ParseNameservers(nameservers))
+}
+
+// SetDefaultDiscoveryNameservers defines the default recursive nameservers
+// used to follow the CNAMEs, and to find the zones and the authoritative nameservers.
+// They are used by the package functions (ex: FindZoneByFqdn),
+// and by the challenges without specific nameservers (see AddDiscoveryNameservers).
+// The recursive nameservers are used if empty.
+func SetDefaultDiscoveryNameservers(nameservers []string) {
+	storeDefault(&discoveryNameservers, ParseNameservers(nameservers))
+}
+
+// SetDefaultPropagationNameservers defines the default recursive nameservers
+// used to check the propagation of the TXT record,
+// by the challenges without specific nameservers (see AddPropagationNameservers).
+// The recursive nameservers are used if empty.
+func SetDefaultPropagationNameservers(nameservers []string) {
+	storeDefault(&propagationNameservers, ParseNameservers(nameservers))
+}
+
+// SetDefaultDNSTransport defines the default transport of the DNS queries.
+// It is used by the package functions (ex: FindZoneByFqdn),
+// and by the challenges without a specific transport (see AddDNSTransport).
+func SetDefaultDNSTransport(transport DNSTransport) {
+	storeDefault(&dnsTransport, transport)
+}
+
+// resolver holds the DNS settings of a challenge instance.
+// The zero value, and a nil resolver, use the package defaults.
+type resolver struct {
+	nameservers            []string
+	discoveryNameservers   []string
+	propagationNameservers []string
+	timeout                time.Duration
+	transport              DNSTransport
+	proxy                  *url.URL
+	transform              *ChallengeTransform
+
+	// weights the weights of the recursive nameservers (see AddWeightedRecursiveNameservers).
+	weights map[string]int
+}
+
+// recursiveNSs returns the recursive nameservers to use.
+func (r *resolver) recursiveNSs() []string {
+	if r == nil || len(r.nameservers) == 0 {
+		return loadDefault(&recursiveNameservers)
+	}
+
+	return r.nameservers
+}
+
+// discoveryNSs returns the recursive nameservers used to follow the CNAMEs,
+// and to find the zones and the authoritative nameservers.
+// The settings of the challenge take precedence over the package defaults.
+func (r *resolver) discoveryNSs() []string {
+	if r != nil && len(r.discoveryNameservers) > 0 {
+		return r.discoveryNameservers
+	}
+
+	if r == nil || len(r.nameservers) == 0 {
+		if nameservers := loadDefault(&discoveryNameservers); len(nameservers) > 0 {
+			return nameservers
+		}
+	}
+
+	return r.recursiveNSs()
+}
+
+// propagationNSs returns the recursive nameservers used to check the propagation of the TXT record.
+// The settings of the challenge take precedence over the package defaults.
+func (r *resolver) propagationNSs() []string {
+	if r != nil && len(r.propagationNameservers) > 0 {
+		return r.propagationNameservers
+	}
+
+	if r == nil || len(r.nameservers) == 0 {
+		if nameservers := loadDefault(&propagationNameservers); len(nameservers) > 0 {
+			return nameservers
+		}
+	}
+
+	return r.recursiveNSs()
+}
+
+// queryTimeout returns the timeout of the DNS queries.
+func (r *resolver) queryTimeout() time.Duration {
+	if r == nil || r.timeout <= 0 {
+		return loadDefault(&dnsTimeout)
+	}
+
+	return r.timeout
+}
+
+// queryTransport returns the transport of the DNS queries.
+func (r *resolver) queryTransport() DNSTransport {
+	if r == nil || r.transport == "" {
+		return defaultDNSTransport()
+	}
+
+	return r.transport
+}
+
 // getNameservers attempts to get systems nameservers before falling back to the defaults.
 func getNameservers(path string, defaults []string) []string {
 	config, err := dns.ClientConfigFromFile(path)
@@ -96,14 +284,21 @@ func ParseNameservers(servers []string) []string {
 
 // lookupNameservers returns the authoritative nameservers for the given fqdn.
 func lookupNameservers(fqdn string) ([]string, error) {
+	return (*resolver)(nil).lookupNameservers(fqdn)
+}
+
+// lookupNameservers returns the authoritative nameservers for the given fqdn.
+func (rs *resolver) lookupNameservers(fqdn string) ([]string, error) {
 	var authoritativeNss []string
 
-	zone, err := FindZoneByFqdn(fqdn)
+	soa, err := rs.lookupSoaByFqdn(fqdn, rs.discoveryNSs())
 	if err != nil {
-		return nil, fmt.Errorf("could not find zone: %w", err)
+		return nil, fmt.Errorf("could not find zone: [fqdn=%s] %w", fqdn, err)
 	}
 
-	r, err := dnsQuery(zone, dns.TypeNS, recursiveNameservers, true)
+	zone := soa.zone
+
+	r, err := rs.query(zone, dns.TypeNS, rs.discoveryNSs(), true)
 	if err != nil {
 		return nil, fmt.Errorf("NS call failed: %w", err)
 	}
@@ -124,13 +319,13 @@ func lookupNameservers(fqdn string) ([]string, error) {
 // FindPrimaryNsByFqdn determines the primary nameserver of the zone apex for the given fqdn
 // by recursing up the domain labels until the nameserver returns a SOA record in the answer section.
 func FindPrimaryNsByFqdn(fqdn string) (string, error) {
-	return FindPrimaryNsByFqdnCustom(fqdn, recursiveNameservers)
+	return FindPrimaryNsByFqdnCustom(fqdn, (*resolver)(nil).discoveryNSs())
 }
 
 // FindPrimaryNsByFqdnCustom determines the primary nameserver of the zone apex for the given fqdn
 // by recursing up the domain labels until the nameserver returns a SOA record in the answer section.
 func FindPrimaryNsByFqdnCustom(fqdn string, nameservers []string) (string, error) {
-	soa, err := lookupSoaByFqdn(fqdn, nameservers)
+	soa, err := (*resolver)(nil).lookupSoaByFqdn(fqdn, nameservers)
 	if err != nil {
 		return "", fmt.Errorf("[fqdn=%s] %w", fqdn, err)
 	}
@@ -141,13 +336,13 @@ func FindPrimaryNsByFqdnCustom(fqdn string, nameservers []string) (string, error
 // FindZoneByFqdn determines the zone apex for the given fqdn
 // by recursing up the domain labels until the nameserver returns a SOA record in the answer section.
 func FindZoneByFqdn(fqdn string) (string, error) {
-	return FindZoneByFqdnCustom(fqdn, recursiveNameservers)
+	return FindZoneByFqdnCustom(fqdn, (*resolver)(nil).discoveryNSs())
 }
 
 // FindZoneByFqdnCustom determines the zone apex for the given fqdn
 // by recursing up the domain labels until the nameserver returns a SOA record in the answer section.
 func FindZoneByFqdnCustom(fqdn string, nameservers []string) (string, error) {
-	soa, err := lookupSoaByFqdn(fqdn, nameservers)
+	soa, err := (*resolver)(nil).lookupSoaByFqdn(fqdn, nameservers)
 	if err != nil {
 		return "", fmt.Errorf("[fqdn=%s] %w", fqdn, err)
 	}
@@ -155,9 +350,12 @@ func FindZoneByFqdnCustom(fqdn string, nameservers []string) (string, error) {
 	return soa.zone, nil
 }
 
-func lookupSoaByFqdn(fqdn string, nameservers []string) (*soaCacheEntry, error) {
+func (rs *resolver) lookupSoaByFqdn(fqdn string, nameservers []string) (*soaCacheEntry, error) {
+	// The nameservers are part of the key because the answers can differ (ex: split-horizon DNS).
+	key := fqdn + "|" + strings.Join(nameservers, ",")
+
 	// Do we have it cached and is it still fresh?
-	entAny, ok := fqdnSoaCache.Load(fqdn)
+	entAny, ok := fqdnSoaCache.Load(key)
 	if ok && entAny != nil {
 		ent, ok1 := entAny.(*soaCacheEntry)
 		if ok1 && !ent.isExpired() {
@@ -165,24 +363,24 @@ func lookupSoaByFqdn(fqdn string, nameservers []string) (*soaCacheEntry, error)
 		}
 	}
 
-	ent, err := fetchSoaByFqdn(fqdn, nameservers)
+	ent, err := rs.fetchSoaByFqdn(fqdn, nameservers)
 	if err != nil {
 		return nil, err
 	}
 
-	fqdnSoaCache.Store(fqdn, ent)
+	fqdnSoaCache.Store(key, ent)
 
 	return ent, nil
 }
 
-func fetchSoaByFqdn(fqdn string, nameservers []string) (*soaCacheEntry, error) {
+func (rs *resolver) fetchSoaByFqdn(fqdn string, nameservers []string) (*soaCacheEntry, error) {
 	var (
 		err error
 		r   *dns.Msg
 	)
 
 	for domain := range DomainsSeq(fqdn) {
-		r, err = dnsQuery(domain, dns.TypeSOA, nameservers, true)
+		r, err = rs.query(domain, dns.TypeSOA, nameservers, true)
 		if err != nil {
 			continue
 		}
@@ -229,6 +427,10 @@ func dnsMsgContainsCNAME(msg *dns.Msg) bool {
 }
 
 func dnsQuery(fqdn string, rtype uint16, nameservers []string, recursive bool) (*dns.Msg, error) {
+	return (*resolver)(nil).query(fqdn, rtype, nameservers, recursive)
+}
+
+func (rs *resolver) query(fqdn string, rtype uint16, nameservers []string, recursive bool) (*dns.Msg, error) {
 	m := createDNSMsg(fqdn, rtype, recursive)
 
 	if len(nameservers) == 0 {
@@ -241,8 +443,9 @@ func dnsQuery(fqdn string, rtype uint16, nameservers []string, recursive bool) (
 		errAll error
 	)
 
-	for _, ns := range nameservers {
-		r, err = sendDNSQuery(m, ns)
+	// The healthy nameservers are queried first (see AddWeightedRecursiveNameservers).
+	for _, ns := range rs.orderNameservers(nameservers) {
+		r, err = rs.exchangeWithHealth(m, ns)
 		if err == nil && len(r.Answer) > 0 {
 			break
 		}
@@ -269,9 +472,9 @@ func createDNSMsg(fqdn string, rtype uint16, recursive bool) *dns.Msg {
 	return m
 }
 
-func sendDNSQuery(m *dns.Msg, ns string) (*dns.Msg, error) {
-	if ok, _ := strconv.ParseBool(os.Getenv("LEGO_EXPERIMENTAL_DNS_TCP_ONLY")); ok {
-		tcp := &dns.Client{Net: "tcp", Timeout: dnsTimeout}
+func sendDNSQuery(m *dns.Msg, ns string, timeout time.Duration, transport DNSTransport) (*dns.Msg, error) {
+	if transport == DNSTransportTCP {
+		tcp := &dns.Client{Net: "tcp", Timeout: timeout}
 
 		r, _, err := tcp.Exchange(m, ns)
 		if err != nil {
@@ -281,13 +484,27 @@ func sendDNSQuery(m *dns.Msg, ns string) (*dns.Msg, error) {
 		return r, nil
 	}
 
-	udp := &dns.Client{Net: "udp", Timeout: dnsTimeout}
+	udp := &dns.Client{Net: "udp", Timeout: timeout}
 	r, _, err := udp.Exchange(m, ns)
 
-	if r != nil && r.Truncated {
-		tcp := &dns.Client{Net: "tcp", Timeout: dnsTimeout}
+	switch {
+	case r != nil && r.Truncated:
+		tcp := &dns.Client{Net: "tcp", Timeout: timeout}
 		// If the TCP request succeeds, the "err" will reset to nil
 		r, _, err = tcp.Exchange(m, ns)
+
+	case err != nil && transport == DNSTransportAuto:
+		// Some networks drop the DNS queries over UDP.
+		tcp := &dns.Client{Net: "tcp", Timeout: timeout}
+
+		var errTCP error
+
+		r, _, errTCP = tcp.Exchange(m, ns)
+		if errTCP != nil {
+			err = fmt.Errorf("udp: %w, tcp: %w", err, errTCP)
+		} else {
+			err = nil
+		}
 	}
 
 	if err != nil {
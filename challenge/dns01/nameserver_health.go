@@ -0,0 +1,242 @@
+package dns01
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	// healthFailureThreshold the number of consecutive failures before a nameserver is considered down.
+	healthFailureThreshold = 2
+
+	// healthDownDuration the duration during which a nameserver considered down is tried last.
+	healthDownDuration = 30 * time.Second
+
+	// healthLatencySmoothing the weight of the last latency in the average latency of a nameserver.
+	healthLatencySmoothing = 0.3
+)
+
+// nsHealth tracks the health of the recursive nameservers, shared by the package functions and the challenges.
+var nsHealth = newNameserverHealth()
+
+// WeightedNameserver a recursive nameserver with a weight (see AddWeightedRecursiveNameservers).
+type WeightedNameserver struct {
+	// Address the address of the nameserver (the port 53 is used by default).
+	Address string
+
+	// Weight the preference of the nameserver (1 by default):
+	// a nameserver with a weight of 2 is preferred to a nameserver with a weight of 1,
+	// unless its latency is more than twice as high.
+	Weight int
+}
+
+// AddWeightedRecursiveNameservers is like AddRecursiveNameservers, with a weight for each nameserver.
+//
+// The healthy nameservers are queried first, ordered by their latency divided by their weight.
+// The nameservers with consecutive failures are only queried when the healthy nameservers don't answer.
+func AddWeightedRecursiveNameservers(nameservers []WeightedNameserver) ChallengeOption {
+	return func(chlg *Challenge) error {
+		chlg.resolver.nameservers = nil
+		chlg.resolver.weights = make(map[string]int)
+
+		for _, nameserver := range nameservers {
+			if nameserver.Weight < 0 {
+				return errors.New("the weight of a nameserver cannot be negative")
+			}
+
+			address := ParseNameservers([]string{nameserver.Address})[0]
+
+			chlg.resolver.nameservers = append(chlg.resolver.nameservers, address)
+			chlg.resolver.weights[address] = max(nameserver.Weight, 1)
+		}
+
+		return nil
+	}
+}
+
+// ProbeNameservers sends a query to each nameserver to update their health, and returns the error of each failing nameserver.
+// The health of the nameservers is also updated by the queries of the challenges:
+// probing the nameservers before solving the challenges avoids waiting for the timeouts of the unhealthy nameservers.
+func ProbeNameservers(nameservers []string) map[string]error {
+	return (*resolver)(nil).probe(ParseNameservers(nameservers))
+}
+
+func (rs *resolver) probe(nameservers []string) map[string]error {
+	errs := make(map[string]error)
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+
+	for _, ns := range nameservers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := rs.exchangeWithHealth(createDNSMsg(".", dns.TypeNS, true), ns)
+			if err != nil {
+				mu.Lock()
+				errs[ns] = err
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return errs
+}
+
+// exchangeWithHealth sends a DNS query to a nameserver, and records the result in the health of the nameserver.
+func (rs *resolver) exchangeWithHealth(m *dns.Msg, ns string) (*dns.Msg, error) {
+	start := nsHealth.now()
+
+	r, err := rs.exchange(m, ns)
+
+	nsHealth.record(ns, nsHealth.now().Sub(start), err)
+
+	return r, err
+}
+
+// nameserverWeight returns the weight of the nameserver.
+func (rs *resolver) nameserverWeight(ns string) int {
+	if rs == nil {
+		return 1
+	}
+
+	if weight, ok := rs.weights[ns]; ok {
+		return weight
+	}
+
+	return 1
+}
+
+// orderNameservers returns the nameservers in the order of the queries.
+func (rs *resolver) orderNameservers(nameservers []string) []string {
+	return nsHealth.order(nameservers, rs.nameserverWeight)
+}
+
+type nameserverHealth struct {
+	stats map[string]*nameserverStats
+	mu    sync.Mutex
+
+	now func() time.Time
+}
+
+type nameserverStats struct {
+	// latency the average latency of the successful queries.
+	latency time.Duration
+
+	// failures the number of consecutive failures.
+	failures int
+
+	// downUntil the end of the period during which the nameserver is tried last.
+	downUntil time.Time
+}
+
+func newNameserverHealth() *nameserverHealth {
+	return &nameserverHealth{
+		stats: make(map[string]*nameserverStats),
+		now:   time.Now,
+	}
+}
+
+// record updates the health of a nameserver with the result of a query.
+func (h *nameserverHealth) record(ns string, latency time.Duration, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	stats, ok := h.stats[ns]
+	if !ok {
+		stats = &nameserverStats{}
+		h.stats[ns] = stats
+	}
+
+	if err != nil {
+		stats.failures++
+
+		if stats.failures >= healthFailureThreshold {
+			stats.downUntil = h.now().Add(healthDownDuration)
+		}
+
+		return
+	}
+
+	stats.failures = 0
+	stats.downUntil = time.Time{}
+
+	if stats.latency == 0 {
+		stats.latency = latency
+		return
+	}
+
+	stats.latency = time.Duration(healthLatencySmoothing*float64(latency) + (1-healthLatencySmoothing)*float64(stats.latency))
+}
+
+// order sorts the nameservers: the healthy nameservers first, by latency divided by weight,
+// then the nameservers considered down.
+// The nameservers without statistics are tried first (by weight), to measure their latency.
+func (h *nameserverHealth) order(nameservers []string, weight func(ns string) int) []string {
+	if len(nameservers) < 2 {
+		return nameservers
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+
+	type candidate struct {
+		ns     string
+		down   bool
+		score  time.Duration
+		weight int
+	}
+
+	candidates := make([]candidate, 0, len(nameservers))
+
+	for _, ns := range nameservers {
+		c := candidate{ns: ns, weight: max(weight(ns), 1)}
+
+		if stats, ok := h.stats[ns]; ok {
+			c.down = now.Before(stats.downUntil)
+			c.score = stats.latency / time.Duration(c.weight)
+		}
+
+		candidates = append(candidates, c)
+	}
+
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		if a.down != b.down {
+			if a.down {
+				return 1
+			}
+
+			return -1
+		}
+
+		return cmp.Or(cmp.Compare(a.score, b.score), cmp.Compare(b.weight, a.weight))
+	})
+
+	ordered := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		ordered = append(ordered, c.ns)
+	}
+
+	return ordered
+}
+
+// reset forgets the health of the nameservers.
+func (h *nameserverHealth) reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	clear(h.stats)
+}
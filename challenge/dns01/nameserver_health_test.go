@@ -0,0 +1,128 @@
+package dns01
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupNameserverHealth(t *testing.T) (*nameserverHealth, *time.Time) {
+	t.Helper()
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	health := newNameserverHealth()
+	health.now = func() time.Time { return now }
+
+	return health, &now
+}
+
+func Test_nameserverHealth_order_latency(t *testing.T) {
+	health, _ := setupNameserverHealth(t)
+
+	health.record("a:53", 300*time.Millisecond, nil)
+	health.record("b:53", 100*time.Millisecond, nil)
+	health.record("c:53", 200*time.Millisecond, nil)
+
+	ordered := health.order([]string{"a:53", "b:53", "c:53"}, func(string) int { return 1 })
+
+	assert.Equal(t, []string{"b:53", "c:53", "a:53"}, ordered)
+}
+
+func Test_nameserverHealth_order_weight(t *testing.T) {
+	health, _ := setupNameserverHealth(t)
+
+	health.record("a:53", 150*time.Millisecond, nil)
+	health.record("b:53", 100*time.Millisecond, nil)
+
+	weights := map[string]int{"a:53": 2}
+
+	ordered := health.order([]string{"a:53", "b:53"}, func(ns string) int { return weights[ns] })
+
+	assert.Equal(t, []string{"a:53", "b:53"}, ordered)
+
+	// Without statistics: by weight.
+	ordered = health.order([]string{"c:53", "d:53"}, func(ns string) int { return map[string]int{"d:53": 3}[ns] })
+
+	assert.Equal(t, []string{"d:53", "c:53"}, ordered)
+}
+
+func Test_nameserverHealth_order_down(t *testing.T) {
+	health, now := setupNameserverHealth(t)
+
+	health.record("a:53", 10*time.Millisecond, nil)
+	health.record("b:53", 100*time.Millisecond, nil)
+
+	// A single failure is not enough.
+	health.record("a:53", 0, errors.New("timeout"))
+
+	ordered := health.order([]string{"a:53", "b:53"}, func(string) int { return 1 })
+	assert.Equal(t, []string{"a:53", "b:53"}, ordered)
+
+	health.record("a:53", 0, errors.New("timeout"))
+
+	ordered = health.order([]string{"a:53", "b:53"}, func(string) int { return 1 })
+	assert.Equal(t, []string{"b:53", "a:53"}, ordered)
+
+	// After the down duration, the nameserver is ordered by latency again.
+	*now = now.Add(healthDownDuration + time.Second)
+
+	ordered = health.order([]string{"a:53", "b:53"}, func(string) int { return 1 })
+	assert.Equal(t, []string{"a:53", "b:53"}, ordered)
+}
+
+func Test_nameserverHealth_order_recovery(t *testing.T) {
+	health, _ := setupNameserverHealth(t)
+
+	health.record("a:53", 10*time.Millisecond, nil)
+	health.record("b:53", 100*time.Millisecond, nil)
+
+	health.record("a:53", 0, errors.New("timeout"))
+	health.record("a:53", 0, errors.New("timeout"))
+
+	// A successful query ends the down period.
+	health.record("a:53", 10*time.Millisecond, nil)
+
+	ordered := health.order([]string{"a:53", "b:53"}, func(string) int { return 1 })
+	assert.Equal(t, []string{"a:53", "b:53"}, ordered)
+}
+
+func Test_nameserverHealth_record_smoothing(t *testing.T) {
+	health, _ := setupNameserverHealth(t)
+
+	health.record("a:53", 100*time.Millisecond, nil)
+	health.record("a:53", 200*time.Millisecond, nil)
+
+	assert.Equal(t, 130*time.Millisecond, health.stats["a:53"].latency)
+
+	health.reset()
+
+	assert.Empty(t, health.stats)
+}
+
+func TestAddWeightedRecursiveNameservers(t *testing.T) {
+	chlg := NewChallenge(nil, nil, nil, AddWeightedRecursiveNameservers([]WeightedNameserver{
+		{Address: "1.1.1.1", Weight: 3},
+		{Address: "8.8.8.8:53"},
+	}))
+
+	assert.Equal(t, []string{"1.1.1.1:53", "8.8.8.8:53"}, chlg.resolver.recursiveNSs())
+	assert.Equal(t, 3, chlg.resolver.nameserverWeight("1.1.1.1:53"))
+	assert.Equal(t, 1, chlg.resolver.nameserverWeight("8.8.8.8:53"))
+
+	// AddRecursiveNameservers drops the weights.
+	err := AddRecursiveNameservers([]string{"1.1.1.1"})(chlg)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, chlg.resolver.nameserverWeight("1.1.1.1:53"))
+}
+
+func TestAddWeightedRecursiveNameservers_negativeWeight(t *testing.T) {
+	chlg := &Challenge{resolver: &resolver{}}
+
+	err := AddWeightedRecursiveNameservers([]WeightedNameserver{{Address: "1.1.1.1", Weight: -1}})(chlg)
+	require.EqualError(t, err, "the weight of a nameserver cannot be negative")
+}
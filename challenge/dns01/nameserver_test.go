@@ -2,8 +2,11 @@ package dns01
 
 import (
 	"errors"
+	"fmt"
 	"sort"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/go-acme/lego/v4/platform/tester/dnsmock"
 	"github.com/miekg/dns"
@@ -363,3 +366,97 @@ func TestDNSError_Error(t *testing.T) {
 		})
 	}
 }
+
+func TestAddRecursiveNameservers_perChallenge(t *testing.T) {
+	chlgA := NewChallenge(nil, nil, nil, AddRecursiveNameservers([]string{"10.0.0.1"}), AddDNSTimeout(3*time.Second))
+	chlgB := NewChallenge(nil, nil, nil, AddRecursiveNameservers([]string{"8.8.8.8:53"}))
+	chlgC := NewChallenge(nil, nil, nil)
+
+	assert.Equal(t, []string{"10.0.0.1:53"}, chlgA.resolver.recursiveNSs())
+	assert.Equal(t, 3*time.Second, chlgA.resolver.queryTimeout())
+
+	assert.Equal(t, []string{"8.8.8.8:53"}, chlgB.resolver.recursiveNSs())
+	assert.Equal(t, dnsTimeout, chlgB.resolver.queryTimeout())
+
+	// the package defaults are not modified.
+	assert.Equal(t, recursiveNameservers, chlgC.resolver.recursiveNSs())
+	assert.NotContains(t, recursiveNameservers, "10.0.0.1:53")
+
+	// the pre-check uses the resolver of the challenge.
+	assert.Same(t, chlgA.resolver, chlgA.preCheck.resolver)
+}
+
+func TestAddPhaseNameservers_perChallenge(t *testing.T) {
+	chlgA := NewChallenge(nil, nil, nil,
+		AddRecursiveNameservers([]string{"8.8.8.8"}),
+		AddDiscoveryNameservers([]string{"10.0.0.1"}))
+	chlgB := NewChallenge(nil, nil, nil,
+		AddDiscoveryNameservers([]string{"10.0.0.1"}),
+		AddPropagationNameservers([]string{"1.1.1.1:53"}))
+	chlgC := NewChallenge(nil, nil, nil)
+
+	assert.Equal(t, []string{"10.0.0.1:53"}, chlgA.resolver.discoveryNSs())
+	assert.Equal(t, []string{"8.8.8.8:53"}, chlgA.resolver.propagationNSs())
+
+	assert.Equal(t, []string{"10.0.0.1:53"}, chlgB.resolver.discoveryNSs())
+	assert.Equal(t, []string{"1.1.1.1:53"}, chlgB.resolver.propagationNSs())
+
+	assert.Equal(t, recursiveNameservers, chlgC.resolver.discoveryNSs())
+	assert.Equal(t, recursiveNameservers, chlgC.resolver.propagationNSs())
+}
+
+func TestSetDefaultPhaseNameservers(t *testing.T) {
+	originalDiscovery, originalPropagation := discoveryNameservers, propagationNameservers
+
+	t.Cleanup(func() {
+		discoveryNameservers, propagationNameservers = originalDiscovery, originalPropagation
+	})
+
+	SetDefaultDiscoveryNameservers([]string{"10.0.0.1"})
+	SetDefaultPropagationNameservers([]string{"1.1.1.1"})
+
+	chlgA := NewChallenge(nil, nil, nil)
+	chlgB := NewChallenge(nil, nil, nil, AddRecursiveNameservers([]string{"8.8.8.8"}))
+
+	assert.Equal(t, []string{"10.0.0.1:53"}, chlgA.resolver.discoveryNSs())
+	assert.Equal(t, []string{"1.1.1.1:53"}, chlgA.resolver.propagationNSs())
+
+	// the settings of the challenge take precedence over the package defaults.
+	assert.Equal(t, []string{"8.8.8.8:53"}, chlgB.resolver.discoveryNSs())
+	assert.Equal(t, []string{"8.8.8.8:53"}, chlgB.resolver.propagationNSs())
+
+	// the package functions use the discovery nameservers.
+	assert.Equal(t, []string{"10.0.0.1:53"}, (*resolver)(nil).discoveryNSs())
+}
+
+func TestSetDefaultRecursiveNameservers_concurrent(t *testing.T) {
+	originalNameservers, originalTimeout := recursiveNameservers, dnsTimeout
+
+	t.Cleanup(func() {
+		recursiveNameservers, dnsTimeout = originalNameservers, originalTimeout
+	})
+
+	var wg sync.WaitGroup
+
+	for i := range 10 {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			SetDefaultRecursiveNameservers([]string{fmt.Sprintf("10.0.0.%d", i)})
+			SetDefaultDNSTimeout(time.Duration(i+1) * time.Second)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			chlg := NewChallenge(nil, nil, nil)
+
+			assert.NotEmpty(t, chlg.resolver.recursiveNSs())
+			assert.Positive(t, chlg.resolver.queryTimeout())
+		}()
+	}
+
+	wg.Wait()
+}
@@ -1,11 +1,13 @@
 package dns01
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"strings"
 	"time"
 
+	"github.com/go-acme/lego/v4/log"
 	"github.com/miekg/dns"
 )
 
@@ -61,6 +63,22 @@ func PropagationWait(wait time.Duration, skipCheck bool) ChallengeOption {
 	})
 }
 
+// PropagationInitialDelay defines a fixed delay before the first propagation check,
+// for the DNS providers needing a settling period before the records are visible.
+// The following checks are done at the polling interval of the provider.
+// By default, the delay before the first check is the polling interval.
+func PropagationInitialDelay(delay time.Duration) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if delay < 0 {
+			return errors.New("the initial delay of the propagation checks cannot be negative")
+		}
+
+		chlg.initialDelay = delay
+
+		return nil
+	}
+}
+
 type preCheck struct {
 	// checks DNS propagation before notifying ACME that the DNS challenge is ready.
 	checkFunc WrapPreCheckFunc
@@ -68,8 +86,21 @@ type preCheck struct {
 	// require the TXT record to be propagated to all authoritative name servers
 	requireAuthoritativeNssPropagation bool
 
+	// require the TXT record to be propagated to all the addresses of all authoritative name servers
+	requireAllAuthoritativeNssAddresses bool
+
 	// require the TXT record to be propagated to all recursive name servers
 	requireRecursiveNssPropagation bool
+
+	// require the TXT record to be visible from a quorum of remote perspectives (resolvers or DoH endpoints).
+	perspectives       []string
+	perspectivesQuorum int
+
+	// the DNS settings of the challenge.
+	resolver *resolver
+
+	// the structured logger of the challenge (optional).
+	logger log.StructuredLogger
 }
 
 func newPreCheck() preCheck {
@@ -78,6 +109,11 @@ func newPreCheck() preCheck {
 	}
 }
 
+// customized reports whether the pre-check has been customized with options requiring DNS queries.
+func (p preCheck) customized() bool {
+	return p.checkFunc != nil || p.requireRecursiveNssPropagation || p.requireAllAuthoritativeNssAddresses || len(p.perspectives) > 0
+}
+
 func (p preCheck) call(domain, fqdn, value string) (bool, error) {
 	if p.checkFunc == nil {
 		return p.checkDNSPropagation(fqdn, value)
@@ -89,7 +125,7 @@ func (p preCheck) call(domain, fqdn, value string) (bool, error) {
 // checkDNSPropagation checks if the expected TXT record has been propagated to all authoritative nameservers.
 func (p preCheck) checkDNSPropagation(fqdn, value string) (bool, error) {
 	// Initial attempt to resolve at the recursive NS (require to get CNAME)
-	r, err := dnsQuery(fqdn, dns.TypeTXT, recursiveNameservers, true)
+	r, err := p.resolver.query(fqdn, dns.TypeTXT, p.resolver.propagationNSs(), true)
 	if err != nil {
 		return false, fmt.Errorf("initial recursive nameserver: %w", err)
 	}
@@ -99,22 +135,38 @@ func (p preCheck) checkDNSPropagation(fqdn, value string) (bool, error) {
 	}
 
 	if p.requireRecursiveNssPropagation {
-		_, err = checkNameserversPropagation(fqdn, value, recursiveNameservers, false)
+		_, err = p.resolver.checkNameserversPropagation(fqdn, value, p.resolver.propagationNSs(), false)
 		if err != nil {
 			return false, fmt.Errorf("recursive nameservers: %w", err)
 		}
 	}
 
-	if !p.requireAuthoritativeNssPropagation {
+	if len(p.perspectives) > 0 {
+		err = p.resolver.checkPerspectivesPropagation(fqdn, value, p.perspectives, p.perspectivesQuorum)
+		if err != nil {
+			return false, fmt.Errorf("remote perspectives: %w", err)
+		}
+	}
+
+	if !p.requireAuthoritativeNssPropagation && !p.requireAllAuthoritativeNssAddresses {
 		return true, nil
 	}
 
-	authoritativeNss, err := lookupNameservers(fqdn)
+	authoritativeNss, err := p.resolver.lookupNameservers(fqdn)
 	if err != nil {
 		return false, err
 	}
 
-	found, err := checkNameserversPropagation(fqdn, value, authoritativeNss, true)
+	if p.requireAllAuthoritativeNssAddresses {
+		err = p.resolver.checkAllAuthoritativeNameservers(fqdn, value, authoritativeNss, p.logger)
+		if err != nil {
+			return false, fmt.Errorf("authoritative nameservers: %w", err)
+		}
+
+		return true, nil
+	}
+
+	found, err := p.resolver.checkNameserversPropagation(fqdn, value, authoritativeNss, true)
 	if err != nil {
 		return found, fmt.Errorf("authoritative nameservers: %w", err)
 	}
@@ -124,40 +176,58 @@ func (p preCheck) checkDNSPropagation(fqdn, value string) (bool, error) {
 
 // checkNameserversPropagation queries each of the given nameservers for the expected TXT record.
 func checkNameserversPropagation(fqdn, value string, nameservers []string, addPort bool) (bool, error) {
+	return (*resolver)(nil).checkNameserversPropagation(fqdn, value, nameservers, addPort)
+}
+
+// checkNameserversPropagation queries each of the given nameservers for the expected TXT record.
+func (rs *resolver) checkNameserversPropagation(fqdn, value string, nameservers []string, addPort bool) (bool, error) {
 	for _, ns := range nameservers {
 		if addPort {
 			ns = net.JoinHostPort(ns, defaultNameserverPort)
 		}
 
-		r, err := dnsQuery(fqdn, dns.TypeTXT, []string{ns}, false)
+		err := rs.checkNameserverPropagation(fqdn, value, ns)
 		if err != nil {
 			return false, err
 		}
+	}
 
-		if r.Rcode != dns.RcodeSuccess {
-			return false, fmt.Errorf("NS %s returned %s for %s", ns, dns.RcodeToString[r.Rcode], fqdn)
-		}
+	return true, nil
+}
 
-		var records []string
+// checkNameserverPropagation queries a nameserver (host:port) for the expected TXT record.
+func (rs *resolver) checkNameserverPropagation(fqdn, value, ns string) error {
+	r, err := rs.query(fqdn, dns.TypeTXT, []string{ns}, false)
+	if err != nil {
+		return err
+	}
 
-		var found bool
+	if r.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("NS %s returned %s for %s", ns, dns.RcodeToString[r.Rcode], fqdn)
+	}
 
-		for _, rr := range r.Answer {
-			if txt, ok := rr.(*dns.TXT); ok {
-				record := strings.Join(txt.Txt, "")
+	records, found := findTXTRecord(r, value)
+	if !found {
+		return fmt.Errorf("NS %s did not return the expected TXT record [fqdn: %s, value: %s]: %s", ns, fqdn, value, strings.Join(records, " ,"))
+	}
 
-				records = append(records, record)
-				if record == value {
-					found = true
-					break
-				}
-			}
-		}
+	return nil
+}
+
+// findTXTRecord returns the TXT records of the answer until the expected value is found.
+func findTXTRecord(r *dns.Msg, value string) ([]string, bool) {
+	var records []string
+
+	for _, rr := range r.Answer {
+		if txt, ok := rr.(*dns.TXT); ok {
+			record := strings.Join(txt.Txt, "")
 
-		if !found {
-			return false, fmt.Errorf("NS %s did not return the expected TXT record [fqdn: %s, value: %s]: %s", ns, fqdn, value, strings.Join(records, " ,"))
+			records = append(records, record)
+			if record == value {
+				return records, true
+			}
 		}
 	}
 
-	return true, nil
+	return records, false
 }
@@ -0,0 +1,128 @@
+package dns01
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/go-acme/lego/v4/log"
+)
+
+// AllAuthoritativeNSsPropagationRequirement requires the TXT record to be visible on every address
+// of every authoritative nameserver of the zone, instead of the first address of each nameserver.
+// It's useful when the zone is served by several DNS providers (ex: primary and secondary providers)
+// or by a split-horizon setup: the record must be visible on all the servers before the validation by the CA.
+//
+// All the nameservers are checked at each attempt,
+// and the status of each nameserver is written to the structured logger (see AddLogger) at the debug level.
+func AllAuthoritativeNSsPropagationRequirement() ChallengeOption {
+	return func(chlg *Challenge) error {
+		chlg.preCheck.requireAllAuthoritativeNssAddresses = true
+		return nil
+	}
+}
+
+// authoritativeNameserverStatus the result of the check of an address of an authoritative nameserver.
+type authoritativeNameserverStatus struct {
+	nameserver string
+	address    string
+	err        error
+}
+
+// checkAllAuthoritativeNameservers resolves the addresses of the authoritative nameservers,
+// and queries each address for the expected TXT record.
+func (rs *resolver) checkAllAuthoritativeNameservers(fqdn, value string, nameservers []string, logger log.StructuredLogger) error {
+	var statuses []authoritativeNameserverStatus
+
+	for _, ns := range nameservers {
+		addresses, err := rs.lookupNameserverAddresses(ns)
+		if err != nil {
+			statuses = append(statuses, authoritativeNameserverStatus{nameserver: ns, err: fmt.Errorf("NS %s: %w", ns, err)})
+			continue
+		}
+
+		for _, address := range addresses {
+			statuses = append(statuses, authoritativeNameserverStatus{nameserver: ns, address: address})
+		}
+	}
+
+	var wg sync.WaitGroup
+
+	for i := range statuses {
+		if statuses[i].address == "" {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			statuses[i].err = rs.checkNameserverPropagation(fqdn, value, statuses[i].address)
+		}()
+	}
+
+	wg.Wait()
+
+	var errs []error
+
+	for _, status := range statuses {
+		logNameserverStatus(logger, fqdn, status)
+
+		if status.err != nil {
+			errs = append(errs, status.err)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("the TXT record is visible on %d of %d nameserver addresses: %w", len(statuses)-len(errs), len(statuses), errors.Join(errs...))
+}
+
+// lookupNameserverAddresses returns the addresses (host:port) of a nameserver.
+func (rs *resolver) lookupNameserverAddresses(ns string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), rs.queryTimeout())
+	defer cancel()
+
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, strings.TrimSuffix(ns, "."))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ips) == 0 {
+		return nil, errors.New("no address")
+	}
+
+	var addresses []string
+
+	for _, ip := range ips {
+		addresses = append(addresses, net.JoinHostPort(ip.String(), defaultNameserverPort))
+	}
+
+	return addresses, nil
+}
+
+// logNameserverStatus writes the status of an address of an authoritative nameserver.
+func logNameserverStatus(logger log.StructuredLogger, fqdn string, status authoritativeNameserverStatus) {
+	if logger == nil {
+		return
+	}
+
+	args := []any{
+		"fqdn", fqdn,
+		"nameserver", status.nameserver,
+		"address", status.address,
+		"propagated", status.err == nil,
+	}
+
+	if status.err != nil {
+		args = append(args, "error", status.err)
+	}
+
+	logger.Debug("dns01: authoritative nameserver", args...)
+}
@@ -0,0 +1,114 @@
+package dns01
+
+import (
+	"testing"
+
+	"github.com/go-acme/lego/v4/platform/tester/dnsmock"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_checkAllAuthoritativeNameservers(t *testing.T) {
+	fqdn := "_acme-challenge.example.com."
+
+	mockResolver(t,
+		dnsmock.NewServer().
+			Query("ns0.lego.localhost. A",
+				dnsmock.Answer(fakeA("ns0.lego.localhost.", "127.0.0.1"))).
+			Query("ns1.lego.localhost. A",
+				dnsmock.Answer(fakeA("ns1.lego.localhost.", "127.0.0.1"))).
+			Query("ns2.lego.localhost.", dnsmock.Error(dns.RcodeNameError)).
+			Query(fqdn+" TXT",
+				dnsmock.Answer(fakeTXT(fqdn, "expected"))).
+			Build(t),
+	)
+
+	testCases := []struct {
+		desc          string
+		value         string
+		nameservers   []string
+		expectedError string
+		expected      []bool
+	}{
+		{
+			desc:        "all nameservers",
+			value:       "expected",
+			nameservers: []string{"ns0.lego.localhost.", "ns1.lego.localhost."},
+			expected:    []bool{true, true},
+		},
+		{
+			desc:          "unexpected value",
+			value:         "other",
+			nameservers:   []string{"ns0.lego.localhost.", "ns1.lego.localhost."},
+			expectedError: "the TXT record is visible on 0 of 2 nameserver addresses: NS 127.0.0.1:",
+			expected:      []bool{false, false},
+		},
+		{
+			desc:          "unresolvable nameserver",
+			value:         "expected",
+			nameservers:   []string{"ns0.lego.localhost.", "ns2.lego.localhost."},
+			expectedError: "the TXT record is visible on 1 of 2 nameserver addresses: NS ns2.lego.localhost.: ",
+			expected:      []bool{true, false},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			logger := &recordLogger{}
+
+			err := (*resolver)(nil).checkAllAuthoritativeNameservers(fqdn, test.value, test.nameservers, logger)
+
+			if test.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, test.expectedError)
+			}
+
+			require.Len(t, logger.events, len(test.expected))
+
+			for i, event := range logger.events {
+				assert.Equal(t, "debug", event.level)
+				assert.Equal(t, "dns01: authoritative nameserver", event.msg)
+				assert.Equal(t, test.nameservers[i], event.args[3])
+				assert.Equal(t, test.expected[i], event.args[7])
+			}
+		})
+	}
+}
+
+func Test_preCheck_checkDNSPropagation_allAuthoritativeNss(t *testing.T) {
+	mockResolver(t,
+		dnsmock.NewServer().
+			Query("ns0.lego.localhost. A",
+				dnsmock.Answer(fakeA("ns0.lego.localhost.", "127.0.0.1"))).
+			Query("example.com. TXT",
+				dnsmock.Answer(fakeTXT("example.com.", "expected"))).
+			Build(t),
+	)
+
+	useAsNameserver(t,
+		dnsmock.NewServer().
+			Query("example.com. SOA", dnsmock.SOA("")).
+			Query("example.com. NS",
+				dnsmock.Answer(fakeNS("example.com.", "ns0.lego.localhost."))).
+			Query("example.com. TXT",
+				dnsmock.Answer(fakeTXT("example.com.", "expected"))).
+			Build(t),
+	)
+
+	chlg := &Challenge{preCheck: newPreCheck()}
+
+	require.NoError(t, DisableAuthoritativeNssPropagationRequirement()(chlg))
+	require.NoError(t, AllAuthoritativeNSsPropagationRequirement()(chlg))
+
+	assert.True(t, chlg.preCheck.customized())
+
+	ok, err := chlg.preCheck.checkDNSPropagation("example.com.", "expected")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = chlg.preCheck.checkDNSPropagation("example.com.", "other")
+	require.ErrorContains(t, err, "authoritative nameservers: the TXT record is visible on 0 of 1 nameserver addresses")
+	assert.False(t, ok)
+}
@@ -0,0 +1,184 @@
+package dns01
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const dohMediaType = "application/dns-message"
+
+// maxDoHResponseSize the maximum size of a DNS-over-HTTPS response:
+// a DNS message is at most 65535 bytes.
+const maxDoHResponseSize = 64 * 1024
+
+// dohClient the HTTP client used to query the DNS-over-HTTPS endpoints.
+// This is for tests only.
+var dohClient = http.DefaultClient
+
+// RemotePerspectivesPropagationRequirement requires the TXT record to be visible from a quorum of remote resolvers,
+// in addition to the other propagation checks.
+// It mimics the multi-perspective validation of the CAs:
+// a record only visible from the local network must not pass the pre-check.
+//
+// A perspective is either a resolver (host:port) or a DNS-over-HTTPS endpoint (https://...).
+// If the quorum is less than or equal to 0, all the perspectives are required.
+func RemotePerspectivesPropagationRequirement(perspectives []string, quorum int) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if len(perspectives) == 0 {
+			return errors.New("the list of remote perspectives is empty")
+		}
+
+		if quorum > len(perspectives) {
+			return fmt.Errorf("the quorum (%d) is greater than the number of remote perspectives (%d)", quorum, len(perspectives))
+		}
+
+		if quorum <= 0 {
+			quorum = len(perspectives)
+		}
+
+		var ps []string
+
+		for _, perspective := range perspectives {
+			if isDoHEndpoint(perspective) {
+				ps = append(ps, perspective)
+			} else {
+				ps = append(ps, ParseNameservers([]string{perspective})...)
+			}
+		}
+
+		chlg.preCheck.perspectives = ps
+		chlg.preCheck.perspectivesQuorum = quorum
+
+		return nil
+	}
+}
+
+// checkPerspectivesPropagation queries all the perspectives for the expected TXT record,
+// and requires the record to be found by at least quorum perspectives.
+func (rs *resolver) checkPerspectivesPropagation(fqdn, value string, perspectives []string, quorum int) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+
+	for _, perspective := range perspectives {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			err := rs.checkPerspective(fqdn, value, perspective)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	found := len(perspectives) - len(errs)
+	if found >= quorum {
+		return nil
+	}
+
+	return fmt.Errorf("the TXT record has been found by %d remote perspectives, the quorum is %d: %w", found, quorum, errors.Join(errs...))
+}
+
+func (rs *resolver) checkPerspective(fqdn, value, perspective string) error {
+	m := createDNSMsg(fqdn, dns.TypeTXT, true)
+
+	var (
+		r   *dns.Msg
+		err error
+	)
+
+	if isDoHEndpoint(perspective) {
+		r, err = sendDoHQuery(rs.dohHTTPClient(), m, perspective, rs.queryTimeout())
+	} else {
+		r, err = rs.exchange(m, perspective)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if r.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("perspective %s returned %s for %s", perspective, dns.RcodeToString[r.Rcode], fqdn)
+	}
+
+	records, found := findTXTRecord(r, value)
+	if !found {
+		return fmt.Errorf("perspective %s did not return the expected TXT record [fqdn: %s, value: %s]: %s", perspective, fqdn, value, strings.Join(records, " ,"))
+	}
+
+	return nil
+}
+
+// sendDoHQuery sends a DNS query to a DNS-over-HTTPS endpoint (RFC 8484).
+func sendDoHQuery(client *http.Client, m *dns.Msg, endpoint string, timeout time.Duration) (*dns.Msg, error) {
+	// The ID should be 0 to maximize the HTTP cache friendliness.
+	// https://www.rfc-editor.org/rfc/rfc8484.html#section-4.1
+	msg := m.Copy()
+	msg.Id = 0
+
+	raw, err := msg.Pack()
+	if err != nil {
+		return nil, &DNSError{Message: "DoH pack error", MsgIn: m, NS: endpoint, Err: err}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(raw))
+	if err != nil {
+		return nil, &DNSError{Message: "DoH request error", MsgIn: m, NS: endpoint, Err: err}
+	}
+
+	req.Header.Set("Content-Type", dohMediaType)
+	req.Header.Set("Accept", dohMediaType)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, &DNSError{Message: "DoH call error", MsgIn: m, NS: endpoint, Err: err}
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &DNSError{Message: "DoH call error", MsgIn: m, NS: endpoint, Err: fmt.Errorf("unexpected status code %d", resp.StatusCode)}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDoHResponseSize+1))
+	if err != nil {
+		return nil, &DNSError{Message: "DoH read error", MsgIn: m, NS: endpoint, Err: err}
+	}
+
+	if len(body) > maxDoHResponseSize {
+		return nil, &DNSError{Message: "DoH read error", MsgIn: m, NS: endpoint, Err: fmt.Errorf("the response is larger than %d bytes", maxDoHResponseSize)}
+	}
+
+	r := new(dns.Msg)
+
+	err = r.Unpack(body)
+	if err != nil {
+		return nil, &DNSError{Message: "DoH unpack error", MsgIn: m, NS: endpoint, Err: err}
+	}
+
+	return r, nil
+}
+
+func isDoHEndpoint(perspective string) bool {
+	return strings.HasPrefix(perspective, "https://")
+}
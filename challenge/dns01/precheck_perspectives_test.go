@@ -0,0 +1,146 @@
+package dns01
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/platform/tester/dnsmock"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockDoHServer(t *testing.T, value string) string {
+	t.Helper()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost || req.Header.Get("Content-Type") != dohMediaType {
+			http.Error(rw, "invalid request", http.StatusBadRequest)
+			return
+		}
+
+		raw, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		m := new(dns.Msg)
+
+		err = m.Unpack(raw)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		r := new(dns.Msg)
+		r.SetReply(m)
+		r.Answer = append(r.Answer, fakeTXT(m.Question[0].Name, value))
+
+		out, err := r.Pack()
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		rw.Header().Set("Content-Type", dohMediaType)
+		_, _ = rw.Write(out)
+	}))
+
+	t.Cleanup(server.Close)
+
+	originalClient := dohClient
+
+	t.Cleanup(func() {
+		dohClient = originalClient
+	})
+
+	dohClient = server.Client()
+
+	return server.URL + "/dns-query"
+}
+
+func Test_checkPerspectivesPropagation(t *testing.T) {
+	fqdn := "_acme-challenge.example.com."
+
+	good := dnsmock.NewServer().
+		Query(fqdn+" TXT", dnsmock.Answer(fakeTXT(fqdn, "expected"))).
+		Build(t)
+
+	bad := dnsmock.NewServer().
+		Query(fqdn+" TXT", dnsmock.Answer(fakeTXT(fqdn, "other"))).
+		Build(t)
+
+	doh := mockDoHServer(t, "expected")
+
+	testCases := []struct {
+		desc          string
+		perspectives  []string
+		quorum        int
+		expectedError string
+	}{
+		{
+			desc:         "all perspectives",
+			perspectives: []string{good.String(), doh},
+			quorum:       2,
+		},
+		{
+			desc:         "quorum reached",
+			perspectives: []string{good.String(), bad.String(), doh},
+			quorum:       2,
+		},
+		{
+			desc:          "quorum not reached",
+			perspectives:  []string{good.String(), bad.String(), doh},
+			quorum:        3,
+			expectedError: "the TXT record has been found by 2 remote perspectives, the quorum is 3: perspective " + bad.String() + " did not return the expected TXT record",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			err := (*resolver)(nil).checkPerspectivesPropagation(fqdn, "expected", test.perspectives, test.quorum)
+
+			if test.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func Test_sendDoHQuery_tooLarge(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.Header().Set("Content-Type", dohMediaType)
+		_, _ = rw.Write(bytes.Repeat([]byte{0}, 2*maxDoHResponseSize))
+	}))
+
+	t.Cleanup(server.Close)
+
+	m := new(dns.Msg)
+	m.SetQuestion("_acme-challenge.example.com.", dns.TypeTXT)
+
+	_, err := sendDoHQuery(server.Client(), m, server.URL+"/dns-query", time.Second)
+	require.ErrorContains(t, err, "the response is larger than 65536 bytes")
+}
+
+func TestRemotePerspectivesPropagationRequirement(t *testing.T) {
+	chlg := &Challenge{}
+
+	err := RemotePerspectivesPropagationRequirement([]string{"8.8.8.8", "https://dns.example.com/dns-query"}, 0)(chlg)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"8.8.8.8:53", "https://dns.example.com/dns-query"}, chlg.preCheck.perspectives)
+	assert.Equal(t, 2, chlg.preCheck.perspectivesQuorum)
+
+	err = RemotePerspectivesPropagationRequirement([]string{"8.8.8.8"}, 2)(chlg)
+	require.EqualError(t, err, "the quorum (2) is greater than the number of remote perspectives (1)")
+
+	err = RemotePerspectivesPropagationRequirement(nil, 0)(chlg)
+	require.EqualError(t, err, "the list of remote perspectives is empty")
+}
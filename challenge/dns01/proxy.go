@@ -0,0 +1,132 @@
+package dns01
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"golang.org/x/net/proxy"
+)
+
+// dnsProxy is used to route the DNS queries through a SOCKS5 proxy.
+var dnsProxy *url.URL
+
+// ParseDNSProxy parses the URL of a SOCKS5 proxy: `socks5://[user:password@]host:port` (or `socks5h://`).
+// The names of the nameservers are resolved by the proxy.
+func ParseDNSProxy(value string) (*url.URL, error) {
+	proxyURL, err := url.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DNS proxy %q: %w", value, err)
+	}
+
+	switch strings.ToLower(proxyURL.Scheme) {
+	case "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid DNS proxy %q: supported schemes are socks5, socks5h", value)
+	}
+
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid DNS proxy %q: missing host", value)
+	}
+
+	return proxyURL, nil
+}
+
+// AddDNSProxy routes the DNS queries performed by the challenge
+// (zone detection, CNAME resolution, propagation checks, and remote perspectives) through a SOCKS5 proxy.
+// The proxy only applies to this challenge instance (see SetDefaultDNSProxy).
+//
+// The queries are sent over TCP (the SOCKS5 proxies rarely support UDP): the DNS transport is ignored.
+func AddDNSProxy(proxyURL *url.URL) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if proxyURL == nil {
+			return nil
+		}
+
+		if _, err := ParseDNSProxy(proxyURL.String()); err != nil {
+			return err
+		}
+
+		chlg.resolver.proxy = proxyURL
+
+		return nil
+	}
+}
+
+// SetDefaultDNSProxy defines the default SOCKS5 proxy of the DNS queries.
+// It is used by the package functions (ex: FindZoneByFqdn),
+// and by the challenges without a specific proxy (see AddDNSProxy).
+func SetDefaultDNSProxy(proxyURL *url.URL) {
+	storeDefault(&dnsProxy, proxyURL)
+}
+
+// queryProxy returns the SOCKS5 proxy of the DNS queries, or nil.
+func (r *resolver) queryProxy() *url.URL {
+	if r == nil || r.proxy == nil {
+		return loadDefault(&dnsProxy)
+	}
+
+	return r.proxy
+}
+
+// exchange sends a DNS query to a nameserver, through the SOCKS5 proxy if defined.
+func (r *resolver) exchange(m *dns.Msg, ns string) (*dns.Msg, error) {
+	proxyURL := r.queryProxy()
+	if proxyURL == nil {
+		return sendDNSQuery(m, ns, r.queryTimeout(), r.queryTransport())
+	}
+
+	return sendDNSQueryProxy(m, ns, r.queryTimeout(), proxyURL)
+}
+
+// dohHTTPClient returns the HTTP client of the DNS-over-HTTPS queries, through the SOCKS5 proxy if defined.
+func (r *resolver) dohHTTPClient() *http.Client {
+	proxyURL := r.queryProxy()
+	if proxyURL == nil {
+		return dohClient
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(proxyURL)
+
+	return &http.Client{Transport: transport}
+}
+
+// sendDNSQueryProxy sends a DNS query over TCP through a SOCKS5 proxy.
+func sendDNSQueryProxy(m *dns.Msg, ns string, timeout time.Duration, proxyURL *url.URL) (*dns.Msg, error) {
+	dialer, err := proxy.FromURL(proxyURL, &net.Dialer{Timeout: timeout})
+	if err != nil {
+		return nil, &DNSError{Message: "DNS proxy error", MsgIn: m, NS: ns, Err: err}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var conn net.Conn
+
+	if contextDialer, ok := dialer.(proxy.ContextDialer); ok {
+		conn, err = contextDialer.DialContext(ctx, "tcp", ns)
+	} else {
+		conn, err = dialer.Dial("tcp", ns)
+	}
+
+	if err != nil {
+		return nil, &DNSError{Message: "DNS proxy error", MsgIn: m, NS: ns, Err: err}
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	client := &dns.Client{Net: "tcp", Timeout: timeout}
+
+	r, _, err := client.ExchangeWithConn(m, &dns.Conn{Conn: conn})
+	if err != nil {
+		return r, &DNSError{Message: "DNS call error", MsgIn: m, NS: ns, Err: err}
+	}
+
+	return r, nil
+}
@@ -0,0 +1,102 @@
+package dns01
+
+import (
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// PrepareTimeouts computes the timeouts of the records of the challenges of an order solved together:
+// the propagation of each record is waited for the longest timeout of the records (see challenge.ProviderRecordTimeout).
+// It's a no-op if the provider doesn't implement challenge.ProviderRecordTimeout.
+func (c *Challenge) PrepareTimeouts(authzs []acme.Authorization) {
+	if _, ok := c.provider.(challenge.ProviderRecordTimeout); !ok || len(authzs) < 2 {
+		return
+	}
+
+	var (
+		tokens     []string
+		maxTimeout time.Duration
+	)
+
+	for _, authz := range authzs {
+		chlng, err := challenge.FindChallenge(challenge.DNS01, authz)
+		if err != nil {
+			continue
+		}
+
+		keyAuth, err := c.core.GetKeyAuthorization(chlng.Token)
+		if err != nil {
+			continue
+		}
+
+		info := c.resolver.getChallengeInfo(authz.Identifier.Value, keyAuth)
+
+		timeout, _ := c.recordTimeout(info.EffectiveFQDN)
+
+		maxTimeout = max(maxTimeout, timeout)
+
+		tokens = append(tokens, chlng.Token)
+	}
+
+	c.orderTimeoutsMu.Lock()
+	defer c.orderTimeoutsMu.Unlock()
+
+	if c.orderTimeouts == nil {
+		c.orderTimeouts = make(map[string]time.Duration)
+	}
+
+	for _, token := range tokens {
+		c.orderTimeouts[token] = maxTimeout
+	}
+}
+
+// popOrderTimeout returns (and forgets) the timeout of the order of the challenge, if any.
+func (c *Challenge) popOrderTimeout(token string) (time.Duration, bool) {
+	c.orderTimeoutsMu.Lock()
+	defer c.orderTimeoutsMu.Unlock()
+
+	timeout, ok := c.orderTimeouts[token]
+
+	delete(c.orderTimeouts, token)
+
+	return timeout, ok
+}
+
+// recordTimeout returns the propagation timeout and the polling interval of a record.
+func (c *Challenge) recordTimeout(fqdn string) (timeout, interval time.Duration) {
+	switch provider := c.provider.(type) {
+	case challenge.ProviderTimeout:
+		timeout, interval = provider.Timeout()
+	default:
+		timeout, interval = DefaultPropagationTimeout, DefaultPollingInterval
+	}
+
+	provider, ok := c.provider.(challenge.ProviderRecordTimeout)
+	if !ok {
+		return timeout, interval
+	}
+
+	var zone string
+
+	soa, err := c.resolver.lookupSoaByFqdn(fqdn, c.resolver.discoveryNSs())
+	if err != nil {
+		log.Warnf("[%s] acme: could not find the zone of the record: %v", fqdn, err)
+	} else {
+		zone = soa.zone
+	}
+
+	recordTimeout, recordInterval := provider.RecordTimeout(fqdn, zone)
+
+	if recordTimeout > 0 {
+		timeout = recordTimeout
+	}
+
+	if recordInterval > 0 {
+		interval = recordInterval
+	}
+
+	return timeout, interval
+}
@@ -0,0 +1,106 @@
+package dns01
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/go-acme/lego/v4/platform/tester/dnsmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type providerRecordTimeoutMock struct {
+	providerTimeoutMock
+
+	// timeouts by zone.
+	timeouts map[string]time.Duration
+}
+
+func (p *providerRecordTimeoutMock) RecordTimeout(_, zone string) (time.Duration, time.Duration) {
+	return p.timeouts[zone], 0
+}
+
+func useSlaveZoneNameserver(t *testing.T) {
+	t.Helper()
+
+	useAsNameserver(t, dnsmock.NewServer().
+		Query("_acme-challenge.slave.example.com. CNAME", dnsmock.Noop).
+		Query("_acme-challenge.slave.example.com. SOA", dnsmock.Noop).
+		Query("slave.example.com. SOA", dnsmock.SOA("")).
+		Query("_acme-challenge.example.org. CNAME", dnsmock.Noop).
+		Query("_acme-challenge.example.org. SOA", dnsmock.Noop).
+		Query("example.org. SOA", dnsmock.SOA("")).
+		Build(t))
+}
+
+func TestChallenge_recordTimeout(t *testing.T) {
+	useSlaveZoneNameserver(t)
+
+	provider := &providerRecordTimeoutMock{
+		providerTimeoutMock: providerTimeoutMock{timeout: 2 * time.Minute, interval: 5 * time.Second},
+		timeouts:            map[string]time.Duration{"slave.example.com.": 20 * time.Minute},
+	}
+
+	chlg := NewChallenge(nil, nil, provider)
+
+	timeout, interval := chlg.recordTimeout("_acme-challenge.slave.example.com.")
+	assert.Equal(t, 20*time.Minute, timeout)
+	assert.Equal(t, 5*time.Second, interval)
+
+	timeout, interval = chlg.recordTimeout("_acme-challenge.example.org.")
+	assert.Equal(t, 2*time.Minute, timeout)
+	assert.Equal(t, 5*time.Second, interval)
+}
+
+func TestChallenge_recordTimeout_default(t *testing.T) {
+	chlg := NewChallenge(nil, nil, &providerMock{})
+
+	timeout, interval := chlg.recordTimeout("_acme-challenge.example.com.")
+	assert.Equal(t, DefaultPropagationTimeout, timeout)
+	assert.Equal(t, DefaultPollingInterval, interval)
+}
+
+func TestChallenge_PrepareTimeouts(t *testing.T) {
+	useSlaveZoneNameserver(t)
+
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	provider := &providerRecordTimeoutMock{
+		timeouts: map[string]time.Duration{"slave.example.com.": 20 * time.Minute},
+	}
+
+	chlg := NewChallenge(core, nil, provider)
+
+	chlg.PrepareTimeouts([]acme.Authorization{
+		{
+			Identifier: acme.Identifier{Value: "slave.example.com"},
+			Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "a"}},
+		},
+		{
+			Identifier: acme.Identifier{Value: "example.org"},
+			Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: "b"}},
+		},
+	})
+
+	for _, token := range []string{"a", "b"} {
+		timeout, ok := chlg.popOrderTimeout(token)
+		require.True(t, ok)
+
+		assert.Equal(t, 20*time.Minute, timeout)
+	}
+
+	_, ok := chlg.popOrderTimeout("a")
+	assert.False(t, ok)
+}
@@ -0,0 +1,155 @@
+package dns01
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	transformDomain = "{domain}"
+	transformValue  = "{value}"
+)
+
+// maxTXTStringLength the maximum length of a character-string of a TXT record.
+const maxTXTStringLength = 255
+
+var placeholderRegexp = regexp.MustCompile(`\{[^{}]*}`)
+
+// challengeTransform is used to rewrite the challenge records.
+var challengeTransform *ChallengeTransform
+
+// ChallengeTransform rewrites the challenge records,
+// for the managed DNS frontends (e.g. a CDN in front of the zone)
+// requiring the records to be created under a different label, or the values to be wrapped.
+type ChallengeTransform struct {
+	fqdn  string
+	value string
+}
+
+// NewChallengeTransform creates a transformation of the challenge records from templates.
+//   - fqdn: the FQDN of the record, `{domain}` is replaced by the domain (ex: `_acme-challenge.{domain}.edge.example.net.`).
+//     The CNAMEs are followed from the rewritten FQDN.
+//   - value: the value of the record, `{value}` is replaced by the value of the challenge (ex: `v1:{value}`).
+//
+// An empty template keeps the default FQDN (or value).
+func NewChallengeTransform(fqdn, value string) (*ChallengeTransform, error) {
+	t := &ChallengeTransform{fqdn: fqdn, value: value}
+
+	if fqdn != "" {
+		err := checkPlaceholders(fqdn, transformDomain)
+		if err != nil {
+			return nil, fmt.Errorf("invalid FQDN template %q: %w", fqdn, err)
+		}
+
+		// The template is validated with an example domain, the domains of the challenges are checked before the creation of the records.
+		err = t.check("example.com")
+		if err != nil {
+			return nil, fmt.Errorf("invalid FQDN template %q: %w", fqdn, err)
+		}
+	}
+
+	if value != "" {
+		err := checkValueTemplate(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value template %q: %w", value, err)
+		}
+	}
+
+	return t, nil
+}
+
+// AddChallengeTransform rewrites the challenge records checked by the challenge (propagation checks, manifest).
+// The transformation only applies to this challenge instance (see SetDefaultChallengeTransform):
+// the DNS providers use the default transformation.
+func AddChallengeTransform(transform *ChallengeTransform) ChallengeOption {
+	return func(chlg *Challenge) error {
+		chlg.resolver.transform = transform
+
+		return nil
+	}
+}
+
+// SetDefaultChallengeTransform defines the default transformation of the challenge records.
+// It is used by the DNS providers (see GetChallengeInfo),
+// and by the challenges without a specific transformation (see AddChallengeTransform).
+func SetDefaultChallengeTransform(transform *ChallengeTransform) {
+	storeDefault(&challengeTransform, transform)
+}
+
+// challengeTransform returns the transformation of the challenge records, or nil.
+func (r *resolver) challengeTransform() *ChallengeTransform {
+	if r == nil || r.transform == nil {
+		return loadDefault(&challengeTransform)
+	}
+
+	return r.transform
+}
+
+// rewriteFQDN returns the FQDN of the challenge record of the domain.
+func (t *ChallengeTransform) rewriteFQDN(domain string) string {
+	if t == nil || t.fqdn == "" {
+		return fmt.Sprintf("_acme-challenge.%s.", domain)
+	}
+
+	return ToFqdn(strings.ReplaceAll(t.fqdn, transformDomain, domain))
+}
+
+// rewriteValue returns the value of the challenge record.
+func (t *ChallengeTransform) rewriteValue(value string) string {
+	if t == nil || t.value == "" {
+		return value
+	}
+
+	return strings.ReplaceAll(t.value, transformValue, value)
+}
+
+// check validates the FQDN rewritten for the domain.
+func (t *ChallengeTransform) check(domain string) error {
+	if t == nil || t.fqdn == "" {
+		return nil
+	}
+
+	fqdn := t.rewriteFQDN(domain)
+
+	if _, ok := dns.IsDomainName(fqdn); !ok {
+		return fmt.Errorf("the FQDN %q of the domain %s is not a valid domain name", fqdn, domain)
+	}
+
+	return nil
+}
+
+func checkValueTemplate(value string) error {
+	err := checkPlaceholders(value, transformValue)
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(value, transformValue) {
+		return fmt.Errorf("the template must contain %s", transformValue)
+	}
+
+	if strings.ContainsFunc(value, func(r rune) bool { return r == '"' || r == '\\' || r < ' ' || r > '~' }) {
+		return errors.New("only printable ASCII characters without quotes and backslashes are allowed")
+	}
+
+	// The values of the challenges are 43 characters long.
+	if len(strings.ReplaceAll(value, transformValue, strings.Repeat("x", 43))) > maxTXTStringLength {
+		return fmt.Errorf("the value is longer than %d characters", maxTXTStringLength)
+	}
+
+	return nil
+}
+
+func checkPlaceholders(template, allowed string) error {
+	for _, placeholder := range placeholderRegexp.FindAllString(template, -1) {
+		if placeholder != allowed {
+			return fmt.Errorf("unknown placeholder %s (allowed: %s)", placeholder, allowed)
+		}
+	}
+
+	return nil
+}
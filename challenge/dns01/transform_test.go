@@ -0,0 +1,136 @@
+package dns01
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewChallengeTransform(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		fqdn          string
+		value         string
+		expectedFQDN  string
+		expectedValue string
+	}{
+		{
+			desc:          "no transformation",
+			expectedFQDN:  "_acme-challenge.example.com.",
+			expectedValue: "abc",
+		},
+		{
+			desc:          "FQDN",
+			fqdn:          "_acme-challenge.{domain}.edge.example.net",
+			expectedFQDN:  "_acme-challenge.example.com.edge.example.net.",
+			expectedValue: "abc",
+		},
+		{
+			desc:          "value",
+			value:         "v1:{value}",
+			expectedFQDN:  "_acme-challenge.example.com.",
+			expectedValue: "v1:abc",
+		},
+		{
+			desc:          "FQDN and value",
+			fqdn:          "_validation.{domain}.",
+			value:         "token={value};",
+			expectedFQDN:  "_validation.example.com.",
+			expectedValue: "token=abc;",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			transform, err := NewChallengeTransform(test.fqdn, test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedFQDN, transform.rewriteFQDN("example.com"))
+			assert.Equal(t, test.expectedValue, transform.rewriteValue("abc"))
+		})
+	}
+}
+
+func TestNewChallengeTransform_error(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		fqdn     string
+		value    string
+		expected string
+	}{
+		{
+			desc:     "unknown placeholder in the FQDN",
+			fqdn:     "_acme-challenge.{zone}.",
+			expected: `invalid FQDN template "_acme-challenge.{zone}.": unknown placeholder {zone} (allowed: {domain})`,
+		},
+		{
+			desc:     "invalid FQDN",
+			fqdn:     "_acme-challenge..{domain}",
+			expected: `invalid FQDN template "_acme-challenge..{domain}": the FQDN "_acme-challenge..example.com." of the domain example.com is not a valid domain name`,
+		},
+		{
+			desc:     "unknown placeholder in the value",
+			value:    "{domain}:{value}",
+			expected: `invalid value template "{domain}:{value}": unknown placeholder {domain} (allowed: {value})`,
+		},
+		{
+			desc:     "value without the placeholder",
+			value:    "static",
+			expected: `invalid value template "static": the template must contain {value}`,
+		},
+		{
+			desc:     "value with quotes",
+			value:    `"{value}"`,
+			expected: `invalid value template "\"{value}\"": only printable ASCII characters without quotes and backslashes are allowed`,
+		},
+		{
+			desc:     "value too long",
+			value:    strings.Repeat("a", 250) + "{value}",
+			expected: `invalid value template "` + strings.Repeat("a", 250) + `{value}": the value is longer than 255 characters`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewChallengeTransform(test.fqdn, test.value)
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestChallengeTransform_check(t *testing.T) {
+	transform, err := NewChallengeTransform("_acme-challenge.{domain}.edge.example.net", "")
+	require.NoError(t, err)
+
+	require.NoError(t, transform.check("example.com"))
+
+	// The rewritten FQDN exceeds the maximum length of a domain name.
+	err = transform.check(strings.Repeat(strings.Repeat("a", 60)+".", 4) + "com")
+	require.Error(t, err)
+}
+
+func TestGetChallengeInfo_transform(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	transform, err := NewChallengeTransform("_acme-challenge.{domain}.edge.example.net.", "v1:{value}")
+	require.NoError(t, err)
+
+	SetDefaultChallengeTransform(transform)
+	t.Cleanup(func() { SetDefaultChallengeTransform(nil) })
+
+	info := GetChallengeInfo("example.com", "123d==")
+
+	expected := ChallengeInfo{
+		FQDN:          "_acme-challenge.example.com.edge.example.net.",
+		EffectiveFQDN: "_acme-challenge.example.com.edge.example.net.",
+		Value:         "v1:" + challengeValue("123d=="),
+	}
+
+	assert.Equal(t, expected, info)
+}
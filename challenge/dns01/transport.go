@@ -0,0 +1,47 @@
+package dns01
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DNSTransport the transport of the DNS queries (zone detection, CNAME resolution, and propagation checks).
+type DNSTransport string
+
+const (
+	// DNSTransportUDP uses UDP, and retries over TCP when the response is truncated (default).
+	DNSTransportUDP DNSTransport = "udp"
+	// DNSTransportTCP uses only TCP.
+	DNSTransportTCP DNSTransport = "tcp"
+	// DNSTransportAuto uses UDP, and retries over TCP when the response is truncated or when the UDP query fails
+	// (ex: the networks where UDP/53 is blocked).
+	DNSTransportAuto DNSTransport = "auto"
+)
+
+// dnsTransport is used to override the default DNS transport.
+var dnsTransport DNSTransport
+
+// ParseDNSTransport parses a DNS transport: udp, tcp, or auto.
+func ParseDNSTransport(value string) (DNSTransport, error) {
+	switch transport := DNSTransport(strings.ToLower(value)); transport {
+	case DNSTransportUDP, DNSTransportTCP, DNSTransportAuto:
+		return transport, nil
+	default:
+		return "", fmt.Errorf("invalid DNS transport %q: supported values are udp, tcp, auto", value)
+	}
+}
+
+func defaultDNSTransport() DNSTransport {
+	if transport := loadDefault(&dnsTransport); transport != "" {
+		return transport
+	}
+
+	// Kept for compatibility.
+	if ok, _ := strconv.ParseBool(os.Getenv("LEGO_EXPERIMENTAL_DNS_TCP_ONLY")); ok {
+		return DNSTransportTCP
+	}
+
+	return DNSTransportUDP
+}
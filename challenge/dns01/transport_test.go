@@ -0,0 +1,109 @@
+package dns01
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/platform/tester/dnsmock"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDNSTransport(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected DNSTransport
+	}{
+		{value: "udp", expected: DNSTransportUDP},
+		{value: "TCP", expected: DNSTransportTCP},
+		{value: "auto", expected: DNSTransportAuto},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.value, func(t *testing.T) {
+			transport, err := ParseDNSTransport(test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, transport)
+		})
+	}
+}
+
+func TestParseDNSTransport_error(t *testing.T) {
+	_, err := ParseDNSTransport("quic")
+	require.EqualError(t, err, `invalid DNS transport "quic": supported values are udp, tcp, auto`)
+}
+
+func TestAddDNSTransport(t *testing.T) {
+	chlgA := NewChallenge(nil, nil, nil, AddDNSTransport(DNSTransportTCP))
+	chlgB := NewChallenge(nil, nil, nil)
+
+	assert.Equal(t, DNSTransportTCP, chlgA.resolver.queryTransport())
+	assert.Equal(t, DNSTransportUDP, chlgB.resolver.queryTransport())
+}
+
+func TestAddDNSTransport_invalid(t *testing.T) {
+	chlg := &Challenge{resolver: &resolver{}}
+
+	err := AddDNSTransport("quic")(chlg)
+	require.Error(t, err)
+}
+
+func Test_defaultDNSTransport(t *testing.T) {
+	t.Setenv("LEGO_EXPERIMENTAL_DNS_TCP_ONLY", "true")
+
+	assert.Equal(t, DNSTransportTCP, defaultDNSTransport())
+
+	originalTransport := dnsTransport
+
+	t.Cleanup(func() {
+		dnsTransport = originalTransport
+	})
+
+	SetDefaultDNSTransport(DNSTransportAuto)
+
+	assert.Equal(t, DNSTransportAuto, defaultDNSTransport())
+}
+
+func Test_sendDNSQuery_transport(t *testing.T) {
+	// The server only answers over TCP (ex: a network where UDP/53 is blocked).
+	addr := dnsmock.NewServer().
+		Query("example.com. TXT", dnsmock.Answer(fakeTXT("example.com.", "foo"))).
+		Build(t, dnsmock.TCP())
+
+	testCases := []struct {
+		desc      string
+		transport DNSTransport
+		assertErr require.ErrorAssertionFunc
+	}{
+		{
+			desc:      "udp",
+			transport: DNSTransportUDP,
+			assertErr: require.Error,
+		},
+		{
+			desc:      "tcp",
+			transport: DNSTransportTCP,
+			assertErr: require.NoError,
+		},
+		{
+			desc:      "auto",
+			transport: DNSTransportAuto,
+			assertErr: require.NoError,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			m := createDNSMsg("example.com.", dns.TypeTXT, true)
+
+			r, err := sendDNSQuery(m, addr.String(), 500*time.Millisecond, test.transport)
+			test.assertErr(t, err)
+
+			if err == nil {
+				require.Len(t, r.Answer, 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package dns01
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/miekg/dns"
+)
+
+// zoneLocks serializes the calls to the DNS providers (Present, CleanUp) touching the same zone,
+// across all the challenges of the process (ex: concurrent orders of a wildcard and its subdomains).
+// The providers commonly read, modify, and write the records of a zone: concurrent calls would overwrite each other.
+var zoneLocks = newKeyedMutex()
+
+// keyedMutex a registry of mutexes by key.
+// The mutex of a key is removed when it's not used anymore.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*refCountedMutex
+}
+
+type refCountedMutex struct {
+	sync.Mutex
+
+	refs int
+}
+
+func newKeyedMutex() *keyedMutex {
+	return &keyedMutex{locks: make(map[string]*refCountedMutex)}
+}
+
+// lock locks the mutexes of the keys, and returns the function unlocking them.
+// The keys are locked in order: the calls locking several keys cannot deadlock.
+func (k *keyedMutex) lock(keys ...string) func() {
+	keys = slices.Compact(slices.Sorted(slices.Values(keys)))
+
+	var mutexes []*refCountedMutex
+
+	for _, key := range keys {
+		k.mu.Lock()
+
+		m, ok := k.locks[key]
+		if !ok {
+			m = &refCountedMutex{}
+			k.locks[key] = m
+		}
+
+		m.refs++
+
+		k.mu.Unlock()
+
+		m.Lock()
+
+		mutexes = append(mutexes, m)
+	}
+
+	return func() {
+		for i := len(mutexes) - 1; i >= 0; i-- {
+			mutexes[i].Unlock()
+
+			k.mu.Lock()
+
+			mutexes[i].refs--
+			if mutexes[i].refs == 0 {
+				delete(k.locks, keys[i])
+			}
+
+			k.mu.Unlock()
+		}
+	}
+}
+
+// lockZones locks the zones of the records of the domains, and returns the function unlocking them.
+func (c *Challenge) lockZones(items ...zoneLockItem) func() {
+	var keys []string
+
+	for _, item := range items {
+		keys = append(keys, c.resolver.zoneLockKey(item.domain, item.keyAuth))
+	}
+
+	return zoneLocks.lock(keys...)
+}
+
+// zoneLockItem a record identified by its domain and its key authorization.
+type zoneLockItem struct {
+	domain  string
+	keyAuth string
+}
+
+// zoneLockKey returns the key of the lock of the zone of a record.
+// The zones are cached: the DNS providers looking for the zone of the record reuse the result.
+func (rs *resolver) zoneLockKey(domain, keyAuth string) string {
+	fqdn := rs.getChallengeInfo(domain, keyAuth).EffectiveFQDN
+
+	soa, err := rs.lookupSoaByFqdn(fqdn, rs.discoveryNSs())
+	if err != nil {
+		// The zone is unknown: only the calls on the same record are serialized.
+		return strings.ToLower(dns.Fqdn(fqdn))
+	}
+
+	return strings.ToLower(dns.Fqdn(soa.zone))
+}
@@ -0,0 +1,155 @@
+package dns01
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/go-acme/lego/v4/platform/tester/dnsmock"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// providerConcurrencyMock records the maximum number of concurrent calls.
+type providerConcurrencyMock struct {
+	active atomic.Int32
+	max    atomic.Int32
+}
+
+func (p *providerConcurrencyMock) Present(_, _, _ string) error { return p.call() }
+func (p *providerConcurrencyMock) CleanUp(_, _, _ string) error { return p.call() }
+
+func (p *providerConcurrencyMock) call() error {
+	active := p.active.Add(1)
+	defer p.active.Add(-1)
+
+	for {
+		current := p.max.Load()
+		if active <= current || p.max.CompareAndSwap(current, active) {
+			break
+		}
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	return nil
+}
+
+func Test_keyedMutex(t *testing.T) {
+	locks := newKeyedMutex()
+
+	unlock := locks.lock("example.com.")
+
+	acquired := make(chan struct{})
+
+	go func() {
+		unlockB := locks.lock("example.com.", "example.org.")
+		close(acquired)
+		unlockB()
+	}()
+
+	// Another key is not blocked.
+	locks.lock("example.net.")()
+
+	select {
+	case <-acquired:
+		t.Fatal("the lock of the same key has been acquired twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("the lock has not been released")
+	}
+
+	// The mutexes are removed when they are not used anymore.
+	require.Eventually(t, func() bool {
+		locks.mu.Lock()
+		defer locks.mu.Unlock()
+
+		return len(locks.locks) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func Test_keyedMutex_severalKeys(t *testing.T) {
+	locks := newKeyedMutex()
+
+	var wg sync.WaitGroup
+
+	for i := range 20 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			// The keys are locked in order, whatever the order of the arguments.
+			if i%2 == 0 {
+				locks.lock("a.", "b.", "a.")()
+			} else {
+				locks.lock("b.", "a.")()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Empty(t, locks.locks)
+}
+
+func TestChallenge_PreSolve_zoneSerialization(t *testing.T) {
+	useAsNameserver(t,
+		dnsmock.NewServer().
+			Query("_acme-challenge.a.example.com.", dnsmock.Error(dns.RcodeNameError)).
+			Query("a.example.com.", dnsmock.Error(dns.RcodeNameError)).
+			Query("_acme-challenge.b.example.com.", dnsmock.Error(dns.RcodeNameError)).
+			Query("b.example.com.", dnsmock.Error(dns.RcodeNameError)).
+			Query("example.com. SOA", dnsmock.SOA("")).
+			Build(t),
+	)
+
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	provider := &providerConcurrencyMock{}
+
+	var wg sync.WaitGroup
+
+	for i, domain := range []string{"a.example.com", "b.example.com", "example.com", "a.example.com"} {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			// Each order has its own challenge.
+			chlg := NewChallenge(core, nil, provider)
+
+			authz := acme.Authorization{
+				Identifier: acme.Identifier{Value: domain},
+				Challenges: []acme.Challenge{{Type: challenge.DNS01.String(), Token: string(rune('a' + i))}},
+			}
+
+			assert.NoError(t, chlg.PreSolve(authz))
+			assert.NoError(t, chlg.CleanUp(authz))
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), provider.max.Load())
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/netip"
+	"net/textproto"
 	"strings"
 )
 
@@ -47,6 +48,20 @@ type domainMatcher interface {
 	name() string
 }
 
+// newDomainMatcher returns the domainMatcher inspecting the given header.
+// The Host header (or an empty name) uses the Host of the request, the Forwarded header is parsed (RFC 7239),
+// and any other header is matched as is.
+func newDomainMatcher(headerName string) domainMatcher {
+	switch h := textproto.CanonicalMIMEHeaderKey(headerName); h {
+	case "", "Host":
+		return &hostMatcher{}
+	case "Forwarded":
+		return &forwardedMatcher{}
+	default:
+		return arbitraryMatcher(h)
+	}
+}
+
 // hostMatcher checks whether (*net/http).Request.Host starts with a domain name.
 type hostMatcher struct{}
 
@@ -195,10 +210,53 @@ func isWS(r rune) bool {
 }
 
 func matchDomain(src, domain string) bool {
-	addr, err := netip.ParseAddr(domain)
+	addr, err := netip.ParseAddr(trimBrackets(domain))
 	if err == nil && addr.Is6() {
-		domain = "[" + domain + "]"
+		host, ok := parseIPv6Host(src)
+
+		return ok && host == addr
 	}
 
 	return strings.HasPrefix(src, domain)
 }
+
+// parseIPv6Host parses the IPv6 literal of a header value (ex: "[2001:db8::1]:8080"),
+// only the first value of a list is parsed.
+// The zone ID can be percent-encoded, as in the URIs (ex: "[fe80::1%25eth0]", RFC 6874).
+func parseIPv6Host(src string) (netip.Addr, bool) {
+	src, _, _ = strings.Cut(src, ",")
+	src = strings.TrimSpace(src)
+
+	if !strings.HasPrefix(src, "[") {
+		return netip.Addr{}, false
+	}
+
+	end := strings.Index(src, "]")
+	if end < 0 {
+		return netip.Addr{}, false
+	}
+
+	if port, ok := strings.CutPrefix(src[end+1:], ":"); ok {
+		if port == "" || strings.TrimLeft(port, "0123456789") != "" {
+			return netip.Addr{}, false
+		}
+	} else if src[end+1:] != "" {
+		return netip.Addr{}, false
+	}
+
+	addr, err := netip.ParseAddr(strings.Replace(src[1:end], "%25", "%", 1))
+	if err != nil || !addr.Is6() {
+		return netip.Addr{}, false
+	}
+
+	return addr, true
+}
+
+// trimBrackets removes the brackets of an IPv6 literal (ex: "[2001:db8::1]").
+func trimBrackets(host string) string {
+	if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		return host[1 : len(host)-1]
+	}
+
+	return host
+}
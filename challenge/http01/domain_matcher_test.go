@@ -125,6 +125,36 @@ func Test_hostMatcher_matches(t *testing.T) {
 			req:      httptest.NewRequest(http.MethodGet, "http://[2001:db8::1]", nil),
 			expected: assert.True,
 		},
+		{
+			desc:     "ipv6 with port",
+			domain:   "2001:db8::1",
+			req:      httptest.NewRequest(http.MethodGet, "http://[2001:db8::1]:5002", nil),
+			expected: assert.True,
+		},
+		{
+			desc:     "ipv6 not compressed",
+			domain:   "2001:db8::1",
+			req:      httptest.NewRequest(http.MethodGet, "http://[2001:0db8:0:0:0:0:0:1]", nil),
+			expected: assert.True,
+		},
+		{
+			desc:     "ipv6 with zone",
+			domain:   "fe80::1%eth0",
+			req:      httptest.NewRequest(http.MethodGet, "http://[fe80::1%25eth0]:5002", nil),
+			expected: assert.True,
+		},
+		{
+			desc:     "ipv6 zone mismatch",
+			domain:   "fe80::1%eth0",
+			req:      httptest.NewRequest(http.MethodGet, "http://[fe80::1%25eth1]:5002", nil),
+			expected: assert.False,
+		},
+		{
+			desc:     "ipv6 prefix",
+			domain:   "2001:db8::1",
+			req:      httptest.NewRequest(http.MethodGet, "http://[2001:db8::10]", nil),
+			expected: assert.False,
+		},
 	}
 
 	for _, test := range testCases {
@@ -136,3 +166,38 @@ func Test_hostMatcher_matches(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseIPv6Host(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		src      string
+		expected string
+	}{
+		{desc: "literal", src: "[2001:db8::1]", expected: "2001:db8::1"},
+		{desc: "with port", src: "[2001:db8::1]:8080", expected: "2001:db8::1"},
+		{desc: "encoded zone", src: "[fe80::1%25eth0]:8080", expected: "fe80::1%eth0"},
+		{desc: "zone", src: "[fe80::1%eth0]", expected: "fe80::1%eth0"},
+		{desc: "list", src: "[2001:db8::1]:8080, example.com", expected: "2001:db8::1"},
+		{desc: "without brackets", src: "2001:db8::1"},
+		{desc: "ipv4", src: "[127.0.0.1]"},
+		{desc: "empty port", src: "[2001:db8::1]:"},
+		{desc: "invalid port", src: "[2001:db8::1]:http"},
+		{desc: "trailing characters", src: "[2001:db8::1].example.com"},
+		{desc: "unterminated", src: "[2001:db8::1"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			addr, ok := parseIPv6Host(test.src)
+			if test.expected == "" {
+				assert.False(t, ok)
+				return
+			}
+
+			require.True(t, ok)
+			assert.Equal(t, test.expected, addr.String())
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package http01
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -8,6 +9,8 @@ import (
 	"github.com/go-acme/lego/v4/acme/api"
 	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/errcode"
+	"github.com/go-acme/lego/v4/platform/redact"
 )
 
 type ValidateFunc func(core *api.Core, domain string, chlng acme.Challenge) error
@@ -55,7 +58,19 @@ func (c *Challenge) SetProvider(provider challenge.Provider) {
 	c.provider = provider
 }
 
+// MaxConcurrency returns the maximum number of challenges presented concurrently with the provider
+// (see challenge.ProviderConcurrency), 0 means no limit.
+func (c *Challenge) MaxConcurrency() int {
+	return challenge.MaxConcurrency(c.provider)
+}
+
 func (c *Challenge) Solve(authz acme.Authorization) error {
+	return c.SolveContext(context.Background(), authz)
+}
+
+// SolveContext is like Solve, the context cancels the presentation of the challenge
+// if the provider implements challenge.ProviderContext.
+func (c *Challenge) SolveContext(ctx context.Context, authz acme.Authorization) error {
 	domain := challenge.GetTargetedDomain(authz)
 	log.Infof("[%s] acme: Trying to solve HTTP-01", domain)
 
@@ -70,13 +85,14 @@ func (c *Challenge) Solve(authz acme.Authorization) error {
 		return err
 	}
 
-	err = c.provider.Present(authz.Identifier.Value, chlng.Token, keyAuth)
+	err = challenge.Present(ctx, c.provider, authz.Identifier.Value, chlng.Token, keyAuth)
 	if err != nil {
-		return fmt.Errorf("[%s] acme: error presenting token: %w", domain, err)
+		return redact.Error(errcode.Wrap(errcode.ChallengePresent, fmt.Errorf("[%s] acme: error presenting token: %w", domain, err)))
 	}
 
 	defer func() {
-		err := c.provider.CleanUp(authz.Identifier.Value, chlng.Token, keyAuth)
+		// The challenge is cleaned up even if the context is canceled.
+		err := challenge.CleanUp(context.WithoutCancel(ctx), c.provider, authz.Identifier.Value, chlng.Token, keyAuth)
 		if err != nil {
 			log.Warnf("[%s] acme: cleaning up failed: %v", domain, err)
 		}
@@ -0,0 +1,122 @@
+package http01
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-acme/lego/v4/log"
+)
+
+// HandlerProvider implements ChallengeProvider for `http-01` challenge.
+// It doesn't start its own server:
+// the challenges are served by an http.Handler mounted into an existing HTTP server.
+//
+//	provider := http01.NewHandlerProvider()
+//	mux.Handle(http01.ChallengePath(""), provider)
+//
+// The HTTP server must be reachable on port 80 (directly or through a proxy) for the validation to succeed.
+type HandlerProvider struct {
+	matcher domainMatcher
+
+	mu         sync.RWMutex
+	challenges map[string]handlerChallenge
+}
+
+type handlerChallenge struct {
+	domain  string
+	keyAuth string
+}
+
+// NewHandlerProvider creates a new HandlerProvider.
+func NewHandlerProvider() *HandlerProvider {
+	return &HandlerProvider{
+		matcher:    &hostMatcher{},
+		challenges: make(map[string]handlerChallenge),
+	}
+}
+
+// Present makes the token available at `ChallengePath(token)` for web requests.
+func (p *HandlerProvider) Present(domain, token, keyAuth string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.challenges[token] = handlerChallenge{domain: domain, keyAuth: keyAuth}
+
+	return nil
+}
+
+// CleanUp removes the token from `ChallengePath(token)`.
+func (p *HandlerProvider) CleanUp(domain, token, keyAuth string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.challenges, token)
+
+	return nil
+}
+
+// SetProxyHeader changes the validation of incoming requests.
+// It behaves like ProviderServer.SetProxyHeader.
+// It must be called before serving any request.
+func (p *HandlerProvider) SetProxyHeader(headerName string) {
+	p.matcher = newDomainMatcher(headerName)
+}
+
+// ServeHTTP serves the key authorizations of the pending challenges.
+// Any other request gets a 404 response.
+func (p *HandlerProvider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !p.serve(w, r) {
+		http.NotFound(w, r)
+	}
+}
+
+// Middleware returns a handler serving the key authorizations of the pending challenges,
+// and delegating all the other requests to next.
+func (p *HandlerProvider) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !p.serve(w, r) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// serve writes the key authorization if the request matches a pending challenge.
+// The incoming request is validated to prevent DNS rebind attacks:
+// only GET requests with the "Host" header matching the domain are answered
+// (the latter is configurable though SetProxyHeader).
+func (p *HandlerProvider) serve(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodGet {
+		return false
+	}
+
+	token, ok := strings.CutPrefix(r.URL.Path, ChallengePath(""))
+	if !ok || token == "" {
+		return false
+	}
+
+	p.mu.RLock()
+	chlg, ok := p.challenges[token]
+	p.mu.RUnlock()
+
+	if !ok {
+		return false
+	}
+
+	if !p.matcher.matches(r, chlg.domain) {
+		log.Warnf("Received request for domain %s with method %s but the domain did not match any challenge. Please ensure you are passing the %s header properly.", r.Host, r.Method, p.matcher.name())
+		return false
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+
+	_, err := w.Write([]byte(chlg.keyAuth))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return true
+	}
+
+	log.Infof("[%s] Served key authentication", chlg.domain)
+
+	return true
+}
@@ -0,0 +1,171 @@
+package http01
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerProvider_ServeHTTP(t *testing.T) {
+	provider := NewHandlerProvider()
+
+	err := provider.Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc         string
+		method       string
+		host         string
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			desc:         "matching challenge",
+			method:       http.MethodGet,
+			host:         "example.com",
+			path:         ChallengePath("token"),
+			expectedCode: http.StatusOK,
+			expectedBody: "keyAuth",
+		},
+		{
+			desc:         "unknown token",
+			method:       http.MethodGet,
+			host:         "example.com",
+			path:         ChallengePath("other"),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			desc:         "empty token",
+			method:       http.MethodGet,
+			host:         "example.com",
+			path:         ChallengePath(""),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			desc:         "host mismatch",
+			method:       http.MethodGet,
+			host:         "example.org",
+			path:         ChallengePath("token"),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			desc:         "method mismatch",
+			method:       http.MethodPost,
+			host:         "example.com",
+			path:         ChallengePath("token"),
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, "http://"+test.host+test.path, http.NoBody)
+			rec := httptest.NewRecorder()
+
+			provider.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.expectedCode, rec.Code)
+
+			if test.expectedBody != "" {
+				assert.Equal(t, test.expectedBody, rec.Body.String())
+				assert.Equal(t, "text/plain", rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestHandlerProvider_CleanUp(t *testing.T) {
+	provider := NewHandlerProvider()
+
+	err := provider.Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	err = provider.CleanUp("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com"+ChallengePath("token"), http.NoBody)
+	rec := httptest.NewRecorder()
+
+	provider.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandlerProvider_SetProxyHeader(t *testing.T) {
+	provider := NewHandlerProvider()
+	provider.SetProxyHeader("X-Forwarded-Host")
+
+	err := provider.Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost"+ChallengePath("token"), http.NoBody)
+	req.Header.Set("X-Forwarded-Host", "example.com")
+
+	rec := httptest.NewRecorder()
+
+	provider.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "keyAuth", rec.Body.String())
+}
+
+func TestHandlerProvider_Middleware(t *testing.T) {
+	provider := NewHandlerProvider()
+
+	err := provider.Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("next"))
+	})
+
+	server := httptest.NewServer(provider.Middleware(next))
+	t.Cleanup(server.Close)
+
+	testCases := []struct {
+		desc     string
+		path     string
+		expected string
+	}{
+		{
+			desc:     "challenge",
+			path:     ChallengePath("token"),
+			expected: "keyAuth",
+		},
+		{
+			desc:     "unknown token",
+			path:     ChallengePath("other"),
+			expected: "next",
+		},
+		{
+			desc:     "other path",
+			path:     "/index.html",
+			expected: "next",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, server.URL+test.path, http.NoBody)
+			require.NoError(t, err)
+
+			req.Host = "example.com"
+
+			resp, err := server.Client().Do(req)
+			require.NoError(t, err)
+
+			defer func() { _ = resp.Body.Close() }()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, test.expected, string(body))
+		})
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"io/fs"
 	"net"
 	"net/http"
-	"net/textproto"
 	"os"
 	"strings"
 
@@ -29,12 +28,13 @@ type ProviderServer struct {
 // NewProviderServer creates a new ProviderServer on the selected interface and port.
 // Setting iface and / or port to an empty string will make the server fall back to
 // the "any" interface and port 80 respectively.
+// An IPv6 interface can be written with or without brackets (ex: "::1", "[fe80::1%eth0]").
 func NewProviderServer(iface, port string) *ProviderServer {
 	if port == "" {
 		port = "80"
 	}
 
-	return &ProviderServer{network: "tcp", address: net.JoinHostPort(iface, port), matcher: &hostMatcher{}}
+	return &ProviderServer{network: "tcp", address: net.JoinHostPort(trimBrackets(iface), port), matcher: &hostMatcher{}}
 }
 
 func NewUnixProviderServer(socketPath string, mode fs.FileMode) *ProviderServer {
@@ -67,6 +67,11 @@ func (s *ProviderServer) GetAddress() string {
 	return s.address
 }
 
+// MaxConcurrency the server listens on a single address: the challenges are presented one by one.
+func (s *ProviderServer) MaxConcurrency() int {
+	return 1
+}
+
 // CleanUp closes the HTTP server and removes the token from `ChallengePath(token)`.
 func (s *ProviderServer) CleanUp(domain, token, keyAuth string) error {
 	if s.listener == nil {
@@ -93,14 +98,7 @@ func (s *ProviderServer) CleanUp(domain, token, keyAuth string) error {
 // - "Forwarded" will look for a Forwarded header, and inspect it according to https://www.rfc-editor.org/rfc/rfc7239.html
 // - any other value will check the header value with the same name.
 func (s *ProviderServer) SetProxyHeader(headerName string) {
-	switch h := textproto.CanonicalMIMEHeaderKey(headerName); h {
-	case "", "Host":
-		s.matcher = &hostMatcher{}
-	case "Forwarded":
-		s.matcher = &forwardedMatcher{}
-	default:
-		s.matcher = arbitraryMatcher(h)
-	}
+	s.matcher = newDomainMatcher(headerName)
 }
 
 func (s *ProviderServer) serve(domain, token, keyAuth string) {
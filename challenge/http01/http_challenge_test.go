@@ -49,6 +49,21 @@ func TestProviderServer_GetAddress(t *testing.T) {
 			server:   NewProviderServer("localhost", "8080"),
 			expected: "localhost:8080",
 		},
+		{
+			desc:     "TCP with IPv6 host and port",
+			server:   NewProviderServer("::1", "8080"),
+			expected: "[::1]:8080",
+		},
+		{
+			desc:     "TCP with bracketed IPv6 host",
+			server:   NewProviderServer("[::1]", "8080"),
+			expected: "[::1]:8080",
+		},
+		{
+			desc:     "TCP with IPv6 zone",
+			server:   NewProviderServer("[fe80::1%eth0]", ""),
+			expected: "[fe80::1%eth0]:80",
+		},
 		{
 			desc:     "UDS socket",
 			server:   NewUnixProviderServer(sock, fs.ModeSocket|0o666),
@@ -119,6 +134,61 @@ func TestChallenge(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestChallengeIPv6(t *testing.T) {
+	listener, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skip("IPv6 is not available:", err)
+	}
+
+	_ = listener.Close()
+
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	providerServer := NewProviderServer("[::1]", "23458")
+
+	validate := func(_ *api.Core, _ string, chlng acme.Challenge) error {
+		uri := "http://" + providerServer.GetAddress() + ChallengePath(chlng.Token)
+
+		resp, err := http.DefaultClient.Get(uri)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		if string(body) != chlng.KeyAuthorization {
+			return fmt.Errorf("Get(%q) Body: got %q, want %q", uri, string(body), chlng.KeyAuthorization)
+		}
+
+		return nil
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	solver := NewChallenge(core, validate, providerServer)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{
+			Type:  "ip",
+			Value: "::1",
+		},
+		Challenges: []acme.Challenge{
+			{Type: challenge.HTTP01.String(), Token: "http1"},
+		},
+	}
+
+	err = solver.Solve(authz)
+	require.NoError(t, err)
+}
+
 func TestChallengeUnix(t *testing.T) {
 	if runtime.GOOS != "linux" {
 		t.Skip("only for UNIX systems")
@@ -0,0 +1,66 @@
+package challenge
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+
+	jose "github.com/go-jose/go-jose/v4"
+)
+
+// Thumbprint returns the JWK thumbprint (SHA-256, base64url without padding) of the account key.
+// https://www.rfc-editor.org/rfc/rfc7638.html
+//
+// The key can be the private key of the account (ex: registration.User.GetPrivateKey()) or its public key.
+func Thumbprint(key crypto.PublicKey) (string, error) {
+	publicKey, err := toPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	jwk := &jose.JSONWebKey{Key: publicKey}
+
+	thumbBytes, err := jwk.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return "", fmt.Errorf("thumbprint: %w", err)
+	}
+
+	// unpad the base64URL
+	return base64.RawURLEncoding.EncodeToString(thumbBytes), nil
+}
+
+// KeyAuthorization returns the key authorization of a challenge token for the account key.
+// https://www.rfc-editor.org/rfc/rfc8555.html#section-8.1
+//
+// It's the value passed as `keyAuth` to the [Provider]:
+// it allows to feed an external system (ex: a CDN API) with the expected challenge values.
+// The key can be the private key of the account (ex: registration.User.GetPrivateKey()) or its public key.
+func KeyAuthorization(key crypto.PublicKey, token string) (string, error) {
+	thumbprint, err := Thumbprint(key)
+	if err != nil {
+		return "", err
+	}
+
+	return token + "." + thumbprint, nil
+}
+
+func toPublicKey(key crypto.PublicKey) (crypto.PublicKey, error) {
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		return k.Public(), nil
+	case *rsa.PrivateKey:
+		return k.Public(), nil
+	case ed25519.PrivateKey:
+		return k.Public(), nil
+	case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		return k, nil
+	case crypto.Signer:
+		// The private key is not available (ex: a key stored inside a KMS or Vault).
+		return k.Public(), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type: %T", key)
+	}
+}
@@ -0,0 +1,78 @@
+package challenge
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	jose "github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfc7638Key is the key of the example of the RFC 7638 (section 3.1).
+const rfc7638Key = `{
+  "kty": "RSA",
+  "n": "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+  "e": "AQAB"
+}`
+
+func TestThumbprint(t *testing.T) {
+	jwk := &jose.JSONWebKey{}
+
+	err := jwk.UnmarshalJSON([]byte(rfc7638Key))
+	require.NoError(t, err)
+
+	thumbprint, err := Thumbprint(jwk.Key)
+	require.NoError(t, err)
+
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", thumbprint)
+}
+
+func TestThumbprint_privateKey(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	fromPrivate, err := Thumbprint(privateKey)
+	require.NoError(t, err)
+
+	fromPublic, err := Thumbprint(privateKey.Public())
+	require.NoError(t, err)
+
+	assert.Equal(t, fromPublic, fromPrivate)
+}
+
+func TestThumbprint_signer(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	// Hides the concrete type of the key (ex: a key stored inside Vault).
+	signer := struct{ crypto.Signer }{privateKey}
+
+	fromSigner, err := Thumbprint(signer)
+	require.NoError(t, err)
+
+	fromPublic, err := Thumbprint(privateKey.Public())
+	require.NoError(t, err)
+
+	assert.Equal(t, fromPublic, fromSigner)
+}
+
+func TestThumbprint_unsupported(t *testing.T) {
+	_, err := Thumbprint("foo")
+	require.EqualError(t, err, "unsupported key type: string")
+}
+
+func TestKeyAuthorization(t *testing.T) {
+	jwk := &jose.JSONWebKey{}
+
+	err := jwk.UnmarshalJSON([]byte(rfc7638Key))
+	require.NoError(t, err)
+
+	keyAuth, err := KeyAuthorization(jwk.Key, "token")
+	require.NoError(t, err)
+
+	assert.Equal(t, "token.NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", keyAuth)
+}
@@ -0,0 +1,161 @@
+package onioncsr01
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"slices"
+
+	"golang.org/x/crypto/cryptobyte"
+	cryptobyte_asn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// The CSR attributes of the onion-csr-01 challenge.
+// https://cabforum.org/working-groups/server/baseline-requirements/ (Appendix B)
+var (
+	oidCASigningNonce        = asn1.ObjectIdentifier{2, 23, 140, 41}
+	oidApplicantSigningNonce = asn1.ObjectIdentifier{2, 23, 140, 42}
+)
+
+var (
+	oidExtensionRequest        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 14}
+	oidExtensionSubjectAltName = asn1.ObjectIdentifier{2, 5, 29, 17}
+	oidSignatureEd25519        = asn1.ObjectIdentifier{1, 3, 101, 112}
+)
+
+// minNonceSize the minimal size (64 bits) of the nonces.
+const minNonceSize = 8
+
+// applicantNonceSize the size (128 bits) of the nonce generated by lego.
+const applicantNonceSize = 16
+
+// CreateCSR creates the CSR of the onion-csr-01 challenge:
+// the CSR is signed by the key of the hidden service and contains the nonce of the CA and a nonce of the applicant.
+// https://www.rfc-editor.org/rfc/rfc9799.html#section-3.2
+func CreateCSR(key crypto.Signer, domain string, caNonce, applicantNonce []byte) ([]byte, error) {
+	publicKey, ok := key.Public().(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type: %T (the key of a hidden service is an Ed25519 key)", key.Public())
+	}
+
+	if len(caNonce) < minNonceSize || len(applicantNonce) < minNonceSize {
+		return nil, errors.New("the nonces must contain at least 64 bits of entropy")
+	}
+
+	spki, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	san, err := marshalSAN(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := [][]byte{
+		marshalAttribute(oidCASigningNonce, func(b *cryptobyte.Builder) {
+			b.AddASN1OctetString(caNonce)
+		}),
+		marshalAttribute(oidApplicantSigningNonce, func(b *cryptobyte.Builder) {
+			b.AddASN1OctetString(applicantNonce)
+		}),
+		marshalAttribute(oidExtensionRequest, func(b *cryptobyte.Builder) {
+			// Extensions ::= SEQUENCE OF Extension
+			b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+				b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+					b.AddASN1ObjectIdentifier(oidExtensionSubjectAltName)
+					b.AddASN1OctetString(san)
+				})
+			})
+		}),
+	}
+
+	// DER: the elements of a SET OF are sorted.
+	slices.SortFunc(attributes, bytes.Compare)
+
+	info := cryptobyte.NewBuilder(nil)
+
+	// CertificationRequestInfo
+	info.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1Int64(0)
+
+		// Empty subject: the domain is in the subjectAltName extension.
+		b.AddASN1(cryptobyte_asn1.SEQUENCE, func(*cryptobyte.Builder) {})
+
+		b.AddBytes(spki)
+
+		// attributes [0] IMPLICIT SET OF Attribute
+		b.AddASN1(cryptobyte_asn1.Tag(0).Constructed().ContextSpecific(), func(b *cryptobyte.Builder) {
+			for _, attribute := range attributes {
+				b.AddBytes(attribute)
+			}
+		})
+	})
+
+	rawInfo, err := info.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := key.Sign(rand.Reader, rawInfo, crypto.Hash(0))
+	if err != nil {
+		return nil, fmt.Errorf("sign CSR: %w", err)
+	}
+
+	csr := cryptobyte.NewBuilder(nil)
+
+	// CertificationRequest
+	csr.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddBytes(rawInfo)
+
+		b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1ObjectIdentifier(oidSignatureEd25519)
+		})
+
+		b.AddASN1BitString(signature)
+	})
+
+	return csr.Bytes()
+}
+
+// Attribute ::= SEQUENCE { type OBJECT IDENTIFIER, values SET OF AttributeValue }.
+func marshalAttribute(oid asn1.ObjectIdentifier, value cryptobyte.BuilderContinuation) []byte {
+	b := cryptobyte.NewBuilder(nil)
+
+	b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1ObjectIdentifier(oid)
+		b.AddASN1(cryptobyte_asn1.SET, value)
+	})
+
+	return b.BytesOrPanic()
+}
+
+// SubjectAltName ::= GeneralNames (dNSName [2] IA5String).
+func marshalSAN(domain string) ([]byte, error) {
+	b := cryptobyte.NewBuilder(nil)
+
+	b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1(cryptobyte_asn1.Tag(2).ContextSpecific(), func(b *cryptobyte.Builder) {
+			b.AddBytes([]byte(domain))
+		})
+	})
+
+	return b.Bytes()
+}
+
+// newApplicantNonce generates the nonce of the applicant.
+func newApplicantNonce() ([]byte, error) {
+	nonce := make([]byte, applicantNonceSize)
+
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return nonce, nil
+}
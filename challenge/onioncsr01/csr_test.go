@@ -0,0 +1,90 @@
+package onioncsr01
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCSR(t *testing.T) {
+	_, secretKeyFileContent := generateHiddenServiceKey(t)
+
+	key, err := ParseSecretKey(secretKeyFileContent)
+	require.NoError(t, err)
+
+	caNonce := []byte("ca-nonce-value")
+	applicantNonce := []byte("applicant-nonce")
+
+	raw, err := CreateCSR(key, "*.example.onion", caNonce, applicantNonce)
+	require.NoError(t, err)
+
+	csr, err := x509.ParseCertificateRequest(raw)
+	require.NoError(t, err)
+
+	require.NoError(t, csr.CheckSignature())
+
+	assert.Equal(t, x509.Ed25519, csr.PublicKeyAlgorithm)
+	assert.Equal(t, key.Public(), csr.PublicKey)
+	assert.Equal(t, []string{"*.example.onion"}, csr.DNSNames)
+
+	assert.Equal(t, caNonce, findAttribute(t, csr, oidCASigningNonce))
+	assert.Equal(t, applicantNonce, findAttribute(t, csr, oidApplicantSigningNonce))
+}
+
+func TestCreateCSR_errors(t *testing.T) {
+	_, secretKeyFileContent := generateHiddenServiceKey(t)
+
+	key, err := ParseSecretKey(secretKeyFileContent)
+	require.NoError(t, err)
+
+	_, err = CreateCSR(key, "example.onion", []byte("short"), []byte("applicant-nonce"))
+	require.EqualError(t, err, "the nonces must contain at least 64 bits of entropy")
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	_, err = CreateCSR(ecKey, "example.onion", []byte("ca-nonce-value"), []byte("applicant-nonce"))
+	require.EqualError(t, err, "unsupported key type: *ecdsa.PublicKey (the key of a hidden service is an Ed25519 key)")
+}
+
+func findAttribute(t *testing.T, csr *x509.CertificateRequest, oid asn1.ObjectIdentifier) []byte {
+	t.Helper()
+
+	var request struct {
+		Info struct {
+			Version       int
+			Subject       asn1.RawValue
+			PublicKey     asn1.RawValue
+			RawAttributes []asn1.RawValue `asn1:"tag:0"`
+		}
+	}
+
+	_, err := asn1.Unmarshal(csr.Raw, &request)
+	require.NoError(t, err)
+
+	for _, rawAttribute := range request.Info.RawAttributes {
+		var attribute struct {
+			Type   asn1.ObjectIdentifier
+			Values [][]byte `asn1:"set"`
+		}
+
+		_, err = asn1.Unmarshal(rawAttribute.FullBytes, &attribute)
+		if err != nil || !attribute.Type.Equal(oid) {
+			continue
+		}
+
+		require.Len(t, attribute.Values, 1)
+
+		return attribute.Values[0]
+	}
+
+	t.Fatalf("attribute %s not found", oid)
+
+	return nil
+}
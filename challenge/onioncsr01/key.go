@@ -0,0 +1,131 @@
+package onioncsr01
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/edwards25519"
+)
+
+// The files of a hidden service directory (tor `HiddenServiceDir`).
+const (
+	hostnameFile  = "hostname"
+	secretKeyFile = "hs_ed25519_secret_key"
+)
+
+const secretKeyHeader = "== ed25519v1-secret: type0 ==\x00\x00\x00"
+
+// ParseSecretKey parses the content of the `hs_ed25519_secret_key` file of a hidden service.
+//
+// The file contains an expanded Ed25519 private key (the seed is not available):
+// the returned crypto.Signer implements the signature with the expanded key.
+func ParseSecretKey(data []byte) (crypto.Signer, error) {
+	raw, ok := bytes.CutPrefix(data, []byte(secretKeyHeader))
+	if !ok {
+		return nil, errors.New("invalid hidden service secret key: unknown header")
+	}
+
+	if len(raw) != 64 {
+		return nil, fmt.Errorf("invalid hidden service secret key: invalid length %d", len(raw))
+	}
+
+	scalar, err := edwards25519.NewScalar().SetBytesWithClamping(raw[:32])
+	if err != nil {
+		return nil, fmt.Errorf("invalid hidden service secret key: %w", err)
+	}
+
+	key := &expandedKey{
+		scalar: scalar,
+		prefix: bytes.Clone(raw[32:]),
+	}
+
+	key.publicKey = ed25519.PublicKey(new(edwards25519.Point).ScalarBaseMult(scalar).Bytes())
+
+	return key, nil
+}
+
+// LoadHiddenServiceDir loads the .onion address and the key of a hidden service directory (tor `HiddenServiceDir`).
+func LoadHiddenServiceDir(dir string) (string, crypto.Signer, error) {
+	hostname, err := os.ReadFile(filepath.Join(dir, hostnameFile))
+	if err != nil {
+		return "", nil, fmt.Errorf("read hostname: %w", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, secretKeyFile))
+	if err != nil {
+		return "", nil, fmt.Errorf("read secret key: %w", err)
+	}
+
+	key, err := ParseSecretKey(data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	address := strings.TrimSpace(string(hostname))
+
+	if OnionAddress(key.Public().(ed25519.PublicKey)) != address {
+		return "", nil, fmt.Errorf("the secret key doesn't match the address %s", address)
+	}
+
+	return address, key, nil
+}
+
+// expandedKey an Ed25519 private key in the expanded form (scalar and prefix).
+// https://www.rfc-editor.org/rfc/rfc8032.html#section-5.1.6
+type expandedKey struct {
+	scalar    *edwards25519.Scalar
+	prefix    []byte
+	publicKey ed25519.PublicKey
+}
+
+func (k *expandedKey) Public() crypto.PublicKey {
+	return k.publicKey
+}
+
+// Sign signs the message with the expanded key (PureEdDSA): opts.HashFunc() must be zero.
+func (k *expandedKey) Sign(_ io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts.HashFunc() != crypto.Hash(0) {
+		return nil, errors.New("ed25519: cannot sign hashed message")
+	}
+
+	// r = SHA512(prefix || M)
+	h := sha512.New()
+	_, _ = h.Write(k.prefix)
+	_, _ = h.Write(message)
+
+	r, err := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	// R = rB
+	encodedR := new(edwards25519.Point).ScalarBaseMult(r).Bytes()
+
+	// k = SHA512(R || A || M)
+	h.Reset()
+	_, _ = h.Write(encodedR)
+	_, _ = h.Write(k.publicKey)
+	_, _ = h.Write(message)
+
+	hram, err := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	// S = k * a + r
+	s := edwards25519.NewScalar().MultiplyAdd(hram, k.scalar, r)
+
+	signature := make([]byte, 0, ed25519.SignatureSize)
+	signature = append(signature, encodedR...)
+	signature = append(signature, s.Bytes()...)
+
+	return signature, nil
+}
@@ -0,0 +1,90 @@
+package onioncsr01
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSecretKey(t *testing.T) {
+	privateKey, secretKeyFileContent := generateHiddenServiceKey(t)
+
+	key, err := ParseSecretKey(secretKeyFileContent)
+	require.NoError(t, err)
+
+	assert.Equal(t, privateKey.Public(), key.Public())
+
+	message := []byte("message")
+
+	signature, err := key.Sign(rand.Reader, message, crypto.Hash(0))
+	require.NoError(t, err)
+
+	// Ed25519 is deterministic: the signatures must be identical.
+	assert.Equal(t, ed25519.Sign(privateKey, message), signature)
+	assert.True(t, ed25519.Verify(privateKey.Public().(ed25519.PublicKey), message, signature))
+}
+
+func TestParseSecretKey_errors(t *testing.T) {
+	_, err := ParseSecretKey([]byte("== ed25519v1-public: type0 ==\x00\x00\x00"))
+	require.EqualError(t, err, "invalid hidden service secret key: unknown header")
+
+	_, err = ParseSecretKey([]byte(secretKeyHeader + "short"))
+	require.EqualError(t, err, "invalid hidden service secret key: invalid length 5")
+}
+
+func TestLoadHiddenServiceDir(t *testing.T) {
+	privateKey, secretKeyFileContent := generateHiddenServiceKey(t)
+
+	dir := t.TempDir()
+
+	address := OnionAddress(privateKey.Public().(ed25519.PublicKey))
+
+	err := os.WriteFile(filepath.Join(dir, hostnameFile), []byte(address+"\n"), 0o600)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(dir, secretKeyFile), secretKeyFileContent, 0o600)
+	require.NoError(t, err)
+
+	hostname, key, err := LoadHiddenServiceDir(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, address, hostname)
+	assert.Equal(t, privateKey.Public(), key.Public())
+}
+
+func TestLoadHiddenServiceDir_mismatch(t *testing.T) {
+	_, secretKeyFileContent := generateHiddenServiceKey(t)
+
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, hostnameFile), []byte("duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion\n"), 0o600)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(dir, secretKeyFile), secretKeyFileContent, 0o600)
+	require.NoError(t, err)
+
+	_, _, err = LoadHiddenServiceDir(dir)
+	require.EqualError(t, err, "the secret key doesn't match the address duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion")
+}
+
+// generateHiddenServiceKey generates a key, and the content of the `hs_ed25519_secret_key` file (expanded key).
+func generateHiddenServiceKey(t *testing.T) (ed25519.PrivateKey, []byte) {
+	t.Helper()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	expanded := sha512.Sum512(privateKey.Seed())
+	expanded[0] &= 248
+	expanded[31] &= 127
+	expanded[31] |= 64
+
+	return privateKey, append([]byte(secretKeyHeader), expanded[:]...)
+}
@@ -0,0 +1,65 @@
+package onioncsr01
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"fmt"
+)
+
+var _ KeyProvider = (Keys)(nil)
+
+// Keys a KeyProvider based on the keys of the hidden services (onion address -> key).
+type Keys map[string]crypto.Signer
+
+// NewKeys creates a Keys from the keys of hidden services: the onion addresses are computed from the public keys.
+func NewKeys(keys ...crypto.Signer) (Keys, error) {
+	k := Keys{}
+
+	for _, key := range keys {
+		publicKey, ok := key.Public().(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("unsupported key type: %T (the key of a hidden service is an Ed25519 key)", key.Public())
+		}
+
+		k[OnionAddress(publicKey)] = key
+	}
+
+	return k, nil
+}
+
+// NewKeysFromDirs creates a Keys from hidden service directories (tor `HiddenServiceDir`).
+func NewKeysFromDirs(dirs ...string) (Keys, error) {
+	k := Keys{}
+
+	for _, dir := range dirs {
+		address, key, err := LoadHiddenServiceDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("hidden service %s: %w", dir, err)
+		}
+
+		k[address] = key
+	}
+
+	return k, nil
+}
+
+// GetKey returns the key of the hidden service of a .onion domain (subdomains included).
+func (k Keys) GetKey(domain string) (crypto.Signer, error) {
+	publicKey, err := PublicKeyFromAddress(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	address := OnionAddress(publicKey)
+
+	key, ok := k[address]
+	if !ok {
+		return nil, fmt.Errorf("no key for the hidden service %s", address)
+	}
+
+	if !publicKey.Equal(key.Public()) {
+		return nil, fmt.Errorf("the key doesn't match the hidden service %s", address)
+	}
+
+	return key, nil
+}
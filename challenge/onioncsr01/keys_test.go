@@ -0,0 +1,31 @@
+package onioncsr01
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeys_GetKey(t *testing.T) {
+	privateKey, _ := generateHiddenServiceKey(t)
+
+	keys, err := NewKeys(privateKey)
+	require.NoError(t, err)
+
+	address := OnionAddress(privateKey.Public().(ed25519.PublicKey))
+
+	key, err := keys.GetKey(address)
+	require.NoError(t, err)
+
+	assert.Equal(t, privateKey.Public(), key.Public())
+
+	key, err = keys.GetKey("www." + address)
+	require.NoError(t, err)
+
+	assert.Equal(t, privateKey.Public(), key.Public())
+
+	_, err = keys.GetKey("duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion")
+	require.EqualError(t, err, "no key for the hidden service duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion")
+}
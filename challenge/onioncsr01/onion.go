@@ -0,0 +1,73 @@
+package onioncsr01
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha3"
+	"encoding/base32"
+	"fmt"
+	"strings"
+)
+
+const onionSuffix = ".onion"
+
+// onionVersion the version of the onion services (v3).
+const onionVersion = 0x03
+
+var onionEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// IsOnion returns true if the domain is a .onion domain.
+func IsOnion(domain string) bool {
+	return strings.HasSuffix(strings.ToLower(strings.TrimSuffix(domain, ".")), onionSuffix)
+}
+
+// OnionAddress returns the .onion address (v3) of a hidden service public key.
+// https://spec.torproject.org/rend-spec/encoding-onion-addresses.html
+func OnionAddress(publicKey ed25519.PublicKey) string {
+	checksum := onionChecksum(publicKey)
+
+	raw := bytes.Join([][]byte{publicKey, checksum, {onionVersion}}, nil)
+
+	return strings.ToLower(onionEncoding.EncodeToString(raw)) + onionSuffix
+}
+
+// PublicKeyFromAddress returns the hidden service public key of a .onion address (v3).
+// The subdomains are ignored (ex: www.<address>.onion).
+func PublicKeyFromAddress(domain string) (ed25519.PublicKey, error) {
+	if !IsOnion(domain) {
+		return nil, fmt.Errorf("not an onion domain: %s", domain)
+	}
+
+	labels := strings.Split(strings.TrimSuffix(strings.ToLower(strings.TrimSuffix(domain, ".")), onionSuffix), ".")
+
+	raw, err := onionEncoding.DecodeString(strings.ToUpper(labels[len(labels)-1]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid onion address %s: %w", domain, err)
+	}
+
+	if len(raw) != ed25519.PublicKeySize+3 {
+		return nil, fmt.Errorf("invalid onion address %s: only the v3 addresses are supported", domain)
+	}
+
+	publicKey := ed25519.PublicKey(raw[:ed25519.PublicKeySize])
+
+	if raw[len(raw)-1] != onionVersion {
+		return nil, fmt.Errorf("invalid onion address %s: unsupported version %d", domain, raw[len(raw)-1])
+	}
+
+	if !bytes.Equal(raw[ed25519.PublicKeySize:ed25519.PublicKeySize+2], onionChecksum(publicKey)) {
+		return nil, fmt.Errorf("invalid onion address %s: invalid checksum", domain)
+	}
+
+	return publicKey, nil
+}
+
+// onionChecksum CHECKSUM = H(".onion checksum" | PUBKEY | VERSION)[:2].
+func onionChecksum(publicKey ed25519.PublicKey) []byte {
+	h := sha3.New256()
+	_, _ = h.Write([]byte(".onion checksum"))
+	_, _ = h.Write(publicKey)
+	_, _ = h.Write([]byte{onionVersion})
+
+	return h.Sum(nil)[:2]
+}
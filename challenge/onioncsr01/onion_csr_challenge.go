@@ -0,0 +1,103 @@
+package onioncsr01
+
+import (
+	"crypto"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/errcode"
+)
+
+type ValidateFunc func(core *api.Core, domain string, chlng acme.Challenge, payload any) error
+
+// KeyProvider provides the keys of the hidden services.
+type KeyProvider interface {
+	// GetKey returns the key of the hidden service of a .onion domain.
+	GetKey(domain string) (crypto.Signer, error)
+}
+
+type ChallengeOption func(*Challenge) error
+
+// Response the response to the onion-csr-01 challenge.
+// https://www.rfc-editor.org/rfc/rfc9799.html#section-3.2
+type Response struct {
+	// CSR base64url-encoded DER.
+	CSR string `json:"csr"`
+}
+
+type Challenge struct {
+	core     *api.Core
+	validate ValidateFunc
+	keys     KeyProvider
+}
+
+func NewChallenge(core *api.Core, validate ValidateFunc, keys KeyProvider, opts ...ChallengeOption) *Challenge {
+	chlg := &Challenge{
+		core:     core,
+		validate: validate,
+		keys:     keys,
+	}
+
+	for _, opt := range opts {
+		err := opt(chlg)
+		if err != nil {
+			log.Infof("challenge option error: %v", err)
+		}
+	}
+
+	return chlg
+}
+
+// Solve signs the CSR of the challenge with the key of the hidden service, and sends it to the CA.
+func (c *Challenge) Solve(authz acme.Authorization) error {
+	domain := authz.Identifier.Value
+	log.Infof("[%s] acme: Trying to solve ONION-CSR-01", challenge.GetTargetedDomain(authz))
+
+	chlng, err := challenge.FindChallenge(challenge.OnionCSR01, authz)
+	if err != nil {
+		return err
+	}
+
+	key, err := c.keys.GetKey(domain)
+	if err != nil {
+		return errcode.Wrap(errcode.ChallengePresent, fmt.Errorf("[%s] acme: %w", challenge.GetTargetedDomain(authz), err))
+	}
+
+	caNonce, err := decodeNonce(chlng.Nonce)
+	if err != nil {
+		return fmt.Errorf("[%s] acme: invalid CA nonce: %w", challenge.GetTargetedDomain(authz), err)
+	}
+
+	applicantNonce, err := newApplicantNonce()
+	if err != nil {
+		return fmt.Errorf("[%s] acme: applicant nonce: %w", challenge.GetTargetedDomain(authz), err)
+	}
+
+	csr, err := CreateCSR(key, challenge.GetTargetedDomain(authz), caNonce, applicantNonce)
+	if err != nil {
+		return fmt.Errorf("[%s] acme: %w", challenge.GetTargetedDomain(authz), err)
+	}
+
+	return c.validate(c.core, domain, chlng, Response{CSR: base64.RawURLEncoding.EncodeToString(csr)})
+}
+
+// decodeNonce decodes the nonce of the CA: the RFC defines a base64 encoding with padding,
+// but some CAs use the base64url encoding without padding (like the tokens).
+func decodeNonce(nonce string) ([]byte, error) {
+	if nonce == "" {
+		return nil, errors.New("missing nonce")
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(nonce)
+	if err == nil {
+		return raw, nil
+	}
+
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(nonce, "="))
+}
@@ -0,0 +1,115 @@
+package onioncsr01
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChallenge(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	privateKey, _ := generateHiddenServiceKey(t)
+
+	domain := OnionAddress(privateKey.Public().(ed25519.PublicKey))
+
+	caNonce := []byte("ca-nonce-value")
+
+	mockValidate := func(_ *api.Core, _ string, _ acme.Challenge, payload any) error {
+		response, ok := payload.(Response)
+		require.True(t, ok)
+
+		raw, err := base64.RawURLEncoding.DecodeString(response.CSR)
+		require.NoError(t, err)
+
+		csr, err := x509.ParseCertificateRequest(raw)
+		require.NoError(t, err)
+
+		require.NoError(t, csr.CheckSignature())
+
+		assert.Equal(t, []string{"*." + domain}, csr.DNSNames)
+		assert.Equal(t, caNonce, findAttribute(t, csr, oidCASigningNonce))
+		assert.Len(t, findAttribute(t, csr, oidApplicantSigningNonce), applicantNonceSize)
+
+		return nil
+	}
+
+	accountKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", accountKey)
+	require.NoError(t, err)
+
+	keys, err := NewKeys(privateKey)
+	require.NoError(t, err)
+
+	solver := NewChallenge(core, mockValidate, keys)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Type: "dns", Value: domain},
+		Wildcard:   true,
+		Challenges: []acme.Challenge{
+			{Type: "onion-csr-01", Nonce: base64.StdEncoding.EncodeToString(caNonce)},
+		},
+	}
+
+	err = solver.Solve(authz)
+	require.NoError(t, err)
+}
+
+func TestChallenge_unknownHiddenService(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	accountKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", accountKey)
+	require.NoError(t, err)
+
+	mockValidate := func(_ *api.Core, _ string, _ acme.Challenge, _ any) error {
+		return nil
+	}
+
+	solver := NewChallenge(core, mockValidate, Keys{})
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Type: "dns", Value: "duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion"},
+		Challenges: []acme.Challenge{
+			{Type: "onion-csr-01", Nonce: "Y2Etbm9uY2UtdmFsdWU="},
+		},
+	}
+
+	err = solver.Solve(authz)
+	require.EqualError(t, err, "[duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion] acme: no key for the hidden service duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion")
+}
+
+func Test_decodeNonce(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		nonce string
+	}{
+		{desc: "base64 with padding", nonce: "Y2Etbm9uY2UtdmFsdWU="},
+		{desc: "base64url without padding", nonce: "Y2Etbm9uY2UtdmFsdWU"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			raw, err := decodeNonce(test.nonce)
+			require.NoError(t, err)
+
+			assert.Equal(t, []byte("ca-nonce-value"), raw)
+		})
+	}
+
+	_, err := decodeNonce("")
+	require.EqualError(t, err, "missing nonce")
+}
@@ -0,0 +1,74 @@
+package onioncsr01
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnionAddress(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	address := OnionAddress(publicKey)
+
+	assert.Len(t, address, 56+len(onionSuffix))
+
+	decoded, err := PublicKeyFromAddress(address)
+	require.NoError(t, err)
+
+	assert.Equal(t, publicKey, decoded)
+}
+
+func TestPublicKeyFromAddress(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		domain   string
+		expected string
+	}{
+		{
+			desc:   "address",
+			domain: "duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion",
+		},
+		{
+			desc:   "subdomain",
+			domain: "www.duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion",
+		},
+		{
+			desc:   "uppercase and trailing dot",
+			domain: "DUCKDUCKGOGG42XJOC72X3SJASOWOARFBGCMVFIMAFTT6TWAGSWZCZAD.ONION.",
+		},
+		{
+			desc:     "not an onion domain",
+			domain:   "example.com",
+			expected: "not an onion domain: example.com",
+		},
+		{
+			desc:     "v2 address",
+			domain:   "3g2upl4pq6kufc4m.onion",
+			expected: "invalid onion address 3g2upl4pq6kufc4m.onion: only the v3 addresses are supported",
+		},
+		{
+			desc:     "invalid checksum",
+			domain:   "duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzdzad.onion",
+			expected: "invalid onion address duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzdzad.onion: invalid checksum",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			publicKey, err := PublicKeyFromAddress(test.domain)
+			if test.expected != "" {
+				require.EqualError(t, err, test.expected)
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, "duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion", OnionAddress(publicKey))
+		})
+	}
+}
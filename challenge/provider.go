@@ -1,6 +1,9 @@
 package challenge
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // Provider enables implementing a custom challenge
 // provider. Present presents the solution to a challenge available to
@@ -11,6 +14,36 @@ type Provider interface {
 	CleanUp(domain, token, keyAuth string) error
 }
 
+// ProviderContext allows for implementing a Provider able to cancel
+// its long-running operations (e.g. the API calls of a DNS provider) when the context is canceled.
+// If a Provider implements ProviderContext,
+// PresentContext (and CleanUpContext) are called instead of Present (and CleanUp).
+type ProviderContext interface {
+	Provider
+	PresentContext(ctx context.Context, domain, token, keyAuth string) error
+	CleanUpContext(ctx context.Context, domain, token, keyAuth string) error
+}
+
+// Present presents a challenge with the provider.
+// The context is only used if the provider implements ProviderContext.
+func Present(ctx context.Context, provider Provider, domain, token, keyAuth string) error {
+	if p, ok := provider.(ProviderContext); ok {
+		return p.PresentContext(ctx, domain, token, keyAuth)
+	}
+
+	return provider.Present(domain, token, keyAuth)
+}
+
+// CleanUp cleans up a challenge with the provider.
+// The context is only used if the provider implements ProviderContext.
+func CleanUp(ctx context.Context, provider Provider, domain, token, keyAuth string) error {
+	if p, ok := provider.(ProviderContext); ok {
+		return p.CleanUpContext(ctx, domain, token, keyAuth)
+	}
+
+	return provider.CleanUp(domain, token, keyAuth)
+}
+
 // ProviderTimeout allows for implementing a
 // Provider where an unusually long timeout is required when
 // waiting for an ACME challenge to be satisfied, such as when
@@ -26,3 +59,88 @@ type ProviderTimeout interface {
 	Provider
 	Timeout() (timeout, interval time.Duration)
 }
+
+// ProviderRecordTimeout allows for implementing a Provider
+// where the timeout depends on the record,
+// such as a longer timeout for the zones served by secondary nameservers with a slow zone transfer (AXFR).
+// RecordTimeout is called with the FQDN of the record (after the CNAMEs resolutions)
+// and its zone (empty if the zone cannot be found).
+// A zero value falls back to the value of the Timeout method (see ProviderTimeout), or to the default value.
+//
+// When the challenges of an order are solved together,
+// the propagation of each record is waited for the longest timeout of the records of the order.
+type ProviderRecordTimeout interface {
+	Provider
+	RecordTimeout(fqdn, zone string) (timeout, interval time.Duration)
+}
+
+// ProviderBatch allows for implementing a Provider able to present
+// (and to clean up) several challenges in a single call,
+// such as the DNS providers with an API to change several records at once.
+// If a Provider implements ProviderBatch,
+// the challenges of an order solved together are presented (and cleaned up)
+// with a single call to PresentBatch (and CleanUpBatch)
+// instead of one call to Present (and CleanUp) per challenge.
+type ProviderBatch interface {
+	Provider
+	PresentBatch(items []BatchItem) error
+	CleanUpBatch(items []BatchItem) error
+}
+
+// ProviderBatchContext allows for implementing a ProviderBatch able to cancel
+// its long-running operations when the context is canceled (see ProviderContext).
+// If a ProviderBatch implements ProviderBatchContext,
+// PresentBatchContext (and CleanUpBatchContext) are called instead of PresentBatch (and CleanUpBatch).
+type ProviderBatchContext interface {
+	ProviderBatch
+	PresentBatchContext(ctx context.Context, items []BatchItem) error
+	CleanUpBatchContext(ctx context.Context, items []BatchItem) error
+}
+
+// PresentBatch presents several challenges with the provider in a single call.
+// The context is only used if the provider implements ProviderBatchContext.
+func PresentBatch(ctx context.Context, provider ProviderBatch, items []BatchItem) error {
+	if p, ok := provider.(ProviderBatchContext); ok {
+		return p.PresentBatchContext(ctx, items)
+	}
+
+	return provider.PresentBatch(items)
+}
+
+// CleanUpBatch cleans up several challenges with the provider in a single call.
+// The context is only used if the provider implements ProviderBatchContext.
+func CleanUpBatch(ctx context.Context, provider ProviderBatch, items []BatchItem) error {
+	if p, ok := provider.(ProviderBatchContext); ok {
+		return p.CleanUpBatchContext(ctx, items)
+	}
+
+	return provider.CleanUpBatch(items)
+}
+
+// BatchItem contains the parameters of a challenge presented (or cleaned up) by a ProviderBatch.
+type BatchItem struct {
+	Domain  string
+	Token   string
+	KeyAuth string
+}
+
+// ProviderConcurrency allows for implementing a Provider unable to present
+// (and to clean up) an unlimited number of challenges concurrently,
+// such as the DNS providers with an API unable to handle parallel writes,
+// or the servers listening on a single port.
+// The resolver doesn't present more than MaxConcurrency challenges concurrently with the provider.
+// A value lower than 1 means no limit.
+type ProviderConcurrency interface {
+	Provider
+	MaxConcurrency() int
+}
+
+// MaxConcurrency returns the maximum number of challenges presented concurrently with the provider.
+// 0 means no limit.
+func MaxConcurrency(provider Provider) int {
+	if p, ok := provider.(ProviderConcurrency); ok {
+		return max(p.MaxConcurrency(), 0)
+	}
+
+	return 0
+}
@@ -0,0 +1,64 @@
+package challenge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type providerMock struct {
+	calls []string
+}
+
+func (p *providerMock) Present(_, _, _ string) error {
+	p.calls = append(p.calls, "Present")
+	return nil
+}
+
+func (p *providerMock) CleanUp(_, _, _ string) error {
+	p.calls = append(p.calls, "CleanUp")
+	return nil
+}
+
+type providerContextMock struct {
+	providerMock
+}
+
+func (p *providerContextMock) PresentContext(ctx context.Context, _, _, _ string) error {
+	p.calls = append(p.calls, "PresentContext")
+	return ctx.Err()
+}
+
+func (p *providerContextMock) CleanUpContext(ctx context.Context, _, _, _ string) error {
+	p.calls = append(p.calls, "CleanUpContext")
+	return ctx.Err()
+}
+
+func TestPresent(t *testing.T) {
+	provider := &providerMock{}
+
+	err := Present(context.Background(), provider, "example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	err = CleanUp(context.Background(), provider, "example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Present", "CleanUp"}, provider.calls)
+}
+
+func TestPresent_context(t *testing.T) {
+	provider := &providerContextMock{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := Present(ctx, provider, "example.com", "token", "keyAuth")
+	require.ErrorIs(t, err, context.Canceled)
+
+	err = CleanUp(context.Background(), provider, "example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"PresentContext", "CleanUpContext"}, provider.calls)
+}
@@ -2,8 +2,14 @@ package resolver
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"sort"
+	"sync"
+
+	"github.com/go-acme/lego/v4/log"
 )
 
 // obtainError is returned when there are specific errors available per domain.
@@ -25,3 +31,81 @@ func (e obtainError) Error() string {
 
 	return buffer.String()
 }
+
+// Unwrap returns the errors of the domains (sorted by domain).
+func (e obtainError) Unwrap() []error {
+	domains := slices.Sorted(maps.Keys(e))
+
+	errs := make([]error, 0, len(domains))
+	for _, domain := range domains {
+		errs = append(errs, e[domain])
+	}
+
+	return errs
+}
+
+// FailedDomains returns the domains whose challenges failed (sorted),
+// or nil if the error doesn't contain the errors of the challenges (ex: the order creation failed).
+func FailedDomains(err error) []string {
+	var failures obtainError
+	if !errors.As(err, &failures) {
+		return nil
+	}
+
+	return slices.Sorted(maps.Keys(failures))
+}
+
+// cleanUpError is returned when the cleanups of some challenges failed:
+// the records (or the other resources) of these challenges may remain.
+type cleanUpError map[string]error
+
+func (e cleanUpError) Error() string {
+	buffer := bytes.NewBufferString("acme: the cleanup failed, the challenges of these domains may remain:\n")
+
+	for _, domain := range slices.Sorted(maps.Keys(e)) {
+		_, _ = fmt.Fprintf(buffer, "[%s] %s\n", domain, e[domain])
+	}
+
+	return buffer.String()
+}
+
+// Unwrap returns the errors of the domains (sorted by domain).
+func (e cleanUpError) Unwrap() []error {
+	return obtainError(e).Unwrap()
+}
+
+// cleanUpReport collects the errors of the cleanups of a call to Solve (or to CleanUpPending),
+// to report all the challenges which may remain at once.
+type cleanUpReport struct {
+	mu   sync.Mutex
+	errs cleanUpError
+}
+
+func (r *cleanUpReport) add(domain string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.errs == nil {
+		r.errs = make(cleanUpError)
+	}
+
+	r.errs[domain] = errors.Join(r.errs[domain], err)
+}
+
+// err returns the aggregated errors, nil if all the cleanups succeeded.
+func (r *cleanUpReport) err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.errs) == 0 {
+		return nil
+	}
+
+	return r.errs
+}
+
+func (r *cleanUpReport) log() {
+	if err := r.err(); err != nil {
+		log.Warnf("%v", err)
+	}
+}
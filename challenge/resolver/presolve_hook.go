@@ -0,0 +1,35 @@
+package resolver
+
+import (
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/challenge"
+)
+
+// ChallengeOffer the challenges offered by the CA for an authorization.
+type ChallengeOffer struct {
+	// Domain the targeted domain (i.e. `*.example.com` for a wildcard).
+	Domain string
+	// Identifier the identifier of the authorization.
+	Identifier acme.Identifier
+	// Challenges the challenges offered by the CA (type, token, URL, status).
+	Challenges []acme.Challenge
+	// Solvers the challenge types with a configured solver.
+	Solvers []challenge.Type
+}
+
+// Offers reports whether the CA offers a challenge type.
+func (o ChallengeOffer) Offers(chlgType challenge.Type) bool {
+	for _, chlg := range o.Challenges {
+		if chlg.Type == chlgType.String() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PreSolveHook is called after the CA offers the challenges of an authorization, and before solving them.
+//
+// It returns the challenge type to solve, an empty type keeps the default selection.
+// An error vetoes the authorization: the challenges are not solved, and the error is reported for the domain.
+type PreSolveHook func(offer ChallengeOffer) (challenge.Type, error)
@@ -1,11 +1,14 @@
 package resolver
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/onioncsr01"
 	"github.com/go-acme/lego/v4/log"
 )
 
@@ -20,6 +23,16 @@ type preSolver interface {
 	PreSolve(authorization acme.Authorization) error
 }
 
+// Interface for the solvers able to cancel the presentation of the challenges (see challenge.ProviderContext).
+type solverContext interface {
+	SolveContext(ctx context.Context, authorization acme.Authorization) error
+}
+
+// Interface for the pre-solvers able to cancel the presentation of the challenges (see challenge.ProviderContext).
+type preSolverContext interface {
+	PreSolveContext(ctx context.Context, authorization acme.Authorization) error
+}
+
 // Interface for challenges like dns, where we can solve all the challenges before to delete them.
 type cleanup interface {
 	CleanUp(authorization acme.Authorization) error
@@ -29,25 +42,86 @@ type sequential interface {
 	Sequential() (bool, time.Duration)
 }
 
+// Interface for the solvers limiting the number of challenges presented concurrently (see challenge.ProviderConcurrency).
+type concurrencyLimiter interface {
+	MaxConcurrency() int
+}
+
+// Interface for challenges like dns, where the records of ALL challenges can be set (and deleted) in a single call.
+type batchSolver interface {
+	Batch() bool
+	PreSolveBatch(authorizations []acme.Authorization) error
+	CleanUpBatch(authorizations []acme.Authorization) error
+}
+
+// Interface for the batch solvers able to cancel the presentation of the challenges (see challenge.ProviderBatchContext).
+type batchSolverContext interface {
+	PreSolveBatchContext(ctx context.Context, authorizations []acme.Authorization) error
+}
+
+// Interface for the solvers waiting for the longest timeout of the challenges of an order (see challenge.ProviderRecordTimeout).
+type timeoutsPreparer interface {
+	PrepareTimeouts(authorizations []acme.Authorization)
+}
+
 // an authz with the solver we have chosen and the index of the challenge associated with it.
 type selectedAuthSolver struct {
 	authz  acme.Authorization
 	solver solver
 }
 
+// the authz solved together by a solver supporting the batch operations.
+type solverBatch struct {
+	solver      batchSolver
+	authSolvers []*selectedAuthSolver
+}
+
 type Prober struct {
 	solverManager *SolverManager
+
+	// pending keeps the challenges which are not yet cleaned up.
+	pending   map[*selectedAuthSolver]struct{}
+	pendingMu sync.Mutex
+
+	// ctx the context of the presentations of the challenges, canceled by CleanUpPending.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// limits the slots of the solvers limiting the number of challenges presented concurrently.
+	limits   map[any]chan struct{}
+	limitsMu sync.Mutex
 }
 
 func NewProber(solverManager *SolverManager) *Prober {
+	ctx, cancel := context.WithCancel(context.Background())
+
 	return &Prober{
 		solverManager: solverManager,
+		pending:       make(map[*selectedAuthSolver]struct{}),
+		ctx:           ctx,
+		cancel:        cancel,
 	}
 }
 
 // Solve Looks through the challenge combinations to find a solvable match.
-// Then solves the challenges in series and returns.
+// Then solves the challenges (concurrently, see SolverManager.SetMaxParallelChallenges) and returns.
 func (p *Prober) Solve(authorizations []acme.Authorization) error {
+	return p.solveWith(authorizations, p.solverManager.chooseSolver)
+}
+
+// SolveWithOnionKeys is like Solve, but the onion-csr-01 challenges are solved with the keys of the hidden services of a request,
+// instead of the keys defined by SolverManager.SetOnionCSR01Provider.
+func (p *Prober) SolveWithOnionKeys(authorizations []acme.Authorization, keys onioncsr01.KeyProvider) error {
+	requestSolvers := map[challenge.Type]solver{
+		challenge.OnionCSR01: onioncsr01.NewChallenge(p.solverManager.core, p.solverManager.hookedValidateWithPayload, keys),
+	}
+
+	return p.solveWith(authorizations, func(authz acme.Authorization) (solver, error) {
+		return p.solverManager.chooseSolverWith(authz, requestSolvers)
+	})
+}
+
+func (p *Prober) solveWith(authorizations []acme.Authorization, chooseSolver func(authz acme.Authorization) (solver, error)) error {
 	failures := make(obtainError)
 
 	var (
@@ -66,27 +140,33 @@ func (p *Prober) Solve(authorizations []acme.Authorization) error {
 			continue
 		}
 
-		if solvr := p.solverManager.chooseSolver(authz); solvr != nil {
-			authSolver := &selectedAuthSolver{authz: authz, solver: solvr}
+		solvr, err := chooseSolver(authz)
+		if err != nil {
+			failures[domain] = err
+			continue
+		}
 
-			switch s := solvr.(type) {
-			case sequential:
-				if ok, _ := s.Sequential(); ok {
-					authSolversSequential = append(authSolversSequential, authSolver)
-				} else {
-					authSolvers = append(authSolvers, authSolver)
-				}
-			default:
+		authSolver := &selectedAuthSolver{authz: authz, solver: solvr}
+
+		switch s := solvr.(type) {
+		case sequential:
+			if ok, _ := s.Sequential(); ok {
+				authSolversSequential = append(authSolversSequential, authSolver)
+			} else {
 				authSolvers = append(authSolvers, authSolver)
 			}
-		} else {
-			failures[domain] = fmt.Errorf("[%s] acme: could not determine solvers", domain)
+		default:
+			authSolvers = append(authSolvers, authSolver)
 		}
 	}
 
-	parallelSolve(authSolvers, failures)
+	report := &cleanUpReport{}
+
+	p.parallelSolve(authSolvers, failures, report)
+
+	p.sequentialSolve(authSolversSequential, failures, report)
 
-	sequentialSolve(authSolversSequential, failures)
+	report.log()
 
 	// Be careful not to return an empty failures map,
 	// for even an empty obtainError is a non-nil error value
@@ -97,34 +177,64 @@ func (p *Prober) Solve(authorizations []acme.Authorization) error {
 	return nil
 }
 
-func sequentialSolve(authSolvers []*selectedAuthSolver, failures obtainError) {
+// CleanUpPending cleans up the challenges presented by the in-flight calls to Solve, and not yet cleaned up.
+// The in-flight presentations are canceled (see challenge.ProviderContext).
+// It's intended to be used to abandon the in-flight orders (e.g. when the process is stopped).
+func (p *Prober) CleanUpPending() {
+	p.pendingMu.Lock()
+	if p.cancel != nil {
+		p.cancel()
+	}
+
+	// The next calls to Solve use a new context.
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+
+	authSolvers := make([]*selectedAuthSolver, 0, len(p.pending))
+
+	for authSolver := range p.pending {
+		authSolvers = append(authSolvers, authSolver)
+	}
+	p.pendingMu.Unlock()
+
+	report := &cleanUpReport{}
+
+	for _, authSolver := range authSolvers {
+		p.cleanUp(authSolver, report)
+	}
+
+	report.log()
+}
+
+func (p *Prober) sequentialSolve(authSolvers []*selectedAuthSolver, failures obtainError, report *cleanUpReport) {
 	for i, authSolver := range authSolvers {
 		// Submit the challenge
 		domain := challenge.GetTargetedDomain(authSolver.authz)
 
-		if solvr, ok := authSolver.solver.(preSolver); ok {
-			err := solvr.PreSolve(authSolver.authz)
+		p.track(authSolver)
+
+		if _, ok := authSolver.solver.(preSolver); ok {
+			err := p.preSolve(authSolver)
 			if err != nil {
 				failures[domain] = err
 
-				cleanUp(authSolver.solver, authSolver.authz)
+				p.cleanUp(authSolver, report)
 
 				continue
 			}
 		}
 
 		// Solve challenge
-		err := authSolver.solver.Solve(authSolver.authz)
+		err := p.solve(authSolver)
 		if err != nil {
 			failures[domain] = err
 
-			cleanUp(authSolver.solver, authSolver.authz)
+			p.cleanUp(authSolver, report)
 
 			continue
 		}
 
 		// Clean challenge
-		cleanUp(authSolver.solver, authSolver.authz)
+		p.cleanUp(authSolver, report)
 
 		if len(authSolvers)-1 > i {
 			solvr := authSolver.solver.(sequential)
@@ -135,49 +245,317 @@ func sequentialSolve(authSolvers []*selectedAuthSolver, failures obtainError) {
 	}
 }
 
-func parallelSolve(authSolvers []*selectedAuthSolver, failures obtainError) {
-	// For all valid preSolvers, first submit the challenges, so they have max time to propagate
+func (p *Prober) parallelSolve(authSolvers []*selectedAuthSolver, failures obtainError, report *cleanUpReport) {
+	parallel := p.solverManager.maxParallelChallenges()
+
+	var failuresMu sync.Mutex
+
+	fail := func(authSolver *selectedAuthSolver, err error) {
+		failuresMu.Lock()
+		failures[challenge.GetTargetedDomain(authSolver.authz)] = err
+		failuresMu.Unlock()
+	}
+
+	var (
+		batches    []*solverBatch
+		preSolvers []*selectedAuthSolver
+	)
+
 	for _, authSolver := range authSolvers {
-		authz := authSolver.authz
-		if solvr, ok := authSolver.solver.(preSolver); ok {
-			err := solvr.PreSolve(authz)
-			if err != nil {
-				failures[challenge.GetTargetedDomain(authz)] = err
+		p.track(authSolver)
+
+		if solvr, ok := authSolver.solver.(batchSolver); ok && solvr.Batch() {
+			batches = addToBatch(batches, solvr, authSolver)
+			continue
+		}
+
+		if _, ok := authSolver.solver.(preSolver); ok {
+			preSolvers = append(preSolvers, authSolver)
+		}
+	}
+
+	// For all valid preSolvers, first submit the challenges, so they have max time to propagate
+	forEach(parallel, len(preSolvers), func(i int) {
+		err := p.preSolve(preSolvers[i])
+		if err != nil {
+			fail(preSolvers[i], err)
+		}
+	})
+
+	// The challenges of the solvers supporting batch operations are submitted in a single call.
+	for _, batch := range batches {
+		release := p.acquire(batch.solver)
+		err := p.preSolveBatch(batch)
+		release()
+
+		if err != nil {
+			for _, authSolver := range batch.authSolvers {
+				fail(authSolver, err)
 			}
 		}
 	}
 
 	defer func() {
-		// Clean all created TXT records
-		for _, authSolver := range authSolvers {
-			cleanUp(authSolver.solver, authSolver.authz)
+		// Clean all created TXT records:
+		// the cleanup of each challenge is attempted, even if the previous cleanups failed.
+		for _, batch := range batches {
+			p.cleanUpBatch(batch, report)
 		}
+
+		forEach(parallel, len(authSolvers), func(i int) {
+			p.cleanUp(authSolvers[i], report)
+		})
 	}()
 
-	// Finally solve all challenges for real
-	for _, authSolver := range authSolvers {
-		authz := authSolver.authz
+	var toSolve []*selectedAuthSolver
 
-		domain := challenge.GetTargetedDomain(authz)
-		if failures[domain] != nil {
+	for _, authSolver := range authSolvers {
+		if failures[challenge.GetTargetedDomain(authSolver.authz)] != nil {
 			// already failed in previous loop
 			continue
 		}
 
-		err := authSolver.solver.Solve(authz)
+		toSolve = append(toSolve, authSolver)
+	}
+
+	prepareTimeouts(toSolve)
+
+	// Finally solve all challenges for real
+	forEach(parallel, len(toSolve), func(i int) {
+		err := p.solve(toSolve[i])
 		if err != nil {
-			failures[domain] = err
+			fail(toSolve[i], err)
 		}
+	})
+}
+
+// prepareTimeouts provides the challenges solved together to their solvers,
+// to wait for the longest timeout of the challenges.
+func prepareTimeouts(authSolvers []*selectedAuthSolver) {
+	var (
+		preparers []timeoutsPreparer
+		authzs    = make(map[timeoutsPreparer][]acme.Authorization)
+	)
+
+	for _, authSolver := range authSolvers {
+		solvr, ok := authSolver.solver.(timeoutsPreparer)
+		if !ok {
+			continue
+		}
+
+		if _, exists := authzs[solvr]; !exists {
+			preparers = append(preparers, solvr)
+		}
+
+		authzs[solvr] = append(authzs[solvr], authSolver.authz)
+	}
+
+	for _, solvr := range preparers {
+		solvr.PrepareTimeouts(authzs[solvr])
 	}
 }
 
-func cleanUp(solvr solver, authz acme.Authorization) {
-	if solvr, ok := solvr.(cleanup); ok {
-		domain := challenge.GetTargetedDomain(authz)
+func (p *Prober) preSolve(authSolver *selectedAuthSolver) error {
+	defer p.acquire(authSolver.solver)()
 
-		err := solvr.CleanUp(authz)
-		if err != nil {
-			log.Warnf("[%s] acme: cleaning up failed: %v ", domain, err)
+	if solvr, ok := authSolver.solver.(preSolverContext); ok {
+		return solvr.PreSolveContext(p.context(), authSolver.authz)
+	}
+
+	return authSolver.solver.(preSolver).PreSolve(authSolver.authz)
+}
+
+func (p *Prober) preSolveBatch(batch *solverBatch) error {
+	if solvr, ok := batch.solver.(batchSolverContext); ok {
+		return solvr.PreSolveBatchContext(p.context(), batch.authzs())
+	}
+
+	return batch.solver.PreSolveBatch(batch.authzs())
+}
+
+func (p *Prober) solve(authSolver *selectedAuthSolver) error {
+	// The solvers without pre-solve present the challenge during Solve.
+	if _, ok := authSolver.solver.(preSolver); !ok {
+		defer p.acquire(authSolver.solver)()
+	}
+
+	if solvr, ok := authSolver.solver.(solverContext); ok {
+		return solvr.SolveContext(p.context(), authSolver.authz)
+	}
+
+	return authSolver.solver.Solve(authSolver.authz)
+}
+
+// context returns the context of the presentations of the challenges.
+func (p *Prober) context() context.Context {
+	p.pendingMu.Lock()
+	defer p.pendingMu.Unlock()
+
+	if p.ctx == nil {
+		p.ctx, p.cancel = context.WithCancel(context.Background())
+	}
+
+	return p.ctx
+}
+
+// acquire waits until the solver is able to present a new challenge (see challenge.ProviderConcurrency).
+// The returned function releases the slot.
+func (p *Prober) acquire(solvr any) func() {
+	limited, ok := solvr.(concurrencyLimiter)
+	if !ok || limited.MaxConcurrency() < 1 {
+		return func() {}
+	}
+
+	p.limitsMu.Lock()
+	if p.limits == nil {
+		p.limits = make(map[any]chan struct{})
+	}
+
+	slots, ok := p.limits[solvr]
+	if !ok {
+		slots = make(chan struct{}, limited.MaxConcurrency())
+		p.limits[solvr] = slots
+	}
+	p.limitsMu.Unlock()
+
+	slots <- struct{}{}
+
+	return func() { <-slots }
+}
+
+// track keeps the challenge as pending until it's cleaned up.
+func (p *Prober) track(authSolver *selectedAuthSolver) {
+	if _, ok := authSolver.solver.(cleanup); !ok {
+		return
+	}
+
+	p.pendingMu.Lock()
+	if p.pending == nil {
+		p.pending = make(map[*selectedAuthSolver]struct{})
+	}
+
+	p.pending[authSolver] = struct{}{}
+	p.pendingMu.Unlock()
+}
+
+// cleanUp cleans up the challenge only once, even if it's called concurrently by Solve and CleanUpPending.
+func (p *Prober) cleanUp(authSolver *selectedAuthSolver, report *cleanUpReport) {
+	p.pendingMu.Lock()
+	_, ok := p.pending[authSolver]
+	delete(p.pending, authSolver)
+	p.pendingMu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	defer p.acquire(authSolver.solver)()
+
+	err := cleanUp(authSolver.solver, authSolver.authz)
+	if err != nil {
+		report.add(challenge.GetTargetedDomain(authSolver.authz), err)
+	}
+}
+
+// cleanUpBatch cleans up, in a single call, the challenges of the batch not yet cleaned up.
+func (p *Prober) cleanUpBatch(batch *solverBatch, report *cleanUpReport) {
+	var authzs []acme.Authorization
+
+	p.pendingMu.Lock()
+	for _, authSolver := range batch.authSolvers {
+		if _, ok := p.pending[authSolver]; ok {
+			delete(p.pending, authSolver)
+
+			authzs = append(authzs, authSolver.authz)
+		}
+	}
+	p.pendingMu.Unlock()
+
+	if len(authzs) == 0 {
+		return
+	}
+
+	defer p.acquire(batch.solver)()
+
+	err := protect(func() error { return batch.solver.CleanUpBatch(authzs) })
+	if err != nil {
+		for _, authz := range authzs {
+			report.add(challenge.GetTargetedDomain(authz), err)
+		}
+	}
+}
+
+func (b *solverBatch) authzs() []acme.Authorization {
+	authzs := make([]acme.Authorization, 0, len(b.authSolvers))
+
+	for _, authSolver := range b.authSolvers {
+		authzs = append(authzs, authSolver.authz)
+	}
+
+	return authzs
+}
+
+// forEach calls fn for each index, with at most parallel concurrent calls.
+// With a single worker, the calls are made in order by the current goroutine.
+func forEach(parallel, n int, fn func(i int)) {
+	if parallel <= 1 || n <= 1 {
+		for i := range n {
+			fn(i)
 		}
+
+		return
 	}
+
+	sem := make(chan struct{}, parallel)
+
+	var wg sync.WaitGroup
+
+	for i := range n {
+		sem <- struct{}{}
+
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			fn(i)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func addToBatch(batches []*solverBatch, solvr batchSolver, authSolver *selectedAuthSolver) []*solverBatch {
+	for _, batch := range batches {
+		if batch.solver == solvr {
+			batch.authSolvers = append(batch.authSolvers, authSolver)
+			return batches
+		}
+	}
+
+	return append(batches, &solverBatch{solver: solvr, authSolvers: []*selectedAuthSolver{authSolver}})
+}
+
+func cleanUp(solvr solver, authz acme.Authorization) error {
+	c, ok := solvr.(cleanup)
+	if !ok {
+		return nil
+	}
+
+	return protect(func() error { return c.CleanUp(authz) })
+}
+
+// protect calls the function, and converts a panic into an error:
+// a failed cleanup must not prevent the cleanups of the other challenges.
+func protect(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return fn()
 }
@@ -1,6 +1,7 @@
 package resolver
 
 import (
+	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/acme"
@@ -25,6 +26,70 @@ func (s *preSolverMock) CleanUp(authorization acme.Authorization) error {
 	return s.cleanUp[authorization.Identifier.Value]
 }
 
+type batchSolverMock struct {
+	preSolverMock
+
+	preSolveBatch error
+
+	preSolveBatchCalls [][]string
+	cleanUpBatchCalls  [][]string
+}
+
+func (s *batchSolverMock) Batch() bool {
+	return true
+}
+
+func (s *batchSolverMock) PreSolveBatch(authorizations []acme.Authorization) error {
+	s.preSolveBatchCalls = append(s.preSolveBatchCalls, identifiers(authorizations))
+
+	return s.preSolveBatch
+}
+
+func (s *batchSolverMock) CleanUpBatch(authorizations []acme.Authorization) error {
+	s.cleanUpBatchCalls = append(s.cleanUpBatchCalls, identifiers(authorizations))
+
+	return nil
+}
+
+type cleanUpRecorderMock struct {
+	preSolverMock
+
+	mu      sync.Mutex
+	cleaned []string
+}
+
+func (s *cleanUpRecorderMock) CleanUp(authorization acme.Authorization) error {
+	s.mu.Lock()
+	s.cleaned = append(s.cleaned, authorization.Identifier.Value)
+	s.mu.Unlock()
+
+	if authorization.Identifier.Value == "panic.example.com" {
+		panic("OOPS")
+	}
+
+	return s.cleanUp[authorization.Identifier.Value]
+}
+
+type timeoutsPreparerMock struct {
+	preSolverMock
+
+	prepareTimeoutsCalls [][]string
+}
+
+func (s *timeoutsPreparerMock) PrepareTimeouts(authorizations []acme.Authorization) {
+	s.prepareTimeoutsCalls = append(s.prepareTimeoutsCalls, identifiers(authorizations))
+}
+
+func identifiers(authorizations []acme.Authorization) []string {
+	var values []string
+
+	for _, authz := range authorizations {
+		values = append(values, authz.Identifier.Value)
+	}
+
+	return values
+}
+
 func createStubAuthorizationHTTP01(domain, status string) acme.Authorization {
 	return acme.Authorization{
 		Status:  status,
@@ -42,3 +107,52 @@ func createStubAuthorizationHTTP01(domain, status string) acme.Authorization {
 		},
 	}
 }
+
+type concurrentSolverMock struct {
+	maxConcurrency int
+
+	presenting gauge
+	solving    gauge
+}
+
+func (s *concurrentSolverMock) MaxConcurrency() int {
+	return s.maxConcurrency
+}
+
+func (s *concurrentSolverMock) PreSolve(_ acme.Authorization) error {
+	defer s.presenting.enter()()
+
+	return nil
+}
+
+func (s *concurrentSolverMock) Solve(_ acme.Authorization) error {
+	defer s.solving.enter()()
+
+	return nil
+}
+
+func (s *concurrentSolverMock) CleanUp(_ acme.Authorization) error {
+	return nil
+}
+
+// gauge records the maximum number of concurrent calls.
+type gauge struct {
+	mu      sync.Mutex
+	current int
+	max     int
+}
+
+func (g *gauge) enter() func() {
+	g.mu.Lock()
+	g.current++
+	g.max = max(g.max, g.current)
+	g.mu.Unlock()
+
+	time.Sleep(50 * time.Millisecond)
+
+	return func() {
+		g.mu.Lock()
+		g.current--
+		g.mu.Unlock()
+	}
+}
@@ -1,11 +1,16 @@
 package resolver
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"sync/atomic"
 	"testing"
 
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/errcode"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -115,3 +120,286 @@ func TestProber_Solve(t *testing.T) {
 		})
 	}
 }
+
+func TestProber_Solve_batch(t *testing.T) {
+	solvr := &batchSolverMock{
+		preSolverMock: preSolverMock{
+			preSolve: map[string]error{
+				"example.com": errors.New("preSolve must not be called"),
+			},
+			solve:   map[string]error{},
+			cleanUp: map[string]error{},
+		},
+	}
+
+	prober := &Prober{
+		solverManager: &SolverManager{solvers: map[challenge.Type]solver{challenge.HTTP01: solvr}},
+	}
+
+	err := prober.Solve([]acme.Authorization{
+		createStubAuthorizationHTTP01("example.com", acme.StatusProcessing),
+		createStubAuthorizationHTTP01("example.org", acme.StatusProcessing),
+	})
+	require.NoError(t, err)
+
+	expected := [][]string{{"example.com", "example.org"}}
+
+	assert.Equal(t, expected, solvr.preSolveBatchCalls)
+	assert.Equal(t, expected, solvr.cleanUpBatchCalls)
+}
+
+func TestProber_Solve_batch_error(t *testing.T) {
+	solvr := &batchSolverMock{
+		preSolverMock: preSolverMock{
+			solve:   map[string]error{},
+			cleanUp: map[string]error{},
+		},
+		preSolveBatch: errors.New("preSolve batch error"),
+	}
+
+	prober := &Prober{
+		solverManager: &SolverManager{solvers: map[challenge.Type]solver{challenge.HTTP01: solvr}},
+	}
+
+	err := prober.Solve([]acme.Authorization{
+		createStubAuthorizationHTTP01("example.com", acme.StatusProcessing),
+		createStubAuthorizationHTTP01("example.org", acme.StatusProcessing),
+	})
+	require.EqualError(t, err, `error: one or more domains had a problem:
+[example.com] preSolve batch error
+[example.org] preSolve batch error
+`)
+
+	assert.Equal(t, [][]string{{"example.com", "example.org"}}, solvr.cleanUpBatchCalls)
+}
+
+func TestProber_Solve_prepareTimeouts(t *testing.T) {
+	solvr := &timeoutsPreparerMock{
+		preSolverMock: preSolverMock{
+			preSolve: map[string]error{
+				"example.net": errors.New("preSolve error"),
+			},
+			solve:   map[string]error{},
+			cleanUp: map[string]error{},
+		},
+	}
+
+	prober := &Prober{
+		solverManager: &SolverManager{solvers: map[challenge.Type]solver{challenge.HTTP01: solvr}},
+	}
+
+	err := prober.Solve([]acme.Authorization{
+		createStubAuthorizationHTTP01("example.com", acme.StatusProcessing),
+		createStubAuthorizationHTTP01("example.org", acme.StatusProcessing),
+		createStubAuthorizationHTTP01("example.net", acme.StatusProcessing),
+	})
+	require.Error(t, err)
+
+	// The failed challenges are not solved.
+	assert.Equal(t, [][]string{{"example.com", "example.org"}}, solvr.prepareTimeoutsCalls)
+}
+
+func TestProber_parallelSolve_cleanUpErrors(t *testing.T) {
+	solvr := &cleanUpRecorderMock{
+		preSolverMock: preSolverMock{
+			preSolve: map[string]error{},
+			solve:    map[string]error{},
+			cleanUp: map[string]error{
+				"example.com": errors.New("cleanUp error"),
+			},
+		},
+	}
+
+	prober := &Prober{
+		solverManager: &SolverManager{solvers: map[challenge.Type]solver{challenge.HTTP01: solvr}},
+	}
+
+	var authSolvers []*selectedAuthSolver
+
+	for _, domain := range []string{"example.com", "panic.example.com", "example.org"} {
+		authSolvers = append(authSolvers, &selectedAuthSolver{
+			authz:  createStubAuthorizationHTTP01(domain, acme.StatusProcessing),
+			solver: solvr,
+		})
+	}
+
+	failures := make(obtainError)
+	report := &cleanUpReport{}
+
+	prober.parallelSolve(authSolvers, failures, report)
+
+	assert.Empty(t, failures)
+
+	// Every challenge is cleaned up, even after a failure.
+	assert.ElementsMatch(t, []string{"example.com", "panic.example.com", "example.org"}, solvr.cleaned)
+
+	require.EqualError(t, report.err(), `acme: the cleanup failed, the challenges of these domains may remain:
+[example.com] cleanUp error
+[panic.example.com] panic: OOPS
+`)
+}
+
+func TestProber_Solve_errorCode(t *testing.T) {
+	prober := &Prober{
+		solverManager: &SolverManager{solvers: map[challenge.Type]solver{}},
+	}
+
+	err := prober.Solve([]acme.Authorization{
+		createStubAuthorizationHTTP01("example.com", acme.StatusProcessing),
+	})
+	require.Error(t, err)
+
+	assert.Equal(t, errcode.ChallengeUnsupported, errcode.Of(err))
+}
+
+func TestProber_Solve_parallel(t *testing.T) {
+	solvr := &concurrentSolverMock{}
+
+	manager := &SolverManager{solvers: map[challenge.Type]solver{challenge.HTTP01: solvr}}
+	manager.SetMaxParallelChallenges(3)
+
+	prober := NewProber(manager)
+
+	err := prober.Solve(createStubAuthorizations(6))
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, solvr.presenting.max)
+	assert.Equal(t, 3, solvr.solving.max)
+}
+
+func TestProber_Solve_parallel_providerLimit(t *testing.T) {
+	solvr := &concurrentSolverMock{maxConcurrency: 1}
+
+	manager := &SolverManager{solvers: map[challenge.Type]solver{challenge.HTTP01: solvr}}
+	manager.SetMaxParallelChallenges(3)
+
+	prober := NewProber(manager)
+
+	err := prober.Solve(createStubAuthorizations(6))
+	require.NoError(t, err)
+
+	// The limit of the provider applies to the presentations, not to the validations.
+	assert.Equal(t, 1, solvr.presenting.max)
+	assert.Equal(t, 3, solvr.solving.max)
+}
+
+func TestProber_Solve_sequentialByDefault(t *testing.T) {
+	solvr := &concurrentSolverMock{}
+
+	prober := NewProber(&SolverManager{solvers: map[challenge.Type]solver{challenge.HTTP01: solvr}})
+
+	err := prober.Solve(createStubAuthorizations(3))
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, solvr.presenting.max)
+	assert.Equal(t, 1, solvr.solving.max)
+}
+
+func createStubAuthorizations(n int) []acme.Authorization {
+	var authzs []acme.Authorization
+
+	for i := range n {
+		authzs = append(authzs, createStubAuthorizationHTTP01(fmt.Sprintf("%d.example.com", i), acme.StatusProcessing))
+	}
+
+	return authzs
+}
+
+type blockingSolverMock struct {
+	presented chan struct{}
+	release   chan struct{}
+	cleaned   atomic.Int32
+}
+
+func (s *blockingSolverMock) PreSolve(_ acme.Authorization) error {
+	close(s.presented)
+	return nil
+}
+
+func (s *blockingSolverMock) Solve(_ acme.Authorization) error {
+	<-s.release
+	return errors.New("abandoned")
+}
+
+func (s *blockingSolverMock) CleanUp(_ acme.Authorization) error {
+	s.cleaned.Add(1)
+	return nil
+}
+
+func TestProber_CleanUpPending(t *testing.T) {
+	solvr := &blockingSolverMock{
+		presented: make(chan struct{}),
+		release:   make(chan struct{}),
+	}
+
+	prober := NewProber(&SolverManager{solvers: map[challenge.Type]solver{challenge.HTTP01: solvr}})
+
+	done := make(chan error)
+
+	go func() {
+		done <- prober.Solve([]acme.Authorization{createStubAuthorizationHTTP01("example.com", acme.StatusProcessing)})
+	}()
+
+	<-solvr.presented
+
+	prober.CleanUpPending()
+
+	assert.Equal(t, int32(1), solvr.cleaned.Load())
+
+	close(solvr.release)
+
+	require.Error(t, <-done)
+
+	// The challenge is not cleaned up twice.
+	assert.Equal(t, int32(1), solvr.cleaned.Load())
+}
+
+type contextSolverMock struct {
+	presented chan struct{}
+}
+
+func (s *contextSolverMock) Solve(authorization acme.Authorization) error {
+	return s.SolveContext(context.Background(), authorization)
+}
+
+func (s *contextSolverMock) SolveContext(ctx context.Context, _ acme.Authorization) error {
+	close(s.presented)
+
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+func TestProber_CleanUpPending_cancel(t *testing.T) {
+	solvr := &contextSolverMock{presented: make(chan struct{})}
+
+	prober := NewProber(&SolverManager{solvers: map[challenge.Type]solver{challenge.HTTP01: solvr}})
+
+	done := make(chan error)
+
+	go func() {
+		done <- prober.Solve([]acme.Authorization{createStubAuthorizationHTTP01("example.com", acme.StatusProcessing)})
+	}()
+
+	<-solvr.presented
+
+	prober.CleanUpPending()
+
+	err := <-done
+	require.ErrorIs(t, err, context.Canceled)
+
+	// The next calls use a new context.
+	require.NoError(t, prober.context().Err())
+}
+
+func TestFailedDomains(t *testing.T) {
+	failures := obtainError{
+		"example.org":   errors.New("invalid"),
+		"*.example.com": errors.New("timeout"),
+	}
+
+	assert.Equal(t, []string{"*.example.com", "example.org"}, FailedDomains(fmt.Errorf("wrapped: %w", failures)))
+
+	assert.Nil(t, FailedDomains(errors.New("order: rate limited")))
+	assert.Nil(t, FailedDomains(nil))
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v5"
@@ -14,8 +16,11 @@ import (
 	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/challenge/http01"
+	"github.com/go-acme/lego/v4/challenge/onioncsr01"
+	"github.com/go-acme/lego/v4/challenge/tkauth01"
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
 	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/errcode"
 	"github.com/go-acme/lego/v4/platform/wait"
 )
 
@@ -25,9 +30,19 @@ func (a byType) Len() int           { return len(a) }
 func (a byType) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a byType) Less(i, j int) bool { return a[i].Type > a[j].Type }
 
+// SolverManager manages the challenge solvers.
+// A SolverManager is safe for concurrent use:
+// the solvers can be set or removed while certificates are obtained.
 type SolverManager struct {
-	core    *api.Core
-	solvers map[challenge.Type]solver
+	core *api.Core
+
+	solvers      map[challenge.Type]solver
+	preSolve     PreSolveHook
+	preValidate  ValidationHook
+	postValidate ValidationHook
+	logger       log.StructuredLogger
+	parallel     int
+	solversMu    sync.RWMutex
 }
 
 func NewSolversManager(core *api.Core) *SolverManager {
@@ -39,47 +54,191 @@ func NewSolversManager(core *api.Core) *SolverManager {
 
 // SetHTTP01Provider specifies a custom provider p that can solve the given HTTP-01 challenge.
 func (c *SolverManager) SetHTTP01Provider(p challenge.Provider, opts ...http01.ChallengeOption) error {
-	c.solvers[challenge.HTTP01] = http01.NewChallenge(c.core, validate, p, opts...)
+	c.setSolver(challenge.HTTP01, http01.NewChallenge(c.core, c.hookedValidate, p, opts...))
 	return nil
 }
 
 // SetTLSALPN01Provider specifies a custom provider p that can solve the given TLS-ALPN-01 challenge.
 func (c *SolverManager) SetTLSALPN01Provider(p challenge.Provider, opts ...tlsalpn01.ChallengeOption) error {
-	c.solvers[challenge.TLSALPN01] = tlsalpn01.NewChallenge(c.core, validate, p, opts...)
+	c.setSolver(challenge.TLSALPN01, tlsalpn01.NewChallenge(c.core, c.hookedValidate, p, opts...))
 	return nil
 }
 
 // SetDNS01Provider specifies a custom provider p that can solve the given DNS-01 challenge.
+// The structured logger of the manager (see SetLogger) is provided to the challenge.
 func (c *SolverManager) SetDNS01Provider(p challenge.Provider, opts ...dns01.ChallengeOption) error {
-	c.solvers[challenge.DNS01] = dns01.NewChallenge(c.core, validate, p, opts...)
+	c.solversMu.RLock()
+	logger := c.logger
+	c.solversMu.RUnlock()
+
+	opts = append([]dns01.ChallengeOption{dns01.CondOption(logger != nil, dns01.AddLogger(logger))}, opts...)
+
+	c.setSolver(challenge.DNS01, dns01.NewChallenge(c.core, c.hookedValidate, p, opts...))
+
+	return nil
+}
+
+// SetOnionCSR01Provider specifies the keys of the hidden services to solve the given ONION-CSR-01 challenge.
+func (c *SolverManager) SetOnionCSR01Provider(keys onioncsr01.KeyProvider, opts ...onioncsr01.ChallengeOption) error {
+	c.setSolver(challenge.OnionCSR01, onioncsr01.NewChallenge(c.core, c.hookedValidateWithPayload, keys, opts...))
+	return nil
+}
+
+// SetTKAuth01Provider specifies the token fetcher to solve the given TKAUTH-01 challenge (e.g. the TNAuthList identifiers of STIR/SHAKEN).
+func (c *SolverManager) SetTKAuth01Provider(fetcher tkauth01.TokenFetcher, opts ...tkauth01.ChallengeOption) error {
+	c.setSolver(challenge.TKAuth01, tkauth01.NewChallenge(c.core, c.hookedValidateWithPayload, fetcher, opts...))
 	return nil
 }
 
 // Remove removes a challenge type from the available solvers.
 func (c *SolverManager) Remove(chlgType challenge.Type) {
+	c.solversMu.Lock()
+	defer c.solversMu.Unlock()
+
 	delete(c.solvers, chlgType)
 }
 
+// SetPreSolveHook defines a hook called with the challenges offered by the CA for each authorization, before solving them.
+// The hook can veto an authorization, or select the challenge type to solve.
+func (c *SolverManager) SetPreSolveHook(hook PreSolveHook) {
+	c.solversMu.Lock()
+	defer c.solversMu.Unlock()
+
+	c.preSolve = hook
+}
+
+// SetLogger defines the structured logger of the events of the challenges.
+// It must be called before setting the providers.
+func (c *SolverManager) SetLogger(logger log.StructuredLogger) {
+	c.solversMu.Lock()
+	defer c.solversMu.Unlock()
+
+	c.logger = logger
+}
+
+// SetMaxParallelChallenges defines the maximum number of authorizations of an order solved concurrently:
+// the challenges are presented, and validated, by a pool of workers.
+// The limit of each provider (see challenge.ProviderConcurrency) is also applied.
+// The default value (1) solves the authorizations one by one.
+func (c *SolverManager) SetMaxParallelChallenges(n int) {
+	c.solversMu.Lock()
+	defer c.solversMu.Unlock()
+
+	c.parallel = n
+}
+
+func (c *SolverManager) maxParallelChallenges() int {
+	c.solversMu.RLock()
+	defer c.solversMu.RUnlock()
+
+	return max(c.parallel, 1)
+}
+
+func (c *SolverManager) setSolver(chlgType challenge.Type, s solver) {
+	c.solversMu.Lock()
+	defer c.solversMu.Unlock()
+
+	c.solvers[chlgType] = s
+}
+
+func (c *SolverManager) getSolver(chlgType challenge.Type) (solver, bool) {
+	c.solversMu.RLock()
+	defer c.solversMu.RUnlock()
+
+	s, ok := c.solvers[chlgType]
+
+	return s, ok
+}
+
 // Checks all challenges from the server in order and returns the first matching solver.
-func (c *SolverManager) chooseSolver(authz acme.Authorization) solver {
-	// Allow to have a deterministic challenge order
-	sort.Sort(byType(authz.Challenges))
+// The pre-solve hook, if defined, can veto the authorization or select the challenge type.
+func (c *SolverManager) chooseSolver(authz acme.Authorization) (solver, error) {
+	return c.chooseSolverWith(authz, nil)
+}
+
+// chooseSolverWith is like chooseSolver, but the solvers of a request take precedence over the solvers of the manager.
+func (c *SolverManager) chooseSolverWith(authz acme.Authorization, requestSolvers map[challenge.Type]solver) (solver, error) {
+	// Allow to have a deterministic challenge order.
+	// The challenges are copied because the slice can be shared with the caller.
+	challenges := slices.Clone(authz.Challenges)
+	sort.Sort(byType(challenges))
 
 	domain := challenge.GetTargetedDomain(authz)
-	for _, chlg := range authz.Challenges {
-		if solvr, ok := c.solvers[challenge.Type(chlg.Type)]; ok {
+
+	lookup := func(chlgType challenge.Type) (solver, bool) {
+		if solvr, ok := requestSolvers[chlgType]; ok {
+			return solvr, true
+		}
+
+		return c.getSolver(chlgType)
+	}
+
+	selected, err := c.callPreSolveHook(domain, authz, challenges, requestSolvers)
+	if err != nil {
+		return nil, errcode.Wrap(errcode.ChallengeDenied, fmt.Errorf("[%s] acme: the challenges have been denied by the pre-solve hook: %w", domain, err))
+	}
+
+	if selected != "" {
+		solvr, ok := lookup(selected)
+		if !ok || !slices.ContainsFunc(challenges, func(chlg acme.Challenge) bool { return chlg.Type == selected.String() }) {
+			return nil, errcode.Wrap(errcode.ChallengeUnsupported,
+				fmt.Errorf("[%s] acme: the challenge %s selected by the pre-solve hook is not offered or has no solver", domain, selected))
+		}
+
+		log.Infof("[%s] acme: use %s solver (selected by the pre-solve hook)", domain, selected)
+
+		return solvr, nil
+	}
+
+	for _, chlg := range challenges {
+		if solvr, ok := lookup(challenge.Type(chlg.Type)); ok {
 			log.Infof("[%s] acme: use %s solver", domain, chlg.Type)
-			return solvr
+			return solvr, nil
 		}
 
 		log.Infof("[%s] acme: Could not find solver for: %s", domain, chlg.Type)
 	}
 
-	return nil
+	return nil, errcode.Wrap(errcode.ChallengeUnsupported, fmt.Errorf("[%s] acme: could not determine solvers", domain))
+}
+
+func (c *SolverManager) callPreSolveHook(domain string, authz acme.Authorization, challenges []acme.Challenge, requestSolvers map[challenge.Type]solver) (challenge.Type, error) {
+	c.solversMu.RLock()
+	hook := c.preSolve
+
+	var types []challenge.Type
+	for chlgType := range c.solvers {
+		types = append(types, chlgType)
+	}
+	c.solversMu.RUnlock()
+
+	if hook == nil {
+		return "", nil
+	}
+
+	for chlgType := range requestSolvers {
+		if !slices.Contains(types, chlgType) {
+			types = append(types, chlgType)
+		}
+	}
+
+	slices.Sort(types)
+
+	return hook(ChallengeOffer{
+		Domain:     domain,
+		Identifier: authz.Identifier,
+		Challenges: challenges,
+		Solvers:    types,
+	})
 }
 
 func validate(core *api.Core, domain string, chlg acme.Challenge) error {
-	chlng, err := core.Challenges.New(chlg.URL)
+	return validateWithPayload(core, domain, chlg, struct{}{})
+}
+
+// validateWithPayload validates a challenge with a challenge-specific response.
+func validateWithPayload(core *api.Core, domain string, chlg acme.Challenge, payload any) error {
+	chlng, err := core.Challenges.NewWithPayload(chlg.URL, payload)
 	if err != nil {
 		return fmt.Errorf("failed to initiate challenge: %w", err)
 	}
@@ -144,7 +303,7 @@ func checkChallengeStatus(chlng acme.ExtendedChallenge) (bool, error) {
 	case acme.StatusPending, acme.StatusProcessing:
 		return false, nil
 	case acme.StatusInvalid:
-		return false, fmt.Errorf("invalid challenge: %w", chlng.Err())
+		return false, errcode.Wrap(errcode.ChallengeInvalid, fmt.Errorf("invalid challenge: %w", chlng.Err()))
 	default:
 		return false, fmt.Errorf("the server returned an unexpected challenge status: %s", chlng.Status)
 	}
@@ -161,11 +320,11 @@ func checkAuthorizationStatus(authz acme.Authorization) (bool, error) {
 	case acme.StatusInvalid:
 		for _, chlg := range authz.Challenges {
 			if chlg.Status == acme.StatusInvalid && chlg.Error != nil {
-				return false, fmt.Errorf("invalid authorization: %w", chlg.Err())
+				return false, errcode.Wrap(errcode.ChallengeInvalid, fmt.Errorf("invalid authorization: %w", chlg.Err()))
 			}
 		}
 
-		return false, errors.New("invalid authorization")
+		return false, errcode.Wrap(errcode.ChallengeInvalid, errors.New("invalid authorization"))
 	default:
 		return false, fmt.Errorf("the server returned an unexpected authorization status: %s", authz.Status)
 	}
@@ -3,14 +3,19 @@ package resolver
 import (
 	"crypto/rand"
 	"crypto/rsa"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"sort"
+	"sync"
 	"testing"
 
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/http01"
+	"github.com/go-acme/lego/v4/platform/errcode"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/go-acme/lego/v4/platform/tester/servermock"
 	"github.com/go-jose/go-jose/v4"
@@ -32,6 +37,187 @@ func TestByType(t *testing.T) {
 	assert.Equal(t, expected, challenges)
 }
 
+func TestSolverManager_concurrency(t *testing.T) {
+	manager := NewSolversManager(nil)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: "dns-01"}, {Type: "http-01"}, {Type: "tls-alpn-01"}},
+	}
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Add(3)
+
+		go func() {
+			defer wg.Done()
+
+			_ = manager.SetHTTP01Provider(http01.NewProviderServer("", ""))
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			manager.Remove(challenge.HTTP01)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			_, _ = manager.chooseSolver(authz)
+		}()
+	}
+
+	wg.Wait()
+
+	// the challenges of the authorization must not be modified.
+	assert.Equal(t, []acme.Challenge{{Type: "dns-01"}, {Type: "http-01"}, {Type: "tls-alpn-01"}}, authz.Challenges)
+}
+
+func TestSolverManager_chooseSolver_preSolveHook(t *testing.T) {
+	httpSolver := &preSolverMock{}
+	dnsSolver := &preSolverMock{}
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+		Challenges: []acme.Challenge{{Type: "http-01", Token: "a"}, {Type: "dns-01", Token: "b"}},
+	}
+
+	testCases := []struct {
+		desc          string
+		hook          PreSolveHook
+		expected      solver
+		expectedCode  errcode.Code
+		expectedError string
+	}{
+		{
+			desc:     "no hook",
+			expected: httpSolver,
+		},
+		{
+			desc:     "default selection",
+			hook:     func(_ ChallengeOffer) (challenge.Type, error) { return "", nil },
+			expected: httpSolver,
+		},
+		{
+			desc:     "select",
+			hook:     func(_ ChallengeOffer) (challenge.Type, error) { return challenge.DNS01, nil },
+			expected: dnsSolver,
+		},
+		{
+			desc:          "veto",
+			hook:          func(_ ChallengeOffer) (challenge.Type, error) { return "", errors.New("customer suspended") },
+			expectedCode:  errcode.ChallengeDenied,
+			expectedError: "[example.com] acme: the challenges have been denied by the pre-solve hook: customer suspended",
+		},
+		{
+			desc:          "select not offered",
+			hook:          func(_ ChallengeOffer) (challenge.Type, error) { return challenge.TLSALPN01, nil },
+			expectedCode:  errcode.ChallengeUnsupported,
+			expectedError: "[example.com] acme: the challenge tls-alpn-01 selected by the pre-solve hook is not offered or has no solver",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			manager := &SolverManager{solvers: map[challenge.Type]solver{
+				challenge.HTTP01: httpSolver,
+				challenge.DNS01:  dnsSolver,
+			}}
+
+			manager.SetPreSolveHook(test.hook)
+
+			solvr, err := manager.chooseSolver(authz)
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+				assert.Equal(t, test.expectedCode, errcode.Of(err))
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Same(t, test.expected, solvr)
+		})
+	}
+}
+
+func TestSolverManager_chooseSolver_preSolveHook_offer(t *testing.T) {
+	manager := &SolverManager{solvers: map[challenge.Type]solver{
+		challenge.HTTP01: &preSolverMock{},
+		challenge.DNS01:  &preSolverMock{},
+	}}
+
+	var offer ChallengeOffer
+
+	manager.SetPreSolveHook(func(o ChallengeOffer) (challenge.Type, error) {
+		offer = o
+		return "", nil
+	})
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+		Wildcard:   true,
+		Challenges: []acme.Challenge{{Type: "dns-01", Token: "a"}, {Type: "http-01", Token: "b"}},
+	}
+
+	_, err := manager.chooseSolver(authz)
+	require.NoError(t, err)
+
+	assert.Equal(t, "*.example.com", offer.Domain)
+	assert.Equal(t, authz.Identifier, offer.Identifier)
+	assert.Equal(t, []acme.Challenge{{Type: "http-01", Token: "b"}, {Type: "dns-01", Token: "a"}}, offer.Challenges)
+	assert.Equal(t, []challenge.Type{challenge.DNS01, challenge.HTTP01}, offer.Solvers)
+	assert.True(t, offer.Offers(challenge.DNS01))
+	assert.False(t, offer.Offers(challenge.TLSALPN01))
+}
+
+func TestSolverManager_chooseSolverWith(t *testing.T) {
+	onionSolver := &preSolverMock{}
+	requestSolver := &preSolverMock{}
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Type: "dns", Value: "example.onion"},
+		Challenges: []acme.Challenge{{Type: "onion-csr-01", Nonce: "a"}},
+	}
+
+	testCases := []struct {
+		desc     string
+		solvers  map[challenge.Type]solver
+		expected solver
+	}{
+		{
+			desc:     "request solver only",
+			solvers:  map[challenge.Type]solver{challenge.HTTP01: &preSolverMock{}},
+			expected: requestSolver,
+		},
+		{
+			desc:     "request solver takes precedence",
+			solvers:  map[challenge.Type]solver{challenge.OnionCSR01: onionSolver},
+			expected: requestSolver,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			manager := &SolverManager{solvers: test.solvers}
+
+			var offer ChallengeOffer
+
+			manager.SetPreSolveHook(func(o ChallengeOffer) (challenge.Type, error) {
+				offer = o
+				return "", nil
+			})
+
+			solvr, err := manager.chooseSolverWith(authz, map[challenge.Type]solver{challenge.OnionCSR01: requestSolver})
+			require.NoError(t, err)
+
+			assert.Same(t, test.expected, solvr)
+			assert.Contains(t, offer.Solvers, challenge.OnionCSR01)
+		})
+	}
+}
+
 func TestValidate(t *testing.T) {
 	var statuses []string
 
@@ -129,6 +315,89 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidateWithPayload(t *testing.T) {
+	privateKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+
+	server := tester.MockACMEServer().
+		Route("POST /chlg",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := readJWSBody(privateKey, req)
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				if string(body) != `{"csr":"Y3Ny"}` {
+					http.Error(rw, fmt.Sprintf("unexpected body: %s", body), http.StatusBadRequest)
+					return
+				}
+
+				chlg := &acme.Challenge{Type: "onion-csr-01", Status: acme.StatusValid, URL: "http://example.com/"}
+
+				servermock.JSONEncode(chlg).ServeHTTP(rw, req)
+			})).
+		BuildHTTPS(t)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	payload := struct {
+		CSR string `json:"csr"`
+	}{CSR: "Y3Ny"}
+
+	err = validateWithPayload(core, "example.onion", acme.Challenge{Type: "onion-csr-01", URL: server.URL + "/chlg"}, payload)
+	require.NoError(t, err)
+}
+
+func TestSolverManager_SetValidationHooks(t *testing.T) {
+	privateKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+
+	var validated int
+
+	server := tester.MockACMEServer().
+		Route("POST /chlg",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				validated++
+
+				chlg := &acme.Challenge{Type: "http-01", Status: acme.StatusValid, URL: "http://example.com/", Token: "token"}
+
+				servermock.JSONEncode(chlg).ServeHTTP(rw, req)
+			})).
+		BuildHTTPS(t)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	chlg := acme.Challenge{Type: "http-01", Token: "token", URL: server.URL + "/chlg"}
+
+	var events []string
+
+	manager := NewSolversManager(core)
+	manager.SetValidationHooks(
+		func(event ValidationEvent) error {
+			events = append(events, fmt.Sprintf("pre %s %s %d", event.Domain, event.Challenge.Token, validated))
+			return nil
+		},
+		func(event ValidationEvent) error {
+			events = append(events, fmt.Sprintf("post %s %v %d", event.Domain, event.Err, validated))
+			return errors.New("ignored")
+		},
+	)
+
+	err = manager.hookedValidate(core, "example.com", chlg)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"pre example.com token 0", "post example.com <nil> 1"}, events)
+
+	// The pre-validation hook aborts the validation.
+	manager.SetValidationHooks(func(_ ValidationEvent) error { return errors.New("boom") }, nil)
+
+	err = manager.hookedValidate(core, "example.com", chlg)
+	require.EqualError(t, err, "[example.com] pre-validation hook: boom")
+
+	assert.Equal(t, 1, validated)
+}
+
 func Test_checkChallengeStatus(t *testing.T) {
 	testCases := []struct {
 		desc       string
@@ -180,6 +449,24 @@ func Test_checkChallengeStatus(t *testing.T) {
 	}
 }
 
+func Test_checkChallengeStatus_errorCode(t *testing.T) {
+	_, err := checkChallengeStatus(acme.ExtendedChallenge{Challenge: acme.Challenge{Status: acme.StatusInvalid}})
+	require.Error(t, err)
+
+	assert.Equal(t, errcode.ChallengeInvalid, errcode.Of(err))
+
+	chlng := acme.Challenge{
+		Status: acme.StatusInvalid,
+		Error:  &acme.ProblemDetails{Type: "urn:ietf:params:acme:error:dns", Detail: "DNS problem"},
+	}
+
+	_, err = checkChallengeStatus(acme.ExtendedChallenge{Challenge: chlng})
+	require.Error(t, err)
+
+	// The code of the CA takes precedence.
+	assert.Equal(t, errcode.CADNS, errcode.Of(err))
+}
+
 func Test_checkAuthorizationStatus(t *testing.T) {
 	testCases := []struct {
 		desc          string
@@ -254,29 +541,33 @@ func Test_checkAuthorizationStatus(t *testing.T) {
 // or if the JWS body is not the empty JSON payload "{}" or a POST-as-GET payload "" an error is returned.
 // We use this to verify challenge POSTs to the ts below do not send a JWS body.
 func validateNoBody(privateKey *rsa.PrivateKey, r *http.Request) error {
-	reqBody, err := io.ReadAll(r.Body)
+	body, err := readJWSBody(privateKey, r)
 	if err != nil {
 		return err
 	}
 
+	if bodyStr := string(body); bodyStr != "{}" && bodyStr != "" {
+		return fmt.Errorf(`expected JWS POST body "{}" or "", got %q`, bodyStr)
+	}
+
+	return nil
+}
+
+func readJWSBody(privateKey *rsa.PrivateKey, r *http.Request) ([]byte, error) {
+	reqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
 	sigAlgs := []jose.SignatureAlgorithm{jose.RS256}
 
 	jws, err := jose.ParseSigned(string(reqBody), sigAlgs)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	body, err := jws.Verify(&jose.JSONWebKey{
+	return jws.Verify(&jose.JSONWebKey{
 		Key:       privateKey.Public(),
 		Algorithm: "RSA",
 	})
-	if err != nil {
-		return err
-	}
-
-	if bodyStr := string(body); bodyStr != "{}" && bodyStr != "" {
-		return fmt.Errorf(`expected JWS POST body "{}" or "", got %q`, bodyStr)
-	}
-
-	return nil
 }
@@ -0,0 +1,78 @@
+package resolver
+
+import (
+	"fmt"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// ValidationEvent the challenge of a validation hook.
+type ValidationEvent struct {
+	// Domain the targeted domain (i.e. `*.example.com` for a wildcard).
+	Domain string
+	// Challenge the challenge presented by the solver (type, token, URL).
+	Challenge acme.Challenge
+	// Err the result of the validation (post-validation hook only).
+	Err error
+}
+
+// ValidationHook is called around the validation request of a challenge to the CA.
+//
+// The pre-validation hook is called when the challenge is presented (and the propagation checks are done),
+// before the CA is asked to validate the challenge (ex: flush of the CDN caches, wait for the convergence of an anycast DNS).
+// An error of the pre-validation hook aborts the validation: the challenge is cleaned up, and the error is reported for the domain.
+//
+// The post-validation hook is called after the validation, before the cleanup of the challenge.
+// The validation is already done: an error of the post-validation hook is only logged.
+type ValidationHook func(event ValidationEvent) error
+
+// SetValidationHooks defines the hooks called before and after the validation request of each challenge.
+// A nil hook is not called.
+func (c *SolverManager) SetValidationHooks(pre, post ValidationHook) {
+	c.solversMu.Lock()
+	defer c.solversMu.Unlock()
+
+	c.preValidate = pre
+	c.postValidate = post
+}
+
+// hookedValidate is the validate function of the solvers: validate with the validation hooks.
+func (c *SolverManager) hookedValidate(core *api.Core, domain string, chlg acme.Challenge) error {
+	return c.withValidationHooks(domain, chlg, func() error {
+		return validate(core, domain, chlg)
+	})
+}
+
+// hookedValidateWithPayload is the validate function of the solvers with a challenge-specific response:
+// validateWithPayload with the validation hooks.
+func (c *SolverManager) hookedValidateWithPayload(core *api.Core, domain string, chlg acme.Challenge, payload any) error {
+	return c.withValidationHooks(domain, chlg, func() error {
+		return validateWithPayload(core, domain, chlg, payload)
+	})
+}
+
+func (c *SolverManager) withValidationHooks(domain string, chlg acme.Challenge, validateFn func() error) error {
+	c.solversMu.RLock()
+	pre, post := c.preValidate, c.postValidate
+	c.solversMu.RUnlock()
+
+	if pre != nil {
+		err := pre(ValidationEvent{Domain: domain, Challenge: chlg})
+		if err != nil {
+			return fmt.Errorf("[%s] pre-validation hook: %w", domain, err)
+		}
+	}
+
+	err := validateFn()
+
+	if post != nil {
+		errP := post(ValidationEvent{Domain: domain, Challenge: chlg, Err: err})
+		if errP != nil {
+			log.Warnf("[%s] post-validation hook: %v", domain, errP)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,169 @@
+// Package tkauth01 implements the tkauth-01 challenge (ACME Challenges Using an Authority Token),
+// used to obtain the STIR/SHAKEN certificates (SPC tokens, TNAuthList identifiers).
+//
+// The token is provided by a TokenFetcher (ex: a client of the STI-PA API):
+// lego only answers the challenge with the token.
+//
+// - https://www.rfc-editor.org/rfc/rfc9447.html
+// - https://www.rfc-editor.org/rfc/rfc9448.html
+package tkauth01
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/errcode"
+)
+
+// IdentifierType the type of the TNAuthList identifiers.
+// https://www.rfc-editor.org/rfc/rfc9448.html#section-3
+const IdentifierType = "TNAuthList"
+
+// TokenTypeATC the type of the Authority Token for TNAuthList identifiers.
+// https://www.rfc-editor.org/rfc/rfc9448.html#section-4
+const TokenTypeATC = "atc"
+
+type ValidateFunc func(core *api.Core, domain string, chlng acme.Challenge, payload any) error
+
+// TokenRequest the information to request a token from the token authority.
+type TokenRequest struct {
+	// Identifier the identifier of the authorization (ex: a TNAuthList identifier).
+	Identifier acme.Identifier
+
+	// TokenType the type of token requested by the CA (tkauth-type, ex: "atc").
+	TokenType string
+
+	// TokenAuthority the URL of the token authority suggested by the CA (optional).
+	TokenAuthority string
+
+	// Fingerprint the fingerprint of the account key, to bind the token to the ACME account
+	// (ex: `SHA256 56:3E:CF:...`).
+	Fingerprint string
+}
+
+// TokenFetcher fetches the tokens from a token authority.
+type TokenFetcher interface {
+	FetchToken(ctx context.Context, request TokenRequest) (string, error)
+}
+
+// TokenFetcherFunc is an adapter to allow the use of ordinary functions as TokenFetcher.
+type TokenFetcherFunc func(ctx context.Context, request TokenRequest) (string, error)
+
+// FetchToken calls f(ctx, request).
+func (f TokenFetcherFunc) FetchToken(ctx context.Context, request TokenRequest) (string, error) {
+	return f(ctx, request)
+}
+
+type ChallengeOption func(*Challenge) error
+
+// Response the response to the tkauth-01 challenge with an Authority Token of type atc.
+// https://www.rfc-editor.org/rfc/rfc9448.html#section-7
+type Response struct {
+	ATC string `json:"atc"`
+}
+
+type Challenge struct {
+	core     *api.Core
+	validate ValidateFunc
+	fetcher  TokenFetcher
+}
+
+func NewChallenge(core *api.Core, validate ValidateFunc, fetcher TokenFetcher, opts ...ChallengeOption) *Challenge {
+	chlg := &Challenge{
+		core:     core,
+		validate: validate,
+		fetcher:  fetcher,
+	}
+
+	for _, opt := range opts {
+		err := opt(chlg)
+		if err != nil {
+			log.Infof("challenge option error: %v", err)
+		}
+	}
+
+	return chlg
+}
+
+// Solve fetches the token with the TokenFetcher, and sends it to the CA.
+func (c *Challenge) Solve(authz acme.Authorization) error {
+	return c.SolveContext(context.Background(), authz)
+}
+
+// SolveContext is like Solve, the context is provided to the TokenFetcher.
+func (c *Challenge) SolveContext(ctx context.Context, authz acme.Authorization) error {
+	domain := challenge.GetTargetedDomain(authz)
+	log.Infof("[%s] acme: Trying to solve TKAUTH-01", domain)
+
+	chlng, err := challenge.FindChallenge(challenge.TKAuth01, authz)
+	if err != nil {
+		return err
+	}
+
+	if c.fetcher == nil {
+		return fmt.Errorf("[%s] acme: no token fetcher configured", domain)
+	}
+
+	tokenType := chlng.TKAuthType
+	if tokenType == "" {
+		tokenType = TokenTypeATC
+	}
+
+	if tokenType != TokenTypeATC {
+		return errcode.Wrap(errcode.ChallengeUnsupported, fmt.Errorf("[%s] acme: unsupported token type %q", domain, tokenType))
+	}
+
+	thumbprint, err := c.core.GetKeyThumbprint()
+	if err != nil {
+		return fmt.Errorf("[%s] acme: %w", domain, err)
+	}
+
+	fingerprint, err := Fingerprint(thumbprint)
+	if err != nil {
+		return fmt.Errorf("[%s] acme: %w", domain, err)
+	}
+
+	token, err := c.fetcher.FetchToken(ctx, TokenRequest{
+		Identifier:     authz.Identifier,
+		TokenType:      tokenType,
+		TokenAuthority: chlng.TokenAuthority,
+		Fingerprint:    fingerprint,
+	})
+	if err != nil {
+		return errcode.Wrap(errcode.ChallengePresent, fmt.Errorf("[%s] acme: fetch token: %w", domain, err))
+	}
+
+	return c.validate(c.core, authz.Identifier.Value, chlng, Response{ATC: token})
+}
+
+// NewIdentifier creates a TNAuthList identifier from the DER encoding of a TNAuthList (RFC 8226).
+// https://www.rfc-editor.org/rfc/rfc9448.html#section-3
+func NewIdentifier(tnAuthList []byte) acme.Identifier {
+	return acme.Identifier{
+		Type:  IdentifierType,
+		Value: base64.RawURLEncoding.EncodeToString(tnAuthList),
+	}
+}
+
+// Fingerprint formats the JWK thumbprint of the account key (base64url-encoded, see api.Core.GetKeyThumbprint)
+// as the fingerprint of the Authority Token: `SHA256 ` followed by the hexadecimal bytes separated by colons.
+// https://www.rfc-editor.org/rfc/rfc9448.html#section-4
+func Fingerprint(thumbprint string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(thumbprint)
+	if err != nil {
+		return "", fmt.Errorf("invalid thumbprint: %w", err)
+	}
+
+	parts := make([]string, 0, len(raw))
+	for _, b := range raw {
+		parts = append(parts, fmt.Sprintf("%02X", b))
+	}
+
+	return "SHA256 " + strings.Join(parts, ":"), nil
+}
@@ -0,0 +1,132 @@
+package tkauth01
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/errcode"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCore(t *testing.T) (*api.Core, *rsa.PrivateKey) {
+	t.Helper()
+
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	accountKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", accountKey)
+	require.NoError(t, err)
+
+	return core, accountKey
+}
+
+func TestChallenge(t *testing.T) {
+	core, accountKey := setupCore(t)
+
+	thumbprint, err := challenge.Thumbprint(accountKey)
+	require.NoError(t, err)
+
+	expectedFingerprint, err := Fingerprint(thumbprint)
+	require.NoError(t, err)
+
+	identifier := NewIdentifier([]byte{0x30, 0x08, 0xa0, 0x06, 0x16, 0x04, 0x31, 0x32, 0x33, 0x34})
+
+	fetcher := TokenFetcherFunc(func(_ context.Context, request TokenRequest) (string, error) {
+		assert.Equal(t, TokenRequest{
+			Identifier:     identifier,
+			TokenType:      TokenTypeATC,
+			TokenAuthority: "https://authority.example.org",
+			Fingerprint:    expectedFingerprint,
+		}, request)
+
+		return "eyJhbGciOiJFUzI1NiJ9.token.signature", nil
+	})
+
+	mockValidate := func(_ *api.Core, _ string, _ acme.Challenge, payload any) error {
+		assert.Equal(t, Response{ATC: "eyJhbGciOiJFUzI1NiJ9.token.signature"}, payload)
+
+		return nil
+	}
+
+	solver := NewChallenge(core, mockValidate, fetcher)
+
+	authz := acme.Authorization{
+		Identifier: identifier,
+		Challenges: []acme.Challenge{
+			{Type: "tkauth-01", TKAuthType: "atc", TokenAuthority: "https://authority.example.org", Token: "IlirfxKKXAsHtmzK29Pj8A"},
+		},
+	}
+
+	err = solver.Solve(authz)
+	require.NoError(t, err)
+}
+
+func TestChallenge_fetchError(t *testing.T) {
+	core, _ := setupCore(t)
+
+	fetcher := TokenFetcherFunc(func(_ context.Context, _ TokenRequest) (string, error) {
+		return "", errors.New("unauthorized")
+	})
+
+	mockValidate := func(_ *api.Core, _ string, _ acme.Challenge, _ any) error {
+		return errors.New("validate must not be called")
+	}
+
+	solver := NewChallenge(core, mockValidate, fetcher)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Type: IdentifierType, Value: "MAigBhYEMTIzNA"},
+		Challenges: []acme.Challenge{{Type: "tkauth-01", TKAuthType: "atc"}},
+	}
+
+	err := solver.Solve(authz)
+	require.EqualError(t, err, "[MAigBhYEMTIzNA] acme: fetch token: unauthorized")
+
+	assert.Equal(t, errcode.ChallengePresent, errcode.Of(err))
+}
+
+func TestChallenge_unsupportedTokenType(t *testing.T) {
+	core, _ := setupCore(t)
+
+	fetcher := TokenFetcherFunc(func(_ context.Context, _ TokenRequest) (string, error) {
+		return "", errors.New("fetch must not be called")
+	})
+
+	solver := NewChallenge(core, nil, fetcher)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Type: IdentifierType, Value: "MAigBhYEMTIzNA"},
+		Challenges: []acme.Challenge{{Type: "tkauth-01", TKAuthType: "jwt"}},
+	}
+
+	err := solver.Solve(authz)
+	require.EqualError(t, err, `[MAigBhYEMTIzNA] acme: unsupported token type "jwt"`)
+
+	assert.Equal(t, errcode.ChallengeUnsupported, errcode.Of(err))
+}
+
+func TestNewIdentifier(t *testing.T) {
+	identifier := NewIdentifier([]byte{0x30, 0x08, 0xa0, 0x06, 0x16, 0x04, 0x31, 0x32, 0x33, 0x34})
+
+	assert.Equal(t, acme.Identifier{Type: "TNAuthList", Value: "MAigBhYEMTIzNA"}, identifier)
+}
+
+func TestFingerprint(t *testing.T) {
+	fingerprint, err := Fingerprint("AAECAw")
+	require.NoError(t, err)
+
+	assert.Equal(t, "SHA256 00:01:02:03", fingerprint)
+
+	_, err = Fingerprint("!")
+	require.Error(t, err)
+}
@@ -0,0 +1,212 @@
+package tlsalpn01
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// proxyProtocolHeaderTimeout the maximum duration to receive the PROXY protocol header.
+const proxyProtocolHeaderTimeout = 10 * time.Second
+
+// proxyProtocolV2Signature the signature of the PROXY protocol version 2 header.
+// https://www.haproxy.org/download/2.9/doc/proxy-protocol.txt
+var proxyProtocolV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+// proxyProtocolListener a listener expecting a PROXY protocol header (version 1 or 2) at the beginning of each connection.
+type proxyProtocolListener struct {
+	net.Listener
+}
+
+func (l *proxyProtocolListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	return &proxyProtocolConn{Conn: conn, reader: bufio.NewReader(conn)}, nil
+}
+
+// proxyProtocolConn reads the PROXY protocol header before the first read:
+// the header is not read during Accept, to not block the other connections.
+type proxyProtocolConn struct {
+	net.Conn
+
+	reader *bufio.Reader
+
+	once sync.Once
+	err  error
+
+	mu         sync.Mutex
+	remoteAddr net.Addr
+}
+
+func (c *proxyProtocolConn) Read(b []byte) (int, error) {
+	c.once.Do(c.readHeader)
+
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	return c.reader.Read(b)
+}
+
+// RemoteAddr returns the address of the client defined by the PROXY protocol header, once the header is read.
+func (c *proxyProtocolConn) RemoteAddr() net.Addr {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.remoteAddr != nil {
+		return c.remoteAddr
+	}
+
+	return c.Conn.RemoteAddr()
+}
+
+func (c *proxyProtocolConn) readHeader() {
+	_ = c.SetReadDeadline(time.Now().Add(proxyProtocolHeaderTimeout))
+
+	addr, err := readProxyProtocolHeader(c.reader)
+	if err != nil {
+		c.err = fmt.Errorf("PROXY protocol: %w", err)
+
+		return
+	}
+
+	_ = c.SetReadDeadline(time.Time{})
+
+	c.mu.Lock()
+	c.remoteAddr = addr
+	c.mu.Unlock()
+}
+
+// readProxyProtocolHeader reads a PROXY protocol header (version 1 or 2),
+// and returns the source address (nil if the connection is not proxied, i.e. the health checks of the proxy).
+func readProxyProtocolHeader(r *bufio.Reader) (net.Addr, error) {
+	signature, err := r.Peek(len(proxyProtocolV2Signature))
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	if bytes.Equal(signature, proxyProtocolV2Signature) {
+		return readProxyProtocolV2(r)
+	}
+
+	if bytes.HasPrefix(signature, []byte("PROXY ")) {
+		return readProxyProtocolV1(r)
+	}
+
+	return nil, errors.New("missing header")
+}
+
+// readProxyProtocolV1 reads the human-readable header (ex: "PROXY TCP4 192.0.2.1 192.0.2.2 56324 443\r\n").
+func readProxyProtocolV1(r *bufio.Reader) (net.Addr, error) {
+	// The maximum length of a version 1 header.
+	const maxLength = 107
+
+	var line []byte
+
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("read header: %w", err)
+		}
+
+		line = append(line, b)
+
+		if b == '\n' {
+			break
+		}
+
+		if len(line) >= maxLength {
+			return nil, errors.New("header too long")
+		}
+	}
+
+	header, ok := strings.CutSuffix(string(line), "\r\n")
+	if !ok {
+		return nil, errors.New("invalid header: missing CRLF")
+	}
+
+	fields := strings.Split(header, " ")
+
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil
+	}
+
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, fmt.Errorf("invalid header: %q", header)
+	}
+
+	ip := net.ParseIP(fields[2])
+	if ip == nil {
+		return nil, fmt.Errorf("invalid source address: %q", fields[2])
+	}
+
+	port, err := strconv.ParseUint(fields[4], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source port: %q", fields[4])
+	}
+
+	return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+}
+
+// readProxyProtocolV2 reads the binary header.
+func readProxyProtocolV2(r *bufio.Reader) (net.Addr, error) {
+	header := make([]byte, 16)
+
+	_, err := io.ReadFull(r, header)
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	version, command := header[12]>>4, header[12]&0x0F
+	if version != 2 {
+		return nil, fmt.Errorf("unsupported version: %d", version)
+	}
+
+	family := header[13]
+
+	payload := make([]byte, binary.BigEndian.Uint16(header[14:16]))
+
+	_, err = io.ReadFull(r, payload)
+	if err != nil {
+		return nil, fmt.Errorf("read addresses: %w", err)
+	}
+
+	switch command {
+	case 0x0: // LOCAL
+		return nil, nil
+	case 0x1: // PROXY
+	default:
+		return nil, fmt.Errorf("unsupported command: %d", command)
+	}
+
+	switch family {
+	case 0x11: // TCP over IPv4
+		if len(payload) < 12 {
+			return nil, errors.New("invalid IPv4 addresses")
+		}
+
+		return &net.TCPAddr{IP: net.IP(payload[0:4]), Port: int(binary.BigEndian.Uint16(payload[8:10]))}, nil
+
+	case 0x21: // TCP over IPv6
+		if len(payload) < 36 {
+			return nil, errors.New("invalid IPv6 addresses")
+		}
+
+		return &net.TCPAddr{IP: net.IP(payload[0:16]), Port: int(binary.BigEndian.Uint16(payload[32:34]))}, nil
+
+	default:
+		// UNSPEC, UDP, or unix sockets: the address of the client is unknown.
+		return nil, nil
+	}
+}
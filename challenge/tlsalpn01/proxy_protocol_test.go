@@ -0,0 +1,147 @@
+package tlsalpn01
+
+import (
+	"bufio"
+	"crypto/tls"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_readProxyProtocolHeader(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		header   string
+		expected net.Addr
+		rest     string
+	}{
+		{
+			desc:     "v1 TCP4",
+			header:   "PROXY TCP4 192.0.2.1 192.0.2.2 56324 443\r\n",
+			expected: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 56324},
+			rest:     "data",
+		},
+		{
+			desc:     "v1 TCP6",
+			header:   "PROXY TCP6 2001:db8::1 2001:db8::2 56324 443\r\n",
+			expected: &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 56324},
+			rest:     "data",
+		},
+		{
+			desc:   "v1 UNKNOWN",
+			header: "PROXY UNKNOWN\r\n",
+			rest:   "data",
+		},
+		{
+			desc: "v2 TCP4",
+			header: string(proxyProtocolV2Signature) + "\x21\x11\x00\x0c" +
+				"\xc0\x00\x02\x01" + "\xc0\x00\x02\x02" + "\xdc\x04" + "\x01\xbb",
+			expected: &net.TCPAddr{IP: net.IP{192, 0, 2, 1}, Port: 56324},
+			rest:     "data",
+		},
+		{
+			desc:   "v2 LOCAL",
+			header: string(proxyProtocolV2Signature) + "\x20\x00\x00\x00",
+			rest:   "data",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			r := bufio.NewReader(strings.NewReader(test.header + test.rest))
+
+			addr, err := readProxyProtocolHeader(r)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, addr)
+
+			rest, err := r.ReadString(0)
+			require.Error(t, err) // EOF
+
+			assert.Equal(t, test.rest, rest)
+		})
+	}
+}
+
+func Test_readProxyProtocolHeader_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		header   string
+		expected string
+	}{
+		{
+			desc:     "missing header",
+			header:   "\x16\x03\x01\x02\x00\x01\x00\x01\xfc\x03\x03\x00",
+			expected: "missing header",
+		},
+		{
+			desc:     "v1 invalid protocol",
+			header:   "PROXY UDP4 192.0.2.1 192.0.2.2 56324 443\r\n",
+			expected: `invalid header: "PROXY UDP4 192.0.2.1 192.0.2.2 56324 443"`,
+		},
+		{
+			desc:     "v1 too long",
+			header:   "PROXY " + strings.Repeat("A", 120) + "\r\n",
+			expected: "header too long",
+		},
+		{
+			desc:     "v2 invalid version",
+			header:   string(proxyProtocolV2Signature) + "\x11\x00\x00\x00",
+			expected: "unsupported version: 1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := readProxyProtocolHeader(bufio.NewReader(strings.NewReader(test.header)))
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestProviderServer_SetProxyProtocol(t *testing.T) {
+	domain := "localhost"
+	port := "24459"
+
+	srv := NewProviderServer(domain, port)
+	srv.SetProxyProtocol(true)
+
+	err := srv.Present(domain, "token", "keyAuth")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = srv.CleanUp(domain, "token", "keyAuth") })
+
+	rawConn, err := net.Dial("tcp", net.JoinHostPort(domain, port))
+	require.NoError(t, err)
+
+	defer func() { _ = rawConn.Close() }()
+
+	_, err = rawConn.Write([]byte("PROXY TCP4 192.0.2.1 192.0.2.2 56324 443\r\n"))
+	require.NoError(t, err)
+
+	conn := tls.Client(rawConn, &tls.Config{
+		ServerName:         domain,
+		InsecureSkipVerify: true,
+		NextProtos:         []string{ACMETLS1Protocol},
+	})
+
+	err = conn.Handshake()
+	require.NoError(t, err)
+
+	assert.Equal(t, ACMETLS1Protocol, conn.ConnectionState().NegotiatedProtocol)
+
+	// Without the header, the connection is rejected.
+	_, err = tls.Dial("tcp", net.JoinHostPort(domain, port), &tls.Config{
+		ServerName:         domain,
+		InsecureSkipVerify: true,
+		NextProtos:         []string{ACMETLS1Protocol},
+	})
+	require.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package tlsalpn01
 
 import (
+	"context"
 	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/tls"
@@ -14,6 +15,8 @@ import (
 	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/errcode"
+	"github.com/go-acme/lego/v4/platform/redact"
 )
 
 // idPeAcmeIdentifierV1 is the SMI Security for PKIX Certification Extension OID referencing the ACME extension.
@@ -60,8 +63,20 @@ func (c *Challenge) SetProvider(provider challenge.Provider) {
 	c.provider = provider
 }
 
+// MaxConcurrency returns the maximum number of challenges presented concurrently with the provider
+// (see challenge.ProviderConcurrency), 0 means no limit.
+func (c *Challenge) MaxConcurrency() int {
+	return challenge.MaxConcurrency(c.provider)
+}
+
 // Solve manages the provider to validate and solve the challenge.
 func (c *Challenge) Solve(authz acme.Authorization) error {
+	return c.SolveContext(context.Background(), authz)
+}
+
+// SolveContext is like Solve, the context cancels the presentation of the challenge
+// if the provider implements challenge.ProviderContext.
+func (c *Challenge) SolveContext(ctx context.Context, authz acme.Authorization) error {
 	domain := authz.Identifier.Value
 	log.Infof("[%s] acme: Trying to solve TLS-ALPN-01", challenge.GetTargetedDomain(authz))
 
@@ -76,13 +91,14 @@ func (c *Challenge) Solve(authz acme.Authorization) error {
 		return err
 	}
 
-	err = c.provider.Present(domain, chlng.Token, keyAuth)
+	err = challenge.Present(ctx, c.provider, domain, chlng.Token, keyAuth)
 	if err != nil {
-		return fmt.Errorf("[%s] acme: error presenting token: %w", challenge.GetTargetedDomain(authz), err)
+		return redact.Error(errcode.Wrap(errcode.ChallengePresent, fmt.Errorf("[%s] acme: error presenting token: %w", challenge.GetTargetedDomain(authz), err)))
 	}
 
 	defer func() {
-		err := c.provider.CleanUp(domain, chlng.Token, keyAuth)
+		// The challenge is cleaned up even if the context is canceled.
+		err := challenge.CleanUp(context.WithoutCancel(ctx), c.provider, domain, chlng.Token, keyAuth)
 		if err != nil {
 			log.Warnf("[%s] acme: cleaning up failed: %v", challenge.GetTargetedDomain(authz), err)
 		}
@@ -147,6 +163,8 @@ func ChallengeCert(domain, keyAuth string) (*tls.Certificate, error) {
 		return nil, err
 	}
 
+	defer certcrypto.Zeroize(rsaPrivatePEM)
+
 	cert, err := tls.X509KeyPair(tempCertPEM, rsaPrivatePEM)
 	if err != nil {
 		return nil, err
@@ -24,9 +24,13 @@ const (
 // It may be instantiated without using the NewProviderServer
 // if you want only to use the default values.
 type ProviderServer struct {
-	iface    string
-	port     string
-	listener net.Listener
+	iface     string
+	port      string
+	tlsConfig *tls.Config
+	listener  net.Listener
+
+	// expects a PROXY protocol header before the TLS handshake.
+	proxyProtocol bool
 }
 
 // NewProviderServer creates a new ProviderServer on the selected interface and port.
@@ -36,10 +40,29 @@ func NewProviderServer(iface, port string) *ProviderServer {
 	return &ProviderServer{iface: iface, port: port}
 }
 
+// SetTLSConfig sets the TLS configuration used by the server (minimum version, cipher suites, curve preferences, etc.).
+// The certificates and the application protocols are always defined by the server.
+func (s *ProviderServer) SetTLSConfig(config *tls.Config) {
+	s.tlsConfig = config
+}
+
+// SetProxyProtocol enables the PROXY protocol (versions 1 and 2):
+// the server is behind a load balancer prepending a PROXY protocol header to the connections,
+// the header is read before the TLS handshake.
+// When it's enabled, the connections without a header are rejected.
+func (s *ProviderServer) SetProxyProtocol(enabled bool) {
+	s.proxyProtocol = enabled
+}
+
 func (s *ProviderServer) GetAddress() string {
 	return net.JoinHostPort(s.iface, s.port)
 }
 
+// MaxConcurrency the server listens on a single port: the challenges are presented one by one.
+func (s *ProviderServer) MaxConcurrency() int {
+	return 1
+}
+
 // Present generates a certificate with an SHA-256 digest of the keyAuth provided
 // as the acmeValidation-v1 extension value to conform to the ACME-TLS-ALPN spec.
 func (s *ProviderServer) Present(domain, token, keyAuth string) error {
@@ -57,6 +80,10 @@ func (s *ProviderServer) Present(domain, token, keyAuth string) error {
 	// Place the generated certificate with the extension into the TLS config
 	// so that it can serve the correct details.
 	tlsConf := new(tls.Config)
+	if s.tlsConfig != nil {
+		tlsConf = s.tlsConfig.Clone()
+	}
+
 	tlsConf.Certificates = []tls.Certificate{*cert}
 
 	// We must set that the `acme-tls/1` application level protocol is supported
@@ -64,12 +91,18 @@ func (s *ProviderServer) Present(domain, token, keyAuth string) error {
 	// https://www.rfc-editor.org/rfc/rfc8737.html#section-6.2
 	tlsConf.NextProtos = []string{ACMETLS1Protocol}
 
-	// Create the listener with the created tls.Config.
-	s.listener, err = tls.Listen("tcp", s.GetAddress(), tlsConf)
+	listener, err := net.Listen("tcp", s.GetAddress())
 	if err != nil {
 		return fmt.Errorf("could not start HTTPS server for challenge: %w", err)
 	}
 
+	if s.proxyProtocol {
+		listener = &proxyProtocolListener{Listener: listener}
+	}
+
+	// Create the listener with the created tls.Config.
+	s.listener = tls.NewListener(listener, tlsConf)
+
 	// Shut the server down when we're finished.
 	go func() {
 		err := http.Serve(s.listener, nil)
@@ -197,3 +197,36 @@ func TestChallengeIPaddress(t *testing.T) {
 
 	require.NoError(t, solver.Solve(authz))
 }
+
+func TestProviderServer_SetTLSConfig(t *testing.T) {
+	domain := "localhost"
+	port := "24458"
+
+	srv := NewProviderServer(domain, port)
+	srv.SetTLSConfig(&tls.Config{MinVersion: tls.VersionTLS13})
+
+	err := srv.Present(domain, "token", "keyAuth")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = srv.CleanUp(domain, "token", "keyAuth") })
+
+	_, err = tls.Dial("tcp", net.JoinHostPort(domain, port), &tls.Config{
+		ServerName:         domain,
+		InsecureSkipVerify: true,
+		MaxVersion:         tls.VersionTLS12,
+		NextProtos:         []string{ACMETLS1Protocol},
+	})
+	require.Error(t, err)
+
+	conn, err := tls.Dial("tcp", net.JoinHostPort(domain, port), &tls.Config{
+		ServerName:         domain,
+		InsecureSkipVerify: true,
+		NextProtos:         []string{ACMETLS1Protocol},
+	})
+	require.NoError(t, err)
+
+	defer func() { _ = conn.Close() }()
+
+	assert.Equal(t, uint16(tls.VersionTLS13), conn.ConnectionState().Version)
+	assert.Equal(t, ACMETLS1Protocol, conn.ConnectionState().NegotiatedProtocol)
+}
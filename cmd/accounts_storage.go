@@ -2,28 +2,33 @@ package cmd
 
 import (
 	"crypto"
-	"encoding/json"
 	"encoding/pem"
-	"net/url"
 	"os"
 	"path/filepath"
-	"strings"
+	"time"
 
 	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/go-acme/lego/v4/lego"
 	"github.com/go-acme/lego/v4/log"
 	"github.com/go-acme/lego/v4/registration"
+	"github.com/go-acme/lego/v4/storage"
 	"github.com/urfave/cli/v2"
 )
 
 const userIDPlaceholder = "noemail@example.com"
 
 const (
-	baseAccountsRootFolderName = "accounts"
-	baseKeysFolderName         = "keys"
-	accountFileName            = "account.json"
+	baseAccountsRootFolderName = storage.AccountsDirName
+	baseKeysFolderName         = storage.KeysDirName
+	accountFileName            = storage.AccountFileName
+
+	// accountLastUsedFileName the file storing the date of the last use of an account (used by the gc command).
+	accountLastUsedFileName = storage.AccountLastUsedFileName
 )
 
+// accountLastUsedPrecision the minimal duration between two updates of the date of the last use of an account.
+const accountLastUsedPrecision = 24 * time.Hour
+
 // AccountsStorage A storage for account data.
 //
 // rootPath:
@@ -65,6 +70,9 @@ type AccountsStorage struct {
 	keysPath        string
 	accountFilePath string
 	ctx             *cli.Context
+
+	accounts *storage.Accounts
+	location storage.AccountLocation
 }
 
 // NewAccountsStorage Creates a new AccountsStorage.
@@ -77,24 +85,23 @@ func NewAccountsStorage(ctx *cli.Context) *AccountsStorage {
 		userID = userIDPlaceholder
 	}
 
-	serverURL, err := url.Parse(ctx.String(flgServer))
+	location, err := storage.NewAccountLocation(ctx.String(flgServer), userID)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	rootPath := filepath.Join(ctx.String(flgPath), baseAccountsRootFolderName)
-	serverPath := strings.NewReplacer(":", "_", "/", string(os.PathSeparator)).Replace(serverURL.Host)
-	accountsPath := filepath.Join(rootPath, serverPath)
-	rootUserPath := filepath.Join(accountsPath, userID)
+	accounts := storage.NewAccounts(ctx.String(flgPath))
 
 	return &AccountsStorage{
 		userID:          userID,
 		email:           email,
-		rootPath:        rootPath,
-		rootUserPath:    rootUserPath,
-		keysPath:        filepath.Join(rootUserPath, baseKeysFolderName),
-		accountFilePath: filepath.Join(rootUserPath, accountFileName),
+		rootPath:        accounts.RootPath,
+		rootUserPath:    accounts.UserPath(location),
+		keysPath:        accounts.KeysPath(location),
+		accountFilePath: accounts.AccountFilePath(location),
 		ctx:             ctx,
+		accounts:        accounts,
+		location:        location,
 	}
 }
 
@@ -126,28 +133,37 @@ func (s *AccountsStorage) GetEmail() string {
 }
 
 func (s *AccountsStorage) Save(account *Account) error {
-	jsonBytes, err := json.MarshalIndent(account, "", "\t")
+	err := s.accounts.SaveAccount(s.location, account)
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(s.accountFilePath, jsonBytes, filePerm)
+	s.MarkUsed()
+
+	return nil
 }
 
-func (s *AccountsStorage) LoadAccount(privateKey crypto.PrivateKey) *Account {
-	fileBytes, err := os.ReadFile(s.accountFilePath)
-	if err != nil {
-		log.Fatalf("Could not load file for account %s: %v", s.GetUserID(), err)
+// MarkUsed records the date of the last use of the account.
+// The date is only updated once a day, to not modify the storage at each command.
+func (s *AccountsStorage) MarkUsed() {
+	lastUsed, err := s.accounts.ReadLastUsed(s.location)
+	if err == nil && time.Since(lastUsed) < accountLastUsedPrecision {
+		return
 	}
 
-	var account Account
+	err = s.accounts.SaveLastUsed(s.location, time.Now())
+	if err != nil {
+		log.Warnf("Could not save the date of the last use of the account %s: %v", s.GetUserID(), err)
+	}
+}
 
-	err = json.Unmarshal(fileBytes, &account)
+func (s *AccountsStorage) LoadAccount(privateKey crypto.PrivateKey) *Account {
+	account, err := s.accounts.ReadAccount(s.location)
 	if err != nil {
-		log.Fatalf("Could not parse file for account %s: %v", s.GetUserID(), err)
+		log.Fatalf("Could not load file for account %s: %v", s.GetUserID(), err)
 	}
 
-	account.key = privateKey
+	account.Key = privateKey
 
 	if account.Registration == nil || account.Registration.Body.Status == "" {
 		reg, err := tryRecoverRegistration(s.ctx, privateKey)
@@ -157,17 +173,19 @@ func (s *AccountsStorage) LoadAccount(privateKey crypto.PrivateKey) *Account {
 
 		account.Registration = reg
 
-		err = s.Save(&account)
+		err = s.Save(account)
 		if err != nil {
 			log.Fatalf("Could not save account for %s. Registration is nil: %#v", s.GetUserID(), err)
 		}
 	}
 
-	return &account
+	s.MarkUsed()
+
+	return account
 }
 
 func (s *AccountsStorage) GetPrivateKey(keyType certcrypto.KeyType) crypto.PrivateKey {
-	accKeyPath := filepath.Join(s.keysPath, s.GetUserID()+".key")
+	accKeyPath := s.accountKeyPath()
 
 	if _, err := os.Stat(accKeyPath); os.IsNotExist(err) {
 		log.Printf("No key found for account %s. Generating a %s key.", s.GetUserID(), keyType)
@@ -183,7 +201,7 @@ func (s *AccountsStorage) GetPrivateKey(keyType certcrypto.KeyType) crypto.Priva
 		return privateKey
 	}
 
-	privateKey, err := loadPrivateKey(accKeyPath)
+	privateKey, err := s.accounts.ReadPrivateKey(s.location)
 	if err != nil {
 		log.Fatalf("Could not load RSA private key from file %s: %v", accKeyPath, err)
 	}
@@ -191,6 +209,10 @@ func (s *AccountsStorage) GetPrivateKey(keyType certcrypto.KeyType) crypto.Priva
 	return privateKey
 }
 
+func (s *AccountsStorage) accountKeyPath() string {
+	return s.accounts.KeyFilePath(s.location)
+}
+
 func (s *AccountsStorage) createKeysFolder() {
 	if err := createNonExistingFolder(s.keysPath); err != nil {
 		log.Fatalf("Could not check/create directory for account %s: %v", s.GetUserID(), err)
@@ -211,6 +233,8 @@ func generatePrivateKey(file string, keyType certcrypto.KeyType) (crypto.Private
 
 	pemKey := certcrypto.PEMBlock(privateKey)
 
+	defer certcrypto.Zeroize(pemKey.Bytes)
+
 	err = pem.Encode(certOut, pemKey)
 	if err != nil {
 		return nil, err
@@ -225,6 +249,8 @@ func loadPrivateKey(file string) (crypto.PrivateKey, error) {
 		return nil, err
 	}
 
+	defer certcrypto.Zeroize(keyBytes)
+
 	privateKey, err := certcrypto.ParsePEMPrivateKey(keyBytes)
 	if err != nil {
 		return nil, err
@@ -235,7 +261,7 @@ func loadPrivateKey(file string) (crypto.PrivateKey, error) {
 
 func tryRecoverRegistration(ctx *cli.Context, privateKey crypto.PrivateKey) (*registration.Resource, error) {
 	// couldn't load account but got a key. Try to look the account up.
-	config := lego.NewConfig(&Account{key: privateKey})
+	config := lego.NewConfig(&Account{Key: privateKey})
 	config.CADirURL = ctx.String(flgServer)
 	config.UserAgent = getUserAgent(ctx)
 
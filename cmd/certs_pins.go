@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// certificatePins the content of the pins file of a certificate (`.pins.json`).
+type certificatePins struct {
+	// Pins the SPKI pins of the current private key and of the previous private key (the current first).
+	// The list can be used as is by the pinning configurations (ex: `pin-sha256` of HPKP, the pin lists of the mobile applications).
+	Pins []string `json:"pins"`
+
+	Current  certificatePin   `json:"current"`
+	Previous *certificatePin  `json:"previous,omitempty"`
+	Issuers  []certificatePin `json:"issuers,omitempty"`
+}
+
+// certificatePin the pin and the fingerprint of a certificate.
+type certificatePin struct {
+	// SPKI the base64 SHA-256 of the public key (SPKI) of the certificate.
+	SPKI string `json:"spkiSha256"`
+	// Fingerprint the hexadecimal SHA-256 of the certificate (DER).
+	Fingerprint string    `json:"sha256Fingerprint"`
+	Serial      string    `json:"serial"`
+	Subject     string    `json:"subject"`
+	NotAfter    time.Time `json:"notAfter"`
+}
+
+func newCertificatePin(cert *x509.Certificate) certificatePin {
+	fingerprint := sha256.Sum256(cert.Raw)
+
+	return certificatePin{
+		SPKI:        spkiPin(cert),
+		Fingerprint: hex.EncodeToString(fingerprint[:]),
+		Serial:      formatSerial(cert),
+		Subject:     cert.Subject.String(),
+		NotAfter:    cert.NotAfter.UTC(),
+	}
+}
+
+// newCertificatePins computes the pins of a certificate resource.
+// The previous private key is the key of the current pins file, or of the current certificate, if the key has changed.
+func (s *CertificatesStorage) newCertificatePins(domain string, certRes *certificate.Resource) (*certificatePins, error) {
+	certificates, err := certcrypto.ParsePEMBundle(certRes.Certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	pins := &certificatePins{Current: newCertificatePin(certificates[0])}
+
+	issuers := certificates[1:]
+
+	if certRes.IssuerCertificate != nil {
+		issuers, err = certcrypto.ParsePEMBundle(certRes.IssuerCertificate)
+		if err != nil {
+			return nil, fmt.Errorf("issuer certificate: %w", err)
+		}
+	}
+
+	for _, issuer := range issuers {
+		pins.Issuers = append(pins.Issuers, newCertificatePin(issuer))
+	}
+
+	pins.Previous, err = s.previousCertificatePin(domain, pins.Current)
+	if err != nil {
+		log.Warnf("[%s] Unable to read the previous pins: %v", domain, err)
+	}
+
+	pins.Pins = []string{pins.Current.SPKI}
+
+	if pins.Previous != nil {
+		pins.Pins = append(pins.Pins, pins.Previous.SPKI)
+	}
+
+	return pins, nil
+}
+
+// previousCertificatePin returns the pin of the previous private key of a certificate.
+// The previous pin is kept as long as the private key is reused.
+func (s *CertificatesStorage) previousCertificatePin(domain string, current certificatePin) (*certificatePin, error) {
+	exists, err := s.Exists(domain, pinsExt)
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		raw, errR := s.ReadFile(domain, pinsExt)
+		if errR != nil {
+			return nil, errR
+		}
+
+		var existing certificatePins
+
+		err = json.Unmarshal(raw, &existing)
+		if err != nil {
+			return nil, err
+		}
+
+		if existing.Current.SPKI != current.SPKI {
+			return &existing.Current, nil
+		}
+
+		return existing.Previous, nil
+	}
+
+	exists, err = s.Exists(domain, certExt)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	certificates, err := s.ReadCertificate(domain, certExt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(certificates) == 0 {
+		return nil, errors.New("no certificate")
+	}
+
+	previous := newCertificatePin(certificates[0])
+	if previous.SPKI == current.SPKI {
+		return nil, nil
+	}
+
+	return &previous, nil
+}
+
+// WritePinsFile writes the pins file of a certificate.
+func (s *CertificatesStorage) WritePinsFile(domain string, pins *certificatePins) error {
+	raw, err := json.MarshalIndent(pins, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return s.WriteFile(domain, pinsExt, raw)
+}
+
+// spkiPin returns the SPKI pin of a certificate: the base64 SHA-256 of its public key.
+func spkiPin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPinsTestResource(t *testing.T, privateKey *rsa.PrivateKey) *certificate.Resource {
+	t.Helper()
+
+	certPEM, err := certcrypto.GeneratePemCert(privateKey, "example.com", nil)
+	require.NoError(t, err)
+
+	return &certificate.Resource{
+		Domain:      "example.com",
+		Certificate: certPEM,
+		PrivateKey:  certcrypto.PEMEncode(privateKey),
+	}
+}
+
+func readPinsTestFile(t *testing.T, certsStorage *CertificatesStorage) certificatePins {
+	t.Helper()
+
+	raw, err := certsStorage.ReadFile("example.com", pinsExt)
+	require.NoError(t, err)
+
+	var pins certificatePins
+
+	err = json.Unmarshal(raw, &pins)
+	require.NoError(t, err)
+
+	return pins
+}
+
+func TestCertificatesStorage_SaveResource_pins(t *testing.T) {
+	certsStorage := &CertificatesStorage{
+		rootPath:    t.TempDir(),
+		archivePath: t.TempDir(),
+		pins:        true,
+	}
+
+	var keys []*rsa.PrivateKey
+
+	for range 3 {
+		privateKey, err := certcrypto.GeneratePrivateKey(certcrypto.RSA2048)
+		require.NoError(t, err)
+
+		keys = append(keys, privateKey.(*rsa.PrivateKey))
+	}
+
+	// First certificate: no previous key.
+	first := newPinsTestResource(t, keys[0])
+	certsStorage.SaveResource(first)
+
+	pins := readPinsTestFile(t, certsStorage)
+
+	firstCert, err := certcrypto.ParsePEMCertificate(first.Certificate)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{spkiPin(firstCert)}, pins.Pins)
+	assert.Equal(t, newCertificatePin(firstCert), pins.Current)
+	assert.Nil(t, pins.Previous)
+
+	// Renewal with the same key: still no previous key.
+	certsStorage.SaveResource(newPinsTestResource(t, keys[0]))
+
+	pins = readPinsTestFile(t, certsStorage)
+
+	assert.Equal(t, []string{spkiPin(firstCert)}, pins.Pins)
+	assert.Nil(t, pins.Previous)
+
+	// Rotation of the key: the previous key is kept.
+	certsStorage.SaveResource(newPinsTestResource(t, keys[1]))
+
+	pins = readPinsTestFile(t, certsStorage)
+
+	require.NotNil(t, pins.Previous)
+	assert.Equal(t, spkiPin(firstCert), pins.Previous.SPKI)
+	assert.Equal(t, []string{pins.Current.SPKI, spkiPin(firstCert)}, pins.Pins)
+
+	secondPin := pins.Current.SPKI
+
+	// Renewal with the same key: the previous key is still kept.
+	certsStorage.SaveResource(newPinsTestResource(t, keys[1]))
+
+	pins = readPinsTestFile(t, certsStorage)
+
+	assert.Equal(t, []string{secondPin, spkiPin(firstCert)}, pins.Pins)
+
+	// Another rotation: the oldest key is dropped.
+	certsStorage.SaveResource(newPinsTestResource(t, keys[2]))
+
+	pins = readPinsTestFile(t, certsStorage)
+
+	assert.Equal(t, []string{pins.Current.SPKI, secondPin}, pins.Pins)
+}
+
+func TestCertificatesStorage_SaveResource_pinsFromCertificate(t *testing.T) {
+	certsStorage := &CertificatesStorage{
+		rootPath:    t.TempDir(),
+		archivePath: t.TempDir(),
+	}
+
+	privateKey, err := certcrypto.GeneratePrivateKey(certcrypto.RSA2048)
+	require.NoError(t, err)
+
+	previous := newPinsTestResource(t, privateKey.(*rsa.PrivateKey))
+
+	// The pins are not enabled: the certificate is written without pins file.
+	certsStorage.SaveResource(previous)
+
+	assert.False(t, certsStorage.ExistsFile("example.com", pinsExt))
+
+	certsStorage.pins = true
+
+	privateKey, err = certcrypto.GeneratePrivateKey(certcrypto.RSA2048)
+	require.NoError(t, err)
+
+	certsStorage.SaveResource(newPinsTestResource(t, privateKey.(*rsa.PrivateKey)))
+
+	pins := readPinsTestFile(t, certsStorage)
+
+	previousCert, err := certcrypto.ParsePEMCertificate(previous.Certificate)
+	require.NoError(t, err)
+
+	require.NotNil(t, pins.Previous)
+	assert.Equal(t, newCertificatePin(previousCert), *pins.Previous)
+	assert.Equal(t, []string{pins.Current.SPKI, spkiPin(previousCert)}, pins.Pins)
+}
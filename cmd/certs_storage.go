@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/x509"
 	"encoding/json"
 	"encoding/pem"
@@ -9,30 +10,29 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/go-acme/lego/v4/certificate"
 	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/storage"
 	"github.com/urfave/cli/v2"
-	"golang.org/x/net/idna"
-	"software.sslmate.com/src/go-pkcs12"
 )
 
 const (
-	baseCertificatesFolderName = "certificates"
-	baseArchivesFolderName     = "archives"
+	baseCertificatesFolderName = storage.CertificatesDirName
+	baseArchivesFolderName     = storage.ArchivesDirName
 )
 
 const (
-	issuerExt   = ".issuer.crt"
-	certExt     = ".crt"
-	keyExt      = ".key"
-	pemExt      = ".pem"
-	pfxExt      = ".pfx"
-	resourceExt = ".json"
+	issuerExt   = storage.ExtIssuer
+	certExt     = storage.ExtCertificate
+	keyExt      = storage.ExtKey
+	pemExt      = storage.ExtPEM
+	pfxExt      = storage.ExtPFX
+	jksExt      = storage.ExtJKS
+	pinsExt     = storage.ExtPins
+	resourceExt = storage.ExtResource
 )
 
 // CertificatesStorage a certificates' storage.
@@ -55,7 +55,14 @@ type CertificatesStorage struct {
 	pfx         bool
 	pfxPassword string
 	pfxFormat   string
+	jks         bool
+	jksPassword string
+	jksAlias    string
+	pins        bool
 	filename    string // Deprecated
+
+	// allows the replacement of a production certificate by a staging certificate.
+	allowStaging bool
 }
 
 // NewCertificatesStorage create a new certificates storage.
@@ -63,11 +70,15 @@ func NewCertificatesStorage(ctx *cli.Context) *CertificatesStorage {
 	pfxFormat := ctx.String(flgPFXFormat)
 
 	switch pfxFormat {
-	case "DES", "RC2", "SHA256":
+	case certcrypto.PKCS12DES, certcrypto.PKCS12RC2, certcrypto.PKCS12SHA256, certcrypto.PKCS12AES:
 	default:
 		log.Fatalf("Invalid PFX format: %s", pfxFormat)
 	}
 
+	if ctx.Bool(flgJKS) && len(ctx.String(flgJKSPass)) < 6 {
+		log.Fatalf("Invalid JKS password: the password must be at least 6 characters")
+	}
+
 	return &CertificatesStorage{
 		rootPath:    filepath.Join(ctx.String(flgPath), baseCertificatesFolderName),
 		archivePath: filepath.Join(ctx.String(flgPath), baseArchivesFolderName),
@@ -75,7 +86,13 @@ func NewCertificatesStorage(ctx *cli.Context) *CertificatesStorage {
 		pfx:         ctx.Bool(flgPFX),
 		pfxPassword: ctx.String(flgPFXPass),
 		pfxFormat:   pfxFormat,
+		jks:         ctx.Bool(flgJKS),
+		jksPassword: ctx.String(flgJKSPass),
+		jksAlias:    ctx.String(flgJKSAlias),
+		pins:        ctx.Bool(flgPins),
 		filename:    ctx.String(flgFilename),
+
+		allowStaging: ctx.Bool(flgIKnow),
 	}
 }
 
@@ -97,20 +114,50 @@ func (s *CertificatesStorage) GetRootPath() string {
 	return s.rootPath
 }
 
+// certificates returns the storage of the files of the certificates.
+func (s *CertificatesStorage) certificates() *storage.Certificates {
+	return &storage.Certificates{RootPath: s.rootPath, ArchivePath: s.archivePath}
+}
+
 func (s *CertificatesStorage) SaveResource(certRes *certificate.Resource) {
-	domain := certRes.Domain
+	s.SaveResourceAs(certRes.Domain, certRes)
+}
+
+// SaveResourceAs saves the certificate resource in the files of a certificate name (see certificateName).
+// The process exits on error: see WriteResource to handle the errors.
+func (s *CertificatesStorage) SaveResourceAs(domain string, certRes *certificate.Resource) {
+	err := s.WriteResource(domain, certRes)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// WriteResource writes the files of the certificate resource with a certificate name (see certificateName).
+func (s *CertificatesStorage) WriteResource(domain string, certRes *certificate.Resource) error {
+	// The pins must be computed before the replacement of the previous certificate.
+	var (
+		pins *certificatePins
+		err  error
+	)
+
+	if s.pins {
+		pins, err = s.newCertificatePins(domain, certRes)
+		if err != nil {
+			return fmt.Errorf("unable to compute the pins for domain %s: %w", domain, err)
+		}
+	}
 
 	// We store the certificate, private key and metadata in different files
 	// as web servers would not be able to work with a combined file.
-	err := s.WriteFile(domain, certExt, certRes.Certificate)
+	err = s.WriteFile(domain, certExt, certRes.Certificate)
 	if err != nil {
-		log.Fatalf("Unable to save Certificate for domain %s\n\t%v", domain, err)
+		return fmt.Errorf("unable to save Certificate for domain %s: %w", domain, err)
 	}
 
 	if certRes.IssuerCertificate != nil {
 		err = s.WriteFile(domain, issuerExt, certRes.IssuerCertificate)
 		if err != nil {
-			log.Fatalf("Unable to save IssuerCertificate for domain %s\n\t%v", domain, err)
+			return fmt.Errorf("unable to save IssuerCertificate for domain %s: %w", domain, err)
 		}
 	}
 
@@ -118,80 +165,117 @@ func (s *CertificatesStorage) SaveResource(certRes *certificate.Resource) {
 	if certRes.PrivateKey != nil {
 		err = s.WriteCertificateFiles(domain, certRes)
 		if err != nil {
-			log.Fatalf("Unable to save PrivateKey for domain %s\n\t%v", domain, err)
+			return fmt.Errorf("unable to save PrivateKey for domain %s: %w", domain, err)
 		}
-	} else if s.pem || s.pfx {
-		// we don't have the private key; can't write the .pem or .pfx file
-		log.Fatalf("Unable to save PEM or PFX without private key for domain %s. Are you using a CSR?", domain)
+	} else if s.pem || s.pfx || s.jks {
+		// we don't have the private key; can't write the .pem, .pfx, or .jks file
+		return fmt.Errorf("unable to save PEM, PFX, or JKS without private key for domain %s. Are you using a CSR?", domain)
 	}
 
 	jsonBytes, err := json.MarshalIndent(certRes, "", "\t")
 	if err != nil {
-		log.Fatalf("Unable to marshal CertResource for domain %s\n\t%v", domain, err)
+		return fmt.Errorf("unable to marshal CertResource for domain %s: %w", domain, err)
 	}
 
 	err = s.WriteFile(domain, resourceExt, jsonBytes)
 	if err != nil {
-		log.Fatalf("Unable to save CertResource for domain %s\n\t%v", domain, err)
+		return fmt.Errorf("unable to save CertResource for domain %s: %w", domain, err)
+	}
+
+	if pins != nil {
+		err = s.WritePinsFile(domain, pins)
+		if err != nil {
+			return fmt.Errorf("unable to save the pins for domain %s: %w", domain, err)
+		}
+	}
+
+	return nil
+}
+
+// checkStaging prevents the replacement of a certificate issued by a production CA
+// by a certificate issued by the staging environment of a CA (serverURL), unless it's explicitly allowed.
+// It's called before the order: no certificate is issued when the replacement is refused.
+func (s *CertificatesStorage) checkStaging(domain, serverURL string) error {
+	if !isStagingServer(serverURL) {
+		return nil
+	}
+
+	log.Warnf("[%s] The server %s is a staging CA: its certificates are not trusted by the browsers.", domain, serverURL)
+
+	exists, err := s.Exists(domain, certExt)
+	if err != nil || !exists {
+		return err
 	}
+
+	current, err := s.ReadCertificate(domain, certExt)
+	if err != nil || len(current) == 0 || isStagingCertificate(current[0]) {
+		return nil
+	}
+
+	if s.allowStaging {
+		log.Warnf("[%s] The production certificate %s is replaced by a staging certificate (--%s).",
+			domain, s.GetFileName(domain, certExt), flgIKnow)
+
+		return nil
+	}
+
+	return fmt.Errorf("[%s] refusing to replace the production certificate %s by a certificate issued by a staging CA, use --%s to force it",
+		domain, s.GetFileName(domain, certExt), flgIKnow)
 }
 
 func (s *CertificatesStorage) ReadResource(domain string) certificate.Resource {
-	raw, err := s.ReadFile(domain, resourceExt)
+	resource, err := s.LoadResource(domain)
 	if err != nil {
 		log.Fatalf("Error while loading the meta data for domain %s\n\t%v", domain, err)
 	}
 
-	var resource certificate.Resource
-	if err = json.Unmarshal(raw, &resource); err != nil {
-		log.Fatalf("Error while marshaling the meta data for domain %s\n\t%v", domain, err)
-	}
+	return *resource
+}
 
-	return resource
+// LoadResource reads the resource (metadata) of a certificate.
+func (s *CertificatesStorage) LoadResource(domain string) (*certificate.Resource, error) {
+	return s.certificates().ReadResource(domain)
 }
 
 func (s *CertificatesStorage) ExistsFile(domain, extension string) bool {
-	filePath := s.GetFileName(domain, extension)
-
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return false
-	} else if err != nil {
+	exists, err := s.Exists(domain, extension)
+	if err != nil {
 		log.Fatal(err)
 	}
 
-	return true
+	return exists
 }
 
-func (s *CertificatesStorage) ReadFile(domain, extension string) ([]byte, error) {
-	return os.ReadFile(s.GetFileName(domain, extension))
+// Exists checks if a file of a certificate exists.
+// The error is not nil if the domain can't be used as a file name.
+func (s *CertificatesStorage) Exists(domain, extension string) (bool, error) {
+	return s.certificates().Exists(domain, extension)
 }
 
-func (s *CertificatesStorage) GetFileName(domain, extension string) string {
-	filename := sanitizedDomain(domain) + extension
-	return filepath.Join(s.rootPath, filename)
+func (s *CertificatesStorage) ReadFile(domain, extension string) ([]byte, error) {
+	return s.certificates().ReadFile(domain, extension)
 }
 
-func (s *CertificatesStorage) ReadCertificate(domain, extension string) ([]*x509.Certificate, error) {
-	content, err := s.ReadFile(domain, extension)
+func (s *CertificatesStorage) GetFileName(domain, extension string) string {
+	filePath, err := s.certificates().FilePath(domain, extension)
 	if err != nil {
-		return nil, err
+		log.Fatal(err)
 	}
 
+	return filePath
+}
+
+func (s *CertificatesStorage) ReadCertificate(domain, extension string) ([]*x509.Certificate, error) {
 	// The input may be a bundle or a single certificate.
-	return certcrypto.ParsePEMBundle(content)
+	return s.certificates().ReadCertificates(domain, extension)
 }
 
 func (s *CertificatesStorage) WriteFile(domain, extension string, data []byte) error {
-	var baseFileName string
 	if s.filename != "" {
-		baseFileName = s.filename
-	} else {
-		baseFileName = sanitizedDomain(domain)
+		return os.WriteFile(filepath.Join(s.rootPath, s.filename+extension), data, filePerm)
 	}
 
-	filePath := filepath.Join(s.rootPath, baseFileName+extension)
-
-	return os.WriteFile(filePath, data, filePerm)
+	return s.certificates().WriteFile(domain, extension, data)
 }
 
 func (s *CertificatesStorage) WriteCertificateFiles(domain string, certRes *certificate.Resource) error {
@@ -201,7 +285,12 @@ func (s *CertificatesStorage) WriteCertificateFiles(domain string, certRes *cert
 	}
 
 	if s.pem {
-		err = s.WriteFile(domain, pemExt, bytes.Join([][]byte{certRes.Certificate, certRes.PrivateKey}, nil))
+		bundle := bytes.Join([][]byte{certRes.Certificate, certRes.PrivateKey}, nil)
+
+		err = s.WriteFile(domain, pemExt, bundle)
+
+		certcrypto.Zeroize(bundle)
+
 		if err != nil {
 			return fmt.Errorf("unable to save PEM file: %w", err)
 		}
@@ -214,67 +303,81 @@ func (s *CertificatesStorage) WriteCertificateFiles(domain string, certRes *cert
 		}
 	}
 
+	if s.jks {
+		err = s.WriteJKSFile(domain, certRes)
+		if err != nil {
+			return fmt.Errorf("unable to save JKS file: %w", err)
+		}
+	}
+
 	return nil
 }
 
 func (s *CertificatesStorage) WritePFXFile(domain string, certRes *certificate.Resource) error {
-	certPemBlock, _ := pem.Decode(certRes.Certificate)
-	if certPemBlock == nil {
-		return fmt.Errorf("unable to parse Certificate for domain %s", domain)
+	privateKey, chain, err := getKeyAndChain(domain, certRes)
+	if err != nil {
+		return err
 	}
 
-	cert, err := x509.ParseCertificate(certPemBlock.Bytes)
+	pfxBytes, err := certcrypto.EncodePKCS12(privateKey, chain, s.pfxFormat, s.pfxPassword)
 	if err != nil {
-		return fmt.Errorf("unable to load Certificate for domain %s: %w", domain, err)
+		return fmt.Errorf("unable to encode PFX data for domain %s: %w", domain, err)
 	}
 
-	certChain, err := getCertificateChain(certRes)
+	return s.WriteFile(domain, pfxExt, pfxBytes)
+}
+
+func (s *CertificatesStorage) WriteJKSFile(domain string, certRes *certificate.Resource) error {
+	privateKey, chain, err := getKeyAndChain(domain, certRes)
 	if err != nil {
-		return fmt.Errorf("unable to get certificate chain for domain %s: %w", domain, err)
+		return err
 	}
 
-	privateKey, err := certcrypto.ParsePEMPrivateKey(certRes.PrivateKey)
-	if err != nil {
-		return fmt.Errorf("unable to parse PrivateKey for domain %s: %w", domain, err)
+	alias := s.jksAlias
+	if alias == "" {
+		alias = domain
 	}
 
-	encoder, err := getPFXEncoder(s.pfxFormat)
+	jksBytes, err := certcrypto.EncodeJKS(privateKey, chain, alias, s.jksPassword)
 	if err != nil {
-		return fmt.Errorf("PFX encoder: %w", err)
+		return fmt.Errorf("unable to encode JKS data for domain %s: %w", domain, err)
 	}
 
-	pfxBytes, err := encoder.Encode(privateKey, cert, certChain, s.pfxPassword)
+	return s.WriteFile(domain, jksExt, jksBytes)
+}
+
+func (s *CertificatesStorage) MoveToArchive(domain string) error {
+	err := createNonExistingFolder(s.archivePath)
 	if err != nil {
-		return fmt.Errorf("unable to encode PFX data for domain %s: %w", domain, err)
+		return fmt.Errorf("could not check/create path: %w", err)
 	}
 
-	return s.WriteFile(domain, pfxExt, pfxBytes)
+	return s.certificates().Archive(domain, time.Now())
 }
 
-func (s *CertificatesStorage) MoveToArchive(domain string) error {
-	baseFilename := filepath.Join(s.rootPath, sanitizedDomain(domain))
+// getKeyAndChain returns the private key and the certificate chain (the leaf certificate first) of a certificate resource.
+func getKeyAndChain(domain string, certRes *certificate.Resource) (crypto.PrivateKey, []*x509.Certificate, error) {
+	certPemBlock, _ := pem.Decode(certRes.Certificate)
+	if certPemBlock == nil {
+		return nil, nil, fmt.Errorf("unable to parse Certificate for domain %s", domain)
+	}
 
-	matches, err := filepath.Glob(baseFilename + ".*")
+	cert, err := x509.ParseCertificate(certPemBlock.Bytes)
 	if err != nil {
-		return err
+		return nil, nil, fmt.Errorf("unable to load Certificate for domain %s: %w", domain, err)
 	}
 
-	for _, oldFile := range matches {
-		if strings.TrimSuffix(oldFile, filepath.Ext(oldFile)) != baseFilename && oldFile != baseFilename+issuerExt {
-			continue
-		}
-
-		date := strconv.FormatInt(time.Now().Unix(), 10)
-		filename := date + "." + filepath.Base(oldFile)
-		newFile := filepath.Join(s.archivePath, filename)
+	certChain, err := getCertificateChain(certRes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get certificate chain for domain %s: %w", domain, err)
+	}
 
-		err = os.Rename(oldFile, newFile)
-		if err != nil {
-			return err
-		}
+	privateKey, err := certcrypto.ParsePEMPrivateKey(certRes.PrivateKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to parse PrivateKey for domain %s: %w", domain, err)
 	}
 
-	return nil
+	return privateKey, append([]*x509.Certificate{cert}, certChain...), nil
 }
 
 func getCertificateChain(certRes *certificate.Resource) ([]*x509.Certificate, error) {
@@ -298,26 +401,19 @@ func getCertificateChain(certRes *certificate.Resource) ([]*x509.Certificate, er
 	return certChain, nil
 }
 
-func getPFXEncoder(pfxFormat string) (*pkcs12.Encoder, error) {
-	var encoder *pkcs12.Encoder
-
-	switch pfxFormat {
-	case "SHA256":
-		encoder = pkcs12.Modern2023
-	case "DES":
-		encoder = pkcs12.LegacyDES
-	case "RC2":
-		encoder = pkcs12.LegacyRC2
-	default:
-		return nil, fmt.Errorf("invalid PFX format: %s", pfxFormat)
+// certificateName the name of the files of an additional certificate of a domain (ex: example.com_rsa2048).
+// The files of the main certificate are named with the domain only.
+func certificateName(domain, additionalKeyType string) string {
+	if additionalKeyType == "" {
+		return domain
 	}
 
-	return encoder, nil
+	return domain + "_" + additionalKeyType
 }
 
 // sanitizedDomain Make sure no funny chars are in the cert names (like wildcards ;)).
 func sanitizedDomain(domain string) string {
-	safe, err := idna.ToASCII(strings.NewReplacer(":", "-", "*", "_").Replace(domain))
+	safe, err := storage.SanitizedDomain(domain)
 	if err != nil {
 		log.Fatal(err)
 	}
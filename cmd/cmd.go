@@ -7,8 +7,18 @@ func CreateCommands() []*cli.Command {
 	return []*cli.Command{
 		createRun(),
 		createRevoke(),
+		createCompromise(),
 		createRenew(),
 		createDNSHelp(),
 		createList(),
+		createGC(),
+		createServer(),
+		createDaemon(),
+		createRenewNow(),
+		createPlan(),
+		createCleanup(),
+		createSetup(),
+		createAccounts(),
+		createCompletion(),
 	}
 }
@@ -0,0 +1,442 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/lego"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/registration"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/urfave/cli/v2"
+)
+
+// Flag names.
+const (
+	flgAccountBundle = "bundle"
+	flgAccountKey    = "key"
+	flgAccountURL    = "account-url"
+	flgAccountOutput = "output"
+)
+
+// AccountBundle an exported account.
+type AccountBundle struct {
+	// Server the directory URL of the CA.
+	Server string `json:"server,omitempty"`
+	// Email the email address of the account.
+	Email string `json:"email,omitempty"`
+	// AccountURL the URL of the account.
+	AccountURL string `json:"accountURL,omitempty"`
+	// KID the key ID of the account: an alias of AccountURL used by some clients.
+	KID string `json:"kid,omitempty"`
+	// Key the private key of the account: a PEM encoded string, or a JWK.
+	Key json.RawMessage `json:"key"`
+}
+
+func createAccounts() *cli.Command {
+	return &cli.Command{
+		Name:  "accounts",
+		Usage: "Export, import, or update the accounts (ex: to move an account to another machine, or from another ACME client).",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "export",
+				Usage:  "Export an account (the account URL and the private key) to a JSON bundle.",
+				Action: exportAccount,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    flgAccountOutput,
+						Aliases: []string{"o"},
+						Value:   "-",
+						Usage:   "The path of the bundle ('-' for the standard output).",
+					},
+				},
+			},
+			{
+				Name: "import",
+				Usage: "Import an account from a JSON bundle, or from a private key (PEM or JWK) and an account URL." +
+					" The registration is retrieved from the CA: the account is not registered again.",
+				Action: importAccount,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  flgAccountBundle,
+						Usage: "The path of a JSON bundle (created by the 'export' command).",
+					},
+					&cli.StringFlag{
+						Name:  flgAccountKey,
+						Usage: "The path of the private key of the account (PEM or JWK).",
+					},
+					&cli.StringFlag{
+						Name:  flgAccountURL,
+						Usage: "The URL of the account (also known as the key ID). If defined, the account resolved by the CA must match.",
+					},
+				},
+			},
+			{
+				Name: "eab-rotate",
+				Usage: "Bind an existing account to new External Account Binding credentials (the global '--" + flgKID + "' and '--" + flgHMAC + "' options)," +
+					" when the CA expires the EAB credentials. The CA must support the update of the binding.",
+				Action: rotateAccountEAB,
+			},
+			{
+				Name: "key-rollover",
+				Usage: "Replace the key of an account by a new key (the global '--" + flgKeyType + "' option)." +
+					" The key file is replaced, and the old key is kept as a backup.",
+				Action: rolloverAccountKey,
+			},
+		},
+	}
+}
+
+func exportAccount(ctx *cli.Context) error {
+	accountsStorage := NewAccountsStorage(ctx)
+
+	if !accountsStorage.ExistsAccountFilePath() {
+		return fmt.Errorf("no account found for %s", accountsStorage.GetUserID())
+	}
+
+	privateKey, err := loadPrivateKey(accountsStorage.accountKeyPath())
+	if err != nil {
+		return fmt.Errorf("could not load the account key: %w", err)
+	}
+
+	account := accountsStorage.LoadAccount(privateKey)
+
+	keyPEM := pem.EncodeToMemory(certcrypto.PEMBlock(privateKey))
+
+	defer certcrypto.Zeroize(keyPEM)
+
+	key, err := json.Marshal(string(keyPEM))
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(AccountBundle{
+		Server:     ctx.String(flgServer),
+		Email:      account.Email,
+		AccountURL: account.Registration.URI,
+		Key:        key,
+	}, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	if ctx.String(flgAccountOutput) == "-" {
+		_, err = fmt.Println(string(data))
+
+		return err
+	}
+
+	return os.WriteFile(ctx.String(flgAccountOutput), data, 0o600)
+}
+
+func importAccount(ctx *cli.Context) error {
+	bundle, err := readAccountBundle(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = applyAccountBundle(ctx, bundle)
+	if err != nil {
+		return err
+	}
+
+	privateKey, err := parseAccountKey(bundle.Key)
+	if err != nil {
+		return err
+	}
+
+	accountsStorage := NewAccountsStorage(ctx)
+
+	if accountsStorage.ExistsAccountFilePath() {
+		return fmt.Errorf("an account already exists for %s", accountsStorage.GetUserID())
+	}
+
+	accountURL := bundle.AccountURL
+	if accountURL == "" {
+		accountURL = bundle.KID
+	}
+
+	reg, err := resolveImportedAccount(ctx, privateKey, accountURL)
+	if err != nil {
+		return fmt.Errorf("could not resolve the account: %w", err)
+	}
+
+	accountsStorage.createKeysFolder()
+
+	keyPEM := pem.EncodeToMemory(certcrypto.PEMBlock(privateKey))
+
+	defer certcrypto.Zeroize(keyPEM)
+
+	err = os.WriteFile(accountsStorage.accountKeyPath(), keyPEM, 0o600)
+	if err != nil {
+		return err
+	}
+
+	err = accountsStorage.Save(&Account{Email: accountsStorage.GetEmail(), Registration: reg, Key: privateKey})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("The account %s has been imported for %s.", reg.URI, accountsStorage.GetUserID())
+
+	return nil
+}
+
+func rotateAccountEAB(ctx *cli.Context) error {
+	kid := ctx.String(flgKID)
+	hmacEncoded := ctx.String(flgHMAC)
+
+	if kid == "" || hmacEncoded == "" {
+		return fmt.Errorf("the global options '--%s' and '--%s' are required", flgKID, flgHMAC)
+	}
+
+	accountsStorage := NewAccountsStorage(ctx)
+
+	if !accountsStorage.ExistsAccountFilePath() {
+		return fmt.Errorf("no account found for %s", accountsStorage.GetUserID())
+	}
+
+	account, keyType := setupAccount(ctx, accountsStorage)
+
+	if account.Registration == nil {
+		return fmt.Errorf("the account %s is not registered", accountsStorage.GetUserID())
+	}
+
+	client := newClient(ctx, account, keyType)
+
+	reg, err := client.Registration.UpdateExternalAccountBinding(registration.RegisterEABOptions{
+		Kid:         kid,
+		HmacEncoded: hmacEncoded,
+	})
+	if err != nil {
+		return err
+	}
+
+	account.Registration = reg
+
+	err = accountsStorage.Save(account)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("The account %s is bound to the external account %s.", reg.URI, kid)
+
+	return nil
+}
+
+func rolloverAccountKey(ctx *cli.Context) error {
+	if ctx.IsSet(flgAccountKeySigner) {
+		return fmt.Errorf("the key rollover of an account key stored outside lego ('--%s') is not supported", flgAccountKeySigner)
+	}
+
+	accountsStorage := NewAccountsStorage(ctx)
+
+	if !accountsStorage.ExistsAccountFilePath() {
+		return fmt.Errorf("no account found for %s", accountsStorage.GetUserID())
+	}
+
+	account, keyType := setupAccount(ctx, accountsStorage)
+
+	if account.Registration == nil {
+		return fmt.Errorf("the account %s is not registered", accountsStorage.GetUserID())
+	}
+
+	client := newClient(ctx, account, keyType)
+
+	newKey, err := certcrypto.GeneratePrivateKey(keyType)
+	if err != nil {
+		return fmt.Errorf("could not generate the new account key: %w", err)
+	}
+
+	keyPath := accountsStorage.accountKeyPath()
+
+	// The new key is written before the rollover: the key is not lost if the key file can't be replaced.
+	newKeyPath, err := writeNewAccountKey(keyPath, newKey)
+	if err != nil {
+		return fmt.Errorf("could not write the new account key: %w", err)
+	}
+
+	err = client.Registration.ChangeAccountKey(newKey)
+	if err != nil {
+		_ = os.Remove(newKeyPath)
+
+		return err
+	}
+
+	backupPath, err := replaceAccountKey(keyPath, newKeyPath)
+	if err != nil {
+		return fmt.Errorf("the key of the account has been replaced, but not the key file (the new key is %s): %w", newKeyPath, err)
+	}
+
+	log.Printf("The key of the account %s has been replaced (%s key), the old key is kept in %s.", account.Registration.URI, keyType, backupPath)
+
+	return nil
+}
+
+// writeNewAccountKey writes the new key of an account in a temporary file next to the key file.
+func writeNewAccountKey(keyPath string, privateKey crypto.PrivateKey) (string, error) {
+	keyPEM := pem.EncodeToMemory(certcrypto.PEMBlock(privateKey))
+
+	defer certcrypto.Zeroize(keyPEM)
+
+	file, err := os.CreateTemp(filepath.Dir(keyPath), filepath.Base(keyPath)+".new-*")
+	if err != nil {
+		return "", err
+	}
+
+	_, err = file.Write(keyPEM)
+
+	err = errors.Join(err, file.Close())
+	if err != nil {
+		_ = os.Remove(file.Name())
+
+		return "", err
+	}
+
+	return file.Name(), nil
+}
+
+// replaceAccountKey copies the key file to a backup file, then replaces the key file by the new key file (atomic rename).
+func replaceAccountKey(keyPath, newKeyPath string) (string, error) {
+	oldKey, err := os.ReadFile(keyPath)
+	if err != nil {
+		return "", err
+	}
+
+	defer certcrypto.Zeroize(oldKey)
+
+	backupPath := keyPath + "." + time.Now().UTC().Format("20060102150405") + ".bak"
+
+	file, err := os.OpenFile(backupPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = file.Write(oldKey)
+
+	err = errors.Join(err, file.Close())
+	if err != nil {
+		return "", err
+	}
+
+	return backupPath, os.Rename(newKeyPath, keyPath)
+}
+
+// readAccountBundle reads the bundle, or creates a bundle from the key and the account URL.
+func readAccountBundle(ctx *cli.Context) (*AccountBundle, error) {
+	if ctx.IsSet(flgAccountBundle) == ctx.IsSet(flgAccountKey) {
+		return nil, fmt.Errorf("one of '--%s' or '--%s' is required", flgAccountBundle, flgAccountKey)
+	}
+
+	if ctx.IsSet(flgAccountKey) {
+		key, err := os.ReadFile(ctx.String(flgAccountKey))
+		if err != nil {
+			return nil, err
+		}
+
+		return &AccountBundle{AccountURL: ctx.String(flgAccountURL), Key: key}, nil
+	}
+
+	data, err := os.ReadFile(ctx.String(flgAccountBundle))
+	if err != nil {
+		return nil, err
+	}
+
+	defer certcrypto.Zeroize(data)
+
+	bundle := &AccountBundle{}
+
+	err = json.Unmarshal(data, bundle)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the bundle: %w", err)
+	}
+
+	if ctx.IsSet(flgAccountURL) {
+		bundle.AccountURL = ctx.String(flgAccountURL)
+	}
+
+	return bundle, nil
+}
+
+// applyAccountBundle uses the server and the email of the bundle, unless they are explicitly defined.
+func applyAccountBundle(ctx *cli.Context, bundle *AccountBundle) error {
+	if bundle.Server != "" {
+		if ctx.IsSet(flgServer) && ctx.String(flgServer) != bundle.Server {
+			return fmt.Errorf("the bundle is for the server %s, not for %s", bundle.Server, ctx.String(flgServer))
+		}
+
+		err := ctx.Set(flgServer, bundle.Server)
+		if err != nil {
+			return err
+		}
+	}
+
+	if bundle.Email != "" && !ctx.IsSet(flgEmail) {
+		return ctx.Set(flgEmail, bundle.Email)
+	}
+
+	return nil
+}
+
+// parseAccountKey parses a private key: PEM (raw, or as a JSON string), or JWK.
+func parseAccountKey(raw []byte) (crypto.PrivateKey, error) {
+	raw = bytes.TrimSpace(raw)
+
+	switch {
+	case len(raw) == 0:
+		return nil, errors.New("the account key is missing")
+
+	case raw[0] == '{':
+		var jwk jose.JSONWebKey
+
+		err := jwk.UnmarshalJSON(raw)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse the account key (JWK): %w", err)
+		}
+
+		if jwk.IsPublic() {
+			return nil, errors.New("the account key (JWK) is a public key")
+		}
+
+		return jwk.Key, nil
+
+	case raw[0] == '"':
+		var keyPEM string
+
+		err := json.Unmarshal(raw, &keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse the account key: %w", err)
+		}
+
+		raw = []byte(keyPEM)
+	}
+
+	privateKey, err := certcrypto.ParsePEMPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the account key (PEM): %w", err)
+	}
+
+	return privateKey, nil
+}
+
+// resolveImportedAccount retrieves the registration of an account key from the CA (newAccount with onlyReturnExisting).
+func resolveImportedAccount(ctx *cli.Context, privateKey crypto.PrivateKey, accountURL string) (*registration.Resource, error) {
+	config := lego.NewConfig(&Account{Key: privateKey})
+	config.CADirURL = ctx.String(flgServer)
+	config.UserAgent = getUserAgent(ctx)
+
+	client, err := lego.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Registration.ResolveAccountByKeyID(accountURL)
+}
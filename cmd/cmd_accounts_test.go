@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/go-acme/lego/v4/platform/tester/servermock"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func Test_parseAccountKey(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	keyPEM := pem.EncodeToMemory(certcrypto.PEMBlock(privateKey))
+
+	keyJSON, err := json.Marshal(string(keyPEM))
+	require.NoError(t, err)
+
+	jwk, err := jose.JSONWebKey{Key: privateKey}.MarshalJSON()
+	require.NoError(t, err)
+
+	publicJWK, err := jose.JSONWebKey{Key: privateKey.Public()}.MarshalJSON()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc          string
+		raw           []byte
+		expectedError string
+	}{
+		{
+			desc: "PEM",
+			raw:  keyPEM,
+		},
+		{
+			desc: "PEM as JSON string",
+			raw:  keyJSON,
+		},
+		{
+			desc: "JWK",
+			raw:  jwk,
+		},
+		{
+			desc:          "public JWK",
+			raw:           publicJWK,
+			expectedError: "the account key (JWK) is a public key",
+		},
+		{
+			desc:          "missing",
+			raw:           []byte("  "),
+			expectedError: "the account key is missing",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			key, err := parseAccountKey(test.raw)
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, privateKey.Equal(key))
+		})
+	}
+}
+
+func Test_importAccount(t *testing.T) {
+	server := tester.MockACMEServer().
+		Route("POST /account",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Location",
+					fmt.Sprintf("https://%s/account/1", req.Context().Value(http.LocalAddrContextKey)))
+
+				servermock.JSONEncode(acme.Account{Status: "valid"}).ServeHTTP(rw, req)
+			})).
+		BuildHTTPS(t)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+
+	t.Setenv("LEGO_CA_CERTIFICATES", caFile)
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(t.TempDir(), "account.key")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(certcrypto.PEMBlock(privateKey)), 0o600))
+
+	run := func(path string, args ...string) error {
+		app := &cli.App{
+			Name:     "lego",
+			Flags:    CreateFlags(path),
+			Commands: []*cli.Command{createAccounts()},
+		}
+
+		return app.Run(append([]string{"lego", "--" + flgServer, server.URL + "/dir", "--" + flgEmail, "test@example.com"}, args...))
+	}
+
+	accountURL := server.URL + "/account/1"
+
+	// Wrong account URL.
+	err = run(t.TempDir(), "accounts", "import", "--"+flgAccountKey, keyFile, "--"+flgAccountURL, server.URL+"/account/2")
+	require.ErrorContains(t, err, "the account key belongs to the account "+accountURL)
+
+	source := t.TempDir()
+
+	err = run(source, "accounts", "import", "--"+flgAccountKey, keyFile, "--"+flgAccountURL, accountURL)
+	require.NoError(t, err)
+
+	err = run(source, "accounts", "import", "--"+flgAccountKey, keyFile)
+	require.ErrorContains(t, err, "an account already exists for test@example.com")
+
+	bundleFile := filepath.Join(t.TempDir(), "bundle.json")
+
+	err = run(source, "accounts", "export", "--"+flgAccountOutput, bundleFile)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(bundleFile)
+	require.NoError(t, err)
+
+	var bundle AccountBundle
+
+	require.NoError(t, json.Unmarshal(data, &bundle))
+
+	assert.Equal(t, server.URL+"/dir", bundle.Server)
+	assert.Equal(t, "test@example.com", bundle.Email)
+	assert.Equal(t, accountURL, bundle.AccountURL)
+
+	// Moved to another machine.
+	target := t.TempDir()
+
+	err = run(target, "accounts", "import", "--"+flgAccountBundle, bundleFile)
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(target, baseAccountsRootFolderName, "*", "test@example.com", accountFileName))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	data, err = os.ReadFile(files[0])
+	require.NoError(t, err)
+
+	var account Account
+
+	require.NoError(t, json.Unmarshal(data, &account))
+
+	assert.Equal(t, accountURL, account.Registration.URI)
+	assert.Equal(t, "valid", account.Registration.Body.Status)
+
+	keyFiles, err := filepath.Glob(filepath.Join(target, baseAccountsRootFolderName, "*", "test@example.com", baseKeysFolderName, "*.key"))
+	require.NoError(t, err)
+	require.Len(t, keyFiles, 1)
+
+	key, err := loadPrivateKey(keyFiles[0])
+	require.NoError(t, err)
+	assert.True(t, privateKey.Equal(key))
+}
+
+func Test_rolloverAccountKey(t *testing.T) {
+	server := tester.MockACMEServer().
+		Route("POST /account",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Location",
+					fmt.Sprintf("https://%s/account/1", req.Context().Value(http.LocalAddrContextKey)))
+
+				servermock.JSONEncode(acme.Account{Status: "valid"}).ServeHTTP(rw, req)
+			})).
+		Route("POST /keyChange", servermock.Noop()).
+		BuildHTTPS(t)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+
+	t.Setenv("LEGO_CA_CERTIFICATES", caFile)
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(t.TempDir(), "account.key")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(certcrypto.PEMBlock(privateKey)), 0o600))
+
+	path := t.TempDir()
+
+	run := func(args ...string) error {
+		app := &cli.App{
+			Name:     "lego",
+			Flags:    CreateFlags(path),
+			Commands: []*cli.Command{createAccounts()},
+		}
+
+		return app.Run(append([]string{"lego", "--" + flgServer, server.URL + "/dir", "--" + flgEmail, "test@example.com"}, args...))
+	}
+
+	err = run("accounts", "import", "--"+flgAccountKey, keyFile)
+	require.NoError(t, err)
+
+	err = run("accounts", "key-rollover")
+	require.NoError(t, err)
+
+	keysPath := filepath.Join(path, baseAccountsRootFolderName, "*", "test@example.com", baseKeysFolderName)
+
+	keyFiles, err := filepath.Glob(filepath.Join(keysPath, "*.key"))
+	require.NoError(t, err)
+	require.Len(t, keyFiles, 1)
+
+	key, err := loadPrivateKey(keyFiles[0])
+	require.NoError(t, err)
+	assert.False(t, privateKey.Equal(key))
+
+	backupFiles, err := filepath.Glob(filepath.Join(keysPath, "*.key.*.bak"))
+	require.NoError(t, err)
+	require.Len(t, backupFiles, 1)
+
+	backup, err := loadPrivateKey(backupFiles[0])
+	require.NoError(t, err)
+	assert.True(t, privateKey.Equal(backup))
+}
@@ -1,23 +1,98 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"slices"
+
 	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/urfave/cli/v2"
 )
 
 func Before(ctx *cli.Context) error {
+	err := loadEnvFile(ctx)
+	if err != nil {
+		log.Fatalf("Could not load the environment file: %v", err)
+	}
+
+	err = applyServerPreset(ctx)
+	if err != nil {
+		log.Fatalf("Could not apply the server preset: %v", err)
+	}
+
+	err = applyHostCert(ctx)
+	if err != nil {
+		log.Fatalf("Could not discover the identifiers of the host: %v", err)
+	}
+
 	if ctx.String(flgPath) == "" {
 		log.Fatalf("Could not determine current working directory. Please pass --%s.", flgPath)
 	}
 
-	err := createNonExistingFolder(ctx.String(flgPath))
+	err = createNonExistingFolder(ctx.String(flgPath))
 	if err != nil {
 		log.Fatalf("Could not check/create path: %v", err)
 	}
 
+	err = pullStorage(ctx)
+	if err != nil {
+		log.Fatalf("Could not load the data from the storage: %v", err)
+	}
+
 	if ctx.String(flgServer) == "" {
 		log.Fatalf("Could not determine current working server. Please pass --%s.", flgServer)
 	}
 
 	return nil
 }
+
+// After uploads the changes of the data to the remote storage.
+func After(ctx *cli.Context) error {
+	return pushStorage(ctx)
+}
+
+// loadEnvFile loads the environment variables defined in the dotenv file.
+// The precedence is: flags, environment variables, dotenv file, default values.
+func loadEnvFile(ctx *cli.Context) error {
+	filename := ctx.String(flgEnvFile)
+	if filename == "" {
+		return nil
+	}
+
+	names, err := env.LoadDotEnv(filename)
+	if err != nil {
+		return err
+	}
+
+	// The global flags are already parsed:
+	// the values of the flags not explicitly set are updated from the dotenv file.
+	// The flags of the commands are parsed later, they will read the environment variables.
+	for _, flag := range ctx.App.Flags {
+		envFlag, ok := flag.(interface{ GetEnvVars() []string })
+		if !ok {
+			continue
+		}
+
+		name := flag.Names()[0]
+
+		if ctx.IsSet(name) {
+			continue
+		}
+
+		for _, envVar := range envFlag.GetEnvVars() {
+			if !slices.Contains(names, envVar) {
+				continue
+			}
+
+			err = ctx.Set(name, os.Getenv(envVar))
+			if err != nil {
+				return fmt.Errorf("%s: %w", envVar, err)
+			}
+
+			break
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func Test_loadEnvFile(t *testing.T) {
+	content := `
+LEGO_EMAIL=file@example.com
+LEGO_SERVER=https://file.example.com/directory
+LEGO_PATH=/from/file
+TEST_LEGO_PROVIDER_TOKEN=secret
+`
+
+	filename := filepath.Join(t.TempDir(), ".env")
+
+	err := os.WriteFile(filename, []byte(content), 0o600)
+	require.NoError(t, err)
+
+	// Registers the cleanup of the environment variables loaded from the file.
+	for _, name := range []string{envEmail, envPath, "TEST_LEGO_PROVIDER_TOKEN"} {
+		t.Setenv(name, "")
+		require.NoError(t, os.Unsetenv(name))
+	}
+
+	t.Setenv(envServer, "https://env.example.com/directory")
+
+	var email, server, path string
+
+	app := &cli.App{
+		Name:  "lego",
+		Flags: CreateFlags("/default"),
+		Action: func(ctx *cli.Context) error {
+			err := loadEnvFile(ctx)
+			if err != nil {
+				return err
+			}
+
+			email = ctx.String(flgEmail)
+			server = ctx.String(flgServer)
+			path = ctx.String(flgPath)
+
+			return nil
+		},
+	}
+
+	err = app.Run([]string{"lego", "--" + flgEnvFile, filename, "--" + flgPath, "/from/flag"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "file@example.com", email)
+	assert.Equal(t, "https://env.example.com/directory", server)
+	assert.Equal(t, "/from/flag", path)
+	assert.Equal(t, "secret", os.Getenv("TEST_LEGO_PROVIDER_TOKEN"))
+}
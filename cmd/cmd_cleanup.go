@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/providers/dns"
+	"github.com/urfave/cli/v2"
+)
+
+// Flag names.
+const (
+	flgCleanupZone      = "zone"
+	flgCleanupOlderThan = "older-than"
+	flgCleanupDryRun    = "dry-run"
+	flgCleanupAll       = "all"
+)
+
+func createCleanup() *cli.Command {
+	return &cli.Command{
+		Name: "cleanup",
+		Usage: "Remove the stale challenge TXT records (_acme-challenge) left behind by a crash or a failed cleanup." +
+			" The DNS provider is defined by the global '--" + flgDNS + "' option.",
+		Action: cleanup,
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:     flgCleanupZone,
+				Usage:    "The DNS zone to scan. Can be specified multiple times.",
+				Required: true,
+			},
+			&cli.DurationFlag{
+				Name:  flgCleanupOlderThan,
+				Value: 24 * time.Hour,
+				Usage: "Only the records created before this duration are removed.",
+			},
+			&cli.BoolFlag{
+				Name:  flgCleanupDryRun,
+				Usage: "Display the stale records without removing them.",
+			},
+			&cli.BoolFlag{
+				Name: flgCleanupAll,
+				Usage: "Remove all the challenge records, including the records not created by this lego instance" +
+					" (i.e. the records of the other ACME clients sharing the zones).",
+			},
+		},
+	}
+}
+
+func cleanup(ctx *cli.Context) error {
+	if ctx.String(flgDNS) == "" {
+		return fmt.Errorf("the global option '--%s' is required", flgDNS)
+	}
+
+	olderThan := ctx.Duration(flgCleanupOlderThan)
+	if olderThan <= 0 {
+		return fmt.Errorf("'%s' must be greater than zero", flgCleanupOlderThan)
+	}
+
+	err := setupDNSAPIHosts(ctx)
+	if err != nil {
+		return err
+	}
+
+	provider, err := dns.NewDNSChallengeProviderByName(ctx.String(flgDNS))
+	if err != nil {
+		return err
+	}
+
+	janitor, ok := provider.(dns01.RecordJanitor)
+	if !ok {
+		return fmt.Errorf("%s: %w (the provider cannot list and delete the TXT records)", ctx.String(flgDNS), dns01.ErrCleanupNotSupported)
+	}
+
+	opts, err := cleanupOptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	zones := ctx.StringSlice(flgCleanupZone)
+
+	if ctx.Bool(flgCleanupDryRun) {
+		stale, err := dns01.FindStaleRecords(janitor, zones, olderThan, opts...)
+		if err != nil {
+			return err
+		}
+
+		for _, zone := range slices.Sorted(maps.Keys(stale)) {
+			for _, record := range stale[zone] {
+				log.Infof("[%s] Stale record %s (%s), created at %s", zone, record.FQDN, record.ID, record.CreatedAt.Format(time.RFC3339))
+			}
+		}
+
+		return nil
+	}
+
+	removed, err := dns01.CleanUpStaleRecords(janitor, zones, olderThan, opts...)
+
+	for _, record := range removed {
+		log.Infof("Removed stale record %s (%s), created at %s", record.FQDN, record.ID, record.CreatedAt.Format(time.RFC3339))
+	}
+
+	return err
+}
+
+// cleanupOptions restricts the cleanup to the records created by this lego instance (the records of the manifest).
+func cleanupOptions(ctx *cli.Context) ([]dns01.JanitorOption, error) {
+	if ctx.Bool(flgCleanupAll) {
+		return nil, nil
+	}
+
+	path := dnsManifestPath(ctx)
+
+	_, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no record created by lego (%s not found): use '--%s' to remove the records of all the ACME clients", path, flgCleanupAll)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := dns01.OpenManifest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return []dns01.JanitorOption{dns01.OnlyManifestRecords(manifest)}, nil
+}
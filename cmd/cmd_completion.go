@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Shell names.
+const (
+	shellBash = "bash"
+	shellZsh  = "zsh"
+	shellFish = "fish"
+)
+
+// Based on https://github.com/urfave/cli/blob/v2.27.7/autocomplete/bash_autocomplete
+const bashCompletion = `_lego_bash_autocomplete() {
+  if [[ "${COMP_WORDS[0]}" != "source" ]]; then
+    local cur opts base words
+    COMPREPLY=()
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    if declare -F _init_completion >/dev/null 2>&1; then
+      _init_completion -n "=:" || return
+    else
+      COMPREPLY=()
+      _get_comp_words_by_ref -n "=:" cur prev words cword || return
+    fi
+    words=("${words[@]:0:$cword}")
+    if [[ "$cur" == "-"* ]]; then
+      requestComp="${words[*]} ${cur} --generate-bash-completion"
+    else
+      requestComp="${words[*]} --generate-bash-completion"
+    fi
+    opts=$(eval "${requestComp}" 2>/dev/null)
+    COMPREPLY=($(compgen -W "${opts}" -- ${cur}))
+    return 0
+  fi
+}
+
+complete -o bashdefault -o default -o nospace -F _lego_bash_autocomplete %[1]s
+`
+
+// Based on https://github.com/urfave/cli/blob/v2.27.7/autocomplete/zsh_autocomplete
+const zshCompletion = `#compdef %[1]s
+
+_lego_zsh_autocomplete() {
+  local -a opts
+  local cur
+  cur=${words[-1]}
+  if [[ "$cur" == "-"* ]]; then
+    opts=("${(@f)$(${words[@]:0:#words[@]-1} ${cur} --generate-bash-completion)}")
+  else
+    opts=("${(@f)$(${words[@]:0:#words[@]-1} --generate-bash-completion)}")
+  fi
+
+  if [[ "${opts[1]}" != "" ]]; then
+    _describe 'values' opts
+  else
+    _files
+  fi
+}
+
+compdef _lego_zsh_autocomplete %[1]s
+`
+
+func createCompletion() *cli.Command {
+	return &cli.Command{
+		Name:      "completion",
+		Usage:     "Generate the shell completion script (bash, zsh, or fish).",
+		ArgsUsage: strings.Join([]string{shellBash, shellZsh, shellFish}, "|"),
+		Description: "Load the completion in the current shell:\n" +
+			"   bash: source <(lego completion bash)\n" +
+			"   zsh:  source <(lego completion zsh)\n" +
+			"   fish: lego completion fish | source",
+		Action: completion,
+	}
+}
+
+func completion(ctx *cli.Context) error {
+	name := ctx.App.Name
+
+	var script string
+
+	switch shell := ctx.Args().First(); shell {
+	case shellBash:
+		script = fmt.Sprintf(bashCompletion, name)
+
+	case shellZsh:
+		script = fmt.Sprintf(zshCompletion, name)
+
+	case shellFish:
+		var err error
+
+		script, err = ctx.App.ToFishCompletion()
+		if err != nil {
+			return fmt.Errorf("completion: %w", err)
+		}
+
+	case "":
+		return fmt.Errorf("completion: the shell is required (%s, %s, or %s)", shellBash, shellZsh, shellFish)
+
+	default:
+		return fmt.Errorf("completion: unsupported shell %q", shell)
+	}
+
+	_, err := fmt.Fprint(ctx.App.Writer, script)
+
+	return err
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func Test_completion(t *testing.T) {
+	testCases := []struct {
+		shell    string
+		expected string
+	}{
+		{shell: shellBash, expected: "complete -o bashdefault -o default -o nospace -F _lego_bash_autocomplete lego"},
+		{shell: shellZsh, expected: "compdef _lego_zsh_autocomplete lego"},
+		{shell: shellFish, expected: "complete -c lego"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.shell, func(t *testing.T) {
+			output := &bytes.Buffer{}
+
+			app := &cli.App{
+				Name:     "lego",
+				Writer:   output,
+				Commands: []*cli.Command{createCompletion()},
+			}
+
+			err := app.Run([]string{"lego", "completion", test.shell})
+			require.NoError(t, err)
+
+			assert.Contains(t, output.String(), test.expected)
+		})
+	}
+}
+
+func Test_completion_unsupported(t *testing.T) {
+	app := &cli.App{
+		Name:     "lego",
+		Writer:   &bytes.Buffer{},
+		Commands: []*cli.Command{createCompletion()},
+	}
+
+	err := app.Run([]string{"lego", "completion", "powershell"})
+	require.EqualError(t, err, `completion: unsupported shell "powershell"`)
+}
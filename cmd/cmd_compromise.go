@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/lego"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/urfave/cli/v2"
+)
+
+// Flag names.
+const (
+	flgCompromiseHook        = "compromise-hook"
+	flgCompromiseHookTimeout = "compromise-hook-timeout"
+	flgCompromiseAuditFile   = "audit-file"
+)
+
+// compromiseAuditFileName the default audit file of the compromise command (in the "path" directory).
+const compromiseAuditFileName = "compromise-audit.jsonl"
+
+// hookEnvCompromisedSerial the serial number of the revoked certificate (compromise hook).
+const hookEnvCompromisedSerial = "LEGO_COMPROMISED_SERIAL"
+
+func createCompromise() *cli.Command {
+	return &cli.Command{
+		Name: "compromise",
+		Usage: "Respond to the compromise of the private key of a certificate: revoke the certificate (keyCompromise), archive the files," +
+			" obtain a new certificate with a new private key, run the hook, and write an audit record",
+		Before: func(ctx *cli.Context) error {
+			if len(ctx.StringSlice(flgDomains)) == 0 {
+				log.Fatal("Please specify --domains/-d: the domains identify the compromised certificates.")
+			}
+
+			return nil
+		},
+		Action: compromise,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  flgNoBundle,
+				Usage: "Do not create a certificate bundle by adding the issuers certificate to the new certificate.",
+			},
+			&cli.BoolFlag{
+				Name:  flgMustStaple,
+				Usage: "Include the OCSP must staple TLS extension in the CSR and generated certificate.",
+			},
+			&cli.StringFlag{
+				Name: flgPreferredChain,
+				Usage: "If the CA offers multiple certificate chains, prefer the chain with an issuer matching this Subject Common Name." +
+					" If no match, the default offered chain will be used.",
+			},
+			&cli.StringFlag{
+				Name:  flgProfile,
+				Usage: "If the CA offers multiple certificate profiles (draft-ietf-acme-profiles), choose this one.",
+			},
+			&cli.StringFlag{
+				Name: flgCompromiseHook,
+				Usage: "Define a hook executed when the new certificate is created (ex: deployment, reload of the servers)." +
+					" The serial number of the revoked certificate is in LEGO_COMPROMISED_SERIAL.",
+			},
+			&cli.DurationFlag{
+				Name:  flgCompromiseHookTimeout,
+				Usage: "Define the timeout for the hook execution.",
+				Value: 2 * time.Minute,
+			},
+			&cli.StringFlag{
+				Name:  flgCompromiseAuditFile,
+				Usage: "The file where the audit records are appended (JSON lines). The default is '" + compromiseAuditFileName + "' in the '--" + flgPath + "' directory.",
+			},
+		},
+	}
+}
+
+// compromiseRecord the audit record of the response to the compromise of a certificate.
+type compromiseRecord struct {
+	Time    time.Time `json:"time"`
+	Name    string    `json:"name"`
+	Server  string    `json:"server"`
+	Account string    `json:"account"`
+
+	// The compromised certificate.
+	RevokedSerial    string    `json:"revokedSerial,omitempty"`
+	RevokedKeySHA256 string    `json:"revokedKeySha256,omitempty"`
+	RevokedNotAfter  time.Time `json:"revokedNotAfter,omitzero"`
+	Domains          []string  `json:"domains,omitempty"`
+
+	// The steps of the procedure.
+	Revocation string `json:"revocation,omitempty"`
+	Archived   bool   `json:"archived"`
+
+	// The new certificate.
+	NewSerial    string    `json:"newSerial,omitempty"`
+	NewKeySHA256 string    `json:"newKeySha256,omitempty"`
+	NewNotAfter  time.Time `json:"newNotAfter,omitzero"`
+
+	HookExecuted bool   `json:"hookExecuted"`
+	Error        string `json:"error,omitempty"`
+}
+
+func compromise(ctx *cli.Context) error {
+	account, keyType := setupAccount(ctx, NewAccountsStorage(ctx))
+
+	if account.Registration == nil {
+		log.Fatalf("Account %s is not registered. Use 'run' to register a new account.\n", account.Email)
+	}
+
+	client := setupClient(ctx, account, keyType)
+
+	certsStorage := NewCertificatesStorage(ctx)
+	certsStorage.CreateRootFolder()
+	certsStorage.CreateArchiveFolder()
+
+	auditFile := ctx.String(flgCompromiseAuditFile)
+	if auditFile == "" {
+		auditFile = filepath.Join(ctx.String(flgPath), compromiseAuditFileName)
+	}
+
+	for _, domain := range ctx.StringSlice(flgDomains) {
+		record := &compromiseRecord{
+			Time:    time.Now().UTC(),
+			Name:    domain,
+			Server:  ctx.String(flgServer),
+			Account: account.Email,
+		}
+
+		err := respondToCompromise(ctx, client, certsStorage, keyType, domain, record)
+		if err != nil {
+			record.Error = err.Error()
+		}
+
+		errA := appendCompromiseRecord(auditFile, record)
+		if errA != nil {
+			log.Warnf("[%s] Unable to write the audit record: %v", domain, errA)
+		}
+
+		if err != nil {
+			return fmt.Errorf("[%s] compromise: %w", domain, err)
+		}
+
+		log.Infof("[%s] The compromised certificate %s has been replaced by %s.", domain, record.RevokedSerial, record.NewSerial)
+	}
+
+	return nil
+}
+
+// respondToCompromise revokes the certificate (keyCompromise), archives its files,
+// obtains a new certificate with a new private key, and launches the hook.
+// The record is filled at each step.
+func respondToCompromise(ctx *cli.Context, client *lego.Client, certsStorage *CertificatesStorage, keyType certcrypto.KeyType, domain string, record *compromiseRecord) error {
+	certBytes, err := certsStorage.ReadFile(domain, certExt)
+	if err != nil {
+		return fmt.Errorf("read the certificate: %w", err)
+	}
+
+	certificates, err := certcrypto.ParsePEMBundle(certBytes)
+	if err != nil {
+		return fmt.Errorf("parse the certificate: %w", err)
+	}
+
+	cert := certificates[0]
+
+	record.RevokedSerial = formatSerial(cert)
+	record.RevokedKeySHA256 = publicKeySHA256(cert)
+	record.RevokedNotAfter = cert.NotAfter.UTC()
+	record.Domains = certcrypto.ExtractDomains(cert)
+
+	log.Infof("[%s] Revoking the certificate %s (keyCompromise).", domain, record.RevokedSerial)
+
+	reason := acme.CRLReasonKeyCompromise
+
+	err = client.Certificate.RevokeWithReason(certBytes, &reason)
+
+	var problem *acme.ProblemDetails
+
+	switch {
+	case err == nil:
+		record.Revocation = "revoked"
+	case errors.As(err, &problem) && problem.Type == acme.AlreadyRevokedErr:
+		log.Warnf("[%s] The certificate %s is already revoked.", domain, record.RevokedSerial)
+
+		record.Revocation = "already revoked"
+	default:
+		return fmt.Errorf("revoke the certificate: %w", err)
+	}
+
+	// The compromised private key must not be used anymore.
+	err = certsStorage.MoveToArchive(domain)
+	if err != nil {
+		return fmt.Errorf("archive the certificate: %w", err)
+	}
+
+	record.Archived = true
+
+	privateKey, err := certcrypto.GeneratePrivateKey(keyType)
+	if err != nil {
+		return fmt.Errorf("generate the private key: %w", err)
+	}
+
+	log.Infof("[%s] Obtaining a new certificate with a new private key.", domain)
+
+	certRes, err := client.Certificate.Obtain(certificate.ObtainRequest{
+		Domains:        record.Domains,
+		PrivateKey:     privateKey,
+		MustStaple:     ctx.Bool(flgMustStaple),
+		Bundle:         !ctx.Bool(flgNoBundle),
+		PreferredChain: ctx.String(flgPreferredChain),
+		Profile:        ctx.String(flgProfile),
+	})
+	if err != nil {
+		return fmt.Errorf("obtain the new certificate: %w", err)
+	}
+
+	certRes.Domain = domain
+
+	certsStorage.SaveResourceAs(domain, certRes)
+
+	newCert, err := certcrypto.ParsePEMCertificate(certRes.Certificate)
+	if err != nil {
+		return fmt.Errorf("parse the new certificate: %w", err)
+	}
+
+	record.NewSerial = formatSerial(newCert)
+	record.NewKeySHA256 = publicKeySHA256(newCert)
+	record.NewNotAfter = newCert.NotAfter.UTC()
+
+	meta := map[string]string{
+		hookEnvAccountEmail:      record.Account,
+		hookEnvCompromisedSerial: record.RevokedSerial,
+	}
+
+	addPathToMetadata(meta, domain, certRes, certsStorage)
+	addValidityToMetadata(meta, certRes)
+
+	err = launchHook(ctx.Context, ctx.String(flgCompromiseHook), ctx.Duration(flgCompromiseHookTimeout), meta)
+	if err != nil {
+		return fmt.Errorf("hook: %w", err)
+	}
+
+	record.HookExecuted = ctx.String(flgCompromiseHook) != ""
+
+	return nil
+}
+
+// appendCompromiseRecord appends an audit record (JSON line) to the audit file.
+func appendCompromiseRecord(auditFile string, record *compromiseRecord) error {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(auditFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, filePerm)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(append(raw, '\n'))
+
+	return errors.Join(err, file.Close())
+}
+
+// formatSerial returns the serial number of a certificate (hexadecimal).
+func formatSerial(cert *x509.Certificate) string {
+	return hex.EncodeToString(cert.SerialNumber.Bytes())
+}
+
+// publicKeySHA256 returns the SHA-256 fingerprint of the public key (SPKI) of a certificate.
+func publicKeySHA256(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+
+	return hex.EncodeToString(sum[:])
+}
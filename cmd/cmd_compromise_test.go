@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_appendCompromiseRecord(t *testing.T) {
+	auditFile := filepath.Join(t.TempDir(), compromiseAuditFileName)
+
+	records := []*compromiseRecord{
+		{
+			Time:          time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC),
+			Name:          "example.com",
+			RevokedSerial: "03a1",
+			Revocation:    "revoked",
+			Archived:      true,
+			NewSerial:     "04b2",
+		},
+		{
+			Time:          time.Date(2026, 10, 16, 8, 1, 0, 0, time.UTC),
+			Name:          "example.org",
+			RevokedSerial: "05c3",
+			Error:         "revoke the certificate: unauthorized",
+		},
+	}
+
+	for _, record := range records {
+		require.NoError(t, appendCompromiseRecord(auditFile, record))
+	}
+
+	file, err := os.Open(auditFile)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = file.Close() })
+
+	var stored []*compromiseRecord
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record compromiseRecord
+
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+
+		stored = append(stored, &record)
+	}
+
+	require.NoError(t, scanner.Err())
+
+	assert.Equal(t, records, stored)
+}
+
+func Test_formatSerial(t *testing.T) {
+	cert, err := certcrypto.ParsePEMCertificate(generateIssuedCertificate(t, "Test CA"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "01", formatSerial(cert))
+	assert.Len(t, publicKeySHA256(cert), 64)
+}
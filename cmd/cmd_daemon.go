@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-acme/lego/v4/log"
+	"github.com/urfave/cli/v2"
+)
+
+// Flag names.
+const (
+	flgDaemonInterval      = "interval"
+	flgDaemonMetricsListen = "metrics-listen"
+	flgDaemonControlSocket = "control-socket"
+)
+
+func createDaemon() *cli.Command {
+	renewCmd := createRenew()
+
+	flags := []cli.Flag{
+		&cli.DurationFlag{
+			Name:  flgDaemonInterval,
+			Usage: "The interval between two renewal checks.",
+			Value: 12 * time.Hour,
+		},
+		&cli.StringFlag{
+			Name: flgDaemonMetricsListen,
+			Usage: "The address of the Prometheus metrics endpoint (GET /metrics)." +
+				" Supported: host:port or unix:/path/to/socket. The metrics are not exposed if it's not defined.",
+		},
+		&cli.StringFlag{
+			Name: flgDaemonControlSocket,
+			Usage: "The path of the unix socket of the control endpoint (POST /renew), used by the 'renew-now' command." +
+				" The renewal check can also be triggered by SIGUSR1 (all the certificates, not forced).",
+		},
+	}
+
+	for _, flag := range renewCmd.Flags {
+		// The daemon doesn't exit after a renewal.
+		if flag.Names()[0] == flgUnchangedExitCode {
+			continue
+		}
+
+		flags = append(flags, flag)
+	}
+
+	return &cli.Command{
+		Name:  "daemon",
+		Usage: "Renew a certificate on a schedule, and expose Prometheus metrics",
+		Before: func(ctx *cli.Context) error {
+			if ctx.Duration(flgDaemonInterval) <= 0 {
+				log.Fatalf("The --%s must be greater than 0.", flgDaemonInterval)
+			}
+
+			return renewCmd.Before(ctx)
+		},
+		Action: daemon,
+		Flags:  flags,
+	}
+}
+
+func daemon(ctx *cli.Context) error {
+	accountsStorage := NewAccountsStorage(ctx)
+
+	account, keyType := setupAccount(ctx, accountsStorage)
+
+	if account.Registration == nil {
+		log.Fatalf("Account %s is not registered. Use 'run' to register a new account.\n", account.Email)
+	}
+
+	certsStorage := NewCertificatesStorage(ctx)
+
+	metrics := newDaemonMetrics()
+
+	// The clients send the events of the challenges to the metrics.
+	ctx.Context = log.NewContext(ctx.Context, metrics)
+
+	if ctx.IsSet(flgDaemonMetricsListen) {
+		listener, err := listen(ctx.String(flgDaemonMetricsListen))
+		if err != nil {
+			return fmt.Errorf("metrics: %w", err)
+		}
+
+		mux := http.NewServeMux()
+		mux.Handle("GET /metrics", metrics)
+
+		srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+		defer func() { _ = srv.Close() }()
+
+		go func() {
+			errS := srv.Serve(listener)
+			if errS != nil && !errors.Is(errS, http.ErrServerClosed) {
+				log.Warnf("Metrics server: %v", errS)
+			}
+		}()
+
+		log.Infof("Metrics listening on %s", listener.Addr())
+	}
+
+	triggers := make(chan renewalTrigger, 1)
+
+	if ctx.IsSet(flgDaemonControlSocket) {
+		listener, err := listen(unixSocketPrefix + ctx.String(flgDaemonControlSocket))
+		if err != nil {
+			return fmt.Errorf("control: %w", err)
+		}
+
+		srv := &http.Server{Handler: newControlHandler(triggers, daemonCertificateNames(ctx)), ReadHeaderTimeout: 10 * time.Second}
+
+		defer func() { _ = srv.Close() }()
+
+		go func() {
+			errS := srv.Serve(listener)
+			if errS != nil && !errors.Is(errS, http.ErrServerClosed) {
+				log.Warnf("Control server: %v", errS)
+			}
+		}()
+
+		log.Infof("Control listening on %s", listener.Addr())
+	}
+
+	runCtx, cancel := context.WithCancelCause(ctx.Context)
+	defer cancel(nil)
+
+	// The renewals run on runCtx: it's canceled when the in-flight orders are abandoned.
+	ctx.Context = runCtx
+
+	shutdown := newShutdownWatcher(ctx.Duration(flgShutdownGracePeriod))
+
+	stopping := make(chan struct{})
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go watchDaemonShutdown(shutdown, stopping, cancel, done)
+
+	go watchRenewalSignals(triggers, done)
+
+	interval := ctx.Duration(flgDaemonInterval)
+
+	// The loop ticks before and after each renewal check:
+	// the daemon is unhealthy when a renewal check is hung, or when the loop is not running anymore.
+	beat := newHeartbeat(interval + healthTimeout)
+
+	go runWatchdog(runCtx, beat.healthy)
+
+	_, err := sdNotify(sdNotifyReady)
+	if err != nil {
+		log.Warnf("Unable to notify systemd: %v", err)
+	}
+
+	// The trigger of the current renewal check: nil for the scheduled checks.
+	var trigger *renewalTrigger
+
+	for {
+		beat.tick()
+
+		accountsStorage.MarkUsed()
+
+		summaries, errR := renewCertificates(ctx, account, keyType, certsStorage, trigger, shutdown)
+
+		// The clients of the next renewal check are new clients.
+		shutdown.reset()
+
+		errR = finishRenewal(ctx, certsStorage, errR, summaries...)
+		if errR != nil {
+			log.Warnf("Renewal failed: %v", errR)
+		}
+
+		metrics.observeRenewals(summaries...)
+
+		// The daemon runs until it's stopped: the changes are uploaded after each renewal check, not only by After.
+		errP := pushStorage(ctx)
+		if errP != nil {
+			log.Warnf("%v", errP)
+		}
+
+		beat.tick()
+
+		log.Infof("Next renewal check in %s", interval)
+
+		select {
+		case <-stopping:
+			// nil if the in-flight renewal has completed.
+			return context.Cause(runCtx)
+		case <-time.After(interval):
+			trigger = nil
+		case t := <-triggers:
+			log.Infof("Immediate %s", &t)
+			trigger = &t
+		}
+	}
+}
+
+// watchDaemonShutdown handles the stop of the daemon (SIGTERM, SIGINT).
+// The daemon stops after the in-flight renewal, if any:
+// if the renewal is still running after the grace period (or on a second signal),
+// the in-flight orders are abandoned (see shutdownWatcher) and the renewal is canceled.
+// A third signal exits immediately.
+func watchDaemonShutdown(shutdown *shutdownWatcher, stopping chan<- struct{}, cancel context.CancelCauseFunc, done <-chan struct{}) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	defer signal.Stop(signals)
+
+	if !handleDaemonShutdown(signals, shutdown, stopping, cancel, done) {
+		os.Exit(1)
+	}
+}
+
+// handleDaemonShutdown handles the signals received by the daemon (see watchDaemonShutdown).
+// Returns false if the daemon must exit immediately.
+func handleDaemonShutdown(signals <-chan os.Signal, shutdown *shutdownWatcher, stopping chan<- struct{}, cancel context.CancelCauseFunc, done <-chan struct{}) bool {
+	var sig os.Signal
+
+	select {
+	case <-done:
+		return true
+	case sig = <-signals:
+	}
+
+	log.Infof("Received %s: stopping the daemon after the in-flight renewal (%s).", sig, shutdown.gracePeriod)
+
+	_, _ = sdNotify(sdNotifyStopping)
+
+	close(stopping)
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(shutdown.gracePeriod):
+	case <-signals:
+	}
+
+	log.Warnf("Abandoning the in-flight orders.")
+
+	shutdown.abandon()
+
+	cancel(fmt.Errorf("stopped by %s: the in-flight orders have been abandoned", sig))
+
+	select {
+	case <-done:
+		return true
+	case <-signals:
+		return false
+	}
+}
+
+// daemonCertificateNames returns the names of the certificates renewed by the daemon.
+// The names are unknown (nil) with a CSR.
+func daemonCertificateNames(ctx *cli.Context) []string {
+	if ctx.IsSet(flgCSR) {
+		return nil
+	}
+
+	var names []string
+
+	for _, additionalKeyType := range append([]string{""}, getAdditionalKeyTypes(ctx)...) {
+		names = append(names, certificateName(ctx.StringSlice(flgDomains)[0], additionalKeyType))
+	}
+
+	return names
+}
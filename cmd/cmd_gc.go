@@ -0,0 +1,360 @@
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/storage"
+	"github.com/urfave/cli/v2"
+)
+
+// Flag names.
+const (
+	flgGCAccountUnusedDays = "account-unused-days"
+	flgGCArchive           = "archive"
+	flgGCDelete            = "delete"
+)
+
+// certificateFileExts the extensions of the files of a certificate, the longest first.
+var certificateFileExts = []string{sanFailuresExt, issuerExt, renewalSummaryExt, ariCacheExt, pinsExt, certExt, keyExt, pemExt, pfxExt, jksExt, resourceExt}
+
+func createGC() *cli.Command {
+	return &cli.Command{
+		Name: "gc",
+		Usage: "Remove the orphaned keys, the superseded certificates, and the unused accounts from the storage." +
+			" A certificate is superseded by a more recent certificate with the same domains, the same key type, and the same issuer." +
+			" The files are only displayed, unless --archive or --delete is used.",
+		Before: func(ctx *cli.Context) error {
+			if ctx.Bool(flgGCArchive) && ctx.Bool(flgGCDelete) {
+				return fmt.Errorf("--%s and --%s are mutually exclusive", flgGCArchive, flgGCDelete)
+			}
+
+			return nil
+		},
+		Action: gc,
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  flgGCAccountUnusedDays,
+				Usage: "Remove the accounts unused for this number of days. The accounts are kept if not defined.",
+			},
+			&cli.BoolFlag{
+				Name:  flgGCArchive,
+				Usage: "Move the files to the archives directory.",
+			},
+			&cli.BoolFlag{
+				Name:  flgGCDelete,
+				Usage: "Remove the files definitively.",
+			},
+		},
+	}
+}
+
+func gc(ctx *cli.Context) error {
+	unusedDays := ctx.Int(flgGCAccountUnusedDays)
+	if unusedDays < 0 {
+		return fmt.Errorf("'%s' must be positive", flgGCAccountUnusedDays)
+	}
+
+	basePath := ctx.String(flgPath)
+
+	collector := &garbageCollector{
+		certificatesPath: filepath.Join(basePath, baseCertificatesFolderName),
+		accountsPath:     filepath.Join(basePath, baseAccountsRootFolderName),
+		now:              time.Now(),
+	}
+
+	items, err := collector.collect(time.Duration(unusedDays) * 24 * time.Hour)
+	if err != nil {
+		return err
+	}
+
+	if len(items) == 0 {
+		log.Println("Nothing to remove.")
+		return nil
+	}
+
+	for _, item := range items {
+		switch {
+		case ctx.Bool(flgGCArchive):
+			err = archiveGarbage(basePath, collector.now, item)
+			if err != nil {
+				return fmt.Errorf("[%s] %w", item.name, err)
+			}
+
+			log.Infof("[%s] Archived (%s): %s", item.name, item.reason, strings.Join(item.paths, ", "))
+
+		case ctx.Bool(flgGCDelete):
+			err = removeGarbage(item)
+			if err != nil {
+				return fmt.Errorf("[%s] %w", item.name, err)
+			}
+
+			log.Infof("[%s] Removed (%s): %s", item.name, item.reason, strings.Join(item.paths, ", "))
+
+		default:
+			log.Infof("[%s] %s: %s", item.name, item.reason, strings.Join(item.paths, ", "))
+		}
+	}
+
+	if !ctx.Bool(flgGCArchive) && !ctx.Bool(flgGCDelete) {
+		log.Infof("Nothing has been removed: use --%s or --%s to remove the files.", flgGCArchive, flgGCDelete)
+	}
+
+	return nil
+}
+
+// garbage the files of a certificate, or the directory of an account, to remove.
+type garbage struct {
+	name   string
+	reason string
+	paths  []string
+}
+
+// garbageCollector finds the garbage of the storage.
+type garbageCollector struct {
+	certificatesPath string
+	accountsPath     string
+	now              time.Time
+}
+
+// collect returns the orphaned files, the superseded certificates,
+// and the accounts unused since unusedFor (the accounts are kept if unusedFor is 0).
+func (c *garbageCollector) collect(unusedFor time.Duration) ([]garbage, error) {
+	files, err := c.certificateFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []garbage
+
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		if !slices.Contains(files[name], filepath.Join(c.certificatesPath, name+certExt)) {
+			result = append(result, garbage{name: name, reason: "orphaned files (no certificate)", paths: files[name]})
+		}
+	}
+
+	superseded, err := c.supersededCertificates(files)
+	if err != nil {
+		return nil, err
+	}
+
+	result = append(result, superseded...)
+
+	if unusedFor <= 0 {
+		return result, nil
+	}
+
+	accounts, err := c.unusedAccounts(unusedFor)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(result, accounts...), nil
+}
+
+// certificateFiles returns the files of the certificates directory by certificate name.
+// The unknown files are ignored.
+func (c *garbageCollector) certificateFiles() (map[string][]string, error) {
+	entries, err := os.ReadDir(c.certificatesPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]string)
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		for _, ext := range certificateFileExts {
+			name, ok := strings.CutSuffix(entry.Name(), ext)
+			if ok {
+				files[name] = append(files[name], filepath.Join(c.certificatesPath, entry.Name()))
+				break
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// supersededCertificates returns the certificates replaced by a more recent certificate
+// with the same domains, the same key type, and the same issuer (ex: a certificate obtained with the domains in another order).
+// The certificates of different CAs (ex: a backup CA) are kept.
+func (c *garbageCollector) supersededCertificates(files map[string][]string) ([]garbage, error) {
+	type candidate struct {
+		name string
+		cert *x509.Certificate
+	}
+
+	groups := make(map[string][]candidate)
+
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		certPath := filepath.Join(c.certificatesPath, name+certExt)
+		if !slices.Contains(files[name], certPath) {
+			continue
+		}
+
+		data, err := os.ReadFile(certPath)
+		if err != nil {
+			return nil, err
+		}
+
+		cert, err := certcrypto.ParsePEMCertificate(data)
+		if err != nil {
+			log.Warnf("[%s] Unable to parse the certificate: %v", name, err)
+			continue
+		}
+
+		key := certificateIdentity(cert)
+		groups[key] = append(groups[key], candidate{name: name, cert: cert})
+	}
+
+	var result []garbage
+
+	for _, key := range slices.Sorted(maps.Keys(groups)) {
+		group := groups[key]
+		if len(group) < 2 {
+			continue
+		}
+
+		// The most recent first.
+		slices.SortStableFunc(group, func(a, b candidate) int {
+			return b.cert.NotBefore.Compare(a.cert.NotBefore)
+		})
+
+		for _, old := range group[1:] {
+			result = append(result, garbage{
+				name:   old.name,
+				reason: "superseded by " + group[0].name,
+				paths:  files[old.name],
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// unusedAccounts returns the directories of the accounts unused since unusedFor.
+func (c *garbageCollector) unusedAccounts(unusedFor time.Duration) ([]garbage, error) {
+	accounts := &storage.Accounts{RootPath: c.accountsPath}
+
+	locations, err := accounts.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []garbage
+
+	for _, location := range locations {
+		lastUsed, err := accounts.ReadLastUsed(location)
+		if err != nil {
+			return nil, err
+		}
+
+		if c.now.Sub(lastUsed) < unusedFor {
+			continue
+		}
+
+		result = append(result, garbage{
+			name:   location.Server + "/" + location.UserID,
+			reason: "account unused since " + lastUsed.UTC().Format(time.DateOnly),
+			paths:  []string{accounts.UserPath(location)},
+		})
+	}
+
+	return result, nil
+}
+
+// certificateIdentity the issuer, the domains, and the key type of a certificate.
+// The issuer is identified by its organization (the intermediate certificates of a CA rotate), or by its name.
+func certificateIdentity(cert *x509.Certificate) string {
+	issuer := strings.Join(cert.Issuer.Organization, ",")
+	if issuer == "" {
+		issuer = cert.Issuer.CommonName
+	}
+
+	names := slices.Clone(cert.DNSNames)
+
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	keyType := cert.PublicKeyAlgorithm.String()
+
+	switch pub := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		keyType += strconv.Itoa(pub.N.BitLen())
+	case *ecdsa.PublicKey:
+		keyType += strconv.Itoa(pub.Curve.Params().BitSize)
+	}
+
+	return issuer + ":" + keyType + ":" + strings.Join(names, ",")
+}
+
+// removeGarbage removes the files, or the directories, of the garbage.
+func removeGarbage(item garbage) error {
+	for _, p := range item.paths {
+		err := os.RemoveAll(p)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// archiveGarbage moves the files, or the directories, of the garbage to the archives directory:
+// the names are prefixed by the date, as the files archived by the renewal.
+//
+//	./.lego/certificates/example.com.key -> ./.lego/archives/1700000000.example.com.key
+//	./.lego/accounts/localhost_14000/foo@example.com -> ./.lego/archives/accounts/localhost_14000/1700000000.foo@example.com
+func archiveGarbage(basePath string, now time.Time, item garbage) error {
+	date := strconv.FormatInt(now.Unix(), 10)
+
+	certificatesPath := filepath.Join(basePath, baseCertificatesFolderName)
+
+	for _, p := range item.paths {
+		dir := filepath.Join(basePath, baseArchivesFolderName)
+
+		if filepath.Dir(p) != certificatesPath {
+			rel, err := filepath.Rel(basePath, filepath.Dir(p))
+			if err != nil {
+				return err
+			}
+
+			dir = filepath.Join(dir, rel)
+		}
+
+		err := createNonExistingFolder(dir)
+		if err != nil {
+			return err
+		}
+
+		err = os.Rename(p, filepath.Join(dir, date+"."+filepath.Base(p)))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeGCTestCertificate(t *testing.T, dir, name string, notBefore time.Time, domains ...string) {
+	t.Helper()
+
+	writeGCTestCertificateIssuedBy(t, dir, name, "Test CA", notBefore, domains...)
+}
+
+func writeGCTestCertificateIssuedBy(t *testing.T, dir, name, issuer string, notBefore time.Time, domains ...string) {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: domains[0], Organization: []string{issuer}},
+		NotBefore:    notBefore,
+		NotAfter:     notBefore.Add(90 * 24 * time.Hour),
+		DNSNames:     domains,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(dir, name+certExt), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), filePerm)
+	require.NoError(t, err)
+
+	for _, ext := range []string{keyExt, issuerExt, resourceExt} {
+		err = os.WriteFile(filepath.Join(dir, name+ext), []byte(name), filePerm)
+		require.NoError(t, err)
+	}
+}
+
+func writeGCTestAccount(t *testing.T, dir, userID string, lastUsed time.Time) {
+	t.Helper()
+
+	rootUserPath := filepath.Join(dir, "localhost_14000", userID)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(rootUserPath, baseKeysFolderName), 0o700))
+
+	err := os.WriteFile(filepath.Join(rootUserPath, accountFileName), []byte("{}"), filePerm)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(rootUserPath, accountLastUsedFileName), []byte(lastUsed.Format(time.RFC3339)), filePerm)
+	require.NoError(t, err)
+}
+
+func setupGarbageCollector(t *testing.T) (*garbageCollector, string) {
+	t.Helper()
+
+	basePath := t.TempDir()
+
+	collector := &garbageCollector{
+		certificatesPath: filepath.Join(basePath, baseCertificatesFolderName),
+		accountsPath:     filepath.Join(basePath, baseAccountsRootFolderName),
+		now:              time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	require.NoError(t, os.MkdirAll(collector.certificatesPath, 0o700))
+
+	now := collector.now
+
+	writeGCTestCertificate(t, collector.certificatesPath, "example.com", now.Add(-10*24*time.Hour), "example.com", "www.example.com")
+	writeGCTestCertificate(t, collector.certificatesPath, "www.example.com", now.Add(-80*24*time.Hour), "www.example.com", "example.com")
+	writeGCTestCertificate(t, collector.certificatesPath, "example.org", now.Add(-80*24*time.Hour), "example.org")
+
+	// orphaned files
+	for _, ext := range []string{keyExt, resourceExt} {
+		err := os.WriteFile(filepath.Join(collector.certificatesPath, "old.example.com"+ext), []byte("old"), filePerm)
+		require.NoError(t, err)
+	}
+
+	// unknown file
+	err := os.WriteFile(filepath.Join(collector.certificatesPath, "README"), []byte("readme"), filePerm)
+	require.NoError(t, err)
+
+	writeGCTestAccount(t, collector.accountsPath, "foo@example.com", now.Add(-24*time.Hour))
+	writeGCTestAccount(t, collector.accountsPath, "bar@example.com", now.Add(-400*24*time.Hour))
+
+	return collector, basePath
+}
+
+func Test_garbageCollector_collect(t *testing.T) {
+	collector, _ := setupGarbageCollector(t)
+
+	items, err := collector.collect(365 * 24 * time.Hour)
+	require.NoError(t, err)
+
+	certsPath := collector.certificatesPath
+
+	expected := []garbage{
+		{
+			name:   "old.example.com",
+			reason: "orphaned files (no certificate)",
+			paths: []string{
+				filepath.Join(certsPath, "old.example.com.json"),
+				filepath.Join(certsPath, "old.example.com.key"),
+			},
+		},
+		{
+			name:   "www.example.com",
+			reason: "superseded by example.com",
+			paths: []string{
+				filepath.Join(certsPath, "www.example.com.crt"),
+				filepath.Join(certsPath, "www.example.com.issuer.crt"),
+				filepath.Join(certsPath, "www.example.com.json"),
+				filepath.Join(certsPath, "www.example.com.key"),
+			},
+		},
+		{
+			name:   "localhost_14000/bar@example.com",
+			reason: "account unused since 2024-04-27",
+			paths:  []string{filepath.Join(collector.accountsPath, "localhost_14000", "bar@example.com")},
+		},
+	}
+
+	assert.Equal(t, expected, items)
+}
+
+func Test_garbageCollector_collect_otherIssuer(t *testing.T) {
+	collector, _ := setupGarbageCollector(t)
+
+	// The same domains as example.org, from another CA.
+	writeGCTestCertificateIssuedBy(t, collector.certificatesPath, "backup.example.org", "Backup CA", collector.now, "example.org")
+
+	items, err := collector.collect(0)
+	require.NoError(t, err)
+
+	for _, item := range items {
+		assert.NotEqual(t, "example.org", item.name)
+		assert.NotEqual(t, "backup.example.org", item.name)
+	}
+}
+
+func Test_garbageCollector_collect_keepAccounts(t *testing.T) {
+	collector, _ := setupGarbageCollector(t)
+
+	items, err := collector.collect(0)
+	require.NoError(t, err)
+
+	require.Len(t, items, 2)
+
+	for _, item := range items {
+		assert.NotEqual(t, "localhost_14000/bar@example.com", item.name)
+	}
+}
+
+func Test_archiveGarbage(t *testing.T) {
+	collector, basePath := setupGarbageCollector(t)
+
+	items, err := collector.collect(365 * 24 * time.Hour)
+	require.NoError(t, err)
+
+	for _, item := range items {
+		err = archiveGarbage(basePath, collector.now, item)
+		require.NoError(t, err)
+	}
+
+	archivePath := filepath.Join(basePath, baseArchivesFolderName)
+
+	assert.FileExists(t, filepath.Join(archivePath, "1748736000.old.example.com.key"))
+	assert.FileExists(t, filepath.Join(archivePath, "1748736000.www.example.com.crt"))
+	assert.DirExists(t, filepath.Join(archivePath, baseAccountsRootFolderName, "localhost_14000", "1748736000.bar@example.com"))
+
+	assert.NoFileExists(t, filepath.Join(collector.certificatesPath, "old.example.com.key"))
+	assert.NoFileExists(t, filepath.Join(collector.certificatesPath, "www.example.com.crt"))
+	assert.NoDirExists(t, filepath.Join(collector.accountsPath, "localhost_14000", "bar@example.com"))
+
+	assert.FileExists(t, filepath.Join(collector.certificatesPath, "example.com.crt"))
+	assert.FileExists(t, filepath.Join(collector.certificatesPath, "README"))
+	assert.DirExists(t, filepath.Join(collector.accountsPath, "localhost_14000", "foo@example.com"))
+}
+
+func Test_removeGarbage(t *testing.T) {
+	collector, basePath := setupGarbageCollector(t)
+
+	items, err := collector.collect(365 * 24 * time.Hour)
+	require.NoError(t, err)
+
+	for _, item := range items {
+		err = removeGarbage(item)
+		require.NoError(t, err)
+	}
+
+	assert.NoDirExists(t, filepath.Join(basePath, baseArchivesFolderName))
+	assert.NoFileExists(t, filepath.Join(collector.certificatesPath, "www.example.com.key"))
+	assert.NoDirExists(t, filepath.Join(collector.accountsPath, "localhost_14000", "bar@example.com"))
+	assert.FileExists(t, filepath.Join(collector.certificatesPath, "example.com.key"))
+}
@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/platform/storage"
+	"github.com/urfave/cli/v2"
+)
+
+// Flag names.
+const flgPlanConfig = "config"
+
+// Plan actions.
+const (
+	PlanActionIssue  = "issue"
+	PlanActionRenew  = "renew"
+	PlanActionRevoke = "revoke"
+	PlanActionNone   = "none"
+)
+
+func createPlan() *cli.Command {
+	return &cli.Command{
+		Name: "plan",
+		Usage: "Compute the certificates to issue, renew, or revoke, based on a configuration file and the current certificates." +
+			" The CA is never contacted.",
+		Action: plan,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     flgPlanConfig,
+				Usage:    "Path to the configuration file (TOML) describing the expected certificates.",
+				Required: true,
+			},
+			&cli.IntFlag{
+				Name:  flgRenewDays,
+				Value: 30,
+				Usage: "The number of days left on a certificate to renew it.",
+			},
+			&cli.BoolFlag{
+				Name:  flgRenewDynamic,
+				Usage: "Compute dynamically, based on the lifetime of the certificate(s), when to renew.",
+			},
+			&cli.StringFlag{
+				Name:  flgRenewWindow,
+				Usage: "The remaining validity of a certificate to renew it (ex: 30d, 72h). This supersedes --days and --dynamic.",
+			},
+			&cli.IntFlag{
+				Name:  flgRenewPercent,
+				Usage: "The remaining percentage of the lifetime of a certificate to renew it (ex: 33). This supersedes --days and --dynamic.",
+			},
+			&cli.DurationFlag{
+				Name:  flgRenewSpread,
+				Usage: "Spread the renewals over this duration, based on a hash of the domain.",
+			},
+			&cli.StringSliceFlag{
+				Name: flgAdditionalKeyType,
+				Usage: "Key type of an additional certificate expected for the domains of each certificate (ex: rsa2048)." +
+					" The files are named with the key type (ex: example.com_rsa2048.crt). This is added to the additional_key_types of the configuration file.",
+			},
+		},
+	}
+}
+
+// PlanConfig the configuration file of the plan command.
+//
+//	[[certificates]]
+//	domains = ["example.com", "www.example.com"]
+//
+//	[[certificates]]
+//	domains = ["example.org"]
+//	renew_percent = 33
+//	additional_key_types = ["rsa2048"]
+type PlanConfig struct {
+	Certificates []PlanCertificate `toml:"certificates"`
+}
+
+// PlanCertificate an expected certificate.
+// The first domain is the main domain, it's used to find the certificate in the storage.
+//
+// RenewWindow and RenewPercent override, for this certificate, the renewal flags of the command.
+//
+// AdditionalKeyTypes the key types of the additional certificates obtained for the same domains (see --additional-key-type).
+type PlanCertificate struct {
+	Domains            []string `toml:"domains"`
+	RenewWindow        string   `toml:"renew_window"`
+	RenewPercent       int      `toml:"renew_percent"`
+	AdditionalKeyTypes []string `toml:"additional_key_types"`
+}
+
+// Plan the changes to apply to the certificates.
+type Plan struct {
+	Changes []PlanChange `json:"changes"`
+}
+
+// PlanChange a change to apply to a certificate.
+//
+// KeyType is the key type of an additional certificate (see --additional-key-type), it's empty for the main certificate.
+type PlanChange struct {
+	Action  string   `json:"action"`
+	Domain  string   `json:"domain"`
+	KeyType string   `json:"key_type,omitempty"`
+	Domains []string `json:"domains,omitempty"`
+	Current []string `json:"current,omitempty"`
+	Reason  string   `json:"reason,omitempty"`
+}
+
+func plan(ctx *cli.Context) error {
+	config, err := readPlanConfig(ctx.String(flgPlanConfig))
+	if err != nil {
+		return err
+	}
+
+	err = config.addKeyTypes(ctx.StringSlice(flgAdditionalKeyType))
+	if err != nil {
+		return err
+	}
+
+	policy, err := newRenewalPolicy(ctx)
+	if err != nil {
+		return err
+	}
+
+	backend, err := newStorageBackend(ctx.String(flgStorage))
+	if err != nil {
+		return err
+	}
+
+	if backend == nil {
+		backend = storage.NewFileSystem(ctx.String(flgPath))
+	}
+
+	result, err := computePlan(ctx.Context, config, NewCertificatesStorage(ctx), backend, policy)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(result)
+}
+
+func readPlanConfig(filename string) (*PlanConfig, error) {
+	config := &PlanConfig{}
+
+	_, err := toml.DecodeFile(filename, config)
+	if err != nil {
+		return nil, fmt.Errorf("read plan configuration: %w", err)
+	}
+
+	for i, cert := range config.Certificates {
+		if len(cert.Domains) == 0 {
+			return nil, fmt.Errorf("read plan configuration: certificates[%d]: the domains are required", i)
+		}
+
+		if cert.RenewWindow != "" && cert.RenewPercent != 0 {
+			return nil, fmt.Errorf("read plan configuration: certificates[%d]: renew_window and renew_percent are mutually exclusive", i)
+		}
+
+		_, err = renewalPolicy{}.with(cert.RenewWindow, cert.RenewPercent)
+		if err != nil {
+			return nil, fmt.Errorf("read plan configuration: certificates[%d]: %w", i, err)
+		}
+
+		config.Certificates[i].AdditionalKeyTypes = nil
+
+		err = config.Certificates[i].addKeyTypes(cert.AdditionalKeyTypes)
+		if err != nil {
+			return nil, fmt.Errorf("read plan configuration: certificates[%d]: %w", i, err)
+		}
+	}
+
+	return config, nil
+}
+
+// addKeyTypes adds the additional key types to all the certificates.
+func (c *PlanConfig) addKeyTypes(keyTypes []string) error {
+	for i := range c.Certificates {
+		err := c.Certificates[i].addKeyTypes(keyTypes)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addKeyTypes adds the additional key types to the certificate.
+// The values are normalized (lower case) because they are used to name the files of the certificates.
+func (c *PlanCertificate) addKeyTypes(keyTypes []string) error {
+	for _, value := range keyTypes {
+		_, err := toKeyType(value)
+		if err != nil {
+			return err
+		}
+
+		name := strings.ToLower(value)
+
+		if !slices.Contains(c.AdditionalKeyTypes, name) {
+			c.AdditionalKeyTypes = append(c.AdditionalKeyTypes, name)
+		}
+	}
+
+	return nil
+}
+
+// computePlan computes the changes of the expected certificates (read from the local directory),
+// and the certificates to revoke (listed from the storage backend, it includes the certificates only known to a remote storage).
+func computePlan(ctx context.Context, config *PlanConfig, certsStorage *CertificatesStorage, backend storage.Backend, policy renewalPolicy) (*Plan, error) {
+	result := &Plan{Changes: []PlanChange{}}
+
+	expected := make(map[string]struct{})
+
+	for _, cert := range config.Certificates {
+		domain := cert.Domains[0]
+
+		for _, keyType := range slices.Concat([]string{""}, cert.AdditionalKeyTypes) {
+			expected[certificateName(sanitizedDomain(domain), keyType)] = struct{}{}
+
+			change, err := planCertificate(cert, keyType, certsStorage, policy)
+			if err != nil {
+				return nil, err
+			}
+
+			result.Changes = append(result.Changes, change)
+		}
+	}
+
+	orphans, err := planOrphans(ctx, backend, expected)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Changes = append(result.Changes, orphans...)
+
+	return result, nil
+}
+
+// planOrphans plans the revocation of the certificates of the storage backend that are not expected.
+func planOrphans(ctx context.Context, backend storage.Backend, expected map[string]struct{}) ([]PlanChange, error) {
+	keys, err := backend.List(ctx, baseCertificatesFolderName)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []PlanChange
+
+	for _, key := range keys {
+		if !strings.HasSuffix(key, certExt) || strings.HasSuffix(key, issuerExt) || path.Dir(key) != baseCertificatesFolderName {
+			continue
+		}
+
+		name := strings.TrimSuffix(path.Base(key), certExt)
+		if _, ok := expected[name]; ok {
+			continue
+		}
+
+		data, err := backend.Load(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("read certificate %s: %w", key, err)
+		}
+
+		certificates, err := certcrypto.ParsePEMBundle(data)
+		if err != nil {
+			return nil, fmt.Errorf("read certificate %s: %w", key, err)
+		}
+
+		if len(certificates) == 0 {
+			return nil, errors.New("no certificate found for " + name)
+		}
+
+		changes = append(changes, PlanChange{
+			Action:  PlanActionRevoke,
+			Domain:  name,
+			Current: certcrypto.ExtractDomains(certificates[0]),
+			Reason:  "not in the configuration",
+		})
+	}
+
+	return changes, nil
+}
+
+// planCertificate plans the change of the main certificate (empty key type) or of an additional certificate of the domains.
+func planCertificate(cert PlanCertificate, keyType string, certsStorage *CertificatesStorage, policy renewalPolicy) (PlanChange, error) {
+	domain := cert.Domains[0]
+
+	policy, err := policy.with(cert.RenewWindow, cert.RenewPercent)
+	if err != nil {
+		return PlanChange{}, fmt.Errorf("domain %s: %w", domain, err)
+	}
+
+	change := PlanChange{
+		Domain:  domain,
+		KeyType: keyType,
+		Domains: cert.Domains,
+	}
+
+	name := certificateName(domain, keyType)
+
+	if !certsStorage.ExistsFile(name, certExt) {
+		change.Action = PlanActionIssue
+		change.Reason = "no certificate"
+
+		return change, nil
+	}
+
+	certificates, err := certsStorage.ReadCertificate(name, certExt)
+	if err != nil {
+		return PlanChange{}, fmt.Errorf("read certificate %s: %w", name, err)
+	}
+
+	if len(certificates) == 0 {
+		return PlanChange{}, errors.New("no certificate found for " + name)
+	}
+
+	change.Current = certcrypto.ExtractDomains(certificates[0])
+
+	switch {
+	case !sameDomains(change.Current, cert.Domains):
+		change.Action = PlanActionRenew
+		change.Reason = "the domains have changed"
+
+	case needRenewal(certificates[0], domain, policy):
+		change.Action = PlanActionRenew
+		change.Reason = "the certificate is about to expire"
+
+	default:
+		change.Action = PlanActionNone
+	}
+
+	return change, nil
+}
+
+func sameDomains(a, b []string) bool {
+	a = slices.Clone(a)
+	slices.Sort(a)
+
+	b = slices.Clone(b)
+	slices.Sort(b)
+
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
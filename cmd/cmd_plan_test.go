@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/platform/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPlanStorage returns the storage of the certificates and the backend listing them (the data directory).
+func newTestPlanStorage(t *testing.T) (*CertificatesStorage, storage.Backend) {
+	t.Helper()
+
+	root := t.TempDir()
+
+	rootPath := filepath.Join(root, baseCertificatesFolderName)
+
+	err := os.MkdirAll(rootPath, 0o700)
+	require.NoError(t, err)
+
+	return &CertificatesStorage{rootPath: rootPath}, storage.NewFileSystem(root)
+}
+
+func writeTestCertificate(t *testing.T, certsStorage *CertificatesStorage, notAfter time.Time, domains ...string) {
+	t.Helper()
+
+	writeTestCertificateAs(t, certsStorage, domains[0], notAfter, domains...)
+}
+
+// writeTestCertificateAs writes a certificate in the files of a certificate name (see certificateName).
+func writeTestCertificateAs(t *testing.T, certsStorage *CertificatesStorage, name string, notAfter time.Time, domains ...string) {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: domains[0]},
+		NotBefore:    time.Now().Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+		DNSNames:     domains,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	require.NoError(t, err)
+
+	err = certsStorage.WriteFile(name, certExt, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	require.NoError(t, err)
+}
+
+func Test_readPlanConfig(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "plan.toml")
+
+	err := os.WriteFile(filename, []byte(`
+[[certificates]]
+domains = ["example.com", "www.example.com"]
+
+[[certificates]]
+domains = ["*.example.org"]
+renew_percent = 33
+additional_key_types = ["RSA2048", "ec384", "rsa2048"]
+`), 0o600)
+	require.NoError(t, err)
+
+	config, err := readPlanConfig(filename)
+	require.NoError(t, err)
+
+	expected := &PlanConfig{Certificates: []PlanCertificate{
+		{Domains: []string{"example.com", "www.example.com"}},
+		{Domains: []string{"*.example.org"}, RenewPercent: 33, AdditionalKeyTypes: []string{"rsa2048", "ec384"}},
+	}}
+
+	assert.Equal(t, expected, config)
+}
+
+func Test_readPlanConfig_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		content  string
+		expected string
+	}{
+		{
+			desc: "window and percent",
+			content: `
+[[certificates]]
+domains = ["example.com"]
+renew_window = "30d"
+renew_percent = 33
+`,
+			expected: "read plan configuration: certificates[0]: renew_window and renew_percent are mutually exclusive",
+		},
+		{
+			desc: "invalid window",
+			content: `
+[[certificates]]
+domains = ["example.com"]
+renew_window = "30"
+`,
+			expected: `read plan configuration: certificates[0]: invalid renewal window "30": must be a positive duration (ex: 30d, 72h)`,
+		},
+		{
+			desc: "invalid key type",
+			content: `
+[[certificates]]
+domains = ["example.com"]
+additional_key_types = ["rsa1024"]
+`,
+			expected: "read plan configuration: certificates[0]: unsupported key type: rsa1024",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "plan.toml")
+
+			err := os.WriteFile(filename, []byte(test.content), 0o600)
+			require.NoError(t, err)
+
+			_, err = readPlanConfig(filename)
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func Test_computePlan(t *testing.T) {
+	certsStorage, backend := newTestPlanStorage(t)
+
+	writeTestCertificate(t, certsStorage, time.Now().AddDate(0, 2, 0), "unchanged.com")
+	writeTestCertificate(t, certsStorage, time.Now().AddDate(0, 0, 10), "expiring.com")
+	writeTestCertificate(t, certsStorage, time.Now().AddDate(0, 2, 0), "changed.com")
+	writeTestCertificate(t, certsStorage, time.Now().AddDate(0, 2, 0), "removed.com")
+
+	config := &PlanConfig{Certificates: []PlanCertificate{
+		{Domains: []string{"unchanged.com"}},
+		{Domains: []string{"expiring.com"}},
+		{Domains: []string{"changed.com", "www.changed.com"}},
+		{Domains: []string{"new.com"}},
+	}}
+
+	result, err := computePlan(t.Context(), config, certsStorage, backend, renewalPolicy{days: 30})
+	require.NoError(t, err)
+
+	expected := &Plan{Changes: []PlanChange{
+		{
+			Action:  PlanActionNone,
+			Domain:  "unchanged.com",
+			Domains: []string{"unchanged.com"},
+			Current: []string{"unchanged.com"},
+		},
+		{
+			Action:  PlanActionRenew,
+			Domain:  "expiring.com",
+			Domains: []string{"expiring.com"},
+			Current: []string{"expiring.com"},
+			Reason:  "the certificate is about to expire",
+		},
+		{
+			Action:  PlanActionRenew,
+			Domain:  "changed.com",
+			Domains: []string{"changed.com", "www.changed.com"},
+			Current: []string{"changed.com"},
+			Reason:  "the domains have changed",
+		},
+		{
+			Action:  PlanActionIssue,
+			Domain:  "new.com",
+			Domains: []string{"new.com"},
+			Reason:  "no certificate",
+		},
+		{
+			Action:  PlanActionRevoke,
+			Domain:  "removed.com",
+			Current: []string{"removed.com"},
+			Reason:  "not in the configuration",
+		},
+	}}
+
+	assert.Equal(t, expected, result)
+}
+
+func Test_computePlan_renewalOverride(t *testing.T) {
+	certsStorage, backend := newTestPlanStorage(t)
+
+	writeTestCertificate(t, certsStorage, time.Now().AddDate(0, 2, 0), "percent.com")
+	writeTestCertificate(t, certsStorage, time.Now().AddDate(0, 0, 10), "window.com")
+
+	config := &PlanConfig{Certificates: []PlanCertificate{
+		{Domains: []string{"percent.com"}, RenewPercent: 99},
+		{Domains: []string{"window.com"}, RenewWindow: "5d"},
+	}}
+
+	result, err := computePlan(t.Context(), config, certsStorage, backend, renewalPolicy{days: 30})
+	require.NoError(t, err)
+
+	expected := &Plan{Changes: []PlanChange{
+		{
+			Action:  PlanActionRenew,
+			Domain:  "percent.com",
+			Domains: []string{"percent.com"},
+			Current: []string{"percent.com"},
+			Reason:  "the certificate is about to expire",
+		},
+		{
+			Action:  PlanActionNone,
+			Domain:  "window.com",
+			Domains: []string{"window.com"},
+			Current: []string{"window.com"},
+		},
+	}}
+
+	assert.Equal(t, expected, result)
+}
+
+func Test_computePlan_additionalKeyTypes(t *testing.T) {
+	certsStorage, backend := newTestPlanStorage(t)
+
+	writeTestCertificate(t, certsStorage, time.Now().AddDate(0, 2, 0), "example.com")
+	writeTestCertificateAs(t, certsStorage, "example.com_rsa2048", time.Now().AddDate(0, 2, 0), "example.com")
+	writeTestCertificateAs(t, certsStorage, "example.com_ec384", time.Now().AddDate(0, 0, 10), "example.com")
+	writeTestCertificateAs(t, certsStorage, "example.com_ec256", time.Now().AddDate(0, 2, 0), "example.com")
+
+	config := &PlanConfig{Certificates: []PlanCertificate{
+		{Domains: []string{"example.com"}, AdditionalKeyTypes: []string{"rsa2048"}},
+	}}
+
+	err := config.addKeyTypes([]string{"RSA2048", "ec384"})
+	require.NoError(t, err)
+
+	result, err := computePlan(t.Context(), config, certsStorage, backend, renewalPolicy{days: 30})
+	require.NoError(t, err)
+
+	expected := &Plan{Changes: []PlanChange{
+		{
+			Action:  PlanActionNone,
+			Domain:  "example.com",
+			Domains: []string{"example.com"},
+			Current: []string{"example.com"},
+		},
+		{
+			Action:  PlanActionNone,
+			Domain:  "example.com",
+			KeyType: "rsa2048",
+			Domains: []string{"example.com"},
+			Current: []string{"example.com"},
+		},
+		{
+			Action:  PlanActionRenew,
+			Domain:  "example.com",
+			KeyType: "ec384",
+			Domains: []string{"example.com"},
+			Current: []string{"example.com"},
+			Reason:  "the certificate is about to expire",
+		},
+		{
+			Action:  PlanActionRevoke,
+			Domain:  "example.com_ec256",
+			Current: []string{"example.com"},
+			Reason:  "not in the configuration",
+		},
+	}}
+
+	assert.Equal(t, expected, result)
+}
+
+func Test_computePlan_remoteOrphan(t *testing.T) {
+	// The local directory is empty: the certificate is only known to the remote storage.
+	certsStorage, _ := newTestPlanStorage(t)
+
+	remoteStorage, remote := newTestPlanStorage(t)
+
+	writeTestCertificate(t, remoteStorage, time.Now().AddDate(0, 2, 0), "removed.com")
+
+	result, err := computePlan(t.Context(), &PlanConfig{}, certsStorage, remote, renewalPolicy{days: 30})
+	require.NoError(t, err)
+
+	expected := &Plan{Changes: []PlanChange{{
+		Action:  PlanActionRevoke,
+		Domain:  "removed.com",
+		Current: []string{"removed.com"},
+		Reason:  "not in the configuration",
+	}}}
+
+	assert.Equal(t, expected, result)
+}
+
+func Test_computePlan_invalidOrphan(t *testing.T) {
+	certsStorage, backend := newTestPlanStorage(t)
+
+	err := backend.Save(t.Context(), "certificates/empty.com.crt", nil)
+	require.NoError(t, err)
+
+	_, err = computePlan(t.Context(), &PlanConfig{}, certsStorage, backend, renewalPolicy{days: 30})
+	require.EqualError(t, err, "read certificate certificates/empty.com.crt: no certificates were found while parsing the bundle")
+}
@@ -4,9 +4,14 @@ import (
 	"crypto"
 	"crypto/x509"
 	"errors"
+	"fmt"
+	"hash/fnv"
+	"maps"
 	"math/rand"
 	"os"
 	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-acme/lego/v4/acme/api"
@@ -22,6 +27,9 @@ import (
 const (
 	flgRenewDays              = "days"
 	flgRenewDynamic           = "dynamic"
+	flgRenewWindow            = "renew-window"
+	flgRenewPercent           = "renew-percent"
+	flgRenewSpread            = "renew-spread"
 	flgARIDisable             = "ari-disable"
 	flgARIWaitToRenewDuration = "ari-wait-to-renew-duration"
 	flgReuseKey               = "reuse-key"
@@ -29,6 +37,11 @@ const (
 	flgRenewHookTimeout       = "renew-hook-timeout"
 	flgNoRandomSleep          = "no-random-sleep"
 	flgForceCertDomains       = "force-cert-domains"
+	flgDropFailingSANs        = "drop-failing-sans"
+	flgDropFailingSANsAfter   = "drop-failing-sans-after"
+	flgDropFailingSANsWindow  = "drop-failing-sans-window"
+	flgRenewalSummaryURL      = "renewal-summary-url"
+	flgUnchangedExitCode      = "unchanged-exit-code"
 )
 
 func createRenew() *cli.Command {
@@ -49,10 +62,33 @@ func createRenew() *cli.Command {
 				log.Fatalf("Please specify --%s/-d (or --%s/-c if you already have a CSR)", flgDomains, flgCSR)
 			}
 
+			if ctx.IsSet(flgAdditionalKeyType) && hasCsr {
+				log.Fatalf("--%s only works with --%s/-d, --%s/-c doesn't support this option.", flgAdditionalKeyType, flgDomains, flgCSR)
+			}
+
+			getAdditionalKeyTypes(ctx)
+
 			if ctx.Bool(flgForceCertDomains) && hasCsr {
 				log.Fatalf("--%s only works with --%s/-d, --%s/-c doesn't support this option.", flgForceCertDomains, flgDomains, flgCSR)
 			}
 
+			if ctx.Bool(flgDropFailingSANs) && hasCsr {
+				log.Fatalf("--%s only works with --%s/-d, --%s/-c doesn't support this option.", flgDropFailingSANs, flgDomains, flgCSR)
+			}
+
+			if ctx.Int(flgDropFailingSANsAfter) < 1 {
+				log.Fatalf("--%s must be greater than zero.", flgDropFailingSANsAfter)
+			}
+
+			if ctx.Duration(flgDropFailingSANsWindow) < 0 {
+				log.Fatalf("--%s must be positive.", flgDropFailingSANsWindow)
+			}
+
+			_, err := newRenewalPolicy(ctx)
+			if err != nil {
+				log.Fatal(err)
+			}
+
 			return nil
 		},
 		Flags: []cli.Flag{
@@ -67,6 +103,20 @@ func createRenew() *cli.Command {
 				Value: false,
 				Usage: "Compute dynamically, based on the lifetime of the certificate(s), when to renew: use 1/3rd of the lifetime left, or 1/2 of the lifetime for short-lived certificates). This supersedes --days and will be the default behavior in Lego v5.",
 			},
+			&cli.StringFlag{
+				Name:  flgRenewWindow,
+				Usage: "The remaining validity of a certificate to renew it (ex: 30d, 72h). This supersedes --days and --dynamic.",
+			},
+			&cli.IntFlag{
+				Name: flgRenewPercent,
+				Usage: "The remaining percentage of the lifetime of a certificate to renew it (ex: 33 renews when a third of the lifetime is left)." +
+					" The lifetime is computed from the certificate. This supersedes --days and --dynamic.",
+			},
+			&cli.DurationFlag{
+				Name: flgRenewSpread,
+				Usage: "Spread the renewals over this duration: the renewal date of a certificate is moved earlier by a deterministic offset based on a hash of the domain." +
+					" Avoids the renewal of the certificates of a fleet of instances at the same time.",
+			},
 			&cli.BoolFlag{
 				Name:  flgARIDisable,
 				Usage: "Do not use the renewalInfo endpoint (RFC9773) to check if a certificate should be renewed.",
@@ -120,15 +170,54 @@ func createRenew() *cli.Command {
 				Usage: "Define the timeout for the hook execution.",
 				Value: 2 * time.Minute,
 			},
+			&cli.StringSliceFlag{
+				Name: flgAdditionalKeyType,
+				Usage: "Key type of an additional certificate renewed for the same domains (ex: rsa2048 with --key-type ec256)." +
+					" The files are named with the key type (ex: example.com_rsa2048.crt). Supported: rsa2048, rsa3072, rsa4096, rsa8192, ec256, ec384.",
+			},
+			&cli.StringFlag{
+				Name: flgIssuedHook,
+				Usage: "Define a hook executed with the new certificate before the files are written (ex: registration in an inventory or a CT monitor)." +
+					" The certificate is written on the standard input, the SCTs are in LEGO_CERT_SCTS. If the hook fails, the files are not written.",
+			},
+			&cli.DurationFlag{
+				Name:  flgIssuedHookTimeout,
+				Usage: "Define the timeout for the issued hook execution.",
+				Value: 2 * time.Minute,
+			},
 			&cli.BoolFlag{
 				Name: flgNoRandomSleep,
 				Usage: "Do not add a random sleep before the renewal." +
 					" We do not recommend using this flag if you are doing your renewals in an automated way.",
 			},
+			&cli.StringFlag{
+				Name:  flgRenewalSummaryURL,
+				Usage: "Send the renewal summary (JSON) to this URL with a POST request. The summary is always written next to the certificate (<domain>.renewal.json).",
+			},
+			&cli.IntFlag{
+				Name: flgUnchangedExitCode,
+				Usage: "Exit with this code when the certificate is not renewed (ex: to report 'unchanged' to a configuration management tool)." +
+					" By default, a skipped renewal exits with 0.",
+			},
 			&cli.BoolFlag{
 				Name:  flgForceCertDomains,
 				Usage: "Check and ensure that the cert's domain list matches those passed in the domains argument.",
 			},
+			&cli.BoolFlag{
+				Name: flgDropFailingSANs,
+				Usage: "If the challenges of some domains fail repeatedly, renew the certificate without these domains instead of failing the renewal." +
+					" The main domain is never dropped. The dropped domains are retried at the next renewal.",
+			},
+			&cli.IntFlag{
+				Name:  flgDropFailingSANsAfter,
+				Value: 3,
+				Usage: "With --" + flgDropFailingSANs + ", the number of consecutive failed renewals after which a failing domain is dropped.",
+			},
+			&cli.DurationFlag{
+				Name: flgDropFailingSANsWindow,
+				Usage: "With --" + flgDropFailingSANs + ", a failing domain is also dropped when its first consecutive failure is older than this duration (ex: 72h)." +
+					" Disabled by default.",
+			},
 		},
 	}
 }
@@ -137,40 +226,115 @@ func renew(ctx *cli.Context) error {
 	account, keyType := setupAccount(ctx, NewAccountsStorage(ctx))
 
 	if account.Registration == nil {
-		log.Fatalf("Account %s is not registered. Use 'run' to register a new account.\n", account.Email)
+		return fmt.Errorf("account %s is not registered, use 'run' to register a new account", account.Email)
 	}
 
 	certsStorage := NewCertificatesStorage(ctx)
 
+	shutdown := newShutdownWatcher(ctx.Duration(flgShutdownGracePeriod))
+
+	return shutdown.run(ctx.Context, func() error {
+		summaries, err := renewCertificates(ctx, account, keyType, certsStorage, nil, shutdown)
+
+		return finishRenewal(ctx, certsStorage, err, summaries...)
+	})
+}
+
+// renewCertificates renews the certificate of the CSR,
+// or the certificate of the domains, then the additional certificates with the same domains.
+// The renewal stops at the first error.
+// The trigger (daemon) selects the certificates to evaluate, and may force their renewal: nil evaluates all the certificates.
+// The clients are watched by shutdown.
+func renewCertificates(ctx *cli.Context, account *Account, keyType certcrypto.KeyType, certsStorage *CertificatesStorage, trigger *renewalTrigger, shutdown *shutdownWatcher) ([]*RenewalSummary, error) {
 	bundle := !ctx.Bool(flgNoBundle)
 
 	meta := map[string]string{
 		hookEnvAccountEmail: account.Email,
 	}
 
-	// CSR
 	if ctx.IsSet(flgCSR) {
-		return renewForCSR(ctx, account, keyType, certsStorage, bundle, meta)
+		// CSR
+		summary := newRenewalSummary()
+
+		err := renewForCSR(ctx, account, keyType, certsStorage, bundle, trigger.forced(), meta, summary, shutdown)
+
+		return []*RenewalSummary{summary}, err
+	}
+
+	// Domains: the main certificate, then the additional certificates with the same domains.
+	var summaries []*RenewalSummary
+
+	for _, additionalKeyType := range append([]string{""}, getAdditionalKeyTypes(ctx)...) {
+		if !trigger.selects(certificateName(ctx.StringSlice(flgDomains)[0], additionalKeyType)) {
+			continue
+		}
+
+		summary := newRenewalSummary()
+		summaries = append(summaries, summary)
+
+		err := renewForDomains(ctx, account, keyType, additionalKeyType, certsStorage, bundle, trigger.forced(), maps.Clone(meta), summary, shutdown)
+		if err != nil {
+			return summaries, err
+		}
+	}
+
+	if len(summaries) == 0 {
+		return nil, fmt.Errorf("unknown certificate: %s", trigger.Name)
+	}
+
+	return summaries, nil
+}
+
+// finishRenewal reports the renewal of the certificates,
+// and returns the exit code defined by the flag when none of the certificates has been renewed.
+// The error is the error of the last certificate.
+func finishRenewal(ctx *cli.Context, certsStorage *CertificatesStorage, err error, summaries ...*RenewalSummary) error {
+	unchanged := err == nil
+
+	for i, summary := range summaries {
+		if i == len(summaries)-1 {
+			summary.finish(err)
+		} else {
+			summary.finish(nil)
+		}
+
+		reportRenewal(ctx, certsStorage, summary)
+
+		unchanged = unchanged && summary.Status == RenewalStatusSkipped
+	}
+
+	if unchanged && ctx.Int(flgUnchangedExitCode) != 0 {
+		return cli.Exit("", ctx.Int(flgUnchangedExitCode))
 	}
 
-	// Domains
-	return renewForDomains(ctx, account, keyType, certsStorage, bundle, meta)
+	return err
 }
 
-func renewForDomains(ctx *cli.Context, account *Account, keyType certcrypto.KeyType, certsStorage *CertificatesStorage, bundle bool, meta map[string]string) error {
+// renewForDomains renews the certificate of the domains.
+// additionalKeyType is empty for the main certificate.
+// force renews the certificate even if it's outside the renewal window.
+func renewForDomains(ctx *cli.Context, account *Account, keyType certcrypto.KeyType, additionalKeyType string, certsStorage *CertificatesStorage, bundle, force bool, meta map[string]string, summary *RenewalSummary, shutdown *shutdownWatcher) error {
 	domains := ctx.StringSlice(flgDomains)
 	domain := domains[0]
 
+	// The name of the files of the certificate.
+	name := certificateName(domain, additionalKeyType)
+
+	summary.Domain = name
+
 	// load the cert resource from files.
 	// We store the certificate, private key and metadata in different files
 	// as web servers would not be able to work with a combined file.
-	certificates, err := certsStorage.ReadCertificate(domain, certExt)
+	certificates, err := certsStorage.ReadCertificate(name, certExt)
 	if err != nil {
-		log.Fatalf("Error while loading the certificate for domain %s\n\t%v", domain, err)
+		return fmt.Errorf("error while loading the certificate for domain %s: %w", name, err)
 	}
 
 	cert := certificates[0]
 
+	summary.Domains = certcrypto.ExtractDomains(cert)
+	summary.NotAfter = cert.NotAfter
+
 	var (
 		ariRenewalTime *time.Time
 		replacesCertID string
@@ -178,60 +342,87 @@ func renewForDomains(ctx *cli.Context, account *Account, keyType certcrypto.KeyT
 
 	var client *lego.Client
 
+	forceDomains := ctx.Bool(flgForceCertDomains)
+
+	certDomains := certcrypto.ExtractDomains(cert)
+
+	// The certificate satisfies the requested parameters, and is outside the renewal window of the policy.
+	unchanged := !force && !needRenewal(cert, name, mustRenewalPolicy(ctx)) && (!forceDomains || slices.Equal(certDomains, domains))
+
 	if !ctx.Bool(flgARIDisable) {
-		client = setupClient(ctx, account, keyType)
+		if unchanged && ariCacheAllowsSkip(certsStorage, name, cert) {
+			summary.skipped(cert, mustRenewalPolicy(ctx))
+
+			return nil
+		}
+
+		client = setupRenewalClient(ctx, account, keyType, shutdown)
 
-		ariRenewalTime = getARIRenewalTime(ctx, cert, domain, client)
-		if ariRenewalTime != nil {
+		ariRenewalTime = getARIRenewalTime(ctx, certsStorage, cert, name, client)
+		if ariRenewalTime != nil && !force {
 			now := time.Now().UTC()
 
 			// Figure out if we need to sleep before renewing.
 			if ariRenewalTime.After(now) {
-				log.Infof("[%s] Sleeping %s until renewal time %s", domain, ariRenewalTime.Sub(now), ariRenewalTime)
+				log.Infof("[%s] Sleeping %s until renewal time %s", name, ariRenewalTime.Sub(now), ariRenewalTime)
 				time.Sleep(ariRenewalTime.Sub(now))
 			}
 		}
 
 		replacesCertID, err = certificate.MakeARICertID(cert)
 		if err != nil {
-			log.Fatalf("Error while construction the ARI CertID for domain %s\n\t%v", domain, err)
+			return fmt.Errorf("error while construction the ARI CertID for domain %s: %w", name, err)
 		}
 	}
 
-	forceDomains := ctx.Bool(flgForceCertDomains)
-
-	certDomains := certcrypto.ExtractDomains(cert)
+	if ariRenewalTime == nil && unchanged {
+		summary.skipped(cert, mustRenewalPolicy(ctx))
 
-	if ariRenewalTime == nil && !needRenewal(cert, domain, ctx.Int(flgRenewDays), ctx.Bool(flgRenewDynamic)) &&
-		(!forceDomains || slices.Equal(certDomains, domains)) {
 		return nil
 	}
 
+	err = certsStorage.checkStaging(name, ctx.String(flgServer))
+	if err != nil {
+		return err
+	}
+
 	if client == nil {
-		client = setupClient(ctx, account, keyType)
+		client = setupRenewalClient(ctx, account, keyType, shutdown)
 	}
 
 	// This is just meant to be informal for the user.
 	timeLeft := cert.NotAfter.Sub(time.Now().UTC())
-	log.Infof("[%s] acme: Trying renewal with %d hours remaining", domain, int(timeLeft.Hours()))
+	log.Infof("[%s] acme: Trying renewal with %d hours remaining", name, int(timeLeft.Hours()))
 
 	var privateKey crypto.PrivateKey
 
 	if ctx.Bool(flgReuseKey) {
-		keyBytes, errR := certsStorage.ReadFile(domain, keyExt)
+		keyBytes, errR := certsStorage.ReadFile(name, keyExt)
 		if errR != nil {
-			log.Fatalf("Error while loading the private key for domain %s\n\t%v", domain, errR)
+			return fmt.Errorf("error while loading the private key for domain %s: %w", name, errR)
 		}
 
 		privateKey, errR = certcrypto.ParsePEMPrivateKey(keyBytes)
+
+		certcrypto.Zeroize(keyBytes)
+
 		if errR != nil {
 			return errR
 		}
 	}
 
+	if privateKey == nil && additionalKeyType != "" {
+		privateKey, err = certcrypto.GeneratePrivateKey(parseKeyType(additionalKeyType))
+		if err != nil {
+			return fmt.Errorf("generate private key: %w", err)
+		}
+	}
+
 	// https://github.com/go-acme/lego/issues/1656
 	// https://github.com/certbot/certbot/blob/284023a1b7672be2bd4018dd7623b3b92197d4b0/certbot/certbot/_internal/renewal.py#L435-L440
-	if !isatty.IsTerminal(os.Stdout.Fd()) && !ctx.Bool(flgNoRandomSleep) {
+	// The random delay is only applied to the main certificate: the additional certificates are renewed in the same run.
+	// The forced renewals are immediate.
+	if additionalKeyType == "" && !force && !isatty.IsTerminal(os.Stdout.Fd()) && !ctx.Bool(flgNoRandomSleep) {
 		// https://github.com/certbot/certbot/blob/284023a1b7672be2bd4018dd7623b3b92197d4b0/certbot/certbot/_internal/renewal.py#L472
 		const jitter = 8 * time.Minute
 
@@ -256,7 +447,7 @@ func renewForDomains(ctx *cli.Context, account *Account, keyType certcrypto.KeyT
 		Bundle:                         bundle,
 		PreferredChain:                 ctx.String(flgPreferredChain),
 		Profile:                        ctx.String(flgProfile),
-		AlwaysDeactivateAuthorizations: ctx.Bool(flgAlwaysDeactivateAuthorizations),
+		AlwaysDeactivateAuthorizations: ctx.Bool(flgAlwaysDeactivateAuthorizations) && !keepAuthorizations(ctx, additionalKeyType),
 	}
 
 	if replacesCertID != "" {
@@ -264,40 +455,78 @@ func renewForDomains(ctx *cli.Context, account *Account, keyType certcrypto.KeyT
 	}
 
 	certRes, err := client.Certificate.Obtain(request)
+
+	var dropped []string
+
+	switch {
+	case err != nil && ctx.Bool(flgDropFailingSANs):
+		policy := sanDropPolicy{after: ctx.Int(flgDropFailingSANsAfter), window: ctx.Duration(flgDropFailingSANsWindow)}
+
+		certRes, dropped, err = obtainWithoutFailingSANs(client.Certificate.Obtain, request, domain, err, certsStorage, name, policy)
+
+	case err == nil && ctx.Bool(flgDropFailingSANs):
+		errR := resetSANFailures(certsStorage, name)
+		if errR != nil {
+			log.Warnf("[%s] Could not reset the failures of the domains: %v", name, errR)
+		}
+	}
+
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	certRes.Domain = domain
 
-	certsStorage.SaveResource(certRes)
+	err = launchIssuedHook(ctx.Context, ctx.String(flgIssuedHook), ctx.Duration(flgIssuedHookTimeout), meta, domain, certRes)
+	if err != nil {
+		return fmt.Errorf("issued hook (the certificate has not been saved): %w", err)
+	}
 
-	addPathToMetadata(meta, domain, certRes, certsStorage)
+	err = certsStorage.WriteResource(name, certRes)
+	if err != nil {
+		return err
+	}
 
-	return launchHook(ctx.String(flgRenewHook), ctx.Duration(flgRenewHookTimeout), meta)
+	summary.renewed(certRes, mustRenewalPolicy(ctx))
+	summary.DroppedDomains = dropped
+
+	addPathToMetadata(meta, name, certRes, certsStorage)
+	addKeyTypeToMetadata(meta, domain, additionalKeyType)
+	addValidityToMetadata(meta, certRes)
+
+	if len(dropped) > 0 {
+		meta[hookEnvCertDroppedDomains] = strings.Join(dropped, ",")
+	}
+
+	return launchHook(ctx.Context, ctx.String(flgRenewHook), ctx.Duration(flgRenewHookTimeout), meta)
 }
 
-func renewForCSR(ctx *cli.Context, account *Account, keyType certcrypto.KeyType, certsStorage *CertificatesStorage, bundle bool, meta map[string]string) error {
+func renewForCSR(ctx *cli.Context, account *Account, keyType certcrypto.KeyType, certsStorage *CertificatesStorage, bundle, force bool, meta map[string]string, summary *RenewalSummary, shutdown *shutdownWatcher) error {
 	csr, err := readCSRFile(ctx.String(flgCSR))
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	domain, err := certcrypto.GetCSRMainDomain(csr)
 	if err != nil {
-		log.Fatalf("Error: %v", err)
+		return err
 	}
 
+	summary.Domain = domain
+
 	// load the cert resource from files.
 	// We store the certificate, private key and metadata in different files
 	// as web servers would not be able to work with a combined file.
 	certificates, err := certsStorage.ReadCertificate(domain, certExt)
 	if err != nil {
-		log.Fatalf("Error while loading the certificate for domain %s\n\t%v", domain, err)
+		return fmt.Errorf("error while loading the certificate for domain %s: %w", domain, err)
 	}
 
 	cert := certificates[0]
 
+	summary.Domains = certcrypto.ExtractDomains(cert)
+	summary.NotAfter = cert.NotAfter
+
 	var (
 		ariRenewalTime *time.Time
 		replacesCertID string
@@ -305,11 +534,20 @@ func renewForCSR(ctx *cli.Context, account *Account, keyType certcrypto.KeyType,
 
 	var client *lego.Client
 
+	// The certificate is outside the renewal window of the policy.
+	unchanged := !force && !needRenewal(cert, domain, mustRenewalPolicy(ctx))
+
 	if !ctx.Bool(flgARIDisable) {
-		client = setupClient(ctx, account, keyType)
+		if unchanged && ariCacheAllowsSkip(certsStorage, domain, cert) {
+			summary.skipped(cert, mustRenewalPolicy(ctx))
 
-		ariRenewalTime = getARIRenewalTime(ctx, cert, domain, client)
-		if ariRenewalTime != nil {
+			return nil
+		}
+
+		client = setupRenewalClient(ctx, account, keyType, shutdown)
+
+		ariRenewalTime = getARIRenewalTime(ctx, certsStorage, cert, domain, client)
+		if ariRenewalTime != nil && !force {
 			now := time.Now().UTC()
 
 			// Figure out if we need to sleep before renewing.
@@ -321,16 +559,23 @@ func renewForCSR(ctx *cli.Context, account *Account, keyType certcrypto.KeyType,
 
 		replacesCertID, err = certificate.MakeARICertID(cert)
 		if err != nil {
-			log.Fatalf("Error while construction the ARI CertID for domain %s\n\t%v", domain, err)
+			return fmt.Errorf("error while construction the ARI CertID for domain %s: %w", domain, err)
 		}
 	}
 
-	if ariRenewalTime == nil && !needRenewal(cert, domain, ctx.Int(flgRenewDays), ctx.Bool(flgRenewDynamic)) {
+	if ariRenewalTime == nil && unchanged {
+		summary.skipped(cert, mustRenewalPolicy(ctx))
+
 		return nil
 	}
 
+	err = certsStorage.checkStaging(domain, ctx.String(flgServer))
+	if err != nil {
+		return err
+	}
+
 	if client == nil {
-		client = setupClient(ctx, account, keyType)
+		client = setupRenewalClient(ctx, account, keyType, shutdown)
 	}
 
 	// This is just meant to be informal for the user.
@@ -353,41 +598,183 @@ func renewForCSR(ctx *cli.Context, account *Account, keyType certcrypto.KeyType,
 
 	certRes, err := client.Certificate.ObtainForCSR(request)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+
+	err = launchIssuedHook(ctx.Context, ctx.String(flgIssuedHook), ctx.Duration(flgIssuedHookTimeout), meta, domain, certRes)
+	if err != nil {
+		return fmt.Errorf("issued hook (the certificate has not been saved): %w", err)
+	}
+
+	err = certsStorage.WriteResource(certRes.Domain, certRes)
+	if err != nil {
+		return err
 	}
 
-	certsStorage.SaveResource(certRes)
+	summary.renewed(certRes, mustRenewalPolicy(ctx))
 
 	addPathToMetadata(meta, domain, certRes, certsStorage)
+	addValidityToMetadata(meta, certRes)
+
+	return launchHook(ctx.Context, ctx.String(flgRenewHook), ctx.Duration(flgRenewHookTimeout), meta)
+}
+
+// renewalPolicy defines when a certificate is renewed.
+type renewalPolicy struct {
+	// days the number of days left on a certificate to renew it.
+	days int
+	// dynamic computes the renewal date from the lifetime of the certificate.
+	dynamic bool
+
+	// window the remaining validity of a certificate to renew it.
+	// It supersedes days and dynamic.
+	window time.Duration
+	// percent the remaining percentage of the lifetime of a certificate to renew it.
+	// It supersedes days and dynamic.
+	percent int
+
+	// spread the renewal date is moved earlier by an offset (between 0 and spread) based on a hash of the domain.
+	spread time.Duration
+}
+
+func newRenewalPolicy(ctx *cli.Context) (renewalPolicy, error) {
+	if ctx.IsSet(flgRenewWindow) && ctx.IsSet(flgRenewPercent) {
+		return renewalPolicy{}, fmt.Errorf("--%s and --%s are mutually exclusive", flgRenewWindow, flgRenewPercent)
+	}
 
-	return launchHook(ctx.String(flgRenewHook), ctx.Duration(flgRenewHookTimeout), meta)
+	if ctx.Duration(flgRenewSpread) < 0 {
+		return renewalPolicy{}, fmt.Errorf("--%s cannot be negative", flgRenewSpread)
+	}
+
+	policy := renewalPolicy{
+		days:    ctx.Int(flgRenewDays),
+		dynamic: ctx.Bool(flgRenewDynamic),
+		spread:  ctx.Duration(flgRenewSpread),
+	}
+
+	return policy.with(ctx.String(flgRenewWindow), ctx.Int(flgRenewPercent))
+}
+
+// mustRenewalPolicy returns the renewal policy defined by the flags (already validated by the Before of the command).
+func mustRenewalPolicy(ctx *cli.Context) renewalPolicy {
+	policy, err := newRenewalPolicy(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return policy
+}
+
+// with returns a copy of the policy using the given window or percent (if defined).
+func (p renewalPolicy) with(window string, percent int) (renewalPolicy, error) {
+	if window != "" {
+		d, err := parseRenewWindow(window)
+		if err != nil {
+			return renewalPolicy{}, err
+		}
+
+		p.window = d
+		p.percent = 0
+	}
+
+	if percent != 0 {
+		if percent < 1 || percent > 100 {
+			return renewalPolicy{}, fmt.Errorf("invalid renewal percentage %d: must be between 1 and 100", percent)
+		}
+
+		p.percent = percent
+		p.window = 0
+	}
+
+	return p, nil
 }
 
-func needRenewal(x509Cert *x509.Certificate, domain string, days int, dynamic bool) bool {
+// parseRenewWindow parses a duration, the days are supported (ex: 30d).
+func parseRenewWindow(value string) (time.Duration, error) {
+	var (
+		d   time.Duration
+		err error
+	)
+
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		var n int
+
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err = time.ParseDuration(value)
+	}
+
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid renewal window %q: must be a positive duration (ex: 30d, 72h)", value)
+	}
+
+	return d, nil
+}
+
+func needRenewal(x509Cert *x509.Certificate, domain string, policy renewalPolicy) bool {
 	if x509Cert.IsCA {
 		log.Fatalf("[%s] Certificate bundle starts with a CA certificate", domain)
 	}
 
-	if dynamic {
+	if !needRenewalValidity(x509Cert, domain, policy, time.Now()) {
+		return false
+	}
+
+	if policy.spread > 0 && policy.days >= 0 {
+		return needRenewalAt(x509Cert, domain, renewalDueDate(x509Cert, policy), time.Now())
+	}
+
+	switch {
+	case policy.percent > 0:
+		return needRenewalPercent(x509Cert, domain, policy.percent, time.Now())
+
+	case policy.window > 0:
+		return needRenewalAt(x509Cert, domain, x509Cert.NotAfter.Add(-policy.window), time.Now())
+
+	case policy.dynamic:
 		return needRenewalDynamic(x509Cert, domain, time.Now())
 	}
 
-	if days < 0 {
+	if policy.days < 0 {
 		return true
 	}
 
 	notAfter := int(time.Until(x509Cert.NotAfter).Hours() / 24.0)
-	if notAfter <= days {
+	if notAfter <= policy.days {
 		return true
 	}
 
 	log.Printf("[%s] The certificate expires in %d days, the number of days defined to perform the renewal is %d: no renewal.",
-		domain, notAfter, days)
+		domain, notAfter, policy.days)
+
+	return false
+}
+
+// needRenewalValidity checks the validity period of the certificate, with the clock skew tolerance.
+// A certificate that is not yet valid (the clock of the host is late by more than the tolerance) is not renewed,
+// unless the renewal is forced: a new certificate would have the same problem.
+func needRenewalValidity(x509Cert *x509.Certificate, domain string, policy renewalPolicy, now time.Time) bool {
+	validity := certcrypto.NewValidity(x509Cert, certcrypto.DefaultClockSkew)
+	if validity.IsValidAt(now) || !now.Before(validity.EffectiveNotBefore()) || policy.days < 0 {
+		return true
+	}
+
+	log.Warnf("[%s] The certificate is not valid before %s: the clock of the host is late by more than %s: no renewal.",
+		domain, x509Cert.NotBefore.Format(time.RFC3339), validity.Skew)
 
 	return false
 }
 
 func needRenewalDynamic(x509Cert *x509.Certificate, domain string, now time.Time) bool {
+	return needRenewalAt(x509Cert, domain, dynamicDueDate(x509Cert), now)
+}
+
+func needRenewalPercent(x509Cert *x509.Certificate, domain string, percent int, now time.Time) bool {
+	return needRenewalAt(x509Cert, domain, percentDueDate(x509Cert, percent), now)
+}
+
+func dynamicDueDate(x509Cert *x509.Certificate) time.Time {
 	lifetime := x509Cert.NotAfter.Sub(x509Cert.NotBefore)
 
 	var divisor int64 = 3
@@ -395,8 +782,66 @@ func needRenewalDynamic(x509Cert *x509.Certificate, domain string, now time.Time
 		divisor = 2
 	}
 
-	dueDate := x509Cert.NotAfter.Add(-1 * time.Duration(lifetime.Nanoseconds()/divisor))
+	return x509Cert.NotAfter.Add(-1 * time.Duration(lifetime.Nanoseconds()/divisor))
+}
+
+func percentDueDate(x509Cert *x509.Certificate, percent int) time.Time {
+	lifetime := x509Cert.NotAfter.Sub(x509Cert.NotBefore)
 
+	return x509Cert.NotAfter.Add(-1 * lifetime * time.Duration(percent) / 100)
+}
+
+// renewalDueDate returns the date from which the certificate is renewed by the policy,
+// or the zero time if the certificate is renewed at each run.
+func renewalDueDate(x509Cert *x509.Certificate, policy renewalPolicy) time.Time {
+	dueDate := policyDueDate(x509Cert, policy)
+	if dueDate.IsZero() || policy.spread <= 0 {
+		return dueDate
+	}
+
+	// The renewal is never moved before the issuance of the certificate.
+	offset := min(spreadOffset(x509Cert, policy.spread), dueDate.Sub(x509Cert.NotBefore))
+	if offset <= 0 {
+		return dueDate
+	}
+
+	return dueDate.Add(-offset)
+}
+
+// spreadOffset returns a deterministic offset between 0 and spread, based on a hash of the main domain of the certificate:
+// the renewals of a fleet of instances deployed at the same time are spread over the duration.
+func spreadOffset(x509Cert *x509.Certificate, spread time.Duration) time.Duration {
+	var domain string
+	if domains := certcrypto.ExtractDomains(x509Cert); len(domains) > 0 {
+		domain = strings.ToLower(domains[0])
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(domain))
+
+	return time.Duration(h.Sum64() % uint64(spread))
+}
+
+func policyDueDate(x509Cert *x509.Certificate, policy renewalPolicy) time.Time {
+	switch {
+	case policy.percent > 0:
+		return percentDueDate(x509Cert, policy.percent)
+
+	case policy.window > 0:
+		return x509Cert.NotAfter.Add(-policy.window)
+
+	case policy.dynamic:
+		return dynamicDueDate(x509Cert)
+
+	case policy.days < 0:
+		return time.Time{}
+	}
+
+	// The number of remaining days is truncated by needRenewal.
+	return x509Cert.NotAfter.Add(-time.Duration(policy.days+1) * 24 * time.Hour)
+}
+
+func needRenewalAt(x509Cert *x509.Certificate, domain string, dueDate, now time.Time) bool {
 	if dueDate.Before(now) {
 		return true
 	}
@@ -407,8 +852,21 @@ func needRenewalDynamic(x509Cert *x509.Certificate, domain string, now time.Time
 	return false
 }
 
+// ariCacheAllowsSkip reports whether the stored renewal information allows to skip the renewal without contacting the CA.
+func ariCacheAllowsSkip(certsStorage *CertificatesStorage, domain string, cert *x509.Certificate) bool {
+	cache := readARICache(certsStorage, domain, cert)
+	if cache == nil || !cache.allowsSkip(time.Now()) {
+		return false
+	}
+
+	log.Infof("[%s] acme: the stored renewal information (suggested window from %s, next check at %s) indicates that renewal is not needed",
+		domain, cache.SuggestedWindow.Start.Format(time.RFC3339), cache.NextPoll.Format(time.RFC3339))
+
+	return true
+}
+
 // getARIRenewalTime checks if the certificate needs to be renewed using the renewalInfo endpoint.
-func getARIRenewalTime(ctx *cli.Context, cert *x509.Certificate, domain string, client *lego.Client) *time.Time {
+func getARIRenewalTime(ctx *cli.Context, certsStorage *CertificatesStorage, cert *x509.Certificate, domain string, client *lego.Client) *time.Time {
 	if cert.IsCA {
 		log.Fatalf("[%s] Certificate bundle starts with a CA certificate", domain)
 	}
@@ -426,6 +884,8 @@ func getARIRenewalTime(ctx *cli.Context, cert *x509.Certificate, domain string,
 		return nil
 	}
 
+	writeARICache(certsStorage, domain, cert, renewalInfo)
+
 	now := time.Now().UTC()
 
 	renewalTime := renewalInfo.ShouldRenewAt(now, ctx.Duration(flgARIWaitToRenewDuration))
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/go-acme/lego/v4/log"
+	"github.com/urfave/cli/v2"
+)
+
+// Flag names.
+const (
+	flgRenewNowControlSocket = "control-socket"
+	flgRenewNowName          = "name"
+	flgRenewNowForce         = "force"
+)
+
+func createRenewNow() *cli.Command {
+	return &cli.Command{
+		Name:   "renew-now",
+		Usage:  "Ask a running daemon (see 'daemon --control-socket') to check the renewal of its certificates immediately",
+		Action: renewNow,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     flgRenewNowControlSocket,
+				Usage:    "The path of the unix socket of the control endpoint of the daemon.",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  flgRenewNowName,
+				Usage: "The name of the certificate (ex: example.com, example.com_rsa2048). All the certificates of the daemon by default.",
+			},
+			&cli.BoolFlag{
+				Name: flgRenewNowForce,
+				Usage: "Renew the certificates even if they are outside the renewal window" +
+					" (ex: revocation of the certificates, compromise of the private keys).",
+			},
+		},
+	}
+}
+
+func renewNow(ctx *cli.Context) error {
+	trigger := renewalTrigger{
+		Name:  ctx.String(flgRenewNowName),
+		Force: ctx.Bool(flgRenewNowForce),
+	}
+
+	err := requestRenewal(ctx.Context, ctx.String(flgRenewNowControlSocket), trigger)
+	if err != nil {
+		return fmt.Errorf("renew-now: %w", err)
+	}
+
+	log.Infof("The daemon accepted the %s.", &trigger)
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_merge(t *testing.T) {
@@ -104,11 +105,38 @@ func Test_needRenewal(t *testing.T) {
 			days:     -1,
 			expected: true,
 		},
+		{
+			desc: "30 days, NotAfter 10 days, NotBefore inside the clock skew",
+			x509Cert: &x509.Certificate{
+				NotBefore: time.Now().Add(2 * time.Minute),
+				NotAfter:  time.Now().Add(10 * 24 * time.Hour),
+			},
+			days:     30,
+			expected: true,
+		},
+		{
+			desc: "30 days, NotAfter 10 days, NotBefore beyond the clock skew: the clock of the host is late",
+			x509Cert: &x509.Certificate{
+				NotBefore: time.Now().Add(time.Hour),
+				NotAfter:  time.Now().Add(10 * 24 * time.Hour),
+			},
+			days:     30,
+			expected: false,
+		},
+		{
+			desc: "-1 days, NotBefore beyond the clock skew: always renew",
+			x509Cert: &x509.Certificate{
+				NotBefore: time.Now().Add(time.Hour),
+				NotAfter:  time.Now().Add(30 * 24 * time.Hour),
+			},
+			days:     -1,
+			expected: true,
+		},
 	}
 
 	for _, test := range testCases {
 		t.Run(test.desc, func(t *testing.T) {
-			actual := needRenewal(test.x509Cert, "foo.com", test.days, false)
+			actual := needRenewal(test.x509Cert, "foo.com", renewalPolicy{days: test.days})
 
 			assert.Equal(t, test.expected, actual)
 		})
@@ -167,3 +195,229 @@ func Test_needRenewalDynamic(t *testing.T) {
 		})
 	}
 }
+
+func Test_needRenewal_policy(t *testing.T) {
+	x509Cert := &x509.Certificate{
+		NotBefore: time.Now().Add(-60 * 24 * time.Hour),
+		NotAfter:  time.Now().Add(30 * 24 * time.Hour),
+	}
+
+	testCases := []struct {
+		desc     string
+		policy   renewalPolicy
+		expected assert.BoolAssertionFunc
+	}{
+		{
+			desc:     "window higher than the remaining validity",
+			policy:   renewalPolicy{days: 10, window: 31 * 24 * time.Hour},
+			expected: assert.True,
+		},
+		{
+			desc:     "window lower than the remaining validity",
+			policy:   renewalPolicy{days: 40, window: 29 * 24 * time.Hour},
+			expected: assert.False,
+		},
+		{
+			desc:     "percent higher than the remaining lifetime",
+			policy:   renewalPolicy{days: 10, percent: 34},
+			expected: assert.True,
+		},
+		{
+			desc:     "percent lower than the remaining lifetime",
+			policy:   renewalPolicy{days: 40, percent: 32},
+			expected: assert.False,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			test.expected(t, needRenewal(x509Cert, "example.com", test.policy))
+		})
+	}
+}
+
+func Test_needRenewalPercent(t *testing.T) {
+	x509Cert := &x509.Certificate{
+		NotBefore: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:  time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), // 90 days
+	}
+
+	// 25% of the lifetime: 2025-03-09 12:00.
+	assert.False(t, needRenewalPercent(x509Cert, "example.com", 25, time.Date(2025, 3, 9, 11, 0, 0, 0, time.UTC)))
+	assert.True(t, needRenewalPercent(x509Cert, "example.com", 25, time.Date(2025, 3, 9, 13, 0, 0, 0, time.UTC)))
+}
+
+func Test_renewalPolicy_with(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		window   string
+		percent  int
+		expected renewalPolicy
+	}{
+		{
+			desc:     "no override",
+			expected: renewalPolicy{days: 30, percent: 50},
+		},
+		{
+			desc:     "days",
+			window:   "10d",
+			expected: renewalPolicy{days: 30, window: 10 * 24 * time.Hour},
+		},
+		{
+			desc:     "duration",
+			window:   "72h",
+			expected: renewalPolicy{days: 30, window: 72 * time.Hour},
+		},
+		{
+			desc:     "percent",
+			percent:  33,
+			expected: renewalPolicy{days: 30, percent: 33},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			policy, err := renewalPolicy{days: 30, percent: 50}.with(test.window, test.percent)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, policy)
+		})
+	}
+}
+
+func Test_renewalPolicy_with_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		window   string
+		percent  int
+		expected string
+	}{
+		{
+			desc:     "invalid window",
+			window:   "a month",
+			expected: `invalid renewal window "a month": must be a positive duration (ex: 30d, 72h)`,
+		},
+		{
+			desc:     "negative window",
+			window:   "-1d",
+			expected: `invalid renewal window "-1d": must be a positive duration (ex: 30d, 72h)`,
+		},
+		{
+			desc:     "percent too high",
+			percent:  101,
+			expected: "invalid renewal percentage 101: must be between 1 and 100",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := renewalPolicy{}.with(test.window, test.percent)
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func Test_renewalDueDate(t *testing.T) {
+	notBefore := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := notBefore.Add(90 * 24 * time.Hour)
+
+	x509Cert := &x509.Certificate{NotBefore: notBefore, NotAfter: notAfter}
+
+	testCases := []struct {
+		desc     string
+		policy   renewalPolicy
+		expected time.Time
+	}{
+		{
+			desc:     "days",
+			policy:   renewalPolicy{days: 30},
+			expected: notAfter.Add(-31 * 24 * time.Hour),
+		},
+		{
+			desc:   "always",
+			policy: renewalPolicy{days: -1},
+		},
+		{
+			desc:     "dynamic",
+			policy:   renewalPolicy{days: 30, dynamic: true},
+			expected: notAfter.Add(-30 * 24 * time.Hour),
+		},
+		{
+			desc:     "window",
+			policy:   renewalPolicy{days: 30, window: 10 * 24 * time.Hour},
+			expected: notAfter.Add(-10 * 24 * time.Hour),
+		},
+		{
+			desc:     "percent",
+			policy:   renewalPolicy{days: 30, percent: 50},
+			expected: notAfter.Add(-45 * 24 * time.Hour),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			assert.Equal(t, test.expected, renewalDueDate(x509Cert, test.policy))
+		})
+	}
+
+	// The due date is consistent with needRenewal.
+	policy := renewalPolicy{days: 30}
+
+	dueDate := renewalDueDate(x509Cert, policy)
+
+	x509Cert.NotAfter = time.Now().Add(notAfter.Sub(dueDate) - time.Minute)
+	assert.True(t, needRenewal(x509Cert, "example.com", policy))
+
+	x509Cert.NotAfter = time.Now().Add(notAfter.Sub(dueDate) + time.Minute)
+	assert.False(t, needRenewal(x509Cert, "example.com", policy))
+}
+
+func Test_renewalDueDate_spread(t *testing.T) {
+	notBefore := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := notBefore.Add(90 * 24 * time.Hour)
+
+	policy := renewalPolicy{days: 30, spread: 48 * time.Hour}
+
+	base := renewalDueDate(&x509.Certificate{NotBefore: notBefore, NotAfter: notAfter}, renewalPolicy{days: 30})
+
+	dueDates := map[time.Time]struct{}{}
+
+	for _, domain := range []string{"a.example.com", "b.example.com", "c.example.com", "d.example.com"} {
+		x509Cert := &x509.Certificate{NotBefore: notBefore, NotAfter: notAfter, DNSNames: []string{domain}}
+
+		dueDate := renewalDueDate(x509Cert, policy)
+
+		// Deterministic.
+		assert.Equal(t, dueDate, renewalDueDate(x509Cert, policy))
+
+		assert.False(t, dueDate.After(base))
+		assert.True(t, dueDate.After(base.Add(-policy.spread)))
+
+		dueDates[dueDate] = struct{}{}
+	}
+
+	assert.Greater(t, len(dueDates), 1)
+
+	// Never before the issuance of the certificate.
+	x509Cert := &x509.Certificate{NotBefore: notBefore, NotAfter: notAfter, DNSNames: []string{"a.example.com"}}
+	assert.False(t, renewalDueDate(x509Cert, renewalPolicy{days: 30, spread: 1000 * 24 * time.Hour}).Before(notBefore))
+
+	// Always renewed.
+	assert.True(t, renewalDueDate(x509Cert, renewalPolicy{days: -1, spread: time.Hour}).IsZero())
+}
+
+func Test_needRenewal_spread(t *testing.T) {
+	x509Cert := &x509.Certificate{
+		NotBefore: time.Now().Add(-60 * 24 * time.Hour),
+		DNSNames:  []string{"example.com"},
+	}
+
+	offset := spreadOffset(x509Cert, 72*time.Hour)
+	require.Greater(t, offset, 2*time.Minute)
+
+	// The due date without spread is in offset/2.
+	x509Cert.NotAfter = time.Now().Add(31*24*time.Hour + offset/2)
+
+	assert.False(t, needRenewal(x509Cert, "example.com", renewalPolicy{days: 30}))
+	assert.True(t, needRenewal(x509Cert, "example.com", renewalPolicy{days: 30, spread: 72 * time.Hour}))
+}
@@ -3,10 +3,12 @@ package cmd
 import (
 	"bufio"
 	"fmt"
+	"maps"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/go-acme/lego/v4/certificate"
 	"github.com/go-acme/lego/v4/lego"
 	"github.com/go-acme/lego/v4/log"
@@ -45,6 +47,12 @@ func createRun() *cli.Command {
 				log.Fatal("Please specify --domains/-d (or --csr/-c if you already have a CSR)")
 			}
 
+			if ctx.IsSet(flgAdditionalKeyType) && (hasCsr || ctx.IsSet(flgPrivateKey)) {
+				log.Fatalf("--%s only works with a private key generated by lego, --%s/-c and --%s don't support this option.", flgAdditionalKeyType, flgCSR, flgPrivateKey)
+			}
+
+			getAdditionalKeyTypes(ctx)
+
 			return nil
 		},
 		Action: run,
@@ -85,6 +93,11 @@ func createRun() *cli.Command {
 				Name:  flgAlwaysDeactivateAuthorizations,
 				Usage: "Force the authorizations to be relinquished even if the certificate request was successful.",
 			},
+			&cli.StringSliceFlag{
+				Name: flgAdditionalKeyType,
+				Usage: "Key type of an additional certificate obtained for the same domains (ex: rsa2048 with --key-type ec256). The authorizations are reused if the CA allows it." +
+					" The files are named with the key type (ex: example.com_rsa2048.crt). Supported: rsa2048, rsa3072, rsa4096, rsa8192, ec256, ec384.",
+			},
 			&cli.StringFlag{
 				Name:  flgRunHook,
 				Usage: "Define a hook. The hook is executed when the certificates are effectively created.",
@@ -94,6 +107,16 @@ func createRun() *cli.Command {
 				Usage: "Define the timeout for the hook execution.",
 				Value: 2 * time.Minute,
 			},
+			&cli.StringFlag{
+				Name: flgIssuedHook,
+				Usage: "Define a hook executed with the new certificate before the files are written (ex: registration in an inventory or a CT monitor)." +
+					" The certificate is written on the standard input, the SCTs are in LEGO_CERT_SCTS. If the hook fails, the files are not written.",
+			},
+			&cli.DurationFlag{
+				Name:  flgIssuedHookTimeout,
+				Usage: "Define the timeout for the issued hook execution.",
+				Value: 2 * time.Minute,
+			},
 		},
 	}
 }
@@ -110,21 +133,33 @@ backups of this folder is ideal.
 `
 
 func run(ctx *cli.Context) error {
+	shutdown := newShutdownWatcher(ctx.Duration(flgShutdownGracePeriod))
+
+	return shutdown.run(ctx.Context, func() error {
+		return obtainCertificates(ctx, shutdown)
+	})
+}
+
+// obtainCertificates registers the account if needed,
+// then obtains the main certificate and the additional certificates with the same domains.
+func obtainCertificates(ctx *cli.Context, shutdown *shutdownWatcher) error {
 	accountsStorage := NewAccountsStorage(ctx)
 
 	account, keyType := setupAccount(ctx, accountsStorage)
 
 	client := setupClient(ctx, account, keyType)
 
+	shutdown.watch(client)
+
 	if account.Registration == nil {
 		reg, err := register(ctx, client)
 		if err != nil {
-			log.Fatalf("Could not complete registration\n\t%v", err)
+			return fmt.Errorf("could not complete registration: %w", err)
 		}
 
 		account.Registration = reg
 		if err = accountsStorage.Save(account); err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		fmt.Printf(rootPathWarningMessage, accountsStorage.GetRootPath())
@@ -133,22 +168,58 @@ func run(ctx *cli.Context) error {
 	certsStorage := NewCertificatesStorage(ctx)
 	certsStorage.CreateRootFolder()
 
-	cert, err := obtainCertificate(ctx, client)
+	meta := map[string]string{
+		hookEnvAccountEmail: account.Email,
+	}
+
+	// The main certificate, then the additional certificates with the same domains.
+	for _, additionalKeyType := range append([]string{""}, getAdditionalKeyTypes(ctx)...) {
+		err := obtainAndSave(ctx, client, certsStorage, maps.Clone(meta), additionalKeyType)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// obtainAndSave obtains a certificate, writes the files, and launches the run hook.
+// additionalKeyType is empty for the main certificate.
+func obtainAndSave(ctx *cli.Context, client *lego.Client, certsStorage *CertificatesStorage, meta map[string]string, additionalKeyType string) error {
+	domain, err := requestMainDomain(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = certsStorage.checkStaging(certificateName(domain, additionalKeyType), ctx.String(flgServer))
+	if err != nil {
+		return err
+	}
+
+	cert, err := obtainCertificate(ctx, client, additionalKeyType)
 	if err != nil {
 		// Make sure to return a non-zero exit code if ObtainSANCertificate returned at least one error.
-		// Due to us not returning partial certificate we can just exit here instead of at the end.
-		log.Fatalf("Could not obtain certificates:\n\t%v", err)
+		// Due to us not returning partial certificate we can stop here instead of at the end.
+		return fmt.Errorf("could not obtain certificates: %w", err)
+	}
+
+	err = launchIssuedHook(ctx.Context, ctx.String(flgIssuedHook), ctx.Duration(flgIssuedHookTimeout), meta, cert.Domain, cert)
+	if err != nil {
+		return fmt.Errorf("[%s] the issued hook failed, the certificate has not been saved: %w", cert.Domain, err)
 	}
 
-	certsStorage.SaveResource(cert)
+	name := certificateName(cert.Domain, additionalKeyType)
 
-	meta := map[string]string{
-		hookEnvAccountEmail: account.Email,
+	err = certsStorage.WriteResource(name, cert)
+	if err != nil {
+		return err
 	}
 
-	addPathToMetadata(meta, cert.Domain, cert, certsStorage)
+	addPathToMetadata(meta, name, cert, certsStorage)
+	addKeyTypeToMetadata(meta, cert.Domain, additionalKeyType)
+	addValidityToMetadata(meta, cert)
 
-	return launchHook(ctx.String(flgRunHook), ctx.Duration(flgRunHookTimeout), meta)
+	return launchHook(ctx.Context, ctx.String(flgRunHook), ctx.Duration(flgRunHookTimeout), meta)
 }
 
 func handleTOS(ctx *cli.Context, client *lego.Client) bool {
@@ -205,7 +276,21 @@ func register(ctx *cli.Context, client *lego.Client) (*registration.Resource, er
 	return client.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true})
 }
 
-func obtainCertificate(ctx *cli.Context, client *lego.Client) (*certificate.Resource, error) {
+// requestMainDomain returns the main domain of the requested certificate: the first domain, or the main domain of the CSR.
+func requestMainDomain(ctx *cli.Context) (string, error) {
+	if domains := ctx.StringSlice(flgDomains); len(domains) > 0 {
+		return domains[0], nil
+	}
+
+	csr, err := readCSRFile(ctx.String(flgCSR))
+	if err != nil {
+		return "", err
+	}
+
+	return certcrypto.GetCSRMainDomain(csr)
+}
+
+func obtainCertificate(ctx *cli.Context, client *lego.Client, additionalKeyType string) (*certificate.Resource, error) {
 	bundle := !ctx.Bool(flgNoBundle)
 
 	domains := ctx.StringSlice(flgDomains)
@@ -219,7 +304,7 @@ func obtainCertificate(ctx *cli.Context, client *lego.Client) (*certificate.Reso
 			Bundle:                         bundle,
 			PreferredChain:                 ctx.String(flgPreferredChain),
 			Profile:                        ctx.String(flgProfile),
-			AlwaysDeactivateAuthorizations: ctx.Bool(flgAlwaysDeactivateAuthorizations),
+			AlwaysDeactivateAuthorizations: ctx.Bool(flgAlwaysDeactivateAuthorizations) && !keepAuthorizations(ctx, additionalKeyType),
 		}
 
 		if ctx.IsSet(flgPrivateKey) {
@@ -231,6 +316,15 @@ func obtainCertificate(ctx *cli.Context, client *lego.Client) (*certificate.Reso
 			}
 		}
 
+		if additionalKeyType != "" {
+			var err error
+
+			request.PrivateKey, err = certcrypto.GeneratePrivateKey(parseKeyType(additionalKeyType))
+			if err != nil {
+				return nil, fmt.Errorf("generate private key: %w", err)
+			}
+		}
+
 		return client.Certificate.Obtain(request)
 	}
 
@@ -0,0 +1,704 @@
+package cmd
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/errcode"
+	"github.com/go-acme/lego/v4/storage"
+	"github.com/urfave/cli/v2"
+)
+
+// Flag names.
+const (
+	flgServerListen       = "listen"
+	flgServerToken        = "token"
+	flgServerHealthSocket = "health-socket"
+	flgServerTLSCert      = "tls-cert"
+	flgServerTLSKey       = "tls-key"
+)
+
+// Environment variables names.
+const envServerToken = "LEGO_SERVER_TOKEN"
+
+// maxServerRequestSize the maximum size of the body of a request.
+const maxServerRequestSize = 1 << 20
+
+func createServer() *cli.Command {
+	return &cli.Command{
+		Name:  "server",
+		Usage: "Start an HTTP server exposing the issuance, the renewal, and the revocation of certificates through a REST API",
+		Before: func(ctx *cli.Context) error {
+			if ctx.String(flgServerToken) == "" {
+				log.Fatalf("The token is required: use --%s or %s.", flgServerToken, envServerToken)
+			}
+
+			if ctx.IsSet(flgServerTLSCert) != ctx.IsSet(flgServerTLSKey) {
+				log.Fatalf("--%s and --%s must be used together.", flgServerTLSCert, flgServerTLSKey)
+			}
+
+			// The bearer token must not be sent in plaintext over the network.
+			if !ctx.IsSet(flgServerTLSCert) && !isLocalAddress(ctx.String(flgServerListen)) {
+				log.Fatalf("The server must use TLS (--%s and --%s) to listen on %q: only a loopback address or a unix socket can be used without TLS.",
+					flgServerTLSCert, flgServerTLSKey, ctx.String(flgServerListen))
+			}
+
+			return nil
+		},
+		Action: server,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  flgServerListen,
+				Usage: "The address used by the server. Supported: host:port or unix:/path/to/socket. A non-loopback address requires TLS.",
+				Value: "127.0.0.1:8080",
+			},
+			&cli.StringFlag{
+				Name:  flgServerTLSCert,
+				Usage: "The path of the certificate (PEM) used by the server to serve the API over TLS.",
+			},
+			&cli.StringFlag{
+				Name:  flgServerTLSKey,
+				Usage: "The path of the private key (PEM) of the certificate used by the server.",
+			},
+			&cli.StringFlag{
+				Name:  flgServerHealthSocket,
+				Usage: "The path of the unix socket serving the health endpoint (GET /health).",
+			},
+			&cli.StringFlag{
+				Name:    flgServerToken,
+				EnvVars: []string{envServerToken},
+				Usage:   "The bearer token required to call the API.",
+			},
+			&cli.BoolFlag{
+				Name:  flgNoBundle,
+				Usage: "Do not create a certificate bundle by adding the issuers certificate to the new certificate.",
+			},
+		},
+	}
+}
+
+func server(ctx *cli.Context) error {
+	account, keyType := setupAccount(ctx, NewAccountsStorage(ctx))
+
+	if account.Registration == nil {
+		log.Fatalf("Account %s is not registered. Use 'run' to register a new account.\n", account.Email)
+	}
+
+	client := setupClient(ctx, account, keyType)
+
+	certsStorage := NewCertificatesStorage(ctx)
+	certsStorage.CreateRootFolder()
+
+	handler := newServerHandler(client.Certificate, certsStorage, ctx.String(flgServerToken), !ctx.Bool(flgNoBundle))
+	handler.serverURL = ctx.String(flgServer)
+
+	// The server runs until it's stopped: the changes are uploaded after each operation, not only by After.
+	if mirror, ok := ctx.App.Metadata[storageMetadataKey].(*storageMirror); ok {
+		handler.push = mirror.push
+	}
+
+	listener, err := listen(ctx.String(flgServerListen))
+	if err != nil {
+		return fmt.Errorf("server: %w", err)
+	}
+
+	if ctx.IsSet(flgServerHealthSocket) {
+		healthListener, errL := listen(unixSocketPrefix + ctx.String(flgServerHealthSocket))
+		if errL != nil {
+			return fmt.Errorf("health: %w", errL)
+		}
+
+		healthSrv := &http.Server{Handler: newHealthHandler(handler.healthy), ReadHeaderTimeout: 10 * time.Second}
+
+		go func() {
+			errS := healthSrv.Serve(healthListener)
+			if errS != nil && !errors.Is(errS, http.ErrServerClosed) {
+				log.Warnf("Health server: %v", errS)
+			}
+		}()
+	}
+
+	srv := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go handler.runHeartbeat(ctx.Context)
+
+	go runWatchdog(ctx.Context, handler.healthy)
+
+	log.Infof("Server listening on %s", listener.Addr())
+
+	_, err = sdNotify(sdNotifyReady)
+	if err != nil {
+		log.Warnf("Unable to notify systemd: %v", err)
+	}
+
+	shutdownErr := make(chan error, 1)
+
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+		<-signals
+
+		_, _ = sdNotify(sdNotifyStopping)
+
+		// Stops accepting new requests and waits for the in-flight requests.
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), ctx.Duration(flgShutdownGracePeriod))
+		defer cancel()
+
+		shutdownErr <- srv.Shutdown(shutdownCtx)
+	}()
+
+	if ctx.IsSet(flgServerTLSCert) {
+		err = srv.ServeTLS(listener, ctx.String(flgServerTLSCert), ctx.String(flgServerTLSKey))
+	} else {
+		err = srv.Serve(listener)
+	}
+
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	err = <-shutdownErr
+	if err != nil {
+		client.Certificate.Abandon()
+
+		return fmt.Errorf("server shutdown: %w", err)
+	}
+
+	return nil
+}
+
+// certifier the certificate operations used by the server.
+type certifier interface {
+	Obtain(request certificate.ObtainRequest) (*certificate.Resource, error)
+	RevokeWithReason(cert []byte, reason *uint) error
+}
+
+// serverHandler the REST API of the server.
+//
+//	POST /v1/certificates                   obtains a certificate.
+//	GET  /v1/certificates/{domain}          gets a certificate.
+//	POST /v1/certificates/{domain}/renew    renews a certificate.
+//	POST /v1/certificates/{domain}/revoke   revokes and archives a certificate.
+type serverHandler struct {
+	certifier    certifier
+	certsStorage *CertificatesStorage
+	token        string
+	bundle       bool
+
+	// serverURL the directory URL of the CA, used to prevent the replacement of a production certificate by a staging certificate.
+	serverURL string
+
+	// mu serializes the operations on the storage.
+	mu sync.Mutex
+
+	// locks serializes the operations (obtain, renew, revoke) on the same certificate.
+	locks *nameLocks
+
+	// beat ticks while the operations on the storage are not hung (see runHeartbeat).
+	beat *heartbeat
+
+	// push uploads the changes of the storage to the remote storage (`--storage`), nil without remote storage.
+	push func(ctx context.Context) error
+
+	mux *http.ServeMux
+}
+
+func newServerHandler(certifier certifier, certsStorage *CertificatesStorage, token string, bundle bool) *serverHandler {
+	h := &serverHandler{
+		certifier:    certifier,
+		certsStorage: certsStorage,
+		token:        token,
+		bundle:       bundle,
+		beat:         newHeartbeat(healthTimeout),
+		locks:        newNameLocks(),
+		mux:          http.NewServeMux(),
+	}
+
+	h.mux.HandleFunc("POST /v1/certificates", h.obtain)
+	h.mux.HandleFunc("GET /v1/certificates/{domain}", h.get)
+	h.mux.HandleFunc("POST /v1/certificates/{domain}/renew", h.renew)
+	h.mux.HandleFunc("POST /v1/certificates/{domain}/revoke", h.revoke)
+
+	return h
+}
+
+func (h *serverHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		writeServerError(rw, http.StatusUnauthorized, errors.New("invalid or missing bearer token"))
+		return
+	}
+
+	h.mux.ServeHTTP(rw, req)
+}
+
+// ObtainCertificateRequest the body of the obtain request.
+type ObtainCertificateRequest struct {
+	Domains        []string `json:"domains"`
+	MustStaple     bool     `json:"mustStaple,omitempty"`
+	PreferredChain string   `json:"preferredChain,omitempty"`
+	Profile        string   `json:"profile,omitempty"`
+}
+
+// RevokeCertificateRequest the body of the revoke request.
+type RevokeCertificateRequest struct {
+	Reason *uint `json:"reason,omitempty"`
+	Keep   bool  `json:"keep,omitempty"`
+}
+
+// CertificateResponse the representation of a certificate.
+// The private key is never exposed by the API.
+type CertificateResponse struct {
+	Domain            string    `json:"domain"`
+	Domains           []string  `json:"domains,omitempty"`
+	CertURL           string    `json:"certUrl,omitempty"`
+	CertStableURL     string    `json:"certStableUrl,omitempty"`
+	NotBefore         time.Time `json:"notBefore,omitzero"`
+	NotAfter          time.Time `json:"notAfter,omitzero"`
+	Certificate       string    `json:"certificate"`
+	IssuerCertificate string    `json:"issuerCertificate,omitempty"`
+}
+
+func (h *serverHandler) obtain(rw http.ResponseWriter, req *http.Request) {
+	var request ObtainCertificateRequest
+
+	err := decodeServerRequest(rw, req, &request)
+	if err != nil {
+		writeServerError(rw, requestErrorStatus(err), err)
+		return
+	}
+
+	if len(request.Domains) == 0 {
+		writeServerError(rw, http.StatusBadRequest, errors.New("the domains are required"))
+		return
+	}
+
+	// The files of the certificate are named with the first domain.
+	err = checkCertificateName(request.Domains[0])
+	if err != nil {
+		writeServerError(rw, http.StatusBadRequest, err)
+		return
+	}
+
+	defer h.locks.lock(request.Domains[0])()
+
+	h.mu.Lock()
+	err = h.certsStorage.checkStaging(request.Domains[0], h.serverURL)
+	h.mu.Unlock()
+
+	if err != nil {
+		writeServerError(rw, http.StatusConflict, err)
+		return
+	}
+
+	certRes, err := h.certifier.Obtain(certificate.ObtainRequest{
+		Domains:        request.Domains,
+		MustStaple:     request.MustStaple,
+		Bundle:         h.bundle,
+		PreferredChain: request.PreferredChain,
+		Profile:        request.Profile,
+	})
+	if err != nil {
+		writeServerError(rw, http.StatusBadGateway, err)
+		return
+	}
+
+	h.mu.Lock()
+	err = h.certsStorage.WriteResource(certRes.Domain, certRes)
+	if err == nil {
+		h.pushStorage(context.WithoutCancel(req.Context()))
+	}
+	h.mu.Unlock()
+
+	if err != nil {
+		writeServerError(rw, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeServerResponse(rw, http.StatusCreated, newCertificateResponse(certRes))
+}
+
+func (h *serverHandler) get(rw http.ResponseWriter, req *http.Request) {
+	domain, err := pathDomain(req)
+	if err != nil {
+		writeServerError(rw, http.StatusBadRequest, err)
+		return
+	}
+
+	h.mu.Lock()
+	certRes, err := h.readResource(domain)
+	h.mu.Unlock()
+
+	if err != nil {
+		writeServerError(rw, storageErrorStatus(err), err)
+		return
+	}
+
+	writeServerResponse(rw, http.StatusOK, newCertificateResponse(certRes))
+}
+
+func (h *serverHandler) renew(rw http.ResponseWriter, req *http.Request) {
+	domain, err := pathDomain(req)
+	if err != nil {
+		writeServerError(rw, http.StatusBadRequest, err)
+		return
+	}
+
+	defer h.locks.lock(domain)()
+
+	h.mu.Lock()
+	certRes, err := h.readResource(domain)
+	h.mu.Unlock()
+
+	if err != nil {
+		writeServerError(rw, storageErrorStatus(err), err)
+		return
+	}
+
+	certificates, err := certcrypto.ParsePEMBundle(certRes.Certificate)
+	if err != nil {
+		writeServerError(rw, http.StatusInternalServerError, err)
+		return
+	}
+
+	h.mu.Lock()
+	err = h.certsStorage.checkStaging(domain, h.serverURL)
+	h.mu.Unlock()
+
+	if err != nil {
+		writeServerError(rw, http.StatusConflict, err)
+		return
+	}
+
+	request := certificate.ObtainRequest{
+		Domains: certcrypto.ExtractDomains(certificates[0]),
+		Bundle:  h.bundle,
+	}
+
+	request.ReplacesCertID, err = certificate.MakeARICertID(certificates[0])
+	if err != nil {
+		log.Warnf("[%s] Unable to compute the ARI CertID: %v", domain, err)
+	}
+
+	newCertRes, err := h.certifier.Obtain(request)
+	if err != nil {
+		writeServerError(rw, http.StatusBadGateway, err)
+		return
+	}
+
+	h.mu.Lock()
+	err = h.certsStorage.WriteResource(newCertRes.Domain, newCertRes)
+	if err == nil {
+		h.pushStorage(context.WithoutCancel(req.Context()))
+	}
+	h.mu.Unlock()
+
+	if err != nil {
+		writeServerError(rw, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeServerResponse(rw, http.StatusOK, newCertificateResponse(newCertRes))
+}
+
+func (h *serverHandler) revoke(rw http.ResponseWriter, req *http.Request) {
+	domain, err := pathDomain(req)
+	if err != nil {
+		writeServerError(rw, http.StatusBadRequest, err)
+		return
+	}
+
+	defer h.locks.lock(domain)()
+
+	request := RevokeCertificateRequest{}
+
+	if req.ContentLength != 0 {
+		err = decodeServerRequest(rw, req, &request)
+		if err != nil {
+			writeServerError(rw, requestErrorStatus(err), err)
+			return
+		}
+	}
+
+	reason := acme.CRLReasonUnspecified
+	if request.Reason != nil {
+		reason = *request.Reason
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	certBytes, err := h.certsStorage.ReadFile(domain, certExt)
+	if errors.Is(err, fs.ErrNotExist) {
+		writeServerError(rw, http.StatusNotFound, fmt.Errorf("certificate for domain %s not found", domain))
+		return
+	}
+
+	if err != nil {
+		writeServerError(rw, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = h.certifier.RevokeWithReason(certBytes, &reason)
+	if err != nil {
+		writeServerError(rw, http.StatusBadGateway, err)
+		return
+	}
+
+	if !request.Keep {
+		err = h.certsStorage.MoveToArchive(domain)
+		if err != nil {
+			writeServerError(rw, http.StatusInternalServerError, err)
+			return
+		}
+
+		h.pushStorage(context.WithoutCancel(req.Context()))
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// runHeartbeat ticks the heartbeat between the operations on the storage:
+// the heartbeat doesn't tick while an operation holds the storage.
+func (h *serverHandler) runHeartbeat(ctx context.Context) {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.mu.Lock()
+			h.beat.tick()
+			h.mu.Unlock()
+		}
+	}
+}
+
+// healthy checks that the storage is not held by a hung operation (no tick of the heartbeat since healthTimeout).
+func (h *serverHandler) healthy() bool {
+	return h.beat.healthy()
+}
+
+// pushStorage uploads the changes of the storage to the remote storage.
+// A failure is only logged: the changes are uploaded by the next push.
+func (h *serverHandler) pushStorage(ctx context.Context) {
+	if h.push == nil {
+		return
+	}
+
+	err := h.push(ctx)
+	if err != nil {
+		log.Warnf("Could not save the data to the storage: %v", err)
+	}
+}
+
+// readResource reads the resource and the certificates of a domain from the storage.
+func (h *serverHandler) readResource(domain string) (*certificate.Resource, error) {
+	exists, err := h.certsStorage.Exists(domain, resourceExt)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("certificate for domain %s not found: %w", domain, fs.ErrNotExist)
+	}
+
+	certRes, err := h.certsStorage.LoadResource(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	certRes.Certificate, err = h.certsStorage.ReadFile(domain, certExt)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("certificate for domain %s not found: %w", domain, err)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	certRes.IssuerCertificate, err = h.certsStorage.ReadFile(domain, issuerExt)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	return certRes, nil
+}
+
+// isLocalAddress reports whether an address of the server is only reachable from the host:
+// a unix socket or a loopback address.
+func isLocalAddress(address string) bool {
+	if strings.HasPrefix(address, unixSocketPrefix) {
+		return true
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
+
+// nameLocks a registry of mutexes by certificate name.
+// The mutex of a name is removed when it's not used anymore.
+type nameLocks struct {
+	mu    sync.Mutex
+	locks map[string]*nameLock
+}
+
+type nameLock struct {
+	sync.Mutex
+
+	refs int
+}
+
+func newNameLocks() *nameLocks {
+	return &nameLocks{locks: make(map[string]*nameLock)}
+}
+
+// lock locks the mutex of a name, and returns the function unlocking it.
+func (l *nameLocks) lock(name string) func() {
+	l.mu.Lock()
+
+	m, ok := l.locks[name]
+	if !ok {
+		m = &nameLock{}
+		l.locks[name] = m
+	}
+
+	m.refs++
+
+	l.mu.Unlock()
+
+	m.Lock()
+
+	return func() {
+		m.Unlock()
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		m.refs--
+		if m.refs == 0 {
+			delete(l.locks, name)
+		}
+	}
+}
+
+// pathDomain returns the domain of the request path.
+func pathDomain(req *http.Request) (string, error) {
+	domain := req.PathValue("domain")
+
+	err := checkCertificateName(domain)
+	if err != nil {
+		return "", err
+	}
+
+	return domain, nil
+}
+
+// checkCertificateName checks that a domain can be used as the name of the files of a certificate.
+// The path separators (encoded in the request path) are rejected.
+func checkCertificateName(domain string) error {
+	if strings.ContainsAny(domain, `/\`) {
+		return fmt.Errorf("invalid domain %q", domain)
+	}
+
+	_, err := storage.SanitizedDomain(domain)
+	if err != nil {
+		return fmt.Errorf("invalid domain %q: %w", domain, err)
+	}
+
+	return nil
+}
+
+// decodeServerRequest decodes the JSON body of a request, limited to maxServerRequestSize.
+func decodeServerRequest(rw http.ResponseWriter, req *http.Request, v any) error {
+	err := json.NewDecoder(http.MaxBytesReader(rw, req.Body, maxServerRequestSize)).Decode(v)
+	if err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+
+	return nil
+}
+
+// requestErrorStatus returns the status of an error of decodeServerRequest.
+func requestErrorStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+
+	return http.StatusBadRequest
+}
+
+// storageErrorStatus returns the status of an error of the storage.
+func storageErrorStatus(err error) int {
+	if errors.Is(err, fs.ErrNotExist) {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
+
+func newCertificateResponse(certRes *certificate.Resource) CertificateResponse {
+	response := CertificateResponse{
+		Domain:            certRes.Domain,
+		CertURL:           certRes.CertURL,
+		CertStableURL:     certRes.CertStableURL,
+		Certificate:       string(certRes.Certificate),
+		IssuerCertificate: string(certRes.IssuerCertificate),
+	}
+
+	certificates, err := certcrypto.ParsePEMBundle(certRes.Certificate)
+	if err == nil && len(certificates) > 0 {
+		response.Domains = certcrypto.ExtractDomains(certificates[0])
+		response.NotBefore = certificates[0].NotBefore
+		response.NotAfter = certificates[0].NotAfter
+	}
+
+	return response
+}
+
+func writeServerResponse(rw http.ResponseWriter, status int, data any) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+
+	err := json.NewEncoder(rw).Encode(data)
+	if err != nil {
+		log.Warnf("Unable to write the response: %v", err)
+	}
+}
+
+func writeServerError(rw http.ResponseWriter, status int, err error) {
+	writeServerResponse(rw, status, map[string]string{"error": err.Error(), "code": errcode.Of(err).String()})
+}
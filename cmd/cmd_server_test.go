@@ -0,0 +1,317 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCertifier struct {
+	t *testing.T
+
+	requests []certificate.ObtainRequest
+	revoked  [][]byte
+}
+
+func (f *fakeCertifier) Obtain(request certificate.ObtainRequest) (*certificate.Resource, error) {
+	f.requests = append(f.requests, request)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(f.t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(int64(len(f.requests))),
+		Subject:      pkix.Name{CommonName: request.Domains[0]},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().AddDate(0, 3, 0),
+		DNSNames:     request.Domains,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	require.NoError(f.t, err)
+
+	return &certificate.Resource{
+		Domain:      request.Domains[0],
+		CertURL:     "https://example.com/cert/1",
+		PrivateKey:  certcrypto.PEMEncode(privateKey),
+		Certificate: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}, nil
+}
+
+func (f *fakeCertifier) RevokeWithReason(cert []byte, _ *uint) error {
+	f.revoked = append(f.revoked, cert)
+
+	return nil
+}
+
+func setupServerHandler(t *testing.T) (*serverHandler, *fakeCertifier) {
+	t.Helper()
+
+	certifier := &fakeCertifier{t: t}
+
+	storage := &CertificatesStorage{
+		rootPath:    t.TempDir(),
+		archivePath: t.TempDir(),
+	}
+
+	return newServerHandler(certifier, storage, "secret", true), certifier
+}
+
+func serve(handler http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer secret")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestServerHandler_unauthorized(t *testing.T) {
+	handler, _ := setupServerHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/certificates/example.com", http.NoBody)
+	req.Header.Set("Authorization", "Bearer nope")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestServerHandler(t *testing.T) {
+	handler, certifier := setupServerHandler(t)
+
+	rec := serve(handler, http.MethodGet, "/v1/certificates/example.com", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = serve(handler, http.MethodPost, "/v1/certificates", `{"domains":[]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Obtain
+	rec = serve(handler, http.MethodPost, "/v1/certificates", `{"domains":["example.com"],"profile":"shortlived"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var created CertificateResponse
+
+	err := json.NewDecoder(rec.Body).Decode(&created)
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com", created.Domain)
+	assert.Equal(t, []string{"example.com"}, created.Domains)
+	assert.NotEmpty(t, created.Certificate)
+
+	require.Len(t, certifier.requests, 1)
+	assert.Equal(t, "shortlived", certifier.requests[0].Profile)
+	assert.True(t, certifier.requests[0].Bundle)
+
+	// Get
+	rec = serve(handler, http.MethodGet, "/v1/certificates/example.com", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var fetched CertificateResponse
+
+	err = json.NewDecoder(rec.Body).Decode(&fetched)
+	require.NoError(t, err)
+
+	assert.Equal(t, created.Certificate, fetched.Certificate)
+	assert.Equal(t, "https://example.com/cert/1", fetched.CertURL)
+
+	// Renew
+	rec = serve(handler, http.MethodPost, "/v1/certificates/example.com/renew", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	require.Len(t, certifier.requests, 2)
+	assert.Equal(t, []string{"example.com"}, certifier.requests[1].Domains)
+
+	// Revoke
+	rec = serve(handler, http.MethodPost, "/v1/certificates/example.com/revoke", `{"reason":4}`)
+	require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+	require.Len(t, certifier.revoked, 1)
+
+	rec = serve(handler, http.MethodGet, "/v1/certificates/example.com", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestServerHandler_invalidRequest(t *testing.T) {
+	handler, certifier := setupServerHandler(t)
+
+	testCases := []struct {
+		desc     string
+		method   string
+		target   string
+		body     string
+		expected int
+	}{
+		{
+			desc:     "invalid domain",
+			method:   http.MethodGet,
+			target:   "/v1/certificates/xn--0",
+			expected: http.StatusBadRequest,
+		},
+		{
+			desc:     "path separator",
+			method:   http.MethodGet,
+			target:   "/v1/certificates/..%2F..%2Fexample.com",
+			expected: http.StatusBadRequest,
+		},
+		{
+			desc:     "renew invalid domain",
+			method:   http.MethodPost,
+			target:   "/v1/certificates/xn--0/renew",
+			expected: http.StatusBadRequest,
+		},
+		{
+			desc:     "revoke invalid domain",
+			method:   http.MethodPost,
+			target:   "/v1/certificates/xn--0/revoke",
+			expected: http.StatusBadRequest,
+		},
+		{
+			desc:     "obtain invalid domain",
+			method:   http.MethodPost,
+			target:   "/v1/certificates",
+			body:     `{"domains":["xn--0"]}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			desc:     "body too large",
+			method:   http.MethodPost,
+			target:   "/v1/certificates",
+			body:     `{"domains":["example.com"],"profile":"` + strings.Repeat("a", maxServerRequestSize) + `"}`,
+			expected: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			rec := serve(handler, test.method, test.target, test.body)
+			assert.Equal(t, test.expected, rec.Code, rec.Body.String())
+		})
+	}
+
+	assert.Empty(t, certifier.requests)
+}
+
+func TestServerHandler_push(t *testing.T) {
+	handler, _ := setupServerHandler(t)
+
+	var pushes int
+
+	handler.push = func(_ context.Context) error {
+		pushes++
+
+		return nil
+	}
+
+	rec := serve(handler, http.MethodPost, "/v1/certificates", `{"domains":["example.com"]}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec = serve(handler, http.MethodGet, "/v1/certificates/example.com", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = serve(handler, http.MethodPost, "/v1/certificates/example.com/renew", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = serve(handler, http.MethodPost, "/v1/certificates/example.com/revoke", "")
+	require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+	assert.Equal(t, 3, pushes)
+}
+
+type slowCertifier struct {
+	mu        sync.Mutex
+	active    int
+	maxActive int
+}
+
+func (c *slowCertifier) Obtain(_ certificate.ObtainRequest) (*certificate.Resource, error) {
+	c.mu.Lock()
+	c.active++
+	c.maxActive = max(c.maxActive, c.active)
+	c.mu.Unlock()
+
+	time.Sleep(50 * time.Millisecond)
+
+	c.mu.Lock()
+	c.active--
+	c.mu.Unlock()
+
+	return nil, errors.New("oops")
+}
+
+func (c *slowCertifier) RevokeWithReason(_ []byte, _ *uint) error {
+	return nil
+}
+
+func TestServerHandler_serialized(t *testing.T) {
+	certifier := &slowCertifier{}
+
+	storage := &CertificatesStorage{
+		rootPath:    t.TempDir(),
+		archivePath: t.TempDir(),
+	}
+
+	handler := newServerHandler(certifier, storage, "secret", true)
+
+	var wg sync.WaitGroup
+
+	for range 3 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			rec := serve(handler, http.MethodPost, "/v1/certificates", `{"domains": ["example.com"]}`)
+			assert.Equal(t, http.StatusBadGateway, rec.Code)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 1, certifier.maxActive)
+	assert.Empty(t, handler.locks.locks)
+}
+
+func Test_isLocalAddress(t *testing.T) {
+	testCases := []struct {
+		address  string
+		expected bool
+	}{
+		{address: "127.0.0.1:8080", expected: true},
+		{address: "[::1]:8080", expected: true},
+		{address: "localhost:8080", expected: true},
+		{address: "unix:/run/lego/lego.sock", expected: true},
+		{address: ":8080"},
+		{address: "0.0.0.0:8080"},
+		{address: "192.168.1.10:8080"},
+		{address: "example.com:8080"},
+		{address: "invalid"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.address, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, isLocalAddress(test.address))
+		})
+	}
+}
@@ -0,0 +1,528 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/lego"
+	"github.com/go-acme/lego/v4/providers/dns"
+	"github.com/urfave/cli/v2"
+)
+
+// Flag names.
+const flgSetupOutput = "output"
+
+// envVar an environment variable used by a DNS provider.
+type envVar struct {
+	Name        string
+	Description string
+}
+
+// setupCA a CA proposed by the setup wizard.
+type setupCA struct {
+	name string
+	url  string
+}
+
+var setupCAs = []setupCA{
+	{name: "Let's Encrypt", url: lego.LEDirectoryProduction},
+	{name: "Let's Encrypt (staging)", url: lego.LEDirectoryStaging},
+	{name: "ZeroSSL", url: lego.ZeroSSLDirectory},
+	{name: "Google Trust Services", url: "https://dv.acme-v02.api.pki.goog/directory"},
+	{name: "Buypass Go SSL", url: lego.BuypassDirectoryProduction},
+	{name: "SSL.com", url: lego.SSLcomDirectoryRSA},
+	{name: "Actalis", url: lego.ActalisDirectoryProduction},
+	{name: "Other (custom directory URL)"},
+}
+
+func createSetup() *cli.Command {
+	return &cli.Command{
+		Name: "setup",
+		Usage: "Interactively select the CA, the challenge, and the DNS provider, validate the credentials," +
+			" and write them into a configuration file (environment variables).",
+		Action: setupWizard,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  flgSetupOutput,
+				Value: "lego.env",
+				Usage: "Path to the configuration file to write.",
+			},
+		},
+	}
+}
+
+// setupConfig the configuration built by the setup wizard.
+type setupConfig struct {
+	// env the environment variables, in the order of the questions.
+	env [][2]string
+
+	domains   []string
+	challenge string
+	provider  string
+
+	// quirks the known quirks of the selected CA.
+	quirks lego.CAQuirks
+}
+
+func (c *setupConfig) setenv(key, value string) {
+	if value == "" {
+		return
+	}
+
+	c.env = append(c.env, [2]string{key, value})
+}
+
+// write writes the environment variables in a format compatible with the shells.
+func (c *setupConfig) write(w io.Writer) error {
+	_, err := fmt.Fprintln(w, "# Generated by 'lego setup'.")
+	if err != nil {
+		return err
+	}
+
+	for _, kv := range c.env {
+		_, err = fmt.Fprintf(w, "%s='%s'\n", kv[0], strings.ReplaceAll(kv[1], "'", `'\''`))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// command returns the command line to use with the configuration file.
+func (c *setupConfig) command() string {
+	args := []string{"lego", "--" + flgAcceptTOS}
+
+	for _, domain := range c.domains {
+		args = append(args, "--"+flgDomains, domain)
+	}
+
+	if c.challenge == flgDNS {
+		args = append(args, "--"+flgDNS, c.provider)
+	} else {
+		args = append(args, "--"+c.challenge)
+	}
+
+	return strings.Join(append(args, "run"), " ")
+}
+
+func setupWizard(ctx *cli.Context) error {
+	w := &wizard{
+		prompter:   newPrompter(ctx.App.Reader, ctx.App.Writer),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+
+	config, err := w.run(ctx.String(flgPath))
+	if err != nil {
+		return fmt.Errorf("setup: %w", err)
+	}
+
+	filename := ctx.String(flgSetupOutput)
+
+	_, err = os.Stat(filename)
+	if err == nil {
+		overwrite, errC := w.confirm(fmt.Sprintf("The file %s already exists. Overwrite it?", filename), false)
+		if errC != nil {
+			return fmt.Errorf("setup: %w", errC)
+		}
+
+		if !overwrite {
+			return errors.New("setup: aborted")
+		}
+	}
+
+	// The file contains credentials.
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("setup: %w", err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	err = config.write(file)
+	if err != nil {
+		return fmt.Errorf("setup: write %s: %w", filename, err)
+	}
+
+	w.printf("\nThe configuration has been written to %s.\n", filename)
+	w.printf("To obtain the certificate, run:\n\n")
+	w.printf("  set -a && . %s && set +a\n", shellPath(filename))
+	w.printf("  %s\n", config.command())
+
+	return nil
+}
+
+// wizard asks the questions of the setup command.
+type wizard struct {
+	*prompter
+
+	httpClient *http.Client
+}
+
+func (w *wizard) run(defaultPath string) (*setupConfig, error) {
+	config := &setupConfig{}
+
+	err := w.askCA(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var email string
+
+	if config.quirks.ContactRequired {
+		email, err = w.askRequired(fmt.Sprintf("Email address used for the registration (required by %s)", config.quirks.Name), "")
+	} else {
+		email, err = w.ask("Email address used for the registration (recommended)", "")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	config.setenv(envEmail, email)
+
+	domains, err := w.askRequired("Domains (separated by commas)", "")
+	if err != nil {
+		return nil, err
+	}
+
+	config.domains = splitDomains(domains)
+
+	path, err := w.ask("Path to the directory to use for storing the data", defaultPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if path != defaultPath {
+		config.setenv(envPath, path)
+	}
+
+	err = w.askChallenge(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func (w *wizard) askCA(config *setupConfig) error {
+	names := make([]string, 0, len(setupCAs))
+	for _, ca := range setupCAs {
+		names = append(names, ca.name)
+	}
+
+	for {
+		index, err := w.choose("Certificate Authority:", names)
+		if err != nil {
+			return err
+		}
+
+		caURL := setupCAs[index].url
+		if caURL == "" {
+			caURL, err = w.askRequired("ACME directory URL", "")
+			if err != nil {
+				return err
+			}
+		}
+
+		directory, err := w.fetchDirectory(caURL)
+		if err != nil {
+			w.printf("Unable to get the ACME directory: %v\n", err)
+			continue
+		}
+
+		if directory.Meta.TermsOfService != "" {
+			accepted, errC := w.confirm(fmt.Sprintf("Do you accept the terms of service (%s)?", directory.Meta.TermsOfService), false)
+			if errC != nil {
+				return errC
+			}
+
+			if !accepted {
+				return errors.New("the terms of service must be accepted")
+			}
+		}
+
+		if caURL != lego.LEDirectoryProduction {
+			config.setenv(envServer, caURL)
+		}
+
+		config.quirks, _ = lego.LookupCAQuirks(caURL)
+
+		if !directory.Meta.ExternalAccountRequired && !config.quirks.EABRequired {
+			return nil
+		}
+
+		w.printf("This CA requires an External Account Binding (EAB).\n")
+
+		kid, err := w.askRequired("EAB key identifier", "")
+		if err != nil {
+			return err
+		}
+
+		hmac, err := w.askRequired("EAB HMAC key (base64url)", "")
+		if err != nil {
+			return err
+		}
+
+		config.setenv(envEAB, "true")
+		config.setenv(envEABKID, kid)
+		config.setenv(envEABHMAC, hmac)
+
+		return nil
+	}
+}
+
+func (w *wizard) fetchDirectory(caURL string) (*acme.Directory, error) {
+	resp, err := w.httpClient.Get(caURL)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	directory := &acme.Directory{}
+
+	err = json.NewDecoder(resp.Body).Decode(directory)
+	if err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+
+	if directory.NewAccountURL == "" || directory.NewOrderURL == "" {
+		return nil, errors.New("not an ACME directory")
+	}
+
+	return directory, nil
+}
+
+func (w *wizard) askChallenge(config *setupConfig) error {
+	challenges := []string{flgHTTP, flgTLS, flgDNS}
+
+	index, err := w.choose("Challenge:", []string{
+		"HTTP-01 (the port 80 of the domains must be reachable)",
+		"TLS-ALPN-01 (the port 443 of the domains must be reachable)",
+		"DNS-01 (requires an access to the API of the DNS provider, allows wildcard certificates)",
+	})
+	if err != nil {
+		return err
+	}
+
+	config.challenge = challenges[index]
+
+	if config.challenge != flgDNS {
+		return nil
+	}
+
+	codes := strings.Split(allDNSCodes(), ", ")
+
+	for {
+		code, err := w.askRequired("DNS provider code (use 'lego dnshelp' to list them)", "")
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(codes, code) {
+			w.printf("Unknown DNS provider %q.\n", code)
+			continue
+		}
+
+		config.provider = code
+
+		break
+	}
+
+	return w.askCredentials(config)
+}
+
+func (w *wizard) askCredentials(config *setupConfig) error {
+	credentials := dnsCredentials(config.provider)
+
+	if len(credentials) > 0 {
+		w.printf("Credentials of %s (leave empty the unused ones, more information: https://go-acme.github.io/lego/dns/%s):\n",
+			config.provider, config.provider)
+	}
+
+	for {
+		values := make(map[string]string)
+
+		for _, cred := range credentials {
+			value, err := w.ask(fmt.Sprintf("%s (%s)", cred.Name, cred.Description), os.Getenv(cred.Name))
+			if err != nil {
+				return err
+			}
+
+			values[cred.Name] = value
+		}
+
+		err := validateDNSCredentials(config.provider, values)
+		if err == nil {
+			for _, cred := range credentials {
+				config.setenv(cred.Name, values[cred.Name])
+			}
+
+			return nil
+		}
+
+		w.printf("Invalid credentials: %v\n", err)
+	}
+}
+
+// validateDNSCredentials creates the DNS provider with the given environment variables.
+func validateDNSCredentials(code string, values map[string]string) error {
+	previous := make(map[string]*string)
+
+	for key, value := range values {
+		if v, ok := os.LookupEnv(key); ok {
+			previous[key] = &v
+		} else {
+			previous[key] = nil
+		}
+
+		_ = os.Setenv(key, value)
+	}
+
+	defer func() {
+		for key, value := range previous {
+			if value == nil {
+				_ = os.Unsetenv(key)
+			} else {
+				_ = os.Setenv(key, *value)
+			}
+		}
+	}()
+
+	_, err := dns.NewDNSChallengeProviderByName(code)
+
+	return err
+}
+
+func splitDomains(value string) []string {
+	var domains []string
+
+	for _, domain := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' }) {
+		domains = append(domains, strings.TrimSpace(domain))
+	}
+
+	return domains
+}
+
+func shellPath(filename string) string {
+	if strings.ContainsRune(filename, os.PathSeparator) {
+		return filename
+	}
+
+	return "./" + filename
+}
+
+// prompter reads the answers of the interactive questions.
+type prompter struct {
+	reader *bufio.Reader
+	writer io.Writer
+}
+
+func newPrompter(r io.Reader, w io.Writer) *prompter {
+	return &prompter{reader: bufio.NewReader(r), writer: w}
+}
+
+func (p *prompter) printf(format string, a ...any) {
+	_, _ = fmt.Fprintf(p.writer, format, a...)
+}
+
+// ask asks a question, the default value is used if the answer is empty.
+func (p *prompter) ask(question, defaultValue string) (string, error) {
+	if defaultValue == "" {
+		p.printf("%s: ", question)
+	} else {
+		p.printf("%s [%s]: ", question, defaultValue)
+	}
+
+	line, err := p.reader.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", err
+	}
+
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return defaultValue, nil
+	}
+
+	return line, nil
+}
+
+// askRequired asks a question until the answer is not empty.
+func (p *prompter) askRequired(question, defaultValue string) (string, error) {
+	for {
+		answer, err := p.ask(question, defaultValue)
+		if err != nil {
+			return "", err
+		}
+
+		if answer != "" {
+			return answer, nil
+		}
+
+		p.printf("A value is required.\n")
+	}
+}
+
+// choose asks to choose one of the options, and returns its index.
+func (p *prompter) choose(question string, options []string) (int, error) {
+	p.printf("%s\n", question)
+
+	for i, option := range options {
+		p.printf("  %d) %s\n", i+1, option)
+	}
+
+	for {
+		answer, err := p.ask("Choice", "1")
+		if err != nil {
+			return 0, err
+		}
+
+		n, err := strconv.Atoi(answer)
+		if err == nil && n >= 1 && n <= len(options) {
+			return n - 1, nil
+		}
+
+		p.printf("Invalid choice: %s\n", answer)
+	}
+}
+
+// confirm asks a yes/no question.
+func (p *prompter) confirm(question string, defaultValue bool) (bool, error) {
+	choices := "y/N"
+	if defaultValue {
+		choices = "Y/n"
+	}
+
+	for {
+		answer, err := p.ask(fmt.Sprintf("%s (%s)", question, choices), "")
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.ToLower(answer) {
+		case "":
+			return defaultValue, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		default:
+			p.printf("Please answer yes or no.\n")
+		}
+	}
+}
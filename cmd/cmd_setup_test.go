@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupDirectoryServer(t *testing.T, meta acme.Meta) string {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(rw).Encode(acme.Directory{
+			NewNonceURL:   "https://example.com/new-nonce",
+			NewAccountURL: "https://example.com/new-account",
+			NewOrderURL:   "https://example.com/new-order",
+			Meta:          meta,
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+func Test_wizard_run(t *testing.T) {
+	t.Setenv("ZONOMI_API_KEY", "")
+
+	caURL := setupDirectoryServer(t, acme.Meta{
+		TermsOfService:          "https://example.com/tos",
+		ExternalAccountRequired: true,
+	})
+
+	input := strings.Join([]string{
+		"8",    // CA: other
+		caURL,  // directory URL
+		"y",    // terms of service
+		"kid",  // EAB key identifier
+		"hmac", // EAB HMAC
+		"foo@example.com",
+		"example.com, www.example.com",
+		"",        // path
+		"3",       // DNS-01
+		"unknown", // invalid provider
+		"zonomi",  // provider
+		"",        // missing credentials
+		"secret",  // ZONOMI_API_KEY
+	}, "\n") + "\n"
+
+	output := &bytes.Buffer{}
+
+	w := &wizard{
+		prompter:   newPrompter(strings.NewReader(input), output),
+		httpClient: http.DefaultClient,
+	}
+
+	config, err := w.run("/tmp/.lego")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"example.com", "www.example.com"}, config.domains)
+	assert.Equal(t, flgDNS, config.challenge)
+	assert.Equal(t, "zonomi", config.provider)
+
+	expectedEnv := [][2]string{
+		{envServer, caURL},
+		{envEAB, "true"},
+		{envEABKID, "kid"},
+		{envEABHMAC, "hmac"},
+		{envEmail, "foo@example.com"},
+		{"ZONOMI_API_KEY", "secret"},
+	}
+
+	assert.Equal(t, expectedEnv, config.env)
+
+	assert.Contains(t, output.String(), `Unknown DNS provider "unknown".`)
+	assert.Contains(t, output.String(), "Invalid credentials:")
+
+	assert.Equal(t, "lego --accept-tos --domains example.com --domains www.example.com --dns zonomi run", config.command())
+}
+
+func Test_wizard_run_tosRejected(t *testing.T) {
+	caURL := setupDirectoryServer(t, acme.Meta{TermsOfService: "https://example.com/tos"})
+
+	w := &wizard{
+		prompter:   newPrompter(strings.NewReader("8\n"+caURL+"\nn\n"), &bytes.Buffer{}),
+		httpClient: http.DefaultClient,
+	}
+
+	_, err := w.run("/tmp/.lego")
+	require.EqualError(t, err, "the terms of service must be accepted")
+}
+
+func Test_wizard_run_http(t *testing.T) {
+	caURL := setupDirectoryServer(t, acme.Meta{})
+
+	input := strings.Join([]string{"8", caURL, "", "example.com", "/var/lib/lego", "1"}, "\n") + "\n"
+
+	w := &wizard{
+		prompter:   newPrompter(strings.NewReader(input), &bytes.Buffer{}),
+		httpClient: http.DefaultClient,
+	}
+
+	config, err := w.run("/tmp/.lego")
+	require.NoError(t, err)
+
+	expectedEnv := [][2]string{
+		{envServer, caURL},
+		{envPath, "/var/lib/lego"},
+	}
+
+	assert.Equal(t, expectedEnv, config.env)
+	assert.Equal(t, "lego --accept-tos --domains example.com --http run", config.command())
+}
+
+func Test_setupConfig_write(t *testing.T) {
+	config := &setupConfig{}
+	config.setenv(envEmail, "foo@example.com")
+	config.setenv(envPath, "")
+	config.setenv("FOO_PASSWORD", "it's a secret")
+
+	b := &bytes.Buffer{}
+
+	err := config.write(b)
+	require.NoError(t, err)
+
+	expected := `# Generated by 'lego setup'.
+LEGO_EMAIL='foo@example.com'
+FOO_PASSWORD='it'\''s a secret'
+`
+
+	assert.Equal(t, expected, b.String())
+}
+
+func Test_prompter_choose(t *testing.T) {
+	output := &bytes.Buffer{}
+
+	p := newPrompter(strings.NewReader("0\nfoo\n2\n"), output)
+
+	index, err := p.choose("Question:", []string{"a", "b"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, index)
+	assert.Contains(t, output.String(), "Invalid choice: 0")
+	assert.Contains(t, output.String(), "Invalid choice: foo")
+}
+
+func Test_prompter_ask_eof(t *testing.T) {
+	p := newPrompter(strings.NewReader(""), &bytes.Buffer{})
+
+	_, err := p.askRequired("Question", "")
+	require.Error(t, err)
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-acme/lego/v4/log"
+)
+
+// systemd notification states.
+// https://www.freedesktop.org/software/systemd/man/latest/sd_notify.html
+const (
+	sdNotifyReady    = "READY=1"
+	sdNotifyStopping = "STOPPING=1"
+	sdNotifyWatchdog = "WATCHDOG=1"
+)
+
+const unixSocketPrefix = "unix:"
+
+// healthTimeout the maximum duration without a tick of the heartbeat (ex: a hung renewal check or a hung request)
+// before the process is considered as unhealthy.
+const healthTimeout = 15 * time.Minute
+
+// sdNotify sends a state to the systemd notification socket.
+// It's a no-op (false, nil) when the process is not supervised by systemd.
+func sdNotify(state string) (bool, error) {
+	socketName := os.Getenv("NOTIFY_SOCKET")
+	if socketName == "" {
+		return false, nil
+	}
+
+	// Abstract socket.
+	if strings.HasPrefix(socketName, "@") {
+		socketName = "\x00" + socketName[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socketName, Net: "unixgram"})
+	if err != nil {
+		return false, fmt.Errorf("sd_notify: %w", err)
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	_, err = conn.Write([]byte(state))
+	if err != nil {
+		return false, fmt.Errorf("sd_notify: %w", err)
+	}
+
+	return true, nil
+}
+
+// sdWatchdogInterval returns the interval between two watchdog notifications (half of the systemd watchdog timeout),
+// or 0 if the watchdog is not enabled for this process.
+func sdWatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+
+	return time.Duration(usec) * time.Microsecond / 2
+}
+
+// runWatchdog sends the watchdog notifications while the daemon is healthy.
+// When the daemon is not healthy, the notifications are not sent anymore, and systemd restarts the service.
+func runWatchdog(ctx context.Context, healthy func() bool) {
+	interval := sdWatchdogInterval()
+	if interval == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !healthy() {
+				log.Warnf("The daemon is not healthy: the watchdog notification is not sent.")
+				continue
+			}
+
+			_, err := sdNotify(sdNotifyWatchdog)
+			if err != nil {
+				log.Warnf("Unable to notify the watchdog: %v", err)
+			}
+		}
+	}
+}
+
+// heartbeat records the last tick of a loop (the renewal loop of the daemon, the operations of the server).
+// The loop is healthy while the last tick is more recent than maxAge.
+type heartbeat struct {
+	maxAge time.Duration
+
+	// last the time of the last tick (Unix nanoseconds).
+	last atomic.Int64
+}
+
+func newHeartbeat(maxAge time.Duration) *heartbeat {
+	h := &heartbeat{maxAge: maxAge}
+	h.tick()
+
+	return h
+}
+
+func (h *heartbeat) tick() {
+	h.last.Store(time.Now().UnixNano())
+}
+
+func (h *heartbeat) healthy() bool {
+	return time.Since(time.Unix(0, h.last.Load())) <= h.maxAge
+}
+
+// newHealthHandler creates the handler of the health endpoint (GET /health).
+func newHealthHandler(healthy func() bool) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /health", func(rw http.ResponseWriter, _ *http.Request) {
+		if !healthy() {
+			writeServerResponse(rw, http.StatusServiceUnavailable, map[string]string{"status": "unhealthy"})
+			return
+		}
+
+		writeServerResponse(rw, http.StatusOK, map[string]string{"status": "ok"})
+	})
+
+	return mux
+}
+
+// listen creates a listener for a TCP address (host:port) or a unix socket (unix:/path/to/socket).
+func listen(address string) (net.Listener, error) {
+	path, ok := strings.CutPrefix(address, unixSocketPrefix)
+	if !ok {
+		return net.Listen("tcp", address)
+	}
+
+	// Removes the socket left by a previous process.
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("remove the unix socket: %w", err)
+	}
+
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_sdNotify(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "notify.sock")
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	t.Setenv("NOTIFY_SOCKET", socketPath)
+
+	sent, err := sdNotify(sdNotifyReady)
+	require.NoError(t, err)
+
+	assert.True(t, sent)
+
+	buf := make([]byte, 64)
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, sdNotifyReady, string(buf[:n]))
+}
+
+func Test_sdNotify_noSocket(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+
+	sent, err := sdNotify(sdNotifyReady)
+	require.NoError(t, err)
+
+	assert.False(t, sent)
+}
+
+func Test_sdWatchdogInterval(t *testing.T) {
+	t.Setenv("WATCHDOG_USEC", "30000000")
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()))
+
+	assert.Equal(t, 15*time.Second, sdWatchdogInterval())
+
+	t.Setenv("WATCHDOG_PID", "1")
+
+	assert.Zero(t, sdWatchdogInterval())
+}
+
+func Test_newHealthHandler(t *testing.T) {
+	healthy := true
+
+	handler := newHealthHandler(func() bool { return healthy })
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+
+	healthy = false
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", http.NoBody))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func Test_listen_unix(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "lego.sock")
+
+	listener, err := listen(unixSocketPrefix + socketPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, "unix", listener.Addr().Network())
+
+	// The socket is not removed on close by a crashed process.
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, listener.Close())
+
+	listener, err = listen(unixSocketPrefix + socketPath)
+	require.NoError(t, err)
+
+	require.NoError(t, listener.Close())
+}
+
+func Test_heartbeat(t *testing.T) {
+	beat := newHeartbeat(time.Minute)
+
+	assert.True(t, beat.healthy())
+
+	beat.last.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+
+	assert.False(t, beat.healthy())
+
+	beat.tick()
+
+	assert.True(t, beat.healthy())
+}
+
+func Test_handleDaemonShutdown(t *testing.T) {
+	signals := make(chan os.Signal, 1)
+	stopping := make(chan struct{})
+	done := make(chan struct{})
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	result := make(chan bool, 1)
+
+	go func() {
+		result <- handleDaemonShutdown(signals, newShutdownWatcher(time.Minute), stopping, cancel, done)
+	}()
+
+	signals <- syscall.SIGTERM
+
+	<-stopping
+
+	// The in-flight renewal completes during the grace period.
+	close(done)
+
+	assert.True(t, <-result)
+	require.NoError(t, context.Cause(ctx))
+}
+
+func Test_handleDaemonShutdown_abandon(t *testing.T) {
+	signals := make(chan os.Signal, 1)
+	stopping := make(chan struct{})
+	done := make(chan struct{})
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	result := make(chan bool, 1)
+
+	go func() {
+		result <- handleDaemonShutdown(signals, newShutdownWatcher(time.Minute), stopping, cancel, done)
+	}()
+
+	signals <- syscall.SIGTERM
+
+	<-stopping
+
+	// The second signal abandons the in-flight orders, and cancels the renewal.
+	signals <- syscall.SIGTERM
+
+	<-ctx.Done()
+
+	require.EqualError(t, context.Cause(ctx), "stopped by terminated: the in-flight orders have been abandoned")
+
+	close(done)
+
+	assert.True(t, <-result)
+}
+
+func Test_handleDaemonShutdown_exit(t *testing.T) {
+	signals := make(chan os.Signal, 1)
+	stopping := make(chan struct{})
+	done := make(chan struct{})
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	result := make(chan bool, 1)
+
+	go func() {
+		result <- handleDaemonShutdown(signals, newShutdownWatcher(10*time.Millisecond), stopping, cancel, done)
+	}()
+
+	signals <- syscall.SIGTERM
+
+	<-stopping
+
+	// The grace period is over.
+	<-ctx.Done()
+
+	// The renewal doesn't return.
+	signals <- syscall.SIGTERM
+
+	assert.False(t, <-result)
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-acme/lego/v4/log"
+)
+
+// renewalTrigger a request of immediate renewal evaluation sent to the daemon (SIGUSR1, control socket).
+type renewalTrigger struct {
+	// Name the name of the certificate (ex: example.com, example.com_rsa2048).
+	// All the certificates of the daemon are evaluated if it's empty.
+	Name string `json:"name,omitempty"`
+
+	// Force renews the certificates even if they are outside the renewal window
+	// (ex: revocation of the certificates, compromise of the private keys).
+	Force bool `json:"force,omitempty"`
+}
+
+// selects returns true if the trigger applies to the certificate.
+// A nil trigger (scheduled renewal check) applies to all the certificates.
+func (t *renewalTrigger) selects(name string) bool {
+	return t == nil || t.Name == "" || t.Name == name
+}
+
+// forced returns true if the certificates must be renewed even if they are outside the renewal window.
+func (t *renewalTrigger) forced() bool {
+	return t != nil && t.Force
+}
+
+func (t *renewalTrigger) String() string {
+	target := "all the certificates"
+	if t.Name != "" {
+		target = t.Name
+	}
+
+	if t.Force {
+		return "forced renewal of " + target
+	}
+
+	return "renewal check of " + target
+}
+
+// watchRenewalSignals sends a trigger, for all the certificates, on each renewal signal (SIGUSR1).
+// The triggers are dropped while a trigger is pending.
+func watchRenewalSignals(triggers chan<- renewalTrigger, done <-chan struct{}) {
+	if len(renewalSignals) == 0 {
+		return
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, renewalSignals...)
+
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-done:
+			return
+		case sig := <-signals:
+			log.Infof("Received %s: immediate renewal check.", sig)
+
+			sendRenewalTrigger(triggers, renewalTrigger{})
+		}
+	}
+}
+
+// sendRenewalTrigger sends a trigger without blocking: it returns false if a trigger is already pending.
+func sendRenewalTrigger(triggers chan<- renewalTrigger, trigger renewalTrigger) bool {
+	select {
+	case triggers <- trigger:
+		return true
+	default:
+		return false
+	}
+}
+
+// newControlHandler creates the handler of the control socket of the daemon (POST /renew).
+// The names are the names of the certificates of the daemon (not checked if empty).
+//
+// The query parameters:
+//   - name: the name of the certificate (all the certificates by default).
+//   - force: renews the certificates even if they are outside the renewal window.
+func newControlHandler(triggers chan<- renewalTrigger, names []string) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /renew", func(rw http.ResponseWriter, req *http.Request) {
+		trigger := renewalTrigger{Name: req.URL.Query().Get("name")}
+
+		if trigger.Name != "" && len(names) > 0 && !slices.Contains(names, trigger.Name) {
+			writeServerResponse(rw, http.StatusNotFound, map[string]string{"error": "unknown certificate: " + trigger.Name})
+			return
+		}
+
+		if raw := req.URL.Query().Get("force"); raw != "" {
+			force, err := strconv.ParseBool(raw)
+			if err != nil {
+				writeServerResponse(rw, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("invalid force parameter: %q", raw)})
+				return
+			}
+
+			trigger.Force = force
+		}
+
+		if !sendRenewalTrigger(triggers, trigger) {
+			writeServerResponse(rw, http.StatusConflict, map[string]string{"error": "a renewal is already pending"})
+			return
+		}
+
+		log.Infof("Control socket: %s.", &trigger)
+
+		writeServerResponse(rw, http.StatusAccepted, trigger)
+	})
+
+	return mux
+}
+
+// requestRenewal sends a trigger to the control socket of a daemon.
+func requestRenewal(ctx context.Context, socketPath string, trigger renewalTrigger) error {
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+
+				return dialer.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+
+	query := url.Values{}
+
+	if trigger.Name != "" {
+		query.Set("name", trigger.Name)
+	}
+
+	if trigger.Force {
+		query.Set("force", "true")
+	}
+
+	endpoint := url.URL{Scheme: "http", Host: "lego", Path: "/renew", RawQuery: query.Encode()}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusAccepted {
+		return nil
+	}
+
+	var result struct {
+		Error string `json:"error"`
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil || result.Error == "" {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return errors.New(result.Error)
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_renewalTrigger_selects(t *testing.T) {
+	var scheduled *renewalTrigger
+
+	assert.True(t, scheduled.selects("example.com"))
+	assert.False(t, scheduled.forced())
+
+	all := &renewalTrigger{Force: true}
+
+	assert.True(t, all.selects("example.com_rsa2048"))
+	assert.True(t, all.forced())
+
+	named := &renewalTrigger{Name: "example.com"}
+
+	assert.True(t, named.selects("example.com"))
+	assert.False(t, named.selects("example.com_rsa2048"))
+}
+
+func Test_newControlHandler(t *testing.T) {
+	triggers := make(chan renewalTrigger, 1)
+
+	handler := newControlHandler(triggers, []string{"example.com", "example.com_rsa2048"})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/renew?name=example.com_rsa2048&force=true", http.NoBody))
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.JSONEq(t, `{"name":"example.com_rsa2048","force":true}`, rec.Body.String())
+
+	// Only one trigger is pending.
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/renew", http.NoBody))
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	assert.Equal(t, renewalTrigger{Name: "example.com_rsa2048", Force: true}, <-triggers)
+}
+
+func Test_newControlHandler_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		target   string
+		expected int
+	}{
+		{
+			desc:     "unknown certificate",
+			target:   "/renew?name=example.org",
+			expected: http.StatusNotFound,
+		},
+		{
+			desc:     "invalid force",
+			target:   "/renew?force=maybe",
+			expected: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			triggers := make(chan renewalTrigger, 1)
+
+			handler := newControlHandler(triggers, []string{"example.com"})
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, test.target, http.NoBody))
+
+			assert.Equal(t, test.expected, rec.Code)
+			assert.Empty(t, triggers)
+		})
+	}
+}
+
+func Test_requestRenewal(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "control.sock")
+
+	listener, err := listen(unixSocketPrefix + socketPath)
+	require.NoError(t, err)
+
+	triggers := make(chan renewalTrigger, 1)
+
+	srv := &http.Server{Handler: newControlHandler(triggers, []string{"example.com"})}
+
+	t.Cleanup(func() { _ = srv.Close() })
+
+	go func() { _ = srv.Serve(listener) }()
+
+	err = requestRenewal(t.Context(), socketPath, renewalTrigger{Name: "example.com", Force: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, renewalTrigger{Name: "example.com", Force: true}, <-triggers)
+
+	err = requestRenewal(t.Context(), socketPath, renewalTrigger{Name: "example.org"})
+	require.EqualError(t, err, "unknown certificate: example.org")
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"os"
+	"syscall"
+)
+
+// renewalSignals the signals triggering an immediate renewal check of the daemon.
+var renewalSignals = []os.Signal{syscall.SIGUSR1}
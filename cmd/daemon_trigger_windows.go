@@ -0,0 +1,8 @@
+//go:build windows
+
+package cmd
+
+import "os"
+
+// renewalSignals the signals triggering an immediate renewal check of the daemon (none on Windows: use the control socket).
+var renewalSignals []os.Signal
@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/lego"
 	"github.com/urfave/cli/v2"
 	"software.sslmate.com/src/go-pkcs12"
@@ -13,7 +15,12 @@ import (
 // Flag names.
 const (
 	flgDomains                  = "domains"
+	flgHostCert                 = "host-cert"
+	flgHostCertPrivateIPs       = "host-cert.private-ips"
+	flgHostCertNoIP             = "host-cert.no-ip"
 	flgServer                   = "server"
+	flgServerPreset             = "server-preset"
+	flgIKnow                    = "i-know"
 	flgAcceptTOS                = "accept-tos"
 	flgEmail                    = "email"
 	flgDisableCommonName        = "disable-cn"
@@ -22,8 +29,11 @@ const (
 	flgKID                      = "kid"
 	flgHMAC                     = "hmac"
 	flgKeyType                  = "key-type"
+	flgAccountKeySigner         = "account-key-signer"
 	flgFilename                 = "filename"
 	flgPath                     = "path"
+	flgEnvFile                  = "env-file"
+	flgStorage                  = "storage"
 	flgHTTP                     = "http"
 	flgHTTPPort                 = "http.port"
 	flgHTTPDelay                = "http.delay"
@@ -31,37 +41,83 @@ const (
 	flgHTTPWebroot              = "http.webroot"
 	flgHTTPMemcachedHost        = "http.memcached-host"
 	flgHTTPS3Bucket             = "http.s3-bucket"
+	flgHTTPS3CloudFront         = "http.s3-cloudfront-distribution"
+	flgHTTPCDN                  = "http.cdn"
 	flgTLS                      = "tls"
 	flgTLSPort                  = "tls.port"
 	flgTLSDelay                 = "tls.delay"
+	flgTLSProxy                 = "tls.proxy"
+	flgTLSProxyProtocol         = "tls.proxy-protocol"
+	flgTLSMinVersion            = "tls.min-version"
+	flgTLSCipherSuites          = "tls.cipher-suites"
+	flgTLSCurves                = "tls.curves"
+	flgOnionCSR                 = "onion-csr"
 	flgDNS                      = "dns"
 	flgDNSDisableCP             = "dns.disable-cp"
 	flgDNSPropagationWait       = "dns.propagation-wait"
 	flgDNSPropagationDisableANS = "dns.propagation-disable-ans"
 	flgDNSPropagationRNS        = "dns.propagation-rns"
+	flgDNSPropagationAllANS     = "dns.propagation-all-ans"
+	flgDNSPropagationDelay      = "dns.propagation-initial-delay"
 	flgDNSResolvers             = "dns.resolvers"
+	flgDNSDiscoveryResolvers    = "dns.discovery-resolvers"
+	flgDNSPropagationResolvers  = "dns.propagation-resolvers"
+	flgDNSTransport             = "dns.transport"
+	flgDNSProxy                 = "dns.proxy"
+	flgDNSPerspectives          = "dns.perspectives"
+	flgDNSPerspectivesQuorum    = "dns.perspectives-quorum"
+	flgDNSAPIHosts              = "dns.api-hosts"
+	flgDNSChallengeFQDN         = "dns.challenge-fqdn"
+	flgDNSChallengeValue        = "dns.challenge-value"
 	flgHTTPTimeout              = "http-timeout"
 	flgTLSSkipVerify            = "tls-skip-verify"
+	flgHTTPMaxIdleConns         = "http-max-idle-conns"
+	flgHTTPMaxIdleConnsPerHost  = "http-max-idle-conns-per-host"
+	flgHTTPIdleConnTimeout      = "http-idle-conn-timeout"
+	flgHTTP2                    = "http2"
+	flgTLSSessionCache          = "tls-session-cache"
 	flgDNSTimeout               = "dns-timeout"
 	flgPEM                      = "pem"
 	flgPFX                      = "pfx"
 	flgPFXPass                  = "pfx.pass"
 	flgPFXFormat                = "pfx.format"
+	flgJKS                      = "jks"
+	flgJKSPass                  = "jks.pass"
+	flgJKSAlias                 = "jks.alias"
+	flgPins                     = "pins"
 	flgCertTimeout              = "cert.timeout"
+	flgCertDownloadRetries      = "cert.download-retries"
 	flgOverallRequestLimit      = "overall-request-limit"
 	flgUserAgent                = "user-agent"
+	flgStrict                   = "strict"
+	flgShutdownGracePeriod      = "shutdown-grace-period"
+	flgIssuedHook               = "issued-hook"
+	flgIssuedHookTimeout        = "issued-hook-timeout"
+	flgAdditionalKeyType        = "additional-key-type"
+	flgMaxParallelChallenges    = "max-parallel-challenges"
+	flgPreValidateHook          = "pre-validate-hook"
+	flgPostValidateHook         = "post-validate-hook"
+	flgValidateHookTimeout      = "validate-hook-timeout"
 )
 
 const (
-	envEAB         = "LEGO_EAB"
-	envEABHMAC     = "LEGO_EAB_HMAC"
-	envEABKID      = "LEGO_EAB_KID"
-	envEmail       = "LEGO_EMAIL"
-	envPath        = "LEGO_PATH"
-	envPFX         = "LEGO_PFX"
-	envPFXFormat   = "LEGO_PFX_FORMAT"
-	envPFXPassword = "LEGO_PFX_PASSWORD"
-	envServer      = "LEGO_SERVER"
+	envAccountKeySigner = "LEGO_ACCOUNT_KEY_SIGNER"
+	envEAB              = "LEGO_EAB"
+	envEABHMAC          = "LEGO_EAB_HMAC"
+	envEABKID           = "LEGO_EAB_KID"
+	envEmail            = "LEGO_EMAIL"
+	envEnvFile          = "LEGO_ENV_FILE"
+	envJKS              = "LEGO_JKS"
+	envJKSAlias         = "LEGO_JKS_ALIAS"
+	envJKSPassword      = "LEGO_JKS_PASSWORD"
+	envPath             = "LEGO_PATH"
+	envPins             = "LEGO_PINS"
+	envPFX              = "LEGO_PFX"
+	envPFXFormat        = "LEGO_PFX_FORMAT"
+	envPFXPassword      = "LEGO_PFX_PASSWORD"
+	envServer           = "LEGO_SERVER"
+	envServerPreset     = "LEGO_SERVER_PRESET"
+	envStorage          = "LEGO_STORAGE"
 )
 
 func CreateFlags(defaultPath string) []cli.Flag {
@@ -71,6 +127,19 @@ func CreateFlags(defaultPath string) []cli.Flag {
 			Aliases: []string{"d"},
 			Usage:   "Add a domain to the process. Can be specified multiple times.",
 		},
+		&cli.BoolFlag{
+			Name: flgHostCert,
+			Usage: "Add the hostname and the public IP addresses of the machine to the domains (host certificate)." +
+				" The hostname is the name of the certificate if no domain is specified.",
+		},
+		&cli.BoolFlag{
+			Name:  flgHostCertPrivateIPs,
+			Usage: "Add also the private IP addresses of the machine (ex: internal CA). Only works with --" + flgHostCert + ".",
+		},
+		&cli.BoolFlag{
+			Name:  flgHostCertNoIP,
+			Usage: "Do not add the IP addresses of the machine (CA without IP identifiers support). Only works with --" + flgHostCert + ".",
+		},
 		&cli.StringFlag{
 			Name:    flgServer,
 			Aliases: []string{"s"},
@@ -78,6 +147,15 @@ func CreateFlags(defaultPath string) []cli.Flag {
 			Usage:   "CA hostname (and optionally :port). The server certificate must be trusted in order to avoid further modifications to the client.",
 			Value:   lego.LEDirectoryProduction,
 		},
+		&cli.StringFlag{
+			Name:    flgServerPreset,
+			EnvVars: []string{envServerPreset},
+			Usage:   "Use the directory URL of a known CA instead of --server. Supported: " + strings.Join(serverPresetNames(), ", ") + ".",
+		},
+		&cli.BoolFlag{
+			Name:  flgIKnow,
+			Usage: "Allow the replacement of a certificate issued by a production CA by a certificate issued by a staging CA.",
+		},
 		&cli.BoolFlag{
 			Name:    flgAcceptTOS,
 			Aliases: []string{"a"},
@@ -119,6 +197,11 @@ func CreateFlags(defaultPath string) []cli.Flag {
 			Value:   "ec256",
 			Usage:   "Key type to use for private keys. Supported: rsa2048, rsa3072, rsa4096, rsa8192, ec256, ec384.",
 		},
+		&cli.StringFlag{
+			Name:    flgAccountKeySigner,
+			EnvVars: []string{envAccountKeySigner},
+			Usage:   "Sign the requests with an external account key instead of a key file. Supported: vault (HashiCorp Vault transit engine, configured with the VAULT_* environment variables).",
+		},
 		&cli.StringFlag{
 			Name:  flgFilename,
 			Usage: "(deprecated) Filename of the generated certificate.",
@@ -129,6 +212,17 @@ func CreateFlags(defaultPath string) []cli.Flag {
 			Usage:   "Directory to use for storing the data.",
 			Value:   defaultPath,
 		},
+		&cli.StringFlag{
+			Name:    flgStorage,
+			EnvVars: []string{envStorage},
+			Usage:   "Storage of the data. The data of a remote storage are copied into the directory before the command, and the changes are saved after the command. Supported: filesystem, s3 (LEGO_STORAGE_S3_* environment variables), vault (HashiCorp Vault KV v2 engine, VAULT_* environment variables).",
+			Value:   storageFileSystem,
+		},
+		&cli.StringFlag{
+			Name:    flgEnvFile,
+			EnvVars: []string{envEnvFile},
+			Usage:   "Load the environment variables (lego and providers settings) from a dotenv file. The environment variables already defined take precedence.",
+		},
 		&cli.BoolFlag{
 			Name:  flgHTTP,
 			Usage: "Use the HTTP-01 challenge to solve challenges. Can be mixed with other types of challenges.",
@@ -161,6 +255,16 @@ func CreateFlags(defaultPath string) []cli.Flag {
 			Name:  flgHTTPS3Bucket,
 			Usage: "Set the S3 bucket name to use for HTTP-01 based challenges. Challenges will be written to the S3 bucket.",
 		},
+		&cli.StringFlag{
+			Name: flgHTTPS3CloudFront,
+			Usage: "Set the ID of the CloudFront distribution serving the S3 bucket ('--" + flgHTTPS3Bucket + "')." +
+				" The challenges are invalidated in the cache of the distribution after the upload, and are not public in the bucket.",
+		},
+		&cli.StringFlag{
+			Name: flgHTTPCDN,
+			Usage: "Set the CDN to use for HTTP-01 based challenges. Challenges will be answered at the edge of the CDN." +
+				" Supported: fastly (edge dictionary), edgekv (Akamai EdgeKV). The credentials are passed in the environment variables.",
+		},
 		&cli.BoolFlag{
 			Name:  flgTLS,
 			Usage: "Use the TLS-ALPN-01 challenge to solve challenges. Can be mixed with other types of challenges.",
@@ -175,6 +279,32 @@ func CreateFlags(defaultPath string) []cli.Flag {
 			Usage: "Delay between the start of the TLS listener (use for TLSALPN-01 based challenges) and the validation of the challenge.",
 			Value: 0,
 		},
+		&cli.BoolFlag{
+			Name: flgTLSProxyProtocol,
+			Usage: "Expect a PROXY protocol header (v1 or v2) before the TLS handshake (use for TLSALPN-01 based challenges)," +
+				" when the TLS listener is behind a load balancer using the PROXY protocol.",
+		},
+		&cli.StringFlag{
+			Name: flgTLSProxy,
+			Usage: "Set the reverse proxy to use for TLS-ALPN-01 based challenges. The challenge certificate is installed in the proxy which owns the port 443." +
+				" Supported: haproxy (runtime API), envoy (SDS file). The configuration is passed in the environment variables.",
+		},
+		&cli.StringFlag{
+			Name:  flgTLSMinVersion,
+			Usage: "Set the minimum TLS version of the TLS-ALPN-01 server. Supported: 1.0, 1.1, 1.2, 1.3.",
+		},
+		&cli.StringSliceFlag{
+			Name:  flgTLSCipherSuites,
+			Usage: "Set the cipher suites of the TLS-ALPN-01 server (TLS 1.0 to 1.2 only), e.g. TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256.",
+		},
+		&cli.StringSliceFlag{
+			Name:  flgTLSCurves,
+			Usage: "Set the curve preferences of the TLS-ALPN-01 server, e.g. X25519, P256, P384, P521.",
+		},
+		&cli.StringSliceFlag{
+			Name:  flgOnionCSR,
+			Usage: "Solve the ONION-CSR-01 challenges (.onion domains) with the keys of the hidden service directory (tor HiddenServiceDir). Can be specified multiple times. Can be mixed with other types of challenges.",
+		},
 		&cli.StringFlag{
 			Name:  flgDNS,
 			Usage: "Solve a DNS-01 challenge using the specified provider. Can be mixed with other types of challenges. Run 'lego dnshelp' for help on usage.",
@@ -191,10 +321,19 @@ func CreateFlags(defaultPath string) []cli.Flag {
 			Name:  flgDNSPropagationRNS,
 			Usage: "By setting this flag to true, use all the recursive nameservers to check the propagation of the TXT record.",
 		},
+		&cli.BoolFlag{
+			Name: flgDNSPropagationAllANS,
+			Usage: "By setting this flag to true, requires the TXT record on every address of every authoritative nameserver (ex: several DNS providers for the same zone)." +
+				" The status of each nameserver is in the debug logs.",
+		},
 		&cli.DurationFlag{
 			Name:  flgDNSPropagationWait,
 			Usage: "By setting this flag, disables all the propagation checks of the TXT record and uses a wait duration instead.",
 		},
+		&cli.DurationFlag{
+			Name:  flgDNSPropagationDelay,
+			Usage: "Set the delay before the first propagation check of the TXT record (the polling interval of the provider by default). The following checks use the polling interval.",
+		},
 		&cli.StringSliceFlag{
 			Name: flgDNSResolvers,
 			Usage: "Set the resolvers to use for performing (recursive) CNAME resolving and apex domain determination." +
@@ -202,6 +341,55 @@ func CreateFlags(defaultPath string) []cli.Flag {
 				" Supported: host:port." +
 				" The default is to use the system resolvers, or Google's DNS resolvers if the system's cannot be determined.",
 		},
+		&cli.StringSliceFlag{
+			Name: flgDNSDiscoveryResolvers,
+			Usage: "Set the resolvers to use for CNAME resolving, apex domain and authoritative nameservers determination (ex: internal resolvers)." +
+				" Takes precedence over --" + flgDNSResolvers + " for this phase." +
+				" Supported: host:port.",
+		},
+		&cli.StringSliceFlag{
+			Name: flgDNSPropagationResolvers,
+			Usage: "Set the resolvers to use for checking the propagation of the TXT record (ex: public resolvers)." +
+				" Takes precedence over --" + flgDNSResolvers + " for this phase." +
+				" Supported: host:port.",
+		},
+		&cli.StringFlag{
+			Name: flgDNSTransport,
+			Usage: "Set the transport of the DNS queries (zone detection, CNAME resolution, and propagation checks)." +
+				" Supported: udp (retries over TCP when the response is truncated), tcp, auto (also retries over TCP when the UDP query fails).",
+			Value: string(dns01.DNSTransportUDP),
+		},
+		&cli.StringFlag{
+			Name: flgDNSProxy,
+			Usage: "Send the DNS queries (zone detection, CNAME resolution, propagation checks, and remote perspectives) through a SOCKS5 proxy (ex: an SSH tunnel, Tor)." +
+				" The queries are sent over TCP." +
+				" Supported: socks5://[user:password@]host:port.",
+		},
+		&cli.StringSliceFlag{
+			Name: flgDNSPerspectives,
+			Usage: "Set the remote resolvers used to check the propagation of the TXT record from several vantage points (like the multi-perspective validation of the CA)." +
+				" Supported: host:port, and DNS-over-HTTPS endpoints (https://...).",
+		},
+		&cli.IntFlag{
+			Name:  flgDNSPerspectivesQuorum,
+			Usage: "The number of remote resolvers (see '" + flgDNSPerspectives + "') that must see the TXT record. The default is all of them.",
+		},
+		&cli.StringSliceFlag{
+			Name: flgDNSAPIHosts,
+			Usage: "Override the resolution of the API hostnames of the DNS provider (like a hosts file)." +
+				" Useful when the API is only reachable through an internal address." +
+				" Supported: host=address (ex: api.example.com=10.0.0.1).",
+		},
+		&cli.StringFlag{
+			Name: flgDNSChallengeFQDN,
+			Usage: "Rewrite the FQDN of the challenge records, for the DNS frontends requiring the records under a different label." +
+				" {domain} is replaced by the domain (ex: _acme-challenge.{domain}.edge.example.net.). The CNAMEs are followed from the rewritten FQDN.",
+		},
+		&cli.StringFlag{
+			Name: flgDNSChallengeValue,
+			Usage: "Rewrite the value of the challenge records, for the DNS frontends requiring the values to be wrapped." +
+				" {value} is replaced by the value of the challenge (ex: v1:{value}).",
+		},
 		&cli.IntFlag{
 			Name:  flgHTTPTimeout,
 			Usage: "Set the HTTP timeout value to a specific value in seconds.",
@@ -210,6 +398,26 @@ func CreateFlags(defaultPath string) []cli.Flag {
 			Name:  flgTLSSkipVerify,
 			Usage: "Skip the TLS verification of the ACME server.",
 		},
+		&cli.IntFlag{
+			Name:  flgHTTPMaxIdleConns,
+			Usage: "Set the maximum number of idle (keep-alive) connections to the ACME server. (default: 100)",
+		},
+		&cli.IntFlag{
+			Name:  flgHTTPMaxIdleConnsPerHost,
+			Usage: "Set the maximum number of idle (keep-alive) connections per host to the ACME server. (default: 10)",
+		},
+		&cli.DurationFlag{
+			Name:  flgHTTPIdleConnTimeout,
+			Usage: "Set the maximum amount of time an idle connection to the ACME server remains open. (default: 90s)",
+		},
+		&cli.BoolFlag{
+			Name:  flgHTTP2,
+			Usage: "Use HTTP/2 for the requests to the ACME server when supported by the server.",
+		},
+		&cli.IntFlag{
+			Name:  flgTLSSessionCache,
+			Usage: "Set the number of TLS sessions kept for the resumption of the connections to the ACME server. 0 disables the resumption. (default: 64)",
+		},
 		&cli.IntFlag{
 			Name:  flgDNSTimeout,
 			Usage: "Set the DNS timeout value to a specific value in seconds. Used only when performing authoritative name server queries.",
@@ -232,24 +440,82 @@ func CreateFlags(defaultPath string) []cli.Flag {
 		},
 		&cli.StringFlag{
 			Name:    flgPFXFormat,
-			Usage:   "The encoding format to use when encrypting the .pfx (PCKS#12) file. Supported: RC2, DES, SHA256.",
+			Usage:   "The encoding format to use when encrypting the .pfx (PCKS#12) file. Supported: RC2, DES, SHA256 (alias: AES).",
 			Value:   "RC2",
 			EnvVars: []string{envPFXFormat},
 		},
+		&cli.BoolFlag{
+			Name:    flgJKS,
+			Usage:   "Generate an additional .jks (Java keystore) file containing the private key and the certificate chain.",
+			EnvVars: []string{envJKS},
+		},
+		&cli.StringFlag{
+			Name:    flgJKSPass,
+			Usage:   "The password used to protect the .jks (Java keystore) file and its private key entry (at least 6 characters).",
+			Value:   pkcs12.DefaultPassword,
+			EnvVars: []string{envJKSPassword},
+		},
+		&cli.StringFlag{
+			Name:    flgJKSAlias,
+			Usage:   "The alias of the private key entry of the .jks (Java keystore) file. The default is the domain of the certificate.",
+			EnvVars: []string{envJKSAlias},
+		},
+		&cli.BoolFlag{
+			Name: flgPins,
+			Usage: "Generate an additional .pins.json file containing the SPKI pins (base64 SHA-256) and the fingerprints of the certificates." +
+				" The pin of the previous private key is kept on rotation.",
+			EnvVars: []string{envPins},
+		},
 		&cli.IntFlag{
 			Name:  flgCertTimeout,
 			Usage: "Set the certificate timeout value to a specific value in seconds. Only used when obtaining certificates.",
 			Value: 30,
 		},
+		&cli.IntFlag{
+			Name:  flgCertDownloadRetries,
+			Usage: "Set the number of retries of the certificate download when the certificate is not available yet (404, 202) right after the finalization of the order. A negative value disables the retries.",
+			Value: certificate.DefaultDownloadRetries,
+		},
 		&cli.IntFlag{
 			Name:  flgOverallRequestLimit,
 			Usage: "ACME overall requests limit.",
 			Value: certificate.DefaultOverallRequestLimit,
 		},
+		&cli.IntFlag{
+			Name: flgMaxParallelChallenges,
+			Usage: "The maximum number of authorizations of an order solved concurrently (presentation and validation of the challenges)." +
+				" The providers unable to handle parallel calls (e.g. the built-in HTTP and TLS servers) keep their own limit.",
+			Value: 1,
+		},
+		&cli.StringFlag{
+			Name: flgPreValidateHook,
+			Usage: "Define a hook executed for each challenge when it's presented, before the CA is asked to validate it (ex: flush of the CDN caches, wait for the convergence of an anycast DNS)." +
+				" The challenge is in LEGO_CHALLENGE_DOMAIN, LEGO_CHALLENGE_TYPE, and LEGO_CHALLENGE_TOKEN. If the hook fails, the challenge is not validated.",
+		},
+		&cli.StringFlag{
+			Name: flgPostValidateHook,
+			Usage: "Define a hook executed for each challenge after its validation by the CA, before its cleanup." +
+				" The error of the validation, if any, is in LEGO_CHALLENGE_ERROR. The failures of the hook are only logged.",
+		},
+		&cli.DurationFlag{
+			Name:  flgValidateHookTimeout,
+			Usage: "Define the timeout for the execution of the validation hooks.",
+			Value: 2 * time.Minute,
+		},
 		&cli.StringFlag{
 			Name:  flgUserAgent,
 			Usage: "Add to the user-agent sent to the CA to identify an application embedding lego-cli",
 		},
+		&cli.BoolFlag{
+			Name:  flgStrict,
+			Usage: "Validate the ACME server responses against RFC 8555 and log the violations.",
+		},
+		&cli.DurationFlag{
+			Name: flgShutdownGracePeriod,
+			Usage: "Set the duration given to the in-flight orders to complete when the process is stopped (SIGTERM, SIGINT)." +
+				" After that, the presented challenges are cleaned up and the pending authorizations are deactivated.",
+			Value: 30 * time.Second,
+		},
 	}
 }
 
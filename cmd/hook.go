@@ -2,33 +2,115 @@ package cmd
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"os/exec"
 	"strings"
 	"time"
 
+	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/challenge/resolver"
+	"github.com/go-acme/lego/v4/log"
 )
 
 const (
-	hookEnvAccountEmail      = "LEGO_ACCOUNT_EMAIL"
-	hookEnvCertDomain        = "LEGO_CERT_DOMAIN"
-	hookEnvCertPath          = "LEGO_CERT_PATH"
-	hookEnvCertKeyPath       = "LEGO_CERT_KEY_PATH"
-	hookEnvIssuerCertKeyPath = "LEGO_ISSUER_CERT_PATH"
-	hookEnvCertPEMPath       = "LEGO_CERT_PEM_PATH"
-	hookEnvCertPFXPath       = "LEGO_CERT_PFX_PATH"
+	hookEnvAccountEmail       = "LEGO_ACCOUNT_EMAIL"
+	hookEnvCertDomain         = "LEGO_CERT_DOMAIN"
+	hookEnvCertPath           = "LEGO_CERT_PATH"
+	hookEnvCertKeyPath        = "LEGO_CERT_KEY_PATH"
+	hookEnvIssuerCertKeyPath  = "LEGO_ISSUER_CERT_PATH"
+	hookEnvCertPEMPath        = "LEGO_CERT_PEM_PATH"
+	hookEnvCertPFXPath        = "LEGO_CERT_PFX_PATH"
+	hookEnvCertJKSPath        = "LEGO_CERT_JKS_PATH"
+	hookEnvCertPinsPath       = "LEGO_CERT_PINS_PATH"
+	hookEnvCertNotBefore      = "LEGO_CERT_NOT_BEFORE"
+	hookEnvCertNotAfter       = "LEGO_CERT_NOT_AFTER"
+	hookEnvCertSCTs           = "LEGO_CERT_SCTS"
+	hookEnvCertKeyType        = "LEGO_CERT_KEY_TYPE"
+	hookEnvCertDroppedDomains = "LEGO_CERT_DROPPED_DOMAINS"
+
+	hookEnvChallengeDomain = "LEGO_CHALLENGE_DOMAIN"
+	hookEnvChallengeType   = "LEGO_CHALLENGE_TYPE"
+	hookEnvChallengeToken  = "LEGO_CHALLENGE_TOKEN"
+	hookEnvChallengeError  = "LEGO_CHALLENGE_ERROR"
 )
 
-func launchHook(hook string, timeout time.Duration, meta map[string]string) error {
+func launchHook(ctx context.Context, hook string, timeout time.Duration, meta map[string]string) error {
+	if hook == "" {
+		return nil
+	}
+
+	if wait := hookWaitDuration(meta, time.Now()); wait > 0 {
+		log.Infof("[%s] The certificate is not yet valid, waiting %s before launching the hook.", meta[hookEnvCertDomain], wait.Round(time.Second))
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return fmt.Errorf("wait for the validity of the certificate: %w", context.Cause(ctx))
+		case <-timer.C:
+		}
+	}
+
+	return executeHook(ctx, hook, timeout, meta, nil)
+}
+
+// launchIssuedHook executes the hook with the new certificate, before the files are written.
+// The certificate (PEM) is written on the standard input of the hook,
+// and the embedded SCTs are provided as JSON in LEGO_CERT_SCTS.
+// An error of the hook must prevent the files from being written.
+func launchIssuedHook(ctx context.Context, hook string, timeout time.Duration, meta map[string]string, domain string, certRes *certificate.Resource) error {
+	if hook == "" {
+		return nil
+	}
+
+	// The paths of the files are not defined yet.
+	meta = maps.Clone(meta)
+	meta[hookEnvCertDomain] = domain
+
+	addValidityToMetadata(meta, certRes)
+
+	err := addSCTsToMetadata(meta, certRes)
+	if err != nil {
+		return err
+	}
+
+	return executeHook(ctx, hook, timeout, meta, bytes.NewReader(certRes.Certificate))
+}
+
+// newValidationHook creates a validation hook (see resolver.ValidationHook) executing a command.
+func newValidationHook(hook string, timeout time.Duration) resolver.ValidationHook {
 	if hook == "" {
 		return nil
 	}
 
-	ctxCmd, cancel := context.WithTimeout(context.Background(), timeout)
+	return func(event resolver.ValidationEvent) error {
+		meta := map[string]string{
+			hookEnvChallengeDomain: event.Domain,
+			hookEnvChallengeType:   event.Challenge.Type,
+			hookEnvChallengeToken:  event.Challenge.Token,
+		}
+
+		if event.Err != nil {
+			meta[hookEnvChallengeError] = event.Err.Error()
+		}
+
+		return executeHook(context.Background(), hook, timeout, meta, nil)
+	}
+}
+
+func executeHook(ctx context.Context, hook string, timeout time.Duration, meta map[string]string, stdin io.Reader) error {
+	ctxCmd, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	parts := strings.Fields(hook)
@@ -36,6 +118,7 @@ func launchHook(hook string, timeout time.Duration, meta map[string]string) erro
 	cmd := exec.CommandContext(ctxCmd, parts[0], parts[1:]...)
 
 	cmd.Env = append(os.Environ(), metaToEnv(meta)...)
+	cmd.Stdin = stdin
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -101,4 +184,94 @@ func addPathToMetadata(meta map[string]string, domain string, certRes *certifica
 	if certsStorage.pfx {
 		meta[hookEnvCertPFXPath] = certsStorage.GetFileName(domain, pfxExt)
 	}
+
+	if certsStorage.jks {
+		meta[hookEnvCertJKSPath] = certsStorage.GetFileName(domain, jksExt)
+	}
+
+	if certsStorage.pins {
+		meta[hookEnvCertPinsPath] = certsStorage.GetFileName(domain, pinsExt)
+	}
+}
+
+// addKeyTypeToMetadata adds the key type of an additional certificate to the metadata.
+// The paths are based on the name of the certificate (see certificateName), but the domain is the main domain.
+func addKeyTypeToMetadata(meta map[string]string, domain, additionalKeyType string) {
+	if additionalKeyType == "" {
+		return
+	}
+
+	meta[hookEnvCertDomain] = domain
+	meta[hookEnvCertKeyType] = additionalKeyType
+}
+
+// addValidityToMetadata adds the validity period of the certificate to the metadata.
+func addValidityToMetadata(meta map[string]string, certRes *certificate.Resource) {
+	cert, err := certcrypto.ParsePEMCertificate(certRes.Certificate)
+	if err != nil {
+		return
+	}
+
+	meta[hookEnvCertNotBefore] = cert.NotBefore.UTC().Format(time.RFC3339)
+	meta[hookEnvCertNotAfter] = cert.NotAfter.UTC().Format(time.RFC3339)
+}
+
+// hookSCT the JSON representation of an SCT provided to the hooks.
+type hookSCT struct {
+	Version   uint8     `json:"version"`
+	LogID     string    `json:"logID"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// addSCTsToMetadata adds the SCTs embedded in the certificate to the metadata (JSON array, empty if none).
+func addSCTsToMetadata(meta map[string]string, certRes *certificate.Resource) error {
+	cert, err := certcrypto.ParsePEMCertificate(certRes.Certificate)
+	if err != nil {
+		return err
+	}
+
+	scts, err := certcrypto.ExtractSCTs(cert)
+	if err != nil {
+		return err
+	}
+
+	items := make([]hookSCT, 0, len(scts))
+
+	for _, sct := range scts {
+		items = append(items, hookSCT{
+			Version:   sct.Version,
+			LogID:     base64.StdEncoding.EncodeToString(sct.LogID),
+			Timestamp: sct.Timestamp,
+		})
+	}
+
+	raw, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+
+	meta[hookEnvCertSCTs] = string(raw)
+
+	return nil
+}
+
+// hookWaitDuration returns the duration to wait before launching the hook.
+// Some CAs don't backdate the certificates: when the clock of the CA is slightly ahead,
+// the notBefore of the new certificate is in the future, and the hook can fail with "certificate not yet valid".
+// The wait is limited to the clock skew tolerance.
+func hookWaitDuration(meta map[string]string, now time.Time) time.Duration {
+	notBefore, err := time.Parse(time.RFC3339, meta[hookEnvCertNotBefore])
+	if err != nil {
+		return 0
+	}
+
+	wait := certcrypto.Validity{NotBefore: notBefore}.StartsIn(now)
+	if wait > certcrypto.DefaultClockSkew {
+		log.Warnf("[%s] The certificate is not valid before %s: the clock of the host is late by more than %s.",
+			meta[hookEnvCertDomain], notBefore.Format(time.RFC3339), certcrypto.DefaultClockSkew)
+
+		return 0
+	}
+
+	return wait
 }
@@ -1,15 +1,25 @@
 package cmd
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"path/filepath"
 	"runtime"
 	"testing"
 	"time"
 
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/challenge/resolver"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func Test_launchHook(t *testing.T) {
-	err := launchHook("echo foo", 1*time.Second, map[string]string{})
+	err := launchHook(t.Context(), "echo foo", 1*time.Second, map[string]string{})
 	require.NoError(t, err)
 }
 
@@ -54,8 +64,159 @@ func Test_launchHook_errors(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			err := launchHook(test.hook, test.timeout, map[string]string{})
+			err := launchHook(t.Context(), test.hook, test.timeout, map[string]string{})
 			require.EqualError(t, err, test.expected)
 		})
 	}
 }
+
+func Test_launchIssuedHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	certPEM, err := certcrypto.GeneratePemCert(privateKey, "example.com", nil)
+	require.NoError(t, err)
+
+	certRes := &certificate.Resource{Certificate: certPEM}
+
+	meta := map[string]string{hookEnvAccountEmail: "test@example.com"}
+
+	err = launchIssuedHook(t.Context(), "./testdata/issued.sh", 5*time.Second, meta, "example.com", certRes)
+	require.NoError(t, err)
+
+	// The metadata of the other hooks are not modified.
+	assert.Equal(t, map[string]string{hookEnvAccountEmail: "test@example.com"}, meta)
+
+	err = launchIssuedHook(t.Context(), "false", 5*time.Second, meta, "example.com", certRes)
+	require.EqualError(t, err, "wait command: exit status 1")
+}
+
+func Test_newValidationHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+
+	assert.Nil(t, newValidationHook("", 5*time.Second))
+
+	hook := newValidationHook("./testdata/validation.sh", 5*time.Second)
+
+	event := resolver.ValidationEvent{
+		Domain:    "example.com",
+		Challenge: acme.Challenge{Type: "dns-01", Token: "token"},
+	}
+
+	err := hook(event)
+	require.NoError(t, err)
+
+	event.Err = errors.New("invalid challenge")
+
+	err = hook(event)
+	require.EqualError(t, err, "wait command: exit status 1")
+}
+
+func Test_addValidityToMetadata(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	certPEM, err := certcrypto.GeneratePemCert(privateKey, "example.com", nil)
+	require.NoError(t, err)
+
+	meta := map[string]string{}
+
+	addValidityToMetadata(meta, &certificate.Resource{Certificate: certPEM})
+
+	notBefore, err := time.Parse(time.RFC3339, meta[hookEnvCertNotBefore])
+	require.NoError(t, err)
+
+	notAfter, err := time.Parse(time.RFC3339, meta[hookEnvCertNotAfter])
+	require.NoError(t, err)
+
+	assert.WithinDuration(t, time.Now(), notBefore, time.Minute)
+	assert.WithinDuration(t, time.Now().AddDate(1, 0, 0), notAfter, time.Minute)
+}
+
+func Test_addValidityToMetadata_invalid(t *testing.T) {
+	meta := map[string]string{}
+
+	addValidityToMetadata(meta, &certificate.Resource{Certificate: []byte("foo")})
+
+	assert.Empty(t, meta)
+}
+
+func Test_addKeyTypeToMetadata(t *testing.T) {
+	certsStorage := &CertificatesStorage{rootPath: "certificates"}
+
+	meta := map[string]string{}
+
+	name := certificateName("example.com", "rsa2048")
+
+	addPathToMetadata(meta, name, &certificate.Resource{}, certsStorage)
+	addKeyTypeToMetadata(meta, "example.com", "rsa2048")
+
+	expected := map[string]string{
+		hookEnvCertDomain:  "example.com",
+		hookEnvCertKeyType: "rsa2048",
+		hookEnvCertPath:    filepath.Join("certificates", "example.com_rsa2048.crt"),
+		hookEnvCertKeyPath: filepath.Join("certificates", "example.com_rsa2048.key"),
+	}
+
+	assert.Equal(t, expected, meta)
+}
+
+func Test_launchHook_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	meta := map[string]string{
+		hookEnvCertDomain:    "example.com",
+		hookEnvCertNotBefore: time.Now().Add(2 * time.Minute).Format(time.RFC3339),
+	}
+
+	err := launchHook(ctx, "echo foo", time.Second, meta)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func Test_hookWaitDuration(t *testing.T) {
+	now := time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc      string
+		notBefore string
+		expected  time.Duration
+	}{
+		{
+			desc:      "no notBefore",
+			notBefore: "",
+		},
+		{
+			desc:      "already valid",
+			notBefore: now.Add(-time.Hour).Format(time.RFC3339),
+		},
+		{
+			desc:      "clock skew",
+			notBefore: now.Add(30 * time.Second).Format(time.RFC3339),
+			expected:  30 * time.Second,
+		},
+		{
+			desc:      "beyond the clock skew",
+			notBefore: now.Add(time.Hour).Format(time.RFC3339),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			meta := map[string]string{
+				hookEnvCertDomain:    "example.com",
+				hookEnvCertNotBefore: test.notBefore,
+			}
+
+			assert.Equal(t, test.expected, hookWaitDuration(meta, now))
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/log"
+	"github.com/urfave/cli/v2"
+)
+
+// hostLookupTimeout the timeout of the lookup of the fully qualified name of the host.
+const hostLookupTimeout = 5 * time.Second
+
+// applyHostCert adds the hostname and the IP addresses of the machine to the domains (--host-cert).
+func applyHostCert(ctx *cli.Context) error {
+	if !ctx.Bool(flgHostCert) {
+		if ctx.Bool(flgHostCertPrivateIPs) || ctx.Bool(flgHostCertNoIP) {
+			return errors.New("--" + flgHostCertPrivateIPs + " and --" + flgHostCertNoIP + " only work with --" + flgHostCert)
+		}
+
+		return nil
+	}
+
+	hostname, err := hostFQDN()
+	if err != nil {
+		return err
+	}
+
+	identifiers := []string{hostname}
+
+	if !ctx.Bool(flgHostCertNoIP) {
+		addrs, err := net.InterfaceAddrs()
+		if err != nil {
+			return err
+		}
+
+		identifiers = append(identifiers, hostIPs(addrs, ctx.Bool(flgHostCertPrivateIPs))...)
+	}
+
+	domains := ctx.StringSlice(flgDomains)
+
+	for _, identifier := range identifiers {
+		if slices.Contains(domains, identifier) {
+			continue
+		}
+
+		err = ctx.Set(flgDomains, identifier)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Host certificate: %s", strings.Join(ctx.StringSlice(flgDomains), ", "))
+
+	return nil
+}
+
+// hostFQDN returns the fully qualified name of the host if it can be resolved, otherwise the hostname.
+func hostFQDN() (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", err
+	}
+
+	hostname = strings.ToLower(hostname)
+
+	if strings.Contains(hostname, ".") {
+		return hostname, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hostLookupTimeout)
+	defer cancel()
+
+	cname, err := net.DefaultResolver.LookupCNAME(ctx, hostname)
+	if err != nil || !strings.Contains(strings.TrimSuffix(cname, "."), ".") {
+		return hostname, nil
+	}
+
+	return strings.ToLower(strings.TrimSuffix(cname, ".")), nil
+}
+
+// hostIPs returns the unicast IP addresses of the interfaces of the machine,
+// without the loopback and link-local addresses, and without the private addresses (RFC 1918, RFC 4193) if includePrivate is false.
+func hostIPs(addrs []net.Addr, includePrivate bool) []string {
+	var ips []string
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+
+		ip := ipNet.IP
+
+		if !ip.IsGlobalUnicast() || (ip.IsPrivate() && !includePrivate) {
+			continue
+		}
+
+		if !slices.Contains(ips, ip.String()) {
+			ips = append(ips, ip.String())
+		}
+	}
+
+	return ips
+}
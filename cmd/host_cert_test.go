@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_hostIPs(t *testing.T) {
+	addrs := []net.Addr{
+		&net.IPNet{IP: net.ParseIP("127.0.0.1"), Mask: net.CIDRMask(8, 32)},
+		&net.IPNet{IP: net.ParseIP("::1"), Mask: net.CIDRMask(128, 128)},
+		&net.IPNet{IP: net.ParseIP("fe80::1"), Mask: net.CIDRMask(64, 128)},
+		&net.IPNet{IP: net.ParseIP("169.254.1.1"), Mask: net.CIDRMask(16, 32)},
+		&net.IPNet{IP: net.ParseIP("192.168.1.10"), Mask: net.CIDRMask(24, 32)},
+		&net.IPNet{IP: net.ParseIP("fd00::10"), Mask: net.CIDRMask(64, 128)},
+		&net.IPNet{IP: net.ParseIP("203.0.113.10"), Mask: net.CIDRMask(24, 32)},
+		&net.IPNet{IP: net.ParseIP("2001:db8::10"), Mask: net.CIDRMask(64, 128)},
+		&net.IPNet{IP: net.ParseIP("203.0.113.10"), Mask: net.CIDRMask(32, 32)},
+		&net.IPAddr{IP: net.ParseIP("198.51.100.10")},
+	}
+
+	testCases := []struct {
+		desc           string
+		includePrivate bool
+		expected       []string
+	}{
+		{
+			desc:     "public",
+			expected: []string{"203.0.113.10", "2001:db8::10"},
+		},
+		{
+			desc:           "private",
+			includePrivate: true,
+			expected:       []string{"192.168.1.10", "fd00::10", "203.0.113.10", "2001:db8::10"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, hostIPs(addrs, test.includePrivate))
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/go-acme/lego/v4/cmd"
 	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/errcode"
 	"github.com/urfave/cli/v2"
 )
 
@@ -35,11 +36,17 @@ func main() {
 	app.Flags = cmd.CreateFlags(defaultPath)
 
 	app.Before = cmd.Before
+	app.After = cmd.After
+
+	// The exit codes are handled after the run: the exit would skip the upload of the data to the storage (After).
+	app.ExitErrHandler = func(_ *cli.Context, _ error) {}
 
 	app.Commands = cmd.CreateCommands()
 
 	err = app.Run(os.Args)
 	if err != nil {
-		log.Fatal(err)
+		cli.HandleExitCoder(err)
+
+		log.Fatalf("%v (%s)", err, errcode.Of(err))
 	}
 }
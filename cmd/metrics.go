@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// challengeDurationBuckets the upper bounds (in seconds) of the buckets of the challenge durations.
+var challengeDurationBuckets = []float64{5, 10, 30, 60, 120, 300, 600, 1800}
+
+// daemonMetrics the metrics of the daemon, exposed with the Prometheus text format (GET /metrics).
+//
+// It's also the structured logger of the clients (see log.StructuredLogger):
+// the challenges record their durations with ObserveSolve (see dns01.SolveObserver), the events are ignored.
+type daemonMetrics struct {
+	mu sync.Mutex
+
+	// by certificate name.
+	attempted map[string]int
+	succeeded map[string]int
+	notAfter  map[string]time.Time
+
+	// by DNS provider.
+	challenges map[string]*histogram
+
+	now func() time.Time
+}
+
+func newDaemonMetrics() *daemonMetrics {
+	return &daemonMetrics{
+		attempted:  make(map[string]int),
+		succeeded:  make(map[string]int),
+		notAfter:   make(map[string]time.Time),
+		challenges: make(map[string]*histogram),
+		now:        time.Now,
+	}
+}
+
+// observeRenewals records the results of a renewal run.
+// A skipped renewal is not an attempt, but it updates the expiration date of the certificate.
+func (m *daemonMetrics) observeRenewals(summaries ...*RenewalSummary) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, summary := range summaries {
+		if summary.Domain == "" {
+			continue
+		}
+
+		if !summary.NotAfter.IsZero() {
+			m.notAfter[summary.Domain] = summary.NotAfter
+		}
+
+		switch summary.Status {
+		case RenewalStatusRenewed:
+			m.attempted[summary.Domain]++
+			m.succeeded[summary.Domain]++
+
+		case RenewalStatusFailed:
+			m.attempted[summary.Domain]++
+		}
+	}
+}
+
+// ObserveSolve records the duration of a challenge (see dns01.SolveObserver).
+func (m *daemonMetrics) ObserveSolve(provider, _ string, duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status := "success"
+	if err != nil {
+		status = "failure"
+	}
+
+	key := provider + "\x00" + status
+
+	h, ok := m.challenges[key]
+	if !ok {
+		h = newHistogram(challengeDurationBuckets)
+		m.challenges[key] = h
+	}
+
+	h.observe(duration.Seconds())
+}
+
+func (m *daemonMetrics) Debug(string, ...any) {}
+
+func (m *daemonMetrics) Info(string, ...any) {}
+
+func (m *daemonMetrics) Warn(string, ...any) {}
+
+func (m *daemonMetrics) Error(string, ...any) {}
+
+func (m *daemonMetrics) ServeHTTP(rw http.ResponseWriter, _ *http.Request) {
+	rw.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	m.write(rw)
+}
+
+// write writes the metrics with the Prometheus text format.
+func (m *daemonMetrics) write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	writeMetricHeader(w, "lego_renewals_attempted_total", "counter", "The number of renewals attempted by certificate.")
+
+	for _, name := range slices.Sorted(maps.Keys(m.attempted)) {
+		_, _ = fmt.Fprintf(w, "lego_renewals_attempted_total{domain=%s} %d\n", quoteLabel(name), m.attempted[name])
+	}
+
+	writeMetricHeader(w, "lego_renewals_succeeded_total", "counter", "The number of successful renewals by certificate.")
+
+	for _, name := range slices.Sorted(maps.Keys(m.attempted)) {
+		_, _ = fmt.Fprintf(w, "lego_renewals_succeeded_total{domain=%s} %d\n", quoteLabel(name), m.succeeded[name])
+	}
+
+	writeMetricHeader(w, "lego_certificate_expiry_days", "gauge", "The number of days before the expiration of the certificate.")
+
+	now := m.now()
+
+	for _, name := range slices.Sorted(maps.Keys(m.notAfter)) {
+		days := m.notAfter[name].Sub(now).Hours() / 24
+
+		_, _ = fmt.Fprintf(w, "lego_certificate_expiry_days{domain=%s} %s\n", quoteLabel(name), formatFloat(days))
+	}
+
+	writeMetricHeader(w, "lego_challenge_duration_seconds", "histogram",
+		"The duration of the DNS-01 challenges (propagation and validation) by DNS provider.")
+
+	for _, key := range slices.Sorted(maps.Keys(m.challenges)) {
+		provider, status, _ := strings.Cut(key, "\x00")
+
+		labels := fmt.Sprintf("provider=%s,status=%s", quoteLabel(provider), quoteLabel(status))
+
+		m.challenges[key].write(w, "lego_challenge_duration_seconds", labels)
+	}
+}
+
+// histogram a Prometheus histogram: the buckets are cumulative.
+type histogram struct {
+	bounds []float64
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{bounds: bounds, counts: make([]uint64, len(bounds))}
+}
+
+func (h *histogram) observe(value float64) {
+	for i, bound := range h.bounds {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+
+	h.count++
+	h.sum += value
+}
+
+func (h *histogram) write(w io.Writer, name, labels string) {
+	for i, bound := range h.bounds {
+		_, _ = fmt.Fprintf(w, "%s_bucket{%s,le=%q} %d\n", name, labels, formatFloat(bound), h.counts[i])
+	}
+
+	_, _ = fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, h.count)
+	_, _ = fmt.Fprintf(w, "%s_sum{%s} %s\n", name, labels, formatFloat(h.sum))
+	_, _ = fmt.Fprintf(w, "%s_count{%s} %d\n", name, labels, h.count)
+}
+
+func writeMetricHeader(w io.Writer, name, typ, help string) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+var labelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// quoteLabel quotes a label value: only the backslash, the double-quote, and the line feed are escaped.
+func quoteLabel(value string) string {
+	return `"` + labelReplacer.Replace(value) + `"`
+}
+
+func formatFloat(value float64) string {
+	return fmt.Sprintf("%g", value)
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_daemonMetrics(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	metrics := newDaemonMetrics()
+	metrics.now = func() time.Time { return now }
+
+	metrics.observeRenewals(
+		&RenewalSummary{Domain: "example.com", Status: RenewalStatusRenewed, NotAfter: now.Add(90 * 24 * time.Hour)},
+		&RenewalSummary{Domain: "example.com_rsa2048", Status: RenewalStatusFailed, NotAfter: now.Add(12 * time.Hour)},
+		&RenewalSummary{Domain: "example.org", Status: RenewalStatusSkipped, NotAfter: now.Add(60 * 24 * time.Hour)},
+	)
+
+	metrics.ObserveSolve("cloudflare", "example.com", 20*time.Second, nil)
+	metrics.ObserveSolve("cloudflare", "example.com", 3*time.Second, errors.New("oops"))
+
+	// The events are not used by the metrics.
+	metrics.Info("dns01: challenge solved", "provider", "cloudflare", "domain", "example.com", "duration", time.Hour)
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	expected := []string{
+		`lego_renewals_attempted_total{domain="example.com"} 1`,
+		`lego_renewals_attempted_total{domain="example.com_rsa2048"} 1`,
+		`lego_renewals_succeeded_total{domain="example.com"} 1`,
+		`lego_renewals_succeeded_total{domain="example.com_rsa2048"} 0`,
+		`lego_certificate_expiry_days{domain="example.com"} 90`,
+		`lego_certificate_expiry_days{domain="example.com_rsa2048"} 0.5`,
+		`lego_certificate_expiry_days{domain="example.org"} 60`,
+		`lego_challenge_duration_seconds_bucket{provider="cloudflare",status="failure",le="5"} 1`,
+		`lego_challenge_duration_seconds_bucket{provider="cloudflare",status="success",le="10"} 0`,
+		`lego_challenge_duration_seconds_bucket{provider="cloudflare",status="success",le="30"} 1`,
+		`lego_challenge_duration_seconds_bucket{provider="cloudflare",status="success",le="+Inf"} 1`,
+		`lego_challenge_duration_seconds_sum{provider="cloudflare",status="success"} 20`,
+		`lego_challenge_duration_seconds_count{provider="cloudflare",status="success"} 1`,
+	}
+
+	lines := strings.Split(rec.Body.String(), "\n")
+
+	for _, line := range expected {
+		assert.Contains(t, lines, line)
+	}
+}
+
+func Test_quoteLabel(t *testing.T) {
+	assert.Equal(t, `"a\\b\"c\nd"`, quoteLabel("a\\b\"c\nd"))
+}
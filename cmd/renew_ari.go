@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/log"
+)
+
+const ariCacheExt = ".ari.json"
+
+const (
+	// defaultARIRetryAfter the polling interval when the CA doesn't send a Retry-After header.
+	defaultARIRetryAfter = 6 * time.Hour
+	// maxARIRetryAfter the longest duration the renewal information is trusted without asking the CA again.
+	maxARIRetryAfter = 24 * time.Hour
+)
+
+// ariCache the last renewal information of a certificate, stored next to the certificate (`<domain>.ari.json`).
+// The renew command doesn't contact the CA until NextPoll, unless the certificate must be renewed.
+type ariCache struct {
+	CertID          string      `json:"certID"`
+	SuggestedWindow acme.Window `json:"suggestedWindow"`
+	ExplanationURL  string      `json:"explanationURL,omitempty"`
+	// NextPoll the date from which the renewalInfo endpoint is requested again (Retry-After).
+	NextPoll time.Time `json:"nextPoll"`
+}
+
+// allowsSkip reports whether the renewal information is still valid, and the suggested window is not started.
+func (c *ariCache) allowsSkip(now time.Time) bool {
+	return now.Before(c.NextPoll) && now.Before(c.SuggestedWindow.Start)
+}
+
+func newARICache(certID string, info *certificate.RenewalInfoResponse, now time.Time) *ariCache {
+	retryAfter := info.RetryAfter
+	if retryAfter <= 0 {
+		retryAfter = defaultARIRetryAfter
+	}
+
+	return &ariCache{
+		CertID:          certID,
+		SuggestedWindow: info.SuggestedWindow,
+		ExplanationURL:  info.ExplanationURL,
+		NextPoll:        now.Add(min(retryAfter, maxARIRetryAfter)).UTC(),
+	}
+}
+
+// writeARICache stores the renewal information of the certificate.
+// The errors are only logged: the cache only avoids requests to the CA.
+func writeARICache(certsStorage *CertificatesStorage, domain string, cert *x509.Certificate, info *certificate.RenewalInfoResponse) {
+	certID, err := certificate.MakeARICertID(cert)
+	if err != nil {
+		return
+	}
+
+	data, err := json.MarshalIndent(newARICache(certID, info, time.Now()), "", "  ")
+	if err != nil {
+		log.Warnf("[%s] Could not encode the renewal information: %v", domain, err)
+		return
+	}
+
+	err = certsStorage.WriteFile(domain, ariCacheExt, data)
+	if err != nil {
+		log.Warnf("[%s] Could not write the renewal information: %v", domain, err)
+	}
+}
+
+// readARICache returns the stored renewal information of the certificate,
+// or nil if there is no information for this certificate (ex: the certificate has been renewed by another tool).
+func readARICache(certsStorage *CertificatesStorage, domain string, cert *x509.Certificate) *ariCache {
+	data, err := certsStorage.ReadFile(domain, ariCacheExt)
+	if err != nil {
+		return nil
+	}
+
+	var cache ariCache
+
+	err = json.Unmarshal(data, &cache)
+	if err != nil {
+		log.Warnf("[%s] Could not read the renewal information: %v", domain, err)
+		return nil
+	}
+
+	certID, err := certificate.MakeARICertID(cert)
+	if err != nil || cache.CertID != certID {
+		return nil
+	}
+
+	return &cache
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"crypto/x509"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_newARICache(t *testing.T) {
+	now := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc       string
+		retryAfter time.Duration
+		expected   time.Time
+	}{
+		{
+			desc:       "Retry-After",
+			retryAfter: 2 * time.Hour,
+			expected:   now.Add(2 * time.Hour),
+		},
+		{
+			desc:     "no Retry-After",
+			expected: now.Add(defaultARIRetryAfter),
+		},
+		{
+			desc:       "long Retry-After",
+			retryAfter: 7 * 24 * time.Hour,
+			expected:   now.Add(maxARIRetryAfter),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cache := newARICache("aki.serial", &certificate.RenewalInfoResponse{RetryAfter: test.retryAfter}, now)
+
+			assert.Equal(t, test.expected, cache.NextPoll)
+		})
+	}
+}
+
+func Test_ariCache_allowsSkip(t *testing.T) {
+	now := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc     string
+		start    time.Time
+		nextPoll time.Time
+		assert   assert.BoolAssertionFunc
+	}{
+		{
+			desc:     "before the window",
+			start:    now.Add(48 * time.Hour),
+			nextPoll: now.Add(time.Hour),
+			assert:   assert.True,
+		},
+		{
+			desc:     "outdated",
+			start:    now.Add(48 * time.Hour),
+			nextPoll: now.Add(-time.Hour),
+			assert:   assert.False,
+		},
+		{
+			desc:     "inside the window",
+			start:    now.Add(-time.Hour),
+			nextPoll: now.Add(time.Hour),
+			assert:   assert.False,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cache := &ariCache{
+				SuggestedWindow: acme.Window{Start: test.start, End: test.start.Add(24 * time.Hour)},
+				NextPoll:        test.nextPoll,
+			}
+
+			test.assert(t, cache.allowsSkip(now))
+		})
+	}
+}
+
+func Test_readARICache(t *testing.T) {
+	certsStorage := &CertificatesStorage{rootPath: t.TempDir()}
+
+	cert := &x509.Certificate{SerialNumber: big.NewInt(42), AuthorityKeyId: []byte{1, 2, 3}}
+
+	assert.Nil(t, readARICache(certsStorage, "example.com", cert))
+
+	start := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+
+	writeARICache(certsStorage, "example.com", cert, &certificate.RenewalInfoResponse{
+		RenewalInfoResponse: acme.RenewalInfoResponse{
+			SuggestedWindow: acme.Window{Start: start, End: start.Add(24 * time.Hour)},
+		},
+		RetryAfter: time.Hour,
+	})
+
+	cache := readARICache(certsStorage, "example.com", cert)
+	require.NotNil(t, cache)
+
+	assert.Equal(t, start, cache.SuggestedWindow.Start)
+	assert.True(t, cache.allowsSkip(time.Now()))
+
+	// Another certificate (ex: renewed by another tool).
+	other := &x509.Certificate{SerialNumber: big.NewInt(43), AuthorityKeyId: []byte{1, 2, 3}}
+
+	assert.Nil(t, readARICache(certsStorage, "example.com", other))
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/challenge/resolver"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// sanFailuresExt the extension of the file keeping the challenge failures of the domains of a certificate (--drop-failing-sans).
+const sanFailuresExt = ".failures.json"
+
+// sanFailure the consecutive challenge failures of a domain.
+type sanFailure struct {
+	// Count the number of consecutive renewals where the challenge of the domain failed.
+	Count int `json:"count"`
+	// FirstFailedAt the date of the first failure of the consecutive failures.
+	FirstFailedAt time.Time `json:"firstFailedAt"`
+}
+
+// sanFailures the consecutive challenge failures of the domains of a certificate (the key is the domain, in lower case).
+type sanFailures map[string]sanFailure
+
+// record returns the failures after a renewal where the challenges of the domains failed:
+// the failures of the other domains are reset.
+func (f sanFailures) record(failed []string, now time.Time) sanFailures {
+	result := make(sanFailures)
+
+	for _, domain := range failed {
+		domain = strings.ToLower(domain)
+
+		failure, ok := f[domain]
+		if !ok {
+			failure = sanFailure{FirstFailedAt: now.UTC()}
+		}
+
+		failure.Count++
+
+		result[domain] = failure
+	}
+
+	return result
+}
+
+// sanDropPolicy defines when a failing domain is dropped from the certificate (--drop-failing-sans).
+type sanDropPolicy struct {
+	// after the number of consecutive failed renewals.
+	after int
+	// window the duration since the first of the consecutive failures (0 to disable).
+	window time.Duration
+}
+
+// droppable reports whether a failing domain can be dropped from the certificate.
+func (p sanDropPolicy) droppable(failure sanFailure, now time.Time) bool {
+	if failure.Count >= p.after {
+		return true
+	}
+
+	return p.window > 0 && now.Sub(failure.FirstFailedAt) >= p.window
+}
+
+// obtainWithoutFailingSANs obtains the certificate without the domains whose challenges failed (--drop-failing-sans),
+// to keep the other domains covered.
+// The failures are kept across the renewals (see sanFailuresExt):
+// a domain is only dropped after consecutive failures (see sanDropPolicy), otherwise the error of the first attempt is returned.
+// The main domain (the name of the certificate) is never dropped:
+// the error of the first attempt is returned if the main domain failed, or if the error is not related to the challenges.
+// It returns the dropped domains.
+func obtainWithoutFailingSANs(obtain func(certificate.ObtainRequest) (*certificate.Resource, error), request certificate.ObtainRequest, domain string, obtainErr error,
+	certsStorage *CertificatesStorage, name string, policy sanDropPolicy,
+) (*certificate.Resource, []string, error) {
+	previous, err := readSANFailures(certsStorage, name)
+	if err != nil {
+		return nil, nil, errors.Join(obtainErr, err)
+	}
+
+	failed := resolver.FailedDomains(obtainErr)
+	if len(failed) == 0 {
+		// The error is not related to the challenges: the failures are unchanged.
+		return nil, nil, obtainErr
+	}
+
+	failures := previous.record(failed, time.Now())
+
+	err = writeSANFailures(certsStorage, name, failures)
+	if err != nil {
+		return nil, nil, errors.Join(obtainErr, err)
+	}
+
+	for _, d := range failed {
+		failure := failures[strings.ToLower(d)]
+
+		if !policy.droppable(failure, time.Now()) {
+			log.Warnf("[%s] The challenge of %s failed (%d consecutive failures since %s), the domain is not dropped yet.",
+				domain, d, failure.Count, failure.FirstFailedAt.Format(time.RFC3339))
+
+			return nil, nil, obtainErr
+		}
+	}
+
+	return obtainWithout(obtain, request, domain, failed, obtainErr)
+}
+
+// resetSANFailures resets the failures of the domains of the certificate after a renewal without challenge failures.
+func resetSANFailures(certsStorage *CertificatesStorage, name string) error {
+	failures, err := readSANFailures(certsStorage, name)
+	if err != nil || len(failures) == 0 {
+		return err
+	}
+
+	return writeSANFailures(certsStorage, name, sanFailures{})
+}
+
+func obtainWithout(obtain func(certificate.ObtainRequest) (*certificate.Resource, error), request certificate.ObtainRequest, domain string, failed []string, obtainErr error) (*certificate.Resource, []string, error) {
+	if len(failed) == 0 {
+		return nil, nil, obtainErr
+	}
+
+	isFailed := func(d string) bool {
+		return slices.ContainsFunc(failed, func(f string) bool { return strings.EqualFold(f, d) })
+	}
+
+	if isFailed(domain) {
+		return nil, nil, obtainErr
+	}
+
+	request.Domains = slices.DeleteFunc(slices.Clone(request.Domains), isFailed)
+
+	log.Warnf("[%s] The challenges of %s failed, renewal without these domains: %v", domain, strings.Join(failed, ", "), obtainErr)
+
+	certRes, err := obtain(request)
+	if err != nil {
+		return nil, nil, fmt.Errorf("renewal without the failing domains (%s): %w", strings.Join(failed, ", "), err)
+	}
+
+	return certRes, failed, nil
+}
+
+func readSANFailures(certsStorage *CertificatesStorage, name string) (sanFailures, error) {
+	data, err := certsStorage.ReadFile(name, sanFailuresExt)
+	if errors.Is(err, fs.ErrNotExist) {
+		return sanFailures{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read the failures of the domains: %w", err)
+	}
+
+	failures := sanFailures{}
+
+	err = json.Unmarshal(data, &failures)
+	if err != nil {
+		return nil, fmt.Errorf("read the failures of the domains: %w", err)
+	}
+
+	return failures, nil
+}
+
+func writeSANFailures(certsStorage *CertificatesStorage, name string, failures sanFailures) error {
+	data, err := json.MarshalIndent(failures, "", "  ")
+	if err != nil {
+		return fmt.Errorf("write the failures of the domains: %w", err)
+	}
+
+	err = certsStorage.WriteFile(name, sanFailuresExt, data)
+	if err != nil {
+		return fmt.Errorf("write the failures of the domains: %w", err)
+	}
+
+	return nil
+}
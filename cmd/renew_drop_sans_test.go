@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_obtainWithout(t *testing.T) {
+	obtainErr := errors.New("error: one or more domains had a problem")
+
+	testCases := []struct {
+		desc            string
+		domains         []string
+		failed          []string
+		obtainErr       error
+		expectedDomains []string
+		expectedDropped []string
+		expectedError   string
+	}{
+		{
+			desc:            "failing SAN",
+			failed:          []string{"b.example.com"},
+			expectedDomains: []string{"example.com", "a.example.com", "c.example.com"},
+			expectedDropped: []string{"b.example.com"},
+		},
+		{
+			desc:            "failing SANs (case-insensitive)",
+			domains:         []string{"example.com", "A.example.com", "B.example.com", "c.example.com"},
+			failed:          []string{"a.example.com", "c.example.com"},
+			expectedDomains: []string{"example.com", "B.example.com"},
+			expectedDropped: []string{"a.example.com", "c.example.com"},
+		},
+		{
+			desc:          "failing main domain",
+			failed:        []string{"example.com", "b.example.com"},
+			expectedError: "error: one or more domains had a problem",
+		},
+		{
+			desc:          "not a challenge error",
+			expectedError: "error: one or more domains had a problem",
+		},
+		{
+			desc:          "second attempt failed",
+			failed:        []string{"b.example.com"},
+			obtainErr:     errors.New("rate limited"),
+			expectedError: "renewal without the failing domains (b.example.com): rate limited",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			domains := test.domains
+			if domains == nil {
+				domains = []string{"example.com", "a.example.com", "b.example.com", "c.example.com"}
+			}
+
+			request := certificate.ObtainRequest{Domains: domains}
+
+			var obtained []string
+
+			obtain := func(r certificate.ObtainRequest) (*certificate.Resource, error) {
+				obtained = r.Domains
+
+				if test.obtainErr != nil {
+					return nil, test.obtainErr
+				}
+
+				return &certificate.Resource{Domain: r.Domains[0]}, nil
+			}
+
+			certRes, dropped, err := obtainWithout(obtain, request, "example.com", test.failed, obtainErr)
+
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, certRes)
+
+			assert.Equal(t, test.expectedDomains, obtained)
+			assert.Equal(t, test.expectedDropped, dropped)
+
+			// The request of the first attempt is not modified.
+			assert.Len(t, domains, 4)
+		})
+	}
+}
+
+func Test_sanFailures_record(t *testing.T) {
+	first := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := first.Add(24 * time.Hour)
+
+	previous := sanFailures{
+		"a.example.com": {Count: 2, FirstFailedAt: first},
+		"b.example.com": {Count: 1, FirstFailedAt: first},
+	}
+
+	failures := previous.record([]string{"A.example.com", "c.example.com"}, now)
+
+	expected := sanFailures{
+		"a.example.com": {Count: 3, FirstFailedAt: first},
+		"c.example.com": {Count: 1, FirstFailedAt: now},
+	}
+
+	assert.Equal(t, expected, failures)
+}
+
+func Test_sanDropPolicy_droppable(t *testing.T) {
+	now := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc     string
+		policy   sanDropPolicy
+		failure  sanFailure
+		expected bool
+	}{
+		{
+			desc:    "first failure",
+			policy:  sanDropPolicy{after: 3},
+			failure: sanFailure{Count: 1, FirstFailedAt: now},
+		},
+		{
+			desc:     "consecutive failures",
+			policy:   sanDropPolicy{after: 3},
+			failure:  sanFailure{Count: 3, FirstFailedAt: now},
+			expected: true,
+		},
+		{
+			desc:     "immediate",
+			policy:   sanDropPolicy{after: 1},
+			failure:  sanFailure{Count: 1, FirstFailedAt: now},
+			expected: true,
+		},
+		{
+			desc:    "within the window",
+			policy:  sanDropPolicy{after: 3, window: 72 * time.Hour},
+			failure: sanFailure{Count: 2, FirstFailedAt: now.Add(-48 * time.Hour)},
+		},
+		{
+			desc:     "after the window",
+			policy:   sanDropPolicy{after: 3, window: 72 * time.Hour},
+			failure:  sanFailure{Count: 2, FirstFailedAt: now.Add(-96 * time.Hour)},
+			expected: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, test.policy.droppable(test.failure, now))
+		})
+	}
+}
+
+func Test_sanFailures_storage(t *testing.T) {
+	certsStorage := &CertificatesStorage{rootPath: t.TempDir()}
+
+	failures, err := readSANFailures(certsStorage, "example.com")
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+
+	expected := sanFailures{"a.example.com": {Count: 1, FirstFailedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}}
+
+	err = writeSANFailures(certsStorage, "example.com", expected)
+	require.NoError(t, err)
+
+	failures, err = readSANFailures(certsStorage, "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, expected, failures)
+
+	err = resetSANFailures(certsStorage, "example.com")
+	require.NoError(t, err)
+
+	failures, err = readSANFailures(certsStorage, "example.com")
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+}
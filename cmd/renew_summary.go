@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/errcode"
+	"github.com/urfave/cli/v2"
+)
+
+const renewalSummaryExt = ".renewal.json"
+
+// Renewal statuses.
+const (
+	RenewalStatusRenewed = "renewed"
+	RenewalStatusSkipped = "skipped"
+	RenewalStatusFailed  = "failed"
+)
+
+// RenewalSummary the machine-readable result of a `renew` run.
+type RenewalSummary struct {
+	Domain  string   `json:"domain"`
+	Domains []string `json:"domains,omitempty"`
+	// DroppedDomains the domains dropped from the certificate because their challenges failed (--drop-failing-sans).
+	DroppedDomains []string `json:"droppedDomains,omitempty"`
+	Status         string   `json:"status"`
+	Error          string   `json:"error,omitempty"`
+	// ErrorCode the stable code of the error (ex: LEGO_E_DNS_PROPAGATION_TIMEOUT).
+	ErrorCode string `json:"errorCode,omitempty"`
+
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	Duration   string    `json:"duration"`
+
+	// NotAfter the expiration date of the current certificate.
+	NotAfter time.Time `json:"notAfter,omitzero"`
+	// NextAttempt the date from which the certificate will be renewed by the renewal policy.
+	NextAttempt time.Time `json:"nextAttempt,omitzero"`
+}
+
+func newRenewalSummary() *RenewalSummary {
+	return &RenewalSummary{StartedAt: time.Now().UTC()}
+}
+
+func (s *RenewalSummary) skipped(cert *x509.Certificate, policy renewalPolicy) {
+	s.Status = RenewalStatusSkipped
+	s.NotAfter = cert.NotAfter.UTC()
+	s.NextAttempt = renewalDueDate(cert, policy).UTC()
+}
+
+func (s *RenewalSummary) renewed(certRes *certificate.Resource, policy renewalPolicy) {
+	s.Status = RenewalStatusRenewed
+
+	cert, err := certcrypto.ParsePEMCertificate(certRes.Certificate)
+	if err != nil {
+		return
+	}
+
+	s.Domains = certcrypto.ExtractDomains(cert)
+	s.NotAfter = cert.NotAfter.UTC()
+	s.NextAttempt = renewalDueDate(cert, policy).UTC()
+}
+
+// finish records the end of the run.
+// A certificate can be renewed even if the run fails (ex: the renew hook fails).
+func (s *RenewalSummary) finish(err error) {
+	s.FinishedAt = time.Now().UTC()
+	s.Duration = s.FinishedAt.Sub(s.StartedAt).Round(time.Millisecond).String()
+
+	if err == nil {
+		return
+	}
+
+	s.Error = err.Error()
+	s.ErrorCode = errcode.Of(err).String()
+
+	if s.Status == "" {
+		s.Status = RenewalStatusFailed
+		s.NextAttempt = time.Time{}
+	}
+}
+
+// reportRenewal writes the renewal summary next to the certificate (`<domain>.renewal.json`),
+// and sends it to the URL defined by the flag.
+// The errors are only logged: they don't change the result of the renewal.
+func reportRenewal(ctx *cli.Context, certsStorage *CertificatesStorage, summary *RenewalSummary) {
+	if summary.Domain == "" {
+		return
+	}
+
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		log.Warnf("[%s] Could not encode the renewal summary: %v", summary.Domain, err)
+		return
+	}
+
+	err = certsStorage.WriteFile(summary.Domain, renewalSummaryExt, data)
+	if err != nil {
+		log.Warnf("[%s] Could not write the renewal summary: %v", summary.Domain, err)
+	}
+
+	endpoint := ctx.String(flgRenewalSummaryURL)
+	if endpoint == "" {
+		return
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	err = postRenewalSummary(ctx.Context, client, endpoint, data)
+	if err != nil {
+		log.Warnf("[%s] Could not send the renewal summary: %v", summary.Domain, err)
+	}
+}
+
+func postRenewalSummary(ctx context.Context, client *http.Client, endpoint string, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return fmt.Errorf("unexpected status code: [status code: %d] body: %s", resp.StatusCode, string(raw))
+	}
+
+	return nil
+}
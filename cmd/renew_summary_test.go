@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/platform/errcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestRenewalSummary_skipped(t *testing.T) {
+	notAfter := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	summary := newRenewalSummary()
+	summary.skipped(&x509.Certificate{NotAfter: notAfter}, renewalPolicy{window: 24 * time.Hour})
+	summary.finish(nil)
+
+	assert.Equal(t, RenewalStatusSkipped, summary.Status)
+	assert.Equal(t, notAfter, summary.NotAfter)
+	assert.Equal(t, notAfter.Add(-24*time.Hour), summary.NextAttempt)
+	assert.Empty(t, summary.Error)
+	assert.False(t, summary.FinishedAt.Before(summary.StartedAt))
+	assert.NotEmpty(t, summary.Duration)
+}
+
+func TestRenewalSummary_renewed(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	certPEM, err := certcrypto.GeneratePemCert(privateKey, "example.com", nil)
+	require.NoError(t, err)
+
+	summary := newRenewalSummary()
+	summary.renewed(&certificate.Resource{Certificate: certPEM}, renewalPolicy{days: 30})
+
+	// The certificate is renewed, but the hook fails.
+	summary.finish(errors.New("hook failed"))
+
+	assert.Equal(t, RenewalStatusRenewed, summary.Status)
+	assert.Equal(t, "hook failed", summary.Error)
+	assert.Contains(t, summary.Domains, "example.com")
+	assert.False(t, summary.NotAfter.IsZero())
+	assert.Equal(t, summary.NotAfter.Add(-31*24*time.Hour), summary.NextAttempt)
+}
+
+func TestRenewalSummary_failed(t *testing.T) {
+	summary := newRenewalSummary()
+	summary.finish(errors.New("acme: error: 403"))
+
+	assert.Equal(t, RenewalStatusFailed, summary.Status)
+	assert.Equal(t, "acme: error: 403", summary.Error)
+	assert.Equal(t, "LEGO_E_UNKNOWN", summary.ErrorCode)
+	assert.True(t, summary.NextAttempt.IsZero())
+}
+
+func TestRenewalSummary_failed_code(t *testing.T) {
+	summary := newRenewalSummary()
+	summary.finish(fmt.Errorf("error: one or more domains had a problem:\n%w",
+		errcode.Wrap(errcode.DNSPropagationTimeout, errors.New("propagation: time limit exceeded"))))
+
+	assert.Equal(t, RenewalStatusFailed, summary.Status)
+	assert.Equal(t, "LEGO_E_DNS_PROPAGATION_TIMEOUT", summary.ErrorCode)
+}
+
+func Test_reportRenewal(t *testing.T) {
+	received := make(chan RenewalSummary, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/json" {
+			http.Error(rw, "invalid request", http.StatusBadRequest)
+			return
+		}
+
+		var summary RenewalSummary
+
+		_ = json.NewDecoder(req.Body).Decode(&summary)
+
+		received <- summary
+	}))
+	t.Cleanup(server.Close)
+
+	certsStorage := &CertificatesStorage{rootPath: t.TempDir()}
+
+	summary := newRenewalSummary()
+	summary.Domain = "*.example.com"
+	summary.finish(errors.New("boom"))
+
+	app := &cli.App{
+		Name:   "lego",
+		Writer: io.Discard,
+		Flags:  []cli.Flag{&cli.StringFlag{Name: flgRenewalSummaryURL}},
+		Action: func(ctx *cli.Context) error {
+			reportRenewal(ctx, certsStorage, summary)
+			return nil
+		},
+	}
+
+	err := app.Run([]string{"lego", "--" + flgRenewalSummaryURL, server.URL})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(certsStorage.GetRootPath(), "_.example.com"+renewalSummaryExt))
+	require.NoError(t, err)
+
+	var written RenewalSummary
+
+	err = json.Unmarshal(data, &written)
+	require.NoError(t, err)
+
+	assert.Equal(t, RenewalStatusFailed, written.Status)
+	assert.Equal(t, "boom", written.Error)
+
+	select {
+	case sent := <-received:
+		assert.Equal(t, written, sent)
+	default:
+		t.Fatal("the summary has not been sent")
+	}
+}
+
+func Test_postRenewalSummary_error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		http.Error(rw, "unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	err := postRenewalSummary(t.Context(), server.Client(), server.URL, []byte(`{}`))
+	require.EqualError(t, err, "unexpected status code: [status code: 503] body: unavailable\n")
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/lego"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/urfave/cli/v2"
+)
+
+// setupRenewalClient creates the client of a renewal,
+// and warns when the renewal policy is incompatible with the maximum validity of the certificates of the CA.
+// The client is watched by shutdown.
+func setupRenewalClient(ctx *cli.Context, account *Account, keyType certcrypto.KeyType, shutdown *shutdownWatcher) *lego.Client {
+	client := setupClient(ctx, account, keyType)
+
+	shutdown.watch(client)
+
+	maxValidity := client.GetCAQuirks().GetMaxValidity(ctx.String(flgProfile))
+
+	if warning := renewalPolicyWarning(mustRenewalPolicy(ctx), maxValidity); warning != "" {
+		log.Warnf("%s", warning)
+	}
+
+	return client
+}
+
+// renewalPolicyWarning returns an actionable warning when the renewal window of the policy is incompatible
+// with the maximum validity of the certificates, or an empty string.
+// The policies relative to the lifetime of the certificates (--renew-percent, --dynamic) are always compatible.
+func renewalPolicyWarning(policy renewalPolicy, maxValidity time.Duration) string {
+	if maxValidity <= 0 {
+		return ""
+	}
+
+	var (
+		window time.Duration
+		option string
+	)
+
+	// The same precedence as needRenewal.
+	switch {
+	case policy.percent > 0:
+		return ""
+
+	case policy.window > 0:
+		window, option = policy.window, flgRenewWindow
+
+	case policy.dynamic, policy.days < 0:
+		return ""
+
+	default:
+		window, option = time.Duration(policy.days)*24*time.Hour, flgRenewDays
+	}
+
+	advice := fmt.Sprintf("use '--%s %s' or '--%s 33' to renew the certificates when a third of their lifetime is left",
+		flgRenewWindow, formatValidity(maxValidity/3), flgRenewPercent)
+
+	switch {
+	case window >= maxValidity:
+		return fmt.Sprintf("The renewal window (--%s: %s) is not shorter than the maximum lifetime of the certificates of the CA (%s):"+
+			" the certificates are renewed at each run, %s.",
+			option, formatValidity(window), formatValidity(maxValidity), advice)
+
+	case maxValidity-window < maxValidity/2:
+		return fmt.Sprintf("The renewal window (--%s: %s) leaves a margin of %s with the maximum lifetime of the certificates of the CA (%s):"+
+			" the certificates are renewed %s after their issuance, %s.",
+			option, formatValidity(window), formatValidity(maxValidity-window), formatValidity(maxValidity), formatValidity(maxValidity-window), advice)
+
+	case window < maxValidity/10:
+		return fmt.Sprintf("The renewal window (--%s: %s) is less than 10%% of the maximum lifetime of the certificates of the CA (%s):"+
+			" a failed renewal has little time to be retried before the expiration, %s.",
+			option, formatValidity(window), formatValidity(maxValidity), advice)
+	}
+
+	return ""
+}
+
+// formatValidity formats a duration in days (ex: 30d), or in hours if it's not a number of days (ex: 53h).
+func formatValidity(d time.Duration) string {
+	d = d.Round(time.Hour)
+
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+
+	return fmt.Sprintf("%dh", d/time.Hour)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_renewalPolicyWarning(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		policy      renewalPolicy
+		maxValidity time.Duration
+		expected    string
+	}{
+		{
+			desc:   "unknown maximum validity",
+			policy: renewalPolicy{days: 30},
+		},
+		{
+			desc:        "compatible days",
+			policy:      renewalPolicy{days: 30},
+			maxValidity: 90 * 24 * time.Hour,
+		},
+		{
+			desc:        "percent",
+			policy:      renewalPolicy{days: 30, percent: 33},
+			maxValidity: 160 * time.Hour,
+		},
+		{
+			desc:        "dynamic",
+			policy:      renewalPolicy{days: 30, dynamic: true},
+			maxValidity: 160 * time.Hour,
+		},
+		{
+			desc:        "renewal at each run",
+			policy:      renewalPolicy{days: -1},
+			maxValidity: 160 * time.Hour,
+		},
+		{
+			desc:        "days longer than the lifetime",
+			policy:      renewalPolicy{days: 30},
+			maxValidity: 160 * time.Hour,
+			expected: "The renewal window (--days: 30d) is not shorter than the maximum lifetime of the certificates of the CA (160h):" +
+				" the certificates are renewed at each run, use '--renew-window 53h' or '--renew-percent 33' to renew the certificates when a third of their lifetime is left.",
+		},
+		{
+			desc:        "small margin",
+			policy:      renewalPolicy{days: 30, window: 30 * 24 * time.Hour},
+			maxValidity: 45 * 24 * time.Hour,
+			expected: "The renewal window (--renew-window: 30d) leaves a margin of 15d with the maximum lifetime of the certificates of the CA (45d):" +
+				" the certificates are renewed 15d after their issuance, use '--renew-window 15d' or '--renew-percent 33' to renew the certificates when a third of their lifetime is left.",
+		},
+		{
+			desc:        "window too short",
+			policy:      renewalPolicy{days: 2},
+			maxValidity: 90 * 24 * time.Hour,
+			expected: "The renewal window (--days: 2d) is less than 10% of the maximum lifetime of the certificates of the CA (90d):" +
+				" a failed renewal has little time to be retried before the expiration, use '--renew-window 30d' or '--renew-percent 33' to renew the certificates when a third of their lifetime is left.",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, renewalPolicyWarning(test.policy, test.maxValidity))
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"crypto/x509"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/go-acme/lego/v4/lego"
+	"github.com/urfave/cli/v2"
+)
+
+// Server presets.
+const (
+	serverPresetLetsEncrypt        = "letsencrypt"
+	serverPresetLetsEncryptStaging = "letsencrypt-staging"
+	serverPresetZeroSSL            = "zerossl"
+)
+
+// serverPresets the directory URLs of the server presets.
+var serverPresets = map[string]string{
+	serverPresetLetsEncrypt:        lego.LEDirectoryProduction,
+	serverPresetLetsEncryptStaging: lego.LEDirectoryStaging,
+	serverPresetZeroSSL:            lego.ZeroSSLDirectory,
+}
+
+// stagingHosts the hosts of the staging environments of the CAs without "staging" in their name.
+var stagingHosts = []string{"api.test4.buypass.no"}
+
+func serverPresetNames() []string {
+	return slices.Sorted(maps.Keys(serverPresets))
+}
+
+// applyServerPreset replaces the server by the directory URL of the preset.
+func applyServerPreset(ctx *cli.Context) error {
+	preset := ctx.String(flgServerPreset)
+	if preset == "" {
+		return nil
+	}
+
+	dirURL, ok := serverPresets[preset]
+	if !ok {
+		return fmt.Errorf("unknown preset %q (supported: %s)", preset, strings.Join(serverPresetNames(), ", "))
+	}
+
+	if ctx.IsSet(flgServer) && ctx.String(flgServer) != dirURL {
+		return fmt.Errorf("--%s and --%s are mutually exclusive", flgServerPreset, flgServer)
+	}
+
+	return ctx.Set(flgServer, dirURL)
+}
+
+// isStagingServer reports whether the directory URL is the staging environment of a CA
+// (ex: https://acme-staging-v02.api.letsencrypt.org/directory).
+func isStagingServer(serverURL string) bool {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+
+	return strings.Contains(host, "staging") || slices.Contains(stagingHosts, host)
+}
+
+// isStagingCertificate reports whether the certificate is issued by the staging environment of a CA
+// (ex: "(STAGING) Let's Encrypt", "Fake LE Intermediate X1").
+func isStagingCertificate(cert *x509.Certificate) bool {
+	for _, name := range append([]string{cert.Issuer.CommonName}, cert.Issuer.Organization...) {
+		name = strings.ToUpper(name)
+
+		if strings.Contains(name, "STAGING") || strings.Contains(name, "FAKE LE") {
+			return true
+		}
+	}
+
+	return false
+}
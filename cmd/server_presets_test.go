@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/lego"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func generateIssuedCertificate(t *testing.T, issuer string) []byte {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: issuer},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		DNSNames:     []string{"example.com"},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func Test_applyServerPreset(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		args       []string
+		expected   string
+		requireErr require.ErrorAssertionFunc
+	}{
+		{
+			desc:       "no preset",
+			args:       []string{"--" + flgServer, "https://example.com/dir"},
+			expected:   "https://example.com/dir",
+			requireErr: require.NoError,
+		},
+		{
+			desc:       "staging",
+			args:       []string{"--" + flgServerPreset, serverPresetLetsEncryptStaging},
+			expected:   lego.LEDirectoryStaging,
+			requireErr: require.NoError,
+		},
+		{
+			desc:       "zerossl",
+			args:       []string{"--" + flgServerPreset, serverPresetZeroSSL},
+			expected:   lego.ZeroSSLDirectory,
+			requireErr: require.NoError,
+		},
+		{
+			desc:       "unknown preset",
+			args:       []string{"--" + flgServerPreset, "foo"},
+			requireErr: require.Error,
+		},
+		{
+			desc:       "preset and server",
+			args:       []string{"--" + flgServerPreset, serverPresetLetsEncrypt, "--" + flgServer, "https://example.com/dir"},
+			requireErr: require.Error,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			for _, name := range []string{envServer, envServerPreset} {
+				t.Setenv(name, "")
+				require.NoError(t, os.Unsetenv(name))
+			}
+
+			var server string
+
+			app := &cli.App{
+				Name:  "lego",
+				Flags: CreateFlags(t.TempDir()),
+				Action: func(ctx *cli.Context) error {
+					err := applyServerPreset(ctx)
+					if err != nil {
+						return err
+					}
+
+					server = ctx.String(flgServer)
+
+					return nil
+				},
+			}
+
+			err := app.Run(append([]string{"lego"}, test.args...))
+			test.requireErr(t, err)
+
+			assert.Equal(t, test.expected, server)
+		})
+	}
+}
+
+func TestCertificatesStorage_checkStaging(t *testing.T) {
+	production := generateIssuedCertificate(t, "R10")
+	staging := generateIssuedCertificate(t, "(STAGING) Counterfeit Cashew R10")
+
+	storage := &CertificatesStorage{rootPath: t.TempDir()}
+
+	// No current certificate.
+	require.NoError(t, storage.checkStaging("example.com", lego.LEDirectoryStaging))
+
+	err := storage.WriteFile("example.com", certExt, production)
+	require.NoError(t, err)
+
+	require.NoError(t, storage.checkStaging("example.com", lego.LEDirectoryProduction))
+
+	err = storage.checkStaging("example.com", lego.LEDirectoryStaging)
+	require.ErrorContains(t, err, "refusing to replace the production certificate")
+
+	storage.allowStaging = true
+
+	require.NoError(t, storage.checkStaging("example.com", lego.LEDirectoryStaging))
+
+	// The current certificate is a staging certificate.
+	storage.allowStaging = false
+
+	err = storage.WriteFile("example.com", certExt, staging)
+	require.NoError(t, err)
+
+	require.NoError(t, storage.checkStaging("example.com", lego.LEDirectoryStaging))
+}
+
+func Test_isStagingServer(t *testing.T) {
+	testCases := []struct {
+		serverURL string
+		expected  bool
+	}{
+		{serverURL: lego.LEDirectoryStaging, expected: true},
+		{serverURL: "https://api.test4.buypass.no/acme/directory", expected: true},
+		{serverURL: lego.LEDirectoryProduction},
+		{serverURL: lego.ZeroSSLDirectory},
+		{serverURL: "https://localhost:14000/dir"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.serverURL, func(t *testing.T) {
+			assert.Equal(t, test.expected, isStagingServer(test.serverURL))
+		})
+	}
+}
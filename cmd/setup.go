@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"crypto"
 	"crypto/x509"
 	"encoding/json"
 	"encoding/pem"
@@ -9,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -16,12 +18,14 @@ import (
 	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/go-acme/lego/v4/lego"
 	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/providers/signer/vault"
 	"github.com/go-acme/lego/v4/registration"
+	"github.com/go-acme/lego/v4/storage"
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/urfave/cli/v2"
 )
 
-const filePerm os.FileMode = 0o600
+const filePerm = storage.FilePerm
 
 // setupClient creates a new client with challenge settings.
 func setupClient(ctx *cli.Context, account *Account, keyType certcrypto.KeyType) *lego.Client {
@@ -29,23 +33,50 @@ func setupClient(ctx *cli.Context, account *Account, keyType certcrypto.KeyType)
 
 	setupChallenges(ctx, client)
 
+	if profile := ctx.String(flgProfile); profile != "" && len(client.GetProfiles()) == 0 {
+		log.Warnf("The CA doesn't advertise any certificate profile: the profile %q may be rejected.", profile)
+	}
+
 	return client
 }
 
 func setupAccount(ctx *cli.Context, accountsStorage *AccountsStorage) (*Account, certcrypto.KeyType) {
 	keyType := getKeyType(ctx)
-	privateKey := accountsStorage.GetPrivateKey(keyType)
+
+	var privateKey crypto.PrivateKey
+	if ctx.IsSet(flgAccountKeySigner) {
+		privateKey = newAccountKeySigner(ctx.String(flgAccountKeySigner))
+	} else {
+		privateKey = accountsStorage.GetPrivateKey(keyType)
+	}
 
 	var account *Account
 	if accountsStorage.ExistsAccountFilePath() {
 		account = accountsStorage.LoadAccount(privateKey)
 	} else {
-		account = &Account{Email: accountsStorage.GetEmail(), key: privateKey}
+		account = &Account{Email: accountsStorage.GetEmail(), Key: privateKey}
 	}
 
 	return account, keyType
 }
 
+// newAccountKeySigner creates the signer of an account key stored outside lego: the private key is never written on disk.
+func newAccountKeySigner(name string) crypto.Signer {
+	switch strings.ToLower(name) {
+	case "vault":
+		signer, err := vault.NewSigner()
+		if err != nil {
+			log.Fatalf("Could not create the account key signer: %v", err)
+		}
+
+		return signer
+	}
+
+	log.Fatalf("Unsupported account key signer: %s", name)
+
+	return nil
+}
+
 func newClient(ctx *cli.Context, acc registration.User, keyType certcrypto.KeyType) *lego.Client {
 	config := lego.NewConfig(acc)
 	config.CADirURL = ctx.String(flgServer)
@@ -55,8 +86,15 @@ func newClient(ctx *cli.Context, acc registration.User, keyType certcrypto.KeyTy
 		Timeout:             time.Duration(ctx.Int(flgCertTimeout)) * time.Second,
 		OverallRequestLimit: ctx.Int(flgOverallRequestLimit),
 		DisableCommonName:   ctx.Bool(flgDisableCommonName),
+		DownloadRetries:     ctx.Int(flgCertDownloadRetries),
 	}
 	config.UserAgent = getUserAgent(ctx)
+	config.StrictMode = ctx.Bool(flgStrict)
+
+	// The structured logger defined by a command (ex: the metrics of the daemon).
+	if logger, ok := log.FromContext(ctx.Context); ok {
+		config.Logger = logger
+	}
 
 	if ctx.IsSet(flgHTTPTimeout) {
 		config.HTTPClient.Timeout = time.Duration(ctx.Int(flgHTTPTimeout)) * time.Second
@@ -71,6 +109,14 @@ func newClient(ctx *cli.Context, acc registration.User, keyType certcrypto.KeyTy
 		}
 	}
 
+	if ctx.IsSet(flgHTTPMaxIdleConns) || ctx.IsSet(flgHTTPMaxIdleConnsPerHost) || ctx.IsSet(flgHTTPIdleConnTimeout) ||
+		ctx.IsSet(flgHTTP2) || ctx.IsSet(flgTLSSessionCache) {
+		err := lego.ConfigureTransport(config.HTTPClient, getTransportConfig(ctx))
+		if err != nil {
+			log.Fatalf("Could not configure the HTTP client: %v", err)
+		}
+	}
+
 	retryClient := retryablehttp.NewClient()
 	retryClient.RetryMax = 5
 	retryClient.HTTPClient = config.HTTPClient
@@ -97,25 +143,89 @@ func newClient(ctx *cli.Context, acc registration.User, keyType certcrypto.KeyTy
 
 // getKeyType the type from which private keys should be generated.
 func getKeyType(ctx *cli.Context) certcrypto.KeyType {
-	keyType := ctx.String(flgKeyType)
+	return parseKeyType(ctx.String(flgKeyType))
+}
+
+// getAdditionalKeyTypes the key types of the additional certificates obtained for the same domains.
+// The values are normalized (lower case) because they are used to name the files of the certificates.
+func getAdditionalKeyTypes(ctx *cli.Context) []string {
+	mainKeyType := getKeyType(ctx)
+
+	var names []string
+
+	for _, value := range ctx.StringSlice(flgAdditionalKeyType) {
+		name := strings.ToLower(value)
+
+		if parseKeyType(name) == mainKeyType {
+			log.Fatalf("The additional key type %s is the key type of the main certificate (--%s).", value, flgKeyType)
+		}
+
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// keepAuthorizations reports whether the authorizations must be kept after the issuance of a certificate,
+// even with --always-deactivate-authorizations: the following certificates of the run reuse them (if the CA allows it).
+func keepAuthorizations(ctx *cli.Context, additionalKeyType string) bool {
+	names := getAdditionalKeyTypes(ctx)
+	if len(names) == 0 {
+		return false
+	}
+
+	return additionalKeyType != names[len(names)-1]
+}
+
+func parseKeyType(keyType string) certcrypto.KeyType {
+	kt, err := toKeyType(keyType)
+	if err != nil {
+		log.Fatalf("Unsupported KeyType: %s", keyType)
+	}
+
+	return kt
+}
+
+// toKeyType converts the name of a key type (ex: rsa2048, EC256) to a certcrypto.KeyType.
+func toKeyType(keyType string) (certcrypto.KeyType, error) {
 	switch strings.ToUpper(keyType) {
 	case "RSA2048":
-		return certcrypto.RSA2048
+		return certcrypto.RSA2048, nil
 	case "RSA3072":
-		return certcrypto.RSA3072
+		return certcrypto.RSA3072, nil
 	case "RSA4096":
-		return certcrypto.RSA4096
+		return certcrypto.RSA4096, nil
 	case "RSA8192":
-		return certcrypto.RSA8192
+		return certcrypto.RSA8192, nil
 	case "EC256":
-		return certcrypto.EC256
+		return certcrypto.EC256, nil
 	case "EC384":
-		return certcrypto.EC384
+		return certcrypto.EC384, nil
+	}
+
+	return "", fmt.Errorf("unsupported key type: %s", keyType)
+}
+
+func getTransportConfig(ctx *cli.Context) lego.TransportConfig {
+	config := lego.TransportConfig{
+		MaxIdleConns:        ctx.Int(flgHTTPMaxIdleConns),
+		MaxIdleConnsPerHost: ctx.Int(flgHTTPMaxIdleConnsPerHost),
+		IdleConnTimeout:     ctx.Duration(flgHTTPIdleConnTimeout),
+		EnableHTTP2:         ctx.Bool(flgHTTP2),
 	}
 
-	log.Fatalf("Unsupported KeyType: %s", keyType)
+	if ctx.IsSet(flgTLSSessionCache) {
+		config.TLSSessionCacheSize = ctx.Int(flgTLSSessionCache)
 
-	return ""
+		// For the library, 0 keeps the default cache.
+		if config.TLSSessionCacheSize <= 0 {
+			config.TLSSessionCacheSize = -1
+		}
+	}
+
+	return config
 }
 
 func getUserAgent(ctx *cli.Context) string {
@@ -123,13 +233,7 @@ func getUserAgent(ctx *cli.Context) string {
 }
 
 func createNonExistingFolder(path string) error {
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return os.MkdirAll(path, 0o700)
-	} else if err != nil {
-		return err
-	}
-
-	return nil
+	return storage.CreateNonExistingFolder(path)
 }
 
 func readCSRFile(filename string) (*x509.CertificateRequest, error) {
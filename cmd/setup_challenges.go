@@ -1,29 +1,49 @@
 package cmd
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
+	"net/url"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/challenge/http01"
+	"github.com/go-acme/lego/v4/challenge/onioncsr01"
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
 	"github.com/go-acme/lego/v4/lego"
 	"github.com/go-acme/lego/v4/log"
 	"github.com/go-acme/lego/v4/providers/dns"
+	"github.com/go-acme/lego/v4/providers/http/edgekv"
+	"github.com/go-acme/lego/v4/providers/http/fastly"
 	"github.com/go-acme/lego/v4/providers/http/memcached"
 	"github.com/go-acme/lego/v4/providers/http/s3"
 	"github.com/go-acme/lego/v4/providers/http/webroot"
+	"github.com/go-acme/lego/v4/providers/tls/envoy"
+	"github.com/go-acme/lego/v4/providers/tls/haproxy"
 	"github.com/urfave/cli/v2"
 )
 
 func setupChallenges(ctx *cli.Context, client *lego.Client) {
-	if !ctx.Bool(flgHTTP) && !ctx.Bool(flgTLS) && !ctx.IsSet(flgDNS) {
-		log.Fatalf("No challenge selected. You must specify at least one challenge: `--%s`, `--%s`, `--%s`.", flgHTTP, flgTLS, flgDNS)
+	if !ctx.Bool(flgHTTP) && !ctx.Bool(flgTLS) && !ctx.IsSet(flgDNS) && !ctx.IsSet(flgOnionCSR) {
+		log.Fatalf("No challenge selected. You must specify at least one challenge: `--%s`, `--%s`, `--%s`, `--%s`.", flgHTTP, flgTLS, flgDNS, flgOnionCSR)
 	}
 
+	if ctx.Int(flgMaxParallelChallenges) < 1 {
+		log.Fatalf("'%s' must be greater than zero", flgMaxParallelChallenges)
+	}
+
+	client.Challenge.SetMaxParallelChallenges(ctx.Int(flgMaxParallelChallenges))
+
+	client.Challenge.SetValidationHooks(
+		newValidationHook(ctx.String(flgPreValidateHook), ctx.Duration(flgValidateHookTimeout)),
+		newValidationHook(ctx.String(flgPostValidateHook), ctx.Duration(flgValidateHookTimeout)),
+	)
+
 	if ctx.Bool(flgHTTP) {
 		err := client.Challenge.SetHTTP01Provider(setupHTTPProvider(ctx), http01.SetDelay(ctx.Duration(flgHTTPDelay)))
 		if err != nil {
@@ -44,6 +64,18 @@ func setupChallenges(ctx *cli.Context, client *lego.Client) {
 			log.Fatal(err)
 		}
 	}
+
+	if ctx.IsSet(flgOnionCSR) {
+		keys, err := onioncsr01.NewKeysFromDirs(ctx.StringSlice(flgOnionCSR)...)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		err = client.Challenge.SetOnionCSR01Provider(keys)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 }
 
 //nolint:gocyclo // the complexity is expected.
@@ -69,6 +101,17 @@ func setupHTTPProvider(ctx *cli.Context) challenge.Provider {
 			log.Fatal(err)
 		}
 
+		if ctx.IsSet(flgHTTPS3CloudFront) {
+			ps.SetCloudFrontDistribution(ctx.String(flgHTTPS3CloudFront))
+		}
+
+		return ps
+	case ctx.IsSet(flgHTTPCDN):
+		ps, err := newCDNProvider(ctx.String(flgHTTPCDN))
+		if err != nil {
+			log.Fatal(err)
+		}
+
 		return ps
 	case ctx.IsSet(flgHTTPPort):
 		iface := ctx.String(flgHTTPPort)
@@ -100,8 +143,38 @@ func setupHTTPProvider(ctx *cli.Context) challenge.Provider {
 	}
 }
 
+// newCDNProvider creates the HTTP-01 provider of a CDN.
+func newCDNProvider(name string) (challenge.Provider, error) {
+	var (
+		provider challenge.Provider
+		err      error
+	)
+
+	switch name {
+	case "fastly":
+		provider, err = fastly.NewHTTPProvider()
+	case "edgekv":
+		provider, err = edgekv.NewHTTPProvider()
+	default:
+		return nil, fmt.Errorf("unsupported CDN: %q", name)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
+
 func setupTLSProvider(ctx *cli.Context) challenge.Provider {
 	switch {
+	case ctx.IsSet(flgTLSProxy):
+		ps, err := newTLSProxyProvider(ctx.String(flgTLSProxy))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		return ps
 	case ctx.IsSet(flgTLSPort):
 		iface := ctx.String(flgTLSPort)
 		if !strings.Contains(iface, ":") {
@@ -113,15 +186,113 @@ func setupTLSProvider(ctx *cli.Context) challenge.Provider {
 			log.Fatal(err)
 		}
 
-		return tlsalpn01.NewProviderServer(host, port)
+		srv := tlsalpn01.NewProviderServer(host, port)
+		srv.SetTLSConfig(getTLSConfig(ctx))
+		srv.SetProxyProtocol(ctx.Bool(flgTLSProxyProtocol))
+
+		return srv
 	case ctx.Bool(flgTLS):
-		return tlsalpn01.NewProviderServer("", "")
+		srv := tlsalpn01.NewProviderServer("", "")
+		srv.SetTLSConfig(getTLSConfig(ctx))
+		srv.SetProxyProtocol(ctx.Bool(flgTLSProxyProtocol))
+
+		return srv
 	default:
 		log.Fatal("Invalid HTTP challenge options.")
 		return nil
 	}
 }
 
+// newTLSProxyProvider creates the TLS-ALPN-01 provider of a reverse proxy.
+func newTLSProxyProvider(name string) (challenge.Provider, error) {
+	var (
+		provider challenge.Provider
+		err      error
+	)
+
+	switch name {
+	case "haproxy":
+		provider, err = haproxy.NewTLSProvider()
+	case "envoy":
+		provider, err = envoy.NewTLSProvider()
+	default:
+		return nil, fmt.Errorf("unsupported reverse proxy: %q", name)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
+
+// getTLSConfig returns the TLS configuration of the TLS-ALPN-01 server, or nil if no TLS option is set.
+func getTLSConfig(ctx *cli.Context) *tls.Config {
+	if !ctx.IsSet(flgTLSMinVersion) && !ctx.IsSet(flgTLSCipherSuites) && !ctx.IsSet(flgTLSCurves) {
+		return nil
+	}
+
+	config, err := newTLSConfig(ctx.String(flgTLSMinVersion), ctx.StringSlice(flgTLSCipherSuites), ctx.StringSlice(flgTLSCurves))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return config
+}
+
+func newTLSConfig(minVersion string, cipherSuites, curves []string) (*tls.Config, error) {
+	config := &tls.Config{}
+
+	switch minVersion {
+	case "":
+	case "1.0":
+		config.MinVersion = tls.VersionTLS10
+	case "1.1":
+		config.MinVersion = tls.VersionTLS11
+	case "1.2":
+		config.MinVersion = tls.VersionTLS12
+	case "1.3":
+		config.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("unsupported TLS version: %s", minVersion)
+	}
+
+	knownSuites := make(map[string]uint16)
+	for _, suite := range slices.Concat(tls.CipherSuites(), tls.InsecureCipherSuites()) {
+		knownSuites[suite.Name] = suite.ID
+	}
+
+	for _, name := range cipherSuites {
+		id, ok := knownSuites[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported cipher suite: %s", name)
+		}
+
+		config.CipherSuites = append(config.CipherSuites, id)
+	}
+
+	knownCurves := make(map[string]tls.CurveID)
+	for _, id := range []tls.CurveID{tls.X25519, tls.CurveP256, tls.CurveP384, tls.CurveP521, tls.X25519MLKEM768} {
+		knownCurves[strings.ToUpper(id.String())] = id
+	}
+
+	// Aliases.
+	knownCurves["P256"] = tls.CurveP256
+	knownCurves["P384"] = tls.CurveP384
+	knownCurves["P521"] = tls.CurveP521
+
+	for _, name := range curves {
+		id, ok := knownCurves[strings.ToUpper(name)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve: %s", name)
+		}
+
+		config.CurvePreferences = append(config.CurvePreferences, id)
+	}
+
+	return config, nil
+}
+
 func setupDNS(ctx *cli.Context, client *lego.Client) error {
 	err := checkPropagationExclusiveOptions(ctx)
 	if err != nil {
@@ -133,6 +304,15 @@ func setupDNS(ctx *cli.Context, client *lego.Client) error {
 		return fmt.Errorf("'%s' cannot be negative", flgDNSPropagationWait)
 	}
 
+	if ctx.Duration(flgDNSPropagationDelay) < 0 {
+		return fmt.Errorf("'%s' cannot be negative", flgDNSPropagationDelay)
+	}
+
+	err = setupDNSAPIHosts(ctx)
+	if err != nil {
+		return err
+	}
+
 	provider, err := dns.NewDNSChallengeProviderByName(ctx.String(flgDNS))
 	if err != nil {
 		return err
@@ -140,10 +320,74 @@ func setupDNS(ctx *cli.Context, client *lego.Client) error {
 
 	servers := ctx.StringSlice(flgDNSResolvers)
 
+	// The CLI handles only one DNS provider, so the settings are also used by the provider (ex: zone lookup).
+	if len(servers) > 0 {
+		dns01.SetDefaultRecursiveNameservers(servers)
+	}
+
+	discoveryServers := ctx.StringSlice(flgDNSDiscoveryResolvers)
+	if len(discoveryServers) > 0 {
+		dns01.SetDefaultDiscoveryNameservers(discoveryServers)
+	}
+
+	propagationServers := ctx.StringSlice(flgDNSPropagationResolvers)
+	if len(propagationServers) > 0 {
+		dns01.SetDefaultPropagationNameservers(propagationServers)
+	}
+
+	if ctx.IsSet(flgDNSTimeout) {
+		dns01.SetDefaultDNSTimeout(time.Duration(ctx.Int(flgDNSTimeout)) * time.Second)
+	}
+
+	transport, err := dns01.ParseDNSTransport(ctx.String(flgDNSTransport))
+	if err != nil {
+		return fmt.Errorf("'%s': %w", flgDNSTransport, err)
+	}
+
+	if ctx.IsSet(flgDNSTransport) {
+		dns01.SetDefaultDNSTransport(transport)
+	}
+
+	var proxyURL *url.URL
+
+	if ctx.IsSet(flgDNSProxy) {
+		proxyURL, err = dns01.ParseDNSProxy(ctx.String(flgDNSProxy))
+		if err != nil {
+			return fmt.Errorf("'%s': %w", flgDNSProxy, err)
+		}
+
+		dns01.SetDefaultDNSProxy(proxyURL)
+	}
+
+	var transform *dns01.ChallengeTransform
+
+	if ctx.IsSet(flgDNSChallengeFQDN) || ctx.IsSet(flgDNSChallengeValue) {
+		transform, err = dns01.NewChallengeTransform(ctx.String(flgDNSChallengeFQDN), ctx.String(flgDNSChallengeValue))
+		if err != nil {
+			return fmt.Errorf("'%s', '%s': %w", flgDNSChallengeFQDN, flgDNSChallengeValue, err)
+		}
+
+		// The CLI handles only one DNS provider, so the transformation is also used by the provider.
+		dns01.SetDefaultChallengeTransform(transform)
+	}
+
+	manifest, err := dns01.OpenManifest(dnsManifestPath(ctx))
+	if err != nil {
+		return err
+	}
+
 	err = client.Challenge.SetDNS01Provider(provider,
+		dns01.AddRecordManifest(manifest),
+
 		dns01.CondOption(len(servers) > 0,
 			dns01.AddRecursiveNameservers(dns01.ParseNameservers(ctx.StringSlice(flgDNSResolvers)))),
 
+		dns01.CondOption(len(discoveryServers) > 0,
+			dns01.AddDiscoveryNameservers(discoveryServers)),
+
+		dns01.CondOption(len(propagationServers) > 0,
+			dns01.AddPropagationNameservers(propagationServers)),
+
 		dns01.CondOption(ctx.Bool(flgDNSDisableCP) || ctx.Bool(flgDNSPropagationDisableANS),
 			dns01.DisableAuthoritativeNssPropagationRequirement()),
 
@@ -155,13 +399,49 @@ func setupDNS(ctx *cli.Context, client *lego.Client) error {
 		dns01.CondOption(ctx.Bool(flgDNSPropagationRNS),
 			dns01.RecursiveNSsPropagationRequirement()),
 
+		dns01.CondOption(ctx.Bool(flgDNSPropagationAllANS),
+			dns01.AllAuthoritativeNSsPropagationRequirement()),
+
+		dns01.CondOption(ctx.Duration(flgDNSPropagationDelay) > 0,
+			dns01.PropagationInitialDelay(ctx.Duration(flgDNSPropagationDelay))),
+
 		dns01.CondOption(ctx.IsSet(flgDNSTimeout),
 			dns01.AddDNSTimeout(time.Duration(ctx.Int(flgDNSTimeout))*time.Second)),
+
+		dns01.CondOption(ctx.IsSet(flgDNSTransport),
+			dns01.AddDNSTransport(transport)),
+
+		dns01.CondOption(proxyURL != nil,
+			dns01.AddDNSProxy(proxyURL)),
+
+		dns01.CondOption(transform != nil,
+			dns01.AddChallengeTransform(transform)),
+
+		dns01.CondOption(len(ctx.StringSlice(flgDNSPerspectives)) > 0,
+			dns01.RemotePerspectivesPropagationRequirement(ctx.StringSlice(flgDNSPerspectives), ctx.Int(flgDNSPerspectivesQuorum))),
 	)
 
 	return err
 }
 
+// dnsManifestPath returns the path of the manifest of the challenge records created by lego.
+func dnsManifestPath(ctx *cli.Context) string {
+	return filepath.Join(ctx.String(flgPath), "dns-challenges.json")
+}
+
+func setupDNSAPIHosts(ctx *cli.Context) error {
+	if !ctx.IsSet(flgDNSAPIHosts) {
+		return nil
+	}
+
+	hosts, err := dns.ParseAPIHosts(ctx.StringSlice(flgDNSAPIHosts))
+	if err != nil {
+		return fmt.Errorf("'%s': %w", flgDNSAPIHosts, err)
+	}
+
+	return dns.OverrideAPIHosts(hosts)
+}
+
 func checkPropagationExclusiveOptions(ctx *cli.Context) error {
 	if ctx.IsSet(flgDNSDisableCP) {
 		log.Printf("The flag '%s' is deprecated use '%s' instead.", flgDNSDisableCP, flgDNSPropagationDisableANS)
@@ -175,6 +455,10 @@ func checkPropagationExclusiveOptions(ctx *cli.Context) error {
 		return fmt.Errorf("'%s' and '%s' are mutually exclusive", flgDNSPropagationRNS, flgDNSPropagationWait)
 	}
 
+	if ctx.IsSet(flgDNSPropagationDelay) && ctx.IsSet(flgDNSPropagationWait) {
+		return fmt.Errorf("'%s' and '%s' are mutually exclusive", flgDNSPropagationDelay, flgDNSPropagationWait)
+	}
+
 	return nil
 }
 
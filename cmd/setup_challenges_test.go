@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_newTLSConfig(t *testing.T) {
+	config, err := newTLSConfig("1.2",
+		[]string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
+		[]string{"x25519", "P256", "CurveP384"})
+	require.NoError(t, err)
+
+	assert.Equal(t, uint16(tls.VersionTLS12), config.MinVersion)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384}, config.CipherSuites)
+	assert.Equal(t, []tls.CurveID{tls.X25519, tls.CurveP256, tls.CurveP384}, config.CurvePreferences)
+}
+
+func Test_newTLSConfig_errors(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		minVersion   string
+		cipherSuites []string
+		curves       []string
+		expected     string
+	}{
+		{
+			desc:       "invalid version",
+			minVersion: "1.4",
+			expected:   "unsupported TLS version: 1.4",
+		},
+		{
+			desc:         "invalid cipher suite",
+			cipherSuites: []string{"TLS_FOO"},
+			expected:     "unsupported cipher suite: TLS_FOO",
+		},
+		{
+			desc:     "invalid curve",
+			curves:   []string{"P999"},
+			expected: "unsupported curve: P999",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := newTLSConfig(test.minVersion, test.cipherSuites, test.curves)
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func Test_newCDNProvider(t *testing.T) {
+	t.Setenv("FASTLY_API_TOKEN", "secret")
+	t.Setenv("FASTLY_SERVICE_ID", "srv")
+	t.Setenv("FASTLY_DICTIONARY_ID", "dict")
+
+	provider, err := newCDNProvider("fastly")
+	require.NoError(t, err)
+
+	assert.NotNil(t, provider)
+}
+
+func Test_newCDNProvider_errors(t *testing.T) {
+	t.Setenv("FASTLY_API_TOKEN", "")
+
+	_, err := newCDNProvider("fastly")
+	require.Error(t, err)
+
+	_, err = newCDNProvider("foo")
+	require.EqualError(t, err, `unsupported CDN: "foo"`)
+}
+
+func Test_newTLSProxyProvider(t *testing.T) {
+	t.Setenv("HAPROXY_RUNTIME_API", "unix:/run/haproxy/admin.sock")
+	t.Setenv("HAPROXY_CRT_LIST", "/etc/haproxy/crt-list.txt")
+	t.Setenv("ENVOY_SDS_PATH", "/etc/envoy/sds/acme.json")
+
+	for _, name := range []string{"haproxy", "envoy"} {
+		provider, err := newTLSProxyProvider(name)
+		require.NoError(t, err)
+
+		assert.NotNil(t, provider)
+	}
+}
+
+func Test_newTLSProxyProvider_errors(t *testing.T) {
+	t.Setenv("HAPROXY_RUNTIME_API", "")
+
+	_, err := newTLSProxyProvider("haproxy")
+	require.Error(t, err)
+
+	_, err = newTLSProxyProvider("foo")
+	require.EqualError(t, err, `unsupported reverse proxy: "foo"`)
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/lego"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func Test_getTransportConfig(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		args     []string
+		expected lego.TransportConfig
+	}{
+		{
+			desc: "no flags",
+		},
+		{
+			desc: "all flags",
+			args: []string{
+				"--" + flgHTTPMaxIdleConns, "500",
+				"--" + flgHTTPMaxIdleConnsPerHost, "50",
+				"--" + flgHTTPIdleConnTimeout, "5m",
+				"--" + flgHTTP2,
+				"--" + flgTLSSessionCache, "256",
+			},
+			expected: lego.TransportConfig{
+				MaxIdleConns:        500,
+				MaxIdleConnsPerHost: 50,
+				IdleConnTimeout:     5 * time.Minute,
+				EnableHTTP2:         true,
+				TLSSessionCacheSize: 256,
+			},
+		},
+		{
+			desc:     "TLS session resumption disabled",
+			args:     []string{"--" + flgTLSSessionCache, "0"},
+			expected: lego.TransportConfig{TLSSessionCacheSize: -1},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			var config lego.TransportConfig
+
+			app := &cli.App{
+				Name:  "lego",
+				Flags: CreateFlags(""),
+				Action: func(ctx *cli.Context) error {
+					config = getTransportConfig(ctx)
+					return nil
+				},
+			}
+
+			err := app.Run(append([]string{"lego"}, test.args...))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, config)
+		})
+	}
+}
+
+func Test_getAdditionalKeyTypes(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		args         []string
+		expected     []string
+		expectedKeep map[string]bool
+	}{
+		{
+			desc:         "no flags",
+			expectedKeep: map[string]bool{"": false},
+		},
+		{
+			desc:         "one additional key type",
+			args:         []string{"--" + flgKeyType, "ec256", "--" + flgAdditionalKeyType, "RSA2048"},
+			expected:     []string{"rsa2048"},
+			expectedKeep: map[string]bool{"": true, "rsa2048": false},
+		},
+		{
+			desc:         "duplicates",
+			args:         []string{"--" + flgAdditionalKeyType, "rsa2048", "--" + flgAdditionalKeyType, "ec384", "--" + flgAdditionalKeyType, "rsa2048"},
+			expected:     []string{"rsa2048", "ec384"},
+			expectedKeep: map[string]bool{"": true, "rsa2048": true, "ec384": false},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			var (
+				keyTypes []string
+				keep     = map[string]bool{}
+			)
+
+			app := &cli.App{
+				Name:  "lego",
+				Flags: append(CreateFlags(""), &cli.StringSliceFlag{Name: flgAdditionalKeyType}),
+				Action: func(ctx *cli.Context) error {
+					keyTypes = getAdditionalKeyTypes(ctx)
+
+					for name := range test.expectedKeep {
+						keep[name] = keepAuthorizations(ctx, name)
+					}
+
+					return nil
+				},
+			}
+
+			err := app.Run(append([]string{"lego"}, test.args...))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, keyTypes)
+			assert.Equal(t, test.expectedKeep, keep)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-acme/lego/v4/lego"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// shutdownWatcher handles the stop of the process (SIGTERM, SIGINT) while certificates are obtained by the one-shot commands (run, renew).
+// The in-flight orders have a grace period to complete: if the command is still running after that,
+// the in-flight orders are abandoned (the presented challenges are cleaned up and the pending authorizations are deactivated),
+// and the command fails.
+// A second signal abandons the in-flight orders immediately.
+// The daemon watches the signals itself, and only uses the abandon of the in-flight orders (see watchDaemonShutdown).
+type shutdownWatcher struct {
+	gracePeriod time.Duration
+
+	mu      sync.Mutex
+	clients []*lego.Client
+}
+
+func newShutdownWatcher(gracePeriod time.Duration) *shutdownWatcher {
+	return &shutdownWatcher{gracePeriod: gracePeriod}
+}
+
+// watch adds a client whose in-flight orders are abandoned when the process is stopped.
+// A nil watcher does nothing.
+func (w *shutdownWatcher) watch(client *lego.Client) {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.clients = append(w.clients, client)
+}
+
+// run runs fn, and watches the stop signals until fn returns or the context is done.
+// Returns the error of fn, or an error if the in-flight orders have been abandoned.
+func (w *shutdownWatcher) run(ctx context.Context, fn func() error) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	defer signal.Stop(signals)
+
+	result := make(chan error, 1)
+
+	go func() { result <- fn() }()
+
+	var sig os.Signal
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case sig = <-signals:
+	}
+
+	log.Infof("Received %s: waiting %s for the in-flight orders to complete.", sig, w.gracePeriod)
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+	case <-time.After(w.gracePeriod):
+	case <-signals:
+	}
+
+	log.Warnf("Abandoning the in-flight orders.")
+
+	w.abandon()
+
+	return fmt.Errorf("stopped by %s: the in-flight orders have been abandoned", sig)
+}
+
+// reset forgets the watched clients (ex: after a renewal check of the daemon).
+func (w *shutdownWatcher) reset() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.clients = nil
+}
+
+func (w *shutdownWatcher) abandon() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, client := range w.clients {
+		client.Certificate.Abandon()
+	}
+}
@@ -0,0 +1,92 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_shutdownWatcher_run(t *testing.T) {
+	shutdown := newShutdownWatcher(time.Second)
+
+	err := shutdown.run(context.Background(), func() error {
+		return errors.New("oops")
+	})
+
+	require.EqualError(t, err, "oops")
+}
+
+func Test_shutdownWatcher_run_contextDone(t *testing.T) {
+	shutdown := newShutdownWatcher(time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	err := shutdown.run(ctx, func() error {
+		<-release
+
+		return nil
+	})
+
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func Test_shutdownWatcher_run_signal(t *testing.T) {
+	shutdown := newShutdownWatcher(10 * time.Millisecond)
+
+	started := make(chan struct{})
+
+	release := make(chan struct{})
+	defer close(release)
+
+	go func() {
+		<-started
+
+		_ = syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
+	}()
+
+	err := shutdown.run(context.Background(), func() error {
+		close(started)
+
+		<-release
+
+		return nil
+	})
+
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), "the in-flight orders have been abandoned")
+}
+
+func Test_shutdownWatcher_run_signalGracePeriod(t *testing.T) {
+	shutdown := newShutdownWatcher(time.Minute)
+
+	started := make(chan struct{})
+
+	go func() {
+		<-started
+
+		_ = syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
+	}()
+
+	err := shutdown.run(context.Background(), func() error {
+		close(started)
+
+		// Completes during the grace period.
+		time.Sleep(50 * time.Millisecond)
+
+		return nil
+	})
+
+	require.NoError(t, err)
+}
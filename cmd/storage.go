@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/storage"
+	"github.com/go-acme/lego/v4/providers/storage/s3"
+	"github.com/go-acme/lego/v4/providers/storage/vault"
+	"github.com/urfave/cli/v2"
+)
+
+const storageFileSystem = "filesystem"
+
+// storageMetadataKey the key of the storage mirror in the metadata of the application.
+const storageMetadataKey = "lego.storage"
+
+// newStorageBackend returns the remote storage backend, or nil if the data are only stored in the local directory.
+func newStorageBackend(name string) (storage.Backend, error) {
+	switch strings.ToLower(name) {
+	case "", storageFileSystem:
+		return nil, nil
+
+	case "s3":
+		return s3.NewBackend()
+
+	case "vault":
+		return vault.NewBackend()
+
+	default:
+		return nil, fmt.Errorf("unsupported storage: %s", name)
+	}
+}
+
+// storageMirror synchronizes the local directory (`--path`) with a remote storage backend.
+// The files are downloaded before the command, and the changes are uploaded after the command:
+// the commands keep reading and writing the local directory.
+type storageMirror struct {
+	local  storage.Backend
+	remote storage.Backend
+
+	// hashes the hashes of the files known to be identical in the local directory and the remote storage.
+	hashes map[string][sha256.Size]byte
+}
+
+func newStorageMirror(local, remote storage.Backend) *storageMirror {
+	return &storageMirror{
+		local:  local,
+		remote: remote,
+		hashes: make(map[string][sha256.Size]byte),
+	}
+}
+
+// pull downloads all the files of the remote storage into the local directory.
+// The local files unknown to the remote storage are kept: they are uploaded by the next push.
+func (m *storageMirror) pull(ctx context.Context) error {
+	keys, err := m.remote.List(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		data, err := m.remote.Load(ctx, key)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				// Deleted since the listing.
+				continue
+			}
+
+			return err
+		}
+
+		err = m.local.Save(ctx, key, data)
+		if err != nil {
+			return err
+		}
+
+		m.hashes[key] = sha256.Sum256(data)
+	}
+
+	return nil
+}
+
+// push uploads the files created or modified in the local directory,
+// and deletes the remote files deleted from the local directory.
+func (m *storageMirror) push(ctx context.Context) error {
+	keys, err := m.local.List(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	present := make(map[string]struct{}, len(keys))
+
+	for _, key := range keys {
+		present[key] = struct{}{}
+
+		data, err := m.local.Load(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		hash := sha256.Sum256(data)
+
+		if known, ok := m.hashes[key]; ok && known == hash {
+			continue
+		}
+
+		err = m.remote.Save(ctx, key, data)
+		if err != nil {
+			return err
+		}
+
+		m.hashes[key] = hash
+	}
+
+	for key := range m.hashes {
+		if _, ok := present[key]; ok {
+			continue
+		}
+
+		err = m.remote.Delete(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		delete(m.hashes, key)
+	}
+
+	return nil
+}
+
+// pullStorage downloads the data from the remote storage backend (`--storage`) into the local directory (`--path`).
+func pullStorage(ctx *cli.Context) error {
+	remote, err := newStorageBackend(ctx.String(flgStorage))
+	if err != nil {
+		return err
+	}
+
+	if remote == nil {
+		return nil
+	}
+
+	mirror := newStorageMirror(storage.NewFileSystem(ctx.String(flgPath)), remote)
+
+	err = mirror.pull(ctx.Context)
+	if err != nil {
+		return err
+	}
+
+	if ctx.App.Metadata == nil {
+		ctx.App.Metadata = make(map[string]any)
+	}
+
+	ctx.App.Metadata[storageMetadataKey] = mirror
+
+	return nil
+}
+
+// pushStorage uploads the changes of the local directory (`--path`) to the remote storage backend (`--storage`).
+func pushStorage(ctx *cli.Context) error {
+	mirror, ok := ctx.App.Metadata[storageMetadataKey].(*storageMirror)
+	if !ok {
+		return nil
+	}
+
+	err := mirror.push(ctx.Context)
+	if err != nil {
+		return fmt.Errorf("could not save the data to the storage: %w", err)
+	}
+
+	log.Infof("The data have been saved to the storage (%s).", ctx.String(flgStorage))
+
+	return nil
+}
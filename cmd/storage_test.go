@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/go-acme/lego/v4/platform/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_newStorageBackend(t *testing.T) {
+	backend, err := newStorageBackend("")
+	require.NoError(t, err)
+	assert.Nil(t, backend)
+
+	backend, err = newStorageBackend("filesystem")
+	require.NoError(t, err)
+	assert.Nil(t, backend)
+
+	_, err = newStorageBackend("ftp")
+	require.EqualError(t, err, "unsupported storage: ftp")
+}
+
+func Test_storageMirror(t *testing.T) {
+	remote := storage.NewFileSystem(t.TempDir())
+
+	err := remote.Save(t.Context(), "certificates/example.com.crt", []byte("cert"))
+	require.NoError(t, err)
+
+	err = remote.Save(t.Context(), "certificates/example.org.crt", []byte("cert"))
+	require.NoError(t, err)
+
+	local := storage.NewFileSystem(t.TempDir())
+
+	err = local.Save(t.Context(), "accounts/example/account.json", []byte("{}"))
+	require.NoError(t, err)
+
+	mirror := newStorageMirror(local, remote)
+
+	err = mirror.pull(t.Context())
+	require.NoError(t, err)
+
+	data, err := local.Load(t.Context(), "certificates/example.com.crt")
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte("cert"), data)
+
+	// The command renews a certificate, and removes another one.
+
+	err = local.Save(t.Context(), "certificates/example.com.crt", []byte("renewed"))
+	require.NoError(t, err)
+
+	err = local.Delete(t.Context(), "certificates/example.org.crt")
+	require.NoError(t, err)
+
+	err = mirror.push(t.Context())
+	require.NoError(t, err)
+
+	keys, err := remote.List(t.Context(), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"accounts/example/account.json", "certificates/example.com.crt"}, keys)
+
+	data, err = remote.Load(t.Context(), "certificates/example.com.crt")
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte("renewed"), data)
+}
+
+func Test_storageMirror_push_unchanged(t *testing.T) {
+	remote := storage.NewFileSystem(t.TempDir())
+
+	err := remote.Save(t.Context(), "certificates/example.com.crt", []byte("cert"))
+	require.NoError(t, err)
+
+	mirror := newStorageMirror(storage.NewFileSystem(t.TempDir()), remote)
+
+	err = mirror.pull(t.Context())
+	require.NoError(t, err)
+
+	// Modified by another instance: an unchanged local file must not overwrite it.
+	err = remote.Save(t.Context(), "certificates/example.com.crt", []byte("other"))
+	require.NoError(t, err)
+
+	err = mirror.push(t.Context())
+	require.NoError(t, err)
+
+	data, err := remote.Load(t.Context(), "certificates/example.com.crt")
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte("other"), data)
+}
@@ -0,0 +1,6 @@
+#!/bin/bash -e
+
+grep -q "BEGIN CERTIFICATE" -
+test "$LEGO_CERT_DOMAIN" = "example.com"
+test "$LEGO_CERT_SCTS" = "[]"
+test -z "$LEGO_CERT_PATH"
@@ -0,0 +1,6 @@
+#!/bin/bash -e
+
+test "$LEGO_CHALLENGE_DOMAIN" = "example.com"
+test "$LEGO_CHALLENGE_TYPE" = "dns-01"
+test "$LEGO_CHALLENGE_TOKEN" = "token"
+test -z "$LEGO_CHALLENGE_ERROR"
@@ -146,6 +146,7 @@ func allDNSCodes() string {
 		"rfc2136",
 		"rimuhosting",
 		"route53",
+		"routeros",
 		"safedns",
 		"sakuracloud",
 		"scaleway",
@@ -164,6 +165,7 @@ func allDNSCodes() string {
 		"timewebcloud",
 		"transip",
 		"ultradns",
+		"unifi",
 		"uniteddomains",
 		"variomedia",
 		"vegadns",
@@ -737,6 +739,7 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln(`Additional Configuration:`)
 		ew.writeln(`	- "CLOUDFLARE_BASE_URL":	API base URL (Default: https://api.cloudflare.com/client/v4)`)
 		ew.writeln(`	- "CLOUDFLARE_HTTP_TIMEOUT":	API request timeout in seconds (Default: )`)
+		ew.writeln(`	- "CLOUDFLARE_MAX_RETRIES":	The maximum number of retries of an API call on the 429 and 5xx responses, only on Retry-After for the non-idempotent calls (Default: 3)`)
 		ew.writeln(`	- "CLOUDFLARE_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 2)`)
 		ew.writeln(`	- "CLOUDFLARE_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 120)`)
 		ew.writeln(`	- "CLOUDFLARE_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 120)`)
@@ -2303,6 +2306,13 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln(`Since:	'v0.3.0'`)
 		ew.writeln()
 
+		ew.writeln(`Additional Configuration:`)
+		ew.writeln(`	- "MANUAL_CONFIRM_ADDRESS":	The address of the HTTP server waiting for the confirmation of the creation of the record (ex: ':8053')`)
+		ew.writeln(`	- "MANUAL_CONFIRM_FILE":	The file created by an external system to confirm the creation of the record`)
+		ew.writeln(`	- "MANUAL_CONFIRM_INTERVAL":	Time between the checks of the confirmation file in seconds (Default: 2)`)
+		ew.writeln(`	- "MANUAL_CONFIRM_TIMEOUT":	Maximum waiting time for the confirmation in seconds (Default: no limit)`)
+		ew.writeln(`	- "MANUAL_INSTRUCTIONS":	The destination of the JSON instructions: a file path, or a file descriptor (ex: 'fd:3') (Default: text on the standard output)`)
+
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/manual`)
 
@@ -3059,8 +3069,14 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln(`	- "AWS_HOSTED_ZONE_ID":	Override the hosted zone ID.`)
 		ew.writeln(`	- "AWS_PROFILE":	Managed by the AWS client ('AWS_PROFILE_FILE' is not supported)`)
 		ew.writeln(`	- "AWS_REGION":	Managed by the AWS client ('AWS_REGION_FILE' is not supported)`)
+		ew.writeln(`	- "AWS_ROLE_CHAIN":	Comma-separated list of role ARNs assumed in sequence, after 'AWS_ASSUME_ROLE_ARN'`)
 		ew.writeln(`	- "AWS_SDK_LOAD_CONFIG":	Managed by the AWS client. Retrieve the region from the CLI config file ('AWS_SDK_LOAD_CONFIG_FILE' is not supported)`)
 		ew.writeln(`	- "AWS_SECRET_ACCESS_KEY":	Managed by the AWS client. Secret access key ('AWS_SECRET_ACCESS_KEY_FILE' is not supported, use 'AWS_SHARED_CREDENTIALS_FILE' instead)`)
+		ew.writeln(`	- "AWS_SSO_ACCOUNT_ID":	ID of the AWS account of the SSO role`)
+		ew.writeln(`	- "AWS_SSO_REGION":	Region of the SSO portal (Default: the AWS region)`)
+		ew.writeln(`	- "AWS_SSO_ROLE_NAME":	Name of the SSO role (permission set)`)
+		ew.writeln(`	- "AWS_SSO_SESSION":	Name of the SSO session, used to find and refresh the cached token`)
+		ew.writeln(`	- "AWS_SSO_START_URL":	URL of the SSO portal, used to find the cached token of a legacy SSO configuration`)
 		ew.writeln(`	- "AWS_WAIT_FOR_RECORD_SETS_CHANGED":	Wait for changes to be INSYNC (it can be unstable)`)
 		ew.writeln()
 
@@ -3075,6 +3091,29 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/route53`)
 
+	case "routeros":
+		// generated from: providers/dns/routeros/routeros.toml
+		ew.writeln(`Configuration for MikroTik RouterOS.`)
+		ew.writeln(`Code:	'routeros'`)
+		ew.writeln(`Since:	'v4.30.0'`)
+		ew.writeln()
+
+		ew.writeln(`Credentials:`)
+		ew.writeln(`	- "ROUTEROS_BASE_URL":	The URL of the router (ex: https://192.168.88.1)`)
+		ew.writeln(`	- "ROUTEROS_PASSWORD":	Password`)
+		ew.writeln(`	- "ROUTEROS_USERNAME":	Username`)
+		ew.writeln()
+
+		ew.writeln(`Additional Configuration:`)
+		ew.writeln(`	- "ROUTEROS_HTTP_TIMEOUT":	API request timeout in seconds (Default: 30)`)
+		ew.writeln(`	- "ROUTEROS_INSECURE_SKIP_VERIFY":	Whether or not to skip the verification of the TLS certificate of the router (Default: false)`)
+		ew.writeln(`	- "ROUTEROS_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 2)`)
+		ew.writeln(`	- "ROUTEROS_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 60)`)
+		ew.writeln(`	- "ROUTEROS_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 120)`)
+
+		ew.writeln()
+		ew.writeln(`More information: https://go-acme.github.io/lego/dns/routeros`)
+
 	case "safedns":
 		// generated from: providers/dns/safedns/safedns.toml
 		ew.writeln(`Configuration for UKFast SafeDNS.`)
@@ -3108,10 +3147,13 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln()
 
 		ew.writeln(`Additional Configuration:`)
+		ew.writeln(`	- "SAKURACLOUD_API_ROOT_URL":	The root URL of the API (Default: https://secure.sakura.ad.jp/cloud/zone)`)
 		ew.writeln(`	- "SAKURACLOUD_HTTP_TIMEOUT":	API request timeout in seconds (Default: 10)`)
+		ew.writeln(`	- "SAKURACLOUD_MAX_RETRIES":	The maximum number of retries of an API call on the 429 and 5xx responses, only on Retry-After for the non-idempotent calls (Default: 10)`)
 		ew.writeln(`	- "SAKURACLOUD_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 2)`)
 		ew.writeln(`	- "SAKURACLOUD_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 60)`)
 		ew.writeln(`	- "SAKURACLOUD_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 120)`)
+		ew.writeln(`	- "SAKURACLOUD_ZONE":	The default zone of the API, ex: 'tk1v' for the sandbox (Default: is1a)`)
 
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/sakuracloud`)
@@ -3460,6 +3502,29 @@ func displayDNSHelp(w io.Writer, name string) error {
 		ew.writeln()
 		ew.writeln(`More information: https://go-acme.github.io/lego/dns/ultradns`)
 
+	case "unifi":
+		// generated from: providers/dns/unifi/unifi.toml
+		ew.writeln(`Configuration for UniFi.`)
+		ew.writeln(`Code:	'unifi'`)
+		ew.writeln(`Since:	'v4.30.0'`)
+		ew.writeln()
+
+		ew.writeln(`Credentials:`)
+		ew.writeln(`	- "UNIFI_API_KEY":	API key`)
+		ew.writeln(`	- "UNIFI_BASE_URL":	The URL of the UniFi gateway (ex: https://192.168.1.1)`)
+		ew.writeln()
+
+		ew.writeln(`Additional Configuration:`)
+		ew.writeln(`	- "UNIFI_HTTP_TIMEOUT":	API request timeout in seconds (Default: 30)`)
+		ew.writeln(`	- "UNIFI_INSECURE_SKIP_VERIFY":	Whether or not to skip the verification of the TLS certificate of the gateway (Default: false)`)
+		ew.writeln(`	- "UNIFI_POLLING_INTERVAL":	Time between DNS propagation check in seconds (Default: 2)`)
+		ew.writeln(`	- "UNIFI_PROPAGATION_TIMEOUT":	Maximum waiting time for DNS propagation in seconds (Default: 60)`)
+		ew.writeln(`	- "UNIFI_SITE":	The name of the UniFi site (Default: default)`)
+		ew.writeln(`	- "UNIFI_TTL":	The TTL of the TXT record used for the DNS challenge in seconds (Default: 120)`)
+
+		ew.writeln()
+		ew.writeln(`More information: https://go-acme.github.io/lego/dns/unifi`)
+
 	case "uniteddomains":
 		// generated from: providers/dns/uniteddomains/uniteddomains.toml
 		ew.writeln(`Configuration for United-Domains.`)
@@ -3933,3 +3998,906 @@ func displayDNSHelp(w io.Writer, name string) error {
 	}
 	return nil
 }
+
+func dnsCredentials(name string) []envVar {
+	switch name {
+	case "acme-dns":
+		return []envVar{
+			{Name: "ACME_DNS_API_BASE", Description: `The ACME-DNS API address`},
+			{Name: "ACME_DNS_STORAGE_BASE_URL", Description: `The ACME-DNS JSON account data server.`},
+			{Name: "ACME_DNS_STORAGE_PATH", Description: `The ACME-DNS JSON account data file. A per-domain account will be registered/persisted to this file and used for TXT updates.`},
+		}
+	case "active24":
+		return []envVar{
+			{Name: "ACTIVE24_API_KEY", Description: `API key`},
+			{Name: "ACTIVE24_SECRET", Description: `Secret`},
+		}
+	case "alidns":
+		return []envVar{
+			{Name: "ALICLOUD_ACCESS_KEY", Description: `Access key ID`},
+			{Name: "ALICLOUD_RAM_ROLE", Description: `Your instance RAM role (https://www.alibabacloud.com/help/en/ecs/user-guide/attach-an-instance-ram-role-to-an-ecs-instance)`},
+			{Name: "ALICLOUD_SECRET_KEY", Description: `Access Key secret`},
+			{Name: "ALICLOUD_SECURITY_TOKEN", Description: `STS Security Token (optional)`},
+		}
+	case "aliesa":
+		return []envVar{
+			{Name: "ALIESA_ACCESS_KEY", Description: `Access key ID`},
+			{Name: "ALIESA_RAM_ROLE", Description: `Your instance RAM role (https://www.alibabacloud.com/help/en/ecs/user-guide/attach-an-instance-ram-role-to-an-ecs-instance)`},
+			{Name: "ALIESA_SECRET_KEY", Description: `Access Key secret`},
+			{Name: "ALIESA_SECURITY_TOKEN", Description: `STS Security Token (optional)`},
+		}
+	case "allinkl":
+		return []envVar{
+			{Name: "ALL_INKL_LOGIN", Description: `KAS login`},
+			{Name: "ALL_INKL_PASSWORD", Description: `KAS password`},
+		}
+	case "anexia":
+		return []envVar{
+			{Name: "ANEXIA_TOKEN", Description: `API token for Anexia Engine`},
+		}
+	case "arvancloud":
+		return []envVar{
+			{Name: "ARVANCLOUD_API_KEY", Description: `API key`},
+		}
+	case "auroradns":
+		return []envVar{
+			{Name: "AURORA_API_KEY", Description: `API key or username to used`},
+			{Name: "AURORA_SECRET", Description: `Secret password to be used`},
+		}
+	case "autodns":
+		return []envVar{
+			{Name: "AUTODNS_API_PASSWORD", Description: `User Password`},
+			{Name: "AUTODNS_API_USER", Description: `Username`},
+		}
+	case "axelname":
+		return []envVar{
+			{Name: "AXELNAME_NICKNAME", Description: `Account nickname`},
+			{Name: "AXELNAME_TOKEN", Description: `API token`},
+		}
+	case "azion":
+		return []envVar{
+			{Name: "AZION_PERSONAL_TOKEN", Description: `Your Azion personal token.`},
+		}
+	case "azure":
+		return []envVar{
+			{Name: "AZURE_CLIENT_ID", Description: `Client ID`},
+			{Name: "AZURE_CLIENT_SECRET", Description: `Client secret`},
+			{Name: "AZURE_ENVIRONMENT", Description: `Azure environment, one of: public, usgovernment, german, and china`},
+			{Name: "AZURE_RESOURCE_GROUP", Description: `Resource group`},
+			{Name: "AZURE_SUBSCRIPTION_ID", Description: `Subscription ID`},
+			{Name: "AZURE_TENANT_ID", Description: `Tenant ID`},
+			{Name: "instance metadata service", Description: `If the credentials are **not** set via the environment, then it will attempt to get a bearer token via the [instance metadata service](https://docs.microsoft.com/en-us/azure/virtual-machines/windows/instance-metadata-service).`},
+		}
+	case "azuredns":
+		return []envVar{
+			{Name: "AZURE_CLIENT_CERTIFICATE_PATH", Description: `Client certificate path`},
+			{Name: "AZURE_CLIENT_ID", Description: `Client ID`},
+			{Name: "AZURE_CLIENT_SECRET", Description: `Client secret`},
+			{Name: "AZURE_TENANT_ID", Description: `Tenant ID`},
+		}
+	case "baiducloud":
+		return []envVar{
+			{Name: "BAIDUCLOUD_ACCESS_KEY_ID", Description: `Access key`},
+			{Name: "BAIDUCLOUD_SECRET_ACCESS_KEY", Description: `Secret access key`},
+		}
+	case "beget":
+		return []envVar{
+			{Name: "BEGET_PASSWORD", Description: `API password`},
+			{Name: "BEGET_USERNAME", Description: `API username`},
+		}
+	case "binarylane":
+		return []envVar{
+			{Name: "BINARYLANE_API_TOKEN", Description: `API token`},
+		}
+	case "bindman":
+		return []envVar{
+			{Name: "BINDMAN_MANAGER_ADDRESS", Description: `The server URL, should have scheme, hostname, and port (if required) of the Bindman-DNS Manager server`},
+		}
+	case "bluecat":
+		return []envVar{
+			{Name: "BLUECAT_CONFIG_NAME", Description: `Configuration name`},
+			{Name: "BLUECAT_DNS_VIEW", Description: `External DNS View Name`},
+			{Name: "BLUECAT_PASSWORD", Description: `API password`},
+			{Name: "BLUECAT_SERVER_URL", Description: `The server URL, should have scheme, hostname, and port (if required) of the authoritative Bluecat BAM serve`},
+			{Name: "BLUECAT_USER_NAME", Description: `API username`},
+		}
+	case "bookmyname":
+		return []envVar{
+			{Name: "BOOKMYNAME_PASSWORD", Description: `Password`},
+			{Name: "BOOKMYNAME_USERNAME", Description: `Username`},
+		}
+	case "brandit":
+		return []envVar{
+			{Name: "BRANDIT_API_KEY", Description: `The API key`},
+			{Name: "BRANDIT_API_USERNAME", Description: `The API username`},
+		}
+	case "bunny":
+		return []envVar{
+			{Name: "BUNNY_API_KEY", Description: `API key`},
+		}
+	case "checkdomain":
+		return []envVar{
+			{Name: "CHECKDOMAIN_TOKEN", Description: `API token`},
+		}
+	case "civo":
+		return []envVar{
+			{Name: "CIVO_TOKEN", Description: `Authentication token`},
+		}
+	case "clouddns":
+		return []envVar{
+			{Name: "CLOUDDNS_CLIENT_ID", Description: `Client ID`},
+			{Name: "CLOUDDNS_EMAIL", Description: `Account email`},
+			{Name: "CLOUDDNS_PASSWORD", Description: `Account password`},
+		}
+	case "cloudflare":
+		return []envVar{
+			{Name: "CF_API_EMAIL", Description: `Account email`},
+			{Name: "CF_API_KEY", Description: `API key`},
+			{Name: "CF_DNS_API_TOKEN", Description: `API token with DNS:Edit permission (since v3.1.0)`},
+			{Name: "CF_ZONE_API_TOKEN", Description: `API token with Zone:Read permission (since v3.1.0)`},
+			{Name: "CLOUDFLARE_API_KEY", Description: `Alias to CF_API_KEY`},
+			{Name: "CLOUDFLARE_DNS_API_TOKEN", Description: `Alias to CF_DNS_API_TOKEN`},
+			{Name: "CLOUDFLARE_EMAIL", Description: `Alias to CF_API_EMAIL`},
+			{Name: "CLOUDFLARE_ZONE_API_TOKEN", Description: `Alias to CF_ZONE_API_TOKEN`},
+		}
+	case "cloudns":
+		return []envVar{
+			{Name: "CLOUDNS_AUTH_ID", Description: `The API user ID`},
+			{Name: "CLOUDNS_AUTH_PASSWORD", Description: `The password for API user ID`},
+		}
+	case "cloudru":
+		return []envVar{
+			{Name: "CLOUDRU_KEY_ID", Description: `Key ID (login)`},
+			{Name: "CLOUDRU_SECRET", Description: `Key Secret`},
+			{Name: "CLOUDRU_SERVICE_INSTANCE_ID", Description: `Service Instance ID (parentId)`},
+		}
+	case "cloudxns":
+		return []envVar{
+			{Name: "CLOUDXNS_API_KEY", Description: `The API key`},
+			{Name: "CLOUDXNS_SECRET_KEY", Description: `The API secret key`},
+		}
+	case "conoha":
+		return []envVar{
+			{Name: "CONOHA_API_PASSWORD", Description: `The API password`},
+			{Name: "CONOHA_API_USERNAME", Description: `The API username`},
+			{Name: "CONOHA_TENANT_ID", Description: `Tenant ID`},
+		}
+	case "conohav3":
+		return []envVar{
+			{Name: "CONOHAV3_API_PASSWORD", Description: `The API password`},
+			{Name: "CONOHAV3_API_USER_ID", Description: `The API user ID`},
+			{Name: "CONOHAV3_TENANT_ID", Description: `Tenant ID`},
+		}
+	case "constellix":
+		return []envVar{
+			{Name: "CONSTELLIX_API_KEY", Description: `User API key`},
+			{Name: "CONSTELLIX_SECRET_KEY", Description: `User secret key`},
+		}
+	case "corenetworks":
+		return []envVar{
+			{Name: "CORENETWORKS_LOGIN", Description: `The username of the API account`},
+			{Name: "CORENETWORKS_PASSWORD", Description: `The password`},
+		}
+	case "cpanel":
+		return []envVar{
+			{Name: "CPANEL_BASE_URL", Description: `API server URL`},
+			{Name: "CPANEL_TOKEN", Description: `API token`},
+			{Name: "CPANEL_USERNAME", Description: `username`},
+		}
+	case "derak":
+		return []envVar{
+			{Name: "DERAK_API_KEY", Description: `The API key`},
+		}
+	case "desec":
+		return []envVar{
+			{Name: "DESEC_TOKEN", Description: `Domain token`},
+		}
+	case "designate":
+		return []envVar{
+			{Name: "OS_APPLICATION_CREDENTIAL_ID", Description: `Application credential ID`},
+			{Name: "OS_APPLICATION_CREDENTIAL_NAME", Description: `Application credential name`},
+			{Name: "OS_APPLICATION_CREDENTIAL_SECRET", Description: `Application credential secret`},
+			{Name: "OS_AUTH_URL", Description: `Identity endpoint URL`},
+			{Name: "OS_PASSWORD", Description: `Password`},
+			{Name: "OS_PROJECT_NAME", Description: `Project name`},
+			{Name: "OS_REGION_NAME", Description: `Region name`},
+			{Name: "OS_USERNAME", Description: `Username`},
+			{Name: "OS_USER_ID", Description: `User ID`},
+		}
+	case "digitalocean":
+		return []envVar{
+			{Name: "DO_AUTH_TOKEN", Description: `Authentication token`},
+		}
+	case "directadmin":
+		return []envVar{
+			{Name: "DIRECTADMIN_API_URL", Description: `URL of the API`},
+			{Name: "DIRECTADMIN_PASSWORD", Description: `API password`},
+			{Name: "DIRECTADMIN_USERNAME", Description: `API username`},
+		}
+	case "dnshomede":
+		return []envVar{
+			{Name: "DNSHOMEDE_CREDENTIALS", Description: `Comma-separated list of domain:password credential pairs`},
+		}
+	case "dnsimple":
+		return []envVar{
+			{Name: "DNSIMPLE_OAUTH_TOKEN", Description: `OAuth token`},
+		}
+	case "dnsmadeeasy":
+		return []envVar{
+			{Name: "DNSMADEEASY_API_KEY", Description: `The API key`},
+			{Name: "DNSMADEEASY_API_SECRET", Description: `The API Secret key`},
+		}
+	case "dnspod":
+		return []envVar{
+			{Name: "DNSPOD_API_KEY", Description: `The user token`},
+		}
+	case "dode":
+		return []envVar{
+			{Name: "DODE_TOKEN", Description: `API token`},
+		}
+	case "domeneshop":
+		return []envVar{
+			{Name: "DOMENESHOP_API_SECRET", Description: `API secret`},
+			{Name: "DOMENESHOP_API_TOKEN", Description: `API token`},
+		}
+	case "dreamhost":
+		return []envVar{
+			{Name: "DREAMHOST_API_KEY", Description: `The API key`},
+		}
+	case "duckdns":
+		return []envVar{
+			{Name: "DUCKDNS_TOKEN", Description: `Account token`},
+		}
+	case "dyn":
+		return []envVar{
+			{Name: "DYN_CUSTOMER_NAME", Description: `Customer name`},
+			{Name: "DYN_PASSWORD", Description: `Password`},
+			{Name: "DYN_USER_NAME", Description: `User name`},
+		}
+	case "dyndnsfree":
+		return []envVar{
+			{Name: "DYNDNSFREE_PASSWORD", Description: `Password`},
+			{Name: "DYNDNSFREE_USERNAME", Description: `Username`},
+		}
+	case "dynu":
+		return []envVar{
+			{Name: "DYNU_API_KEY", Description: `API key`},
+		}
+	case "easydns":
+		return []envVar{
+			{Name: "EASYDNS_KEY", Description: `API Key`},
+			{Name: "EASYDNS_TOKEN", Description: `API Token`},
+		}
+	case "edgecenter":
+		return []envVar{
+			{Name: "EDGECENTER_PERMANENT_API_TOKEN", Description: `Permanent API token (https://edgecenter.ru/blog/permanent-api-token-explained/)`},
+		}
+	case "edgedns":
+		return []envVar{
+			{Name: "AKAMAI_ACCESS_TOKEN", Description: `Access token, managed by the Akamai EdgeGrid client`},
+			{Name: "AKAMAI_CLIENT_SECRET", Description: `Client secret, managed by the Akamai EdgeGrid client`},
+			{Name: "AKAMAI_CLIENT_TOKEN", Description: `Client token, managed by the Akamai EdgeGrid client`},
+			{Name: "AKAMAI_EDGERC", Description: `Path to the .edgerc file, managed by the Akamai EdgeGrid client`},
+			{Name: "AKAMAI_EDGERC_SECTION", Description: `Configuration section, managed by the Akamai EdgeGrid client`},
+			{Name: "AKAMAI_HOST", Description: `API host, managed by the Akamai EdgeGrid client`},
+		}
+	case "edgeone":
+		return []envVar{
+			{Name: "EDGEONE_SECRET_ID", Description: `Access key ID`},
+			{Name: "EDGEONE_SECRET_KEY", Description: `Access Key secret`},
+		}
+	case "efficientip":
+		return []envVar{
+			{Name: "EFFICIENTIP_DNS_NAME", Description: `DNS name (ex: dns.smart)`},
+			{Name: "EFFICIENTIP_HOSTNAME", Description: `Hostname (ex: foo.example.com)`},
+			{Name: "EFFICIENTIP_PASSWORD", Description: `Password`},
+			{Name: "EFFICIENTIP_USERNAME", Description: `Username`},
+		}
+	case "epik":
+		return []envVar{
+			{Name: "EPIK_SIGNATURE", Description: `Epik API signature (https://registrar.epik.com/account/api-settings/)`},
+		}
+	case "exoscale":
+		return []envVar{
+			{Name: "EXOSCALE_API_KEY", Description: `API key`},
+			{Name: "EXOSCALE_API_SECRET", Description: `API secret`},
+		}
+	case "f5xc":
+		return []envVar{
+			{Name: "F5XC_API_TOKEN", Description: `API token`},
+			{Name: "F5XC_GROUP_NAME", Description: `Group name`},
+			{Name: "F5XC_TENANT_NAME", Description: `XC Tenant shortname`},
+		}
+	case "freemyip":
+		return []envVar{
+			{Name: "FREEMYIP_TOKEN", Description: `Account token`},
+		}
+	case "gandi":
+		return []envVar{
+			{Name: "GANDI_API_KEY", Description: `API key`},
+		}
+	case "gandiv5":
+		return []envVar{
+			{Name: "GANDIV5_API_KEY", Description: `API key (Deprecated)`},
+			{Name: "GANDIV5_PERSONAL_ACCESS_TOKEN", Description: `Personal Access Token`},
+		}
+	case "gcloud":
+		return []envVar{
+			{Name: "Application Default Credentials", Description: `[Documentation](https://cloud.google.com/docs/authentication/production#providing_credentials_to_your_application)`},
+			{Name: "GCE_PROJECT", Description: `Project name (by default, the project name is auto-detected by using the metadata service)`},
+			{Name: "GCE_SERVICE_ACCOUNT", Description: `Account`},
+			{Name: "GCE_SERVICE_ACCOUNT_FILE", Description: `Account file path`},
+		}
+	case "gcore":
+		return []envVar{
+			{Name: "GCORE_PERMANENT_API_TOKEN", Description: `Permanent API token (https://gcore.com/blog/permanent-api-token-explained/)`},
+		}
+	case "gigahostno":
+		return []envVar{
+			{Name: "GIGAHOSTNO_PASSWORD", Description: `Password`},
+			{Name: "GIGAHOSTNO_USERNAME", Description: `Username`},
+		}
+	case "glesys":
+		return []envVar{
+			{Name: "GLESYS_API_KEY", Description: `API key`},
+			{Name: "GLESYS_API_USER", Description: `API user`},
+		}
+	case "godaddy":
+		return []envVar{
+			{Name: "GODADDY_API_KEY", Description: `API key`},
+			{Name: "GODADDY_API_SECRET", Description: `API secret`},
+		}
+	case "googledomains":
+		return []envVar{
+			{Name: "GOOGLE_DOMAINS_ACCESS_TOKEN", Description: `Access token`},
+		}
+	case "gravity":
+		return []envVar{
+			{Name: "GRAVITY_PASSWORD", Description: `Password`},
+			{Name: "GRAVITY_SERVER_URL", Description: `URL of the server`},
+			{Name: "GRAVITY_USERNAME", Description: `Username`},
+		}
+	case "hetzner":
+		return []envVar{
+			{Name: "HETZNER_API_TOKEN", Description: `API token`},
+		}
+	case "hostingde":
+		return []envVar{
+			{Name: "HOSTINGDE_API_KEY", Description: `API key`},
+		}
+	case "hostinger":
+		return []envVar{
+			{Name: "HOSTINGER_API_TOKEN", Description: `API Token`},
+		}
+	case "hosttech":
+		return []envVar{
+			{Name: "HOSTTECH_API_KEY", Description: `API login`},
+			{Name: "HOSTTECH_PASSWORD", Description: `API password`},
+		}
+	case "httpnet":
+		return []envVar{
+			{Name: "HTTPNET_API_KEY", Description: `API key`},
+		}
+	case "httpreq":
+		return []envVar{
+			{Name: "HTTPREQ_ENDPOINT", Description: `The URL of the server`},
+			{Name: "HTTPREQ_MODE", Description: `'RAW', none`},
+		}
+	case "huaweicloud":
+		return []envVar{
+			{Name: "HUAWEICLOUD_ACCESS_KEY_ID", Description: `Access key ID`},
+			{Name: "HUAWEICLOUD_REGION", Description: `Region`},
+			{Name: "HUAWEICLOUD_SECRET_ACCESS_KEY", Description: `Access Key secret`},
+		}
+	case "hurricane":
+		return []envVar{
+			{Name: "HURRICANE_TOKENS", Description: `TXT record names and tokens`},
+		}
+	case "ibmcloud":
+		return []envVar{
+			{Name: "SOFTLAYER_API_KEY", Description: `Classic Infrastructure API key`},
+			{Name: "SOFTLAYER_USERNAME", Description: `Username (IBM Cloud is {accountID}_{emailAddress})`},
+		}
+	case "iij":
+		return []envVar{
+			{Name: "IIJ_API_ACCESS_KEY", Description: `API access key`},
+			{Name: "IIJ_API_SECRET_KEY", Description: `API secret key`},
+			{Name: "IIJ_DO_SERVICE_CODE", Description: `DO service code`},
+		}
+	case "iijdpf":
+		return []envVar{
+			{Name: "IIJ_DPF_API_TOKEN", Description: `API token`},
+			{Name: "IIJ_DPF_DPM_SERVICE_CODE", Description: `IIJ Managed DNS Service's service code`},
+		}
+	case "infoblox":
+		return []envVar{
+			{Name: "INFOBLOX_HOST", Description: `Host URI`},
+			{Name: "INFOBLOX_PASSWORD", Description: `Account Password`},
+			{Name: "INFOBLOX_USERNAME", Description: `Account Username`},
+		}
+	case "infomaniak":
+		return []envVar{
+			{Name: "INFOMANIAK_ACCESS_TOKEN", Description: `Access token`},
+		}
+	case "internetbs":
+		return []envVar{
+			{Name: "INTERNET_BS_API_KEY", Description: `API key`},
+			{Name: "INTERNET_BS_PASSWORD", Description: `API password`},
+		}
+	case "inwx":
+		return []envVar{
+			{Name: "INWX_PASSWORD", Description: `Password`},
+			{Name: "INWX_USERNAME", Description: `Username`},
+		}
+	case "ionos":
+		return []envVar{
+			{Name: "IONOS_API_KEY", Description: `API key '<prefix>.<secret>' https://developer.hosting.ionos.com/docs/getstarted`},
+		}
+	case "ipv64":
+		return []envVar{
+			{Name: "IPV64_API_KEY", Description: `Account API Key`},
+		}
+	case "iwantmyname":
+		return []envVar{
+			{Name: "IWANTMYNAME_PASSWORD", Description: `API password`},
+			{Name: "IWANTMYNAME_USERNAME", Description: `API username`},
+		}
+	case "joker":
+		return []envVar{
+			{Name: "JOKER_API_KEY", Description: `API key (only with DMAPI mode)`},
+			{Name: "JOKER_API_MODE", Description: `'DMAPI' or 'SVC'. DMAPI is for resellers accounts. (Default: DMAPI)`},
+			{Name: "JOKER_PASSWORD", Description: `Joker.com password`},
+			{Name: "JOKER_USERNAME", Description: `Joker.com username`},
+		}
+	case "keyhelp":
+		return []envVar{
+			{Name: "KEYHELP_API_KEY", Description: `API key`},
+			{Name: "KEYHELP_BASE_URL", Description: `Server URL`},
+		}
+	case "liara":
+		return []envVar{
+			{Name: "LIARA_API_KEY", Description: `The API key`},
+		}
+	case "lightsail":
+		return []envVar{
+			{Name: "AWS_ACCESS_KEY_ID", Description: `Managed by the AWS client. Access key ID ('AWS_ACCESS_KEY_ID_FILE' is not supported, use 'AWS_SHARED_CREDENTIALS_FILE' instead)`},
+			{Name: "AWS_SECRET_ACCESS_KEY", Description: `Managed by the AWS client. Secret access key ('AWS_SECRET_ACCESS_KEY_FILE' is not supported, use 'AWS_SHARED_CREDENTIALS_FILE' instead)`},
+			{Name: "DNS_ZONE", Description: `Domain name of the DNS zone`},
+		}
+	case "limacity":
+		return []envVar{
+			{Name: "LIMACITY_API_KEY", Description: `The API key`},
+		}
+	case "linode":
+		return []envVar{
+			{Name: "LINODE_TOKEN", Description: `API token`},
+		}
+	case "liquidweb":
+		return []envVar{
+			{Name: "LWAPI_PASSWORD", Description: `Liquid Web API Password`},
+			{Name: "LWAPI_USERNAME", Description: `Liquid Web API Username`},
+		}
+	case "loopia":
+		return []envVar{
+			{Name: "LOOPIA_API_PASSWORD", Description: `API password`},
+			{Name: "LOOPIA_API_USER", Description: `API username`},
+		}
+	case "luadns":
+		return []envVar{
+			{Name: "LUADNS_API_TOKEN", Description: `API token`},
+			{Name: "LUADNS_API_USERNAME", Description: `Username (your email)`},
+		}
+	case "mailinabox":
+		return []envVar{
+			{Name: "MAILINABOX_BASE_URL", Description: `Base API URL (ex: https://box.example.com)`},
+			{Name: "MAILINABOX_EMAIL", Description: `User email`},
+			{Name: "MAILINABOX_PASSWORD", Description: `User password`},
+		}
+	case "manageengine":
+		return []envVar{
+			{Name: "MANAGEENGINE_CLIENT_ID", Description: `Client ID`},
+			{Name: "MANAGEENGINE_CLIENT_SECRET", Description: `Client Secret`},
+		}
+	case "metaname":
+		return []envVar{
+			{Name: "METANAME_ACCOUNT_REFERENCE", Description: `The four-digit reference of a Metaname account`},
+			{Name: "METANAME_API_KEY", Description: `API Key`},
+		}
+	case "metaregistrar":
+		return []envVar{
+			{Name: "METAREGISTRAR_API_TOKEN", Description: `The API token`},
+		}
+	case "mijnhost":
+		return []envVar{
+			{Name: "MIJNHOST_API_KEY", Description: `The API key`},
+		}
+	case "mittwald":
+		return []envVar{
+			{Name: "MITTWALD_TOKEN", Description: `API token`},
+		}
+	case "myaddr":
+		return []envVar{
+			{Name: "MYADDR_PRIVATE_KEYS_MAPPING", Description: `Mapping between subdomains and private keys. The format is: '<subdomain1>:<private_key1>,<subdomain2>:<private_key2>,<subdomain3>:<private_key3>'`},
+		}
+	case "mydnsjp":
+		return []envVar{
+			{Name: "MYDNSJP_MASTER_ID", Description: `Master ID`},
+			{Name: "MYDNSJP_PASSWORD", Description: `Password`},
+		}
+	case "mythicbeasts":
+		return []envVar{
+			{Name: "MYTHICBEASTS_PASSWORD", Description: `Password`},
+			{Name: "MYTHICBEASTS_USERNAME", Description: `User name`},
+		}
+	case "namecheap":
+		return []envVar{
+			{Name: "NAMECHEAP_API_KEY", Description: `API key`},
+			{Name: "NAMECHEAP_API_USER", Description: `API user`},
+		}
+	case "namedotcom":
+		return []envVar{
+			{Name: "NAMECOM_API_TOKEN", Description: `API token`},
+			{Name: "NAMECOM_USERNAME", Description: `Username`},
+		}
+	case "namesilo":
+		return []envVar{
+			{Name: "NAMESILO_API_KEY", Description: `Client ID`},
+		}
+	case "nearlyfreespeech":
+		return []envVar{
+			{Name: "NEARLYFREESPEECH_API_KEY", Description: `API Key for API requests`},
+			{Name: "NEARLYFREESPEECH_LOGIN", Description: `Username for API requests`},
+		}
+	case "neodigit":
+		return []envVar{
+			{Name: "NEODIGIT_TOKEN", Description: `API token`},
+		}
+	case "netcup":
+		return []envVar{
+			{Name: "NETCUP_API_KEY", Description: `API key`},
+			{Name: "NETCUP_API_PASSWORD", Description: `API password`},
+			{Name: "NETCUP_CUSTOMER_NUMBER", Description: `Customer number`},
+		}
+	case "netlify":
+		return []envVar{
+			{Name: "NETLIFY_TOKEN", Description: `Token`},
+		}
+	case "nicmanager":
+		return []envVar{
+			{Name: "NICMANAGER_API_EMAIL", Description: `Email-based login`},
+			{Name: "NICMANAGER_API_LOGIN", Description: `Login, used for Username-based login`},
+			{Name: "NICMANAGER_API_PASSWORD", Description: `Password, always required`},
+			{Name: "NICMANAGER_API_USERNAME", Description: `Username, used for Username-based login`},
+		}
+	case "nicru":
+		return []envVar{
+			{Name: "NICRU_PASSWORD", Description: `Password for an account in RU CENTER`},
+			{Name: "NICRU_SECRET", Description: `Secret for application in DNS-hosting RU CENTER`},
+			{Name: "NICRU_SERVICE_ID", Description: `Service ID for application in DNS-hosting RU CENTER`},
+			{Name: "NICRU_SERVICE_NAME", Description: `Service Name for DNS-hosting RU CENTER`},
+			{Name: "NICRU_USER", Description: `Agreement for an account in RU CENTER`},
+		}
+	case "nifcloud":
+		return []envVar{
+			{Name: "NIFCLOUD_ACCESS_KEY_ID", Description: `Access key`},
+			{Name: "NIFCLOUD_SECRET_ACCESS_KEY", Description: `Secret access key`},
+		}
+	case "njalla":
+		return []envVar{
+			{Name: "NJALLA_TOKEN", Description: `API token`},
+		}
+	case "nodion":
+		return []envVar{
+			{Name: "NODION_API_TOKEN", Description: `The API token`},
+		}
+	case "ns1":
+		return []envVar{
+			{Name: "NS1_API_KEY", Description: `API key`},
+		}
+	case "octenium":
+		return []envVar{
+			{Name: "OCTENIUM_API_KEY", Description: `API key`},
+		}
+	case "oraclecloud":
+		return []envVar{
+			{Name: "OCI_COMPARTMENT_OCID", Description: `Compartment OCID`},
+			{Name: "OCI_FINGERPRINT", Description: `Public key fingerprint (ignored if 'OCI_AUTH_TYPE=instance_principal')`},
+			{Name: "OCI_PRIVATE_KEY_PASSWORD", Description: `Private key password (ignored if 'OCI_AUTH_TYPE=instance_principal')`},
+			{Name: "OCI_PRIVATE_KEY_PATH", Description: `Private key file (ignored if 'OCI_AUTH_TYPE=instance_principal')`},
+			{Name: "OCI_REGION", Description: `Region (it can be empty if 'OCI_AUTH_TYPE=instance_principal').`},
+			{Name: "OCI_TENANCY_OCID", Description: `Tenancy OCID (ignored if 'OCI_AUTH_TYPE=instance_principal')`},
+			{Name: "OCI_USER_OCID", Description: `User OCID (ignored if 'OCI_AUTH_TYPE=instance_principal')`},
+		}
+	case "otc":
+		return []envVar{
+			{Name: "OTC_DOMAIN_NAME", Description: `Domain name`},
+			{Name: "OTC_PASSWORD", Description: `Password`},
+			{Name: "OTC_PROJECT_NAME", Description: `Project name`},
+			{Name: "OTC_USER_NAME", Description: `User name`},
+		}
+	case "ovh":
+		return []envVar{
+			{Name: "OVH_ACCESS_TOKEN", Description: `Access token`},
+			{Name: "OVH_APPLICATION_KEY", Description: `Application key (Application Key authentication)`},
+			{Name: "OVH_APPLICATION_SECRET", Description: `Application secret (Application Key authentication)`},
+			{Name: "OVH_CLIENT_ID", Description: `Client ID (OAuth2)`},
+			{Name: "OVH_CLIENT_SECRET", Description: `Client secret (OAuth2)`},
+			{Name: "OVH_CONSUMER_KEY", Description: `Consumer key (Application Key authentication)`},
+			{Name: "OVH_ENDPOINT", Description: `Endpoint URL (ovh-eu or ovh-ca)`},
+		}
+	case "pdns":
+		return []envVar{
+			{Name: "PDNS_API_KEY", Description: `API key`},
+			{Name: "PDNS_API_URL", Description: `API URL`},
+		}
+	case "plesk":
+		return []envVar{
+			{Name: "PLESK_PASSWORD", Description: `API password`},
+			{Name: "PLESK_SERVER_BASE_URL", Description: `Base URL of the server (ex: https://plesk.myserver.com:8443)`},
+			{Name: "PLESK_USERNAME", Description: `API username`},
+		}
+	case "porkbun":
+		return []envVar{
+			{Name: "PORKBUN_API_KEY", Description: `API key`},
+			{Name: "PORKBUN_SECRET_API_KEY", Description: `secret API key`},
+		}
+	case "rackspace":
+		return []envVar{
+			{Name: "RACKSPACE_API_KEY", Description: `API key`},
+			{Name: "RACKSPACE_USER", Description: `API user`},
+		}
+	case "rainyun":
+		return []envVar{
+			{Name: "RAINYUN_API_KEY", Description: `API key`},
+		}
+	case "rcodezero":
+		return []envVar{
+			{Name: "RCODEZERO_API_TOKEN", Description: `API token`},
+		}
+	case "regfish":
+		return []envVar{
+			{Name: "REGFISH_API_KEY", Description: `API key`},
+		}
+	case "regru":
+		return []envVar{
+			{Name: "REGRU_PASSWORD", Description: `API password`},
+			{Name: "REGRU_USERNAME", Description: `API username`},
+		}
+	case "rfc2136":
+		return []envVar{
+			{Name: "RFC2136_NAMESERVER", Description: `Network address in the form "host" or "host:port"`},
+			{Name: "RFC2136_TSIG_ALGORITHM", Description: `TSIG algorithm. See [miekg/dns#tsig.go](https://github.com/miekg/dns/blob/master/tsig.go) for supported values. To disable TSIG authentication, leave the 'RFC2136_TSIG_KEY' or 'RFC2136_TSIG_SECRET' variables unset.`},
+			{Name: "RFC2136_TSIG_KEY", Description: `Name of the secret key as defined in DNS server configuration. To disable TSIG authentication, leave the 'RFC2136_TSIG_KEY' variable unset.`},
+			{Name: "RFC2136_TSIG_SECRET", Description: `Secret key payload. To disable TSIG authentication, leave the 'RFC2136_TSIG_SECRET' variable unset.`},
+		}
+	case "rimuhosting":
+		return []envVar{
+			{Name: "RIMUHOSTING_API_KEY", Description: `User API key`},
+		}
+	case "route53":
+		return []envVar{
+			{Name: "AWS_ACCESS_KEY_ID", Description: `Managed by the AWS client. Access key ID ('AWS_ACCESS_KEY_ID_FILE' is not supported, use 'AWS_SHARED_CREDENTIALS_FILE' instead)`},
+			{Name: "AWS_ASSUME_ROLE_ARN", Description: `Managed by the AWS Role ARN ('AWS_ASSUME_ROLE_ARN_FILE' is not supported)`},
+			{Name: "AWS_EXTERNAL_ID", Description: `Managed by STS AssumeRole API operation ('AWS_EXTERNAL_ID_FILE' is not supported)`},
+			{Name: "AWS_HOSTED_ZONE_ID", Description: `Override the hosted zone ID.`},
+			{Name: "AWS_PROFILE", Description: `Managed by the AWS client ('AWS_PROFILE_FILE' is not supported)`},
+			{Name: "AWS_REGION", Description: `Managed by the AWS client ('AWS_REGION_FILE' is not supported)`},
+			{Name: "AWS_ROLE_CHAIN", Description: `Comma-separated list of role ARNs assumed in sequence, after 'AWS_ASSUME_ROLE_ARN'`},
+			{Name: "AWS_SDK_LOAD_CONFIG", Description: `Managed by the AWS client. Retrieve the region from the CLI config file ('AWS_SDK_LOAD_CONFIG_FILE' is not supported)`},
+			{Name: "AWS_SECRET_ACCESS_KEY", Description: `Managed by the AWS client. Secret access key ('AWS_SECRET_ACCESS_KEY_FILE' is not supported, use 'AWS_SHARED_CREDENTIALS_FILE' instead)`},
+			{Name: "AWS_SSO_ACCOUNT_ID", Description: `ID of the AWS account of the SSO role`},
+			{Name: "AWS_SSO_REGION", Description: `Region of the SSO portal (Default: the AWS region)`},
+			{Name: "AWS_SSO_ROLE_NAME", Description: `Name of the SSO role (permission set)`},
+			{Name: "AWS_SSO_SESSION", Description: `Name of the SSO session, used to find and refresh the cached token`},
+			{Name: "AWS_SSO_START_URL", Description: `URL of the SSO portal, used to find the cached token of a legacy SSO configuration`},
+			{Name: "AWS_WAIT_FOR_RECORD_SETS_CHANGED", Description: `Wait for changes to be INSYNC (it can be unstable)`},
+		}
+	case "routeros":
+		return []envVar{
+			{Name: "ROUTEROS_BASE_URL", Description: `The URL of the router (ex: https://192.168.88.1)`},
+			{Name: "ROUTEROS_PASSWORD", Description: `Password`},
+			{Name: "ROUTEROS_USERNAME", Description: `Username`},
+		}
+	case "safedns":
+		return []envVar{
+			{Name: "SAFEDNS_AUTH_TOKEN", Description: `Authentication token`},
+		}
+	case "sakuracloud":
+		return []envVar{
+			{Name: "SAKURACLOUD_ACCESS_TOKEN", Description: `Access token`},
+			{Name: "SAKURACLOUD_ACCESS_TOKEN_SECRET", Description: `Access token secret`},
+		}
+	case "scaleway":
+		return []envVar{
+			{Name: "SCW_PROJECT_ID", Description: `Project to use (optional)`},
+			{Name: "SCW_SECRET_KEY", Description: `Secret key`},
+		}
+	case "selectel":
+		return []envVar{
+			{Name: "SELECTEL_API_TOKEN", Description: `API token`},
+		}
+	case "selectelv2":
+		return []envVar{
+			{Name: "SELECTELV2_ACCOUNT_ID", Description: `Selectel account ID (INT)`},
+			{Name: "SELECTELV2_PASSWORD", Description: `Openstack username's password`},
+			{Name: "SELECTELV2_PROJECT_ID", Description: `Cloud project ID (UUID)`},
+			{Name: "SELECTELV2_USERNAME", Description: `Openstack username`},
+		}
+	case "selfhostde":
+		return []envVar{
+			{Name: "SELFHOSTDE_PASSWORD", Description: `Password`},
+			{Name: "SELFHOSTDE_RECORDS_MAPPING", Description: `Record IDs mapping with domains (ex: example.com:123:456,example.org:789,foo.example.com:147)`},
+			{Name: "SELFHOSTDE_USERNAME", Description: `Username`},
+		}
+	case "servercow":
+		return []envVar{
+			{Name: "SERVERCOW_PASSWORD", Description: `API password`},
+			{Name: "SERVERCOW_USERNAME", Description: `API username`},
+		}
+	case "shellrent":
+		return []envVar{
+			{Name: "SHELLRENT_TOKEN", Description: `Token`},
+			{Name: "SHELLRENT_USERNAME", Description: `Username`},
+		}
+	case "simply":
+		return []envVar{
+			{Name: "SIMPLY_ACCOUNT_NAME", Description: `Account name`},
+			{Name: "SIMPLY_API_KEY", Description: `API key`},
+		}
+	case "sonic":
+		return []envVar{
+			{Name: "SONIC_API_KEY", Description: `API Key`},
+			{Name: "SONIC_USER_ID", Description: `User ID`},
+		}
+	case "spaceship":
+		return []envVar{
+			{Name: "SPACESHIP_API_KEY", Description: `API key`},
+			{Name: "SPACESHIP_API_SECRET", Description: `API secret`},
+		}
+	case "stackpath":
+		return []envVar{
+			{Name: "STACKPATH_CLIENT_ID", Description: `Client ID`},
+			{Name: "STACKPATH_CLIENT_SECRET", Description: `Client secret`},
+			{Name: "STACKPATH_STACK_ID", Description: `Stack ID`},
+		}
+	case "syse":
+		return []envVar{
+			{Name: "SYSE_CREDENTIALS", Description: `Comma-separated list of 'zone:password' credential pairs`},
+		}
+	case "technitium":
+		return []envVar{
+			{Name: "TECHNITIUM_API_TOKEN", Description: `API token`},
+			{Name: "TECHNITIUM_SERVER_BASE_URL", Description: `Server base URL`},
+		}
+	case "tencentcloud":
+		return []envVar{
+			{Name: "TENCENTCLOUD_SECRET_ID", Description: `Access key ID`},
+			{Name: "TENCENTCLOUD_SECRET_KEY", Description: `Access Key secret`},
+		}
+	case "timewebcloud":
+		return []envVar{
+			{Name: "TIMEWEBCLOUD_AUTH_TOKEN", Description: `Authentication token`},
+		}
+	case "transip":
+		return []envVar{
+			{Name: "TRANSIP_ACCOUNT_NAME", Description: `Account name`},
+			{Name: "TRANSIP_PRIVATE_KEY_PATH", Description: `Private key path`},
+		}
+	case "ultradns":
+		return []envVar{
+			{Name: "ULTRADNS_PASSWORD", Description: `API Password`},
+			{Name: "ULTRADNS_USERNAME", Description: `API Username`},
+		}
+	case "unifi":
+		return []envVar{
+			{Name: "UNIFI_API_KEY", Description: `API key`},
+			{Name: "UNIFI_BASE_URL", Description: `The URL of the UniFi gateway (ex: https://192.168.1.1)`},
+		}
+	case "uniteddomains":
+		return []envVar{
+			{Name: "UNITEDDOMAINS_API_KEY", Description: `API key '<prefix>.<secret>' https://www.united-domains.de/help/faq-article/getting-started-with-the-united-domains-dns-api/`},
+		}
+	case "variomedia":
+		return []envVar{
+			{Name: "VARIOMEDIA_API_TOKEN", Description: `API token`},
+		}
+	case "vegadns":
+		return []envVar{
+			{Name: "SECRET_VEGADNS_KEY", Description: `API key`},
+			{Name: "SECRET_VEGADNS_SECRET", Description: `API secret`},
+			{Name: "VEGADNS_URL", Description: `API endpoint URL`},
+		}
+	case "vercel":
+		return []envVar{
+			{Name: "VERCEL_API_TOKEN", Description: `Authentication token`},
+		}
+	case "versio":
+		return []envVar{
+			{Name: "VERSIO_PASSWORD", Description: `Basic authentication password`},
+			{Name: "VERSIO_USERNAME", Description: `Basic authentication username`},
+		}
+	case "vinyldns":
+		return []envVar{
+			{Name: "VINYLDNS_ACCESS_KEY", Description: `The VinylDNS API key`},
+			{Name: "VINYLDNS_HOST", Description: `The VinylDNS API URL`},
+			{Name: "VINYLDNS_SECRET_KEY", Description: `The VinylDNS API Secret key`},
+		}
+	case "virtualname":
+		return []envVar{
+			{Name: "VIRTUALNAME_TOKEN", Description: `API token`},
+		}
+	case "vkcloud":
+		return []envVar{
+			{Name: "VK_CLOUD_PASSWORD", Description: `Password for VK Cloud account`},
+			{Name: "VK_CLOUD_PROJECT_ID", Description: `String ID of project in VK Cloud`},
+			{Name: "VK_CLOUD_USERNAME", Description: `Email of VK Cloud account`},
+		}
+	case "volcengine":
+		return []envVar{
+			{Name: "VOLC_ACCESSKEY", Description: `Access Key ID (AK)`},
+			{Name: "VOLC_SECRETKEY", Description: `Secret Access Key (SK)`},
+		}
+	case "vscale":
+		return []envVar{
+			{Name: "VSCALE_API_TOKEN", Description: `API token`},
+		}
+	case "vultr":
+		return []envVar{
+			{Name: "VULTR_API_KEY", Description: `API key`},
+		}
+	case "webnames":
+		return []envVar{
+			{Name: "WEBNAMESRU_API_KEY", Description: `Domain API key`},
+		}
+	case "webnamesca":
+		return []envVar{
+			{Name: "WEBNAMESCA_API_KEY", Description: `API key`},
+			{Name: "WEBNAMESCA_API_USER", Description: `API username`},
+		}
+	case "websupport":
+		return []envVar{
+			{Name: "WEBSUPPORT_API_KEY", Description: `API key`},
+			{Name: "WEBSUPPORT_SECRET", Description: `API secret`},
+		}
+	case "wedos":
+		return []envVar{
+			{Name: "WEDOS_USERNAME", Description: `Username is the same as for the admin account`},
+			{Name: "WEDOS_WAPI_PASSWORD", Description: `Password needs to be generated and IP allowed in the admin interface`},
+		}
+	case "westcn":
+		return []envVar{
+			{Name: "WESTCN_PASSWORD", Description: `API password`},
+			{Name: "WESTCN_USERNAME", Description: `Username`},
+		}
+	case "yandex":
+		return []envVar{
+			{Name: "YANDEX_PDD_TOKEN", Description: `Basic authentication username`},
+		}
+	case "yandex360":
+		return []envVar{
+			{Name: "YANDEX360_OAUTH_TOKEN", Description: `The OAuth Token`},
+			{Name: "YANDEX360_ORG_ID", Description: `The organization ID`},
+		}
+	case "yandexcloud":
+		return []envVar{
+			{Name: "YANDEX_CLOUD_FOLDER_ID", Description: `The string id of folder (aka project) in Yandex Cloud`},
+			{Name: "YANDEX_CLOUD_IAM_TOKEN", Description: `The base64 encoded json which contains information about iam token of service account with 'dns.admin' permissions`},
+		}
+	case "zoneedit":
+		return []envVar{
+			{Name: "ZONEEDIT_AUTH_TOKEN", Description: `Authentication token`},
+			{Name: "ZONEEDIT_USER", Description: `User ID`},
+		}
+	case "zoneee":
+		return []envVar{
+			{Name: "ZONEEE_API_KEY", Description: `API key`},
+			{Name: "ZONEEE_API_USER", Description: `API user`},
+		}
+	case "zonomi":
+		return []envVar{
+			{Name: "ZONOMI_API_KEY", Description: `User API key`},
+		}
+	default:
+		return nil
+	}
+}
@@ -57,6 +57,13 @@ func generate() error {
 		{"lego", "help", "renew"},
 		{"lego", "help", "revoke"},
 		{"lego", "help", "list"},
+		{"lego", "help", "server"},
+		{"lego", "help", "plan"},
+		{"lego", "help", "cleanup"},
+		{"lego", "help", "setup"},
+		{"lego", "accounts", "help", "export"},
+		{"lego", "accounts", "help", "import"},
+		{"lego", "help", "completion"},
 		{"lego", "dnshelp"},
 	} {
 		content, err := run(app, args)
@@ -0,0 +1,132 @@
+package lego
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Directory URLs of the CAs with known quirks.
+const (
+	// BuypassDirectoryProduction URL to the Buypass Go SSL production.
+	BuypassDirectoryProduction = "https://api.buypass.com/acme/directory"
+	// BuypassDirectoryStaging URL to the Buypass Go SSL staging.
+	BuypassDirectoryStaging = "https://api.test4.buypass.no/acme/directory"
+
+	// SSLcomDirectoryRSA URL to the SSL.com DV RSA production.
+	SSLcomDirectoryRSA = "https://acme.ssl.com/sslcom-dv-rsa"
+	// SSLcomDirectoryECC URL to the SSL.com DV ECC production.
+	SSLcomDirectoryECC = "https://acme.ssl.com/sslcom-dv-ecc"
+
+	// ActalisDirectoryProduction URL to the Actalis production.
+	ActalisDirectoryProduction = "https://acme-api.actalis.com/acme/directory"
+
+	// ZeroSSLDirectory URL to the ZeroSSL production.
+	ZeroSSLDirectory = "https://acme.zerossl.com/v2/DV90"
+)
+
+// CAQuirks the known deviations of a CA from the behavior of Let's Encrypt,
+// and the known limits of a CA that are not advertised by its directory.
+type CAQuirks struct {
+	// Name the name of the CA.
+	Name string
+
+	// ContactRequired the CA rejects the accounts without an email address.
+	ContactRequired bool
+
+	// EABRequired the CA requires an External Account Binding,
+	// the requirement is not always advertised by the directory (`meta.externalAccountRequired`).
+	EABRequired bool
+
+	// CertificateTimeout the minimum time to wait for the certificate:
+	// the orders can stay in the `processing` state for several minutes after the finalization.
+	CertificateTimeout time.Duration
+
+	// MaxValidity the maximum validity of the certificates (RFC 8555 doesn't define a way to advertise it).
+	MaxValidity time.Duration
+
+	// ProfileValidities the maximum validity of the certificates of the profiles (draft-ietf-acme-profiles),
+	// when it differs from MaxValidity.
+	ProfileValidities map[string]time.Duration
+}
+
+// GetMaxValidity returns the maximum validity of the certificates of a profile (empty for the default profile),
+// or 0 if the maximum validity is unknown.
+func (q CAQuirks) GetMaxValidity(profile string) time.Duration {
+	if validity, ok := q.ProfileValidities[profile]; ok {
+		return validity
+	}
+
+	return q.MaxValidity
+}
+
+// caQuirks the quirks of the CAs (the key is the normalized directory URL).
+var caQuirks = map[string]CAQuirks{}
+
+func init() {
+	letsEncrypt := CAQuirks{
+		Name:        "Let's Encrypt",
+		MaxValidity: 90 * 24 * time.Hour,
+		ProfileValidities: map[string]time.Duration{
+			"shortlived": 160 * time.Hour,
+		},
+	}
+
+	zeroSSL := CAQuirks{
+		Name:        "ZeroSSL",
+		MaxValidity: 90 * 24 * time.Hour,
+	}
+
+	buypass := CAQuirks{
+		Name:            "Buypass Go SSL",
+		ContactRequired: true,
+	}
+
+	sslcom := CAQuirks{
+		Name:               "SSL.com",
+		ContactRequired:    true,
+		EABRequired:        true,
+		CertificateTimeout: 2 * time.Minute,
+	}
+
+	actalis := CAQuirks{
+		Name:               "Actalis",
+		ContactRequired:    true,
+		EABRequired:        true,
+		CertificateTimeout: 2 * time.Minute,
+	}
+
+	for dirURL, quirks := range map[string]CAQuirks{
+		LEDirectoryProduction:      letsEncrypt,
+		LEDirectoryStaging:         letsEncrypt,
+		ZeroSSLDirectory:           zeroSSL,
+		BuypassDirectoryProduction: buypass,
+		BuypassDirectoryStaging:    buypass,
+		SSLcomDirectoryRSA:         sslcom,
+		SSLcomDirectoryECC:         sslcom,
+		ActalisDirectoryProduction: actalis,
+	} {
+		caQuirks[normalizeDirectoryURL(dirURL)] = quirks
+	}
+}
+
+// LookupCAQuirks returns the known quirks of a CA from its directory URL.
+func LookupCAQuirks(dirURL string) (CAQuirks, bool) {
+	quirks, ok := caQuirks[normalizeDirectoryURL(dirURL)]
+
+	return quirks, ok
+}
+
+// normalizeDirectoryURL lowercases the scheme and the host, and removes the default port and the trailing slash.
+func normalizeDirectoryURL(dirURL string) string {
+	u, err := url.Parse(strings.TrimSpace(dirURL))
+	if err != nil {
+		return dirURL
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(strings.TrimSuffix(u.Host, ":443"))
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	return u.String()
+}
@@ -0,0 +1,110 @@
+package lego
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupCAQuirks(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		dirURL   string
+		expected string
+	}{
+		{
+			desc:     "exact URL",
+			dirURL:   BuypassDirectoryProduction,
+			expected: "Buypass Go SSL",
+		},
+		{
+			desc:     "trailing slash",
+			dirURL:   SSLcomDirectoryRSA + "/",
+			expected: "SSL.com",
+		},
+		{
+			desc:     "uppercase host and default port",
+			dirURL:   "https://ACME-API.actalis.com:443/acme/directory",
+			expected: "Actalis",
+		},
+		{
+			desc:     "known limits only",
+			dirURL:   LEDirectoryStaging,
+			expected: "Let's Encrypt",
+		},
+		{
+			desc:   "unknown CA",
+			dirURL: "https://ca.example.com/acme/directory",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			quirks, ok := LookupCAQuirks(test.dirURL)
+
+			assert.Equal(t, test.expected != "", ok)
+			assert.Equal(t, test.expected, quirks.Name)
+		})
+	}
+}
+
+func TestCAQuirks_GetMaxValidity(t *testing.T) {
+	quirks, ok := LookupCAQuirks(LEDirectoryProduction)
+	require.True(t, ok)
+
+	assert.Equal(t, 90*24*time.Hour, quirks.GetMaxValidity(""))
+	assert.Equal(t, 160*time.Hour, quirks.GetMaxValidity("shortlived"))
+	assert.Equal(t, 90*24*time.Hour, quirks.GetMaxValidity("tlsserver"))
+
+	assert.Zero(t, CAQuirks{}.GetMaxValidity(""))
+}
+
+// TestCAQuirks_live checks that the directories of the CAs don't contradict the registry.
+func TestCAQuirks_live(t *testing.T) {
+	if os.Getenv("LEGO_CA_QUIRKS_LIVE_TEST") == "" {
+		t.Skip("skipping live test")
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	for _, dirURL := range []string{
+		LEDirectoryStaging,
+		ZeroSSLDirectory,
+		BuypassDirectoryStaging,
+		SSLcomDirectoryRSA,
+		SSLcomDirectoryECC,
+		ActalisDirectoryProduction,
+	} {
+		t.Run(dirURL, func(t *testing.T) {
+			resp, err := client.Get(dirURL)
+			require.NoError(t, err)
+
+			defer func() { _ = resp.Body.Close() }()
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var dir acme.Directory
+
+			err = json.NewDecoder(resp.Body).Decode(&dir)
+			require.NoError(t, err)
+
+			assert.NotEmpty(t, dir.NewAccountURL)
+			assert.NotEmpty(t, dir.NewOrderURL)
+
+			quirks, ok := LookupCAQuirks(dirURL)
+			require.True(t, ok)
+
+			if dir.Meta.ExternalAccountRequired {
+				assert.True(t, quirks.EABRequired, "the directory requires an EAB")
+			}
+		})
+	}
+}
@@ -1,25 +1,35 @@
 package lego
 
 import (
+	"crypto"
 	"errors"
 	"net/url"
 
 	"github.com/go-acme/lego/v4/acme/api"
 	"github.com/go-acme/lego/v4/certificate"
 	"github.com/go-acme/lego/v4/challenge/resolver"
+	"github.com/go-acme/lego/v4/log"
 	"github.com/go-acme/lego/v4/registration"
 )
 
 // Client is the user-friendly way to ACME.
+//
+// A Client is safe for concurrent use by multiple goroutines
+// (ex: obtaining several certificates at the same time, or setting the challenge providers while obtaining certificates),
+// as long as the challenge providers used are safe for concurrent use.
+// The built-in servers (HTTP-01 and TLS-ALPN-01) listen on a single port,
+// so the challenges of concurrent orders using them can conflict.
 type Client struct {
 	Certificate  *certificate.Certifier
 	Challenge    *resolver.SolverManager
 	Registration *registration.Registrar
 	core         *api.Core
+
+	quirks CAQuirks
 }
 
 // NewClient creates a new ACME client on behalf of the user.
-// The client will depend on the ACME directory located at CADirURL for the rest of its actions.
+// The client will depend on the ACME directory located at CADirURL (or on the endpoints of Config.Directory) for the rest of its actions.
 // A private key of type keyType (see KeyType constants) will be generated when requesting a new certificate if one isn't provided.
 func NewClient(config *Config) (*Client, error) {
 	if config == nil {
@@ -45,12 +55,21 @@ func NewClient(config *Config) (*Client, error) {
 		kid = reg.URI
 	}
 
-	core, err := api.New(config.HTTPClient, config.UserAgent, config.CADirURL, kid, privateKey)
+	core, err := newCore(config, kid, privateKey)
 	if err != nil {
 		return nil, err
 	}
 
+	if config.StrictMode {
+		core.SetStrictMode(nil)
+	}
+
+	if config.RawJSON {
+		core.SetRawJSON(true)
+	}
+
 	solversManager := resolver.NewSolversManager(core)
+	solversManager.SetLogger(config.Logger)
 
 	prober := resolver.NewProber(solversManager)
 
@@ -59,6 +78,27 @@ func NewClient(config *Config) (*Client, error) {
 		Timeout:             config.Certificate.Timeout,
 		OverallRequestLimit: config.Certificate.OverallRequestLimit,
 		DisableCommonName:   config.Certificate.DisableCommonName,
+
+		DownloadRetries:       config.Certificate.DownloadRetries,
+		DownloadRetryInterval: config.Certificate.DownloadRetryInterval,
+	}
+
+	registrar := registration.NewRegistrar(core, config.User)
+
+	quirks, ok := LookupCAQuirks(config.CADirURL)
+	if ok {
+		options.MaxValidity = quirks.MaxValidity
+
+		if options.Timeout < quirks.CertificateTimeout {
+			log.Infof("The certificate timeout is increased to %s for %s.", quirks.CertificateTimeout, quirks.Name)
+			options.Timeout = quirks.CertificateTimeout
+		}
+
+		registrar.SetRequirements(registration.Requirements{
+			CAName:  quirks.Name,
+			Contact: quirks.ContactRequired,
+			EAB:     quirks.EABRequired,
+		})
 	}
 
 	certifier := certificate.NewCertifier(core, prober, options)
@@ -66,17 +106,44 @@ func NewClient(config *Config) (*Client, error) {
 	return &Client{
 		Certificate:  certifier,
 		Challenge:    solversManager,
-		Registration: registration.NewRegistrar(core, config.User),
+		Registration: registrar,
 		core:         core,
+		quirks:       quirks,
 	}, nil
 }
 
+func newCore(config *Config, kid string, privateKey crypto.PrivateKey) (*api.Core, error) {
+	if config.Directory != nil {
+		return api.NewWithDirectory(config.HTTPClient, config.UserAgent, *config.Directory, kid, privateKey)
+	}
+
+	return api.NewWithDirectoryCache(config.HTTPClient, config.UserAgent, config.CADirURL, kid, privateKey, config.DirectoryCacheTTL)
+}
+
 // GetToSURL returns the current ToS URL from the Directory.
 func (c *Client) GetToSURL() string {
 	return c.core.GetDirectory().Meta.TermsOfService
 }
 
-// GetExternalAccountRequired returns the External Account Binding requirement of the Directory.
+// GetExternalAccountRequired returns the External Account Binding requirement of the Directory,
+// or of the known quirks of the CA.
 func (c *Client) GetExternalAccountRequired() bool {
-	return c.core.GetDirectory().Meta.ExternalAccountRequired
+	return c.core.GetDirectory().Meta.ExternalAccountRequired || c.quirks.EABRequired
+}
+
+// GetProfiles returns the certificate profiles advertised by the CA (draft-ietf-acme-profiles),
+// the names of the profiles associated with their descriptions.
+func (c *Client) GetProfiles() map[string]string {
+	return c.core.GetDirectory().Meta.Profiles
+}
+
+// GetCAQuirks returns the known quirks of the CA.
+func (c *Client) GetCAQuirks() CAQuirks {
+	return c.quirks
+}
+
+// GetKeyAuthorization returns the key authorization of a challenge token for the account key.
+// It allows to feed an external system (ex: a CDN API) with the expected challenge values.
+func (c *Client) GetKeyAuthorization(token string) (string, error) {
+	return c.core.GetKeyAuthorization(token)
 }
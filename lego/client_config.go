@@ -3,6 +3,7 @@ package lego
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -11,7 +12,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/log"
 	"github.com/go-acme/lego/v4/registration"
 )
 
@@ -33,6 +36,13 @@ const (
 	// the system-wide trusted root list.
 	caServerNameEnvVar = "LEGO_CA_SERVER_NAME"
 
+	// The connection reuse defaults of the HTTP client:
+	// the defaults of http.Transport (2 idle connections per host) open too many connections during large batches.
+	defaultMaxIdleConns        = 100
+	defaultMaxIdleConnsPerHost = 10
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultTLSSessionCacheSize = 64
+
 	// LEDirectoryProduction URL to the Let's Encrypt production.
 	LEDirectoryProduction = "https://acme-v02.api.letsencrypt.org/directory"
 
@@ -46,6 +56,32 @@ type Config struct {
 	UserAgent   string
 	HTTPClient  *http.Client
 	Certificate CertificateConfig
+
+	// DirectoryCacheTTL enables the cached-directory mode:
+	// the directory of the ACME server is cached by the process during this duration,
+	// the fetching of the directory is retried with a backoff when the ACME server is unavailable,
+	// and the expired directory is used if the ACME server is still unreachable.
+	// 0 disables the cache: the directory is fetched once by each call to NewClient.
+	DirectoryCacheTTL time.Duration
+
+	// Directory the endpoints of the ACME server, for the ACME servers without a directory document (ex: some embedded CAs).
+	// If not nil, the directory discovery is bypassed: CADirURL and DirectoryCacheTTL are not used to fetch the directory.
+	// The newNonce, newAccount, and newOrder URLs are required.
+	Directory *acme.Directory
+
+	// StrictMode enables the validation of the ACME server responses against RFC 8555.
+	// The violations are logged, they don't stop the process.
+	StrictMode bool
+
+	// RawJSON keeps the raw JSON objects returned by the ACME server
+	// in the Raw fields of the orders, authorizations, and challenges.
+	RawJSON bool
+
+	// Logger the structured logger (ex: a *slog.Logger) of the events of the challenges:
+	// the calls to the DNS provider, the propagation checks,
+	// and, for the DNS providers supporting it, the API requests and the retries.
+	// If nil, the events are not emitted: only the messages of the global logger (log.Logger) are written.
+	Logger log.StructuredLogger
 }
 
 func NewConfig(user registration.User) *Config {
@@ -65,6 +101,12 @@ type CertificateConfig struct {
 	Timeout             time.Duration
 	OverallRequestLimit int
 	DisableCommonName   bool
+
+	// DownloadRetries the number of retries of the certificate download when the certificate is not available yet.
+	// 0 uses the default value (certificate.DefaultDownloadRetries), a negative value disables the retries.
+	DownloadRetries int
+	// DownloadRetryInterval the initial interval between the retries of the certificate download.
+	DownloadRetryInterval time.Duration
 }
 
 // createDefaultHTTPClient Creates an HTTP client with a reasonable timeout value
@@ -81,14 +123,84 @@ func createDefaultHTTPClient() *http.Client {
 			}).DialContext,
 			TLSHandshakeTimeout:   30 * time.Second,
 			ResponseHeaderTimeout: 30 * time.Second,
+			MaxIdleConns:          defaultMaxIdleConns,
+			MaxIdleConnsPerHost:   defaultMaxIdleConnsPerHost,
+			IdleConnTimeout:       defaultIdleConnTimeout,
 			TLSClientConfig: &tls.Config{
-				ServerName: os.Getenv(caServerNameEnvVar),
-				RootCAs:    initCertPool(),
+				ServerName:         os.Getenv(caServerNameEnvVar),
+				RootCAs:            initCertPool(),
+				ClientSessionCache: tls.NewLRUClientSessionCache(defaultTLSSessionCacheSize),
 			},
 		},
 	}
 }
 
+// TransportConfig the tuning of the connections to the ACME server.
+// The zero values keep the current values of the transport.
+type TransportConfig struct {
+	// MaxIdleConns the maximum number of idle (keep-alive) connections across all hosts.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost the maximum number of idle (keep-alive) connections to keep per host.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout the maximum amount of time an idle connection remains open.
+	IdleConnTimeout time.Duration
+	// EnableHTTP2 enables HTTP/2 (the default client only uses HTTP/1.1).
+	EnableHTTP2 bool
+	// TLSSessionCacheSize the number of TLS sessions kept for the resumption.
+	// A negative value disables the TLS session resumption.
+	TLSSessionCacheSize int
+}
+
+// ConfigureTransport applies the TransportConfig to the transport of the HTTP client.
+// The transport must be an *http.Transport (ex: the transport of the default HTTP client of the Config).
+func ConfigureTransport(client *http.Client, config TransportConfig) error {
+	if client == nil {
+		return errors.New("the HTTP client is nil")
+	}
+
+	defaultTransport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("unsupported transport type: %T", client.Transport)
+	}
+
+	tr := defaultTransport.Clone()
+
+	if config.MaxIdleConns > 0 {
+		tr.MaxIdleConns = config.MaxIdleConns
+	}
+
+	if config.MaxIdleConnsPerHost > 0 {
+		tr.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+	}
+
+	if config.IdleConnTimeout > 0 {
+		tr.IdleConnTimeout = config.IdleConnTimeout
+	}
+
+	if config.EnableHTTP2 {
+		// The custom TLS configuration and dialer disable the automatic HTTP/2 support of the transport.
+		tr.Protocols = new(http.Protocols)
+		tr.Protocols.SetHTTP1(true)
+		tr.Protocols.SetHTTP2(true)
+	}
+
+	if config.TLSSessionCacheSize != 0 {
+		if tr.TLSClientConfig == nil {
+			tr.TLSClientConfig = &tls.Config{}
+		}
+
+		tr.TLSClientConfig.ClientSessionCache = nil
+
+		if config.TLSSessionCacheSize > 0 {
+			tr.TLSClientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(config.TLSSessionCacheSize)
+		}
+	}
+
+	client.Transport = tr
+
+	return nil
+}
+
 // initCertPool creates a *x509.CertPool populated with the PEM certificates
 // found in the filepath specified in the caCertificatesEnvVar OS environment variable.
 // If the caCertificatesEnvVar is not set then initCertPool will return nil.
@@ -0,0 +1,110 @@
+package lego
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_createDefaultHTTPClient(t *testing.T) {
+	client := createDefaultHTTPClient()
+
+	tr, ok := client.Transport.(*http.Transport)
+	require.True(t, ok)
+
+	assert.Equal(t, defaultMaxIdleConns, tr.MaxIdleConns)
+	assert.Equal(t, defaultMaxIdleConnsPerHost, tr.MaxIdleConnsPerHost)
+	assert.Equal(t, defaultIdleConnTimeout, tr.IdleConnTimeout)
+	assert.NotNil(t, tr.TLSClientConfig.ClientSessionCache)
+}
+
+func TestConfigureTransport(t *testing.T) {
+	client := createDefaultHTTPClient()
+
+	err := ConfigureTransport(client, TransportConfig{
+		MaxIdleConns:        500,
+		MaxIdleConnsPerHost: 50,
+		IdleConnTimeout:     5 * time.Minute,
+		TLSSessionCacheSize: -1,
+	})
+	require.NoError(t, err)
+
+	tr, ok := client.Transport.(*http.Transport)
+	require.True(t, ok)
+
+	assert.Equal(t, 500, tr.MaxIdleConns)
+	assert.Equal(t, 50, tr.MaxIdleConnsPerHost)
+	assert.Equal(t, 5*time.Minute, tr.IdleConnTimeout)
+	assert.Nil(t, tr.TLSClientConfig.ClientSessionCache)
+	assert.Nil(t, tr.Protocols)
+}
+
+func TestConfigureTransport_zeroValues(t *testing.T) {
+	client := createDefaultHTTPClient()
+
+	err := ConfigureTransport(client, TransportConfig{})
+	require.NoError(t, err)
+
+	tr, ok := client.Transport.(*http.Transport)
+	require.True(t, ok)
+
+	assert.Equal(t, defaultMaxIdleConns, tr.MaxIdleConns)
+	assert.Equal(t, defaultMaxIdleConnsPerHost, tr.MaxIdleConnsPerHost)
+	assert.Equal(t, defaultIdleConnTimeout, tr.IdleConnTimeout)
+	assert.NotNil(t, tr.TLSClientConfig.ClientSessionCache)
+}
+
+func TestConfigureTransport_http2(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(req.Proto))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	testCases := []struct {
+		desc     string
+		config   TransportConfig
+		expected int
+	}{
+		{
+			desc:     "HTTP/1.1",
+			expected: 1,
+		},
+		{
+			desc:     "HTTP/2",
+			config:   TransportConfig{EnableHTTP2: true},
+			expected: 2,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			client := createDefaultHTTPClient()
+
+			// Trusts the certificate of the test server.
+			client.Transport.(*http.Transport).TLSClientConfig.RootCAs = server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+
+			err := ConfigureTransport(client, test.config)
+			require.NoError(t, err)
+
+			resp, err := client.Get(server.URL)
+			require.NoError(t, err)
+
+			defer func() { _ = resp.Body.Close() }()
+
+			assert.Equal(t, test.expected, resp.ProtoMajor)
+		})
+	}
+}
+
+func TestConfigureTransport_unsupportedTransport(t *testing.T) {
+	client := &http.Client{Transport: http.NewFileTransport(http.Dir("."))}
+
+	err := ConfigureTransport(client, TransportConfig{EnableHTTP2: true})
+	require.EqualError(t, err, "unsupported transport type: http.fileTransport")
+}
@@ -5,7 +5,9 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"testing"
+	"time"
 
+	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/go-acme/lego/v4/registration"
 	"github.com/stretchr/testify/assert"
@@ -34,6 +36,61 @@ func TestNewClient(t *testing.T) {
 	assert.NotNil(t, client)
 }
 
+func TestNewClient_directory(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	dir := &acme.Directory{
+		NewNonceURL:   "https://ca.example.com/acme/new-nonce",
+		NewAccountURL: "https://ca.example.com/acme/new-account",
+		NewOrderURL:   "https://ca.example.com/acme/new-order",
+	}
+
+	config := NewConfig(mockUser{privatekey: key})
+	// The directory is not fetched.
+	config.CADirURL = "https://ca.example.com/unreachable"
+	config.Directory = dir
+
+	client, err := NewClient(config)
+	require.NoError(t, err, "Could not create client")
+
+	assert.Equal(t, *dir, client.core.GetDirectory())
+}
+
+func TestNewClient_quirks(t *testing.T) {
+	server := tester.MockACMEServer().BuildHTTPS(t)
+
+	dirURL := server.URL + "/dir"
+
+	caQuirks[normalizeDirectoryURL(dirURL)] = CAQuirks{
+		Name:               "Example CA",
+		ContactRequired:    true,
+		EABRequired:        true,
+		CertificateTimeout: 2 * time.Minute,
+	}
+
+	t.Cleanup(func() { delete(caQuirks, normalizeDirectoryURL(dirURL)) })
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	config := NewConfig(mockUser{privatekey: key})
+	config.CADirURL = dirURL
+	config.HTTPClient = server.Client()
+
+	client, err := NewClient(config)
+	require.NoError(t, err, "Could not create client")
+
+	assert.Equal(t, "Example CA", client.GetCAQuirks().Name)
+	assert.True(t, client.GetExternalAccountRequired())
+
+	_, err = client.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true})
+	require.EqualError(t, err, "acme: Example CA requires an External Account Binding (EAB)")
+
+	_, err = client.Registration.RegisterWithExternalAccountBinding(registration.RegisterEABOptions{TermsOfServiceAgreed: true})
+	require.EqualError(t, err, "acme: Example CA requires an email address for the account")
+}
+
 type mockUser struct {
 	email      string
 	regres     *registration.Resource
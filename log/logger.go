@@ -1,11 +1,16 @@
 package log
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"strings"
+
+	"github.com/go-acme/lego/v4/platform/redact"
 )
 
 // Logger is an optional custom logger.
+// The entries are redacted (see [redact.String]) before being written to the logger.
 var Logger StdLogger = log.New(os.Stderr, "", log.LstdFlags)
 
 // StdLogger interface for Standard Logger.
@@ -21,31 +26,31 @@ type StdLogger interface {
 // Fatal writes a log entry.
 // It uses Logger if not nil, otherwise it uses the default log.Logger.
 func Fatal(args ...any) {
-	Logger.Fatal(args...)
+	Logger.Fatal(redact.String(fmt.Sprint(args...)))
 }
 
 // Fatalf writes a log entry.
 // It uses Logger if not nil, otherwise it uses the default log.Logger.
 func Fatalf(format string, args ...any) {
-	Logger.Fatalf(format, args...)
+	Logger.Fatal(redact.String(fmt.Sprintf(format, args...)))
 }
 
 // Print writes a log entry.
 // It uses Logger if not nil, otherwise it uses the default log.Logger.
 func Print(args ...any) {
-	Logger.Print(args...)
+	Logger.Print(redact.String(fmt.Sprint(args...)))
 }
 
 // Println writes a log entry.
 // It uses Logger if not nil, otherwise it uses the default log.Logger.
 func Println(args ...any) {
-	Logger.Println(args...)
+	Logger.Println(redact.String(strings.TrimSuffix(fmt.Sprintln(args...), "\n")))
 }
 
 // Printf writes a log entry.
 // It uses Logger if not nil, otherwise it uses the default log.Logger.
 func Printf(format string, args ...any) {
-	Logger.Printf(format, args...)
+	Logger.Print(redact.String(fmt.Sprintf(format, args...)))
 }
 
 // Warnf writes a log entry.
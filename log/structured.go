@@ -0,0 +1,40 @@
+package log
+
+import "context"
+
+// StructuredLogger a logger of structured events (ex: a [*log/slog.Logger]).
+// The arguments are alternating keys and values, as for [log/slog.Logger.Info].
+//
+// It's defined with lego.Config.Logger:
+// the events of the challenges (DNS provider calls, DNS API requests, retries, propagation checks)
+// are written to this logger instead of the global Logger.
+type StructuredLogger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+type structuredLoggerKey struct{}
+
+// NewContext returns a copy of the context carrying the structured logger.
+// A nil logger returns the context unchanged.
+func NewContext(ctx context.Context, logger StructuredLogger) context.Context {
+	if logger == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, structuredLoggerKey{}, logger)
+}
+
+// FromContext returns the structured logger carried by the context, if any.
+// The DNS providers use it to emit their events (ex: the API requests).
+func FromContext(ctx context.Context) (StructuredLogger, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	logger, ok := ctx.Value(structuredLoggerKey{}).(StructuredLogger)
+
+	return logger, ok
+}
@@ -0,0 +1,215 @@
+package env
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const fileSuffix = "_FILE"
+
+var dotEnvKeyRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// LoadDotEnv loads the environment variables defined in a dotenv file.
+//
+// The environment variables already defined (even empty) are not overridden:
+// a `FOO` (or `FOO_FILE`) from the file is ignored if `FOO` or `FOO_FILE` is already defined.
+//
+// The relative paths of the `_FILE` environment variables are relative to the directory of the dotenv file.
+//
+// Returns the names of the loaded environment variables.
+func LoadDotEnv(filename string) ([]string, error) {
+	values, err := ReadDotEnv(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+
+	for name, value := range values {
+		base := strings.TrimSuffix(name, fileSuffix)
+
+		if isDefined(base) || isDefined(base+fileSuffix) {
+			continue
+		}
+
+		err = os.Setenv(name, value)
+		if err != nil {
+			return nil, fmt.Errorf("set %s: %w", name, err)
+		}
+
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// ReadDotEnv reads a dotenv file.
+//
+// The supported syntax:
+//
+//	# comment
+//	FOO=value
+//	export FOO=value
+//	FOO="value with \"escaped\" characters\n" # comment
+//	FOO='literal value'
+func ReadDotEnv(filename string) (map[string]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	values, err := parseDotEnv(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	dir := filepath.Dir(filename)
+
+	for name, value := range values {
+		if strings.HasSuffix(name, fileSuffix) && value != "" && !filepath.IsAbs(value) {
+			values[name] = filepath.Join(dir, value)
+		}
+	}
+
+	return values, nil
+}
+
+func parseDotEnv(r io.Reader) (map[string]string, error) {
+	values := map[string]string{}
+
+	scanner := bufio.NewScanner(r)
+
+	var lineNumber int
+
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, value, err := parseDotEnvLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		values[name] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+func parseDotEnvLine(line string) (string, string, error) {
+	line = strings.TrimPrefix(line, "export ")
+
+	name, raw, ok := strings.Cut(line, "=")
+	if !ok {
+		return "", "", fmt.Errorf("missing '=': %q", line)
+	}
+
+	name = strings.TrimSpace(name)
+
+	if !dotEnvKeyRegexp.MatchString(name) {
+		return "", "", fmt.Errorf("invalid name: %q", name)
+	}
+
+	raw = strings.TrimSpace(raw)
+
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		value, rest, err := parseDoubleQuoted(raw[1:])
+		if err != nil {
+			return "", "", fmt.Errorf("%s: %w", name, err)
+		}
+
+		if !isComment(rest) {
+			return "", "", fmt.Errorf("%s: unexpected characters after the closing quote: %q", name, rest)
+		}
+
+		return name, value, nil
+
+	case strings.HasPrefix(raw, "'"):
+		value, rest, ok := strings.Cut(raw[1:], "'")
+		if !ok {
+			return "", "", fmt.Errorf("%s: missing closing quote", name)
+		}
+
+		if !isComment(rest) {
+			return "", "", fmt.Errorf("%s: unexpected characters after the closing quote: %q", name, rest)
+		}
+
+		return name, value, nil
+
+	default:
+		if strings.HasPrefix(raw, "#") {
+			return name, "", nil
+		}
+
+		if i := strings.Index(raw, " #"); i >= 0 {
+			raw = raw[:i]
+		}
+
+		return name, strings.TrimSpace(raw), nil
+	}
+}
+
+func parseDoubleQuoted(s string) (string, string, error) {
+	var sb strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return sb.String(), s[i+1:], nil
+
+		case '\\':
+			i++
+			if i >= len(s) {
+				return "", "", errors.New("missing closing quote")
+			}
+
+			switch s[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				sb.WriteByte(s[i])
+			}
+
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+
+	return "", "", errors.New("missing closing quote")
+}
+
+func isComment(s string) bool {
+	s = strings.TrimSpace(s)
+
+	return s == "" || strings.HasPrefix(s, "#")
+}
+
+func isDefined(name string) bool {
+	_, ok := os.LookupEnv(name)
+
+	return ok
+}
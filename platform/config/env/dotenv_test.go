@@ -0,0 +1,129 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseDotEnv(t *testing.T) {
+	content := `
+# comment
+TEST_LEGO_A=a
+export TEST_LEGO_B = b
+TEST_LEGO_C=c # comment
+TEST_LEGO_D="d \"quoted\"\nnew line" # comment
+TEST_LEGO_E='e \n literal'
+TEST_LEGO_F=
+TEST_LEGO_G=#value
+TEST_LEGO_H=https://example.com/#anchor
+`
+
+	values, err := parseDotEnv(strings.NewReader(content))
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"TEST_LEGO_A": "a",
+		"TEST_LEGO_B": "b",
+		"TEST_LEGO_C": "c",
+		"TEST_LEGO_D": "d \"quoted\"\nnew line",
+		"TEST_LEGO_E": `e \n literal`,
+		"TEST_LEGO_F": "",
+		"TEST_LEGO_G": "",
+		"TEST_LEGO_H": "https://example.com/#anchor",
+	}
+
+	assert.Equal(t, expected, values)
+}
+
+func Test_parseDotEnv_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		content  string
+		expected string
+	}{
+		{
+			desc:     "missing equal sign",
+			content:  "TEST_LEGO_A",
+			expected: `line 1: missing '=': "TEST_LEGO_A"`,
+		},
+		{
+			desc:     "invalid name",
+			content:  "\nTEST-LEGO=a",
+			expected: `line 2: invalid name: "TEST-LEGO"`,
+		},
+		{
+			desc:     "missing double quote",
+			content:  `TEST_LEGO_A="a`,
+			expected: "line 1: TEST_LEGO_A: missing closing quote",
+		},
+		{
+			desc:     "missing single quote",
+			content:  `TEST_LEGO_A='a`,
+			expected: "line 1: TEST_LEGO_A: missing closing quote",
+		},
+		{
+			desc:     "characters after the closing quote",
+			content:  `TEST_LEGO_A="a"b`,
+			expected: `line 1: TEST_LEGO_A: unexpected characters after the closing quote: "b"`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := parseDotEnv(strings.NewReader(test.content))
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestLoadDotEnv(t *testing.T) {
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "token"), []byte("secret\n"), 0o600)
+	require.NoError(t, err)
+
+	content := `
+TEST_LEGO_DOTENV_A=from-file
+TEST_LEGO_DOTENV_B=from-file
+TEST_LEGO_DOTENV_C=from-file
+TEST_LEGO_DOTENV_D=from-file
+TEST_LEGO_DOTENV_TOKEN_FILE=token
+`
+
+	filename := filepath.Join(dir, ".env")
+
+	err = os.WriteFile(filename, []byte(content), 0o600)
+	require.NoError(t, err)
+
+	t.Setenv("TEST_LEGO_DOTENV_A", "from-env")
+	t.Setenv("TEST_LEGO_DOTENV_B_FILE", "/run/secrets/b")
+
+	t.Setenv("TEST_LEGO_DOTENV_D", "")
+
+	t.Cleanup(func() {
+		_ = os.Unsetenv("TEST_LEGO_DOTENV_C")
+		_ = os.Unsetenv("TEST_LEGO_DOTENV_TOKEN_FILE")
+	})
+
+	names, err := LoadDotEnv(filename)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"TEST_LEGO_DOTENV_C", "TEST_LEGO_DOTENV_TOKEN_FILE"}, names)
+
+	assert.Equal(t, "from-env", os.Getenv("TEST_LEGO_DOTENV_A"))
+	assert.Empty(t, os.Getenv("TEST_LEGO_DOTENV_B"))
+	assert.Equal(t, "from-file", os.Getenv("TEST_LEGO_DOTENV_C"))
+	assert.Empty(t, os.Getenv("TEST_LEGO_DOTENV_D"))
+	assert.Equal(t, filepath.Join(dir, "token"), os.Getenv("TEST_LEGO_DOTENV_TOKEN_FILE"))
+	assert.Equal(t, "secret", GetOrFile("TEST_LEGO_DOTENV_TOKEN"))
+}
+
+func TestLoadDotEnv_missingFile(t *testing.T) {
+	_, err := LoadDotEnv(filepath.Join(t.TempDir(), ".env"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
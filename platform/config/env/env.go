@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/platform/redact"
 )
 
 // Get environment variables.
@@ -27,7 +28,7 @@ func Get(names ...string) (map[string]string, error) {
 	}
 
 	if len(missingEnvVars) > 0 {
-		return nil, fmt.Errorf("some credentials information are missing: %s", strings.Join(missingEnvVars, ","))
+		return nil, &MissingError{Names: missingEnvVars}
 	}
 
 	return values, nil
@@ -76,7 +77,7 @@ func GetWithFallback(groups ...[]string) (map[string]string, error) {
 	}
 
 	if len(missingEnvVars) > 0 {
-		return nil, fmt.Errorf("some credentials information are missing: %s", strings.Join(missingEnvVars, ","))
+		return nil, &MissingError{Names: missingEnvVars}
 	}
 
 	return values, nil
@@ -145,7 +146,18 @@ func getOrDefault[T any](envVar string, defaultValue T, fn func(string) (T, erro
 // GetOrFile Attempts to resolve 'key' as an environment variable.
 // Failing that, it will check to see if '<key>_FILE' exists.
 // If so, it will attempt to read from the referenced file to populate a value.
+// The values of the secret environment variables (see [redact.IsSecretKey]) are registered to be redacted.
 func GetOrFile(envVar string) string {
+	value := getOrFile(envVar)
+
+	if value != "" && redact.IsSecretKey(envVar) {
+		redact.Secret(value)
+	}
+
+	return value
+}
+
+func getOrFile(envVar string) string {
 	envVarValue := os.Getenv(envVar)
 	if envVarValue != "" {
 		return envVarValue
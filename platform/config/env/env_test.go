@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-acme/lego/v4/platform/redact"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -342,6 +343,16 @@ func TestGetOrFile_ReadsEnvVars(t *testing.T) {
 	assert.Equal(t, "lego_env", value)
 }
 
+func TestGetOrFile_redactsSecrets(t *testing.T) {
+	t.Setenv("TEST_LEGO_API_TOKEN", "token-aaaa-aaaa")
+	t.Setenv("TEST_LEGO_ZONE", "zone-aaaa-aaaa")
+
+	assert.Equal(t, "token-aaaa-aaaa", GetOrFile("TEST_LEGO_API_TOKEN"))
+	assert.Equal(t, "zone-aaaa-aaaa", GetOrFile("TEST_LEGO_ZONE"))
+
+	assert.Equal(t, "token ***, zone zone-aaaa-aaaa", redact.String("token token-aaaa-aaaa, zone zone-aaaa-aaaa"))
+}
+
 func TestGetOrFile_ReadsFiles(t *testing.T) {
 	varEnvFileName := "TEST_LEGO_ENV_VAR_FILE"
 	varEnvName := "TEST_LEGO_ENV_VAR"
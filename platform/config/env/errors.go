@@ -0,0 +1,51 @@
+package env
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-acme/lego/v4/platform/errcode"
+)
+
+// ParseError an invalid value of an environment variable.
+type ParseError struct {
+	// Name the name of the environment variable.
+	Name string
+	// Value the invalid value (empty for the secret environment variables).
+	Value string
+	// Expected the description of the expected format.
+	Expected string
+	// Err the underlying error, if any.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("%s: invalid value: expected %s", e.Name, e.Expected)
+	}
+
+	return fmt.Sprintf("%s: invalid value %q: expected %s", e.Name, e.Value, e.Expected)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCode returns the code of the error.
+func (e *ParseError) ErrorCode() errcode.Code {
+	return errcode.Config
+}
+
+// MissingError the required environment variables without values.
+type MissingError struct {
+	Names []string
+}
+
+func (e *MissingError) Error() string {
+	return "some credentials information are missing: " + strings.Join(e.Names, ",")
+}
+
+// ErrorCode returns the code of the error.
+func (e *MissingError) ErrorCode() errcode.Code {
+	return errcode.Config
+}
@@ -0,0 +1,183 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/platform/redact"
+)
+
+// Parser reads typed environment variables, and collects all the errors:
+// a misconfiguration reports all the invalid environment variables at once.
+//
+//	p := env.NewParser()
+//
+//	token := p.Required(EnvAPIToken)
+//	ttl := p.IntRange(EnvTTL, 300, 60, 86400)
+//	timeout := p.Duration(EnvHTTPTimeout, 30*time.Second)
+//
+//	if err := p.Err(); err != nil {
+//		return nil, fmt.Errorf("example: %w", err)
+//	}
+//
+// The getters return the default value when the environment variable is not defined, or when the value is invalid.
+type Parser struct {
+	missing []string
+	errs    []error
+}
+
+// NewParser creates a new Parser.
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Err returns all the errors: a *MissingError for the missing required environment variables,
+// and a *ParseError for each invalid value.
+func (p *Parser) Err() error {
+	var errs []error
+
+	if len(p.missing) > 0 {
+		errs = append(errs, &MissingError{Names: slices.Clone(p.missing)})
+	}
+
+	errs = append(errs, p.errs...)
+
+	return errors.Join(errs...)
+}
+
+// Required returns the value of a required environment variable.
+func (p *Parser) Required(name string) string {
+	value := GetOrFile(name)
+	if value == "" {
+		p.missing = append(p.missing, name)
+	}
+
+	return value
+}
+
+// String returns the value of an environment variable, or the default value.
+func (p *Parser) String(name, defaultValue string) string {
+	value := GetOrFile(name)
+	if value == "" {
+		return defaultValue
+	}
+
+	return value
+}
+
+// Bool returns the value of an environment variable as a boolean.
+func (p *Parser) Bool(name string, defaultValue bool) bool {
+	return parseValue(p, name, defaultValue, "a boolean (true, false, 1, 0)", strconv.ParseBool)
+}
+
+// Int returns the value of an environment variable as an integer.
+func (p *Parser) Int(name string, defaultValue int) int {
+	return parseValue(p, name, defaultValue, "an integer", strconv.Atoi)
+}
+
+// IntRange returns the value of an environment variable as an integer between minValue and maxValue (inclusive).
+func (p *Parser) IntRange(name string, defaultValue, minValue, maxValue int) int {
+	expected := fmt.Sprintf("an integer between %d and %d", minValue, maxValue)
+
+	return parseValue(p, name, defaultValue, expected, func(s string) (int, error) {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, err
+		}
+
+		if v < minValue || v > maxValue {
+			return 0, fmt.Errorf("out of range: %d", v)
+		}
+
+		return v, nil
+	})
+}
+
+// Duration returns the value of an environment variable as a duration (see [ParseDuration]).
+func (p *Parser) Duration(name string, defaultValue time.Duration) time.Duration {
+	return parseValue(p, name, defaultValue, "a positive duration (ex: 30, 30s, 5m, 1h30m)", ParseDuration)
+}
+
+// URL returns the value of an environment variable as an absolute URL (see [ParseURL]).
+func (p *Parser) URL(name string, defaultValue *url.URL) *url.URL {
+	return parseValue(p, name, defaultValue, "an absolute HTTP(S) URL (ex: https://api.example.com)", ParseURL)
+}
+
+// Enum returns the value of an environment variable, the value must be one of the allowed values (case-insensitive).
+func (p *Parser) Enum(name, defaultValue string, allowed ...string) string {
+	expected := "one of: " + strings.Join(allowed, ", ")
+
+	return parseValue(p, name, defaultValue, expected, func(s string) (string, error) {
+		for _, v := range allowed {
+			if strings.EqualFold(s, v) {
+				return v, nil
+			}
+		}
+
+		return "", errors.New("unsupported value")
+	})
+}
+
+func parseValue[T any](p *Parser, name string, defaultValue T, expected string, fn func(string) (T, error)) T {
+	raw := GetOrFile(name)
+	if raw == "" {
+		return defaultValue
+	}
+
+	value, err := fn(strings.TrimSpace(raw))
+	if err != nil {
+		errParse := &ParseError{Name: name, Value: raw, Expected: expected, Err: err}
+
+		if redact.IsSecretKey(name) {
+			errParse.Value = ""
+		}
+
+		p.errs = append(p.errs, errParse)
+
+		return defaultValue
+	}
+
+	return value
+}
+
+// ParseDuration parses env var value (string) to a time.Duration.
+// The value is a number of seconds (ex: 30), or a duration with units (ex: 30s, 5m, 1h30m).
+func ParseDuration(s string) (time.Duration, error) {
+	if _, err := strconv.Atoi(s); err == nil {
+		return ParseSecond(s)
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+
+	if d < 0 {
+		return 0, fmt.Errorf("unsupported value: %s", s)
+	}
+
+	return d, nil
+}
+
+// ParseURL parses env var value (string) to an absolute HTTP(S) URL.
+func ParseURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported scheme: %q", u.Scheme)
+	}
+
+	if u.Host == "" {
+		return nil, errors.New("missing host")
+	}
+
+	return u, nil
+}
@@ -0,0 +1,131 @@
+package env
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser(t *testing.T) {
+	t.Setenv("TEST_LEGO_TOKEN", "secret")
+	t.Setenv("TEST_LEGO_TTL", "600")
+	t.Setenv("TEST_LEGO_TIMEOUT", "1m30s")
+	t.Setenv("TEST_LEGO_ENDPOINT", "https://api.example.com/v1")
+	t.Setenv("TEST_LEGO_MODE", "STRICT")
+	t.Setenv("TEST_LEGO_DEBUG", "true")
+
+	p := NewParser()
+
+	assert.Equal(t, "secret", p.Required("TEST_LEGO_TOKEN"))
+	assert.Equal(t, 600, p.IntRange("TEST_LEGO_TTL", 300, 60, 86400))
+	assert.Equal(t, 90*time.Second, p.Duration("TEST_LEGO_TIMEOUT", 30*time.Second))
+	assert.Equal(t, "https://api.example.com/v1", p.URL("TEST_LEGO_ENDPOINT", nil).String())
+	assert.Equal(t, "strict", p.Enum("TEST_LEGO_MODE", "lax", "lax", "strict"))
+	assert.True(t, p.Bool("TEST_LEGO_DEBUG", false))
+	assert.Equal(t, "default", p.String("TEST_LEGO_UNDEFINED", "default"))
+	assert.Equal(t, 5, p.Int("TEST_LEGO_UNDEFINED", 5))
+
+	require.NoError(t, p.Err())
+}
+
+func TestParser_errors(t *testing.T) {
+	t.Setenv("TEST_LEGO_TTL", "30")
+	t.Setenv("TEST_LEGO_TIMEOUT", "soon")
+	t.Setenv("TEST_LEGO_ENDPOINT", "api.example.com")
+	t.Setenv("TEST_LEGO_MODE", "paranoid")
+	t.Setenv("TEST_LEGO_API_KEY", "abc")
+
+	defaultEndpoint, _ := url.Parse("https://example.com")
+
+	p := NewParser()
+
+	p.Required("TEST_LEGO_UNDEFINED_1")
+	p.Required("TEST_LEGO_UNDEFINED_2")
+
+	// The default values are returned for the invalid values.
+	assert.Equal(t, 300, p.IntRange("TEST_LEGO_TTL", 300, 60, 86400))
+	assert.Equal(t, 30*time.Second, p.Duration("TEST_LEGO_TIMEOUT", 30*time.Second))
+	assert.Equal(t, defaultEndpoint, p.URL("TEST_LEGO_ENDPOINT", defaultEndpoint))
+	assert.Equal(t, "lax", p.Enum("TEST_LEGO_MODE", "lax", "lax", "strict"))
+	assert.Equal(t, 0, p.Int("TEST_LEGO_API_KEY", 0))
+
+	err := p.Err()
+
+	expected := `some credentials information are missing: TEST_LEGO_UNDEFINED_1,TEST_LEGO_UNDEFINED_2
+TEST_LEGO_TTL: invalid value "30": expected an integer between 60 and 86400
+TEST_LEGO_TIMEOUT: invalid value "soon": expected a positive duration (ex: 30, 30s, 5m, 1h30m)
+TEST_LEGO_ENDPOINT: invalid value "api.example.com": expected an absolute HTTP(S) URL (ex: https://api.example.com)
+TEST_LEGO_MODE: invalid value "paranoid": expected one of: lax, strict
+TEST_LEGO_API_KEY: invalid value: expected an integer`
+
+	require.EqualError(t, err, expected)
+
+	var errMissing *MissingError
+	require.ErrorAs(t, err, &errMissing)
+
+	assert.Equal(t, []string{"TEST_LEGO_UNDEFINED_1", "TEST_LEGO_UNDEFINED_2"}, errMissing.Names)
+
+	var errParse *ParseError
+	require.ErrorAs(t, err, &errParse)
+
+	assert.Equal(t, "TEST_LEGO_TTL", errParse.Name)
+}
+
+func TestParseDuration(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected time.Duration
+		err      string
+	}{
+		{value: "30", expected: 30 * time.Second},
+		{value: "30s", expected: 30 * time.Second},
+		{value: "1h30m", expected: 90 * time.Minute},
+		{value: "-1", err: "unsupported value: -1"},
+		{value: "-5m", err: "unsupported value: -5m"},
+		{value: "soon", err: `time: invalid duration "soon"`},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.value, func(t *testing.T) {
+			d, err := ParseDuration(test.value)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, d)
+		})
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	testCases := []struct {
+		value string
+		err   string
+	}{
+		{value: "https://api.example.com"},
+		{value: "http://127.0.0.1:8080/api"},
+		{value: "ftp://example.com", err: `unsupported scheme: "ftp"`},
+		{value: "example.com", err: `unsupported scheme: ""`},
+		{value: "https://", err: "missing host"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.value, func(t *testing.T) {
+			u, err := ParseURL(test.value)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, test.value, u.String())
+		})
+	}
+}
@@ -0,0 +1,144 @@
+// Package errcode provides stable machine-readable codes for the errors.
+//
+// The codes don't change across the versions: the automation can branch on the failure categories without string matching.
+package errcode
+
+import "errors"
+
+// Code a stable machine-readable error code.
+type Code string
+
+func (c Code) String() string {
+	return string(c)
+}
+
+// Generic codes.
+const (
+	// Unknown an error without a specific code.
+	Unknown Code = "LEGO_E_UNKNOWN"
+	// Config an invalid or missing configuration (flags, environment variables).
+	Config Code = "LEGO_E_CONFIG"
+)
+
+// CA (ACME server) codes.
+const (
+	// CAUnreachable the ACME server cannot be reached (network, TLS, timeout).
+	CAUnreachable Code = "LEGO_E_CA_UNREACHABLE"
+	// CARateLimit the request exceeds a rate limit of the CA.
+	CARateLimit Code = "LEGO_E_CA_RATELIMIT"
+	// CABadNonce the nonce was rejected by the CA.
+	CABadNonce Code = "LEGO_E_CA_BAD_NONCE"
+	// CABadCSR the CSR was rejected by the CA.
+	CABadCSR Code = "LEGO_E_CA_BAD_CSR"
+	// CAUnauthorized the client lacks sufficient authorization.
+	CAUnauthorized Code = "LEGO_E_CA_UNAUTHORIZED"
+	// CARejectedIdentifier the CA will not issue certificates for an identifier.
+	CARejectedIdentifier Code = "LEGO_E_CA_REJECTED_IDENTIFIER"
+	// CACAA the CAA records forbid the CA from issuing a certificate.
+	CACAA Code = "LEGO_E_CA_CAA"
+	// CADNS the CA had a problem with the DNS queries during the validation.
+	CADNS Code = "LEGO_E_CA_DNS"
+	// CAConnection the CA could not connect to the validation target.
+	CAConnection Code = "LEGO_E_CA_CONNECTION"
+	// CAIncorrectResponse the response received by the CA didn't match the challenge requirements.
+	CAIncorrectResponse Code = "LEGO_E_CA_INCORRECT_RESPONSE"
+	// CAAccountNotFound the account doesn't exist on the CA.
+	CAAccountNotFound Code = "LEGO_E_CA_ACCOUNT_NOT_FOUND"
+	// CAExternalAccountRequired the CA requires an External Account Binding.
+	CAExternalAccountRequired Code = "LEGO_E_CA_EAB_REQUIRED"
+	// CAAlreadyReplaced the certificate has already been replaced (ARI).
+	CAAlreadyReplaced Code = "LEGO_E_CA_ALREADY_REPLACED"
+	// CAUserActionRequired the CA requires an action of the user (ex: new terms of service).
+	CAUserActionRequired Code = "LEGO_E_CA_USER_ACTION_REQUIRED"
+	// CAServerError an internal error of the CA.
+	CAServerError Code = "LEGO_E_CA_SERVER_ERROR"
+	// CAError any other error returned by the CA.
+	CAError Code = "LEGO_E_CA_ERROR"
+	// CACertificateTimeout the certificate was not issued by the CA in time.
+	CACertificateTimeout Code = "LEGO_E_CA_CERTIFICATE_TIMEOUT"
+)
+
+// Challenge codes.
+const (
+	// ChallengeUnsupported no solver matches the challenges offered by the CA.
+	ChallengeUnsupported Code = "LEGO_E_CHALLENGE_UNSUPPORTED"
+	// ChallengePresent the challenge cannot be presented (HTTP-01, TLS-ALPN-01).
+	ChallengePresent Code = "LEGO_E_CHALLENGE_PRESENT"
+	// ChallengeDenied the challenges were denied by a pre-solve hook.
+	ChallengeDenied Code = "LEGO_E_CHALLENGE_DENIED"
+	// ChallengeInvalid the challenge was rejected by the CA without more details.
+	ChallengeInvalid Code = "LEGO_E_CHALLENGE_INVALID"
+	// DNSProvider the DNS provider failed to create or remove the TXT record.
+	DNSProvider Code = "LEGO_E_DNS_PROVIDER"
+	// DNSPropagation the TXT record propagation check failed.
+	DNSPropagation Code = "LEGO_E_DNS_PROPAGATION"
+	// DNSPropagationTimeout the TXT record was not propagated in time.
+	DNSPropagationTimeout Code = "LEGO_E_DNS_PROPAGATION_TIMEOUT"
+)
+
+// Coder is implemented by the errors with a code.
+type Coder interface {
+	ErrorCode() Code
+}
+
+// Error an error with a code.
+type Error struct {
+	Code Code
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCode returns the code of the error.
+func (e *Error) ErrorCode() Code {
+	return e.Code
+}
+
+// Wrap attaches a code to an error.
+// The error is returned unchanged if it already has a code: the most specific code is kept.
+func Wrap(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := lookup(err); ok {
+		return err
+	}
+
+	return &Error{Code: code, Err: err}
+}
+
+// Of returns the code of an error.
+// Returns an empty code if err is nil, and Unknown if the error has no code.
+func Of(err error) Code {
+	if err == nil {
+		return ""
+	}
+
+	code, ok := lookup(err)
+	if !ok {
+		return Unknown
+	}
+
+	return code
+}
+
+func lookup(err error) (Code, bool) {
+	var coder Coder
+	if !errors.As(err, &coder) {
+		return "", false
+	}
+
+	code := coder.ErrorCode()
+	if code == "" {
+		return "", false
+	}
+
+	return code, true
+}
@@ -0,0 +1,82 @@
+package errcode
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type coderError struct {
+	code Code
+}
+
+func (e coderError) Error() string {
+	return "coder error"
+}
+
+func (e coderError) ErrorCode() Code {
+	return e.code
+}
+
+func TestOf(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		err      error
+		expected Code
+	}{
+		{
+			desc: "nil",
+		},
+		{
+			desc:     "without code",
+			err:      errors.New("error"),
+			expected: Unknown,
+		},
+		{
+			desc:     "wrapped",
+			err:      fmt.Errorf("context: %w", Wrap(DNSProvider, errors.New("error"))),
+			expected: DNSProvider,
+		},
+		{
+			desc:     "coder",
+			err:      fmt.Errorf("context: %w", coderError{code: CARateLimit}),
+			expected: CARateLimit,
+		},
+		{
+			desc:     "coder without code",
+			err:      coderError{},
+			expected: Unknown,
+		},
+		{
+			desc:     "joined",
+			err:      errors.Join(errors.New("error"), Wrap(DNSPropagationTimeout, errors.New("error"))),
+			expected: DNSPropagationTimeout,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			assert.Equal(t, test.expected, Of(test.err))
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	assert.NoError(t, Wrap(Config, nil))
+
+	err := Wrap(ChallengeInvalid, fmt.Errorf("invalid challenge: %w", coderError{code: CADNS}))
+
+	// The most specific code is kept.
+	assert.Equal(t, CADNS, Of(err))
+	assert.EqualError(t, err, "invalid challenge: coder error")
+
+	base := errors.New("error")
+
+	err = Wrap(Config, base)
+
+	assert.Equal(t, Config, Of(err))
+	assert.ErrorIs(t, err, base)
+	assert.EqualError(t, err, "error")
+}
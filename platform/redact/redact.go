@@ -0,0 +1,169 @@
+// Package redact removes the credentials from the errors, the logs, and the debug dumps.
+package redact
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Replacement the value used in place of the credentials.
+const Replacement = "***"
+
+// minSecretLength the minimal length of a registered secret:
+// shorter values are too likely to match unrelated content.
+const minSecretLength = 6
+
+// secretKeySuffixes are the last parts of the environment variable names that identify a secret value.
+var secretKeySuffixes = []string{
+	"TOKEN", "TOKENS", "ACCESSTOKEN",
+	"KEY", "PRIVKEY", "SECRETKEY",
+	"SECRET", "PASSWORD", "PASSPHRASE", "CREDENTIALS", "HMAC",
+}
+
+// nonSecretKeys are the known environment variables (suffixes) matching [secretKeySuffixes]
+// but without a secret value (key names, file paths, etc.).
+var nonSecretKeys = []string{
+	"TSIG_KEY",                       // RFC2136: the name of the TSIG key.
+	"ACCOUNT_SWITCH_KEY",             // Akamai: the account identifier.
+	"TRANSIT_KEY",                    // Vault: the name of the transit key.
+	"GOOGLE_APPLICATION_CREDENTIALS", // Google: the path of the credentials file.
+}
+
+var (
+	// headersRe matches the lines of credential headers (HTTP dumps).
+	headersRe = regexp.MustCompile(`(?im)^(Authorization|Proxy-Authorization|Token|X-Token|Auth-Token|X-Auth-Token|Api-Key|X-Api-Key|X-Api-Secret|X-Auth-Key):.+$`)
+
+	// authorizationRe matches the values of the authorization headers inside a message.
+	authorizationRe = regexp.MustCompile(`(?i)(^|[^\w-])(Authorization|Proxy-Authorization)(\s*[:=]\s*)[^"'\r\n,;}\]]+`)
+
+	// schemesRe matches the tokens of the authorization schemes.
+	schemesRe = regexp.MustCompile(`(?i)\b(Bearer|Sso-key)\s+[\w\-.~+/=:]{6,}`)
+
+	// userinfoRe matches the password of the user information of a URL.
+	userinfoRe = regexp.MustCompile(`(://[^/\s:@]+:)[^/\s@]+@`)
+)
+
+var (
+	mu       sync.RWMutex
+	secrets  = map[string]struct{}{}
+	replacer *strings.Replacer
+)
+
+// Secret registers secret values: they are replaced by [Replacement] in the strings passed to [String].
+// The values shorter than 6 characters are ignored.
+func Secret(values ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var updated bool
+
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if len(value) < minSecretLength {
+			continue
+		}
+
+		if _, ok := secrets[value]; ok {
+			continue
+		}
+
+		secrets[value] = struct{}{}
+		updated = true
+	}
+
+	if !updated {
+		return
+	}
+
+	// The longest secrets first: a secret can contain another one.
+	sorted := make([]string, 0, len(secrets))
+	for value := range secrets {
+		sorted = append(sorted, value)
+	}
+
+	slices.SortFunc(sorted, func(a, b string) int {
+		return len(b) - len(a)
+	})
+
+	oldnew := make([]string, 0, 2*len(sorted))
+	for _, value := range sorted {
+		oldnew = append(oldnew, value, Replacement)
+	}
+
+	replacer = strings.NewReplacer(oldnew...)
+}
+
+// IsSecretKey reports whether an environment variable name identifies a secret value.
+// Only the names ending with an explicit secret part (ex: `_API_TOKEN`, `_SECRET_KEY`, `_PASSWORD`) are secrets:
+// the other names (ex: `_USERNAME`, `_AUTH_ID`, `_ACCESS_KEY_ID`) are not.
+// The `_FILE` variant of a name is handled as the name itself.
+func IsSecretKey(name string) bool {
+	name = strings.TrimSuffix(strings.ToUpper(name), "_FILE")
+
+	for _, key := range nonSecretKeys {
+		if name == key || strings.HasSuffix(name, "_"+key) {
+			return false
+		}
+	}
+
+	last := name[strings.LastIndex(name, "_")+1:]
+
+	return slices.Contains(secretKeySuffixes, last)
+}
+
+// String removes from s the registered secrets,
+// the credentials of the HTTP headers and authorization schemes, and the passwords of the URLs.
+func String(s string) string {
+	s = headersRe.ReplaceAllString(s, "$1: "+Replacement)
+	s = authorizationRe.ReplaceAllString(s, "${1}${2}${3}"+Replacement)
+	s = schemesRe.ReplaceAllString(s, "$1 "+Replacement)
+	s = userinfoRe.ReplaceAllString(s, "${1}"+Replacement+"@")
+
+	mu.RLock()
+	r := replacer
+	mu.RUnlock()
+
+	if r == nil {
+		return s
+	}
+
+	return r.Replace(s)
+}
+
+// Error wraps err: the message is redacted with [String].
+// The original error is still reachable with [errors.Is] and [errors.As].
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := err.(*redactedError); ok {
+		return err
+	}
+
+	return &redactedError{err: err}
+}
+
+type redactedError struct {
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return String(e.err.Error())
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// reset removes all the registered secrets.
+// This is for tests only.
+func reset() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	secrets = map[string]struct{}{}
+	replacer = nil
+}
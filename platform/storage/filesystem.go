@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+const (
+	filePerm os.FileMode = 0o600
+	dirPerm  os.FileMode = 0o700
+)
+
+var _ Backend = (*FileSystem)(nil)
+
+// FileSystem stores the files inside a local directory.
+type FileSystem struct {
+	root string
+}
+
+// NewFileSystem creates a new FileSystem.
+func NewFileSystem(root string) *FileSystem {
+	return &FileSystem{root: root}
+}
+
+// Save creates or replaces the content of a key.
+// The file is replaced atomically: a failure doesn't leave a truncated file.
+func (f *FileSystem) Save(_ context.Context, key string, data []byte) error {
+	err := ValidateKey(key)
+	if err != nil {
+		return err
+	}
+
+	filename := f.filename(key)
+
+	err = os.MkdirAll(filepath.Dir(filename), dirPerm)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	err = tmp.Chmod(filePerm)
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filename)
+}
+
+// Load returns the content of a key, or ErrNotFound.
+func (f *FileSystem) Load(_ context.Context, key string) ([]byte, error) {
+	err := ValidateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(f.filename(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%s: %w", key, ErrNotFound)
+	}
+
+	return data, err
+}
+
+// List returns the keys inside a folder and its sub-folders, sorted.
+func (f *FileSystem) List(_ context.Context, folder string) ([]string, error) {
+	err := ValidateFolder(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+
+	err = filepath.WalkDir(f.filename(folder), func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(f.root, name)
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, filepath.ToSlash(rel))
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(keys)
+
+	return keys, nil
+}
+
+// Delete deletes a key.
+func (f *FileSystem) Delete(_ context.Context, key string) error {
+	err := ValidateKey(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(f.filename(key))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (f *FileSystem) filename(key string) string {
+	return filepath.Join(f.root, filepath.FromSlash(key))
+}
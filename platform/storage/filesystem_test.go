@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSystem(t *testing.T) {
+	root := t.TempDir()
+
+	backend := NewFileSystem(root)
+
+	err := backend.Save(t.Context(), "certificates/example.com.crt", []byte("cert"))
+	require.NoError(t, err)
+
+	err = backend.Save(t.Context(), "accounts/example/account.json", []byte("{}"))
+	require.NoError(t, err)
+
+	info, err := os.Stat(filepath.Join(root, "certificates", "example.com.crt"))
+	require.NoError(t, err)
+
+	assert.Equal(t, filePerm, info.Mode().Perm())
+
+	data, err := backend.Load(t.Context(), "certificates/example.com.crt")
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte("cert"), data)
+
+	keys, err := backend.List(t.Context(), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"accounts/example/account.json", "certificates/example.com.crt"}, keys)
+
+	keys, err = backend.List(t.Context(), "certificates")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"certificates/example.com.crt"}, keys)
+
+	err = backend.Delete(t.Context(), "certificates/example.com.crt")
+	require.NoError(t, err)
+
+	_, err = backend.Load(t.Context(), "certificates/example.com.crt")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	err = backend.Delete(t.Context(), "certificates/example.com.crt")
+	require.NoError(t, err)
+}
+
+func TestFileSystem_List_missing(t *testing.T) {
+	backend := NewFileSystem(filepath.Join(t.TempDir(), "missing"))
+
+	keys, err := backend.List(t.Context(), "")
+	require.NoError(t, err)
+
+	assert.Empty(t, keys)
+}
+
+func TestFileSystem_invalidKey(t *testing.T) {
+	backend := NewFileSystem(t.TempDir())
+
+	err := backend.Save(t.Context(), "../secret", []byte("foo"))
+	require.Error(t, err)
+
+	_, err = backend.Load(t.Context(), "/etc/passwd")
+	require.Error(t, err)
+}
@@ -0,0 +1,55 @@
+// Package storage defines the backends used to store the accounts and the certificates.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ErrNotFound the key doesn't exist.
+var ErrNotFound = errors.New("not found")
+
+// Backend stores the files of the accounts and the certificates.
+//
+// The keys are the slash-separated paths of the files, relative to the root of the storage
+// (ex: `accounts/acme-v02.api.letsencrypt.org/you@example.com/account.json`, `certificates/example.com.crt`).
+type Backend interface {
+	// Save creates or replaces the content of a key.
+	Save(ctx context.Context, key string, data []byte) error
+	// Load returns the content of a key, or ErrNotFound.
+	Load(ctx context.Context, key string) ([]byte, error)
+	// List returns the keys inside a folder and its sub-folders (all the keys if the folder is empty), sorted.
+	List(ctx context.Context, folder string) ([]string, error)
+	// Delete deletes a key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// ValidateKey checks that a key is a clean relative path.
+func ValidateKey(key string) error {
+	switch {
+	case key == "":
+		return errors.New("empty key")
+	case strings.HasPrefix(key, "/"):
+		return fmt.Errorf("invalid key %q: absolute path", key)
+	case strings.Contains(key, `\`):
+		return fmt.Errorf("invalid key %q: backslash", key)
+	case path.Clean(key) != key:
+		return fmt.Errorf("invalid key %q: not a clean path", key)
+	case key == ".." || strings.HasPrefix(key, "../"):
+		return fmt.Errorf("invalid key %q: outside of the storage", key)
+	default:
+		return nil
+	}
+}
+
+// ValidateFolder checks that a folder is empty (the root of the storage) or a clean relative path.
+func ValidateFolder(folder string) error {
+	if folder == "" {
+		return nil
+	}
+
+	return ValidateKey(folder)
+}
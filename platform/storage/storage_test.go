@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateKey(t *testing.T) {
+	testCases := []struct {
+		key    string
+		assert assert.ErrorAssertionFunc
+	}{
+		{key: "certificates/example.com.crt", assert: assert.NoError},
+		{key: "accounts/acme-v02.api.letsencrypt.org/you@example.com/keys/you@example.com.key", assert: assert.NoError},
+		{key: "", assert: assert.Error},
+		{key: "/etc/passwd", assert: assert.Error},
+		{key: "../secret", assert: assert.Error},
+		{key: "..", assert: assert.Error},
+		{key: "certificates/../../secret", assert: assert.Error},
+		{key: "certificates//example.com.crt", assert: assert.Error},
+		{key: "certificates/", assert: assert.Error},
+		{key: `certificates\example.com.crt`, assert: assert.Error},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.key, func(t *testing.T) {
+			t.Parallel()
+
+			test.assert(t, ValidateKey(test.key))
+		})
+	}
+}
@@ -187,5 +187,17 @@ func (b *Builder) Build(t *testing.T, options ...Option) net.Addr {
 
 	waitLock.Lock()
 
+	if server.Listener != nil {
+		return server.Listener.Addr()
+	}
+
 	return server.PacketConn.LocalAddr()
 }
+
+// TCP serves the DNS queries over TCP instead of UDP.
+func TCP() Option {
+	return func(server *dns.Server) error {
+		server.Net = "tcp"
+		return nil
+	}
+}
@@ -26,6 +26,21 @@ func TestServer_Query_matchType(t *testing.T) {
 	assert.Equal(t, m.Question, r.Question)
 }
 
+func TestServer_Query_tcp(t *testing.T) {
+	addr := NewServer().
+		Query("example.com. SOA", Noop).
+		Build(t, TCP())
+
+	client := &dns.Client{Net: "tcp", Timeout: 1 * time.Second}
+
+	m := new(dns.Msg).SetQuestion("example.com.", dns.TypeSOA)
+
+	r, _, err := client.Exchange(m, addr.String())
+	require.NoError(t, err)
+
+	assert.Equal(t, dns.RcodeSuccess, r.Rcode)
+}
+
 func TestServer_Query_noType(t *testing.T) {
 	addr := NewServer().
 		Query("example.com.", Noop).
@@ -0,0 +1,63 @@
+package tester
+
+import (
+	"crypto/sha256"
+	"math/rand/v2"
+	"sync"
+	"testing"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	jose "github.com/go-jose/go-jose/v4"
+)
+
+// DeterministicReader a seeded CSPRNG (ChaCha8): the same seed always produces the same stream.
+// It allows to generate reproducible keys, CSRs, and JWS signatures (ex: golden-file tests).
+//
+// This is for tests only: the stream is predictable.
+type DeterministicReader struct {
+	mu  sync.Mutex
+	rng *rand.ChaCha8
+}
+
+// NewDeterministicReader creates a DeterministicReader from a seed.
+func NewDeterministicReader(seed string) *DeterministicReader {
+	return &DeterministicReader{rng: rand.NewChaCha8(sha256.Sum256([]byte(seed)))}
+}
+
+// Read fills p with the next bytes of the stream.
+//
+// The crypto packages randomly read a single byte before some operations (ex: RSA key generation, ECDSA signatures)
+// to prevent callers from relying on a deterministic output:
+// the single-byte reads don't advance the stream, so the output only depends on the seed.
+func (r *DeterministicReader) Read(p []byte) (int, error) {
+	if len(p) == 1 {
+		p[0] = 0
+
+		return 1, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.rng.Read(p)
+}
+
+// UseDeterministicRandom uses a DeterministicReader for the generation of the keys, the CSRs, and the JWS signatures,
+// until the end of the test.
+// The previous sources of randomness are restored by the cleanup of the test.
+//
+// The tests using this function must not be run in parallel.
+func UseDeterministicRandom(t *testing.T, seed string) {
+	t.Helper()
+
+	restore := certcrypto.SetRandom(NewDeterministicReader(seed))
+
+	previous := jose.RandReader
+	jose.RandReader = NewDeterministicReader(seed + "/jws")
+
+	t.Cleanup(func() {
+		restore()
+
+		jose.RandReader = previous
+	})
+}
@@ -0,0 +1,87 @@
+package tester
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeterministicReader(t *testing.T) {
+	a := make([]byte, 32)
+	_, err := NewDeterministicReader("foo").Read(a)
+	require.NoError(t, err)
+
+	b := make([]byte, 32)
+	_, err = NewDeterministicReader("foo").Read(b)
+	require.NoError(t, err)
+
+	c := make([]byte, 32)
+	_, err = NewDeterministicReader("bar").Read(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+}
+
+func TestUseDeterministicRandom(t *testing.T) {
+	testCases := []struct {
+		keyType certcrypto.KeyType
+	}{
+		{keyType: certcrypto.EC256},
+		{keyType: certcrypto.RSA2048},
+	}
+
+	for _, test := range testCases {
+		t.Run(string(test.keyType), func(t *testing.T) {
+			generate := func() ([]byte, []byte) {
+				t.Helper()
+
+				key, err := certcrypto.GeneratePrivateKey(test.keyType)
+				require.NoError(t, err)
+
+				csr, err := certcrypto.CreateCSR(key, certcrypto.CSROptions{Domain: "example.com", SAN: []string{"example.com"}})
+				require.NoError(t, err)
+
+				return certcrypto.PEMEncode(key), csr
+			}
+
+			var keys, csrs [][]byte
+
+			for range 3 {
+				t.Run("run", func(t *testing.T) {
+					UseDeterministicRandom(t, "seed")
+
+					key, csr := generate()
+
+					keys = append(keys, key)
+					csrs = append(csrs, csr)
+				})
+			}
+
+			assert.Equal(t, keys[0], keys[1])
+			assert.Equal(t, keys[0], keys[2])
+			assert.Equal(t, csrs[0], csrs[1])
+			assert.Equal(t, csrs[0], csrs[2])
+
+			// The default source is restored.
+			key, _ := generate()
+			assert.NotEqual(t, keys[0], key)
+		})
+	}
+}
+
+func TestUseDeterministicRandom_keyTypes(t *testing.T) {
+	UseDeterministicRandom(t, "seed")
+
+	key, err := certcrypto.GeneratePrivateKey(certcrypto.EC384)
+	require.NoError(t, err)
+	assert.IsType(t, &ecdsa.PrivateKey{}, key)
+
+	key, err = certcrypto.GeneratePrivateKey(certcrypto.RSA3072)
+	require.NoError(t, err)
+	assert.IsType(t, &rsa.PrivateKey{}, key)
+}
@@ -2,6 +2,7 @@ package wait
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,6 +10,9 @@ import (
 	"github.com/go-acme/lego/v4/log"
 )
 
+// ErrTimeLimitExceeded is returned by For when the timeout is reached.
+var ErrTimeLimitExceeded = errors.New("time limit exceeded")
+
 // For polls the given function 'f', once every 'interval', up to 'timeout'.
 func For(msg string, timeout, interval time.Duration, f func() (bool, error)) error {
 	log.Infof("Wait for %s [timeout: %s, interval: %s]", msg, timeout, interval)
@@ -21,10 +25,10 @@ func For(msg string, timeout, interval time.Duration, f func() (bool, error)) er
 		select {
 		case <-timeUp:
 			if lastErr == nil {
-				return fmt.Errorf("%s: time limit exceeded", msg)
+				return fmt.Errorf("%s: %w", msg, ErrTimeLimitExceeded)
 			}
 
-			return fmt.Errorf("%s: time limit exceeded: last error: %w", msg, lastErr)
+			return fmt.Errorf("%s: %w: last error: %w", msg, ErrTimeLimitExceeded, lastErr)
 		default:
 		}
 
@@ -43,9 +47,21 @@ func For(msg string, timeout, interval time.Duration, f func() (bool, error)) er
 
 // Retry retries the given operation until it succeeds or the context is canceled.
 // Similar to [backoff.Retry] but with a different signature.
+// The failed attempts are written to the structured logger of the context, if any (see [log.FromContext]).
 func Retry(ctx context.Context, operation func() error, opts ...backoff.RetryOption) error {
+	logger, withLogger := log.FromContext(ctx)
+
+	attempt := 0
+
 	_, err := backoff.Retry(ctx, func() (any, error) {
-		return nil, operation()
+		attempt++
+
+		err := operation()
+		if err != nil && withLogger {
+			logger.Debug("retry: attempt failed", "attempt", attempt, "error", err)
+		}
+
+		return nil, err
 	}, opts...)
 
 	return err
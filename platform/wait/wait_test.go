@@ -1,11 +1,14 @@
 package wait
 
 import (
+	"context"
 	"errors"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/cenkalti/backoff/v5"
+	"github.com/go-acme/lego/v4/log"
 	"github.com/stretchr/testify/require"
 )
 
@@ -35,6 +38,7 @@ func TestFor_timeout(t *testing.T) {
 		t.Fatal("timeout exceeded")
 	case err := <-c:
 		require.EqualError(t, err, "test: time limit exceeded")
+		require.ErrorIs(t, err, ErrTimeLimitExceeded)
 	}
 
 	require.EqualValues(t, 3, io.Load())
@@ -65,6 +69,7 @@ func TestFor_timeout_with_error(t *testing.T) {
 		t.Fatal("timeout exceeded")
 	case err := <-c:
 		require.EqualError(t, err, "test: time limit exceeded: last error: oops")
+		require.ErrorIs(t, err, ErrTimeLimitExceeded)
 	}
 
 	require.EqualValues(t, 3, io.Load())
@@ -119,3 +124,31 @@ func TestFor_stop_with_error(t *testing.T) {
 
 	require.EqualValues(t, 1, io.Load())
 }
+
+func TestRetry_logger(t *testing.T) {
+	logger := &debugLogger{}
+
+	ctx := log.NewContext(context.Background(), logger)
+
+	var io atomic.Int64
+
+	err := Retry(ctx, func() error {
+		if io.Add(1) < 3 {
+			return errors.New("oops")
+		}
+
+		return nil
+	}, backoff.WithBackOff(backoff.NewConstantBackOff(10*time.Millisecond)))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"retry: attempt failed", "retry: attempt failed"}, logger.messages)
+}
+
+type debugLogger struct {
+	messages []string
+}
+
+func (l *debugLogger) Debug(msg string, _ ...any) { l.messages = append(l.messages, msg) }
+func (l *debugLogger) Info(string, ...any)        {}
+func (l *debugLogger) Warn(string, ...any)        {}
+func (l *debugLogger) Error(string, ...any)       {}
@@ -36,7 +36,10 @@ const (
 
 const defaultRegionID = "cn-hangzhou"
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout = (*DNSProvider)(nil)
+	_ challenge.ProviderContext = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -152,8 +155,11 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	ctx := context.Background()
+	return d.PresentContext(context.Background(), domain, token, keyAuth)
+}
 
+// PresentContext is like Present, the API calls are canceled with the context.
+func (d *DNSProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	zoneName, err := d.getHostedZone(ctx, info.EffectiveFQDN)
@@ -176,8 +182,11 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	ctx := context.Background()
+	return d.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
 
+// CleanUpContext is like CleanUp, the API calls are canceled with the context.
+func (d *DNSProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	records, err := d.findTxtRecords(ctx, info.EffectiveFQDN)
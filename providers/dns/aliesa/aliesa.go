@@ -159,8 +159,11 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	ctx := context.Background()
+	return d.PresentContext(context.Background(), domain, token, keyAuth)
+}
 
+// PresentContext is like Present, the API calls are canceled with the context.
+func (d *DNSProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	siteID, err := d.getSiteID(ctx, info.EffectiveFQDN)
@@ -190,8 +193,11 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	ctx := context.Background()
+	return d.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
 
+// CleanUpContext is like CleanUp, the API calls are canceled with the context.
+func (d *DNSProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	// gets the record's unique ID
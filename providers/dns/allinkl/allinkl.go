@@ -93,7 +93,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		identifier.HTTPClient = config.HTTPClient
 	}
 
-	identifier.HTTPClient = clientdebug.Wrap(identifier.HTTPClient)
+	identifier.HTTPClient = clientdebug.Wrap(identifier.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))
 
 	client := internal.NewClient(config.Login)
 
@@ -101,7 +101,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		client.HTTPClient = config.HTTPClient
 	}
 
-	client.HTTPClient = clientdebug.Wrap(client.HTTPClient)
+	client.HTTPClient = clientdebug.Wrap(client.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))
 
 	return &DNSProvider{
 		config:     config,
@@ -33,7 +33,10 @@ const (
 
 const defaultTTL = 300
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout = (*DNSProvider)(nil)
+	_ challenge.ProviderContext = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -107,7 +110,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		client.HTTPClient = config.HTTPClient
 	}
 
-	client.HTTPClient = clientdebug.Wrap(client.HTTPClient)
+	client.HTTPClient = clientdebug.Wrap(client.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))
 
 	return &DNSProvider{
 		config: config,
@@ -123,8 +126,11 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	ctx := context.Background()
+	return d.PresentContext(context.Background(), domain, token, keyAuth)
+}
 
+// PresentContext is like Present, the API calls are canceled with the context.
+func (d *DNSProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
@@ -158,8 +164,11 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	ctx := context.Background()
+	return d.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
 
+// CleanUpContext is like CleanUp, the API calls are canceled with the context.
+func (d *DNSProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
@@ -14,6 +14,7 @@ import (
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/go-acme/lego/v4/providers/dns/arvancloud/internal"
 	"github.com/go-acme/lego/v4/providers/dns/internal/clientdebug"
+	"github.com/go-acme/lego/v4/providers/dns/internal/ttl"
 )
 
 // Environment variables names.
@@ -86,9 +87,9 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("arvancloud: credentials missing")
 	}
 
-	if config.TTL < minTTL {
-		return nil, fmt.Errorf("arvancloud: invalid TTL, TTL (%d) must be greater than %d", config.TTL, minTTL)
-	}
+	cfg := *config
+	cfg.TTL = ttl.Range{Min: minTTL}.Clamp("arvancloud", config.TTL)
+	config = &cfg
 
 	client := internal.NewClient(config.APIKey)
 
@@ -96,7 +97,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		client.HTTPClient = config.HTTPClient
 	}
 
-	client.HTTPClient = clientdebug.Wrap(client.HTTPClient)
+	client.HTTPClient = clientdebug.Wrap(client.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))
 
 	return &DNSProvider{
 		config:    config,
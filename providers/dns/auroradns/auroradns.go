@@ -95,7 +95,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, fmt.Errorf("aurora: %w", err)
 	}
 
-	client, err := auroradns.NewClient(clientdebug.Wrap(tr.Client()), auroradns.WithBaseURL(config.BaseURL))
+	client, err := auroradns.NewClient(clientdebug.Wrap(tr.Client(), clientdebug.WithEnvNamespace(envNamespace)), auroradns.WithBaseURL(config.BaseURL))
 	if err != nil {
 		return nil, fmt.Errorf("aurora: %w", err)
 	}
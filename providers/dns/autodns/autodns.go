@@ -106,7 +106,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		client.HTTPClient = config.HTTPClient
 	}
 
-	client.HTTPClient = clientdebug.Wrap(client.HTTPClient)
+	client.HTTPClient = clientdebug.Wrap(client.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))
 
 	return &DNSProvider{config: config, client: client}, nil
 }
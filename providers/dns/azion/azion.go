@@ -93,7 +93,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		clientConfig.HTTPClient = config.HTTPClient
 	}
 
-	clientConfig.HTTPClient = clientdebug.Wrap(clientConfig.HTTPClient)
+	clientConfig.HTTPClient = clientdebug.Wrap(clientConfig.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))
 
 	client := idns.NewAPIClient(clientConfig)
 
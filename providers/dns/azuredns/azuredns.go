@@ -172,7 +172,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		config.HTTPClient = &http.Client{Timeout: 5 * time.Second}
 	}
 
-	config.HTTPClient = clientdebug.Wrap(config.HTTPClient)
+	config.HTTPClient = clientdebug.Wrap(config.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))
 
 	credentials, err := getCredentials(config)
 	if err != nil {
@@ -81,7 +81,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		config.HTTPClient = &http.Client{Timeout: time.Minute}
 	}
 
-	client, err := bindman.New(config.BaseURL, clientdebug.Wrap(config.HTTPClient))
+	client, err := bindman.New(config.BaseURL, clientdebug.Wrap(config.HTTPClient, clientdebug.WithEnvNamespace(envNamespace)))
 	if err != nil {
 		return nil, fmt.Errorf("bindman: %w", err)
 	}
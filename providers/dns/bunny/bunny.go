@@ -14,6 +14,7 @@ import (
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/go-acme/lego/v4/providers/dns/internal/clientdebug"
 	"github.com/go-acme/lego/v4/providers/dns/internal/ptr"
+	"github.com/go-acme/lego/v4/providers/dns/internal/ttl"
 	"github.com/go-acme/lego/v4/providers/dns/internal/useragent"
 	"github.com/nrdcg/bunny-go"
 	"golang.org/x/net/publicsuffix"
@@ -87,15 +88,15 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("bunny: credentials missing")
 	}
 
-	if config.TTL < minTTL {
-		return nil, fmt.Errorf("bunny: invalid TTL, TTL (%d) must be greater than %d", config.TTL, minTTL)
-	}
+	cfg := *config
+	cfg.TTL = ttl.Range{Min: minTTL}.Clamp("bunny", config.TTL)
+	config = &cfg
 
 	if config.HTTPClient == nil {
 		config.HTTPClient = &http.Client{Timeout: 30 * time.Second}
 	}
 
-	config.HTTPClient = clientdebug.Wrap(config.HTTPClient)
+	config.HTTPClient = clientdebug.Wrap(config.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))
 
 	return &DNSProvider{
 		config: config,
@@ -76,10 +76,9 @@ func TestNewDNSProviderConfig(t *testing.T) {
 			expected: "bunny: credentials missing",
 		},
 		{
-			desc:     "invalid TTL",
-			apiKey:   "123",
-			ttl:      10,
-			expected: "bunny: invalid TTL, TTL (10) must be greater than 60",
+			desc:   "TTL clamped",
+			apiKey: "123",
+			ttl:    10,
 		},
 	}
 
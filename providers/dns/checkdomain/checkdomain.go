@@ -29,7 +29,10 @@ const (
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 )
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout = (*DNSProvider)(nil)
+	_ challenge.ProviderContext = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -91,6 +94,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	client := internal.NewClient(
 		clientdebug.Wrap(
 			internal.OAuthStaticAccessToken(config.HTTPClient, config.Token),
+			clientdebug.WithEnvNamespace(envNamespace),
 		),
 	)
 
@@ -103,8 +107,11 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	ctx := context.Background()
+	return d.PresentContext(context.Background(), domain, token, keyAuth)
+}
 
+// PresentContext is like Present, the API calls are canceled with the context.
+func (d *DNSProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
 	// TODO(ldez) replace domain by FQDN to follow CNAME.
 	domainID, err := d.client.GetDomainIDByName(ctx, domain)
 	if err != nil {
@@ -133,8 +140,11 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record previously created.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	ctx := context.Background()
+	return d.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
 
+// CleanUpContext is like CleanUp, the API calls are canceled with the context.
+func (d *DNSProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
 	// TODO(ldez) replace domain by FQDN to follow CNAME.
 	domainID, err := d.client.GetDomainIDByName(ctx, domain)
 	if err != nil {
@@ -13,6 +13,7 @@ import (
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/go-acme/lego/v4/providers/dns/civo/internal"
 	"github.com/go-acme/lego/v4/providers/dns/internal/clientdebug"
+	"github.com/go-acme/lego/v4/providers/dns/internal/ttl"
 )
 
 // Environment variables names.
@@ -87,14 +88,15 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("civo: credentials missing")
 	}
 
-	if config.TTL < minTTL {
-		config.TTL = minTTL
-	}
+	cfg := *config
+	cfg.TTL = ttl.Range{Min: minTTL}.Clamp("civo", config.TTL)
+	config = &cfg
 
 	// Create a Civo client - DNS is region independent, we can use any region
 	client, err := internal.NewClient(
 		clientdebug.Wrap(
 			internal.OAuthStaticAccessToken(config.HTTPClient, config.Token),
+			clientdebug.WithEnvNamespace(envNamespace),
 		),
 		"LON1")
 	if err != nil {
@@ -16,6 +16,7 @@ import (
 	"github.com/go-acme/lego/v4/log"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/go-acme/lego/v4/providers/dns/cloudflare/internal"
+	"github.com/go-acme/lego/v4/providers/dns/internal/ttl"
 )
 
 // Environment variables names.
@@ -34,6 +35,7 @@ const (
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+	EnvMaxRetries         = envNamespace + "MAX_RETRIES"
 )
 
 const (
@@ -46,7 +48,10 @@ const (
 	minTTL = 120
 )
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout = (*DNSProvider)(nil)
+	_ challenge.ProviderContext = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -62,6 +67,10 @@ type Config struct {
 	PropagationTimeout time.Duration
 	PollingInterval    time.Duration
 	HTTPClient         *http.Client
+
+	// MaxRetries the maximum number of retries of an API call on the 429 and 5xx responses
+	// (the non-idempotent calls are only retried on the 429 and 503 responses with a Retry-After header).
+	MaxRetries int
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
@@ -73,6 +82,7 @@ func NewDefaultConfig() *Config {
 		HTTPClient: &http.Client{
 			Timeout: env.GetOneWithFallback(EnvHTTPTimeout, 30*time.Second, env.ParseSecond, altEnvName(EnvHTTPTimeout)),
 		},
+		MaxRetries: env.GetOneWithFallback(EnvMaxRetries, 3, strconv.Atoi, altEnvName(EnvMaxRetries)),
 	}
 }
 
@@ -83,6 +93,12 @@ type DNSProvider struct {
 
 	recordIDs   map[string]string
 	recordIDsMu sync.Mutex
+
+	// customHostnameRecords the IDs of the validation records created for the custom hostnames.
+	customHostnameRecords map[string][]string
+
+	// only for testing purpose.
+	findZoneByFqdn func(fqdn string) (string, error)
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Cloudflare.
@@ -131,9 +147,9 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("cloudflare: the configuration of the DNS provider is nil")
 	}
 
-	if config.TTL < minTTL {
-		return nil, fmt.Errorf("cloudflare: invalid TTL, TTL (%d) must be greater than %d", config.TTL, minTTL)
-	}
+	cfg := *config
+	cfg.TTL = ttl.Range{Min: minTTL}.Clamp("cloudflare", config.TTL)
+	config = &cfg
 
 	client, err := newClient(config)
 	if err != nil {
@@ -141,9 +157,11 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	}
 
 	return &DNSProvider{
-		client:    client,
-		config:    config,
-		recordIDs: make(map[string]string),
+		client:                client,
+		config:                config,
+		recordIDs:             make(map[string]string),
+		customHostnameRecords: make(map[string][]string),
+		findZoneByFqdn:        dns01.FindZoneByFqdn,
 	}, nil
 }
 
@@ -155,11 +173,14 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	ctx := context.Background()
+	return d.PresentContext(context.Background(), domain, token, keyAuth)
+}
 
+// PresentContext is like Present, the API calls are canceled with the context.
+func (d *DNSProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
+	authZone, err := d.findZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("cloudflare: could not find zone for domain %q: %w", domain, err)
 	}
@@ -192,11 +213,14 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	ctx := context.Background()
+	return d.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
 
+// CleanUpContext is like CleanUp, the API calls are canceled with the context.
+func (d *DNSProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
+	authZone, err := d.findZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("cloudflare: could not find zone for domain %q: %w", domain, err)
 	}
@@ -228,6 +252,180 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	return nil
 }
 
+// ListTXTRecords lists the TXT records of the zone.
+// Implements dns01.RecordJanitor.
+func (d *DNSProvider) ListTXTRecords(zone string) ([]dns01.ChallengeRecord, error) {
+	ctx := context.Background()
+
+	zoneID, err := d.client.ZoneIDByName(ctx, dns01.ToFqdn(zone))
+	if err != nil {
+		return nil, fmt.Errorf("cloudflare: failed to find zone %s: %w", zone, err)
+	}
+
+	records, err := d.client.ListTXTRecords(ctx, zoneID, "")
+	if err != nil {
+		return nil, fmt.Errorf("cloudflare: failed to list TXT records: %w", err)
+	}
+
+	var result []dns01.ChallengeRecord
+
+	for _, record := range records {
+		rr := dns01.ChallengeRecord{
+			ID:    record.ID,
+			FQDN:  dns01.ToFqdn(record.Name),
+			Value: record.Content,
+		}
+
+		if record.CreatedOn != nil {
+			rr.CreatedAt = *record.CreatedOn
+		}
+
+		result = append(result, rr)
+	}
+
+	return result, nil
+}
+
+// DeleteTXTRecord removes a TXT record of the zone.
+// Implements dns01.RecordJanitor.
+func (d *DNSProvider) DeleteTXTRecord(zone string, record dns01.ChallengeRecord) error {
+	ctx := context.Background()
+
+	zoneID, err := d.client.ZoneIDByName(ctx, dns01.ToFqdn(zone))
+	if err != nil {
+		return fmt.Errorf("cloudflare: failed to find zone %s: %w", zone, err)
+	}
+
+	err = d.client.DeleteDNSRecord(ctx, zoneID, record.ID)
+	if err != nil {
+		return fmt.Errorf("cloudflare: failed to delete TXT record: %w", err)
+	}
+
+	return nil
+}
+
+// PresentCustomHostnameValidation creates the TXT records required to validate a Cloudflare for SaaS custom hostname:
+// the hostname pre-validation record (ownership verification) and the certificate validation records.
+// The custom hostname is defined inside the SaaS zone,
+// and the records are created inside the Cloudflare zone of the custom hostname.
+func (d *DNSProvider) PresentCustomHostnameValidation(saasZone, hostname string) error {
+	ctx := context.Background()
+
+	saasZoneID, err := d.client.ZoneIDByName(ctx, dns01.ToFqdn(saasZone))
+	if err != nil {
+		return fmt.Errorf("cloudflare: failed to find zone %s: %w", saasZone, err)
+	}
+
+	customHostname, err := d.client.CustomHostnameByName(ctx, saasZoneID, hostname)
+	if err != nil {
+		return fmt.Errorf("cloudflare: %w", err)
+	}
+
+	records := customHostnameValidationRecords(customHostname)
+	if len(records) == 0 {
+		return fmt.Errorf("cloudflare: no TXT validation records for the custom hostname %s", hostname)
+	}
+
+	zoneID, err := d.customHostnameZoneID(ctx, hostname)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		record.TTL = d.config.TTL
+
+		response, err := d.client.CreateDNSRecord(ctx, zoneID, record)
+		if err != nil {
+			return fmt.Errorf("cloudflare: failed to create TXT record %s: %w", record.Name, err)
+		}
+
+		d.recordIDsMu.Lock()
+		d.customHostnameRecords[hostname] = append(d.customHostnameRecords[hostname], response.ID)
+		d.recordIDsMu.Unlock()
+
+		log.Infof("cloudflare: new record %s for the custom hostname %s, ID %s", record.Name, hostname, response.ID)
+	}
+
+	return nil
+}
+
+// CleanUpCustomHostnameValidation removes the TXT records created by PresentCustomHostnameValidation.
+func (d *DNSProvider) CleanUpCustomHostnameValidation(hostname string) error {
+	ctx := context.Background()
+
+	d.recordIDsMu.Lock()
+	recordIDs, ok := d.customHostnameRecords[hostname]
+	d.recordIDsMu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("cloudflare: unknown record IDs for the custom hostname %s", hostname)
+	}
+
+	zoneID, err := d.customHostnameZoneID(ctx, hostname)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, recordID := range recordIDs {
+		err = d.client.DeleteDNSRecord(ctx, zoneID, recordID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cloudflare: failed to delete TXT record %s: %w", recordID, err))
+		}
+	}
+
+	d.recordIDsMu.Lock()
+	delete(d.customHostnameRecords, hostname)
+	d.recordIDsMu.Unlock()
+
+	return errors.Join(errs...)
+}
+
+func (d *DNSProvider) customHostnameZoneID(ctx context.Context, hostname string) (string, error) {
+	authZone, err := d.findZoneByFqdn(dns01.ToFqdn(hostname))
+	if err != nil {
+		return "", fmt.Errorf("cloudflare: could not find zone for domain %q: %w", hostname, err)
+	}
+
+	zoneID, err := d.client.ZoneIDByName(ctx, authZone)
+	if err != nil {
+		return "", fmt.Errorf("cloudflare: failed to find zone %s: %w", authZone, err)
+	}
+
+	return zoneID, nil
+}
+
+func customHostnameValidationRecords(customHostname *internal.CustomHostname) []internal.Record {
+	var records []internal.Record
+
+	if ov := customHostname.OwnershipVerification; ov != nil && strings.EqualFold(ov.Type, "txt") && ov.Name != "" {
+		records = append(records, internal.Record{
+			Type:    "TXT",
+			Name:    dns01.UnFqdn(ov.Name),
+			Content: `"` + ov.Value + `"`,
+		})
+	}
+
+	if customHostname.SSL == nil {
+		return records
+	}
+
+	for _, vr := range customHostname.SSL.ValidationRecords {
+		if vr.TXTName == "" {
+			continue
+		}
+
+		records = append(records, internal.Record{
+			Type:    "TXT",
+			Name:    dns01.UnFqdn(vr.TXTName),
+			Content: `"` + vr.TXTValue + `"`,
+		})
+	}
+
+	return records
+}
+
 func altEnvName(v string) string {
 	return strings.ReplaceAll(v, envNamespace, altEnvNamespace)
 }
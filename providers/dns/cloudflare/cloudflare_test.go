@@ -5,8 +5,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/go-acme/lego/v4/platform/tester/servermock"
+	"github.com/go-acme/lego/v4/providers/dns/cloudflare/internal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -313,7 +315,16 @@ func mockBuilder() *servermock.Builder[*DNSProvider] {
 			config.BaseURL = server.URL
 			config.HTTPClient = server.Client()
 
-			return NewDNSProviderConfig(config)
+			p, err := NewDNSProviderConfig(config)
+			if err != nil {
+				return nil, err
+			}
+
+			p.findZoneByFqdn = func(_ string) (string, error) {
+				return "example.com.", nil
+			}
+
+			return p, nil
 		},
 		servermock.CheckHeader().
 			WithRegexp("User-Agent", `goacme-lego/[0-9.]+ \(.+\)`).
@@ -364,3 +375,126 @@ func TestDNSProvider_CleanUp(t *testing.T) {
 	err := provider.CleanUp("example.com", token, "123d==")
 	require.NoError(t, err)
 }
+
+func TestDNSProvider_ListTXTRecords(t *testing.T) {
+	provider := mockBuilder().
+		// https://developers.cloudflare.com/api/resources/zones/methods/list/
+		Route("GET /zones",
+			servermock.ResponseFromInternal("zones.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("name", "example.com").
+				With("per_page", "50")).
+		// https://developers.cloudflare.com/api/resources/dns/subresources/records/methods/list/
+		Route("GET /zones/023e105f4ecef8ad9ca31a8372d0c353/dns_records",
+			servermock.ResponseFromInternal("list_records.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("type", "TXT").
+				With("page", "1").
+				With("per_page", "100")).
+		Build(t)
+
+	records, err := provider.ListTXTRecords("example.com")
+	require.NoError(t, err)
+
+	expected := []dns01.ChallengeRecord{{
+		ID:        "023e105f4ecef8ad9ca31a8372d0c353",
+		FQDN:      "_acme-challenge.example.com.",
+		Value:     `"ADw2sEd82DUgXcQ9hNBZThJs7zVJkR5v9JeSbAb9mZY"`,
+		CreatedAt: time.Date(2014, time.January, 1, 5, 20, 0, 123450000, time.UTC),
+	}}
+
+	assert.Equal(t, expected, records)
+}
+
+func TestDNSProvider_DeleteTXTRecord(t *testing.T) {
+	provider := mockBuilder().
+		// https://developers.cloudflare.com/api/resources/zones/methods/list/
+		Route("GET /zones",
+			servermock.ResponseFromInternal("zones.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("name", "example.com").
+				With("per_page", "50")).
+		// https://developers.cloudflare.com/api/resources/dns/subresources/records/methods/delete/
+		Route("DELETE /zones/023e105f4ecef8ad9ca31a8372d0c353/dns_records/xxx",
+			servermock.ResponseFromInternal("delete_record.json")).
+		Build(t)
+
+	err := provider.DeleteTXTRecord("example.com", dns01.ChallengeRecord{ID: "xxx"})
+	require.NoError(t, err)
+}
+
+func TestDNSProvider_PresentCustomHostnameValidation(t *testing.T) {
+	provider := mockBuilder().
+		// https://developers.cloudflare.com/api/resources/zones/methods/list/
+		Route("GET /zones",
+			servermock.ResponseFromInternal("zones.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("name", "example.com").
+				With("per_page", "50")).
+		// https://developers.cloudflare.com/api/resources/custom_hostnames/methods/list/
+		Route("GET /zones/023e105f4ecef8ad9ca31a8372d0c353/custom_hostnames",
+			servermock.ResponseFromInternal("custom_hostnames.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("hostname", "app.example.com")).
+		// https://developers.cloudflare.com/api/resources/dns/subresources/records/methods/create/
+		Route("POST /zones/023e105f4ecef8ad9ca31a8372d0c353/dns_records",
+			servermock.ResponseFromInternal("create_record.json"),
+			servermock.CheckHeader().
+				WithContentType("application/json")).
+		Build(t)
+
+	err := provider.PresentCustomHostnameValidation("example.com", "app.example.com")
+	require.NoError(t, err)
+
+	assert.Len(t, provider.customHostnameRecords["app.example.com"], 2)
+}
+
+func TestDNSProvider_CleanUpCustomHostnameValidation(t *testing.T) {
+	provider := mockBuilder().
+		// https://developers.cloudflare.com/api/resources/zones/methods/list/
+		Route("GET /zones",
+			servermock.ResponseFromInternal("zones.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("name", "example.com").
+				With("per_page", "50")).
+		// https://developers.cloudflare.com/api/resources/dns/subresources/records/methods/delete/
+		Route("DELETE /zones/023e105f4ecef8ad9ca31a8372d0c353/dns_records/xxx",
+			servermock.ResponseFromInternal("delete_record.json")).
+		Route("DELETE /zones/023e105f4ecef8ad9ca31a8372d0c353/dns_records/yyy",
+			servermock.ResponseFromInternal("delete_record.json")).
+		Build(t)
+
+	provider.customHostnameRecords["app.example.com"] = []string{"xxx", "yyy"}
+
+	err := provider.CleanUpCustomHostnameValidation("app.example.com")
+	require.NoError(t, err)
+
+	assert.Empty(t, provider.customHostnameRecords)
+}
+
+func Test_customHostnameValidationRecords(t *testing.T) {
+	customHostname := &internal.CustomHostname{
+		Hostname: "app.example.com",
+		SSL: &internal.CustomHostnameSSL{
+			Method: "txt",
+			ValidationRecords: []internal.ValidationRecord{
+				{TXTName: "_acme-challenge.app.example.com", TXTValue: "ca3-574923932a82475cb8592200f1a2a23d"},
+				{Status: "pending"},
+			},
+		},
+		OwnershipVerification: &internal.OwnershipVerification{
+			Type:  "txt",
+			Name:  "_cf-custom-hostname.app.example.com",
+			Value: "5cc07c04-ea62-4a5a-95f0-419334a875a4",
+		},
+	}
+
+	records := customHostnameValidationRecords(customHostname)
+
+	expected := []internal.Record{
+		{Type: "TXT", Name: "_cf-custom-hostname.app.example.com", Content: `"5cc07c04-ea62-4a5a-95f0-419334a875a4"`},
+		{Type: "TXT", Name: "_acme-challenge.app.example.com", Content: `"ca3-574923932a82475cb8592200f1a2a23d"`},
+	}
+
+	assert.Equal(t, expected, records)
+}
@@ -15,6 +15,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/go-acme/lego/v4/providers/dns/internal/clientdebug"
@@ -87,6 +88,45 @@ func (c *Client) CreateDNSRecord(ctx context.Context, zoneID string, record Reco
 	return &result.Result, nil
 }
 
+// ListTXTRecords lists the TXT records of a zone with a name starting with the prefix (all the TXT records if the prefix is empty).
+// https://developers.cloudflare.com/api/resources/dns/subresources/records/methods/list/
+func (c *Client) ListTXTRecords(ctx context.Context, zoneID, prefix string) ([]Record, error) {
+	var records []Record
+
+	for page := 1; ; page++ {
+		endpoint := c.baseURL.JoinPath("zones", zoneID, "dns_records")
+
+		query := endpoint.Query()
+		query.Set("type", "TXT")
+
+		if prefix != "" {
+			query.Set("name.startswith", prefix)
+		}
+
+		query.Set("page", strconv.Itoa(page))
+		query.Set("per_page", "100")
+		endpoint.RawQuery = query.Encode()
+
+		req, err := newJSONRequest(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result APIResponse[[]Record]
+
+		err = c.do(req, &result)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, result.Result...)
+
+		if result.ResultInfo == nil || page >= result.ResultInfo.TotalPages {
+			return records, nil
+		}
+	}
+}
+
 // DeleteDNSRecord deletes DNS record.
 // https://developers.cloudflare.com/api/resources/dns/subresources/records/methods/delete/
 func (c *Client) DeleteDNSRecord(ctx context.Context, zoneID, recordID string) error {
@@ -100,6 +140,30 @@ func (c *Client) DeleteDNSRecord(ctx context.Context, zoneID, recordID string) e
 	return c.do(req, nil)
 }
 
+// CustomHostnamesByName returns the Cloudflare for SaaS custom hostnames of a zone matching the given hostname.
+// https://developers.cloudflare.com/api/resources/custom_hostnames/methods/list/
+func (c *Client) CustomHostnamesByName(ctx context.Context, zoneID, hostname string) ([]CustomHostname, error) {
+	endpoint := c.baseURL.JoinPath("zones", zoneID, "custom_hostnames")
+
+	query := endpoint.Query()
+	query.Set("hostname", hostname)
+	endpoint.RawQuery = query.Encode()
+
+	req, err := newJSONRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result APIResponse[[]CustomHostname]
+
+	err = c.do(req, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Result, nil
+}
+
 // ZonesByName returns a list of zones matching the given name.
 // https://developers.cloudflare.com/api/resources/zones/methods/list/
 func (c *Client) ZonesByName(ctx context.Context, name string) ([]Zone, error) {
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-acme/lego/v4/platform/tester/servermock"
 	"github.com/stretchr/testify/assert"
@@ -82,6 +83,34 @@ func TestClient_CreateDNSRecord_error(t *testing.T) {
 	require.EqualError(t, err, "[status code 400] 6003: Invalid request headers; 6103: Invalid format for X-Auth-Key header")
 }
 
+func TestClient_ListTXTRecords(t *testing.T) {
+	client := mockBuilder().
+		Route("GET /zones/023e105f4ecef8ad9ca31a8372d0c353/dns_records",
+			servermock.ResponseFromFixture("list_records.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("type", "TXT").
+				With("name.startswith", "_acme-challenge.").
+				With("page", "1").
+				With("per_page", "100")).
+		Build(t)
+
+	records, err := client.ListTXTRecords(t.Context(), "023e105f4ecef8ad9ca31a8372d0c353", "_acme-challenge.")
+	require.NoError(t, err)
+
+	createdOn := time.Date(2014, time.January, 1, 5, 20, 0, 123450000, time.UTC)
+
+	expected := []Record{{
+		ID:        "023e105f4ecef8ad9ca31a8372d0c353",
+		Name:      "_acme-challenge.example.com",
+		TTL:       120,
+		Type:      "TXT",
+		Content:   `"ADw2sEd82DUgXcQ9hNBZThJs7zVJkR5v9JeSbAb9mZY"`,
+		CreatedOn: &createdOn,
+	}}
+
+	assert.Equal(t, expected, records)
+}
+
 func TestClient_DeleteDNSRecord(t *testing.T) {
 	client := mockBuilder().
 		Route("DELETE /zones/023e105f4ecef8ad9ca31a8372d0c353/dns_records/xxx",
@@ -103,6 +132,44 @@ func TestClient_DeleteDNSRecord_error(t *testing.T) {
 	require.EqualError(t, err, "[status code 400] 6003: Invalid request headers; 6103: Invalid format for X-Auth-Key header")
 }
 
+func TestClient_CustomHostnamesByName(t *testing.T) {
+	client := mockBuilder().
+		Route("GET /zones/023e105f4ecef8ad9ca31a8372d0c353/custom_hostnames",
+			servermock.ResponseFromFixture("custom_hostnames.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("hostname", "app.example.com")).
+		Build(t)
+
+	hostnames, err := client.CustomHostnamesByName(context.Background(), "023e105f4ecef8ad9ca31a8372d0c353", "app.example.com")
+	require.NoError(t, err)
+
+	expected := []CustomHostname{
+		{
+			ID:       "24c8c68e-bec2-49b6-868e-f06373780630",
+			Hostname: "app.example.com",
+			Status:   "pending",
+			SSL: &CustomHostnameSSL{
+				ID:     "0d89c70d-ad9f-4843-b99f-6cc0252067e9",
+				Status: "pending_validation",
+				Method: "txt",
+				Type:   "dv",
+				ValidationRecords: []ValidationRecord{{
+					Status:   "pending",
+					TXTName:  "_acme-challenge.app.example.com",
+					TXTValue: "ca3-574923932a82475cb8592200f1a2a23d",
+				}},
+			},
+			OwnershipVerification: &OwnershipVerification{
+				Type:  "txt",
+				Name:  "_cf-custom-hostname.app.example.com",
+				Value: "5cc07c04-ea62-4a5a-95f0-419334a875a4",
+			},
+		},
+	}
+
+	assert.Equal(t, expected, hostnames)
+}
+
 func TestClient_ZonesByName(t *testing.T) {
 	client := mockBuilder().
 		Route("GET /zones",
@@ -3,15 +3,17 @@ package internal
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 type Record struct {
-	ID      string `json:"id,omitempty"`
-	Name    string `json:"name,omitempty"`
-	TTL     int    `json:"ttl,omitempty"`
-	Type    string `json:"type,omitempty"`
-	Comment string `json:"comment,omitempty"`
-	Content string `json:"content,omitempty"`
+	ID        string     `json:"id,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	TTL       int        `json:"ttl,omitempty"`
+	Type      string     `json:"type,omitempty"`
+	Comment   string     `json:"comment,omitempty"`
+	Content   string     `json:"content,omitempty"`
+	CreatedOn *time.Time `json:"created_on,omitempty"`
 }
 
 type APIResponse[T any] struct {
@@ -121,3 +123,33 @@ type Tenant struct {
 type TenantUnit struct {
 	ID string `json:"id"`
 }
+
+// CustomHostname a Cloudflare for SaaS custom hostname.
+// https://developers.cloudflare.com/api/resources/custom_hostnames/methods/list/
+type CustomHostname struct {
+	ID                    string                 `json:"id"`
+	Hostname              string                 `json:"hostname"`
+	Status                string                 `json:"status,omitempty"`
+	SSL                   *CustomHostnameSSL     `json:"ssl,omitempty"`
+	OwnershipVerification *OwnershipVerification `json:"ownership_verification,omitempty"`
+}
+
+type CustomHostnameSSL struct {
+	ID                string             `json:"id,omitempty"`
+	Status            string             `json:"status,omitempty"`
+	Method            string             `json:"method,omitempty"`
+	Type              string             `json:"type,omitempty"`
+	ValidationRecords []ValidationRecord `json:"validation_records,omitempty"`
+}
+
+type ValidationRecord struct {
+	Status   string `json:"status,omitempty"`
+	TXTName  string `json:"txt_name,omitempty"`
+	TXTValue string `json:"txt_value,omitempty"`
+}
+
+type OwnershipVerification struct {
+	Type  string `json:"type,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
+}
@@ -3,10 +3,13 @@ package cloudflare
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/providers/dns/cloudflare/internal"
+	"github.com/go-acme/lego/v4/providers/dns/internal/retry"
 )
 
 type metaClient struct {
@@ -18,11 +21,13 @@ type metaClient struct {
 }
 
 func newClient(config *Config) (*metaClient, error) {
+	httpClient := retry.Wrap(config.HTTPClient, config.MaxRetries)
+
 	// with AuthKey/AuthEmail we can access all available APIs
 	if config.AuthToken == "" {
 		client, err := internal.NewClient(
 			internal.WithBaseURL(config.BaseURL),
-			internal.WithHTTPClient(config.HTTPClient),
+			internal.WithHTTPClient(httpClient),
 			internal.WithAuthKey(config.AuthEmail, config.AuthKey))
 		if err != nil {
 			return nil, err
@@ -38,7 +43,7 @@ func newClient(config *Config) (*metaClient, error) {
 
 	dns, err := internal.NewClient(
 		internal.WithBaseURL(config.BaseURL),
-		internal.WithHTTPClient(config.HTTPClient),
+		internal.WithHTTPClient(httpClient),
 		internal.WithAuthToken(config.AuthToken))
 	if err != nil {
 		return nil, err
@@ -55,7 +60,7 @@ func newClient(config *Config) (*metaClient, error) {
 
 	zone, err := internal.NewClient(
 		internal.WithBaseURL(config.BaseURL),
-		internal.WithHTTPClient(config.HTTPClient),
+		internal.WithHTTPClient(httpClient),
 		internal.WithAuthToken(config.ZoneToken))
 	if err != nil {
 		return nil, err
@@ -73,10 +78,29 @@ func (m *metaClient) CreateDNSRecord(ctx context.Context, zoneID string, rr inte
 	return m.clientEdit.CreateDNSRecord(ctx, zoneID, rr)
 }
 
+func (m *metaClient) ListTXTRecords(ctx context.Context, zoneID, prefix string) ([]internal.Record, error) {
+	return m.clientEdit.ListTXTRecords(ctx, zoneID, prefix)
+}
+
 func (m *metaClient) DeleteDNSRecord(ctx context.Context, zoneID, recordID string) error {
 	return m.clientEdit.DeleteDNSRecord(ctx, zoneID, recordID)
 }
 
+func (m *metaClient) CustomHostnameByName(ctx context.Context, zoneID, hostname string) (*internal.CustomHostname, error) {
+	hostnames, err := m.clientEdit.CustomHostnamesByName(ctx, zoneID, hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, h := range hostnames {
+		if strings.EqualFold(h.Hostname, hostname) {
+			return &h, nil
+		}
+	}
+
+	return nil, fmt.Errorf("custom hostname %s could not be found", hostname)
+}
+
 func (m *metaClient) ZoneIDByName(ctx context.Context, fdqn string) (string, error) {
 	m.zonesMu.RLock()
 	id := m.zones[fdqn]
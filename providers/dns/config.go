@@ -0,0 +1,200 @@
+package dns
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+)
+
+// newDNSProviderConfig creates a provider from its configuration:
+// the configuration of the provider (*C),
+// or the fields of the configuration of the provider (map[string]any) applied on a zero configuration with the common defaults.
+// The default configuration of the provider is not used by the fields:
+// it reads the environment variables of the process (ex: AWS_ROLE_CHAIN), which must not apply to the configuration of a tenant.
+func newDNSProviderConfig[C any, P challenge.Provider](config any, fn func(*C) (P, error)) (challenge.Provider, error) {
+	var cfg *C
+
+	switch c := config.(type) {
+	case *C:
+		if c == nil {
+			return nil, errors.New("invalid configuration: the configuration is nil")
+		}
+
+		cfg = c
+
+	case map[string]any:
+		cfg = new(C)
+
+		applyCommonDefaults(cfg)
+
+		err := decodeConfig(cfg, c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid configuration: %w", err)
+		}
+
+	default:
+		return nil, fmt.Errorf("invalid configuration: expected %T or map[string]any, got %T", cfg, config)
+	}
+
+	provider, err := fn(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
+
+// commonDefaults the defaults of the common fields of the configurations of the providers.
+var commonDefaults = map[string]any{
+	"PropagationTimeout": dns01.DefaultPropagationTimeout,
+	"PollingInterval":    dns01.DefaultPollingInterval,
+	"SequenceInterval":   dns01.DefaultPropagationTimeout,
+	"TTL":                dns01.DefaultTTL,
+}
+
+// applyCommonDefaults sets the common fields of a configuration (a pointer to a struct) to their defaults,
+// if the configuration has them.
+func applyCommonDefaults(config any) {
+	cfg := reflect.ValueOf(config).Elem()
+
+	for name, value := range commonDefaults {
+		setDefault(cfg.FieldByName(name), reflect.ValueOf(value))
+	}
+
+	setDefault(cfg.FieldByName("HTTPClient"), reflect.ValueOf(&http.Client{Timeout: 30 * time.Second}))
+}
+
+func setDefault(field, value reflect.Value) {
+	if !field.IsValid() || !field.CanSet() {
+		return
+	}
+
+	v, err := convertValue(value, field.Type())
+	if err != nil {
+		return
+	}
+
+	field.Set(v)
+}
+
+// decodeConfig sets the fields of a configuration (a pointer to a struct) from their values.
+// The names of the fields are case-insensitive.
+// The durations and the URLs can be strings (ex: "2m", "https://example.com").
+func decodeConfig(config any, values map[string]any) error {
+	cfg := reflect.ValueOf(config).Elem()
+
+	for name, value := range values {
+		field := cfg.FieldByNameFunc(func(fieldName string) bool {
+			return strings.EqualFold(fieldName, name)
+		})
+
+		if !field.IsValid() || !field.CanSet() {
+			return fmt.Errorf("unknown field %q", name)
+		}
+
+		v, err := convertValue(reflect.ValueOf(value), field.Type())
+		if err != nil {
+			return fmt.Errorf("field %q: %w", name, err)
+		}
+
+		field.Set(v)
+	}
+
+	return nil
+}
+
+var (
+	durationType = reflect.TypeFor[time.Duration]()
+	urlType      = reflect.TypeFor[*url.URL]()
+)
+
+func convertValue(value reflect.Value, typ reflect.Type) (reflect.Value, error) {
+	if !value.IsValid() {
+		return reflect.Zero(typ), nil
+	}
+
+	if value.Type().AssignableTo(typ) {
+		return value, nil
+	}
+
+	switch {
+	case typ == durationType && value.Kind() == reflect.String:
+		d, err := time.ParseDuration(value.String())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return reflect.ValueOf(d), nil
+
+	case typ == urlType && value.Kind() == reflect.String:
+		u, err := url.Parse(value.String())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return reflect.ValueOf(u), nil
+
+	case isNumber(typ.Kind()) && isNumber(value.Kind()):
+		return convertNumber(value, typ)
+
+	case typ.Kind() == reflect.String && value.Kind() == reflect.String:
+		return value.Convert(typ), nil
+
+	case typ.Kind() == reflect.Slice && value.Kind() == reflect.Slice:
+		slice := reflect.MakeSlice(typ, value.Len(), value.Len())
+
+		for i := range value.Len() {
+			elem, err := convertValue(reflect.ValueOf(value.Index(i).Interface()), typ.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			slice.Index(i).Set(elem)
+		}
+
+		return slice, nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("expected %s, got %s", typ, value.Type())
+}
+
+// convertNumber converts a number to another numeric type,
+// only if the value is preserved (ex: no truncated float, no wrapped negative value, no overflow).
+func convertNumber(value reflect.Value, typ reflect.Type) (reflect.Value, error) {
+	converted := value.Convert(typ)
+
+	if isNegative(converted) != isNegative(value) || !converted.Convert(value.Type()).Equal(value) {
+		return reflect.Value{}, fmt.Errorf("the value %v cannot be represented as %s", value, typ)
+	}
+
+	return converted, nil
+}
+
+func isNegative(value reflect.Value) bool {
+	switch {
+	case value.CanInt():
+		return value.Int() < 0
+	case value.CanFloat():
+		return value.Float() < 0
+	default:
+		return false
+	}
+}
+
+func isNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
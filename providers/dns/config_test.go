@@ -0,0 +1,117 @@
+package dns
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_convertValue_number(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		value    any
+		typ      reflect.Type
+		expected any
+	}{
+		{
+			desc:     "int to int",
+			value:    120,
+			typ:      reflect.TypeFor[int](),
+			expected: 120,
+		},
+		{
+			desc:     "float without fractional part to int",
+			value:    120.0,
+			typ:      reflect.TypeFor[int](),
+			expected: 120,
+		},
+		{
+			desc:     "int to float",
+			value:    2,
+			typ:      reflect.TypeFor[float64](),
+			expected: 2.0,
+		},
+		{
+			desc:     "int64 to duration",
+			value:    int64(5 * time.Second),
+			typ:      reflect.TypeFor[time.Duration](),
+			expected: 5 * time.Second,
+		},
+		{
+			desc:     "positive int to uint",
+			value:    3,
+			typ:      reflect.TypeFor[uint](),
+			expected: uint(3),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			v, err := convertValue(reflect.ValueOf(test.value), test.typ)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, v.Interface())
+		})
+	}
+}
+
+func Test_convertValue_number_error(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		value    any
+		typ      reflect.Type
+		expected string
+	}{
+		{
+			desc:     "float with fractional part to int",
+			value:    1.5,
+			typ:      reflect.TypeFor[int](),
+			expected: "the value 1.5 cannot be represented as int",
+		},
+		{
+			desc:     "negative int to uint",
+			value:    -1,
+			typ:      reflect.TypeFor[uint](),
+			expected: "the value -1 cannot be represented as uint",
+		},
+		{
+			desc:     "negative float to uint",
+			value:    -1.0,
+			typ:      reflect.TypeFor[uint32](),
+			expected: "the value -1 cannot be represented as uint32",
+		},
+		{
+			desc:     "overflow",
+			value:    300,
+			typ:      reflect.TypeFor[int8](),
+			expected: "the value 300 cannot be represented as int8",
+		},
+		{
+			desc:     "uint overflowing int",
+			value:    uint64(math.MaxUint64),
+			typ:      reflect.TypeFor[int64](),
+			expected: "the value 18446744073709551615 cannot be represented as int64",
+		},
+		{
+			desc:     "NaN",
+			value:    math.NaN(),
+			typ:      reflect.TypeFor[int](),
+			expected: "the value NaN cannot be represented as int",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := convertValue(reflect.ValueOf(test.value), test.typ)
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
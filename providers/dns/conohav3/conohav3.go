@@ -99,7 +99,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		identifier.HTTPClient = config.HTTPClient
 	}
 
-	identifier.HTTPClient = clientdebug.Wrap(identifier.HTTPClient)
+	identifier.HTTPClient = clientdebug.Wrap(identifier.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))
 
 	auth := internal.Auth{
 		Identity: internal.Identity{
@@ -132,7 +132,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		client.HTTPClient = config.HTTPClient
 	}
 
-	client.HTTPClient = clientdebug.Wrap(client.HTTPClient)
+	client.HTTPClient = clientdebug.Wrap(client.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))
 
 	return &DNSProvider{config: config, client: client}, nil
 }
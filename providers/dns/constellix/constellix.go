@@ -97,7 +97,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	retryClient.HTTPClient = tr.Wrap(config.HTTPClient)
 	retryClient.Backoff = backoff
 
-	client := internal.NewClient(clientdebug.Wrap(retryClient.StandardClient()))
+	client := internal.NewClient(clientdebug.Wrap(retryClient.StandardClient(), clientdebug.WithEnvNamespace(envNamespace)))
 
 	return &DNSProvider{config: config, client: client}, nil
 }
@@ -325,7 +325,7 @@ func createClient(config *Config) (apiClient, error) {
 			client.HTTPClient = config.HTTPClient
 		}
 
-		client.HTTPClient = clientdebug.Wrap(client.HTTPClient)
+		client.HTTPClient = clientdebug.Wrap(client.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))
 
 		return client, nil
 
@@ -339,7 +339,7 @@ func createClient(config *Config) (apiClient, error) {
 			client.HTTPClient = config.HTTPClient
 		}
 
-		client.HTTPClient = clientdebug.Wrap(client.HTTPClient)
+		client.HTTPClient = clientdebug.Wrap(client.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))
 
 		return client, nil
 
@@ -2,8 +2,11 @@ package dns
 
 import (
 	"testing"
+	"time"
 
+	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/go-acme/lego/v4/providers/dns/duckdns"
 	"github.com/go-acme/lego/v4/providers/dns/exec"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -40,3 +43,112 @@ func TestUnknownDNSProvider(t *testing.T) {
 	require.Error(t, err)
 	assert.Nil(t, provider)
 }
+
+func TestNewDNSChallengeProviderByNameConfig(t *testing.T) {
+	config := exec.NewDefaultConfig()
+	config.Program = "abc"
+
+	provider, err := NewDNSChallengeProviderByNameConfig("exec", config)
+	require.NoError(t, err)
+
+	assert.IsType(t, &exec.DNSProvider{}, provider)
+
+	provider, err = NewDNSChallengeProviderByNameConfig("exec", map[string]any{
+		"program":            "abc",
+		"PropagationTimeout": "2m",
+		"PollingInterval":    int64(5 * time.Second),
+	})
+	require.NoError(t, err)
+
+	assert.IsType(t, &exec.DNSProvider{}, provider)
+
+	timeout, interval := provider.(*exec.DNSProvider).Timeout()
+	assert.Equal(t, 2*time.Minute, timeout)
+	assert.Equal(t, 5*time.Second, interval)
+}
+
+func TestNewDNSChallengeProviderByNameConfig_environment(t *testing.T) {
+	t.Setenv(duckdns.EnvToken, "from-env")
+
+	// The credentials are never read from the environment variables.
+	provider, err := NewDNSChallengeProviderByNameConfig("duckdns", map[string]any{})
+	require.EqualError(t, err, "duckdns: credentials missing")
+	assert.Nil(t, provider)
+
+	provider, err = NewDNSChallengeProviderByNameConfig("duckdns", map[string]any{"Token": "from-config"})
+	require.NoError(t, err)
+
+	assert.IsType(t, &duckdns.DNSProvider{}, provider)
+}
+
+func TestNewDNSChallengeProviderByNameConfig_defaults(t *testing.T) {
+	t.Setenv(exec.EnvPropagationTimeout, "600")
+
+	// The fields are applied on a zero configuration with the common defaults, not on the default configuration of the provider.
+	provider, err := NewDNSChallengeProviderByNameConfig("exec", map[string]any{"Program": "abc"})
+	require.NoError(t, err)
+
+	timeout, interval := provider.(*exec.DNSProvider).Timeout()
+	assert.Equal(t, dns01.DefaultPropagationTimeout, timeout)
+	assert.Equal(t, dns01.DefaultPollingInterval, interval)
+}
+
+func TestNewDNSChallengeProviderByNameConfig_error(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		name     string
+		config   any
+		expected string
+	}{
+		{
+			desc:     "unknown provider",
+			name:     "foobar",
+			config:   exec.NewDefaultConfig(),
+			expected: "unrecognized DNS provider: foobar",
+		},
+		{
+			desc:     "configuration of another provider",
+			name:     "exec",
+			config:   &struct{}{},
+			expected: "invalid configuration: expected *exec.Config or map[string]any, got *struct {}",
+		},
+		{
+			desc:     "unknown field",
+			name:     "exec",
+			config:   map[string]any{"Foo": "bar"},
+			expected: `invalid configuration: unknown field "Foo"`,
+		},
+		{
+			desc:     "invalid field value",
+			name:     "exec",
+			config:   map[string]any{"Program": 1},
+			expected: `invalid configuration: field "Program": expected string, got int`,
+		},
+		{
+			desc:     "invalid duration",
+			name:     "exec",
+			config:   map[string]any{"Program": "abc", "PropagationTimeout": "two minutes"},
+			expected: `invalid configuration: field "PropagationTimeout": time: invalid duration "two minutes"`,
+		},
+		{
+			desc:     "truncated number",
+			name:     "exec",
+			config:   map[string]any{"Program": "abc", "PollingInterval": 1.5},
+			expected: `invalid configuration: field "PollingInterval": the value 1.5 cannot be represented as time.Duration`,
+		},
+		{
+			desc:     "nil configuration",
+			name:     "exec",
+			config:   (*exec.Config)(nil),
+			expected: "invalid configuration: the configuration is nil",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			provider, err := NewDNSChallengeProviderByNameConfig(test.name, test.config)
+			require.EqualError(t, err, test.expected)
+			assert.Nil(t, provider)
+		})
+	}
+}
@@ -88,7 +88,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 	client := internal.NewClient()
 
-	client.HTTPClient = clientdebug.Wrap(tr.Wrap(config.HTTPClient))
+	client.HTTPClient = clientdebug.Wrap(tr.Wrap(config.HTTPClient), clientdebug.WithEnvNamespace(envNamespace))
 
 	return &DNSProvider{config: config, client: client}, nil
 }
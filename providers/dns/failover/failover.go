@@ -0,0 +1,114 @@
+// Package failover implements a DNS provider which falls back to a secondary DNS provider when the primary one fails.
+package failover
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/log"
+)
+
+var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+
+// Config Provider configuration.
+type Config struct {
+	Primary   challenge.Provider
+	Secondary challenge.Provider
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+
+	// presented keeps the provider used to present a record.
+	presented   map[string]challenge.Provider
+	presentedMu sync.Mutex
+}
+
+// NewDNSProviderConfig returns a new DNS provider which uses the secondary provider
+// when the primary provider fails to present a record.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("failover: the configuration is nil")
+	}
+
+	if config.Primary == nil || config.Secondary == nil {
+		return nil, errors.New("failover: the primary and the secondary providers are required")
+	}
+
+	return &DNSProvider{
+		config:    config,
+		presented: make(map[string]challenge.Provider),
+	}, nil
+}
+
+// Present creates a TXT record with the primary provider,
+// or with the secondary provider if the primary provider fails
+// (the records partially created by the primary provider are removed before).
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	provider := d.config.Primary
+
+	errP := provider.Present(domain, token, keyAuth)
+	if errP != nil {
+		log.Infof("[%s] failover: primary provider failed, using the secondary provider: %v", domain, errP)
+
+		// The primary provider can fail after the creation of some records: they are removed (best effort).
+		if errC := provider.CleanUp(domain, token, keyAuth); errC != nil {
+			log.Warnf("[%s] failover: primary provider cleanup failed: %v", domain, errC)
+		}
+
+		provider = d.config.Secondary
+
+		errS := provider.Present(domain, token, keyAuth)
+		if errS != nil {
+			return fmt.Errorf("failover: primary: %w, secondary: %w", errP, errS)
+		}
+	}
+
+	d.presentedMu.Lock()
+	d.presented[token] = provider
+	d.presentedMu.Unlock()
+
+	return nil
+}
+
+// CleanUp removes the TXT record with the provider used to create it.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	d.presentedMu.Lock()
+	provider, ok := d.presented[token]
+	delete(d.presented, token)
+	d.presentedMu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("failover: unknown record for '%s'", domain)
+	}
+
+	err := provider.CleanUp(domain, token, keyAuth)
+	if err != nil {
+		return fmt.Errorf("failover: %w", err)
+	}
+
+	return nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+// The longest timeout and interval of both providers are used,
+// because the provider used to present a record is only known after the call to Present.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	for _, provider := range []challenge.Provider{d.config.Primary, d.config.Secondary} {
+		t, i := dns01.DefaultPropagationTimeout, dns01.DefaultPollingInterval
+
+		if p, ok := provider.(challenge.ProviderTimeout); ok {
+			t, i = p.Timeout()
+		}
+
+		timeout = max(timeout, t)
+		interval = max(interval, i)
+	}
+
+	return timeout, interval
+}
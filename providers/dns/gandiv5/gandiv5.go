@@ -16,6 +16,7 @@ import (
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/go-acme/lego/v4/providers/dns/gandiv5/internal"
 	"github.com/go-acme/lego/v4/providers/dns/internal/clientdebug"
+	"github.com/go-acme/lego/v4/providers/dns/internal/ttl"
 )
 
 // Environment variables names.
@@ -103,9 +104,9 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("gandiv5: credentials information are missing")
 	}
 
-	if config.TTL < minTTL {
-		return nil, fmt.Errorf("gandiv5: invalid TTL, TTL (%d) must be greater than %d", config.TTL, minTTL)
-	}
+	cfg := *config
+	cfg.TTL = ttl.Range{Min: minTTL}.Clamp("gandiv5", config.TTL)
+	config = &cfg
 
 	client := internal.NewClient(config.APIKey, config.PersonalAccessToken)
 
@@ -122,7 +123,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		client.HTTPClient = config.HTTPClient
 	}
 
-	client.HTTPClient = clientdebug.Wrap(client.HTTPClient)
+	client.HTTPClient = clientdebug.Wrap(client.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))
 
 	return &DNSProvider{
 		config:          config,
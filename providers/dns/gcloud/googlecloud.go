@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"slices"
 	"strconv"
 	"time"
 
@@ -46,7 +47,10 @@ const (
 
 const changeStatusDone = "done"
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout = (*DNSProvider)(nil)
+	_ challenge.ProviderBatch   = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -179,7 +183,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("googlecloud: unable to create Google Cloud DNS service: client is nil")
 	}
 
-	svc, err := gdns.NewService(context.Background(), option.WithHTTPClient(clientdebug.Wrap(config.HTTPClient)))
+	svc, err := gdns.NewService(context.Background(), option.WithHTTPClient(clientdebug.Wrap(config.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))))
 	if err != nil {
 		return nil, fmt.Errorf("googlecloud: unable to create Google Cloud DNS service: %w", err)
 	}
@@ -329,6 +333,106 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	return nil
 }
 
+// PresentBatch creates the TXT records of several challenges,
+// with a single change per managed zone.
+func (d *DNSProvider) PresentBatch(items []challenge.BatchItem) error {
+	ctx := context.Background()
+
+	zones, err := d.groupByZone(items)
+	if err != nil {
+		return fmt.Errorf("googlecloud: %w", err)
+	}
+
+	for _, zone := range zones {
+		change := &gdns.Change{}
+
+		for _, fqdn := range zone.fqdns {
+			existingRrSet, err := d.findTxtRecords(zone.name, fqdn)
+			if err != nil {
+				return fmt.Errorf("googlecloud: %w", err)
+			}
+
+			rec := &gdns.ResourceRecordSet{
+				Name: fqdn,
+				Ttl:  int64(d.config.TTL),
+				Type: "TXT",
+			}
+
+			for _, rrSet := range existingRrSet {
+				for _, rr := range rrSet.Rrdatas {
+					rec.Rrdatas = append(rec.Rrdatas, mustUnquote(rr))
+				}
+			}
+
+			var modified bool
+
+			for _, value := range zone.values[fqdn] {
+				if slices.Contains(rec.Rrdatas, value) {
+					log.Printf("skip: the record already exists: %s", value)
+					continue
+				}
+
+				rec.Rrdatas = append(rec.Rrdatas, value)
+				modified = true
+			}
+
+			if !modified {
+				continue
+			}
+
+			// The existing records are replaced by the new record set inside the same change.
+			change.Deletions = append(change.Deletions, existingRrSet...)
+			change.Additions = append(change.Additions, rec)
+		}
+
+		if len(change.Additions) == 0 {
+			continue
+		}
+
+		if err = d.applyChanges(ctx, zone.name, change); err != nil {
+			return fmt.Errorf("googlecloud: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// CleanUpBatch removes the TXT records of several challenges,
+// with a single change per managed zone.
+func (d *DNSProvider) CleanUpBatch(items []challenge.BatchItem) error {
+	zones, err := d.groupByZone(items)
+	if err != nil {
+		return fmt.Errorf("googlecloud: %w", err)
+	}
+
+	var errs []error
+
+	for _, zone := range zones {
+		change := &gdns.Change{}
+
+		for _, fqdn := range zone.fqdns {
+			records, err := d.findTxtRecords(zone.name, fqdn)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("googlecloud: %w", err))
+				continue
+			}
+
+			change.Deletions = append(change.Deletions, records...)
+		}
+
+		if len(change.Deletions) == 0 {
+			continue
+		}
+
+		_, err = d.client.Changes.Create(d.config.Project, zone.name, change).Do()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("googlecloud: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 // Timeout customizes the timeout values used by the ACME package for checking
 // DNS record validity.
 func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
@@ -398,6 +502,44 @@ func (d *DNSProvider) lookupHostedZoneID(domain string) (string, []*gdns.Managed
 	return authZone, zones.ManagedZones, nil
 }
 
+// managedZoneChallenges the challenge records of a managed zone.
+type managedZoneChallenges struct {
+	name string
+
+	// fqdns keeps the order of the records.
+	fqdns  []string
+	values map[string][]string
+}
+
+func (d *DNSProvider) groupByZone(items []challenge.BatchItem) ([]*managedZoneChallenges, error) {
+	var zones []*managedZoneChallenges
+
+	for _, item := range items {
+		info := dns01.GetChallengeInfo(item.Domain, item.KeyAuth)
+
+		name, err := d.getHostedZone(info.EffectiveFQDN)
+		if err != nil {
+			return nil, err
+		}
+
+		idx := slices.IndexFunc(zones, func(z *managedZoneChallenges) bool { return z.name == name })
+		if idx < 0 {
+			zones = append(zones, &managedZoneChallenges{name: name, values: make(map[string][]string)})
+			idx = len(zones) - 1
+		}
+
+		zone := zones[idx]
+
+		if _, ok := zone.values[info.EffectiveFQDN]; !ok {
+			zone.fqdns = append(zone.fqdns, info.EffectiveFQDN)
+		}
+
+		zone.values[info.EffectiveFQDN] = append(zone.values[info.EffectiveFQDN], info.Value)
+	}
+
+	return zones, nil
+}
+
 func (d *DNSProvider) findTxtRecords(zone, fqdn string) ([]*gdns.ResourceRecordSet, error) {
 	recs, err := d.client.ResourceRecordSets.List(d.config.Project, zone).Name(fqdn).Type("TXT").Do()
 	if err != nil {
@@ -10,8 +10,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/go-acme/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/dns/v1"
@@ -306,6 +308,93 @@ func TestPresentSkipExistingRR(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestDNSProvider_PresentBatch(t *testing.T) {
+	var changes []dns.Change
+
+	provider := mockBuilder().
+		// lookupHostedZoneID
+		Route("GET /dns/v1/projects/manhattan/managedZones/test",
+			servermock.JSONEncode(&dns.ManagedZone{Name: "test", DnsName: "example.com.", Visibility: "public"})).
+		// findTxtRecords
+		Route("GET /dns/v1/projects/manhattan/managedZones/test/rrsets",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				resp := &dns.ResourceRecordSetsListResponse{}
+
+				if req.URL.Query().Get("name") == "_acme-challenge.example.com." {
+					resp.Rrsets = []*dns.ResourceRecordSet{{
+						Name:    "_acme-challenge.example.com.",
+						Rrdatas: []string{`"huji"`},
+						Ttl:     120,
+						Type:    "TXT",
+					}}
+				}
+
+				if err := json.NewEncoder(rw).Encode(resp); err != nil {
+					http.Error(rw, err.Error(), http.StatusInternalServerError)
+					return
+				}
+			})).
+		// applyChanges [Create]
+		Route("POST /dns/v1/projects/manhattan/managedZones/test/changes",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				var chgReq dns.Change
+				if err := json.NewDecoder(req.Body).Decode(&chgReq); err != nil {
+					http.Error(rw, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				changes = append(changes, chgReq)
+
+				chgResp := chgReq
+				chgResp.Status = changeStatusDone
+
+				if err := json.NewEncoder(rw).Encode(chgResp); err != nil {
+					http.Error(rw, err.Error(), http.StatusInternalServerError)
+					return
+				}
+			})).
+		Build(t)
+
+	provider.config.ZoneID = "test"
+
+	// The wildcard and the apex share the same TXT record set.
+	items := []challenge.BatchItem{
+		{Domain: "example.com", KeyAuth: "123456d=="},
+		{Domain: "example.com", KeyAuth: "789012d=="},
+		{Domain: "www.example.com", KeyAuth: "345678d=="},
+	}
+
+	err := provider.PresentBatch(items)
+	require.NoError(t, err)
+
+	require.Len(t, changes, 1)
+
+	expected := dns.Change{
+		Additions: []*dns.ResourceRecordSet{
+			{
+				Name:    "_acme-challenge.example.com.",
+				Rrdatas: []string{"huji", "O2UTPYgIzRNt5N27EVcNKDxv6goSF7ru3zi3chZXKUw", "yQ_YwsBcaJHSI_lQ7oweUgfU_e38WDctHZXJ2fLSDAw"},
+				Ttl:     120,
+				Type:    "TXT",
+			},
+			{
+				Name:    "_acme-challenge.www.example.com.",
+				Rrdatas: []string{"M0Sm3EPS2xdI0LkWvBx7c6Z8Rj9MmvE-qwnMxg6rhLQ"},
+				Ttl:     120,
+				Type:    "TXT",
+			},
+		},
+		Deletions: []*dns.ResourceRecordSet{{
+			Name:    "_acme-challenge.example.com.",
+			Rrdatas: []string{`"huji"`},
+			Ttl:     120,
+			Type:    "TXT",
+		}},
+	}
+
+	assert.Equal(t, expected, changes[0])
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
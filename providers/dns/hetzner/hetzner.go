@@ -29,6 +29,8 @@ const (
 
 const minTTL = 60
 
+var _ challenge.ProviderBatch = (*DNSProvider)(nil)
+
 var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
 
 // Config is used to configure the creation of the DNSProvider.
@@ -156,3 +158,39 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	return d.provider.CleanUp(domain, token, keyAuth)
 }
+
+// PresentBatch creates the TXT records of several challenges.
+// The records of the same RRSet are created with a single API call (Hetzner Cloud API only).
+func (d *DNSProvider) PresentBatch(items []challenge.BatchItem) error {
+	if provider, ok := d.provider.(challenge.ProviderBatch); ok {
+		return provider.PresentBatch(items)
+	}
+
+	for _, item := range items {
+		err := d.provider.Present(item.Domain, item.Token, item.KeyAuth)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CleanUpBatch removes the TXT records of several challenges.
+// The records of the same RRSet are removed with a single API call (Hetzner Cloud API only).
+func (d *DNSProvider) CleanUpBatch(items []challenge.BatchItem) error {
+	if provider, ok := d.provider.(challenge.ProviderBatch); ok {
+		return provider.CleanUpBatch(items)
+	}
+
+	var errs []error
+
+	for _, item := range items {
+		err := d.provider.CleanUp(item.Domain, item.Token, item.KeyAuth)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
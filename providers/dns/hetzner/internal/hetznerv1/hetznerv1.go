@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"time"
 
 	"github.com/cenkalti/backoff/v5"
+	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/go-acme/lego/v4/platform/wait"
@@ -52,10 +54,15 @@ func NewDefaultConfig() *Config {
 	}
 }
 
+var _ challenge.ProviderBatch = (*DNSProvider)(nil)
+
 // DNSProvider implements the challenge.Provider interface.
 type DNSProvider struct {
 	config *Config
 	client *internal.Client
+
+	// only for testing purpose.
+	findZoneByFqdn func(fqdn string) (string, error)
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Hetzner.
@@ -91,8 +98,9 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	}
 
 	return &DNSProvider{
-		config: config,
-		client: client,
+		config:         config,
+		client:         client,
+		findZoneByFqdn: dns01.FindZoneByFqdn,
 	}, nil
 }
 
@@ -100,82 +108,110 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	ctx := context.Background()
 
-	info := dns01.GetChallengeInfo(domain, keyAuth)
-
-	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
+	rrset, err := d.newRRSetChallenges(domain, keyAuth)
 	if err != nil {
-		return fmt.Errorf("hetzner: could not find zone for domain %q: %w", domain, err)
+		return fmt.Errorf("hetzner: %w", err)
 	}
 
-	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, authZone)
+	action, err := d.client.AddRRSetRecords(ctx, rrset.zone, "TXT", rrset.name, d.config.TTL, rrset.records)
 	if err != nil {
-		return fmt.Errorf("hetzner: %w", err)
+		return fmt.Errorf("hetzner: add RRSet records: %w", err)
 	}
 
-	subDomainPunnycoded, err := idna.ToASCII(dns01.UnFqdn(subDomain))
+	err = d.waitAction(ctx, action.ID)
 	if err != nil {
-		return fmt.Errorf("hetzner: %w", err)
+		return fmt.Errorf("hetzner: wait (add RRSet records): %w", err)
 	}
 
-	zone, err := idna.ToASCII(dns01.UnFqdn(authZone))
+	return nil
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	ctx := context.Background()
+
+	rrset, err := d.newRRSetChallenges(domain, keyAuth)
 	if err != nil {
 		return fmt.Errorf("hetzner: %w", err)
 	}
 
-	records := []internal.Record{{Value: strconv.Quote(info.Value)}}
-
-	action, err := d.client.AddRRSetRecords(ctx, zone, "TXT", subDomainPunnycoded, d.config.TTL, records)
+	action, err := d.client.RemoveRRSetRecords(ctx, rrset.zone, "TXT", rrset.name, rrset.records)
 	if err != nil {
-		return fmt.Errorf("hetzner: add RRSet records: %w", err)
+		return fmt.Errorf("hetzner: remove RRSet records: %w", err)
 	}
 
 	err = d.waitAction(ctx, action.ID)
 	if err != nil {
-		return fmt.Errorf("hetzner: wait (add RRSet records): %w", err)
+		return fmt.Errorf("hetzner: wait (remove RRSet records): %w", err)
 	}
 
 	return nil
 }
 
-// CleanUp removes the TXT record matching the specified parameters.
-func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+// PresentBatch creates the TXT records of several challenges,
+// with a single call per RRSet (ex: the challenges of a wildcard domain and of its apex domain).
+// The actions are started before waiting for their completion.
+func (d *DNSProvider) PresentBatch(items []challenge.BatchItem) error {
 	ctx := context.Background()
 
-	info := dns01.GetChallengeInfo(domain, keyAuth)
-
-	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
+	rrsets, err := d.groupByRRSet(items)
 	if err != nil {
-		return fmt.Errorf("hetzner: could not find zone for domain %q: %w", domain, err)
+		return fmt.Errorf("hetzner: %w", err)
 	}
 
-	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, authZone)
-	if err != nil {
-		return fmt.Errorf("hetzner: %w", err)
+	var actionIDs []int64
+
+	for _, rrset := range rrsets {
+		action, err := d.client.AddRRSetRecords(ctx, rrset.zone, "TXT", rrset.name, d.config.TTL, rrset.records)
+		if err != nil {
+			return fmt.Errorf("hetzner: add RRSet records: %w", err)
+		}
+
+		actionIDs = append(actionIDs, action.ID)
 	}
 
-	subDomainPunnycoded, err := idna.ToASCII(dns01.UnFqdn(subDomain))
-	if err != nil {
-		return fmt.Errorf("hetzner: %w", err)
+	for _, actionID := range actionIDs {
+		err = d.waitAction(ctx, actionID)
+		if err != nil {
+			return fmt.Errorf("hetzner: wait (add RRSet records): %w", err)
+		}
 	}
 
-	zone, err := idna.ToASCII(dns01.UnFqdn(authZone))
+	return nil
+}
+
+// CleanUpBatch removes the TXT records of several challenges, with a single call per RRSet.
+func (d *DNSProvider) CleanUpBatch(items []challenge.BatchItem) error {
+	ctx := context.Background()
+
+	rrsets, err := d.groupByRRSet(items)
 	if err != nil {
 		return fmt.Errorf("hetzner: %w", err)
 	}
 
-	records := []internal.Record{{Value: strconv.Quote(info.Value)}}
+	var (
+		errs      []error
+		actionIDs []int64
+	)
 
-	action, err := d.client.RemoveRRSetRecords(ctx, zone, "TXT", subDomainPunnycoded, records)
-	if err != nil {
-		return fmt.Errorf("hetzner: remove RRSet records: %w", err)
+	for _, rrset := range rrsets {
+		action, err := d.client.RemoveRRSetRecords(ctx, rrset.zone, "TXT", rrset.name, rrset.records)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("hetzner: remove RRSet records: %w", err))
+			continue
+		}
+
+		actionIDs = append(actionIDs, action.ID)
 	}
 
-	err = d.waitAction(ctx, action.ID)
-	if err != nil {
-		return fmt.Errorf("hetzner: wait (remove RRSet records): %w", err)
+	for _, actionID := range actionIDs {
+		err = d.waitAction(ctx, actionID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("hetzner: wait (remove RRSet records): %w", err))
+		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 // Timeout returns the timeout and interval to use when checking for DNS propagation.
@@ -207,3 +243,66 @@ func (d *DNSProvider) waitAction(ctx context.Context, actionID int64) error {
 		backoff.WithMaxElapsedTime(d.config.PropagationTimeout),
 	)
 }
+
+// rrsetChallenges the challenge records of an RRSet.
+type rrsetChallenges struct {
+	zone    string
+	name    string
+	records []internal.Record
+}
+
+func (d *DNSProvider) newRRSetChallenges(domain, keyAuth string) (*rrsetChallenges, error) {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	authZone, err := d.findZoneByFqdn(info.EffectiveFQDN)
+	if err != nil {
+		return nil, fmt.Errorf("could not find zone for domain %q: %w", domain, err)
+	}
+
+	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, authZone)
+	if err != nil {
+		return nil, err
+	}
+
+	subDomainPunnycoded, err := idna.ToASCII(dns01.UnFqdn(subDomain))
+	if err != nil {
+		return nil, err
+	}
+
+	zone, err := idna.ToASCII(dns01.UnFqdn(authZone))
+	if err != nil {
+		return nil, err
+	}
+
+	return &rrsetChallenges{
+		zone:    zone,
+		name:    subDomainPunnycoded,
+		records: []internal.Record{{Value: strconv.Quote(info.Value)}},
+	}, nil
+}
+
+// groupByRRSet groups the challenge records by RRSet, in the order of the items.
+func (d *DNSProvider) groupByRRSet(items []challenge.BatchItem) ([]*rrsetChallenges, error) {
+	var rrsets []*rrsetChallenges
+
+	for _, item := range items {
+		rrset, err := d.newRRSetChallenges(item.Domain, item.KeyAuth)
+		if err != nil {
+			return nil, err
+		}
+
+		idx := slices.IndexFunc(rrsets, func(r *rrsetChallenges) bool {
+			return r.zone == rrset.zone && r.name == rrset.name
+		})
+		if idx < 0 {
+			rrsets = append(rrsets, rrset)
+			continue
+		}
+
+		if !slices.Contains(rrsets[idx].records, rrset.records[0]) {
+			rrsets[idx].records = append(rrsets[idx].records, rrset.records...)
+		}
+	}
+
+	return rrsets, nil
+}
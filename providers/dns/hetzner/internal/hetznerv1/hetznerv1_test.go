@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/go-acme/lego/v4/platform/tester/servermock"
 	"github.com/stretchr/testify/require"
@@ -133,6 +134,10 @@ func mockBuilder() *servermock.Builder[*DNSProvider] {
 
 			p.client.BaseURL, _ = url.Parse(server.URL)
 
+			p.findZoneByFqdn = func(_ string) (string, error) {
+				return "example.com.", nil
+			}
+
 			return p, nil
 		},
 		servermock.CheckHeader().
@@ -230,3 +235,64 @@ func TestDNSProvider_CleanUp_running(t *testing.T) {
 	err := provider.CleanUp("example.com", "", "foobar")
 	require.EqualError(t, err, "hetzner: wait (remove RRSet records): action 1 is running")
 }
+
+func TestDNSProvider_PresentBatch(t *testing.T) {
+	provider := mockBuilder().
+		Route("POST /zones/example.com/rrsets/_acme-challenge/TXT/actions/add_records",
+			servermock.ResponseFromFixture("add_rrset_records.json"),
+			servermock.CheckRequestJSONBodyFromFixture("add_rrset_records_batch-request.json")).
+		Route("GET /actions/1",
+			servermock.ResponseFromFixture("get_action_success.json")).
+		Build(t)
+
+	// The wildcard and the apex share the same TXT record set.
+	items := []challenge.BatchItem{
+		{Domain: "example.com", KeyAuth: "foobar"},
+		{Domain: "example.com", KeyAuth: "barfoo"},
+		{Domain: "example.com", KeyAuth: "foobar"},
+	}
+
+	err := provider.PresentBatch(items)
+	require.NoError(t, err)
+}
+
+func TestDNSProvider_CleanUpBatch(t *testing.T) {
+	provider := mockBuilder().
+		Route("POST /zones/example.com/rrsets/_acme-challenge/TXT/actions/remove_records",
+			servermock.ResponseFromFixture("remove_rrset_records.json"),
+			servermock.CheckRequestJSONBodyFromFixture("remove_rrset_records_batch-request.json")).
+		Route("GET /actions/1",
+			servermock.ResponseFromFixture("get_action_success.json")).
+		Build(t)
+
+	// The wildcard and the apex share the same TXT record set.
+	items := []challenge.BatchItem{
+		{Domain: "example.com", KeyAuth: "foobar"},
+		{Domain: "example.com", KeyAuth: "barfoo"},
+	}
+
+	err := provider.CleanUpBatch(items)
+	require.NoError(t, err)
+}
+
+func TestDNSProvider_CleanUpBatch_error(t *testing.T) {
+	provider := mockBuilder().
+		Route("POST /zones/example.com/rrsets/_acme-challenge/TXT/actions/remove_records",
+			servermock.ResponseFromFixture("remove_rrset_records.json"),
+			servermock.CheckRequestJSONBodyFromFixture("remove_rrset_records_batch-request.json")).
+		Route("GET /actions/1",
+			servermock.ResponseFromFixture("get_action_error.json")).
+		Build(t)
+
+	provider.config.PollingInterval = 20 * time.Millisecond
+	provider.config.PropagationTimeout = 1 * time.Second
+
+	// The wildcard and the apex share the same TXT record set.
+	items := []challenge.BatchItem{
+		{Domain: "example.com", KeyAuth: "foobar"},
+		{Domain: "example.com", KeyAuth: "barfoo"},
+	}
+
+	err := provider.CleanUpBatch(items)
+	require.EqualError(t, err, "hetzner: wait (remove RRSet records): action 1: error: action_failed: Action failed")
+}
@@ -0,0 +1,43 @@
+package dns
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-acme/lego/v4/providers/dns/internal/apihosts"
+)
+
+// OverrideAPIHosts overrides the resolution of the API hostnames used by the DNS providers (like a hosts file).
+// The keys are the hostnames, the values are the addresses to use instead (IP or host, with an optional port).
+//
+// The override is applied to a clone of the transport of the HTTP client of each DNS provider created after the call:
+// http.DefaultTransport is not modified.
+// The TLS verification still uses the original hostname.
+// The providers based on an SDK with its own HTTP client are not affected.
+func OverrideAPIHosts(hosts map[string]string) error {
+	for host, address := range hosts {
+		if host == "" || address == "" {
+			return fmt.Errorf("invalid host mapping: %q=%q", host, address)
+		}
+	}
+
+	apihosts.Set(hosts)
+
+	return nil
+}
+
+// ParseAPIHosts parses the host mappings (`host=address`).
+func ParseAPIHosts(values []string) (map[string]string, error) {
+	hosts := make(map[string]string)
+
+	for _, value := range values {
+		host, address, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(host) == "" || strings.TrimSpace(address) == "" {
+			return nil, fmt.Errorf("invalid host mapping %q: the format must be host=address", value)
+		}
+
+		hosts[strings.TrimSpace(host)] = strings.TrimSpace(address)
+	}
+
+	return hosts, nil
+}
@@ -0,0 +1,54 @@
+package dns
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-acme/lego/v4/providers/dns/internal/apihosts"
+	"github.com/go-acme/lego/v4/providers/dns/internal/clientdebug"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverrideAPIHosts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(req.Host))
+	}))
+	t.Cleanup(server.Close)
+
+	err := OverrideAPIHosts(map[string]string{"api.example.invalid": server.Listener.Addr().String()})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { apihosts.Set(nil) })
+
+	// The client of a DNS provider.
+	client := clientdebug.Wrap(&http.Client{})
+
+	resp, err := client.Get("http://api.example.invalid/")
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestOverrideAPIHosts_invalid(t *testing.T) {
+	err := OverrideAPIHosts(map[string]string{"api.example.com": ""})
+	require.EqualError(t, err, `invalid host mapping: "api.example.com"=""`)
+}
+
+func TestParseAPIHosts(t *testing.T) {
+	hosts, err := ParseAPIHosts([]string{"api.example.com=192.0.2.1", " dns.example.com = 192.0.2.2:8443"})
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"api.example.com": "192.0.2.1",
+		"dns.example.com": "192.0.2.2:8443",
+	}
+
+	assert.Equal(t, expected, hosts)
+
+	_, err = ParseAPIHosts([]string{"api.example.com"})
+	require.EqualError(t, err, `invalid host mapping "api.example.com": the format must be host=address`)
+}
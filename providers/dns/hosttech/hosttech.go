@@ -88,6 +88,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	client := internal.NewClient(
 		clientdebug.Wrap(
 			internal.OAuthStaticAccessToken(config.HTTPClient, config.APIKey),
+			clientdebug.WithEnvNamespace(envNamespace),
 		),
 	)
 
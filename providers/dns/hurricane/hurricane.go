@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge"
@@ -53,6 +54,11 @@ func NewDefaultConfig() *Config {
 type DNSProvider struct {
 	config *Config
 	client *internal.Client
+
+	// The API only allows one TXT value per record:
+	// a challenge holds the record from Present to CleanUp, the other challenges on the same record are queued.
+	queuesMu sync.Mutex
+	queues   map[string]chan struct{}
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for Hurricane Electric.
@@ -89,17 +95,28 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		client.HTTPClient = config.HTTPClient
 	}
 
-	client.HTTPClient = clientdebug.Wrap(client.HTTPClient)
+	client.HTTPClient = clientdebug.Wrap(client.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))
 
-	return &DNSProvider{config: config, client: client}, nil
+	return &DNSProvider{
+		config: config,
+		client: client,
+		queues: make(map[string]chan struct{}),
+	}, nil
 }
 
 // Present updates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, _, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	err := d.client.UpdateTxtRecord(context.Background(), dns01.UnFqdn(info.EffectiveFQDN), info.Value)
+	err := d.acquire(info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("hurricane: %w", err)
+	}
+
+	err = d.client.UpdateTxtRecord(context.Background(), dns01.UnFqdn(info.EffectiveFQDN), info.Value)
 	if err != nil {
+		d.release(info.EffectiveFQDN)
+
 		return fmt.Errorf("hurricane: %w", err)
 	}
 
@@ -110,6 +127,8 @@ func (d *DNSProvider) Present(domain, _, keyAuth string) error {
 func (d *DNSProvider) CleanUp(domain, _, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
+	defer d.release(info.EffectiveFQDN)
+
 	err := d.client.UpdateTxtRecord(context.Background(), dns01.UnFqdn(info.EffectiveFQDN), ".")
 	if err != nil {
 		return fmt.Errorf("hurricane: %w", err)
@@ -129,3 +148,44 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 func (d *DNSProvider) Sequential() time.Duration {
 	return d.config.SequenceInterval
 }
+
+// acquire waits until the record is not used by another challenge (ex: the wildcard and the apex of a domain).
+// The challenges are served in their arrival order.
+// The waiting is bounded by the propagation timeout.
+func (d *DNSProvider) acquire(fqdn string) error {
+	d.queuesMu.Lock()
+
+	queue, ok := d.queues[fqdn]
+	if !ok {
+		queue = make(chan struct{}, 1)
+		d.queues[fqdn] = queue
+	}
+
+	d.queuesMu.Unlock()
+
+	timer := time.NewTimer(d.config.PropagationTimeout)
+	defer timer.Stop()
+
+	select {
+	case queue <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("timeout while waiting for the release of the TXT record %s by another challenge", fqdn)
+	}
+}
+
+// release frees the record for the next challenge.
+func (d *DNSProvider) release(fqdn string) {
+	d.queuesMu.Lock()
+	queue, ok := d.queues[fqdn]
+	d.queuesMu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	select {
+	case <-queue:
+	default:
+	}
+}
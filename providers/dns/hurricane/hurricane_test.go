@@ -2,6 +2,7 @@ package hurricane
 
 import (
 	"testing"
+	"time"
 
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/stretchr/testify/require"
@@ -115,6 +116,43 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestDNSProvider_acquire(t *testing.T) {
+	config := NewDefaultConfig()
+	config.Credentials = map[string]string{"example.com": "secret"}
+	config.PropagationTimeout = 100 * time.Millisecond
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	fqdn := "_acme-challenge.example.com."
+
+	require.NoError(t, p.acquire(fqdn))
+
+	// Another record is not blocked.
+	require.NoError(t, p.acquire("_acme-challenge.example.org."))
+
+	err = p.acquire(fqdn)
+	require.EqualError(t, err, "timeout while waiting for the release of the TXT record _acme-challenge.example.com. by another challenge")
+
+	p.config.PropagationTimeout = time.Second
+
+	acquired := make(chan error)
+
+	go func() {
+		acquired <- p.acquire(fqdn)
+	}()
+
+	p.release(fqdn)
+
+	require.NoError(t, <-acquired)
+
+	p.release(fqdn)
+
+	// A release without acquisition doesn't block.
+	p.release(fqdn)
+	p.release("_acme-challenge.example.net.")
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
@@ -100,6 +100,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	client, err := internal.New(
 		clientdebug.Wrap(
 			internal.OAuthStaticAccessToken(config.HTTPClient, config.AccessToken),
+			clientdebug.WithEnvNamespace(envNamespace),
 		),
 		config.APIEndpoint)
 	if err != nil {
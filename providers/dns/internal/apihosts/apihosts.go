@@ -0,0 +1,87 @@
+// Package apihosts overrides the resolution of the API hostnames in the HTTP clients of the DNS providers.
+package apihosts
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	mu      sync.RWMutex
+	mapping map[string]string
+)
+
+// Set defines the addresses to use instead of the API hostnames (IP or host, with an optional port).
+// It only applies to the HTTP clients of the providers created after the call (see Transport).
+func Set(hosts map[string]string) {
+	m := make(map[string]string, len(hosts))
+
+	for host, address := range hosts {
+		m[strings.ToLower(strings.TrimSuffix(host, "."))] = address
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	mapping = m
+}
+
+// Transport returns a clone of the transport (http.DefaultTransport if nil) resolving the API hostnames with the addresses defined by Set.
+// The transport is returned unchanged when no address is defined, or when it's not an *http.Transport.
+// The TLS verification still uses the original hostname.
+func Transport(rt http.RoundTripper) http.RoundTripper {
+	mu.RLock()
+	m := mapping
+	mu.RUnlock()
+
+	if len(m) == 0 {
+		return rt
+	}
+
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	transport, ok := rt.(*http.Transport)
+	if !ok {
+		return rt
+	}
+
+	transport = transport.Clone()
+
+	dial := transport.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext
+	}
+
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return dial(ctx, network, overrideAddress(m, addr))
+	}
+
+	return transport
+}
+
+func overrideAddress(mapping map[string]string, addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	address, ok := mapping[strings.ToLower(host)]
+	if !ok {
+		return addr
+	}
+
+	if _, _, err := net.SplitHostPort(address); err == nil {
+		return address
+	}
+
+	return net.JoinHostPort(strings.Trim(address, "[]"), port)
+}
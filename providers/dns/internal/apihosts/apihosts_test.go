@@ -0,0 +1,78 @@
+package apihosts
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(req.Host))
+	}))
+	t.Cleanup(server.Close)
+
+	Set(map[string]string{"API.example.invalid.": server.Listener.Addr().String()})
+	t.Cleanup(func() { Set(nil) })
+
+	client := &http.Client{Transport: Transport(nil)}
+
+	resp, err := client.Get("http://api.example.invalid/")
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "api.example.invalid", string(body))
+
+	// The default transport is not modified.
+	assert.NotSame(t, http.DefaultTransport, client.Transport)
+
+	respDefault, err := http.Get("http://api.example.invalid/")
+	if err == nil {
+		_ = respDefault.Body.Close()
+	}
+
+	require.Error(t, err)
+}
+
+func TestTransport_noMapping(t *testing.T) {
+	Set(nil)
+
+	transport := &http.Transport{}
+
+	assert.Same(t, transport, Transport(transport))
+	assert.Nil(t, Transport(nil))
+}
+
+func Test_overrideAddress(t *testing.T) {
+	mapping := map[string]string{
+		"api.example.com": "192.0.2.1",
+		"dns.example.com": "192.0.2.2:8443",
+		"v6.example.com":  "2001:db8::1",
+	}
+
+	testCases := []struct {
+		addr     string
+		expected string
+	}{
+		{addr: "api.example.com:443", expected: "192.0.2.1:443"},
+		{addr: "API.example.com:443", expected: "192.0.2.1:443"},
+		{addr: "dns.example.com:443", expected: "192.0.2.2:8443"},
+		{addr: "v6.example.com:443", expected: "[2001:db8::1]:443"},
+		{addr: "other.example.com:443", expected: "other.example.com:443"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.addr, func(t *testing.T) {
+			assert.Equal(t, test.expected, overrideAddress(mapping, test.addr))
+		})
+	}
+}
@@ -11,9 +11,16 @@ import (
 	"strings"
 
 	"github.com/go-acme/lego/v4/platform/config/env"
+	"github.com/go-acme/lego/v4/platform/redact"
+	"github.com/go-acme/lego/v4/providers/dns/internal/apihosts"
 )
 
-const replacement = "***"
+const replacement = redact.Replacement
+
+const (
+	envDebugHTTPClient    = "LEGO_DEBUG_DNS_API_HTTP_CLIENT"
+	envDebugVerbosePrefix = "LEGO_DEBUG_CLIENT_VERBOSE_"
+)
 
 type Option func(*DumpTransport)
 
@@ -38,6 +45,29 @@ func WithValues(values ...string) Option {
 	}
 }
 
+// WithEnvNamespace defines the environment variable namespace of the provider (ex: `CLOUDFLARE_`).
+// It enables the per-provider debug mode (`LEGO_DEBUG_CLIENT_VERBOSE_<PROVIDER>`),
+// and redacts the values of the secret environment variables of the provider.
+func WithEnvNamespace(namespace string) Option {
+	return func(d *DumpTransport) {
+		d.namespace = namespace
+
+		for _, kv := range os.Environ() {
+			key, _, _ := strings.Cut(kv, "=")
+			if !strings.HasPrefix(key, namespace) || !redact.IsSecretKey(strings.TrimPrefix(key, namespace)) {
+				continue
+			}
+
+			v := strings.TrimSpace(env.GetOrFile(strings.TrimSuffix(key, "_FILE")))
+			if v == "" {
+				continue
+			}
+
+			d.replacements = append(d.replacements, v, replacement)
+		}
+	}
+}
+
 func WithHeaders(keys ...string) Option {
 	return func(d *DumpTransport) {
 		d.regexps = append(d.regexps,
@@ -48,6 +78,8 @@ func WithHeaders(keys ...string) Option {
 type DumpTransport struct {
 	rt http.RoundTripper
 
+	namespace string
+
 	replacements []string
 	replacer     *strings.Replacer
 
@@ -110,25 +142,58 @@ func (d *DumpTransport) redact(content []byte) string {
 		data = r.ReplaceAllString(data, "$1: "+replacement)
 	}
 
-	if d.replacer == nil {
-		return data
+	if d.replacer != nil {
+		data = d.replacer.Replace(data)
 	}
 
-	return d.replacer.Replace(data)
+	return redact.String(data)
 }
 
 // Wrap wraps an HTTP client Transport with the [DumpTransport].
+// The transport is only added if `LEGO_DEBUG_DNS_API_HTTP_CLIENT` is enabled,
+// or if `LEGO_DEBUG_CLIENT_VERBOSE_<PROVIDER>` is enabled for the provider (see [WithEnvNamespace]).
+//
+// The requests are also written as structured events
+// to the logger of the context of the requests, if any (see [log.FromContext]).
+//
+// The API hostnames are resolved with the addresses defined by [apihosts.Set], if any, on a clone of the transport.
 func Wrap(client *http.Client, opts ...Option) *http.Client {
-	val, found := os.LookupEnv("LEGO_DEBUG_DNS_API_HTTP_CLIENT")
-	if !found {
+	if _, ok := client.Transport.(*eventTransport); ok {
 		return client
 	}
 
-	if ok, _ := strconv.ParseBool(val); !ok {
-		return client
+	client.Transport = apihosts.Transport(client.Transport)
+
+	d := NewDumpTransport(client.Transport, opts...)
+
+	if isEnabled(envDebugHTTPClient) || isEnabled(verboseEnvKey(d.namespace)) {
+		client.Transport = d
 	}
 
-	client.Transport = NewDumpTransport(client.Transport, opts...)
+	client.Transport = &eventTransport{
+		rt:       client.Transport,
+		provider: strings.ToLower(strings.TrimSuffix(d.namespace, "_")),
+		redact:   d.redact,
+	}
 
 	return client
 }
+
+func verboseEnvKey(namespace string) string {
+	name := strings.TrimSuffix(namespace, "_")
+	if name == "" {
+		return ""
+	}
+
+	return envDebugVerbosePrefix + name
+}
+
+func isEnabled(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	ok, _ := strconv.ParseBool(os.Getenv(key))
+
+	return ok
+}
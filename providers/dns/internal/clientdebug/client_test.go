@@ -12,6 +12,7 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/go-acme/lego/v4/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -80,6 +81,98 @@ func TestWrap_redact_values(t *testing.T) {
 	assertDump(t, now, server, buf, "values.txt")
 }
 
+func TestWrap_redact_namespace(t *testing.T) {
+	t.Setenv("LEGO_DEBUG_CLIENT_VERBOSE_MYPROVIDER", "true")
+
+	t.Setenv("MYPROVIDER_API_TOKEN", "query-aaaa-aaaa")
+	t.Setenv("MYPROVIDER_SECRET", "request-body-aaaa-aaaa")
+	t.Setenv("MYPROVIDER_ZONE", "path-aaaa-aaaa")
+
+	buf := bytes.NewBufferString("")
+
+	server, client, req := setupTest(t, buf, WithEnvNamespace("MYPROVIDER_"))
+
+	now := time.Now()
+
+	resp, err := client.Transport.RoundTrip(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assertDump(t, now, server, buf, "namespace.txt")
+}
+
+func TestWrap_namespace_disabled(t *testing.T) {
+	t.Setenv("LEGO_DEBUG_CLIENT_VERBOSE_OTHER", "true")
+
+	client := Wrap(&http.Client{}, WithEnvNamespace("MYPROVIDER_"))
+
+	// Only the structured events are enabled.
+	require.IsType(t, &eventTransport{}, client.Transport)
+	assert.Nil(t, client.Transport.(*eventTransport).rt)
+}
+
+func TestWrap_events(t *testing.T) {
+	t.Setenv("MYPROVIDER_API_TOKEN", "query-aaaa-aaaa")
+
+	server := httptest.NewServer(fakeResponse())
+	t.Cleanup(server.Close)
+
+	client := Wrap(server.Client(), WithEnvNamespace("MYPROVIDER_"))
+
+	logger := &recordLogger{}
+
+	req := fakeRequest(t, server.URL)
+	req = req.WithContext(log.NewContext(req.Context(), logger))
+
+	resp, err := client.Transport.RoundTrip(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.Len(t, logger.events, 1)
+
+	args := logger.events[0]
+
+	assert.Equal(t, "dns api: request", args[0])
+	assert.Equal(t, []any{"provider", "myprovider", "method", http.MethodGet}, args[1:5])
+	assert.Equal(t, "url", args[5])
+	assert.Equal(t, server.URL+"/path-aaaa-aaaa?foo=***", args[6])
+	assert.Equal(t, []any{"status", http.StatusOK}, args[9:])
+}
+
+func TestWrap_events_noLogger(t *testing.T) {
+	server := httptest.NewServer(fakeResponse())
+	t.Cleanup(server.Close)
+
+	client := Wrap(server.Client())
+
+	resp, err := client.Transport.RoundTrip(fakeRequest(t, server.URL))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestWrap_twice(t *testing.T) {
+	client := Wrap(Wrap(&http.Client{}))
+
+	require.IsType(t, &eventTransport{}, client.Transport)
+	assert.Nil(t, client.Transport.(*eventTransport).rt)
+}
+
+type recordLogger struct {
+	events [][]any
+}
+
+func (l *recordLogger) Debug(msg string, args ...any) { l.record(msg, args) }
+func (l *recordLogger) Info(msg string, args ...any)  { l.record(msg, args) }
+func (l *recordLogger) Warn(msg string, args ...any)  { l.record(msg, args) }
+func (l *recordLogger) Error(msg string, args ...any) { l.record(msg, args) }
+
+func (l *recordLogger) record(msg string, args []any) {
+	l.events = append(l.events, append([]any{msg}, args...))
+}
+
 func fakeRequest(t *testing.T, baseURL string) *http.Request {
 	t.Helper()
 
@@ -0,0 +1,55 @@
+package clientdebug
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-acme/lego/v4/log"
+)
+
+// eventTransport writes the requests as structured events to the logger of the context of the requests.
+// The requests without logger are not modified.
+type eventTransport struct {
+	rt http.RoundTripper
+
+	// the name of the provider (ex: cloudflare), can be empty.
+	provider string
+
+	redact func(content []byte) string
+}
+
+func (e *eventTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt := e.rt
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	logger, ok := log.FromContext(req.Context())
+	if !ok {
+		return rt.RoundTrip(req)
+	}
+
+	start := time.Now()
+
+	resp, err := rt.RoundTrip(req)
+
+	args := []any{
+		"method", req.Method,
+		"url", e.redact([]byte(req.URL.String())),
+		"duration", time.Since(start),
+	}
+
+	if e.provider != "" {
+		args = append([]any{"provider", e.provider}, args...)
+	}
+
+	if err != nil {
+		logger.Warn("dns api: request failed", append(args, "error", err)...)
+
+		return nil, err
+	}
+
+	logger.Debug("dns api: request", append(args, "status", resp.StatusCode)...)
+
+	return resp, nil
+}
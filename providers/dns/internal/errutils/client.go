@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+
+	"github.com/go-acme/lego/v4/platform/redact"
 )
 
 const legoDebugClientVerboseError = "LEGO_DEBUG_CLIENT_VERBOSE_ERROR"
@@ -30,10 +32,10 @@ func (h HTTPDoError) Error() string {
 	}
 
 	if h.err == nil {
-		return msg
+		return redact.String(msg)
 	}
 
-	return msg + fmt.Sprintf(" error: %v", h.err)
+	return redact.String(msg + fmt.Sprintf(" error: %v", h.err))
 }
 
 func (h HTTPDoError) Unwrap() error {
@@ -62,10 +64,10 @@ func (r ReadResponseError) Error() string {
 	msg += fmt.Sprintf(" [status code: %d]", r.StatusCode)
 
 	if r.err == nil {
-		return msg
+		return redact.String(msg)
 	}
 
-	return msg + fmt.Sprintf(" error: %v", r.err)
+	return redact.String(msg + fmt.Sprintf(" error: %v", r.err))
 }
 
 func (r ReadResponseError) Unwrap() error {
@@ -95,10 +97,10 @@ func (u UnmarshalError) Error() string {
 	msg += fmt.Sprintf(" [status code: %d] body: %s", u.StatusCode, string(u.Body))
 
 	if u.err == nil {
-		return msg
+		return redact.String(msg)
 	}
 
-	return msg + fmt.Sprintf(" error: %v", u.err)
+	return redact.String(msg + fmt.Sprintf(" error: %v", u.err))
 }
 
 func (u UnmarshalError) Unwrap() error {
@@ -129,5 +131,5 @@ func (u UnexpectedStatusCodeError) Error() string {
 		msg += fmt.Sprintf(" [request: %s %s]", u.req.Method, u.req.URL)
 	}
 
-	return msg + fmt.Sprintf(" [status code: %d] body: %s", u.StatusCode, string(u.Body))
+	return redact.String(msg + fmt.Sprintf(" [status code: %d] body: %s", u.StatusCode, string(u.Body)))
 }
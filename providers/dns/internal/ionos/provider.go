@@ -16,6 +16,8 @@ import (
 	ionos "github.com/go-acme/lego/v4/providers/dns/internal/ionos/internal"
 )
 
+// MinTTL the minimum TTL allowed by the API.
+// The TTL is clamped by the providers.
 const MinTTL = 300
 
 var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
@@ -45,10 +47,6 @@ func NewDNSProviderConfig(config *Config, baseURL string) (*DNSProvider, error)
 		return nil, errors.New("credentials missing")
 	}
 
-	if config.TTL < MinTTL {
-		return nil, fmt.Errorf("invalid TTL, TTL (%d) must be greater than %d", config.TTL, MinTTL)
-	}
-
 	client, err := ionos.NewClient(config.APIKey)
 	if err != nil {
 		return nil, err
@@ -24,10 +24,9 @@ func TestNewDNSProviderConfig(t *testing.T) {
 			expected: "credentials missing",
 		},
 		{
-			desc:     "invalid TTL",
-			apiKey:   "123",
-			tll:      30,
-			expected: "invalid TTL, TTL (30) must be greater than 300",
+			desc:   "TTL clamped",
+			apiKey: "123",
+			tll:    30,
 		},
 	}
 
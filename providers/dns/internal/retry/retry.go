@@ -0,0 +1,301 @@
+// Package retry retries the API calls of the DNS providers on the transient errors (429 and 5xx responses),
+// with an exponential backoff, a jitter, and the Retry-After header.
+// A response with a Retry-After longer than the maximum delay between two attempts is not retried.
+// The non-idempotent requests (ex: POST) are only retried when the server asks for it (429 or 503 with a Retry-After header).
+package retry
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/providers/dns/internal/apihosts"
+)
+
+const (
+	// DefaultInitialInterval the delay before the first retry.
+	DefaultInitialInterval = 500 * time.Millisecond
+
+	// DefaultMaxInterval the maximum delay between two attempts:
+	// a response with a longer Retry-After is returned instead of being retried earlier than requested by the server.
+	DefaultMaxInterval = 30 * time.Second
+)
+
+type Option func(*Transport)
+
+// WithInitialInterval defines the delay before the first retry.
+func WithInitialInterval(interval time.Duration) Option {
+	return func(t *Transport) {
+		if interval > 0 {
+			t.initialInterval = interval
+		}
+	}
+}
+
+// WithMaxInterval defines the maximum delay between two attempts (see DefaultMaxInterval).
+func WithMaxInterval(interval time.Duration) Option {
+	return func(t *Transport) {
+		if interval > 0 {
+			t.maxInterval = interval
+		}
+	}
+}
+
+// Transport retries the idempotent requests on the 429 and 5xx responses,
+// and the non-idempotent requests on the 429 and 503 responses with a Retry-After header.
+// The delay between two attempts is the Retry-After header of the response, if any,
+// or an exponential backoff with a jitter.
+// A response with a Retry-After longer than the maximum delay is returned.
+type Transport struct {
+	rt http.RoundTripper
+
+	maxRetries int
+
+	initialInterval time.Duration
+	maxInterval     time.Duration
+
+	// the timeout of each attempt (0 means no timeout).
+	attemptTimeout time.Duration
+
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewTransport creates a Transport: the requests are sent at most maxRetries+1 times.
+func NewTransport(rt http.RoundTripper, maxRetries int, opts ...Option) *Transport {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	t := &Transport{
+		rt:              rt,
+		maxRetries:      max(maxRetries, 0),
+		initialInterval: DefaultInitialInterval,
+		maxInterval:     DefaultMaxInterval,
+		sleep:           sleep,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Wrap returns a copy of the client with the retries added to its transport: the client is not modified.
+// The API hosts overrides (see apihosts.Transport) are applied to the wrapped transport,
+// because they only apply to an *http.Transport.
+// The timeout of the client becomes the timeout of each attempt,
+// otherwise the retries would be canceled by the timeout of the first attempt.
+func Wrap(client *http.Client, maxRetries int, opts ...Option) *http.Client {
+	if client == nil {
+		client = &http.Client{}
+	}
+
+	if _, ok := client.Transport.(*Transport); ok {
+		return client
+	}
+
+	t := NewTransport(apihosts.Transport(client.Transport), maxRetries, opts...)
+	t.attemptTimeout = client.Timeout
+
+	wrapped := *client
+	wrapped.Transport = t
+	wrapped.Timeout = 0
+
+	return &wrapped
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.maxRetries == 0 {
+		return t.roundTrip(req)
+	}
+
+	// The body is read by each attempt.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		raw, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		req = req.Clone(req.Context())
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(raw)), nil
+		}
+		req.Body, _ = req.GetBody()
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.roundTrip(req)
+		if err != nil || attempt >= t.maxRetries {
+			return resp, err
+		}
+
+		delay, ok := ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+
+		if !shouldRetry(req.Method, resp.StatusCode, ok) {
+			return resp, nil
+		}
+
+		if !ok {
+			delay = Backoff(attempt, t.initialInterval, t.maxInterval)
+		} else if delay > t.maxInterval {
+			log.Infof("retry: %s %s: status code %d, the server asks to retry in %s: giving up (more than %s)",
+				req.Method, req.URL.Redacted(), resp.StatusCode, delay, t.maxInterval)
+
+			return resp, nil
+		}
+
+		log.Infof("retry: %s %s: status code %d, retrying in %s (%d/%d)",
+			req.Method, req.URL.Redacted(), resp.StatusCode, delay, attempt+1, t.maxRetries)
+
+		// The body is drained to reuse the connection.
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		_ = resp.Body.Close()
+
+		err = t.sleep(req.Context(), delay)
+		if err != nil {
+			return nil, err
+		}
+
+		if req.GetBody != nil {
+			req = req.Clone(req.Context())
+
+			req.Body, err = req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (t *Transport) roundTrip(req *http.Request) (*http.Response, error) {
+	if t.attemptTimeout <= 0 {
+		return t.rt.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.attemptTimeout)
+
+	resp, err := t.rt.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+
+		return nil, err
+	}
+
+	// The context is canceled when the body is closed, because the body is read after the end of RoundTrip.
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+}
+
+// Retryable reports whether a response with this status code can be retried.
+func Retryable(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// shouldRetry reports whether a request can be retried after a response.
+// A non-idempotent request may have been applied by the server before the error:
+// it's only retried when the server asks for it (429 or 503 with a Retry-After header).
+func shouldRetry(method string, statusCode int, hasRetryAfter bool) bool {
+	if Idempotent(method) {
+		return Retryable(statusCode)
+	}
+
+	return hasRetryAfter && (statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable)
+}
+
+// Idempotent reports whether a request with this method can be sent several times with the same effect (RFC 9110, section 9.2.2).
+func Idempotent(method string) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// Backoff returns the delay before the retry following the attempt (starting at 0):
+// an exponential backoff, capped by maxInterval, with a jitter of ±25%.
+func Backoff(attempt int, initialInterval, maxInterval time.Duration) time.Duration {
+	delay := initialInterval
+
+	for range attempt {
+		delay *= 2
+
+		if delay >= maxInterval {
+			delay = maxInterval
+			break
+		}
+	}
+
+	delay = min(delay, maxInterval)
+
+	jitter := time.Duration(rand.Int63n(int64(delay)/2+1)) - delay/4
+
+	return delay + jitter
+}
+
+// ParseRetryAfter parses the value of a Retry-After header: a number of seconds or an HTTP-date.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	seconds, err := strconv.Atoi(value)
+	if err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(date.Sub(now), 0), true
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type cancelBody struct {
+	io.ReadCloser
+
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+
+	b.cancel()
+
+	return err
+}
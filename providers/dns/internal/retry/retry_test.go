@@ -0,0 +1,304 @@
+package retry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/providers/dns/internal/apihosts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupClient(t *testing.T, maxRetries int, handler http.HandlerFunc) (*http.Client, *[]time.Duration, string) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := Wrap(&http.Client{Timeout: 5 * time.Second}, maxRetries)
+
+	var delays []time.Duration
+
+	client.Transport.(*Transport).sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+
+	return client, &delays, server.URL
+}
+
+func TestTransport_RoundTrip(t *testing.T) {
+	var calls atomic.Int32
+
+	client, delays, serverURL := setupClient(t, 3, func(rw http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		if string(body) != "content" {
+			http.Error(rw, "invalid body: "+string(body), http.StatusBadRequest)
+			return
+		}
+
+		switch calls.Add(1) {
+		case 1:
+			rw.Header().Set("Retry-After", "7")
+			rw.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			rw.WriteHeader(http.StatusBadGateway)
+		default:
+			_, _ = rw.Write([]byte("ok"))
+		}
+	})
+
+	req, err := http.NewRequest(http.MethodPut, serverURL, io.NopCloser(strings.NewReader("content")))
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.EqualValues(t, 3, calls.Load())
+
+	require.Len(t, *delays, 2)
+	assert.Equal(t, 7*time.Second, (*delays)[0])
+	assert.InDelta(t, 2*DefaultInitialInterval, (*delays)[1], float64(DefaultInitialInterval/2))
+}
+
+func TestTransport_RoundTrip_nonIdempotent(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		statusCode    int
+		retryAfter    string
+		expectedCalls int32
+	}{
+		{
+			desc:          "429 with Retry-After",
+			statusCode:    http.StatusTooManyRequests,
+			retryAfter:    "1",
+			expectedCalls: 2,
+		},
+		{
+			desc:          "503 with Retry-After",
+			statusCode:    http.StatusServiceUnavailable,
+			retryAfter:    "1",
+			expectedCalls: 2,
+		},
+		{
+			desc:          "429 without Retry-After",
+			statusCode:    http.StatusTooManyRequests,
+			expectedCalls: 1,
+		},
+		{
+			desc:          "502 with Retry-After",
+			statusCode:    http.StatusBadGateway,
+			retryAfter:    "1",
+			expectedCalls: 1,
+		},
+		{
+			desc:          "500",
+			statusCode:    http.StatusInternalServerError,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			var calls atomic.Int32
+
+			client, _, serverURL := setupClient(t, 3, func(rw http.ResponseWriter, _ *http.Request) {
+				if calls.Add(1) > 1 {
+					_, _ = rw.Write([]byte("ok"))
+					return
+				}
+
+				if test.retryAfter != "" {
+					rw.Header().Set("Retry-After", test.retryAfter)
+				}
+
+				rw.WriteHeader(test.statusCode)
+			})
+
+			resp, err := client.Post(serverURL, "text/plain", strings.NewReader("content"))
+			require.NoError(t, err)
+
+			defer func() { _ = resp.Body.Close() }()
+
+			assert.Equal(t, test.expectedCalls, calls.Load())
+		})
+	}
+}
+
+func TestTransport_RoundTrip_maxRetries(t *testing.T) {
+	var calls atomic.Int32
+
+	client, delays, serverURL := setupClient(t, 2, func(rw http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	resp, err := client.Get(serverURL)
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.EqualValues(t, 3, calls.Load())
+	assert.Len(t, *delays, 2)
+}
+
+func TestTransport_RoundTrip_notRetryable(t *testing.T) {
+	var calls atomic.Int32
+
+	client, delays, serverURL := setupClient(t, 2, func(rw http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		rw.WriteHeader(http.StatusNotImplemented)
+	})
+
+	resp, err := client.Get(serverURL)
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusNotImplemented, resp.StatusCode)
+	assert.EqualValues(t, 1, calls.Load())
+	assert.Empty(t, *delays)
+}
+
+func TestTransport_RoundTrip_retryAfterTooLong(t *testing.T) {
+	var calls atomic.Int32
+
+	client, delays, serverURL := setupClient(t, 3, func(rw http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		rw.Header().Set("Retry-After", "120")
+		rw.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	resp, err := client.Get(serverURL)
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	// The server asks to retry later than the maximum delay between two attempts.
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.EqualValues(t, 1, calls.Load())
+	assert.Empty(t, *delays)
+}
+
+func TestWrap(t *testing.T) {
+	original := &http.Client{Timeout: 10 * time.Second}
+
+	client := Wrap(original, 3)
+	client = Wrap(client, 3)
+
+	transport, ok := client.Transport.(*Transport)
+	require.True(t, ok)
+
+	assert.Zero(t, client.Timeout)
+	assert.Equal(t, 10*time.Second, transport.attemptTimeout)
+	assert.Equal(t, http.DefaultTransport, transport.rt)
+
+	// The client is not modified.
+	assert.Nil(t, original.Transport)
+	assert.Equal(t, 10*time.Second, original.Timeout)
+}
+
+func TestWrap_apiHosts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	apihosts.Set(map[string]string{"api.example.invalid": serverURL.Host})
+	t.Cleanup(func() { apihosts.Set(nil) })
+
+	client := Wrap(&http.Client{Timeout: 5 * time.Second}, 0)
+
+	resp, err := client.Get("http://api.example.invalid/")
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestBackoff(t *testing.T) {
+	testCases := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{attempt: 0, expected: 500 * time.Millisecond},
+		{attempt: 1, expected: time.Second},
+		{attempt: 3, expected: 4 * time.Second},
+		{attempt: 10, expected: 30 * time.Second},
+		{attempt: 100, expected: 30 * time.Second},
+	}
+
+	for _, test := range testCases {
+		delay := Backoff(test.attempt, DefaultInitialInterval, DefaultMaxInterval)
+
+		assert.GreaterOrEqual(t, delay, test.expected-test.expected/4)
+		assert.LessOrEqual(t, delay, test.expected+test.expected/4)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{
+			desc: "empty",
+		},
+		{
+			desc:     "seconds",
+			value:    "120",
+			expected: 2 * time.Minute,
+			ok:       true,
+		},
+		{
+			desc:  "negative seconds",
+			value: "-1",
+		},
+		{
+			desc:     "HTTP-date",
+			value:    "Wed, 01 Jan 2025 12:00:30 GMT",
+			expected: 30 * time.Second,
+			ok:       true,
+		},
+		{
+			desc:     "HTTP-date in the past",
+			value:    "Wed, 01 Jan 2025 11:00:00 GMT",
+			expected: 0,
+			ok:       true,
+		},
+		{
+			desc:  "invalid",
+			value: "soon",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			delay, ok := ParseRetryAfter(test.value, now)
+
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.expected, delay)
+		})
+	}
+}
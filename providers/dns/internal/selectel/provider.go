@@ -15,6 +15,8 @@ import (
 	"github.com/go-acme/lego/v4/providers/dns/internal/selectel/internal"
 )
 
+// MinTTL the minimum TTL allowed by the API.
+// The TTL is clamped by the providers.
 const MinTTL = 60
 
 var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
@@ -47,10 +49,6 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("credentials missing")
 	}
 
-	if config.TTL < MinTTL {
-		return nil, fmt.Errorf("invalid TTL, TTL (%d) must be greater than %d", config.TTL, MinTTL)
-	}
-
 	client := internal.NewClient(config.Token)
 
 	if config.HTTPClient != nil {
@@ -1,7 +1,6 @@
 package selectel
 
 import (
-	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -27,10 +26,9 @@ func TestNewDNSProviderConfig(t *testing.T) {
 			expected: "credentials missing",
 		},
 		{
-			desc:     "bad TTL value",
-			token:    "123",
-			ttl:      59,
-			expected: fmt.Sprintf("invalid TTL, TTL (59) must be greater than %d", MinTTL),
+			desc:  "TTL clamped",
+			token: "123",
+			ttl:   59,
 		},
 	}
 
@@ -0,0 +1,40 @@
+// Package ttl enforces the TTL range allowed by the DNS providers.
+package ttl
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/go-acme/lego/v4/log"
+)
+
+// EnvDisableClamping disables the clamping: the TTL is sent as is to the DNS provider.
+const EnvDisableClamping = "LEGO_DISABLE_TTL_CLAMPING"
+
+// Range the TTL range (in seconds) allowed by a DNS provider.
+// A zero bound means no limit.
+type Range struct {
+	Min int
+	Max int
+}
+
+// Clamp returns the TTL inside the range, and logs a warning if the TTL has been changed.
+// The providers use the clamped TTL on a copy of their configuration: the configuration of the caller is not modified.
+func (r Range) Clamp(provider string, ttl int) int {
+	if ok, _ := strconv.ParseBool(os.Getenv(EnvDisableClamping)); ok {
+		return ttl
+	}
+
+	switch {
+	case r.Min > 0 && ttl < r.Min:
+		log.Warnf("%s: the TTL (%d) is lower than the minimum allowed by the provider, %d is used instead.", provider, ttl, r.Min)
+		return r.Min
+
+	case r.Max > 0 && ttl > r.Max:
+		log.Warnf("%s: the TTL (%d) is greater than the maximum allowed by the provider, %d is used instead.", provider, ttl, r.Max)
+		return r.Max
+
+	default:
+		return ttl
+	}
+}
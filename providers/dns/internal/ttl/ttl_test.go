@@ -0,0 +1,53 @@
+package ttl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRange_Clamp(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rng      Range
+		ttl      int
+		expected int
+	}{
+		{
+			desc:     "inside the range",
+			rng:      Range{Min: 60, Max: 3600},
+			ttl:      120,
+			expected: 120,
+		},
+		{
+			desc:     "lower than the minimum",
+			rng:      Range{Min: 60, Max: 3600},
+			ttl:      1,
+			expected: 60,
+		},
+		{
+			desc:     "greater than the maximum",
+			rng:      Range{Min: 60, Max: 3600},
+			ttl:      86400,
+			expected: 3600,
+		},
+		{
+			desc:     "no maximum",
+			rng:      Range{Min: 60},
+			ttl:      86400,
+			expected: 86400,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.rng.Clamp("test", test.ttl))
+		})
+	}
+}
+
+func TestRange_Clamp_disabled(t *testing.T) {
+	t.Setenv(EnvDisableClamping, "true")
+
+	assert.Equal(t, 1, Range{Min: 60}.Clamp("test", 1))
+}
@@ -71,10 +71,9 @@ func TestNewDNSProviderConfig(t *testing.T) {
 			expected: "ionos: credentials missing",
 		},
 		{
-			desc:     "invalid TTL",
-			apiKey:   "123",
-			tll:      30,
-			expected: "ionos: invalid TTL, TTL (30) must be greater than 300",
+			desc:   "TTL clamped",
+			apiKey: "123",
+			tll:    30,
 		},
 	}
 
@@ -68,7 +68,7 @@ func newDmapiProviderConfig(config *Config) (*dmapiProvider, error) {
 		client.HTTPClient = config.HTTPClient
 	}
 
-	client.HTTPClient = clientdebug.Wrap(client.HTTPClient)
+	client.HTTPClient = clientdebug.Wrap(client.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))
 
 	return &dmapiProvider{config: config, client: client}, nil
 }
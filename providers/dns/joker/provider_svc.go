@@ -48,7 +48,7 @@ func newSvcProviderConfig(config *Config) (*svcProvider, error) {
 
 	client := svc.NewClient(config.Username, config.Password)
 
-	client.HTTPClient = clientdebug.Wrap(client.HTTPClient)
+	client.HTTPClient = clientdebug.Wrap(client.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))
 
 	return &svcProvider{config: config, client: client}, nil
 }
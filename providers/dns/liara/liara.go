@@ -14,6 +14,7 @@ import (
 	"github.com/go-acme/lego/v4/log"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/go-acme/lego/v4/providers/dns/internal/clientdebug"
+	"github.com/go-acme/lego/v4/providers/dns/internal/ttl"
 	"github.com/go-acme/lego/v4/providers/dns/liara/internal"
 	"github.com/hashicorp/go-retryablehttp"
 )
@@ -91,13 +92,9 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("liara: APIKey is missing")
 	}
 
-	if config.TTL < minTTL {
-		return nil, fmt.Errorf("liara: invalid TTL, TTL (%d) must be greater than %d", config.TTL, minTTL)
-	}
-
-	if config.TTL > maxTTL {
-		return nil, fmt.Errorf("liara: invalid TTL, TTL (%d) must be lower than %d", config.TTL, maxTTL)
-	}
+	cfg := *config
+	cfg.TTL = ttl.Range{Min: minTTL, Max: maxTTL}.Clamp("liara", config.TTL)
+	config = &cfg
 
 	retryClient := retryablehttp.NewClient()
 
@@ -111,6 +108,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	client := internal.NewClient(
 		clientdebug.Wrap(
 			internal.OAuthStaticAccessToken(retryClient.StandardClient(), config.APIKey),
+			clientdebug.WithEnvNamespace(envNamespace),
 		),
 	)
 
@@ -1,10 +1,10 @@
 package liara
 
 import (
-	"fmt"
 	"testing"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -75,16 +75,14 @@ func TestNewDNSProviderConfig(t *testing.T) {
 			expected: "liara: APIKey is missing",
 		},
 		{
-			desc:     "invalid TTL",
-			ttl:      lowerThanMinTTL,
-			apiKey:   "key",
-			expected: fmt.Sprintf("liara: invalid TTL, TTL (%d) must be greater than %d", lowerThanMinTTL, minTTL),
+			desc:   "TTL lower than the minimum (clamped)",
+			ttl:    lowerThanMinTTL,
+			apiKey: "key",
 		},
 		{
-			desc:     "invalid TTL",
-			ttl:      greaterThanMaxTTL,
-			apiKey:   "key",
-			expected: fmt.Sprintf("liara: invalid TTL, TTL (%d) must be lower than %d", greaterThanMaxTTL, maxTTL),
+			desc:   "TTL greater than the maximum (clamped)",
+			ttl:    greaterThanMaxTTL,
+			apiKey: "key",
 		},
 	}
 
@@ -108,6 +106,20 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestNewDNSProviderConfig_clampedTTL(t *testing.T) {
+	config := NewDefaultConfig()
+	config.APIKey = "key"
+	config.TTL = greaterThanMaxTTL
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	assert.Equal(t, maxTTL, p.config.TTL)
+
+	// The configuration of the caller is not modified.
+	assert.Equal(t, greaterThanMaxTTL, config.TTL)
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
@@ -109,11 +109,10 @@ func TestNewDNSProviderConfig(t *testing.T) {
 			expected: "luadns: credentials missing",
 		},
 		{
-			desc:      "invalid TTL",
+			desc:      "TTL clamped",
 			apiKey:    "123",
 			apiSecret: "456",
 			tll:       30,
-			expected:  "luadns: invalid TTL, TTL (30) must be greater than 300",
 		},
 	}
 
@@ -0,0 +1,112 @@
+package manual
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// confirmServer waits for the HTTP request confirming the creation of a record.
+type confirmServer struct {
+	// the URL called by the external system.
+	url string
+
+	server    *http.Server
+	confirmed chan struct{}
+}
+
+func listenConfirmation(address, token string) (*confirmServer, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("could not start the confirmation server: %w", err)
+	}
+
+	s := &confirmServer{
+		url:       confirmURL(address, listener.Addr(), token),
+		confirmed: make(chan struct{}),
+	}
+
+	var once sync.Once
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /confirm/{token}", func(rw http.ResponseWriter, req *http.Request) {
+		if req.PathValue("token") != token {
+			http.NotFound(rw, req)
+			return
+		}
+
+		once.Do(func() { close(s.confirmed) })
+
+		rw.WriteHeader(http.StatusNoContent)
+	})
+
+	s.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() { _ = s.server.Serve(listener) }()
+
+	return s, nil
+}
+
+func (s *confirmServer) wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for the confirmation on %s: %w", s.url, ctx.Err())
+	case <-s.confirmed:
+		return nil
+	}
+}
+
+func (s *confirmServer) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_ = s.server.Shutdown(ctx)
+}
+
+// confirmURL returns the URL called by an external system to confirm the creation of a record.
+// The host of the configured address is used, `localhost` if it's unspecified (ex: `:8053`).
+func confirmURL(address string, addr net.Addr, token string) string {
+	host, _, _ := net.SplitHostPort(address)
+	if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+		host = "localhost"
+	}
+
+	_, port, _ := net.SplitHostPort(addr.String())
+
+	return (&url.URL{Scheme: "http", Host: net.JoinHostPort(host, port), Path: "/confirm/" + token}).String()
+}
+
+// waitConfirmFile waits for the creation (or the modification) of the file after the start.
+func waitConfirmFile(ctx context.Context, path string, start time.Time, interval time.Duration) error {
+	// Some file systems only store the modification times with a precision of one second.
+	start = start.Truncate(time.Second)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		info, err := os.Stat(path)
+
+		switch {
+		case err == nil && !info.ModTime().Before(start):
+			return nil
+		case err != nil && !errors.Is(err, os.ErrNotExist):
+			return fmt.Errorf("confirmation file: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for the confirmation file %s: %w", path, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package manual
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+)
+
+const (
+	actionPresent = "present"
+	actionCleanUp = "cleanup"
+)
+
+// instruction the machine-readable instruction emitted for each record (one JSON object per line).
+type instruction struct {
+	Action string `json:"action"`
+	Domain string `json:"domain"`
+	Token  string `json:"token"`
+
+	FQDN      string `json:"fqdn"`
+	Zone      string `json:"zone"`
+	SubDomain string `json:"subDomain"`
+	Value     string `json:"value"`
+	TTL       int    `json:"ttl"`
+
+	// how to confirm the creation of the record (only for the present action).
+	ConfirmFile string `json:"confirmFile,omitempty"`
+	ConfirmURL  string `json:"confirmURL,omitempty"`
+}
+
+func newInstruction(action, domain, token string, zoneInfo dns01.ChallengeZoneInfo) instruction {
+	return instruction{
+		Action:    action,
+		Domain:    domain,
+		Token:     token,
+		FQDN:      zoneInfo.EffectiveFQDN,
+		Zone:      zoneInfo.Zone,
+		SubDomain: zoneInfo.SubDomain,
+		Value:     zoneInfo.Value,
+		TTL:       zoneInfo.TTL,
+	}
+}
+
+func (i instruction) write(w io.Writer) error {
+	err := json.NewEncoder(w).Encode(i)
+	if err != nil {
+		return fmt.Errorf("write instruction: %w", err)
+	}
+
+	return nil
+}
+
+// openInstructions opens the destination of the instructions:
+// the standard output (empty), a file descriptor (`fd:<n>`), or a file (the instructions are appended).
+func openInstructions(destination string) (io.Writer, error) {
+	if destination == "" {
+		return os.Stdout, nil
+	}
+
+	if raw, ok := strings.CutPrefix(destination, "fd:"); ok {
+		fd, err := strconv.ParseUint(raw, 10, 0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file descriptor %q: %w", destination, err)
+		}
+
+		return os.NewFile(uintptr(fd), destination), nil
+	}
+
+	file, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open instructions file: %w", err)
+	}
+
+	return file, nil
+}
@@ -1,13 +1,237 @@
+// Package manual implements a DNS provider for solving the DNS-01 challenge by creating the records manually.
 package manual
 
 import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/platform/config/env"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "MANUAL_"
+
+	EnvInstructions    = envNamespace + "INSTRUCTIONS"
+	EnvConfirmFile     = envNamespace + "CONFIRM_FILE"
+	EnvConfirmAddress  = envNamespace + "CONFIRM_ADDRESS"
+	EnvConfirmTimeout  = envNamespace + "CONFIRM_TIMEOUT"
+	EnvConfirmInterval = envNamespace + "CONFIRM_INTERVAL"
 )
 
-// DNSProvider is an implementation of the ChallengeProvider interface.
-type DNSProvider = dns01.DNSProviderManual
+const dnsTemplate = `%s %d IN TXT %q`
+
+var _ challenge.ProviderContext = (*DNSProvider)(nil)
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	// Instructions the destination of the machine-readable instructions (JSON lines):
+	// a file path, or a file descriptor (ex: `fd:3`).
+	// The instructions are printed as text on the standard output when it's empty.
+	Instructions string
+
+	// ConfirmFile the file created (or touched) by an external system to confirm the creation of the record.
+	ConfirmFile string
+	// ConfirmAddress the address of the HTTP server waiting for the confirmation of the creation of the record
+	// (ex: `:8053`), the external system calls `POST /confirm/<token>`.
+	ConfirmAddress string
+	// ConfirmTimeout the maximum waiting time for the confirmation of the creation of the record (0 means no limit).
+	ConfirmTimeout time.Duration
+	// ConfirmInterval the interval between the checks of the ConfirmFile.
+	ConfirmInterval time.Duration
+
+	SequenceInterval time.Duration
+}
+
+// NewDefaultConfig returns a default configuration for the DNSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Instructions:     env.GetOrDefaultString(EnvInstructions, ""),
+		ConfirmFile:      env.GetOrDefaultString(EnvConfirmFile, ""),
+		ConfirmAddress:   env.GetOrDefaultString(EnvConfirmAddress, ""),
+		ConfirmTimeout:   env.GetOrDefaultSecond(EnvConfirmTimeout, 0),
+		ConfirmInterval:  env.GetOrDefaultSecond(EnvConfirmInterval, dns01.DefaultPollingInterval),
+		SequenceInterval: dns01.DefaultPropagationTimeout,
+	}
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+
+	// the destination of the instructions.
+	output io.Writer
+	// the source of the confirmations in interactive mode.
+	input io.Reader
+}
 
 // NewDNSProvider returns a DNSProvider instance.
+// The instructions are printed on the standard output,
+// and the creation of the record is confirmed with the standard input,
+// unless the MANUAL_INSTRUCTIONS, MANUAL_CONFIRM_FILE, or MANUAL_CONFIRM_ADDRESS environment variables are defined.
 func NewDNSProvider() (*DNSProvider, error) {
-	return &DNSProvider{}, nil
+	return NewDNSProviderConfig(NewDefaultConfig())
+}
+
+// NewDNSProviderConfig return a DNSProvider instance configured for the manual creation of the records.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("manual: the configuration of the DNS provider is nil")
+	}
+
+	if config.ConfirmFile != "" && config.ConfirmAddress != "" {
+		return nil, errors.New("manual: the confirmation file and the confirmation address are mutually exclusive")
+	}
+
+	output, err := openInstructions(config.Instructions)
+	if err != nil {
+		return nil, fmt.Errorf("manual: %w", err)
+	}
+
+	return &DNSProvider{
+		config: config,
+		output: output,
+		input:  os.Stdin,
+	}, nil
+}
+
+// Present prints instructions for manually creating the TXT record,
+// and waits for the confirmation of the creation.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	return d.PresentContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentContext is like Present, the waiting for the confirmation is canceled with the context.
+func (d *DNSProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
+	zoneInfo, err := dns01.GetChallengeZoneInfo(domain, keyAuth)
+	if err != nil {
+		return fmt.Errorf("manual: %w", err)
+	}
+
+	err = d.present(ctx, domain, token, zoneInfo)
+	if err != nil {
+		return fmt.Errorf("manual: %w", err)
+	}
+
+	return nil
+}
+
+// CleanUp prints instructions for manually removing the TXT record.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	return d.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpContext is like CleanUp, the context is not used: the removal of the record is not confirmed.
+func (d *DNSProvider) CleanUpContext(_ context.Context, domain, token, keyAuth string) error {
+	zoneInfo, err := dns01.GetChallengeZoneInfo(domain, keyAuth)
+	if err != nil {
+		return fmt.Errorf("manual: %w", err)
+	}
+
+	err = d.cleanUp(domain, token, zoneInfo)
+	if err != nil {
+		return fmt.Errorf("manual: %w", err)
+	}
+
+	return nil
+}
+
+// Sequential All DNS challenges for this provider will be resolved sequentially.
+// Returns the interval between each iteration.
+func (d *DNSProvider) Sequential() time.Duration {
+	return d.config.SequenceInterval
+}
+
+func (d *DNSProvider) present(ctx context.Context, domain, token string, zoneInfo dns01.ChallengeZoneInfo) error {
+	if d.config.ConfirmTimeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, d.config.ConfirmTimeout)
+		defer cancel()
+	}
+
+	inst := newInstruction(actionPresent, domain, token, zoneInfo)
+
+	switch {
+	case d.config.ConfirmAddress != "":
+		// The server is started before the instructions are emitted: the confirmation cannot be missed.
+		server, err := listenConfirmation(d.config.ConfirmAddress, token)
+		if err != nil {
+			return err
+		}
+
+		defer server.close()
+
+		inst.ConfirmURL = server.url
+
+		err = d.writeInstruction(inst)
+		if err != nil {
+			return err
+		}
+
+		return server.wait(ctx)
+
+	case d.config.ConfirmFile != "":
+		inst.ConfirmFile = d.config.ConfirmFile
+
+		start := time.Now()
+
+		err := d.writeInstruction(inst)
+		if err != nil {
+			return err
+		}
+
+		return waitConfirmFile(ctx, d.config.ConfirmFile, start, d.config.ConfirmInterval)
+
+	default:
+		err := d.writeInstruction(inst)
+		if err != nil {
+			return err
+		}
+
+		_, err = bufio.NewReader(d.input).ReadBytes('\n')
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
+
+func (d *DNSProvider) cleanUp(domain, token string, zoneInfo dns01.ChallengeZoneInfo) error {
+	return d.writeInstruction(newInstruction(actionCleanUp, domain, token, zoneInfo))
+}
+
+func (d *DNSProvider) writeInstruction(inst instruction) error {
+	if d.config.Instructions != "" {
+		return inst.write(d.output)
+	}
+
+	switch inst.Action {
+	case actionPresent:
+		_, _ = fmt.Fprintf(d.output, "lego: Please create the following TXT record in your %s zone:\n", inst.Zone)
+		_, _ = fmt.Fprintf(d.output, dnsTemplate+"\n", inst.FQDN, inst.TTL, inst.Value)
+
+		switch {
+		case inst.ConfirmURL != "":
+			_, _ = fmt.Fprintf(d.output, "lego: Send a POST request to %s when you are done\n", inst.ConfirmURL)
+		case inst.ConfirmFile != "":
+			_, _ = fmt.Fprintf(d.output, "lego: Create or touch the file %s when you are done\n", inst.ConfirmFile)
+		default:
+			_, _ = fmt.Fprintf(d.output, "lego: Press 'Enter' when you are done\n")
+		}
+
+	case actionCleanUp:
+		_, _ = fmt.Fprintf(d.output, "lego: You can now remove this TXT record from your %s zone:\n", inst.Zone)
+		_, _ = fmt.Fprintf(d.output, dnsTemplate+"\n", inst.FQDN, inst.TTL, "...")
+	}
+
+	return nil
 }
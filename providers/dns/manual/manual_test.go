@@ -1,10 +1,21 @@
 package manual
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
 	"io"
+	"net"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -59,3 +70,155 @@ func TestDNSProviderManual(t *testing.T) {
 		})
 	}
 }
+
+func fakeZoneInfo() dns01.ChallengeZoneInfo {
+	return dns01.ChallengeZoneInfo{
+		ChallengeInfo: dns01.ChallengeInfo{
+			FQDN:          "_acme-challenge.example.com.",
+			EffectiveFQDN: "_acme-challenge.example.com.",
+			Value:         "pmWkWSBCL51Bfkhn79xPuKBKHz__H6B-mY6G9_eieuM",
+		},
+		Zone:      "example.com.",
+		SubDomain: "_acme-challenge",
+		TTL:       dns01.DefaultTTL,
+	}
+}
+
+func TestDNSProvider_present_text(t *testing.T) {
+	provider, err := NewDNSProviderConfig(NewDefaultConfig())
+	require.NoError(t, err)
+
+	output := &bytes.Buffer{}
+	provider.output = output
+	provider.input = strings.NewReader("\n")
+
+	err = provider.present(t.Context(), "example.com", "token", fakeZoneInfo())
+	require.NoError(t, err)
+
+	err = provider.cleanUp("example.com", "token", fakeZoneInfo())
+	require.NoError(t, err)
+
+	expected := `lego: Please create the following TXT record in your example.com. zone:
+_acme-challenge.example.com. 120 IN TXT "pmWkWSBCL51Bfkhn79xPuKBKHz__H6B-mY6G9_eieuM"
+lego: Press 'Enter' when you are done
+lego: You can now remove this TXT record from your example.com. zone:
+_acme-challenge.example.com. 120 IN TXT "..."
+`
+
+	assert.Equal(t, expected, output.String())
+}
+
+func TestDNSProvider_present_confirmFile(t *testing.T) {
+	dir := t.TempDir()
+
+	config := NewDefaultConfig()
+	config.Instructions = filepath.Join(dir, "instructions.jsonl")
+	config.ConfirmFile = filepath.Join(dir, "confirm")
+	config.ConfirmInterval = 10 * time.Millisecond
+	config.ConfirmTimeout = 5 * time.Second
+
+	provider, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	go func() {
+		// The external system creates the record when it reads the instruction.
+		for {
+			raw, _ := os.ReadFile(config.Instructions)
+			if len(raw) > 0 {
+				_ = os.WriteFile(config.ConfirmFile, nil, 0o600)
+				return
+			}
+
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	err = provider.present(t.Context(), "example.com", "token", fakeZoneInfo())
+	require.NoError(t, err)
+
+	err = provider.cleanUp("example.com", "token", fakeZoneInfo())
+	require.NoError(t, err)
+
+	raw, err := os.ReadFile(config.Instructions)
+	require.NoError(t, err)
+
+	expected := fmt.Sprintf(`{"action":"present","domain":"example.com","token":"token","fqdn":"_acme-challenge.example.com.","zone":"example.com.","subDomain":"_acme-challenge","value":"pmWkWSBCL51Bfkhn79xPuKBKHz__H6B-mY6G9_eieuM","ttl":120,"confirmFile":%q}
+{"action":"cleanup","domain":"example.com","token":"token","fqdn":"_acme-challenge.example.com.","zone":"example.com.","subDomain":"_acme-challenge","value":"pmWkWSBCL51Bfkhn79xPuKBKHz__H6B-mY6G9_eieuM","ttl":120}
+`, config.ConfirmFile)
+
+	assert.Equal(t, expected, string(raw))
+}
+
+func TestDNSProvider_present_confirmFile_timeout(t *testing.T) {
+	dir := t.TempDir()
+
+	config := NewDefaultConfig()
+	config.Instructions = filepath.Join(dir, "instructions.jsonl")
+	config.ConfirmFile = filepath.Join(dir, "confirm")
+	config.ConfirmInterval = 10 * time.Millisecond
+	config.ConfirmTimeout = 50 * time.Millisecond
+
+	// A file older than the instruction doesn't confirm the creation of the record.
+	require.NoError(t, os.WriteFile(config.ConfirmFile, nil, 0o600))
+	require.NoError(t, os.Chtimes(config.ConfirmFile, time.Now().Add(-time.Hour), time.Now().Add(-time.Hour)))
+
+	provider, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	err = provider.present(t.Context(), "example.com", "token", fakeZoneInfo())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestDNSProvider_present_confirmAddress(t *testing.T) {
+	config := NewDefaultConfig()
+	config.Instructions = filepath.Join(t.TempDir(), "instructions.jsonl")
+	config.ConfirmAddress = "127.0.0.1:0"
+	config.ConfirmTimeout = 5 * time.Second
+
+	provider, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	reader, writer := io.Pipe()
+	provider.output = writer
+
+	go func() {
+		var inst instruction
+
+		err := json.NewDecoder(reader).Decode(&inst)
+		if err != nil {
+			return
+		}
+
+		// wrong token
+		resp, err := http.Post(strings.Replace(inst.ConfirmURL, "/token", "/other", 1), "", nil)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+
+		resp, err = http.Post(inst.ConfirmURL, "", nil)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+
+	err = provider.present(t.Context(), "example.com", "token", fakeZoneInfo())
+	require.NoError(t, err)
+}
+
+func TestNewDNSProviderConfig_exclusiveConfirmations(t *testing.T) {
+	config := NewDefaultConfig()
+	config.ConfirmFile = "confirm"
+	config.ConfirmAddress = ":8053"
+
+	_, err := NewDNSProviderConfig(config)
+	require.EqualError(t, err, "manual: the confirmation file and the confirmation address are mutually exclusive")
+}
+
+func Test_confirmURL(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.IPv6unspecified, Port: 8053}
+
+	assert.Equal(t, "http://localhost:8053/confirm/abc", confirmURL(":8053", addr, "abc"))
+	assert.Equal(t, "http://localhost:8053/confirm/abc", confirmURL("0.0.0.0:8053", addr, "abc"))
+	assert.Equal(t, "http://lego.example.com:8053/confirm/abc", confirmURL("lego.example.com:8053", addr, "abc"))
+	assert.Equal(t, "http://[::1]:8053/confirm/abc", confirmURL("[::1]:0", addr, "abc"))
+}
@@ -0,0 +1,140 @@
+// Package mirror implements a DNS provider which presents the TXT record through two DNS providers at the same time.
+//
+// This is useful during a DNS provider migration, when both providers are authoritative for the zone.
+package mirror
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+)
+
+var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+
+// Config Provider configuration.
+type Config struct {
+	Primary   challenge.Provider
+	Secondary challenge.Provider
+
+	// TolerateFailure allows one of the providers to fail:
+	// Present succeeds if at least one of the providers succeeds.
+	// By default, both providers must succeed.
+	TolerateFailure bool
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+
+	// presented keeps the providers that successfully presented a record.
+	presented   map[string][]challenge.Provider
+	presentedMu sync.Mutex
+}
+
+// NewDNSProviderConfig returns a new DNS provider which presents the records through the primary and the secondary providers.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("mirror: the configuration is nil")
+	}
+
+	if config.Primary == nil || config.Secondary == nil {
+		return nil, errors.New("mirror: the primary and the secondary providers are required")
+	}
+
+	return &DNSProvider{
+		config:    config,
+		presented: make(map[string][]challenge.Provider),
+	}, nil
+}
+
+// Present creates a TXT record through the primary and the secondary providers.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	var (
+		succeeded []challenge.Provider
+		errs      []error
+	)
+
+	for _, p := range d.providers() {
+		err := p.Present(domain, token, keyAuth)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.name, err))
+			continue
+		}
+
+		succeeded = append(succeeded, p.Provider)
+	}
+
+	if len(errs) > 0 && (!d.config.TolerateFailure || len(succeeded) == 0) {
+		// Rollback the records created by the providers that succeeded.
+		for _, provider := range succeeded {
+			errs = append(errs, provider.CleanUp(domain, token, keyAuth))
+		}
+
+		return fmt.Errorf("mirror: %w", errors.Join(errs...))
+	}
+
+	d.presentedMu.Lock()
+	d.presented[token] = succeeded
+	d.presentedMu.Unlock()
+
+	return nil
+}
+
+// CleanUp removes the TXT record through the providers that successfully presented it.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	d.presentedMu.Lock()
+	providers, ok := d.presented[token]
+	delete(d.presented, token)
+	d.presentedMu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("mirror: unknown record for '%s'", domain)
+	}
+
+	var errs []error
+
+	for _, provider := range providers {
+		errs = append(errs, provider.CleanUp(domain, token, keyAuth))
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		return fmt.Errorf("mirror: %w", err)
+	}
+
+	return nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+// The longest timeout and interval of both providers are used.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	for _, provider := range d.providers() {
+		t, i := dns01.DefaultPropagationTimeout, dns01.DefaultPollingInterval
+
+		if p, ok := provider.Provider.(challenge.ProviderTimeout); ok {
+			t, i = p.Timeout()
+		}
+
+		timeout = max(timeout, t)
+		interval = max(interval, i)
+	}
+
+	return timeout, interval
+}
+
+type namedProvider struct {
+	challenge.Provider
+
+	name string
+}
+
+func (d *DNSProvider) providers() []namedProvider {
+	return []namedProvider{
+		{Provider: d.config.Primary, name: "primary"},
+		{Provider: d.config.Secondary, name: "secondary"},
+	}
+}
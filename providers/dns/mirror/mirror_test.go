@@ -0,0 +1,148 @@
+package mirror
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProvider struct {
+	presentErr error
+	timeout    time.Duration
+
+	presented []string
+	cleaned   []string
+}
+
+func (f *fakeProvider) Present(domain, _, _ string) error {
+	if f.presentErr != nil {
+		return f.presentErr
+	}
+
+	f.presented = append(f.presented, domain)
+
+	return nil
+}
+
+func (f *fakeProvider) CleanUp(domain, _, _ string) error {
+	f.cleaned = append(f.cleaned, domain)
+
+	return nil
+}
+
+func (f *fakeProvider) Timeout() (timeout, interval time.Duration) {
+	return f.timeout, time.Second
+}
+
+func TestNewDNSProviderConfig(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   *Config
+		expected string
+	}{
+		{
+			desc:     "nil config",
+			expected: "mirror: the configuration is nil",
+		},
+		{
+			desc:     "missing secondary",
+			config:   &Config{Primary: &fakeProvider{}},
+			expected: "mirror: the primary and the secondary providers are required",
+		},
+		{
+			desc:   "success",
+			config: &Config{Primary: &fakeProvider{}, Secondary: &fakeProvider{}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			p, err := NewDNSProviderConfig(test.config)
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestDNSProvider_Present(t *testing.T) {
+	primary := &fakeProvider{}
+	secondary := &fakeProvider{}
+
+	p, err := NewDNSProviderConfig(&Config{Primary: primary, Secondary: secondary})
+	require.NoError(t, err)
+
+	err = p.Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"example.com"}, primary.presented)
+	assert.Equal(t, []string{"example.com"}, secondary.presented)
+
+	err = p.CleanUp("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"example.com"}, primary.cleaned)
+	assert.Equal(t, []string{"example.com"}, secondary.cleaned)
+}
+
+func TestDNSProvider_Present_requireBoth(t *testing.T) {
+	primary := &fakeProvider{}
+	secondary := &fakeProvider{presentErr: errors.New("boom")}
+
+	p, err := NewDNSProviderConfig(&Config{Primary: primary, Secondary: secondary})
+	require.NoError(t, err)
+
+	err = p.Present("example.com", "token", "keyAuth")
+	require.EqualError(t, err, "mirror: secondary: boom")
+
+	// the record created by the primary must be rolled back.
+	assert.Equal(t, []string{"example.com"}, primary.cleaned)
+}
+
+func TestDNSProvider_Present_tolerateFailure(t *testing.T) {
+	primary := &fakeProvider{presentErr: errors.New("boom")}
+	secondary := &fakeProvider{}
+
+	p, err := NewDNSProviderConfig(&Config{Primary: primary, Secondary: secondary, TolerateFailure: true})
+	require.NoError(t, err)
+
+	err = p.Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	err = p.CleanUp("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	assert.Empty(t, primary.cleaned)
+	assert.Equal(t, []string{"example.com"}, secondary.cleaned)
+}
+
+func TestDNSProvider_Present_tolerateFailure_allFailed(t *testing.T) {
+	primary := &fakeProvider{presentErr: errors.New("boom1")}
+	secondary := &fakeProvider{presentErr: errors.New("boom2")}
+
+	p, err := NewDNSProviderConfig(&Config{Primary: primary, Secondary: secondary, TolerateFailure: true})
+	require.NoError(t, err)
+
+	err = p.Present("example.com", "token", "keyAuth")
+	require.EqualError(t, err, "mirror: primary: boom1\nsecondary: boom2")
+}
+
+func TestDNSProvider_Timeout(t *testing.T) {
+	primary := &fakeProvider{timeout: 5 * time.Minute}
+	secondary := &fakeProvider{timeout: 10 * time.Minute}
+
+	p, err := NewDNSProviderConfig(&Config{Primary: primary, Secondary: secondary})
+	require.NoError(t, err)
+
+	timeout, interval := p.Timeout()
+
+	assert.Equal(t, 10*time.Minute, timeout)
+	assert.Equal(t, time.Second, interval)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/go-acme/lego/v4/providers/dns/internal/clientdebug"
+	"github.com/go-acme/lego/v4/providers/dns/internal/ttl"
 	"github.com/namedotcom/go/v4/namecom"
 )
 
@@ -93,9 +94,9 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("namedotcom: API token is required")
 	}
 
-	if config.TTL < minTTL {
-		return nil, fmt.Errorf("namedotcom: invalid TTL, TTL (%d) must be greater than %d", config.TTL, minTTL)
-	}
+	cfg := *config
+	cfg.TTL = ttl.Range{Min: minTTL}.Clamp("namedotcom", config.TTL)
+	config = &cfg
 
 	client := namecom.New(config.Username, config.APIToken)
 
@@ -103,7 +104,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		client.Client = config.HTTPClient
 	}
 
-	client.Client = clientdebug.Wrap(client.Client)
+	client.Client = clientdebug.Wrap(client.Client, clientdebug.WithEnvNamespace(envNamespace))
 
 	if config.Server != "" {
 		client.Server = config.Server
@@ -11,6 +11,7 @@ import (
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/go-acme/lego/v4/providers/dns/internal/clientdebug"
+	"github.com/go-acme/lego/v4/providers/dns/internal/ttl"
 	"github.com/nrdcg/namesilo"
 )
 
@@ -77,9 +78,9 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("namesilo: the configuration of the DNS provider is nil")
 	}
 
-	if config.TTL < defaultTTL || config.TTL > maxTTL {
-		return nil, fmt.Errorf("namesilo: TTL should be in [%d, %d]", defaultTTL, maxTTL)
-	}
+	cfg := *config
+	cfg.TTL = ttl.Range{Min: defaultTTL, Max: maxTTL}.Clamp("namesilo", config.TTL)
+	config = &cfg
 
 	if config.APIKey == "" {
 		return nil, errors.New("namesilo: credentials missing")
@@ -87,7 +88,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 	client := namesilo.NewClient(config.APIKey)
 
-	client.HTTPClient = clientdebug.Wrap(client.HTTPClient)
+	client.HTTPClient = clientdebug.Wrap(client.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))
 
 	return &DNSProvider{client: client, config: config}, nil
 }
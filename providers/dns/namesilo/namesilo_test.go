@@ -33,12 +33,11 @@ func TestNewDNSProvider(t *testing.T) {
 			expected: "namesilo: some credentials information are missing: NAMESILO_API_KEY",
 		},
 		{
-			desc: "unsupported TTL",
+			desc: "TTL clamped",
 			envVars: map[string]string{
 				EnvAPIKey: "A",
 				EnvTTL:    "180",
 			},
-			expected: "namesilo: TTL should be in [3600, 2592000]",
 		},
 	}
 
@@ -81,10 +80,9 @@ func TestNewDNSProviderConfig(t *testing.T) {
 			expected: "namesilo: credentials missing",
 		},
 		{
-			desc:     "unavailable TTL",
-			apiKey:   "A",
-			ttl:      100,
-			expected: "namesilo: TTL should be in [3600, 2592000]",
+			desc:   "TTL clamped",
+			apiKey: "A",
+			ttl:    100,
 		},
 	}
 
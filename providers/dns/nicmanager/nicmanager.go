@@ -13,6 +13,7 @@ import (
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/go-acme/lego/v4/providers/dns/internal/clientdebug"
+	"github.com/go-acme/lego/v4/providers/dns/internal/ttl"
 	"github.com/go-acme/lego/v4/providers/dns/nicmanager/internal"
 )
 
@@ -92,9 +93,9 @@ func NewDNSProvider() (*DNSProvider, error) {
 	config.Email = env.GetOrFile(EnvEmail)
 	config.OTPSecret = env.GetOrFile(EnvOTP)
 
-	if config.TTL < minTTL {
-		return nil, fmt.Errorf("TTL must be higher than %d: %d", minTTL, config.TTL)
-	}
+	cfg := *config
+	cfg.TTL = ttl.Range{Min: minTTL}.Clamp("nicmanager", config.TTL)
+	config = &cfg
 
 	return NewDNSProviderConfig(config)
 }
@@ -129,7 +130,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		client.HTTPClient = config.HTTPClient
 	}
 
-	client.HTTPClient = clientdebug.Wrap(client.HTTPClient)
+	client.HTTPClient = clientdebug.Wrap(client.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))
 
 	return &DNSProvider{client: client, config: config}, nil
 }
@@ -91,7 +91,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, fmt.Errorf("nicru: %w", err)
 	}
 
-	client, err := internal.NewClient(clientdebug.Wrap(oauthClient))
+	client, err := internal.NewClient(clientdebug.Wrap(oauthClient, clientdebug.WithEnvNamespace(envNamespace)))
 	if err != nil {
 		return nil, fmt.Errorf("nicru: unable to build API client: %w", err)
 	}
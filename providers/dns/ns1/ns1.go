@@ -86,7 +86,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
 	}
 
-	client := rest.NewClient(clientdebug.Wrap(config.HTTPClient), rest.SetAPIKey(config.APIKey))
+	client := rest.NewClient(clientdebug.Wrap(config.HTTPClient, clientdebug.WithEnvNamespace(envNamespace)), rest.SetAPIKey(config.APIKey))
 
 	return &DNSProvider{client: client, config: config}, nil
 }
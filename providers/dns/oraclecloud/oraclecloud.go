@@ -148,7 +148,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	}
 
 	if config.HTTPClient != nil {
-		client.HTTPClient = clientdebug.Wrap(config.HTTPClient)
+		client.HTTPClient = clientdebug.Wrap(config.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))
 	}
 
 	return &DNSProvider{client: &client, config: config}, nil
@@ -288,7 +288,7 @@ func newClient(config *Config) (*ovh.Client, error) {
 		client.Client = config.HTTPClient
 	}
 
-	client.Client = clientdebug.Wrap(client.Client)
+	client.Client = clientdebug.Wrap(client.Client, clientdebug.WithEnvNamespace(envNamespace))
 
 	return client, nil
 }
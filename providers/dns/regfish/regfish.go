@@ -92,7 +92,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		client.Client = &http.Client{Timeout: 30 * time.Second}
 	}
 
-	client.Client = clientdebug.Wrap(client.Client)
+	client.Client = clientdebug.Wrap(client.Client, clientdebug.WithEnvNamespace(envNamespace))
 
 	return &DNSProvider{
 		config:    config,
@@ -5,17 +5,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math/rand"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awsretry "github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
 	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/route53"
 	awstypes "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/aws/aws-sdk-go-v2/service/sso"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/cenkalti/backoff/v5"
 	"github.com/go-acme/lego/v4/challenge"
@@ -23,6 +28,7 @@ import (
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/go-acme/lego/v4/platform/wait"
 	"github.com/go-acme/lego/v4/providers/dns/internal/ptr"
+	"github.com/go-acme/lego/v4/providers/dns/internal/retry"
 )
 
 // Environment variables names.
@@ -37,6 +43,13 @@ const (
 	EnvAssumeRoleArn   = envNamespace + "ASSUME_ROLE_ARN"
 	EnvExternalID      = envNamespace + "EXTERNAL_ID"
 	EnvPrivateZone     = envNamespace + "PRIVATE_ZONE"
+	EnvRoleChain       = envNamespace + "ROLE_CHAIN"
+
+	EnvSSOStartURL  = envNamespace + "SSO_START_URL"
+	EnvSSORegion    = envNamespace + "SSO_REGION"
+	EnvSSOAccountID = envNamespace + "SSO_ACCOUNT_ID"
+	EnvSSORoleName  = envNamespace + "SSO_ROLE_NAME"
+	EnvSSOSession   = envNamespace + "SSO_SESSION"
 
 	EnvWaitForRecordSetsChanged = envNamespace + "WAIT_FOR_RECORD_SETS_CHANGED"
 
@@ -45,7 +58,13 @@ const (
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
 )
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout = (*DNSProvider)(nil)
+	_ challenge.ProviderBatch   = (*DNSProvider)(nil)
+	_ challenge.ProviderContext = (*DNSProvider)(nil)
+
+	_ dns01.PropagationConfirmer = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -56,11 +75,22 @@ type Config struct {
 	SessionToken    string
 	Region          string
 
+	// SSO (IAM Identity Center) credentials, from the token cache of the AWS CLI (`aws sso login`).
+	SSOStartURL  string
+	SSORegion    string
+	SSOAccountID string
+	SSORoleName  string
+	// SSOSession the name of the SSO session (`sso-session` section of the AWS CLI configuration):
+	// the cached token is refreshed with its refresh token.
+	SSOSession string
+
 	HostedZoneID  string
 	MaxRetries    int
 	AssumeRoleArn string
 	ExternalID    string
-	PrivateZone   bool
+	// RoleChain the ARNs of the roles assumed in sequence (after AssumeRoleArn), each role with the credentials of the previous role.
+	RoleChain   []string
+	PrivateZone bool
 
 	WaitForRecordSetsChanged bool
 
@@ -78,8 +108,15 @@ func NewDefaultConfig() *Config {
 		MaxRetries:    env.GetOrDefaultInt(EnvMaxRetries, 5),
 		AssumeRoleArn: env.GetOrDefaultString(EnvAssumeRoleArn, ""),
 		ExternalID:    env.GetOrDefaultString(EnvExternalID, ""),
+		RoleChain:     parseRoleChain(env.GetOrDefaultString(EnvRoleChain, "")),
 		PrivateZone:   env.GetOrDefaultBool(EnvPrivateZone, false),
 
+		SSOStartURL:  env.GetOrDefaultString(EnvSSOStartURL, ""),
+		SSORegion:    env.GetOrDefaultString(EnvSSORegion, ""),
+		SSOAccountID: env.GetOrDefaultString(EnvSSOAccountID, ""),
+		SSORoleName:  env.GetOrDefaultString(EnvSSORoleName, ""),
+		SSOSession:   env.GetOrDefaultString(EnvSSOSession, ""),
+
 		WaitForRecordSetsChanged: env.GetOrDefaultBool(EnvWaitForRecordSetsChanged, true),
 
 		TTL:                env.GetOrDefaultInt(EnvTTL, 10),
@@ -92,6 +129,10 @@ func NewDefaultConfig() *Config {
 type DNSProvider struct {
 	client *route53.Client
 	config *Config
+
+	// changeIDs the IDs of the changes of the TXT records, by token.
+	changeIDs   map[string]string
+	changeIDsMu sync.Mutex
 }
 
 // NewDNSProvider returns a DNSProvider instance configured for the AWS Route 53 service.
@@ -102,6 +143,11 @@ type DNSProvider struct {
 //  2. Shared credentials file (defaults to ~/.aws/credentials)
 //  3. Amazon EC2 IAM role
 //
+// The SSO (IAM Identity Center) credentials cached by the AWS CLI are used instead,
+// if AWS_SSO_ACCOUNT_ID, AWS_SSO_ROLE_NAME, and AWS_SSO_START_URL or AWS_SSO_SESSION are set.
+//
+// The roles of AWS_ASSUME_ROLE_ARN and AWS_ROLE_CHAIN are assumed in sequence from these credentials.
+//
 // If AWS_HOSTED_ZONE_ID is not set, Lego tries to determine the correct public hosted zone via the FQDN.
 //
 // See also: https://github.com/aws/aws-sdk-go/wiki/configuring-sdk
@@ -116,7 +162,11 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	}
 
 	if config.Client != nil {
-		return &DNSProvider{client: config.Client, config: config}, nil
+		return &DNSProvider{
+			client:    config.Client,
+			config:    config,
+			changeIDs: make(map[string]string),
+		}, nil
 	}
 
 	ctx := context.Background()
@@ -127,8 +177,9 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	}
 
 	return &DNSProvider{
-		client: route53.NewFromConfig(cfg),
-		config: config,
+		client:    route53.NewFromConfig(cfg),
+		config:    config,
+		changeIDs: make(map[string]string),
 	}, nil
 }
 
@@ -139,7 +190,11 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	ctx := context.Background()
+	return d.PresentContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentContext is like Present, the API calls are canceled with the context.
+func (d *DNSProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	hostedZoneID, err := d.getHostedZoneID(ctx, info.EffectiveFQDN)
@@ -147,33 +202,51 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("route53: failed to determine hosted zone ID: %w", err)
 	}
 
-	records, err := d.getExistingRecordSets(ctx, hostedZoneID, info.EffectiveFQDN)
+	change, err := d.presentChange(ctx, hostedZoneID, info.EffectiveFQDN, []string{info.Value})
 	if err != nil {
 		return fmt.Errorf("route53: %w", err)
 	}
 
-	realValue := `"` + info.Value + `"`
+	changeID, err := d.changeRecords(ctx, hostedZoneID, []awstypes.Change{change})
+	if err != nil {
+		return fmt.Errorf("route53: %w", err)
+	}
 
-	var found bool
+	d.changeIDsMu.Lock()
+	d.changeIDs[token] = changeID
+	d.changeIDsMu.Unlock()
 
-	for _, record := range records {
-		if ptr.Deref(record.Value) == realValue {
-			found = true
-		}
+	return nil
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	return d.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpContext is like CleanUp, the API calls are canceled with the context.
+func (d *DNSProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	d.changeIDsMu.Lock()
+	delete(d.changeIDs, token)
+	d.changeIDsMu.Unlock()
+
+	hostedZoneID, err := d.getHostedZoneID(ctx, info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("failed to determine Route 53 hosted zone ID: %w", err)
 	}
 
-	if !found {
-		records = append(records, awstypes.ResourceRecord{Value: aws.String(realValue)})
+	change, err := d.cleanUpChange(ctx, hostedZoneID, info.EffectiveFQDN, []string{info.Value})
+	if err != nil {
+		return fmt.Errorf("route53: %w", err)
 	}
 
-	recordSet := &awstypes.ResourceRecordSet{
-		Name:            aws.String(info.EffectiveFQDN),
-		Type:            "TXT",
-		TTL:             aws.Int64(int64(d.config.TTL)),
-		ResourceRecords: records,
+	if change == nil {
+		return nil
 	}
 
-	err = d.changeRecord(ctx, awstypes.ChangeActionUpsert, hostedZoneID, recordSet)
+	_, err = d.changeRecords(ctx, hostedZoneID, []awstypes.Change{*change})
 	if err != nil {
 		return fmt.Errorf("route53: %w", err)
 	}
@@ -181,29 +254,180 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 	return nil
 }
 
-// CleanUp removes the TXT record matching the specified parameters.
-func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+// PresentBatch creates the TXT records of several challenges,
+// with a single change batch per hosted zone.
+func (d *DNSProvider) PresentBatch(items []challenge.BatchItem) error {
 	ctx := context.Background()
-	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	hostedZoneID, err := d.getHostedZoneID(ctx, info.EffectiveFQDN)
+	zones, err := d.groupByZone(ctx, items)
 	if err != nil {
-		return fmt.Errorf("failed to determine Route 53 hosted zone ID: %w", err)
+		return fmt.Errorf("route53: failed to determine hosted zone ID: %w", err)
+	}
+
+	for _, zone := range zones {
+		var changes []awstypes.Change
+
+		for _, fqdn := range zone.fqdns {
+			change, err := d.presentChange(ctx, zone.id, fqdn, zone.values[fqdn])
+			if err != nil {
+				return fmt.Errorf("route53: %w", err)
+			}
+
+			changes = append(changes, change)
+		}
+
+		changeID, err := d.changeRecords(ctx, zone.id, changes)
+		if err != nil {
+			return fmt.Errorf("route53: %w", err)
+		}
+
+		d.changeIDsMu.Lock()
+		for _, token := range zone.tokens {
+			d.changeIDs[token] = changeID
+		}
+		d.changeIDsMu.Unlock()
 	}
 
-	existingRecords, err := d.getExistingRecordSets(ctx, hostedZoneID, info.EffectiveFQDN)
+	return nil
+}
+
+// CleanUpBatch removes the TXT records of several challenges,
+// with a single change batch per hosted zone.
+func (d *DNSProvider) CleanUpBatch(items []challenge.BatchItem) error {
+	ctx := context.Background()
+
+	zones, err := d.groupByZone(ctx, items)
 	if err != nil {
-		return fmt.Errorf("route53: %w", err)
+		return fmt.Errorf("route53: failed to determine hosted zone ID: %w", err)
+	}
+
+	var errs []error
+
+	for _, zone := range zones {
+		d.changeIDsMu.Lock()
+		for _, token := range zone.tokens {
+			delete(d.changeIDs, token)
+		}
+		d.changeIDsMu.Unlock()
+
+		var changes []awstypes.Change
+
+		for _, fqdn := range zone.fqdns {
+			change, err := d.cleanUpChange(ctx, zone.id, fqdn, zone.values[fqdn])
+			if err != nil {
+				errs = append(errs, fmt.Errorf("route53: %w", err))
+				continue
+			}
+
+			if change != nil {
+				changes = append(changes, *change)
+			}
+		}
+
+		if len(changes) == 0 {
+			continue
+		}
+
+		_, err = d.changeRecords(ctx, zone.id, changes)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("route53: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// hostedZoneChallenges the challenge records of a hosted zone.
+type hostedZoneChallenges struct {
+	id string
+
+	// fqdns keeps the order of the records.
+	fqdns  []string
+	values map[string][]string
+
+	tokens []string
+}
+
+func (d *DNSProvider) groupByZone(ctx context.Context, items []challenge.BatchItem) ([]*hostedZoneChallenges, error) {
+	var zones []*hostedZoneChallenges
+
+	for _, item := range items {
+		info := dns01.GetChallengeInfo(item.Domain, item.KeyAuth)
+
+		hostedZoneID, err := d.getHostedZoneID(ctx, info.EffectiveFQDN)
+		if err != nil {
+			return nil, err
+		}
+
+		idx := slices.IndexFunc(zones, func(z *hostedZoneChallenges) bool { return z.id == hostedZoneID })
+		if idx < 0 {
+			zones = append(zones, &hostedZoneChallenges{id: hostedZoneID, values: make(map[string][]string)})
+			idx = len(zones) - 1
+		}
+
+		zone := zones[idx]
+
+		if _, ok := zone.values[info.EffectiveFQDN]; !ok {
+			zone.fqdns = append(zone.fqdns, info.EffectiveFQDN)
+		}
+
+		zone.values[info.EffectiveFQDN] = append(zone.values[info.EffectiveFQDN], info.Value)
+		zone.tokens = append(zone.tokens, item.Token)
+	}
+
+	return zones, nil
+}
+
+// presentChange creates the change to add the values to the TXT record set, keeping the existing values.
+func (d *DNSProvider) presentChange(ctx context.Context, hostedZoneID, fqdn string, values []string) (awstypes.Change, error) {
+	records, err := d.getExistingRecordSets(ctx, hostedZoneID, fqdn)
+	if err != nil {
+		return awstypes.Change{}, err
+	}
+
+	for _, value := range values {
+		realValue := `"` + value + `"`
+
+		found := slices.ContainsFunc(records, func(record awstypes.ResourceRecord) bool {
+			return ptr.Deref(record.Value) == realValue
+		})
+
+		if !found {
+			records = append(records, awstypes.ResourceRecord{Value: aws.String(realValue)})
+		}
+	}
+
+	return awstypes.Change{
+		Action: awstypes.ChangeActionUpsert,
+		ResourceRecordSet: &awstypes.ResourceRecordSet{
+			Name:            aws.String(fqdn),
+			Type:            "TXT",
+			TTL:             aws.Int64(int64(d.config.TTL)),
+			ResourceRecords: records,
+		},
+	}, nil
+}
+
+// cleanUpChange creates the change to remove the values from the TXT record set, keeping the other values.
+// It returns nil if there is no TXT record set.
+func (d *DNSProvider) cleanUpChange(ctx context.Context, hostedZoneID, fqdn string, values []string) (*awstypes.Change, error) {
+	existingRecords, err := d.getExistingRecordSets(ctx, hostedZoneID, fqdn)
+	if err != nil {
+		return nil, err
 	}
 
 	if len(existingRecords) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	var nonLegoRecords []awstypes.ResourceRecord
 
 	for _, record := range existingRecords {
-		if ptr.Deref(record.Value) != `"`+info.Value+`"` {
+		isLegoRecord := slices.ContainsFunc(values, func(value string) bool {
+			return ptr.Deref(record.Value) == `"`+value+`"`
+		})
+
+		if !isLegoRecord {
 			nonLegoRecords = append(nonLegoRecords, record)
 		}
 	}
@@ -211,7 +435,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	action := awstypes.ChangeActionUpsert
 
 	recordSet := &awstypes.ResourceRecordSet{
-		Name:            aws.String(info.EffectiveFQDN),
+		Name:            aws.String(fqdn),
 		Type:            "TXT",
 		TTL:             aws.Int64(int64(d.config.TTL)),
 		ResourceRecords: nonLegoRecords,
@@ -224,35 +448,28 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		recordSet.ResourceRecords = existingRecords
 	}
 
-	err = d.changeRecord(ctx, action, hostedZoneID, recordSet)
-	if err != nil {
-		return fmt.Errorf("route53: %w", err)
-	}
-
-	return nil
+	return &awstypes.Change{Action: action, ResourceRecordSet: recordSet}, nil
 }
 
-func (d *DNSProvider) changeRecord(ctx context.Context, action awstypes.ChangeAction, hostedZoneID string, recordSet *awstypes.ResourceRecordSet) error {
+// changeRecords applies the changes and returns the ID of the change.
+func (d *DNSProvider) changeRecords(ctx context.Context, hostedZoneID string, changes []awstypes.Change) (string, error) {
 	recordSetInput := &route53.ChangeResourceRecordSetsInput{
 		HostedZoneId: aws.String(hostedZoneID),
 		ChangeBatch: &awstypes.ChangeBatch{
 			Comment: aws.String("Managed by Lego"),
-			Changes: []awstypes.Change{{
-				Action:            action,
-				ResourceRecordSet: recordSet,
-			}},
+			Changes: changes,
 		},
 	}
 
 	resp, err := d.client.ChangeResourceRecordSets(ctx, recordSetInput)
 	if err != nil {
-		return fmt.Errorf("failed to change record set: %w", err)
+		return "", fmt.Errorf("failed to change record set: %w", err)
 	}
 
 	changeID := resp.ChangeInfo.Id
 
 	if d.config.WaitForRecordSetsChanged {
-		return wait.Retry(ctx,
+		err = wait.Retry(ctx,
 			func() error {
 				resp, err := d.client.GetChange(ctx, &route53.GetChangeInput{Id: changeID})
 				if err != nil {
@@ -268,9 +485,32 @@ func (d *DNSProvider) changeRecord(ctx context.Context, action awstypes.ChangeAc
 			backoff.WithBackOff(backoff.NewConstantBackOff(d.config.PollingInterval)),
 			backoff.WithMaxElapsedTime(d.config.PropagationTimeout),
 		)
+		if err != nil {
+			return "", err
+		}
 	}
 
-	return nil
+	return ptr.Deref(changeID), nil
+}
+
+// ConfirmPropagation reports whether the change of the TXT record is propagated
+// to all the Route 53 authoritative DNS servers (status INSYNC).
+// Implements dns01.PropagationConfirmer.
+func (d *DNSProvider) ConfirmPropagation(domain, token, keyAuth string) (bool, error) {
+	d.changeIDsMu.Lock()
+	changeID, ok := d.changeIDs[token]
+	d.changeIDsMu.Unlock()
+
+	if !ok {
+		return false, fmt.Errorf("route53: unknown change ID for %s", domain)
+	}
+
+	resp, err := d.client.GetChange(context.Background(), &route53.GetChangeInput{Id: aws.String(changeID)})
+	if err != nil {
+		return false, fmt.Errorf("route53: failed to query change status: %w", err)
+	}
+
+	return resp.ChangeInfo.Status == awstypes.ChangeStatusInsync, nil
 }
 
 func (d *DNSProvider) getExistingRecordSets(ctx context.Context, hostedZoneID, fqdn string) ([]awstypes.ResourceRecord, error) {
@@ -346,19 +586,24 @@ func createAWSConfig(ctx context.Context, config *Config) (aws.Config, error) {
 
 	optFns := []func(options *awsconfig.LoadOptions) error{
 		awsconfig.WithRetryer(func() aws.Retryer {
-			return retry.NewStandard(func(options *retry.StandardOptions) {
+			return awsretry.NewStandard(func(options *awsretry.StandardOptions) {
 				options.MaxAttempts = config.MaxRetries
 
-				// It uses a basic exponential backoff algorithm that returns an initial
+				// It uses an exponential backoff algorithm that returns an initial
 				// delay of ~400ms with an upper limit of ~30 seconds which should prevent
 				// causing a high number of consecutive throttling errors.
 				// For reference: Route 53 enforces an account-wide(!) 5req/s query limit.
-				options.Backoff = retry.BackoffDelayerFunc(func(attempt int, err error) (time.Duration, error) {
-					retryCount := min(attempt, 7)
-
-					delay := (1 << uint(retryCount)) * (rand.Intn(50) + 200)
-
-					return time.Duration(delay) * time.Millisecond, nil
+				options.Backoff = awsretry.BackoffDelayerFunc(func(attempt int, err error) (time.Duration, error) {
+					var respErr *awshttp.ResponseError
+					if errors.As(err, &respErr) && respErr.Response != nil {
+						delay, ok := retry.ParseRetryAfter(respErr.Response.Header.Get("Retry-After"), time.Now())
+						if ok {
+							return min(delay, retry.DefaultMaxInterval), nil
+						}
+					}
+
+					// The attempts start at 1.
+					return retry.Backoff(attempt-1, 400*time.Millisecond, retry.DefaultMaxInterval), nil
 				})
 			})
 		}),
@@ -379,12 +624,26 @@ func createAWSConfig(ctx context.Context, config *Config) (aws.Config, error) {
 		return aws.Config{}, err
 	}
 
+	if config.SSOAccountID != "" {
+		cfg.Credentials, err = newSSOCredentialsProvider(cfg, config)
+		if err != nil {
+			return aws.Config{}, err
+		}
+	}
+
+	roles := config.RoleChain
 	if config.AssumeRoleArn != "" {
-		cfg.Credentials = stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), config.AssumeRoleArn, func(options *stscreds.AssumeRoleOptions) {
+		roles = append([]string{config.AssumeRoleArn}, roles...)
+	}
+
+	for _, role := range roles {
+		stsCfg := cfg.Copy()
+
+		cfg.Credentials = newCredentialsCache(stscreds.NewAssumeRoleProvider(sts.NewFromConfig(stsCfg), role, func(options *stscreds.AssumeRoleOptions) {
 			if config.ExternalID != "" {
 				options.ExternalID = &config.ExternalID
 			}
-		})
+		}))
 	}
 
 	return cfg, nil
@@ -403,5 +662,77 @@ func createAWSConfigCheckParams(config *Config) error {
 		return errors.New("AccessKeyID and SecretAccessKey must be supplied together")
 	}
 
+	if config.SSOAccountID == "" && config.SSORoleName == "" && config.SSOStartURL == "" && config.SSOSession == "" {
+		return nil
+	}
+
+	switch {
+	case config.SSOAccountID == "" || config.SSORoleName == "" || config.SSOStartURL == "" && config.SSOSession == "":
+		return errors.New("SSOAccountID, SSORoleName, and SSOStartURL or SSOSession must be supplied together")
+
+	case config.AccessKeyID != "":
+		return errors.New("AccessKeyID and SSO credentials are mutually exclusive")
+	}
+
 	return nil
 }
+
+// credentialsExpiryWindow the credentials are refreshed before their expiration:
+// the credentials stay valid during the API calls, and the waiting for the changes, of a long run.
+const credentialsExpiryWindow = 5 * time.Minute
+
+func newCredentialsCache(provider aws.CredentialsProvider) *aws.CredentialsCache {
+	return aws.NewCredentialsCache(provider, func(options *aws.CredentialsCacheOptions) {
+		options.ExpiryWindow = credentialsExpiryWindow
+	})
+}
+
+// newSSOCredentialsProvider creates the provider of the SSO (IAM Identity Center) credentials,
+// from the token cached by the AWS CLI (`aws sso login`).
+//
+// The cached token is read at each refresh of the credentials (a new `aws sso login` is taken into account without restart).
+// With an SSO session, the expired token is refreshed with its refresh token, and the cache is updated.
+func newSSOCredentialsProvider(cfg aws.Config, config *Config) (aws.CredentialsProvider, error) {
+	ssoCfg := cfg.Copy()
+
+	if config.SSORegion != "" {
+		ssoCfg.Region = config.SSORegion
+	}
+
+	if ssoCfg.Region == "" {
+		return nil, errors.New("SSO region is required (SSORegion or Region)")
+	}
+
+	var optFns []func(options *ssocreds.Options)
+
+	if config.SSOSession != "" {
+		cachedTokenFilepath, err := ssocreds.StandardCachedTokenFilepath(config.SSOSession)
+		if err != nil {
+			return nil, err
+		}
+
+		tokenProvider := ssocreds.NewSSOTokenProvider(ssooidc.NewFromConfig(ssoCfg), cachedTokenFilepath)
+
+		optFns = append(optFns, func(options *ssocreds.Options) {
+			options.SSOTokenProvider = tokenProvider
+		})
+	}
+
+	provider := ssocreds.New(sso.NewFromConfig(ssoCfg), config.SSOAccountID, config.SSORoleName, config.SSOStartURL, optFns...)
+
+	return newCredentialsCache(provider), nil
+}
+
+// parseRoleChain parses a comma-separated list of role ARNs.
+func parseRoleChain(raw string) []string {
+	var roles []string
+
+	for role := range strings.SplitSeq(raw, ",") {
+		role = strings.TrimSpace(role)
+		if role != "" {
+			roles = append(roles, role)
+		}
+	}
+
+	return roles
+}
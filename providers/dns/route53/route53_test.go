@@ -1,15 +1,22 @@
 package route53
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
 	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/go-acme/lego/v4/platform/tester/servermock"
 	"github.com/stretchr/testify/assert"
@@ -25,6 +32,12 @@ var envTest = tester.NewEnvTest(
 	EnvHostedZoneID,
 	EnvMaxRetries,
 	EnvPrivateZone,
+	EnvRoleChain,
+	EnvSSOStartURL,
+	EnvSSORegion,
+	EnvSSOAccountID,
+	EnvSSORoleName,
+	EnvSSOSession,
 	EnvTTL,
 	EnvPropagationTimeout,
 	EnvPollingInterval,
@@ -126,6 +139,30 @@ func TestNewDefaultConfig(t *testing.T) {
 				HostedZoneID:       "abc123",
 			},
 		},
+		{
+			desc: "SSO and role chain",
+			envVars: map[string]string{
+				EnvSSOStartURL:  "https://example.awsapps.com/start",
+				EnvSSORegion:    "eu-west-1",
+				EnvSSOAccountID: "111111111111",
+				EnvSSORoleName:  "ReadOnly",
+				EnvSSOSession:   "example",
+				EnvRoleChain:    "arn:aws:iam::222222222222:role/a, arn:aws:iam::333333333333:role/b,",
+			},
+			expected: &Config{
+				MaxRetries:               5,
+				TTL:                      10,
+				PropagationTimeout:       2 * time.Minute,
+				PollingInterval:          4 * time.Second,
+				WaitForRecordSetsChanged: true,
+				SSOStartURL:              "https://example.awsapps.com/start",
+				SSORegion:                "eu-west-1",
+				SSOAccountID:             "111111111111",
+				SSORoleName:              "ReadOnly",
+				SSOSession:               "example",
+				RoleChain:                []string{"arn:aws:iam::222222222222:role/a", "arn:aws:iam::333333333333:role/b"},
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -159,8 +196,9 @@ func TestDNSProvider_Present(t *testing.T) {
 			}
 
 			return &DNSProvider{
-				client: route53.NewFromConfig(cfg),
-				config: NewDefaultConfig(),
+				client:    route53.NewFromConfig(cfg),
+				config:    NewDefaultConfig(),
+				changeIDs: make(map[string]string),
 			}, nil
 		},
 	).
@@ -219,6 +257,27 @@ func Test_createAWSConfig(t *testing.T) {
 			config:  &Config{SecretAccessKey: "foo"},
 			wantErr: "AccessKeyID and SecretAccessKey must be supplied together",
 		},
+		{
+			desc:    "SSO account ID without role name",
+			config:  &Config{SSOAccountID: "111111111111", SSOStartURL: "https://example.awsapps.com/start"},
+			wantErr: "SSOAccountID, SSORoleName, and SSOStartURL or SSOSession must be supplied together",
+		},
+		{
+			desc:    "SSO without start URL or session",
+			config:  &Config{SSOAccountID: "111111111111", SSORoleName: "ReadOnly"},
+			wantErr: "SSOAccountID, SSORoleName, and SSOStartURL or SSOSession must be supplied together",
+		},
+		{
+			desc: "SSO with static credentials",
+			config: &Config{
+				AccessKeyID:     "one",
+				SecretAccessKey: "two",
+				SSOAccountID:    "111111111111",
+				SSORoleName:     "ReadOnly",
+				SSOSession:      "example",
+			},
+			wantErr: "AccessKeyID and SSO credentials are mutually exclusive",
+		},
 		{
 			desc:             "credentials from default chain",
 			config:           &Config{},
@@ -322,3 +381,289 @@ func requireErr(t *testing.T, err error, wantErr string) {
 		require.EqualError(t, err, wantErr)
 	}
 }
+
+func TestDNSProvider_PresentBatch(t *testing.T) {
+	defer envTest.RestoreEnv()
+
+	envTest.ClearEnv()
+
+	provider := servermock.NewBuilder(
+		func(server *httptest.Server) (*DNSProvider, error) {
+			cfg := aws.Config{
+				HTTPClient:       server.Client(),
+				Credentials:      credentials.NewStaticCredentialsProvider("abc", "123", " "),
+				Region:           "mock-region",
+				BaseEndpoint:     aws.String(server.URL),
+				RetryMaxAttempts: 1,
+			}
+
+			config := NewDefaultConfig()
+			config.HostedZoneID = "ABCDEFG"
+
+			return &DNSProvider{
+				client:    route53.NewFromConfig(cfg),
+				config:    config,
+				changeIDs: make(map[string]string),
+			}, nil
+		},
+	).
+		Route("POST /2013-04-01/hostedzone/ABCDEFG/rrset",
+			servermock.ResponseFromFixture("changeResourceRecordSetsResponse.xml").
+				WithHeader("Content-Type", "application/xml"),
+			servermock.CheckRequestBodyFromFixture("changeResourceRecordSetsRequest_batch.xml")).
+		Route("GET /2013-04-01/change/123456",
+			servermock.ResponseFromFixture("getChangeResponse.xml").
+				WithHeader("Content-Type", "application/xml")).
+		Route("GET /2013-04-01/hostedzone/ABCDEFG/rrset",
+			servermock.Noop().
+				WithHeader("Content-Type", "application/xml")).
+		Build(t)
+
+	// The wildcard and the apex share the same TXT record set.
+	items := []challenge.BatchItem{
+		{Domain: "example.com", KeyAuth: "123456d=="},
+		{Domain: "example.com", KeyAuth: "789012d=="},
+		{Domain: "www.example.com", KeyAuth: "345678d=="},
+	}
+
+	err := provider.PresentBatch(items)
+	require.NoError(t, err)
+}
+
+func TestDNSProvider_ConfirmPropagation(t *testing.T) {
+	defer envTest.RestoreEnv()
+
+	envTest.ClearEnv()
+
+	provider := servermock.NewBuilder(
+		func(server *httptest.Server) (*DNSProvider, error) {
+			cfg := aws.Config{
+				HTTPClient:       server.Client(),
+				Credentials:      credentials.NewStaticCredentialsProvider("abc", "123", " "),
+				Region:           "mock-region",
+				BaseEndpoint:     aws.String(server.URL),
+				RetryMaxAttempts: 1,
+			}
+
+			return &DNSProvider{
+				client:    route53.NewFromConfig(cfg),
+				config:    NewDefaultConfig(),
+				changeIDs: map[string]string{"abc": "123456"},
+			}, nil
+		},
+	).
+		Route("GET /2013-04-01/change/123456",
+			servermock.ResponseFromFixture("getChangeResponse.xml").
+				WithHeader("Content-Type", "application/xml")).
+		Build(t)
+
+	confirmed, err := provider.ConfirmPropagation("example.com", "abc", "123456d==")
+	require.NoError(t, err)
+
+	assert.True(t, confirmed)
+
+	_, err = provider.ConfirmPropagation("example.com", "xyz", "123456d==")
+	require.EqualError(t, err, "route53: unknown change ID for example.com")
+}
+
+// setupAWSMockServer creates a server mocking the STS and SSO APIs, used by the clients of the AWS configuration.
+// The credentials of a role are the name of the role, and the access key ID used to assume each role is recorded.
+func setupAWSMockServer(t *testing.T) (callers map[string]string) {
+	t.Helper()
+
+	callers = make(map[string]string)
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /{$}", func(rw http.ResponseWriter, req *http.Request) {
+		err := req.ParseForm()
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		role := req.PostForm.Get("RoleArn")
+		name := role[strings.LastIndex(role, "/")+1:]
+
+		// Authorization: AWS4-HMAC-SHA256 Credential=<access key ID>/...
+		auth := req.Header.Get("Authorization")
+		callers[role], _, _ = strings.Cut(strings.TrimPrefix(auth[strings.Index(auth, "Credential="):], "Credential="), "/")
+
+		rw.Header().Set("Content-Type", "text/xml")
+
+		_, _ = fmt.Fprintf(rw, `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>%[1]s</AccessKeyId>
+      <SecretAccessKey>secret-%[1]s</SecretAccessKey>
+      <SessionToken>token-%[1]s</SessionToken>
+      <Expiration>%[2]s</Expiration>
+    </Credentials>
+    <AssumedRoleUser>
+      <Arn>%[3]s/lego</Arn>
+      <AssumedRoleId>ID:lego</AssumedRoleId>
+    </AssumedRoleUser>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`, name, time.Now().Add(time.Hour).UTC().Format(time.RFC3339), role)
+	})
+
+	mux.HandleFunc("GET /federation/credentials", func(rw http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+
+		callers["sso:"+query.Get("account_id")+":"+query.Get("role_name")] = req.Header.Get("X-Amz-Sso_bearer_token")
+
+		rw.Header().Set("Content-Type", "application/json")
+
+		_, _ = fmt.Fprintf(rw, `{"roleCredentials":{"accessKeyId":"sso","secretAccessKey":"secret-sso","sessionToken":"token-sso","expiration":%d}}`,
+			time.Now().Add(time.Hour).UnixMilli())
+	})
+
+	mux.HandleFunc("POST /token", func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+
+		_, _ = fmt.Fprint(rw, `{"accessToken":"refreshed-token","expiresIn":3600,"refreshToken":"new-refresh-token","tokenType":"Bearer"}`)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+
+	t.Setenv("HOME", dir)
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_ENDPOINT_URL", server.URL)
+	t.Setenv("AWS_REGION", "us-east-1")
+
+	return callers
+}
+
+// writeSSOCachedToken writes a token in the SSO token cache of the AWS CLI.
+func writeSSOCachedToken(t *testing.T, key string, token map[string]string) string {
+	t.Helper()
+
+	filename, err := ssocreds.StandardCachedTokenFilepath(key)
+	require.NoError(t, err)
+
+	err = os.MkdirAll(filepath.Dir(filename), 0o700)
+	require.NoError(t, err)
+
+	raw, err := json.Marshal(token)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filename, raw, 0o600)
+	require.NoError(t, err)
+
+	return filename
+}
+
+func Test_createAWSConfig_roleChain(t *testing.T) {
+	defer envTest.RestoreEnv()
+
+	envTest.ClearEnv()
+
+	callers := setupAWSMockServer(t)
+
+	config := &Config{
+		AccessKeyID:     "static",
+		SecretAccessKey: "secret",
+		AssumeRoleArn:   "arn:aws:iam::111111111111:role/first",
+		RoleChain:       []string{"arn:aws:iam::222222222222:role/second", "arn:aws:iam::333333333333:role/third"},
+	}
+
+	cfg, err := createAWSConfig(t.Context(), config)
+	require.NoError(t, err)
+
+	creds, err := cfg.Credentials.Retrieve(t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, "third", creds.AccessKeyID)
+	assert.Equal(t, "token-third", creds.SessionToken)
+
+	expected := map[string]string{
+		"arn:aws:iam::111111111111:role/first":  "static",
+		"arn:aws:iam::222222222222:role/second": "first",
+		"arn:aws:iam::333333333333:role/third":  "second",
+	}
+
+	assert.Equal(t, expected, callers)
+}
+
+func Test_createAWSConfig_sso(t *testing.T) {
+	defer envTest.RestoreEnv()
+
+	envTest.ClearEnv()
+
+	callers := setupAWSMockServer(t)
+
+	writeSSOCachedToken(t, "https://example.awsapps.com/start", map[string]string{
+		"accessToken": "cached-token",
+		"expiresAt":   time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+	})
+
+	config := &Config{
+		SSOStartURL:  "https://example.awsapps.com/start",
+		SSOAccountID: "111111111111",
+		SSORoleName:  "ReadOnly",
+		RoleChain:    []string{"arn:aws:iam::222222222222:role/dns"},
+	}
+
+	cfg, err := createAWSConfig(t.Context(), config)
+	require.NoError(t, err)
+
+	creds, err := cfg.Credentials.Retrieve(t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, "dns", creds.AccessKeyID)
+
+	expected := map[string]string{
+		"sso:111111111111:ReadOnly":          "cached-token",
+		"arn:aws:iam::222222222222:role/dns": "sso",
+	}
+
+	assert.Equal(t, expected, callers)
+}
+
+func Test_createAWSConfig_ssoSessionRefresh(t *testing.T) {
+	defer envTest.RestoreEnv()
+
+	envTest.ClearEnv()
+
+	callers := setupAWSMockServer(t)
+
+	filename := writeSSOCachedToken(t, "example", map[string]string{
+		"accessToken":  "expired-token",
+		"expiresAt":    time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+		"refreshToken": "refresh-token",
+		"clientId":     "client",
+		"clientSecret": "secret",
+	})
+
+	config := &Config{
+		SSOSession:   "example",
+		SSORegion:    "eu-west-1",
+		SSOAccountID: "111111111111",
+		SSORoleName:  "ReadOnly",
+	}
+
+	cfg, err := createAWSConfig(t.Context(), config)
+	require.NoError(t, err)
+
+	creds, err := cfg.Credentials.Retrieve(t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, "sso", creds.AccessKeyID)
+	assert.Equal(t, map[string]string{"sso:111111111111:ReadOnly": "refreshed-token"}, callers)
+
+	// The refreshed token is written to the cache.
+	raw, err := os.ReadFile(filename)
+	require.NoError(t, err)
+
+	var token map[string]string
+
+	err = json.Unmarshal(raw, &token)
+	require.NoError(t, err)
+
+	assert.Equal(t, "refreshed-token", token["accessToken"])
+	assert.Equal(t, "new-refresh-token", token["refreshToken"])
+}
@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-acme/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockBuilder() *servermock.Builder[*Client] {
+	return servermock.NewBuilder[*Client](
+		func(server *httptest.Server) (*Client, error) {
+			client, err := NewClient(server.URL, "user", "secret")
+			if err != nil {
+				return nil, err
+			}
+
+			client.HTTPClient = server.Client()
+
+			return client, nil
+		},
+		servermock.CheckHeader().
+			WithAccept("application/json").
+			WithBasicAuth("user", "secret"),
+	)
+}
+
+func TestClient_GetTXTRecords(t *testing.T) {
+	client := mockBuilder().
+		Route("GET /rest/ip/dns/static",
+			servermock.ResponseFromFixture("records.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("type", "TXT").
+				With("name", "_acme-challenge.lab.example.com")).
+		Build(t)
+
+	records, err := client.GetTXTRecords(t.Context(), "_acme-challenge.lab.example.com")
+	require.NoError(t, err)
+
+	expected := []Record{
+		{
+			ID:       "*1",
+			Name:     "nas.lab.example.com",
+			TTL:      "1d",
+			Disabled: "false",
+		},
+		{
+			ID:       "*2",
+			Name:     "_acme-challenge.lab.example.com",
+			Type:     "TXT",
+			Text:     "ADw2sEd82DUgXcQ9hNBZThJs7zVJkR5v9JeSbAb9mZY",
+			TTL:      "2m",
+			Comment:  "lego",
+			Disabled: "false",
+		},
+	}
+
+	assert.Equal(t, expected, records)
+}
+
+func TestClient_CreateRecord(t *testing.T) {
+	client := mockBuilder().
+		Route("PUT /rest/ip/dns/static",
+			servermock.ResponseFromFixture("create_record.json"),
+			servermock.CheckHeader().
+				WithContentType("application/json"),
+			servermock.CheckRequestJSONBodyFromFixture("create_record-request.json")).
+		Build(t)
+
+	record := Record{
+		Name:    "_acme-challenge.lab.example.com",
+		Type:    "TXT",
+		Text:    "ADw2sEd82DUgXcQ9hNBZThJs7zVJkR5v9JeSbAb9mZY",
+		TTL:     "120s",
+		Comment: "lego",
+	}
+
+	newRecord, err := client.CreateRecord(t.Context(), record)
+	require.NoError(t, err)
+
+	expected := &Record{
+		ID:       "*2",
+		Name:     "_acme-challenge.lab.example.com",
+		Type:     "TXT",
+		Text:     "ADw2sEd82DUgXcQ9hNBZThJs7zVJkR5v9JeSbAb9mZY",
+		TTL:      "2m",
+		Comment:  "lego",
+		Disabled: "false",
+	}
+
+	assert.Equal(t, expected, newRecord)
+}
+
+func TestClient_CreateRecord_error(t *testing.T) {
+	client := mockBuilder().
+		Route("PUT /rest/ip/dns/static",
+			servermock.ResponseFromFixture("error.json").
+				WithStatusCode(http.StatusBadRequest)).
+		Build(t)
+
+	_, err := client.CreateRecord(t.Context(), Record{Name: "_acme-challenge.lab.example.com", Type: "TXT"})
+	require.EqualError(t, err, "[status code 400] 400: Bad Request: invalid value for argument type")
+}
+
+func TestClient_DeleteRecord(t *testing.T) {
+	client := mockBuilder().
+		Route("DELETE /rest/ip/dns/static/*2",
+			servermock.Noop().
+				WithStatusCode(http.StatusNoContent)).
+		Build(t)
+
+	err := client.DeleteRecord(t.Context(), "*2")
+	require.NoError(t, err)
+}
@@ -0,0 +1,29 @@
+package internal
+
+import "fmt"
+
+// Record a static DNS record.
+// https://help.mikrotik.com/docs/spaces/ROS/pages/37748767/DNS#DNS-DNSStatic
+type Record struct {
+	ID       string `json:".id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Text     string `json:"text,omitempty"`
+	TTL      string `json:"ttl,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+	Disabled string `json:"disabled,omitempty"`
+}
+
+type APIError struct {
+	Status  int    `json:"error"`
+	Message string `json:"message"`
+	Detail  string `json:"detail"`
+}
+
+func (a *APIError) Error() string {
+	if a.Detail == "" {
+		return fmt.Sprintf("%d: %s", a.Status, a.Message)
+	}
+
+	return fmt.Sprintf("%d: %s: %s", a.Status, a.Message, a.Detail)
+}
@@ -0,0 +1,250 @@
+package routeros
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/go-acme/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const envDomain = envNamespace + "DOMAIN"
+
+var envTest = tester.NewEnvTest(EnvBaseURL, EnvUsername, EnvPassword).WithDomain(envDomain)
+
+func TestNewDNSProvider(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			desc: "success",
+			envVars: map[string]string{
+				EnvBaseURL:  "https://192.168.88.1",
+				EnvUsername: "user",
+				EnvPassword: "secret",
+			},
+		},
+		{
+			desc: "missing base URL",
+			envVars: map[string]string{
+				EnvUsername: "user",
+				EnvPassword: "secret",
+			},
+			expected: "routeros: some credentials information are missing: ROUTEROS_BASE_URL",
+		},
+		{
+			desc: "missing username",
+			envVars: map[string]string{
+				EnvBaseURL:  "https://192.168.88.1",
+				EnvPassword: "secret",
+			},
+			expected: "routeros: some credentials information are missing: ROUTEROS_USERNAME",
+		},
+		{
+			desc: "missing password",
+			envVars: map[string]string{
+				EnvBaseURL:  "https://192.168.88.1",
+				EnvUsername: "user",
+			},
+			expected: "routeros: some credentials information are missing: ROUTEROS_PASSWORD",
+		},
+		{
+			desc:     "missing credentials",
+			envVars:  map[string]string{},
+			expected: "routeros: some credentials information are missing: ROUTEROS_BASE_URL,ROUTEROS_USERNAME,ROUTEROS_PASSWORD",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			p, err := NewDNSProvider()
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+				require.NotNil(t, p.client)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewDNSProviderConfig(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		baseURL  string
+		username string
+		password string
+		expected string
+	}{
+		{
+			desc:     "success",
+			baseURL:  "https://192.168.88.1",
+			username: "user",
+			password: "secret",
+		},
+		{
+			desc:     "missing base URL",
+			username: "user",
+			password: "secret",
+			expected: "routeros: missing base URL",
+		},
+		{
+			desc:     "missing username",
+			baseURL:  "https://192.168.88.1",
+			password: "secret",
+			expected: "routeros: credentials missing",
+		},
+		{
+			desc:     "missing password",
+			baseURL:  "https://192.168.88.1",
+			username: "user",
+			expected: "routeros: credentials missing",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			config := NewDefaultConfig()
+			config.BaseURL = test.baseURL
+			config.Username = test.username
+			config.Password = test.password
+
+			p, err := NewDNSProviderConfig(config)
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+				require.NotNil(t, p.client)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func Test_insecureClient(t *testing.T) {
+	transport := &http.Transport{
+		IdleConnTimeout: time.Minute,
+		TLSClientConfig: &tls.Config{ServerName: "router.example.com"},
+	}
+
+	client := &http.Client{Transport: transport}
+
+	insecure, err := insecureClient(client)
+	require.NoError(t, err)
+
+	// The client of the configuration is not modified.
+	assert.Same(t, transport, client.Transport)
+	assert.False(t, transport.TLSClientConfig.InsecureSkipVerify)
+
+	insecureTransport, ok := insecure.Transport.(*http.Transport)
+	require.True(t, ok)
+
+	assert.True(t, insecureTransport.TLSClientConfig.InsecureSkipVerify)
+	assert.Equal(t, "router.example.com", insecureTransport.TLSClientConfig.ServerName)
+	assert.Equal(t, time.Minute, insecureTransport.IdleConnTimeout)
+}
+
+func TestLivePresent(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	provider, err := NewDNSProvider()
+	require.NoError(t, err)
+
+	err = provider.Present(envTest.GetDomain(), "", "123d==")
+	require.NoError(t, err)
+}
+
+func TestLiveCleanUp(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	provider, err := NewDNSProvider()
+	require.NoError(t, err)
+
+	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
+	require.NoError(t, err)
+}
+
+func mockBuilder() *servermock.Builder[*DNSProvider] {
+	return servermock.NewBuilder(
+		func(server *httptest.Server) (*DNSProvider, error) {
+			config := NewDefaultConfig()
+			config.BaseURL = server.URL
+			config.Username = "user"
+			config.Password = "secret"
+			config.HTTPClient = server.Client()
+
+			return NewDNSProviderConfig(config)
+		},
+		servermock.CheckHeader().
+			WithRegexp("User-Agent", `goacme-lego/[0-9.]+ \(.+\)`).
+			WithBasicAuth("user", "secret"),
+	)
+}
+
+func TestDNSProvider_Present(t *testing.T) {
+	provider := mockBuilder().
+		Route("PUT /rest/ip/dns/static",
+			servermock.ResponseFromInternal("create_record.json"),
+			servermock.CheckRequestJSONBodyFromInternal("create_record-request.json")).
+		Build(t)
+
+	err := provider.Present("lab.example.com", "abc", "123d==")
+	require.NoError(t, err)
+
+	require.Equal(t, "*2", provider.recordIDs["abc"])
+}
+
+func TestDNSProvider_CleanUp(t *testing.T) {
+	provider := mockBuilder().
+		Route("DELETE /rest/ip/dns/static/*2",
+			servermock.Noop().
+				WithStatusCode(http.StatusNoContent)).
+		Build(t)
+
+	provider.recordIDs["abc"] = "*2"
+
+	err := provider.CleanUp("lab.example.com", "abc", "123d==")
+	require.NoError(t, err)
+}
+
+func TestDNSProvider_CleanUp_unknownRecordID(t *testing.T) {
+	provider := mockBuilder().
+		Route("GET /rest/ip/dns/static",
+			servermock.ResponseFromInternal("records.json"),
+			servermock.CheckQueryParameter().Strict().
+				With("type", "TXT").
+				With("name", "_acme-challenge.lab.example.com")).
+		Route("DELETE /rest/ip/dns/static/*2",
+			servermock.Noop().
+				WithStatusCode(http.StatusNoContent)).
+		Build(t)
+
+	err := provider.CleanUp("lab.example.com", "abc", "123d==")
+	require.NoError(t, err)
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/go-acme/lego/v4/providers/dns/internal/clientdebug"
+	"github.com/go-acme/lego/v4/providers/dns/internal/retry"
 	"github.com/go-acme/lego/v4/providers/dns/internal/useragent"
 	client "github.com/sacloud/api-client-go"
 	"github.com/sacloud/iaas-api-go"
@@ -26,34 +28,53 @@ const (
 
 	EnvAccessToken       = envNamespace + "ACCESS_TOKEN"
 	EnvAccessTokenSecret = envNamespace + "ACCESS_TOKEN_SECRET"
+	EnvZone              = envNamespace + "ZONE"
+	EnvAPIRootURL        = envNamespace + "API_ROOT_URL"
 
 	EnvTTL                = envNamespace + "TTL"
 	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
 	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+	EnvMaxRetries         = envNamespace + "MAX_RETRIES"
 )
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout = (*DNSProvider)(nil)
+	_ challenge.ProviderContext = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
-	Token              string
-	Secret             string
+	Token  string
+	Secret string
+
+	// Zone the default zone of the API (ex: "is1a", "tk1v" for the sandbox).
+	Zone string
+	// APIRootURL the root URL of the API (ex: an alternative endpoint).
+	APIRootURL string
+
 	PropagationTimeout time.Duration
 	PollingInterval    time.Duration
 	TTL                int
 	HTTPClient         *http.Client
+
+	// MaxRetries the maximum number of retries of an API call on the 429 and 5xx responses
+	// (the non-idempotent calls are only retried on the 429 and 503 responses with a Retry-After header).
+	MaxRetries int
 }
 
 // NewDefaultConfig returns a default configuration for the DNSProvider.
 func NewDefaultConfig() *Config {
 	return &Config{
+		Zone:               env.GetOrDefaultString(EnvZone, ""),
+		APIRootURL:         env.GetOrDefaultString(EnvAPIRootURL, ""),
 		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
 		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
 		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
 		HTTPClient: &http.Client{
 			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 10*time.Second),
 		},
+		MaxRetries: env.GetOrDefaultInt(EnvMaxRetries, 10),
 	}
 }
 
@@ -93,6 +114,13 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("sakuracloud: AccessSecret is missing")
 	}
 
+	if config.APIRootURL != "" {
+		u, err := url.Parse(config.APIRootURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("sakuracloud: invalid API root URL: %q", config.APIRootURL)
+		}
+	}
+
 	defaultOption, err := api.DefaultOption()
 	if err != nil {
 		return nil, fmt.Errorf("sakuracloud: %w", err)
@@ -102,9 +130,15 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		Options: &client.Options{
 			AccessToken:       config.Token,
 			AccessTokenSecret: config.Secret,
-			HttpClient:        clientdebug.Wrap(config.HTTPClient),
+			HttpClient:        clientdebug.Wrap(retry.Wrap(config.HTTPClient, config.MaxRetries), clientdebug.WithEnvNamespace(envNamespace)),
 			UserAgent:         fmt.Sprintf("%s %s", iaas.DefaultUserAgent, useragent.Get()),
+			// The retries are done by the transport of the HTTP client (retry.Transport).
+			CheckRetryFunc: func(ctx context.Context, _ *http.Response, _ error) (bool, error) {
+				return false, ctx.Err()
+			},
 		},
+		DefaultZone: config.Zone,
+		APIRootURL:  config.APIRootURL,
 	}
 
 	return &DNSProvider{
@@ -115,9 +149,14 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	return d.PresentContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentContext is like Present, the API calls are canceled with the context.
+func (d *DNSProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	err := d.addTXTRecord(context.Background(), info.EffectiveFQDN, info.Value, d.config.TTL)
+	err := d.addTXTRecord(ctx, info.EffectiveFQDN, info.Value, d.config.TTL)
 	if err != nil {
 		return fmt.Errorf("sakuracloud: %w", err)
 	}
@@ -127,9 +166,14 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	return d.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpContext is like CleanUp, the API calls are canceled with the context.
+func (d *DNSProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	err := d.cleanupTXTRecord(context.Background(), info.EffectiveFQDN, info.Value)
+	err := d.cleanupTXTRecord(ctx, info.EffectiveFQDN, info.Value)
 	if err != nil {
 		return fmt.Errorf("sakuracloud: %w", err)
 	}
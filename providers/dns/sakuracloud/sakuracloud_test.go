@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/sacloud/iaas-api-go"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -12,7 +14,9 @@ const envDomain = envNamespace + "DOMAIN"
 
 var envTest = tester.NewEnvTest(
 	EnvAccessToken,
-	EnvAccessTokenSecret).
+	EnvAccessTokenSecret,
+	EnvZone,
+	EnvAPIRootURL).
 	WithDomain(envDomain)
 
 func TestNewDNSProvider(t *testing.T) {
@@ -124,6 +128,31 @@ func TestNewDNSProviderConfig(t *testing.T) {
 	}
 }
 
+func TestNewDNSProviderConfig_endpoint(t *testing.T) {
+	apiRoot, defaultZone := iaas.SakuraCloudAPIRoot, iaas.APIDefaultZone
+
+	t.Cleanup(func() {
+		iaas.SakuraCloudAPIRoot, iaas.APIDefaultZone = apiRoot, defaultZone
+	})
+
+	config := NewDefaultConfig()
+	config.Token = "123"
+	config.Secret = "456"
+	config.Zone = "tk1v"
+	config.APIRootURL = "https://api.example.com/cloud/zone/"
+
+	_, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://api.example.com/cloud/zone", iaas.SakuraCloudAPIRoot)
+	assert.Equal(t, "tk1v", iaas.APIDefaultZone)
+
+	config.APIRootURL = "api.example.com"
+
+	_, err = NewDNSProviderConfig(config)
+	require.EqualError(t, err, `sakuracloud: invalid API root URL: "api.example.com"`)
+}
+
 func TestLivePresent(t *testing.T) {
 	if !envTest.IsLiveTest() {
 		t.Skip("skipping live test")
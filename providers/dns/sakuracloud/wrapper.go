@@ -2,7 +2,10 @@ package sakuracloud
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"slices"
 	"sync"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
@@ -14,71 +17,95 @@ import (
 // see: https://github.com/go-acme/lego/pull/850
 var mu sync.Mutex
 
-func (d *DNSProvider) addTXTRecord(ctx context.Context, fqdn, value string, ttl int) error {
-	mu.Lock()
-	defer mu.Unlock()
+// maxUpdateAttempts the maximum number of attempts of an update of the records of a zone,
+// when the records are modified concurrently (ex: by another lego instance).
+const maxUpdateAttempts = 5
 
-	zone, err := d.getHostedZone(ctx, fqdn)
-	if err != nil {
-		return err
-	}
-
-	subDomain, err := dns01.ExtractSubDomain(fqdn, zone.Name)
-	if err != nil {
-		return err
-	}
+func (d *DNSProvider) addTXTRecord(ctx context.Context, fqdn, value string, ttl int) error {
+	return d.updateRecords(ctx, fqdn, func(records iaas.DNSRecords, subDomain string) (iaas.DNSRecords, bool) {
+		for _, r := range records {
+			if isTXTRecord(r, subDomain, value) {
+				// The value is already in the record set (ex: created by another lego instance).
+				return records, false
+			}
+		}
 
-	records := append(zone.Records, &iaas.DNSRecord{
-		Name:  subDomain,
-		Type:  "TXT",
-		RData: value,
-		TTL:   ttl,
+		return append(records, &iaas.DNSRecord{
+			Name:  subDomain,
+			Type:  "TXT",
+			RData: value,
+			TTL:   ttl,
+		}), true
 	})
+}
 
-	_, err = d.client.UpdateSettings(ctx, zone.ID, &iaas.DNSUpdateSettingsRequest{
-		Records:      records,
-		SettingsHash: zone.SettingsHash,
-	})
-	if err != nil {
-		return fmt.Errorf("API call failed: %w", err)
-	}
+func (d *DNSProvider) cleanupTXTRecord(ctx context.Context, fqdn, value string) error {
+	return d.updateRecords(ctx, fqdn, func(records iaas.DNSRecords, subDomain string) (iaas.DNSRecords, bool) {
+		var updRecords iaas.DNSRecords
+
+		// Only the value of this challenge is removed: the values of the other instances are kept.
+		for _, r := range records {
+			if !isTXTRecord(r, subDomain, value) {
+				updRecords = append(updRecords, r)
+			}
+		}
 
-	return nil
+		return updRecords, len(updRecords) != len(records)
+	})
 }
 
-func (d *DNSProvider) cleanupTXTRecord(ctx context.Context, fqdn, value string) error {
+// updateRecords updates the records of the zone with optimistic concurrency:
+// the update is rejected by the API if the records have been modified since they were read (SettingsHash),
+// in this case, the records are read again and the update is applied again.
+// The update function returns false if the records don't need to be updated.
+func (d *DNSProvider) updateRecords(ctx context.Context, fqdn string, update func(records iaas.DNSRecords, subDomain string) (iaas.DNSRecords, bool)) error {
 	mu.Lock()
 	defer mu.Unlock()
 
-	zone, err := d.getHostedZone(ctx, fqdn)
-	if err != nil {
-		return err
-	}
+	for attempt := 1; ; attempt++ {
+		// The context can be canceled while waiting for the lock.
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
-	subDomain, err := dns01.ExtractSubDomain(fqdn, zone.Name)
-	if err != nil {
-		return err
-	}
+		zone, err := d.getHostedZone(ctx, fqdn)
+		if err != nil {
+			return err
+		}
 
-	var updRecords iaas.DNSRecords
+		subDomain, err := dns01.ExtractSubDomain(fqdn, zone.Name)
+		if err != nil {
+			return err
+		}
 
-	for _, r := range zone.Records {
-		if !(r.Name == subDomain && r.Type == "TXT" && r.RData == value) { //nolint:staticcheck // Clearer without De Morgan's law.
-			updRecords = append(updRecords, r)
+		records, changed := update(slices.Clone(zone.Records), subDomain)
+		if !changed {
+			return nil
 		}
-	}
 
-	settings := &iaas.DNSUpdateSettingsRequest{
-		Records:      updRecords,
-		SettingsHash: zone.SettingsHash,
-	}
+		_, err = d.client.UpdateSettings(ctx, zone.ID, &iaas.DNSUpdateSettingsRequest{
+			Records:      records,
+			SettingsHash: zone.SettingsHash,
+		})
+		if err == nil {
+			return nil
+		}
 
-	_, err = d.client.UpdateSettings(ctx, zone.ID, settings)
-	if err != nil {
-		return fmt.Errorf("API call failed: %w", err)
+		if !isConflictError(err) || attempt >= maxUpdateAttempts {
+			return fmt.Errorf("API call failed: %w", err)
+		}
 	}
+}
+
+func isTXTRecord(r *iaas.DNSRecord, subDomain, value string) bool {
+	return r.Name == subDomain && r.Type == "TXT" && r.RData == value
+}
+
+// isConflictError returns true if the records of the zone have been modified concurrently.
+func isConflictError(err error) bool {
+	var apiError iaas.APIError
 
-	return nil
+	return errors.As(err, &apiError) && apiError.ResponseCode() == http.StatusConflict
 }
 
 func (d *DNSProvider) getHostedZone(ctx context.Context, domain string) (*iaas.DNS, error) {
@@ -1,13 +1,17 @@
 package sakuracloud
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"sync"
 	"testing"
 
 	client "github.com/sacloud/api-client-go"
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/helper/api"
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -155,3 +159,134 @@ func TestDNSProvider_concurrentAddAndCleanupRecords(t *testing.T) {
 		require.Empty(t, updZone.Records)
 	})
 }
+
+func TestDNSProvider_addTXTRecord_canceled(t *testing.T) {
+	setupTest(t)
+
+	config := NewDefaultConfig()
+	config.Token = "token1"
+	config.Secret = "secret1"
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	err = p.addTXTRecord(ctx, "test.example.com.", "dummyValue", 10)
+	require.ErrorIs(t, err, context.Canceled)
+
+	zone, err := p.getHostedZone(t.Context(), "test.example.com.")
+	require.NoError(t, err)
+
+	require.Empty(t, zone.Records)
+}
+
+func TestDNSProvider_addTXTRecord_existingRecordSet(t *testing.T) {
+	setupTest(t)
+
+	config := NewDefaultConfig()
+	config.Token = "token1"
+	config.Secret = "secret1"
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	ctx := t.Context()
+
+	// The value of another lego instance.
+	err = p.addTXTRecord(ctx, "_acme-challenge.example.com.", "otherValue", 10)
+	require.NoError(t, err)
+
+	err = p.addTXTRecord(ctx, "_acme-challenge.example.com.", "dummyValue", 10)
+	require.NoError(t, err)
+
+	// The value is already in the record set.
+	err = p.addTXTRecord(ctx, "_acme-challenge.example.com.", "dummyValue", 10)
+	require.NoError(t, err)
+
+	zone, err := p.getHostedZone(ctx, "example.com.")
+	require.NoError(t, err)
+
+	require.Len(t, zone.Records, 2)
+
+	err = p.cleanupTXTRecord(ctx, "_acme-challenge.example.com.", "dummyValue")
+	require.NoError(t, err)
+
+	zone, err = p.getHostedZone(ctx, "example.com.")
+	require.NoError(t, err)
+
+	require.Len(t, zone.Records, 1)
+	assert.Equal(t, "otherValue", zone.Records[0].RData)
+}
+
+// conflictDNSAPI simulates a concurrent update of the records of the zone, by another lego instance, before the first update.
+type conflictDNSAPI struct {
+	iaas.DNSAPI
+
+	conflicts int
+}
+
+func (c *conflictDNSAPI) UpdateSettings(ctx context.Context, id types.ID, param *iaas.DNSUpdateSettingsRequest) (*iaas.DNS, error) {
+	if c.conflicts > 0 {
+		c.conflicts--
+
+		zone, err := c.DNSAPI.Read(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		records := append(zone.Records, &iaas.DNSRecord{Name: "_acme-challenge", Type: "TXT", RData: "otherValue", TTL: 10})
+
+		_, err = c.DNSAPI.UpdateSettings(ctx, id, &iaas.DNSUpdateSettingsRequest{Records: records, SettingsHash: zone.SettingsHash})
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, iaas.NewAPIError(http.MethodPut, nil, http.StatusConflict, &iaas.APIErrorResponse{ErrorCode: "conflict"})
+	}
+
+	return c.DNSAPI.UpdateSettings(ctx, id, param)
+}
+
+func TestDNSProvider_addTXTRecord_conflict(t *testing.T) {
+	setupTest(t)
+
+	config := NewDefaultConfig()
+	config.Token = "token1"
+	config.Secret = "secret1"
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	p.client = &conflictDNSAPI{DNSAPI: p.client, conflicts: 1}
+
+	ctx := t.Context()
+
+	err = p.addTXTRecord(ctx, "_acme-challenge.example.com.", "dummyValue", 10)
+	require.NoError(t, err)
+
+	zone, err := p.getHostedZone(ctx, "example.com.")
+	require.NoError(t, err)
+
+	// The record of the other instance is not overwritten.
+	require.Len(t, zone.Records, 2)
+	assert.Equal(t, "otherValue", zone.Records[0].RData)
+	assert.Equal(t, "dummyValue", zone.Records[1].RData)
+}
+
+func TestDNSProvider_addTXTRecord_tooManyConflicts(t *testing.T) {
+	setupTest(t)
+
+	config := NewDefaultConfig()
+	config.Token = "token1"
+	config.Secret = "secret1"
+
+	p, err := NewDNSProviderConfig(config)
+	require.NoError(t, err)
+
+	p.client = &conflictDNSAPI{DNSAPI: p.client, conflicts: maxUpdateAttempts}
+
+	err = p.addTXTRecord(t.Context(), "test.example.com.", "dummyValue", 10)
+	require.ErrorContains(t, err, "API call failed")
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/go-acme/lego/v4/providers/dns/internal/clientdebug"
+	"github.com/go-acme/lego/v4/providers/dns/internal/ttl"
 	"github.com/go-acme/lego/v4/providers/dns/internal/useragent"
 	scwdomain "github.com/scaleway/scaleway-sdk-go/api/domain/v2beta1"
 	"github.com/scaleway/scaleway-sdk-go/scw"
@@ -106,9 +107,9 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("scaleway: credentials missing")
 	}
 
-	if config.TTL < minTTL {
-		config.TTL = minTTL
-	}
+	cfg := *config
+	cfg.TTL = ttl.Range{Min: minTTL}.Clamp("scaleway", config.TTL)
+	config = &cfg
 
 	configuration := []scw.ClientOption{
 		scw.WithAuth(config.AccessKey, config.Token),
@@ -116,7 +117,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	}
 
 	if config.HTTPClient != nil {
-		configuration = append(configuration, scw.WithHTTPClient(clientdebug.Wrap(config.HTTPClient)))
+		configuration = append(configuration, scw.WithHTTPClient(clientdebug.Wrap(config.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))))
 	}
 
 	if config.ProjectID != "" {
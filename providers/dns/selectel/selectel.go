@@ -13,6 +13,7 @@ import (
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/go-acme/lego/v4/providers/dns/internal/selectel"
+	"github.com/go-acme/lego/v4/providers/dns/internal/ttl"
 )
 
 // Environment variables names.
@@ -71,6 +72,10 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("selectel: the configuration of the DNS provider is nil")
 	}
 
+	cfg := *config
+	cfg.TTL = ttl.Range{Min: selectel.MinTTL}.Clamp("selectel", config.TTL)
+	config = &cfg
+
 	provider, err := selectel.NewDNSProviderConfig(config)
 	if err != nil {
 		return nil, fmt.Errorf("selectel: %w", err)
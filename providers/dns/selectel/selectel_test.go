@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/go-acme/lego/v4/platform/tester"
-	"github.com/go-acme/lego/v4/providers/dns/internal/selectel"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -74,10 +73,9 @@ func TestNewDNSProviderConfig(t *testing.T) {
 			expected: "selectel: credentials missing",
 		},
 		{
-			desc:     "bad TTL value",
-			token:    "123",
-			ttl:      59,
-			expected: fmt.Sprintf("selectel: invalid TTL, TTL (59) must be greater than %d", selectel.MinTTL),
+			desc:  "TTL clamped",
+			token: "123",
+			ttl:   59,
 		},
 	}
 
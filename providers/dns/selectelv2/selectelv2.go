@@ -135,7 +135,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	useragent.SetHeader(headers)
 
 	return &DNSProvider{
-		baseClient: selectelapi.NewClient(config.BaseURL, clientdebug.Wrap(config.HTTPClient), headers),
+		baseClient: selectelapi.NewClient(config.BaseURL, clientdebug.Wrap(config.HTTPClient, clientdebug.WithEnvNamespace(envNamespace)), headers),
 		config:     config,
 	}, nil
 }
@@ -92,6 +92,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		client: internal.NewClient(config.StackID,
 			clientdebug.Wrap(
 				internal.CreateOAuthClient(context.Background(), config.ClientID, config.ClientSecret),
+				clientdebug.WithEnvNamespace(envNamespace),
 			),
 		),
 	}, nil
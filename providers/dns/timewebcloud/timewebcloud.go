@@ -85,6 +85,7 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 	client := internal.NewClient(
 		clientdebug.Wrap(
 			internal.OAuthStaticAccessToken(config.HTTPClient, config.AuthToken),
+			clientdebug.WithEnvNamespace(envNamespace),
 		),
 	)
 
@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-acme/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockBuilder() *servermock.Builder[*Client] {
+	return servermock.NewBuilder[*Client](
+		func(server *httptest.Server) (*Client, error) {
+			client, err := NewClient(server.URL, "secret", "default")
+			if err != nil {
+				return nil, err
+			}
+
+			client.HTTPClient = server.Client()
+
+			return client, nil
+		},
+		servermock.CheckHeader().
+			WithAccept("application/json").
+			With(apiKeyHeader, "secret"),
+	)
+}
+
+func TestClient_GetRecords(t *testing.T) {
+	client := mockBuilder().
+		Route("GET /proxy/network/v2/api/site/default/static-dns",
+			servermock.ResponseFromFixture("records.json")).
+		Build(t)
+
+	records, err := client.GetRecords(t.Context())
+	require.NoError(t, err)
+
+	expected := []Record{
+		{
+			ID:         "66f1e6b2a3c4d5e6f7a8b9c0",
+			Key:        "nas.lab.example.com",
+			RecordType: "A",
+			Value:      "192.168.1.10",
+			Enabled:    true,
+		},
+		{
+			ID:         "66f1e6b2a3c4d5e6f7a8b9c1",
+			Key:        "_acme-challenge.lab.example.com",
+			RecordType: "TXT",
+			Value:      "ADw2sEd82DUgXcQ9hNBZThJs7zVJkR5v9JeSbAb9mZY",
+			TTL:        120,
+			Enabled:    true,
+		},
+	}
+
+	assert.Equal(t, expected, records)
+}
+
+func TestClient_CreateRecord(t *testing.T) {
+	client := mockBuilder().
+		Route("POST /proxy/network/v2/api/site/default/static-dns",
+			servermock.ResponseFromFixture("create_record.json"),
+			servermock.CheckHeader().
+				WithContentType("application/json"),
+			servermock.CheckRequestJSONBodyFromFixture("create_record-request.json")).
+		Build(t)
+
+	record := Record{
+		Key:        "_acme-challenge.lab.example.com",
+		RecordType: "TXT",
+		Value:      "ADw2sEd82DUgXcQ9hNBZThJs7zVJkR5v9JeSbAb9mZY",
+		TTL:        120,
+		Enabled:    true,
+	}
+
+	newRecord, err := client.CreateRecord(t.Context(), record)
+	require.NoError(t, err)
+
+	expected := &Record{
+		ID:         "66f1e6b2a3c4d5e6f7a8b9c1",
+		Key:        "_acme-challenge.lab.example.com",
+		RecordType: "TXT",
+		Value:      "ADw2sEd82DUgXcQ9hNBZThJs7zVJkR5v9JeSbAb9mZY",
+		TTL:        120,
+		Enabled:    true,
+	}
+
+	assert.Equal(t, expected, newRecord)
+}
+
+func TestClient_CreateRecord_error(t *testing.T) {
+	client := mockBuilder().
+		Route("POST /proxy/network/v2/api/site/default/static-dns",
+			servermock.ResponseFromFixture("error.json").
+				WithStatusCode(http.StatusBadRequest)).
+		Build(t)
+
+	_, err := client.CreateRecord(t.Context(), Record{Key: "_acme-challenge.lab.example.com", RecordType: "TXT"})
+	require.EqualError(t, err, "[status code 400] api.err.Invalid: Invalid value for record_type")
+}
+
+func TestClient_DeleteRecord(t *testing.T) {
+	client := mockBuilder().
+		Route("DELETE /proxy/network/v2/api/site/default/static-dns/66f1e6b2a3c4d5e6f7a8b9c1",
+			servermock.Noop()).
+		Build(t)
+
+	err := client.DeleteRecord(t.Context(), "66f1e6b2a3c4d5e6f7a8b9c1")
+	require.NoError(t, err)
+}
@@ -0,0 +1,22 @@
+package internal
+
+import "fmt"
+
+// Record a static DNS record.
+type Record struct {
+	ID         string `json:"_id,omitempty"`
+	Key        string `json:"key,omitempty"`
+	RecordType string `json:"record_type,omitempty"`
+	Value      string `json:"value,omitempty"`
+	TTL        int    `json:"ttl,omitempty"`
+	Enabled    bool   `json:"enabled"`
+}
+
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (a *APIError) Error() string {
+	return fmt.Sprintf("%s: %s", a.Code, a.Message)
+}
@@ -0,0 +1,223 @@
+// Package unifi implements a DNS provider for solving the DNS-01 challenge using the static DNS records of the UniFi Network application.
+package unifi
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/platform/config/env"
+	"github.com/go-acme/lego/v4/providers/dns/internal/clientdebug"
+	"github.com/go-acme/lego/v4/providers/dns/unifi/internal"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "UNIFI_"
+
+	EnvBaseURL            = envNamespace + "BASE_URL"
+	EnvAPIKey             = envNamespace + "API_KEY"
+	EnvSite               = envNamespace + "SITE"
+	EnvInsecureSkipVerify = envNamespace + "INSECURE_SKIP_VERIFY"
+
+	EnvTTL                = envNamespace + "TTL"
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+)
+
+const defaultSite = "default"
+
+var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	BaseURL            string
+	APIKey             string
+	Site               string
+	InsecureSkipVerify bool
+
+	PropagationTimeout time.Duration
+	PollingInterval    time.Duration
+	TTL                int
+	HTTPClient         *http.Client
+}
+
+// NewDefaultConfig returns a default configuration for the DNSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Site:               env.GetOrDefaultString(EnvSite, defaultSite),
+		InsecureSkipVerify: env.GetOrDefaultBool(EnvInsecureSkipVerify, false),
+		TTL:                env.GetOrDefaultInt(EnvTTL, dns01.DefaultTTL),
+		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, dns01.DefaultPropagationTimeout),
+		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, dns01.DefaultPollingInterval),
+		HTTPClient: &http.Client{
+			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+		},
+	}
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+	client *internal.Client
+
+	recordIDs   map[string]string
+	recordIDsMu sync.Mutex
+}
+
+// NewDNSProvider returns a DNSProvider instance configured for UniFi.
+func NewDNSProvider() (*DNSProvider, error) {
+	values, err := env.Get(EnvBaseURL, EnvAPIKey)
+	if err != nil {
+		return nil, fmt.Errorf("unifi: %w", err)
+	}
+
+	config := NewDefaultConfig()
+	config.BaseURL = values[EnvBaseURL]
+	config.APIKey = values[EnvAPIKey]
+
+	return NewDNSProviderConfig(config)
+}
+
+// NewDNSProviderConfig return a DNSProvider instance configured for UniFi.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("unifi: the configuration of the DNS provider is nil")
+	}
+
+	client, err := internal.NewClient(config.BaseURL, config.APIKey, config.Site)
+	if err != nil {
+		return nil, fmt.Errorf("unifi: %w", err)
+	}
+
+	if config.HTTPClient != nil {
+		client.HTTPClient = config.HTTPClient
+	}
+
+	// The UniFi gateways use a self-signed certificate by default.
+	if config.InsecureSkipVerify {
+		client.HTTPClient, err = insecureClient(client.HTTPClient)
+		if err != nil {
+			return nil, fmt.Errorf("unifi: %w", err)
+		}
+	}
+
+	client.HTTPClient = clientdebug.Wrap(client.HTTPClient, clientdebug.WithEnvNamespace(envNamespace))
+
+	return &DNSProvider{
+		config:    config,
+		client:    client,
+		recordIDs: make(map[string]string),
+	}, nil
+}
+
+// Present creates a TXT record using the specified parameters.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	record := internal.Record{
+		Key:        dns01.UnFqdn(info.EffectiveFQDN),
+		RecordType: "TXT",
+		Value:      info.Value,
+		TTL:        d.config.TTL,
+		Enabled:    true,
+	}
+
+	newRecord, err := d.client.CreateRecord(context.Background(), record)
+	if err != nil {
+		return fmt.Errorf("unifi: create record: %w", err)
+	}
+
+	d.recordIDsMu.Lock()
+	d.recordIDs[token] = newRecord.ID
+	d.recordIDsMu.Unlock()
+
+	return nil
+}
+
+// CleanUp removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	ctx := context.Background()
+
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	d.recordIDsMu.Lock()
+	recordID, ok := d.recordIDs[token]
+	d.recordIDsMu.Unlock()
+
+	if !ok {
+		var err error
+
+		// The record ID is unknown (ex: the record has been created by another process).
+		recordID, err = d.findRecordID(ctx, dns01.UnFqdn(info.EffectiveFQDN), info.Value)
+		if err != nil {
+			return fmt.Errorf("unifi: %w", err)
+		}
+	}
+
+	err := d.client.DeleteRecord(ctx, recordID)
+	if err != nil {
+		return fmt.Errorf("unifi: delete record: %w", err)
+	}
+
+	d.recordIDsMu.Lock()
+	delete(d.recordIDs, token)
+	d.recordIDsMu.Unlock()
+
+	return nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS propagation.
+// Adjusting here to cope with spikes in propagation times.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+func (d *DNSProvider) findRecordID(ctx context.Context, key, value string) (string, error) {
+	records, err := d.client.GetRecords(ctx)
+	if err != nil {
+		return "", fmt.Errorf("get records: %w", err)
+	}
+
+	for _, record := range records {
+		if record.RecordType == "TXT" && record.Key == key && record.Value == value {
+			return record.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown record for '%s'", key)
+}
+
+// insecureClient returns a copy of the client skipping the verification of the certificate of the server.
+// The transport of the client is cloned: its other settings are kept, and the client of the configuration is not modified.
+func insecureClient(client *http.Client) (*http.Client, error) {
+	rt := client.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	transport, ok := rt.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("the verification of the certificate cannot be disabled with the transport %T", rt)
+	}
+
+	transport = transport.Clone()
+
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+
+	transport.TLSClientConfig.InsecureSkipVerify = true
+
+	insecure := *client
+	insecure.Transport = transport
+
+	return &insecure, nil
+}
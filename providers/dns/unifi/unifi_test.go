@@ -0,0 +1,246 @@
+package unifi
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/go-acme/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const envDomain = envNamespace + "DOMAIN"
+
+var envTest = tester.NewEnvTest(EnvBaseURL, EnvAPIKey, EnvSite).WithDomain(envDomain)
+
+func TestNewDNSProvider(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			desc: "success",
+			envVars: map[string]string{
+				EnvBaseURL: "https://192.168.1.1",
+				EnvAPIKey:  "secret",
+			},
+		},
+		{
+			desc: "missing base URL",
+			envVars: map[string]string{
+				EnvAPIKey: "secret",
+			},
+			expected: "unifi: some credentials information are missing: UNIFI_BASE_URL",
+		},
+		{
+			desc: "missing API key",
+			envVars: map[string]string{
+				EnvBaseURL: "https://192.168.1.1",
+			},
+			expected: "unifi: some credentials information are missing: UNIFI_API_KEY",
+		},
+		{
+			desc:     "missing credentials",
+			envVars:  map[string]string{},
+			expected: "unifi: some credentials information are missing: UNIFI_BASE_URL,UNIFI_API_KEY",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			p, err := NewDNSProvider()
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+				require.NotNil(t, p.client)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewDNSProviderConfig(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		baseURL  string
+		apiKey   string
+		site     string
+		expected string
+	}{
+		{
+			desc:    "success",
+			baseURL: "https://192.168.1.1",
+			apiKey:  "secret",
+			site:    "default",
+		},
+		{
+			desc:     "missing base URL",
+			apiKey:   "secret",
+			site:     "default",
+			expected: "unifi: missing base URL",
+		},
+		{
+			desc:     "missing API key",
+			baseURL:  "https://192.168.1.1",
+			site:     "default",
+			expected: "unifi: missing API key",
+		},
+		{
+			desc:     "missing site",
+			baseURL:  "https://192.168.1.1",
+			apiKey:   "secret",
+			expected: "unifi: missing site",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			config := NewDefaultConfig()
+			config.BaseURL = test.baseURL
+			config.APIKey = test.apiKey
+			config.Site = test.site
+
+			p, err := NewDNSProviderConfig(config)
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+				require.NotNil(t, p.client)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func Test_insecureClient(t *testing.T) {
+	transport := &http.Transport{
+		IdleConnTimeout: time.Minute,
+		TLSClientConfig: &tls.Config{ServerName: "unifi.example.com"},
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second, Transport: transport}
+
+	insecure, err := insecureClient(client)
+	require.NoError(t, err)
+
+	// The client of the configuration is not modified.
+	assert.Same(t, transport, client.Transport)
+	assert.False(t, transport.TLSClientConfig.InsecureSkipVerify)
+
+	assert.Equal(t, 10*time.Second, insecure.Timeout)
+
+	insecureTransport, ok := insecure.Transport.(*http.Transport)
+	require.True(t, ok)
+
+	assert.True(t, insecureTransport.TLSClientConfig.InsecureSkipVerify)
+	assert.Equal(t, "unifi.example.com", insecureTransport.TLSClientConfig.ServerName)
+	assert.Equal(t, time.Minute, insecureTransport.IdleConnTimeout)
+}
+
+func Test_insecureClient_defaultTransport(t *testing.T) {
+	insecure, err := insecureClient(&http.Client{})
+	require.NoError(t, err)
+
+	insecureTransport, ok := insecure.Transport.(*http.Transport)
+	require.True(t, ok)
+
+	assert.True(t, insecureTransport.TLSClientConfig.InsecureSkipVerify)
+	assert.NotSame(t, http.DefaultTransport, insecureTransport)
+}
+
+func TestLivePresent(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	provider, err := NewDNSProvider()
+	require.NoError(t, err)
+
+	err = provider.Present(envTest.GetDomain(), "", "123d==")
+	require.NoError(t, err)
+}
+
+func TestLiveCleanUp(t *testing.T) {
+	if !envTest.IsLiveTest() {
+		t.Skip("skipping live test")
+	}
+
+	envTest.RestoreEnv()
+
+	provider, err := NewDNSProvider()
+	require.NoError(t, err)
+
+	err = provider.CleanUp(envTest.GetDomain(), "", "123d==")
+	require.NoError(t, err)
+}
+
+func mockBuilder() *servermock.Builder[*DNSProvider] {
+	return servermock.NewBuilder(
+		func(server *httptest.Server) (*DNSProvider, error) {
+			config := NewDefaultConfig()
+			config.BaseURL = server.URL
+			config.APIKey = "secret"
+			config.HTTPClient = server.Client()
+
+			return NewDNSProviderConfig(config)
+		},
+		servermock.CheckHeader().
+			WithRegexp("User-Agent", `goacme-lego/[0-9.]+ \(.+\)`).
+			With("X-API-KEY", "secret"),
+	)
+}
+
+func TestDNSProvider_Present(t *testing.T) {
+	provider := mockBuilder().
+		Route("POST /proxy/network/v2/api/site/default/static-dns",
+			servermock.ResponseFromInternal("create_record.json"),
+			servermock.CheckRequestJSONBodyFromInternal("create_record-request.json")).
+		Build(t)
+
+	err := provider.Present("lab.example.com", "abc", "123d==")
+	require.NoError(t, err)
+
+	require.Equal(t, "66f1e6b2a3c4d5e6f7a8b9c1", provider.recordIDs["abc"])
+}
+
+func TestDNSProvider_CleanUp(t *testing.T) {
+	provider := mockBuilder().
+		Route("DELETE /proxy/network/v2/api/site/default/static-dns/66f1e6b2a3c4d5e6f7a8b9c1",
+			servermock.Noop()).
+		Build(t)
+
+	provider.recordIDs["abc"] = "66f1e6b2a3c4d5e6f7a8b9c1"
+
+	err := provider.CleanUp("lab.example.com", "abc", "123d==")
+	require.NoError(t, err)
+}
+
+func TestDNSProvider_CleanUp_unknownRecordID(t *testing.T) {
+	provider := mockBuilder().
+		Route("GET /proxy/network/v2/api/site/default/static-dns",
+			servermock.ResponseFromInternal("records.json")).
+		Route("DELETE /proxy/network/v2/api/site/default/static-dns/66f1e6b2a3c4d5e6f7a8b9c1",
+			servermock.Noop()).
+		Build(t)
+
+	err := provider.CleanUp("lab.example.com", "abc", "123d==")
+	require.NoError(t, err)
+}
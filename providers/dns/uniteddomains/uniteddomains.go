@@ -11,6 +11,7 @@ import (
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/platform/config/env"
 	"github.com/go-acme/lego/v4/providers/dns/internal/ionos"
+	"github.com/go-acme/lego/v4/providers/dns/internal/ttl"
 )
 
 // Environment variables names.
@@ -70,6 +71,10 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 		return nil, errors.New("uniteddomains: the configuration of the DNS provider is nil")
 	}
 
+	cfg := *config
+	cfg.TTL = ttl.Range{Min: ionos.MinTTL}.Clamp("uniteddomains", config.TTL)
+	config = &cfg
+
 	provider, err := ionos.NewDNSProviderConfig(config, defaultBaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("uniteddomains: %w", err)
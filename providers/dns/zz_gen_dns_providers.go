@@ -140,6 +140,7 @@ import (
 	"github.com/go-acme/lego/v4/providers/dns/rfc2136"
 	"github.com/go-acme/lego/v4/providers/dns/rimuhosting"
 	"github.com/go-acme/lego/v4/providers/dns/route53"
+	"github.com/go-acme/lego/v4/providers/dns/routeros"
 	"github.com/go-acme/lego/v4/providers/dns/safedns"
 	"github.com/go-acme/lego/v4/providers/dns/sakuracloud"
 	"github.com/go-acme/lego/v4/providers/dns/scaleway"
@@ -158,6 +159,7 @@ import (
 	"github.com/go-acme/lego/v4/providers/dns/timewebcloud"
 	"github.com/go-acme/lego/v4/providers/dns/transip"
 	"github.com/go-acme/lego/v4/providers/dns/ultradns"
+	"github.com/go-acme/lego/v4/providers/dns/unifi"
 	"github.com/go-acme/lego/v4/providers/dns/uniteddomains"
 	"github.com/go-acme/lego/v4/providers/dns/variomedia"
 	"github.com/go-acme/lego/v4/providers/dns/vegadns"
@@ -453,6 +455,8 @@ func NewDNSChallengeProviderByName(name string) (challenge.Provider, error) {
 		return rimuhosting.NewDNSProvider()
 	case "route53":
 		return route53.NewDNSProvider()
+	case "routeros":
+		return routeros.NewDNSProvider()
 	case "safedns":
 		return safedns.NewDNSProvider()
 	case "sakuracloud":
@@ -489,6 +493,8 @@ func NewDNSChallengeProviderByName(name string) (challenge.Provider, error) {
 		return transip.NewDNSProvider()
 	case "ultradns":
 		return ultradns.NewDNSProvider()
+	case "unifi":
+		return unifi.NewDNSProvider()
 	case "uniteddomains":
 		return uniteddomains.NewDNSProvider()
 	case "variomedia":
@@ -537,3 +543,367 @@ func NewDNSChallengeProviderByName(name string) (challenge.Provider, error) {
 		return nil, fmt.Errorf("unrecognized DNS provider: %s", name)
 	}
 }
+
+// NewDNSChallengeProviderByNameConfig Factory for DNS providers from an explicit configuration.
+// The configuration is either the configuration of the provider (ex: *cloudflare.Config),
+// or the fields of the configuration of the provider (map[string]any, ex: {"AuthToken": "xxx"})
+// applied on a zero configuration with the common defaults (PropagationTimeout, PollingInterval, SequenceInterval, TTL, HTTPClient).
+// The environment variables of the process are never used by the fields.
+func NewDNSChallengeProviderByNameConfig(name string, config any) (challenge.Provider, error) {
+	switch name {
+	case "acme-dns", "acmedns":
+		return newDNSProviderConfig(config, acmedns.NewDNSProviderConfig)
+	case "active24":
+		return newDNSProviderConfig(config, active24.NewDNSProviderConfig)
+	case "alidns":
+		return newDNSProviderConfig(config, alidns.NewDNSProviderConfig)
+	case "aliesa":
+		return newDNSProviderConfig(config, aliesa.NewDNSProviderConfig)
+	case "allinkl":
+		return newDNSProviderConfig(config, allinkl.NewDNSProviderConfig)
+	case "anexia":
+		return newDNSProviderConfig(config, anexia.NewDNSProviderConfig)
+	case "arvancloud":
+		return newDNSProviderConfig(config, arvancloud.NewDNSProviderConfig)
+	case "auroradns":
+		return newDNSProviderConfig(config, auroradns.NewDNSProviderConfig)
+	case "autodns":
+		return newDNSProviderConfig(config, autodns.NewDNSProviderConfig)
+	case "axelname":
+		return newDNSProviderConfig(config, axelname.NewDNSProviderConfig)
+	case "azion":
+		return newDNSProviderConfig(config, azion.NewDNSProviderConfig)
+	case "azure":
+		return newDNSProviderConfig(config, azure.NewDNSProviderConfig)
+	case "azuredns":
+		return newDNSProviderConfig(config, azuredns.NewDNSProviderConfig)
+	case "baiducloud":
+		return newDNSProviderConfig(config, baiducloud.NewDNSProviderConfig)
+	case "beget":
+		return newDNSProviderConfig(config, beget.NewDNSProviderConfig)
+	case "binarylane":
+		return newDNSProviderConfig(config, binarylane.NewDNSProviderConfig)
+	case "bindman":
+		return newDNSProviderConfig(config, bindman.NewDNSProviderConfig)
+	case "bluecat":
+		return newDNSProviderConfig(config, bluecat.NewDNSProviderConfig)
+	case "bookmyname":
+		return newDNSProviderConfig(config, bookmyname.NewDNSProviderConfig)
+	case "brandit":
+		return newDNSProviderConfig(config, brandit.NewDNSProviderConfig)
+	case "bunny":
+		return newDNSProviderConfig(config, bunny.NewDNSProviderConfig)
+	case "checkdomain":
+		return newDNSProviderConfig(config, checkdomain.NewDNSProviderConfig)
+	case "civo":
+		return newDNSProviderConfig(config, civo.NewDNSProviderConfig)
+	case "clouddns":
+		return newDNSProviderConfig(config, clouddns.NewDNSProviderConfig)
+	case "cloudflare":
+		return newDNSProviderConfig(config, cloudflare.NewDNSProviderConfig)
+	case "cloudns":
+		return newDNSProviderConfig(config, cloudns.NewDNSProviderConfig)
+	case "cloudru":
+		return newDNSProviderConfig(config, cloudru.NewDNSProviderConfig)
+	case "cloudxns":
+		return newDNSProviderConfig(config, cloudxns.NewDNSProviderConfig)
+	case "conoha":
+		return newDNSProviderConfig(config, conoha.NewDNSProviderConfig)
+	case "conohav3":
+		return newDNSProviderConfig(config, conohav3.NewDNSProviderConfig)
+	case "constellix":
+		return newDNSProviderConfig(config, constellix.NewDNSProviderConfig)
+	case "corenetworks":
+		return newDNSProviderConfig(config, corenetworks.NewDNSProviderConfig)
+	case "cpanel":
+		return newDNSProviderConfig(config, cpanel.NewDNSProviderConfig)
+	case "derak":
+		return newDNSProviderConfig(config, derak.NewDNSProviderConfig)
+	case "desec":
+		return newDNSProviderConfig(config, desec.NewDNSProviderConfig)
+	case "designate":
+		return newDNSProviderConfig(config, designate.NewDNSProviderConfig)
+	case "digitalocean":
+		return newDNSProviderConfig(config, digitalocean.NewDNSProviderConfig)
+	case "directadmin":
+		return newDNSProviderConfig(config, directadmin.NewDNSProviderConfig)
+	case "dnshomede":
+		return newDNSProviderConfig(config, dnshomede.NewDNSProviderConfig)
+	case "dnsimple":
+		return newDNSProviderConfig(config, dnsimple.NewDNSProviderConfig)
+	case "dnsmadeeasy":
+		return newDNSProviderConfig(config, dnsmadeeasy.NewDNSProviderConfig)
+	case "dnspod":
+		return newDNSProviderConfig(config, dnspod.NewDNSProviderConfig)
+	case "dode":
+		return newDNSProviderConfig(config, dode.NewDNSProviderConfig)
+	case "domeneshop", "domainnameshop":
+		return newDNSProviderConfig(config, domeneshop.NewDNSProviderConfig)
+	case "dreamhost":
+		return newDNSProviderConfig(config, dreamhost.NewDNSProviderConfig)
+	case "duckdns":
+		return newDNSProviderConfig(config, duckdns.NewDNSProviderConfig)
+	case "dyn":
+		return newDNSProviderConfig(config, dyn.NewDNSProviderConfig)
+	case "dyndnsfree":
+		return newDNSProviderConfig(config, dyndnsfree.NewDNSProviderConfig)
+	case "dynu":
+		return newDNSProviderConfig(config, dynu.NewDNSProviderConfig)
+	case "easydns":
+		return newDNSProviderConfig(config, easydns.NewDNSProviderConfig)
+	case "edgecenter":
+		return newDNSProviderConfig(config, edgecenter.NewDNSProviderConfig)
+	case "edgedns", "fastdns":
+		return newDNSProviderConfig(config, edgedns.NewDNSProviderConfig)
+	case "edgeone":
+		return newDNSProviderConfig(config, edgeone.NewDNSProviderConfig)
+	case "efficientip":
+		return newDNSProviderConfig(config, efficientip.NewDNSProviderConfig)
+	case "epik":
+		return newDNSProviderConfig(config, epik.NewDNSProviderConfig)
+	case "exec":
+		return newDNSProviderConfig(config, exec.NewDNSProviderConfig)
+	case "exoscale":
+		return newDNSProviderConfig(config, exoscale.NewDNSProviderConfig)
+	case "f5xc":
+		return newDNSProviderConfig(config, f5xc.NewDNSProviderConfig)
+	case "freemyip":
+		return newDNSProviderConfig(config, freemyip.NewDNSProviderConfig)
+	case "gandi":
+		return newDNSProviderConfig(config, gandi.NewDNSProviderConfig)
+	case "gandiv5":
+		return newDNSProviderConfig(config, gandiv5.NewDNSProviderConfig)
+	case "gcloud":
+		return newDNSProviderConfig(config, gcloud.NewDNSProviderConfig)
+	case "gcore":
+		return newDNSProviderConfig(config, gcore.NewDNSProviderConfig)
+	case "gigahostno":
+		return newDNSProviderConfig(config, gigahostno.NewDNSProviderConfig)
+	case "glesys":
+		return newDNSProviderConfig(config, glesys.NewDNSProviderConfig)
+	case "godaddy":
+		return newDNSProviderConfig(config, godaddy.NewDNSProviderConfig)
+	case "googledomains":
+		return newDNSProviderConfig(config, googledomains.NewDNSProviderConfig)
+	case "gravity":
+		return newDNSProviderConfig(config, gravity.NewDNSProviderConfig)
+	case "hetzner":
+		return newDNSProviderConfig(config, hetzner.NewDNSProviderConfig)
+	case "hostingde":
+		return newDNSProviderConfig(config, hostingde.NewDNSProviderConfig)
+	case "hostinger":
+		return newDNSProviderConfig(config, hostinger.NewDNSProviderConfig)
+	case "hosttech":
+		return newDNSProviderConfig(config, hosttech.NewDNSProviderConfig)
+	case "httpnet":
+		return newDNSProviderConfig(config, httpnet.NewDNSProviderConfig)
+	case "httpreq":
+		return newDNSProviderConfig(config, httpreq.NewDNSProviderConfig)
+	case "huaweicloud":
+		return newDNSProviderConfig(config, huaweicloud.NewDNSProviderConfig)
+	case "hurricane":
+		return newDNSProviderConfig(config, hurricane.NewDNSProviderConfig)
+	case "hyperone":
+		return newDNSProviderConfig(config, hyperone.NewDNSProviderConfig)
+	case "ibmcloud":
+		return newDNSProviderConfig(config, ibmcloud.NewDNSProviderConfig)
+	case "iij":
+		return newDNSProviderConfig(config, iij.NewDNSProviderConfig)
+	case "iijdpf":
+		return newDNSProviderConfig(config, iijdpf.NewDNSProviderConfig)
+	case "infoblox":
+		return newDNSProviderConfig(config, infoblox.NewDNSProviderConfig)
+	case "infomaniak":
+		return newDNSProviderConfig(config, infomaniak.NewDNSProviderConfig)
+	case "internetbs":
+		return newDNSProviderConfig(config, internetbs.NewDNSProviderConfig)
+	case "inwx":
+		return newDNSProviderConfig(config, inwx.NewDNSProviderConfig)
+	case "ionos":
+		return newDNSProviderConfig(config, ionos.NewDNSProviderConfig)
+	case "ipv64":
+		return newDNSProviderConfig(config, ipv64.NewDNSProviderConfig)
+	case "iwantmyname":
+		return newDNSProviderConfig(config, iwantmyname.NewDNSProviderConfig)
+	case "joker":
+		return newDNSProviderConfig(config, joker.NewDNSProviderConfig)
+	case "keyhelp":
+		return newDNSProviderConfig(config, keyhelp.NewDNSProviderConfig)
+	case "liara":
+		return newDNSProviderConfig(config, liara.NewDNSProviderConfig)
+	case "lightsail":
+		return newDNSProviderConfig(config, lightsail.NewDNSProviderConfig)
+	case "limacity":
+		return newDNSProviderConfig(config, limacity.NewDNSProviderConfig)
+	case "linode", "linodev4":
+		return newDNSProviderConfig(config, linode.NewDNSProviderConfig)
+	case "liquidweb":
+		return newDNSProviderConfig(config, liquidweb.NewDNSProviderConfig)
+	case "loopia":
+		return newDNSProviderConfig(config, loopia.NewDNSProviderConfig)
+	case "luadns":
+		return newDNSProviderConfig(config, luadns.NewDNSProviderConfig)
+	case "mailinabox":
+		return newDNSProviderConfig(config, mailinabox.NewDNSProviderConfig)
+	case "manageengine":
+		return newDNSProviderConfig(config, manageengine.NewDNSProviderConfig)
+	case "manual":
+		return newDNSProviderConfig(config, manual.NewDNSProviderConfig)
+	case "metaname":
+		return newDNSProviderConfig(config, metaname.NewDNSProviderConfig)
+	case "metaregistrar":
+		return newDNSProviderConfig(config, metaregistrar.NewDNSProviderConfig)
+	case "mijnhost":
+		return newDNSProviderConfig(config, mijnhost.NewDNSProviderConfig)
+	case "mittwald":
+		return newDNSProviderConfig(config, mittwald.NewDNSProviderConfig)
+	case "myaddr":
+		return newDNSProviderConfig(config, myaddr.NewDNSProviderConfig)
+	case "mydnsjp":
+		return newDNSProviderConfig(config, mydnsjp.NewDNSProviderConfig)
+	case "mythicbeasts":
+		return newDNSProviderConfig(config, mythicbeasts.NewDNSProviderConfig)
+	case "namecheap":
+		return newDNSProviderConfig(config, namecheap.NewDNSProviderConfig)
+	case "namedotcom":
+		return newDNSProviderConfig(config, namedotcom.NewDNSProviderConfig)
+	case "namesilo":
+		return newDNSProviderConfig(config, namesilo.NewDNSProviderConfig)
+	case "nearlyfreespeech":
+		return newDNSProviderConfig(config, nearlyfreespeech.NewDNSProviderConfig)
+	case "neodigit":
+		return newDNSProviderConfig(config, neodigit.NewDNSProviderConfig)
+	case "netcup":
+		return newDNSProviderConfig(config, netcup.NewDNSProviderConfig)
+	case "netlify":
+		return newDNSProviderConfig(config, netlify.NewDNSProviderConfig)
+	case "nicmanager":
+		return newDNSProviderConfig(config, nicmanager.NewDNSProviderConfig)
+	case "nicru":
+		return newDNSProviderConfig(config, nicru.NewDNSProviderConfig)
+	case "nifcloud":
+		return newDNSProviderConfig(config, nifcloud.NewDNSProviderConfig)
+	case "njalla":
+		return newDNSProviderConfig(config, njalla.NewDNSProviderConfig)
+	case "nodion":
+		return newDNSProviderConfig(config, nodion.NewDNSProviderConfig)
+	case "ns1":
+		return newDNSProviderConfig(config, ns1.NewDNSProviderConfig)
+	case "octenium":
+		return newDNSProviderConfig(config, octenium.NewDNSProviderConfig)
+	case "oraclecloud":
+		return newDNSProviderConfig(config, oraclecloud.NewDNSProviderConfig)
+	case "otc":
+		return newDNSProviderConfig(config, otc.NewDNSProviderConfig)
+	case "ovh":
+		return newDNSProviderConfig(config, ovh.NewDNSProviderConfig)
+	case "pdns":
+		return newDNSProviderConfig(config, pdns.NewDNSProviderConfig)
+	case "plesk":
+		return newDNSProviderConfig(config, plesk.NewDNSProviderConfig)
+	case "porkbun":
+		return newDNSProviderConfig(config, porkbun.NewDNSProviderConfig)
+	case "rackspace":
+		return newDNSProviderConfig(config, rackspace.NewDNSProviderConfig)
+	case "rainyun":
+		return newDNSProviderConfig(config, rainyun.NewDNSProviderConfig)
+	case "rcodezero":
+		return newDNSProviderConfig(config, rcodezero.NewDNSProviderConfig)
+	case "regfish":
+		return newDNSProviderConfig(config, regfish.NewDNSProviderConfig)
+	case "regru":
+		return newDNSProviderConfig(config, regru.NewDNSProviderConfig)
+	case "rfc2136":
+		return newDNSProviderConfig(config, rfc2136.NewDNSProviderConfig)
+	case "rimuhosting":
+		return newDNSProviderConfig(config, rimuhosting.NewDNSProviderConfig)
+	case "route53":
+		return newDNSProviderConfig(config, route53.NewDNSProviderConfig)
+	case "routeros":
+		return newDNSProviderConfig(config, routeros.NewDNSProviderConfig)
+	case "safedns":
+		return newDNSProviderConfig(config, safedns.NewDNSProviderConfig)
+	case "sakuracloud":
+		return newDNSProviderConfig(config, sakuracloud.NewDNSProviderConfig)
+	case "scaleway":
+		return newDNSProviderConfig(config, scaleway.NewDNSProviderConfig)
+	case "selectel":
+		return newDNSProviderConfig(config, selectel.NewDNSProviderConfig)
+	case "selectelv2":
+		return newDNSProviderConfig(config, selectelv2.NewDNSProviderConfig)
+	case "selfhostde":
+		return newDNSProviderConfig(config, selfhostde.NewDNSProviderConfig)
+	case "servercow":
+		return newDNSProviderConfig(config, servercow.NewDNSProviderConfig)
+	case "shellrent":
+		return newDNSProviderConfig(config, shellrent.NewDNSProviderConfig)
+	case "simply":
+		return newDNSProviderConfig(config, simply.NewDNSProviderConfig)
+	case "sonic":
+		return newDNSProviderConfig(config, sonic.NewDNSProviderConfig)
+	case "spaceship":
+		return newDNSProviderConfig(config, spaceship.NewDNSProviderConfig)
+	case "stackpath":
+		return newDNSProviderConfig(config, stackpath.NewDNSProviderConfig)
+	case "syse":
+		return newDNSProviderConfig(config, syse.NewDNSProviderConfig)
+	case "technitium":
+		return newDNSProviderConfig(config, technitium.NewDNSProviderConfig)
+	case "tencentcloud":
+		return newDNSProviderConfig(config, tencentcloud.NewDNSProviderConfig)
+	case "timewebcloud":
+		return newDNSProviderConfig(config, timewebcloud.NewDNSProviderConfig)
+	case "transip":
+		return newDNSProviderConfig(config, transip.NewDNSProviderConfig)
+	case "ultradns":
+		return newDNSProviderConfig(config, ultradns.NewDNSProviderConfig)
+	case "unifi":
+		return newDNSProviderConfig(config, unifi.NewDNSProviderConfig)
+	case "uniteddomains":
+		return newDNSProviderConfig(config, uniteddomains.NewDNSProviderConfig)
+	case "variomedia":
+		return newDNSProviderConfig(config, variomedia.NewDNSProviderConfig)
+	case "vegadns":
+		return newDNSProviderConfig(config, vegadns.NewDNSProviderConfig)
+	case "vercel":
+		return newDNSProviderConfig(config, vercel.NewDNSProviderConfig)
+	case "versio":
+		return newDNSProviderConfig(config, versio.NewDNSProviderConfig)
+	case "vinyldns":
+		return newDNSProviderConfig(config, vinyldns.NewDNSProviderConfig)
+	case "virtualname":
+		return newDNSProviderConfig(config, virtualname.NewDNSProviderConfig)
+	case "vkcloud":
+		return newDNSProviderConfig(config, vkcloud.NewDNSProviderConfig)
+	case "volcengine":
+		return newDNSProviderConfig(config, volcengine.NewDNSProviderConfig)
+	case "vscale":
+		return newDNSProviderConfig(config, vscale.NewDNSProviderConfig)
+	case "vultr":
+		return newDNSProviderConfig(config, vultr.NewDNSProviderConfig)
+	case "webnames", "webnamesru":
+		return newDNSProviderConfig(config, webnames.NewDNSProviderConfig)
+	case "webnamesca":
+		return newDNSProviderConfig(config, webnamesca.NewDNSProviderConfig)
+	case "websupport":
+		return newDNSProviderConfig(config, websupport.NewDNSProviderConfig)
+	case "wedos":
+		return newDNSProviderConfig(config, wedos.NewDNSProviderConfig)
+	case "westcn":
+		return newDNSProviderConfig(config, westcn.NewDNSProviderConfig)
+	case "yandex":
+		return newDNSProviderConfig(config, yandex.NewDNSProviderConfig)
+	case "yandex360":
+		return newDNSProviderConfig(config, yandex360.NewDNSProviderConfig)
+	case "yandexcloud":
+		return newDNSProviderConfig(config, yandexcloud.NewDNSProviderConfig)
+	case "zoneedit":
+		return newDNSProviderConfig(config, zoneedit.NewDNSProviderConfig)
+	case "zoneee":
+		return newDNSProviderConfig(config, zoneee.NewDNSProviderConfig)
+	case "zonomi":
+		return newDNSProviderConfig(config, zonomi.NewDNSProviderConfig)
+	default:
+		return nil, fmt.Errorf("unrecognized DNS provider: %s", name)
+	}
+}
@@ -0,0 +1,176 @@
+// Package edgekv implements an HTTP provider for solving the HTTP-01 challenge using Akamai EdgeKV.
+package edgekv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v11/pkg/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v11/pkg/edgeworkers"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v11/pkg/session"
+	"github.com/go-acme/lego/v4/platform/config/env"
+	"github.com/go-acme/lego/v4/providers/http/internal/propagation"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "AKAMAI_"
+
+	EnvEdgeRc           = envNamespace + "EDGERC"
+	EnvEdgeRcSection    = envNamespace + "EDGERC_SECTION"
+	EnvAccountSwitchKey = envNamespace + "ACCOUNT_SWITCH_KEY"
+
+	EnvNamespace = envNamespace + "EDGEKV_NAMESPACE"
+	EnvGroup     = envNamespace + "EDGEKV_GROUP"
+	EnvNetwork   = envNamespace + "EDGEKV_NETWORK"
+
+	EnvPropagationTimeout = envNamespace + "EDGEKV_PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "EDGEKV_POLLING_INTERVAL"
+)
+
+const (
+	defaultGroup   = "acme-challenge"
+	defaultNetwork = string(edgeworkers.ItemProductionNetwork)
+)
+
+// Config is used to configure the creation of the HTTPProvider.
+type Config struct {
+	*edgegrid.Config
+
+	// Namespace the EdgeKV namespace.
+	Namespace string
+	// Group the EdgeKV group inside the namespace.
+	Group string
+	// Network the EdgeKV network (`staging` or `production`).
+	Network string
+
+	// PropagationTimeout the maximum duration to wait for the challenge to be served at the edge (0 disables the check).
+	PropagationTimeout time.Duration
+	PollingInterval    time.Duration
+}
+
+// NewDefaultConfig returns a default configuration for the HTTPProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Group:   env.GetOrDefaultString(EnvGroup, defaultGroup),
+		Network: env.GetOrDefaultString(EnvNetwork, defaultNetwork),
+		Config:  &edgegrid.Config{},
+
+		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 2*time.Minute),
+		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 5*time.Second),
+	}
+}
+
+// HTTPProvider implements ChallengeProvider for `http-01` challenge.
+// The challenges are stored in an EdgeKV group (the item ID is the token, the value is the key authorization),
+// an EdgeWorker must answer the requests to `/.well-known/acme-challenge/<token>` with the EdgeKV items.
+type HTTPProvider struct {
+	config *Config
+	client edgeworkers.Edgeworkers
+}
+
+// NewHTTPProvider returns a HTTPProvider instance configured for Akamai EdgeKV.
+// Akamai's credentials are automatically detected in the same locations as the `edgedns` DNS provider:
+// environment variables (`AKAMAI_HOST`, `AKAMAI_ACCESS_TOKEN`, `AKAMAI_CLIENT_TOKEN`, `AKAMAI_CLIENT_SECRET`),
+// or .edgerc file located at `AKAMAI_EDGERC` (defaults to `~/.edgerc`, sections can be specified using `AKAMAI_EDGERC_SECTION`).
+// The EdgeKV namespace must be passed in the environment variable `AKAMAI_EDGEKV_NAMESPACE`.
+func NewHTTPProvider() (*HTTPProvider, error) {
+	values, err := env.Get(EnvNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("edgekv: %w", err)
+	}
+
+	conf, err := edgegrid.New(
+		edgegrid.WithEnv(true),
+		edgegrid.WithFile(env.GetOrDefaultString(EnvEdgeRc, "~/.edgerc")),
+		edgegrid.WithSection(env.GetOrDefaultString(EnvEdgeRcSection, "default")),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("edgekv: %w", err)
+	}
+
+	accountSwitchKey := env.GetOrDefaultString(EnvAccountSwitchKey, "")
+
+	if accountSwitchKey != "" {
+		conf.AccountKey = accountSwitchKey
+	}
+
+	config := NewDefaultConfig()
+	config.Config = conf
+	config.Namespace = values[EnvNamespace]
+
+	return NewHTTPProviderConfig(config)
+}
+
+// NewHTTPProviderConfig return a HTTPProvider instance configured for Akamai EdgeKV.
+func NewHTTPProviderConfig(config *Config) (*HTTPProvider, error) {
+	if config == nil {
+		return nil, errors.New("edgekv: the configuration of the HTTP provider is nil")
+	}
+
+	if config.Namespace == "" || config.Group == "" {
+		return nil, errors.New("edgekv: the namespace and the group are required")
+	}
+
+	switch edgeworkers.ItemNetwork(config.Network) {
+	case edgeworkers.ItemStagingNetwork, edgeworkers.ItemProductionNetwork:
+	default:
+		return nil, fmt.Errorf("edgekv: invalid network %q: must be %q or %q",
+			config.Network, edgeworkers.ItemStagingNetwork, edgeworkers.ItemProductionNetwork)
+	}
+
+	err := config.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("edgekv: %w", err)
+	}
+
+	sess, err := session.New(session.WithSigner(config))
+	if err != nil {
+		return nil, fmt.Errorf("edgekv: %w", err)
+	}
+
+	return &HTTPProvider{config: config, client: edgeworkers.Client(sess)}, nil
+}
+
+// Present makes the token available at `HTTP01ChallengePath(token)` by creating an EdgeKV item.
+// The writes to EdgeKV take several seconds to be visible at the edge: Present waits until the key authorization is served at the edge.
+func (p *HTTPProvider) Present(domain, token, keyAuth string) error {
+	_, err := p.client.UpsertItem(context.Background(), edgeworkers.UpsertItemRequest{
+		ItemID:             token,
+		ItemData:           edgeworkers.Item(keyAuth),
+		ItemsRequestParams: p.requestParams(),
+	})
+	if err != nil {
+		return fmt.Errorf("edgekv: unable to create the item for %s: %w", domain, err)
+	}
+
+	err = propagation.WaitFor(domain, token, keyAuth, p.config.PropagationTimeout, p.config.PollingInterval)
+	if err != nil {
+		return fmt.Errorf("edgekv: %w", err)
+	}
+
+	return nil
+}
+
+// CleanUp removes the EdgeKV item created for the challenge.
+func (p *HTTPProvider) CleanUp(domain, token, keyAuth string) error {
+	_, err := p.client.DeleteItem(context.Background(), edgeworkers.DeleteItemRequest{
+		ItemID:             token,
+		ItemsRequestParams: p.requestParams(),
+	})
+	if err != nil {
+		return fmt.Errorf("edgekv: unable to delete the item for %s: %w", domain, err)
+	}
+
+	return nil
+}
+
+func (p *HTTPProvider) requestParams() edgeworkers.ItemsRequestParams {
+	return edgeworkers.ItemsRequestParams{
+		Network:     edgeworkers.ItemNetwork(p.config.Network),
+		NamespaceID: p.config.Namespace,
+		GroupID:     p.config.Group,
+	}
+}
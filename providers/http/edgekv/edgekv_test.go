@@ -0,0 +1,130 @@
+package edgekv
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v11/pkg/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v11/pkg/edgeworkers"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	envHost         = envNamespace + "HOST"
+	envClientToken  = envNamespace + "CLIENT_TOKEN"
+	envClientSecret = envNamespace + "CLIENT_SECRET"
+	envAccessToken  = envNamespace + "ACCESS_TOKEN"
+)
+
+var envTest = tester.NewEnvTest(
+	envHost,
+	envClientToken,
+	envClientSecret,
+	envAccessToken,
+	EnvEdgeRc,
+	EnvEdgeRcSection,
+	EnvAccountSwitchKey,
+	EnvNamespace,
+	EnvGroup,
+	EnvNetwork)
+
+func TestNewHTTPProvider(t *testing.T) {
+	credentials := map[string]string{
+		envHost:         "akaa-xxxxxxxxxxxxxxxx-xxxxxxxxxxxxxxxx.luna.akamaiapis.net",
+		envClientToken:  "akab-xxxxxxxxxxxxxxxx-xxxxxxxxxxxxxxxx",
+		envClientSecret: "xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx",
+		envAccessToken:  "akac-xxxxxxxxxxxxxxxx-xxxxxxxxxxxxxxxx",
+		EnvEdgeRc:       "/dev/null",
+	}
+
+	testCases := []struct {
+		desc     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			desc:    "success",
+			envVars: map[string]string{EnvNamespace: "lego"},
+		},
+		{
+			desc:     "missing namespace",
+			envVars:  map[string]string{},
+			expected: "edgekv: some credentials information are missing: AKAMAI_EDGEKV_NAMESPACE",
+		},
+		{
+			desc:     "invalid network",
+			envVars:  map[string]string{EnvNamespace: "lego", EnvNetwork: "foo"},
+			expected: `edgekv: invalid network "foo": must be "staging" or "production"`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+
+			envTest.ClearEnv()
+
+			envTest.Apply(credentials)
+			envTest.Apply(test.envVars)
+
+			p, err := NewHTTPProvider()
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.Equal(t, defaultGroup, p.config.Group)
+				require.Equal(t, defaultNetwork, p.config.Network)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewHTTPProviderConfig(t *testing.T) {
+	config := NewDefaultConfig()
+	config.Config = &edgegrid.Config{}
+	config.Namespace = "lego"
+
+	_, err := NewHTTPProviderConfig(config)
+	require.Error(t, err)
+
+	_, err = NewHTTPProviderConfig(nil)
+	require.EqualError(t, err, "edgekv: the configuration of the HTTP provider is nil")
+}
+
+func TestHTTPProvider_PresentCleanUp(t *testing.T) {
+	client := &edgeworkers.Mock{}
+
+	params := edgeworkers.ItemsRequestParams{
+		Network:     edgeworkers.ItemStagingNetwork,
+		NamespaceID: "lego",
+		GroupID:     "challenges",
+	}
+
+	client.On("UpsertItem", mock.Anything, edgeworkers.UpsertItemRequest{
+		ItemID:             "token",
+		ItemData:           "keyAuth",
+		ItemsRequestParams: params,
+	}).Return(nil, nil).Once()
+
+	client.On("DeleteItem", mock.Anything, edgeworkers.DeleteItemRequest{
+		ItemID:             "token",
+		ItemsRequestParams: params,
+	}).Return(nil, errors.New("boom")).Once()
+
+	p := &HTTPProvider{
+		config: &Config{Namespace: "lego", Group: "challenges", Network: "staging"},
+		client: client,
+	}
+
+	err := p.Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	err = p.CleanUp("example.com", "token", "keyAuth")
+	require.EqualError(t, err, "edgekv: unable to delete the item for example.com: boom")
+
+	client.AssertExpectations(t)
+}
@@ -0,0 +1,123 @@
+// Package fastly implements an HTTP provider for solving the HTTP-01 challenge using a Fastly edge dictionary.
+package fastly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-acme/lego/v4/platform/config/env"
+	"github.com/go-acme/lego/v4/providers/http/fastly/internal"
+	"github.com/go-acme/lego/v4/providers/http/internal/propagation"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "FASTLY_"
+
+	EnvAPIToken     = envNamespace + "API_TOKEN"
+	EnvServiceID    = envNamespace + "SERVICE_ID"
+	EnvDictionaryID = envNamespace + "DICTIONARY_ID"
+
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
+)
+
+// Config is used to configure the creation of the HTTPProvider.
+type Config struct {
+	APIToken     string
+	ServiceID    string
+	DictionaryID string
+
+	// PropagationTimeout the maximum duration to wait for the challenge to be served at the edge (0 disables the check).
+	PropagationTimeout time.Duration
+	PollingInterval    time.Duration
+	HTTPClient         *http.Client
+}
+
+// NewDefaultConfig returns a default configuration for the HTTPProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 2*time.Minute),
+		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 5*time.Second),
+		HTTPClient: &http.Client{
+			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+		},
+	}
+}
+
+// HTTPProvider implements ChallengeProvider for `http-01` challenge.
+// The challenges are stored in an edge dictionary (the key is the token, the value is the key authorization),
+// the VCL of the service must answer the requests to `/.well-known/acme-challenge/<token>` with the dictionary.
+type HTTPProvider struct {
+	config *Config
+	client *internal.Client
+}
+
+// NewHTTPProvider returns a HTTPProvider instance configured for Fastly.
+// Credentials must be passed in the environment variables:
+// FASTLY_API_TOKEN, FASTLY_SERVICE_ID, and FASTLY_DICTIONARY_ID.
+func NewHTTPProvider() (*HTTPProvider, error) {
+	values, err := env.Get(EnvAPIToken, EnvServiceID, EnvDictionaryID)
+	if err != nil {
+		return nil, fmt.Errorf("fastly: %w", err)
+	}
+
+	config := NewDefaultConfig()
+	config.APIToken = values[EnvAPIToken]
+	config.ServiceID = values[EnvServiceID]
+	config.DictionaryID = values[EnvDictionaryID]
+
+	return NewHTTPProviderConfig(config)
+}
+
+// NewHTTPProviderConfig return a HTTPProvider instance configured for Fastly.
+func NewHTTPProviderConfig(config *Config) (*HTTPProvider, error) {
+	if config == nil {
+		return nil, errors.New("fastly: the configuration of the HTTP provider is nil")
+	}
+
+	if config.ServiceID == "" || config.DictionaryID == "" {
+		return nil, errors.New("fastly: the service ID and the dictionary ID are required")
+	}
+
+	client, err := internal.NewClient(config.APIToken)
+	if err != nil {
+		return nil, fmt.Errorf("fastly: %w", err)
+	}
+
+	if config.HTTPClient != nil {
+		client.HTTPClient = config.HTTPClient
+	}
+
+	return &HTTPProvider{config: config, client: client}, nil
+}
+
+// Present makes the token available at `HTTP01ChallengePath(token)` by adding an item to the edge dictionary.
+// The edge dictionaries are eventually consistent: Present waits until the key authorization is served at the edge.
+func (p *HTTPProvider) Present(domain, token, keyAuth string) error {
+	err := p.client.UpsertDictionaryItem(context.Background(), p.config.ServiceID, p.config.DictionaryID, token, keyAuth)
+	if err != nil {
+		return fmt.Errorf("fastly: unable to add the challenge for %s to the dictionary: %w", domain, err)
+	}
+
+	err = propagation.WaitFor(domain, token, keyAuth, p.config.PropagationTimeout, p.config.PollingInterval)
+	if err != nil {
+		return fmt.Errorf("fastly: %w", err)
+	}
+
+	return nil
+}
+
+// CleanUp removes the item created for the challenge.
+func (p *HTTPProvider) CleanUp(domain, token, keyAuth string) error {
+	err := p.client.DeleteDictionaryItem(context.Background(), p.config.ServiceID, p.config.DictionaryID, token)
+	if err != nil {
+		return fmt.Errorf("fastly: unable to remove the challenge for %s from the dictionary: %w", domain, err)
+	}
+
+	return nil
+}
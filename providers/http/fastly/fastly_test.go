@@ -0,0 +1,107 @@
+package fastly
+
+import (
+	"testing"
+
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/require"
+)
+
+var envTest = tester.NewEnvTest(EnvAPIToken, EnvServiceID, EnvDictionaryID)
+
+func TestNewHTTPProvider(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			desc: "success",
+			envVars: map[string]string{
+				EnvAPIToken:     "secret",
+				EnvServiceID:    "srv",
+				EnvDictionaryID: "dict",
+			},
+		},
+		{
+			desc:     "missing credentials",
+			envVars:  map[string]string{},
+			expected: "fastly: some credentials information are missing: FASTLY_API_TOKEN,FASTLY_SERVICE_ID,FASTLY_DICTIONARY_ID",
+		},
+		{
+			desc: "missing dictionary ID",
+			envVars: map[string]string{
+				EnvAPIToken:  "secret",
+				EnvServiceID: "srv",
+			},
+			expected: "fastly: some credentials information are missing: FASTLY_DICTIONARY_ID",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			p, err := NewHTTPProvider()
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				require.NotNil(t, p.config)
+				require.NotNil(t, p.client)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewHTTPProviderConfig(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		apiToken     string
+		serviceID    string
+		dictionaryID string
+		expected     string
+	}{
+		{
+			desc:         "success",
+			apiToken:     "secret",
+			serviceID:    "srv",
+			dictionaryID: "dict",
+		},
+		{
+			desc:         "missing API token",
+			serviceID:    "srv",
+			dictionaryID: "dict",
+			expected:     "fastly: credentials missing",
+		},
+		{
+			desc:     "missing service ID",
+			apiToken: "secret",
+			expected: "fastly: the service ID and the dictionary ID are required",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			config := NewDefaultConfig()
+			config.APIToken = test.apiToken
+			config.ServiceID = test.serviceID
+			config.DictionaryID = test.dictionaryID
+
+			p, err := NewHTTPProviderConfig(config)
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultBaseURL = "https://api.fastly.com"
+
+const authHeader = "Fastly-Key"
+
+// Client the Fastly API client.
+type Client struct {
+	apiToken string
+
+	baseURL    *url.URL
+	HTTPClient *http.Client
+}
+
+// NewClient creates a new Client.
+func NewClient(apiToken string) (*Client, error) {
+	if apiToken == "" {
+		return nil, errors.New("credentials missing")
+	}
+
+	baseURL, _ := url.Parse(defaultBaseURL)
+
+	return &Client{
+		apiToken:   apiToken,
+		baseURL:    baseURL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// UpsertDictionaryItem creates or updates an item of an edge dictionary.
+// https://www.fastly.com/documentation/reference/api/dictionaries/dictionary-item/#upsert-dictionary-item
+func (c *Client) UpsertDictionaryItem(ctx context.Context, serviceID, dictionaryID, key, value string) error {
+	endpoint := c.baseURL.JoinPath("service", serviceID, "dictionary", dictionaryID, "item", key)
+
+	data := url.Values{}
+	data.Set("item_value", value)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint.String(), strings.NewReader(data.Encode()))
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return c.do(req, &DictionaryItem{})
+}
+
+// DeleteDictionaryItem deletes an item of an edge dictionary.
+// https://www.fastly.com/documentation/reference/api/dictionaries/dictionary-item/#delete-dictionary-item
+func (c *Client) DeleteDictionaryItem(ctx context.Context, serviceID, dictionaryID, key string) error {
+	endpoint := c.baseURL.JoinPath("service", serviceID, "dictionary", dictionaryID, "item", key)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint.String(), http.NoBody)
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+
+	return c.do(req, nil)
+}
+
+func (c *Client) do(req *http.Request, result any) error {
+	req.Header.Set(authHeader, c.apiToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to communicate with the API server: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("unable to read response body: %w", err)
+	}
+
+	if resp.StatusCode/100 != 2 {
+		return parseError(req, resp.StatusCode, raw)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	err = json.Unmarshal(raw, result)
+	if err != nil {
+		return fmt.Errorf("unable to unmarshal response: [status code: %d] body: %s, error: %w", resp.StatusCode, string(raw), err)
+	}
+
+	return nil
+}
+
+func parseError(req *http.Request, statusCode int, raw []byte) error {
+	errAPI := &APIError{}
+
+	err := json.Unmarshal(raw, errAPI)
+	if err != nil || errAPI.Message == "" {
+		return fmt.Errorf("unexpected status code: [status code: %d] %s %s: %s", statusCode, req.Method, req.URL.Path, string(raw))
+	}
+
+	return fmt.Errorf("[status code: %d] %w", statusCode, errAPI)
+}
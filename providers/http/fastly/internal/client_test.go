@@ -0,0 +1,72 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-acme/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupClient(server *httptest.Server) (*Client, error) {
+	client, err := NewClient("secret")
+	if err != nil {
+		return nil, err
+	}
+
+	client.baseURL, _ = url.Parse(server.URL)
+	client.HTTPClient = server.Client()
+
+	return client, nil
+}
+
+func TestClient_UpsertDictionaryItem(t *testing.T) {
+	client := servermock.NewBuilder[*Client](setupClient,
+		servermock.CheckHeader().
+			With(authHeader, "secret").
+			WithContentTypeFromURLEncoded()).
+		Route("PUT /service/srv/dictionary/dict/item/token",
+			servermock.RawStringResponse(`{"dictionary_id":"dict","service_id":"srv","item_key":"token","item_value":"keyAuth"}`),
+			servermock.CheckForm().Strict().
+				With("item_value", "keyAuth")).
+		Build(t)
+
+	err := client.UpsertDictionaryItem(t.Context(), "srv", "dict", "token", "keyAuth")
+	require.NoError(t, err)
+}
+
+func TestClient_UpsertDictionaryItem_error(t *testing.T) {
+	client := servermock.NewBuilder[*Client](setupClient).
+		Route("PUT /service/srv/dictionary/dict/item/token",
+			servermock.RawStringResponse(`{"msg":"Bad request","detail":"Dictionary not found"}`).
+				WithStatusCode(http.StatusBadRequest)).
+		Build(t)
+
+	err := client.UpsertDictionaryItem(t.Context(), "srv", "dict", "token", "keyAuth")
+	require.EqualError(t, err, "[status code: 400] Bad request: Dictionary not found")
+}
+
+func TestClient_DeleteDictionaryItem(t *testing.T) {
+	client := servermock.NewBuilder[*Client](setupClient,
+		servermock.CheckHeader().
+			With(authHeader, "secret")).
+		Route("DELETE /service/srv/dictionary/dict/item/token",
+			servermock.RawStringResponse(`{"status":"ok"}`)).
+		Build(t)
+
+	err := client.DeleteDictionaryItem(t.Context(), "srv", "dict", "token")
+	require.NoError(t, err)
+}
+
+func TestClient_DeleteDictionaryItem_error(t *testing.T) {
+	client := servermock.NewBuilder[*Client](setupClient).
+		Route("DELETE /service/srv/dictionary/dict/item/token",
+			servermock.RawStringResponse(`not found`).
+				WithStatusCode(http.StatusNotFound)).
+		Build(t)
+
+	err := client.DeleteDictionaryItem(t.Context(), "srv", "dict", "token")
+	require.EqualError(t, err, "unexpected status code: [status code: 404] DELETE /service/srv/dictionary/dict/item/token: not found")
+}
@@ -0,0 +1,25 @@
+package internal
+
+import "fmt"
+
+// DictionaryItem an item of an edge dictionary.
+type DictionaryItem struct {
+	DictionaryID string `json:"dictionary_id,omitempty"`
+	ServiceID    string `json:"service_id,omitempty"`
+	ItemKey      string `json:"item_key,omitempty"`
+	ItemValue    string `json:"item_value,omitempty"`
+}
+
+// APIError an error returned by the Fastly API.
+type APIError struct {
+	Message string `json:"msg"`
+	Detail  string `json:"detail"`
+}
+
+func (a *APIError) Error() string {
+	if a.Detail == "" {
+		return a.Message
+	}
+
+	return fmt.Sprintf("%s: %s", a.Message, a.Detail)
+}
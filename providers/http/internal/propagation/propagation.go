@@ -0,0 +1,53 @@
+// Package propagation checks that the challenges stored by the CDN providers are served at the edge.
+package propagation
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/http01"
+	"github.com/go-acme/lego/v4/platform/wait"
+)
+
+// maxBodySize the maximum size of the body read from the challenge URL.
+const maxBodySize = 64 * 1024
+
+// WaitFor polls the challenge URL of the domain (`http://<domain>/.well-known/acme-challenge/<token>`)
+// until the key authorization is served, up to timeout.
+// The check is disabled when the timeout is not positive.
+func WaitFor(domain, token, keyAuth string, timeout, interval time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	challengeURL := "http://" + domain + http01.ChallengePath(token)
+
+	return wait.For("propagation of the challenge to "+challengeURL, timeout, interval, func() (bool, error) {
+		resp, err := client.Get(challengeURL)
+		if err != nil {
+			return false, err
+		}
+
+		defer func() { _ = resp.Body.Close() }()
+
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+		if err != nil {
+			return false, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+
+		if !bytes.Equal(bytes.TrimSpace(body), []byte(keyAuth)) {
+			return false, fmt.Errorf("the key authorization is not served yet: got %q", body)
+		}
+
+		return true, nil
+	})
+}
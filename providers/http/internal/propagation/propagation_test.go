@@ -0,0 +1,58 @@
+package propagation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/platform/wait"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitFor(t *testing.T) {
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/.well-known/acme-challenge/token" {
+			http.NotFound(rw, req)
+			return
+		}
+
+		// The key authorization is served from the third request.
+		if calls.Add(1) < 3 {
+			http.NotFound(rw, req)
+			return
+		}
+
+		_, _ = rw.Write([]byte("keyAuth"))
+	}))
+	t.Cleanup(server.Close)
+
+	domain := strings.TrimPrefix(server.URL, "http://")
+
+	err := WaitFor(domain, "token", "keyAuth", 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 3, calls.Load())
+}
+
+func TestWaitFor_timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte("other"))
+	}))
+	t.Cleanup(server.Close)
+
+	domain := strings.TrimPrefix(server.URL, "http://")
+
+	err := WaitFor(domain, "token", "keyAuth", 50*time.Millisecond, 10*time.Millisecond)
+	require.ErrorIs(t, err, wait.ErrTimeLimitExceeded)
+}
+
+func TestWaitFor_disabled(t *testing.T) {
+	err := WaitFor("127.0.0.1:0", "token", "keyAuth", 0, time.Second)
+	require.NoError(t, err)
+}
@@ -6,18 +6,29 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/go-acme/lego/v4/challenge/http01"
 )
 
+// invalidationTimeout the maximum duration to wait for the completion of a CloudFront invalidation.
+const invalidationTimeout = 5 * time.Minute
+
 // HTTPProvider implements ChallengeProvider for `http-01` challenge.
 type HTTPProvider struct {
 	bucket string
 	client *s3.Client
+
+	// the ID of the CloudFront distribution serving the bucket.
+	distributionID string
+	cloudfront     *cloudfront.Client
 }
 
 // NewHTTPProvider returns a HTTPProvider instance with a configured s3 bucket and aws session.
@@ -37,27 +48,49 @@ func NewHTTPProvider(bucket string) (*HTTPProvider, error) {
 	client := s3.NewFromConfig(cfg)
 
 	return &HTTPProvider{
-		bucket: bucket,
-		client: client,
+		bucket:     bucket,
+		client:     client,
+		cloudfront: cloudfront.NewFromConfig(cfg),
 	}, nil
 }
 
+// SetCloudFrontDistribution defines the CloudFront distribution serving the bucket:
+// the challenge file is invalidated in the cache of the distribution after the upload,
+// and the file is not public (the bucket is only accessed through the distribution).
+func (s *HTTPProvider) SetCloudFrontDistribution(id string) {
+	s.distributionID = id
+}
+
 // Present makes the token available at `HTTP01ChallengePath(token)` by creating a file in the given s3 bucket.
 func (s *HTTPProvider) Present(domain, token, keyAuth string) error {
 	ctx := context.Background()
 
+	key := strings.Trim(http01.ChallengePath(token), "/")
+
 	params := &s3.PutObjectInput{
-		ACL:    "public-read",
 		Bucket: aws.String(s.bucket),
-		Key:    aws.String(strings.Trim(http01.ChallengePath(token), "/")),
+		Key:    aws.String(key),
 		Body:   bytes.NewReader([]byte(keyAuth)),
 	}
 
+	if s.distributionID == "" {
+		params.ACL = "public-read"
+	}
+
 	_, err := s.client.PutObject(ctx, params)
 	if err != nil {
 		return fmt.Errorf("s3: failed to upload token to s3: %w", err)
 	}
 
+	if s.distributionID == "" {
+		return nil
+	}
+
+	err = s.invalidate(ctx, "/"+key)
+	if err != nil {
+		return fmt.Errorf("s3: %w", err)
+	}
+
 	return nil
 }
 
@@ -77,3 +110,39 @@ func (s *HTTPProvider) CleanUp(domain, token, keyAuth string) error {
 
 	return nil
 }
+
+// invalidate removes a path from the cache of the CloudFront distribution (ex: a 404 response cached before the upload),
+// and waits for the completion of the invalidation.
+func (s *HTTPProvider) invalidate(ctx context.Context, path string) error {
+	output, err := s.cloudfront.CreateInvalidation(ctx, &cloudfront.CreateInvalidationInput{
+		DistributionId: aws.String(s.distributionID),
+		InvalidationBatch: &cftypes.InvalidationBatch{
+			CallerReference: aws.String("lego-" + strconv.FormatInt(time.Now().UnixNano(), 10)),
+			Paths: &cftypes.Paths{
+				Quantity: aws.Int32(1),
+				Items:    []string{path},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to invalidate the CloudFront cache: %w", err)
+	}
+
+	if output.Invalidation == nil {
+		return errors.New("failed to invalidate the CloudFront cache: missing invalidation")
+	}
+
+	waiter := cloudfront.NewInvalidationCompletedWaiter(s.cloudfront, func(o *cloudfront.InvalidationCompletedWaiterOptions) {
+		o.MinDelay = 5 * time.Second
+	})
+
+	err = waiter.Wait(ctx, &cloudfront.GetInvalidationInput{
+		DistributionId: aws.String(s.distributionID),
+		Id:             output.Invalidation.Id,
+	}, invalidationTimeout)
+	if err != nil {
+		return fmt.Errorf("CloudFront invalidation %s: %w", aws.ToString(output.Invalidation.Id), err)
+	}
+
+	return nil
+}
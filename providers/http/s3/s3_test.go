@@ -4,11 +4,17 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/go-acme/lego/v4/challenge/http01"
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/go-acme/lego/v4/platform/tester/servermock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -76,3 +82,62 @@ func TestLiveNewHTTPProvider(t *testing.T) {
 
 	assert.Equal(t, 403, cleanupResp.StatusCode)
 }
+
+func TestHTTPProvider_Present_cloudFront(t *testing.T) {
+	provider := servermock.NewBuilder(
+		func(server *httptest.Server) (*HTTPProvider, error) {
+			cfg := aws.Config{
+				HTTPClient:       server.Client(),
+				Credentials:      credentials.NewStaticCredentialsProvider("abc", "123", " "),
+				Region:           "mock-region",
+				BaseEndpoint:     aws.String(server.URL),
+				RetryMaxAttempts: 1,
+			}
+
+			p := &HTTPProvider{
+				bucket: "mybucket",
+				client: s3.NewFromConfig(cfg, func(o *s3.Options) {
+					o.UsePathStyle = true
+				}),
+				cloudfront: cloudfront.NewFromConfig(cfg),
+			}
+
+			p.SetCloudFrontDistribution("EDFDVBD6EXAMPLE")
+
+			return p, nil
+		},
+	).
+		Route("PUT /mybucket/.well-known/acme-challenge/foo",
+			servermock.Noop(),
+			servermock.LinkFunc(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+					if req.Header.Get("X-Amz-Acl") != "" {
+						http.Error(rw, "unexpected ACL", http.StatusBadRequest)
+						return
+					}
+
+					next.ServeHTTP(rw, req)
+				})
+			})).
+		Route("POST /2020-05-31/distribution/EDFDVBD6EXAMPLE/invalidation",
+			servermock.RawStringResponse(`<?xml version="1.0" encoding="UTF-8"?>
+<Invalidation xmlns="http://cloudfront.amazonaws.com/doc/2020-05-31/">
+  <Id>I2J0I21PCUYOIK</Id>
+  <Status>InProgress</Status>
+  <CreateTime>2025-01-01T00:00:00Z</CreateTime>
+</Invalidation>`).
+				WithStatusCode(http.StatusCreated).
+				WithHeader("Content-Type", "application/xml")).
+		Route("GET /2020-05-31/distribution/EDFDVBD6EXAMPLE/invalidation/I2J0I21PCUYOIK",
+			servermock.RawStringResponse(`<?xml version="1.0" encoding="UTF-8"?>
+<Invalidation xmlns="http://cloudfront.amazonaws.com/doc/2020-05-31/">
+  <Id>I2J0I21PCUYOIK</Id>
+  <Status>Completed</Status>
+  <CreateTime>2025-01-01T00:00:00Z</CreateTime>
+</Invalidation>`).
+				WithHeader("Content-Type", "application/xml")).
+		Build(t)
+
+	err := provider.Present(domain, token, keyAuth)
+	require.NoError(t, err)
+}
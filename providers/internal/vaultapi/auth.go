@@ -0,0 +1,100 @@
+package vaultapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Auth methods.
+const (
+	AuthToken      = "token"
+	AuthAppRole    = "approle"
+	AuthKubernetes = "kubernetes"
+)
+
+// Auth the credentials of an auth method.
+type Auth struct {
+	// Method the auth method: token, approle, or kubernetes.
+	Method string
+	// Mount the mount path of the auth method (default: the name of the auth method).
+	Mount string
+
+	Token string
+
+	RoleID   string
+	SecretID string
+
+	KubernetesRole      string
+	KubernetesTokenPath string
+}
+
+// Authenticate authenticates the client with the auth method.
+func (c *Client) Authenticate(ctx context.Context, auth Auth) error {
+	mount := auth.Mount
+	if mount == "" {
+		mount = auth.Method
+	}
+
+	var payload map[string]string
+
+	switch auth.Method {
+	case AuthToken:
+		if auth.Token == "" {
+			return errors.New("the token is required")
+		}
+
+		c.SetToken(auth.Token)
+
+		return nil
+
+	case AuthAppRole:
+		if auth.RoleID == "" {
+			return errors.New("the AppRole role ID is required")
+		}
+
+		payload = map[string]string{"role_id": auth.RoleID, "secret_id": auth.SecretID}
+
+	case AuthKubernetes:
+		if auth.KubernetesRole == "" {
+			return errors.New("the Kubernetes role is required")
+		}
+
+		// The service account token is read for each login: the token is rotated by Kubernetes.
+		jwt, err := os.ReadFile(auth.KubernetesTokenPath)
+		if err != nil {
+			return fmt.Errorf("read the service account token: %w", err)
+		}
+
+		payload = map[string]string{"role": auth.KubernetesRole, "jwt": strings.TrimSpace(string(jwt))}
+
+	default:
+		return fmt.Errorf("unsupported auth method: %q", auth.Method)
+	}
+
+	token, err := c.Login(ctx, mount, payload)
+	if err != nil {
+		return fmt.Errorf("login (%s): %w", auth.Method, err)
+	}
+
+	c.SetToken(token)
+
+	return nil
+}
+
+// IsPermissionDenied reports whether the error is a permission denied error (ex: the token has expired).
+func IsPermissionDenied(err error) bool {
+	var errAPI *APIError
+
+	return errors.As(err, &errAPI) && errAPI.StatusCode == http.StatusForbidden
+}
+
+// IsNotFound reports whether the error is a not found error (ex: a missing secret).
+func IsNotFound(err error) bool {
+	var errAPI *APIError
+
+	return errors.As(err, &errAPI) && errAPI.StatusCode == http.StatusNotFound
+}
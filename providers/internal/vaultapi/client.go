@@ -0,0 +1,274 @@
+package vaultapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	tokenHeader     = "X-Vault-Token"
+	namespaceHeader = "X-Vault-Namespace"
+)
+
+// Client the Vault API client.
+type Client struct {
+	namespace string
+
+	muToken sync.RWMutex
+	token   string
+
+	baseURL    *url.URL
+	HTTPClient *http.Client
+}
+
+// NewClient creates a new Client.
+func NewClient(address, namespace string) (*Client, error) {
+	if address == "" {
+		return nil, errors.New("missing address")
+	}
+
+	baseURL, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+
+	return &Client{
+		namespace:  namespace,
+		baseURL:    baseURL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// SetToken sets the token used to authenticate the requests.
+func (c *Client) SetToken(token string) {
+	c.muToken.Lock()
+	defer c.muToken.Unlock()
+
+	c.token = token
+}
+
+func (c *Client) getToken() string {
+	c.muToken.RLock()
+	defer c.muToken.RUnlock()
+
+	return c.token
+}
+
+// Login authenticates with an auth method, and returns the client token.
+// https://developer.hashicorp.com/vault/api-docs/auth/approle#login-with-approle
+// https://developer.hashicorp.com/vault/api-docs/auth/kubernetes#login
+func (c *Client) Login(ctx context.Context, mount string, payload any) (string, error) {
+	endpoint := c.baseURL.JoinPath("v1", "auth", mount, "login")
+
+	req, err := newJSONRequest(ctx, http.MethodPost, endpoint, payload)
+	if err != nil {
+		return "", err
+	}
+
+	result := &LoginResponse{}
+
+	err = c.do(req, false, result)
+	if err != nil {
+		return "", err
+	}
+
+	if result.Auth.ClientToken == "" {
+		return "", errors.New("login: missing client token")
+	}
+
+	return result.Auth.ClientToken, nil
+}
+
+// ReadKey reads a transit key.
+// https://developer.hashicorp.com/vault/api-docs/secret/transit#read-key
+func (c *Client) ReadKey(ctx context.Context, mount, name string) (*Key, error) {
+	endpoint := c.baseURL.JoinPath("v1", mount, "keys", name)
+
+	req, err := newJSONRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &KeyResponse{}
+
+	err = c.do(req, true, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result.Data, nil
+}
+
+// Sign signs data with a transit key, and returns the signature (`vault:v<version>:<base64>`).
+// https://developer.hashicorp.com/vault/api-docs/secret/transit#sign-data
+func (c *Client) Sign(ctx context.Context, mount, name string, payload SignRequest) (string, error) {
+	endpoint := c.baseURL.JoinPath("v1", mount, "sign", name)
+
+	req, err := newJSONRequest(ctx, http.MethodPost, endpoint, payload)
+	if err != nil {
+		return "", err
+	}
+
+	result := &SignResponse{}
+
+	err = c.do(req, true, result)
+	if err != nil {
+		return "", err
+	}
+
+	return result.Data.Signature, nil
+}
+
+// ReadSecret reads the latest version of a KV v2 secret.
+// https://developer.hashicorp.com/vault/api-docs/secret/kv/kv-v2#read-secret-version
+func (c *Client) ReadSecret(ctx context.Context, mount, path string) (map[string]string, error) {
+	endpoint := c.baseURL.JoinPath("v1", mount, "data", path)
+
+	req, err := newJSONRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SecretResponse{}
+
+	err = c.do(req, true, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Data.Data, nil
+}
+
+// WriteSecret creates a new version of a KV v2 secret.
+// https://developer.hashicorp.com/vault/api-docs/secret/kv/kv-v2#create-update-secret
+func (c *Client) WriteSecret(ctx context.Context, mount, path string, data map[string]string) error {
+	endpoint := c.baseURL.JoinPath("v1", mount, "data", path)
+
+	req, err := newJSONRequest(ctx, http.MethodPost, endpoint, SecretRequest{Data: data})
+	if err != nil {
+		return err
+	}
+
+	return c.do(req, true, nil)
+}
+
+// DeleteSecret deletes all the versions and the metadata of a KV v2 secret.
+// https://developer.hashicorp.com/vault/api-docs/secret/kv/kv-v2#delete-metadata-and-all-versions
+func (c *Client) DeleteSecret(ctx context.Context, mount, path string) error {
+	endpoint := c.baseURL.JoinPath("v1", mount, "metadata", path)
+
+	req, err := newJSONRequest(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.do(req, true, nil)
+}
+
+// ListSecrets lists the keys at a path of a KV v2 secrets engine.
+// The names of the folders end with a `/`.
+// https://developer.hashicorp.com/vault/api-docs/secret/kv/kv-v2#list-secrets
+func (c *Client) ListSecrets(ctx context.Context, mount, path string) ([]string, error) {
+	endpoint := c.baseURL.JoinPath("v1", mount, "metadata", path)
+
+	query := endpoint.Query()
+	query.Set("list", "true")
+	endpoint.RawQuery = query.Encode()
+
+	req, err := newJSONRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ListResponse{}
+
+	err = c.do(req, true, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Data.Keys, nil
+}
+
+func (c *Client) do(req *http.Request, authenticated bool, result any) error {
+	if authenticated {
+		req.Header.Set(tokenHeader, c.getToken())
+	}
+
+	if c.namespace != "" {
+		req.Header.Set(namespaceHeader, c.namespace)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to communicate with the API server: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("unable to read response body: %w", err)
+	}
+
+	if resp.StatusCode/100 != 2 {
+		return parseError(req, resp.StatusCode, raw)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	err = json.Unmarshal(raw, result)
+	if err != nil {
+		return fmt.Errorf("unable to unmarshal response: [status code: %d] body: %s, error: %w", resp.StatusCode, string(raw), err)
+	}
+
+	return nil
+}
+
+func newJSONRequest(ctx context.Context, method string, endpoint *url.URL, payload any) (*http.Request, error) {
+	buf := new(bytes.Buffer)
+
+	if payload != nil {
+		err := json.NewEncoder(buf).Encode(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request JSON body: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), buf)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
+
+func parseError(req *http.Request, statusCode int, raw []byte) error {
+	errAPI := &APIError{StatusCode: statusCode}
+
+	err := json.Unmarshal(raw, errAPI)
+	if err != nil || len(errAPI.Errors) == 0 {
+		errAPI.Errors = []string{strings.TrimSpace(string(raw))}
+
+		return fmt.Errorf("unexpected status code: [status code: %d] %s %s: %w", statusCode, req.Method, req.URL.Path, errAPI)
+	}
+
+	return fmt.Errorf("[status code: %d] %w", statusCode, errAPI)
+}
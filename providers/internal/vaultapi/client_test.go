@@ -0,0 +1,186 @@
+package vaultapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-acme/lego/v4/platform/tester/servermock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupClient(server *httptest.Server) (*Client, error) {
+	client, err := NewClient(server.URL, "ns1")
+	if err != nil {
+		return nil, err
+	}
+
+	client.SetToken("secret")
+	client.HTTPClient = server.Client()
+
+	return client, nil
+}
+
+func TestClient_Login(t *testing.T) {
+	client := servermock.NewBuilder[*Client](setupClient,
+		servermock.CheckHeader().
+			WithJSONHeaders().
+			With(namespaceHeader, "ns1")).
+		Route("POST /v1/auth/approle/login",
+			servermock.RawStringResponse(`{"auth":{"client_token":"s.token","lease_duration":3600,"renewable":true}}`),
+			servermock.CheckRequestJSONBody(`{"role_id":"role","secret_id":"secret-id"}`)).
+		Build(t)
+
+	token, err := client.Login(t.Context(), "approle", map[string]string{"role_id": "role", "secret_id": "secret-id"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "s.token", token)
+}
+
+func TestClient_Login_error(t *testing.T) {
+	client := servermock.NewBuilder[*Client](setupClient).
+		Route("POST /v1/auth/approle/login",
+			servermock.RawStringResponse(`{"errors":["invalid role or secret ID"]}`).
+				WithStatusCode(http.StatusBadRequest)).
+		Build(t)
+
+	_, err := client.Login(t.Context(), "approle", map[string]string{"role_id": "role", "secret_id": "secret-id"})
+	require.EqualError(t, err, "[status code: 400] invalid role or secret ID")
+}
+
+func TestClient_ReadKey(t *testing.T) {
+	client := servermock.NewBuilder[*Client](setupClient,
+		servermock.CheckHeader().
+			With(tokenHeader, "secret").
+			With(namespaceHeader, "ns1")).
+		Route("GET /v1/transit/keys/acme",
+			servermock.RawStringResponse(`{"data":{"name":"acme","type":"ecdsa-p256","latest_version":2,"keys":{"1":{"name":"P-256","public_key":"pem1"},"2":{"name":"P-256","public_key":"pem2"}}}}`)).
+		Build(t)
+
+	key, err := client.ReadKey(t.Context(), "transit", "acme")
+	require.NoError(t, err)
+
+	assert.Equal(t, "acme", key.Name)
+	assert.Equal(t, "ecdsa-p256", key.Type)
+	assert.Equal(t, 2, key.LatestVersion)
+	assert.Len(t, key.Keys, 2)
+}
+
+func TestClient_ReadKey_error(t *testing.T) {
+	client := servermock.NewBuilder[*Client](setupClient).
+		Route("GET /v1/transit/keys/acme",
+			servermock.RawStringResponse(`{"errors":["permission denied"]}`).
+				WithStatusCode(http.StatusForbidden)).
+		Build(t)
+
+	_, err := client.ReadKey(t.Context(), "transit", "acme")
+	require.EqualError(t, err, "[status code: 403] permission denied")
+
+	var errAPI *APIError
+	require.ErrorAs(t, err, &errAPI)
+
+	assert.Equal(t, http.StatusForbidden, errAPI.StatusCode)
+}
+
+func TestClient_Sign(t *testing.T) {
+	client := servermock.NewBuilder[*Client](setupClient,
+		servermock.CheckHeader().
+			WithJSONHeaders().
+			With(tokenHeader, "secret")).
+		Route("POST /v1/transit/sign/acme",
+			servermock.RawStringResponse(`{"data":{"signature":"vault:v2:c2lnbmF0dXJl","key_version":2}}`),
+			servermock.CheckRequestJSONBody(`{"input":"ZGlnZXN0","prehashed":true,"hash_algorithm":"sha2-256","marshaling_algorithm":"asn1","key_version":2}`)).
+		Build(t)
+
+	signature, err := client.Sign(t.Context(), "transit", "acme", SignRequest{
+		Input:               "ZGlnZXN0",
+		Prehashed:           true,
+		HashAlgorithm:       "sha2-256",
+		MarshalingAlgorithm: "asn1",
+		KeyVersion:          2,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "vault:v2:c2lnbmF0dXJl", signature)
+}
+
+func TestClient_Sign_error(t *testing.T) {
+	client := servermock.NewBuilder[*Client](setupClient).
+		Route("POST /v1/transit/sign/acme",
+			servermock.RawStringResponse(`internal error`).
+				WithStatusCode(http.StatusInternalServerError)).
+		Build(t)
+
+	_, err := client.Sign(t.Context(), "transit", "acme", SignRequest{Input: "ZGlnZXN0"})
+	require.EqualError(t, err, "unexpected status code: [status code: 500] POST /v1/transit/sign/acme: internal error")
+}
+
+func TestClient_ReadSecret(t *testing.T) {
+	client := servermock.NewBuilder[*Client](setupClient,
+		servermock.CheckHeader().
+			With(tokenHeader, "secret")).
+		Route("GET /v1/secret/data/lego/accounts/account.json",
+			servermock.RawStringResponse(`{"data":{"data":{"content":"e30="},"metadata":{"version":1}}}`)).
+		Build(t)
+
+	data, err := client.ReadSecret(t.Context(), "secret", "lego/accounts/account.json")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"content": "e30="}, data)
+}
+
+func TestClient_ReadSecret_notFound(t *testing.T) {
+	client := servermock.NewBuilder[*Client](setupClient).
+		Route("GET /v1/secret/data/lego/account.json",
+			servermock.RawStringResponse(`{"errors":[]}`).
+				WithStatusCode(http.StatusNotFound)).
+		Build(t)
+
+	_, err := client.ReadSecret(t.Context(), "secret", "lego/account.json")
+	require.Error(t, err)
+
+	assert.True(t, IsNotFound(err))
+}
+
+func TestClient_WriteSecret(t *testing.T) {
+	client := servermock.NewBuilder[*Client](setupClient,
+		servermock.CheckHeader().
+			WithJSONHeaders().
+			With(tokenHeader, "secret")).
+		Route("POST /v1/secret/data/lego/account.json",
+			servermock.RawStringResponse(`{"data":{"version":2}}`),
+			servermock.CheckRequestJSONBody(`{"data":{"content":"e30="}}`)).
+		Build(t)
+
+	err := client.WriteSecret(t.Context(), "secret", "lego/account.json", map[string]string{"content": "e30="})
+	require.NoError(t, err)
+}
+
+func TestClient_DeleteSecret(t *testing.T) {
+	client := servermock.NewBuilder[*Client](setupClient,
+		servermock.CheckHeader().
+			With(tokenHeader, "secret")).
+		Route("DELETE /v1/secret/metadata/lego/account.json",
+			servermock.Noop().WithStatusCode(http.StatusNoContent)).
+		Build(t)
+
+	err := client.DeleteSecret(t.Context(), "secret", "lego/account.json")
+	require.NoError(t, err)
+}
+
+func TestClient_ListSecrets(t *testing.T) {
+	client := servermock.NewBuilder[*Client](setupClient,
+		servermock.CheckHeader().
+			With(tokenHeader, "secret")).
+		Route("GET /v1/secret/metadata/lego",
+			servermock.RawStringResponse(`{"data":{"keys":["accounts/","certificates/"]}}`),
+			servermock.CheckQueryParameter().Strict().
+				With("list", "true")).
+		Build(t)
+
+	keys, err := client.ListSecrets(t.Context(), "secret", "lego")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"accounts/", "certificates/"}, keys)
+}
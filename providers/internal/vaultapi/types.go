@@ -0,0 +1,84 @@
+package vaultapi
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// SignRequest the request body of the transit sign endpoint.
+type SignRequest struct {
+	Input               string `json:"input"`
+	Prehashed           bool   `json:"prehashed"`
+	HashAlgorithm       string `json:"hash_algorithm,omitempty"`
+	SignatureAlgorithm  string `json:"signature_algorithm,omitempty"`
+	MarshalingAlgorithm string `json:"marshaling_algorithm,omitempty"`
+	KeyVersion          int    `json:"key_version,omitempty"`
+}
+
+// SignResponse the response of the transit sign endpoint.
+type SignResponse struct {
+	Data struct {
+		Signature  string `json:"signature"`
+		KeyVersion int    `json:"key_version"`
+	} `json:"data"`
+}
+
+// KeyResponse the response of the transit read key endpoint.
+type KeyResponse struct {
+	Data Key `json:"data"`
+}
+
+// Key a transit key.
+type Key struct {
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	LatestVersion int    `json:"latest_version"`
+
+	// Keys the versions of the key:
+	// the values are objects for the asymmetric keys, and timestamps for the symmetric keys.
+	Keys map[string]json.RawMessage `json:"keys"`
+}
+
+// KeyVersion a version of an asymmetric transit key.
+type KeyVersion struct {
+	Name      string `json:"name"`
+	PublicKey string `json:"public_key"`
+}
+
+// LoginResponse the response of the login endpoints of the auth methods.
+type LoginResponse struct {
+	Auth struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int    `json:"lease_duration"`
+		Renewable     bool   `json:"renewable"`
+	} `json:"auth"`
+}
+
+// SecretRequest the request body of the KV v2 create/update endpoint.
+type SecretRequest struct {
+	Data map[string]string `json:"data"`
+}
+
+// SecretResponse the response of the KV v2 read endpoint.
+type SecretResponse struct {
+	Data struct {
+		Data map[string]string `json:"data"`
+	} `json:"data"`
+}
+
+// ListResponse the response of the KV v2 list endpoint.
+type ListResponse struct {
+	Data struct {
+		Keys []string `json:"keys"`
+	} `json:"data"`
+}
+
+// APIError an error returned by the Vault API.
+type APIError struct {
+	StatusCode int      `json:"-"`
+	Errors     []string `json:"errors"`
+}
+
+func (a *APIError) Error() string {
+	return strings.Join(a.Errors, ", ")
+}
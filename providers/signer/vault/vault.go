@@ -0,0 +1,306 @@
+// Package vault implements a signer for the ACME account key using the transit secrets engine of HashiCorp Vault.
+// The private key never leaves Vault: the requests to the ACME server are signed by the sign endpoint of the engine.
+package vault
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/platform/config/env"
+	"github.com/go-acme/lego/v4/providers/internal/vaultapi"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "VAULT_"
+
+	EnvAddress   = envNamespace + "ADDR"
+	EnvNamespace = envNamespace + "NAMESPACE"
+
+	EnvAuthMethod          = envNamespace + "AUTH_METHOD"
+	EnvAuthMount           = envNamespace + "AUTH_MOUNT"
+	EnvToken               = envNamespace + "TOKEN"
+	EnvAppRoleRoleID       = envNamespace + "APPROLE_ROLE_ID"
+	EnvAppRoleSecretID     = envNamespace + "APPROLE_SECRET_ID"
+	EnvKubernetesRole      = envNamespace + "KUBERNETES_ROLE"
+	EnvKubernetesTokenPath = envNamespace + "KUBERNETES_TOKEN_PATH"
+
+	EnvTransitMount      = envNamespace + "TRANSIT_MOUNT"
+	EnvTransitKey        = envNamespace + "TRANSIT_KEY"
+	EnvTransitKeyVersion = envNamespace + "TRANSIT_KEY_VERSION"
+
+	EnvHTTPTimeout = envNamespace + "HTTP_TIMEOUT"
+)
+
+// Auth methods.
+const (
+	AuthToken      = vaultapi.AuthToken
+	AuthAppRole    = vaultapi.AuthAppRole
+	AuthKubernetes = vaultapi.AuthKubernetes
+)
+
+const defaultKubernetesTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// Config is used to configure the creation of the Signer.
+type Config struct {
+	Address   string
+	Namespace string
+
+	// AuthMethod the auth method: token, approle, or kubernetes.
+	AuthMethod string
+	// AuthMount the mount path of the auth method (default: the name of the auth method).
+	AuthMount string
+
+	Token string
+
+	RoleID   string
+	SecretID string
+
+	KubernetesRole      string
+	KubernetesTokenPath string
+
+	TransitMount string
+	KeyName      string
+	// KeyVersion the version of the transit key (0: the latest version).
+	KeyVersion int
+
+	HTTPClient *http.Client
+}
+
+// NewDefaultConfig returns a default configuration for the Signer.
+func NewDefaultConfig() *Config {
+	return &Config{
+		AuthMethod:          env.GetOrDefaultString(EnvAuthMethod, AuthToken),
+		KubernetesTokenPath: env.GetOrDefaultString(EnvKubernetesTokenPath, defaultKubernetesTokenPath),
+		TransitMount:        env.GetOrDefaultString(EnvTransitMount, "transit"),
+		KeyVersion:          env.GetOrDefaultInt(EnvTransitKeyVersion, 0),
+		HTTPClient: &http.Client{
+			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+		},
+	}
+}
+
+// Signer implements crypto.Signer with a key of the transit secrets engine.
+// Only the RSA keys and the ECDSA P-256 and P-384 keys are supported (the algorithms allowed by the ACME servers).
+type Signer struct {
+	config *Config
+	client *vaultapi.Client
+
+	publicKey crypto.PublicKey
+	version   int
+}
+
+// NewSigner returns a Signer instance configured for Vault.
+// The configuration must be passed in the environment variables:
+// VAULT_ADDR, VAULT_TRANSIT_KEY, and the credentials of the auth method
+// (VAULT_TOKEN, VAULT_APPROLE_ROLE_ID and VAULT_APPROLE_SECRET_ID, or VAULT_KUBERNETES_ROLE).
+func NewSigner() (*Signer, error) {
+	values, err := env.Get(EnvAddress, EnvTransitKey)
+	if err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+
+	config := NewDefaultConfig()
+	config.Address = values[EnvAddress]
+	config.KeyName = values[EnvTransitKey]
+	config.Namespace = env.GetOrFile(EnvNamespace)
+	config.AuthMount = env.GetOrFile(EnvAuthMount)
+	config.Token = env.GetOrFile(EnvToken)
+	config.RoleID = env.GetOrFile(EnvAppRoleRoleID)
+	config.SecretID = env.GetOrFile(EnvAppRoleSecretID)
+	config.KubernetesRole = env.GetOrFile(EnvKubernetesRole)
+
+	return NewSignerConfig(config)
+}
+
+// NewSignerConfig return a Signer instance configured for Vault.
+// It authenticates with the auth method, and reads the public key of the transit key.
+func NewSignerConfig(config *Config) (*Signer, error) {
+	if config == nil {
+		return nil, errors.New("vault: the configuration of the signer is nil")
+	}
+
+	if config.KeyName == "" {
+		return nil, errors.New("vault: the transit key name is required")
+	}
+
+	client, err := vaultapi.NewClient(config.Address, config.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+
+	if config.HTTPClient != nil {
+		client.HTTPClient = config.HTTPClient
+	}
+
+	s := &Signer{config: config, client: client}
+
+	ctx := context.Background()
+
+	err = s.login(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+
+	err = s.loadPublicKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+
+	return s, nil
+}
+
+// Public returns the public key of the transit key.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+// Sign signs the digest with the transit key.
+// The random source is ignored: the signature is computed by Vault.
+func (s *Signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	hashAlgorithm, err := toHashAlgorithm(opts.HashFunc())
+	if err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+
+	request := vaultapi.SignRequest{
+		Input:         base64.StdEncoding.EncodeToString(digest),
+		Prehashed:     true,
+		HashAlgorithm: hashAlgorithm,
+		KeyVersion:    s.version,
+	}
+
+	switch s.publicKey.(type) {
+	case *rsa.PublicKey:
+		request.SignatureAlgorithm = "pkcs1v15"
+		if _, ok := opts.(*rsa.PSSOptions); ok {
+			request.SignatureAlgorithm = "pss"
+		}
+
+	case *ecdsa.PublicKey:
+		request.MarshalingAlgorithm = "asn1"
+	}
+
+	ctx := context.Background()
+
+	signature, err := s.client.Sign(ctx, s.config.TransitMount, s.config.KeyName, request)
+	if err != nil && vaultapi.IsPermissionDenied(err) && s.config.AuthMethod != AuthToken {
+		// The token may have expired: authenticates again.
+		err = s.login(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("vault: %w", err)
+		}
+
+		signature, err = s.client.Sign(ctx, s.config.TransitMount, s.config.KeyName, request)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("vault: sign: %w", err)
+	}
+
+	raw, err := decodeSignature(signature)
+	if err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+
+	return raw, nil
+}
+
+func (s *Signer) login(ctx context.Context) error {
+	return s.client.Authenticate(ctx, vaultapi.Auth{
+		Method:              s.config.AuthMethod,
+		Mount:               s.config.AuthMount,
+		Token:               s.config.Token,
+		RoleID:              s.config.RoleID,
+		SecretID:            s.config.SecretID,
+		KubernetesRole:      s.config.KubernetesRole,
+		KubernetesTokenPath: s.config.KubernetesTokenPath,
+	})
+}
+
+func (s *Signer) loadPublicKey(ctx context.Context) error {
+	key, err := s.client.ReadKey(ctx, s.config.TransitMount, s.config.KeyName)
+	if err != nil {
+		return fmt.Errorf("read key: %w", err)
+	}
+
+	switch key.Type {
+	case "rsa-2048", "rsa-3072", "rsa-4096", "ecdsa-p256", "ecdsa-p384":
+	default:
+		return fmt.Errorf("unsupported key type: %q", key.Type)
+	}
+
+	// The version is pinned: the signatures must match the public key, even if the key is rotated.
+	version := s.config.KeyVersion
+	if version == 0 {
+		version = key.LatestVersion
+	}
+
+	raw, ok := key.Keys[strconv.Itoa(version)]
+	if !ok {
+		return fmt.Errorf("the version %d of the key %q doesn't exist", version, s.config.KeyName)
+	}
+
+	var keyVersion vaultapi.KeyVersion
+
+	err = json.Unmarshal(raw, &keyVersion)
+	if err != nil {
+		return fmt.Errorf("unable to unmarshal the version %d of the key %q: %w", version, s.config.KeyName, err)
+	}
+
+	block, _ := pem.Decode([]byte(keyVersion.PublicKey))
+	if block == nil {
+		return fmt.Errorf("invalid public key for the version %d of the key %q", version, s.config.KeyName)
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("parse the public key: %w", err)
+	}
+
+	s.publicKey = publicKey
+	s.version = version
+
+	return nil
+}
+
+func toHashAlgorithm(h crypto.Hash) (string, error) {
+	switch h {
+	case crypto.SHA256:
+		return "sha2-256", nil
+	case crypto.SHA384:
+		return "sha2-384", nil
+	case crypto.SHA512:
+		return "sha2-512", nil
+	default:
+		return "", fmt.Errorf("unsupported hash function: %s", h)
+	}
+}
+
+// decodeSignature decodes a signature of the transit engine (`vault:v<version>:<base64>`).
+func decodeSignature(signature string) ([]byte, error) {
+	parts := strings.SplitN(signature, ":", 3)
+	if len(parts) != 3 || parts[0] != "vault" {
+		return nil, fmt.Errorf("invalid signature format: %q", signature)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("decode signature: %w", err)
+	}
+
+	return raw, nil
+}
@@ -0,0 +1,317 @@
+package vault
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/go-acme/lego/v4/platform/tester/servermock"
+	"github.com/go-acme/lego/v4/providers/internal/vaultapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var envTest = tester.NewEnvTest(
+	EnvAddress,
+	EnvNamespace,
+	EnvAuthMethod,
+	EnvAuthMount,
+	EnvToken,
+	EnvAppRoleRoleID,
+	EnvAppRoleSecretID,
+	EnvKubernetesRole,
+	EnvKubernetesTokenPath,
+	EnvTransitMount,
+	EnvTransitKey,
+	EnvTransitKeyVersion,
+)
+
+func TestNewSigner(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(readKeyHandler(t, privateKey, "ecdsa-p256"))
+	t.Cleanup(server.Close)
+
+	testCases := []struct {
+		desc     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			desc: "success",
+			envVars: map[string]string{
+				EnvAddress:    server.URL,
+				EnvToken:      "secret",
+				EnvTransitKey: "acme",
+			},
+		},
+		{
+			desc:     "missing configuration",
+			envVars:  map[string]string{},
+			expected: "vault: some credentials information are missing: VAULT_ADDR,VAULT_TRANSIT_KEY",
+		},
+		{
+			desc: "missing token",
+			envVars: map[string]string{
+				EnvAddress:    server.URL,
+				EnvTransitKey: "acme",
+			},
+			expected: "vault: the token is required",
+		},
+		{
+			desc: "unsupported auth method",
+			envVars: map[string]string{
+				EnvAddress:    server.URL,
+				EnvAuthMethod: "ldap",
+				EnvTransitKey: "acme",
+			},
+			expected: `vault: unsupported auth method: "ldap"`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			s, err := NewSigner()
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, s)
+				assert.Equal(t, privateKey.Public(), s.Public())
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewSignerConfig_unsupportedKeyType(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(readKeyHandler(t, privateKey, "aes256-gcm96"))
+	t.Cleanup(server.Close)
+
+	config := NewDefaultConfig()
+	config.Address = server.URL
+	config.AuthMethod = AuthToken
+	config.Token = "secret"
+	config.KeyName = "acme"
+
+	_, err = NewSignerConfig(config)
+	require.EqualError(t, err, `vault: unsupported key type: "aes256-gcm96"`)
+}
+
+func TestSigner_Sign_ecdsa(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	signer := servermock.NewBuilder[*Signer](setupSigner(AuthToken),
+		servermock.CheckHeader().
+			With("X-Vault-Token", "secret")).
+		Route("GET /v1/transit/keys/acme", readKeyHandler(t, privateKey, "ecdsa-p256")).
+		Route("POST /v1/transit/sign/acme", signHandler(t, privateKey)).
+		Build(t)
+
+	digest := sha256.Sum256([]byte("content"))
+
+	signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	require.NoError(t, err)
+
+	assert.True(t, ecdsa.VerifyASN1(&privateKey.PublicKey, digest[:], signature))
+}
+
+func TestSigner_Sign_rsa(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	signer := servermock.NewBuilder[*Signer](setupSigner(AuthToken)).
+		Route("GET /v1/transit/keys/acme", readKeyHandler(t, privateKey, "rsa-2048")).
+		Route("POST /v1/transit/sign/acme", signHandler(t, privateKey)).
+		Build(t)
+
+	digest := sha256.Sum256([]byte("content"))
+
+	signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	require.NoError(t, err)
+
+	require.NoError(t, rsa.VerifyPKCS1v15(&privateKey.PublicKey, crypto.SHA256, digest[:], signature))
+}
+
+func TestSigner_Sign_appRole(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var logins atomic.Int32
+
+	var denied atomic.Bool
+
+	signer := servermock.NewBuilder[*Signer](setupSigner(AuthAppRole)).
+		Route("POST /v1/auth/approle/login",
+			http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				logins.Add(1)
+
+				_, _ = rw.Write([]byte(`{"auth":{"client_token":"s.token"}}`))
+			}),
+			servermock.CheckRequestJSONBody(`{"role_id":"role","secret_id":"secret-id"}`)).
+		Route("GET /v1/transit/keys/acme", readKeyHandler(t, privateKey, "ecdsa-p256"),
+			servermock.CheckHeader().With("X-Vault-Token", "s.token")).
+		Route("POST /v1/transit/sign/acme",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				// The first call simulates an expired token.
+				if denied.CompareAndSwap(false, true) {
+					rw.WriteHeader(http.StatusForbidden)
+					_, _ = rw.Write([]byte(`{"errors":["permission denied"]}`))
+
+					return
+				}
+
+				signHandler(t, privateKey).ServeHTTP(rw, req)
+			})).
+		Build(t)
+
+	require.Equal(t, int32(1), logins.Load())
+
+	digest := sha256.Sum256([]byte("content"))
+
+	signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	require.NoError(t, err)
+
+	assert.True(t, ecdsa.VerifyASN1(&privateKey.PublicKey, digest[:], signature))
+	assert.Equal(t, int32(2), logins.Load())
+}
+
+func TestNewSignerConfig_kubernetes(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	tokenPath := filepath.Join(t.TempDir(), "token")
+
+	err = os.WriteFile(tokenPath, []byte("service-account-jwt\n"), 0o600)
+	require.NoError(t, err)
+
+	signer := servermock.NewBuilder[*Signer](
+		func(server *httptest.Server) (*Signer, error) {
+			config := NewDefaultConfig()
+			config.Address = server.URL
+			config.AuthMethod = AuthKubernetes
+			config.AuthMount = "k8s"
+			config.KubernetesRole = "lego"
+			config.KubernetesTokenPath = tokenPath
+			config.KeyName = "acme"
+			config.HTTPClient = server.Client()
+
+			return NewSignerConfig(config)
+		}).
+		Route("POST /v1/auth/k8s/login",
+			servermock.RawStringResponse(`{"auth":{"client_token":"s.token"}}`),
+			servermock.CheckRequestJSONBody(`{"jwt":"service-account-jwt","role":"lego"}`)).
+		Route("GET /v1/transit/keys/acme", readKeyHandler(t, privateKey, "ecdsa-p384"),
+			servermock.CheckHeader().With("X-Vault-Token", "s.token")).
+		Build(t)
+
+	assert.Equal(t, privateKey.Public(), signer.Public())
+}
+
+func Test_decodeSignature(t *testing.T) {
+	raw, err := decodeSignature("vault:v1:c2lnbmF0dXJl")
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte("signature"), raw)
+
+	_, err = decodeSignature("c2lnbmF0dXJl")
+	require.EqualError(t, err, `invalid signature format: "c2lnbmF0dXJl"`)
+}
+
+func setupSigner(authMethod string) servermock.ClientBuilder[*Signer] {
+	return func(server *httptest.Server) (*Signer, error) {
+		config := NewDefaultConfig()
+		config.Address = server.URL
+		config.AuthMethod = authMethod
+		config.Token = "secret"
+		config.RoleID = "role"
+		config.SecretID = "secret-id"
+		config.KeyName = "acme"
+		config.HTTPClient = server.Client()
+
+		return NewSignerConfig(config)
+	}
+}
+
+func readKeyHandler(t *testing.T, privateKey crypto.Signer, keyType string) http.HandlerFunc {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	require.NoError(t, err)
+
+	version := vaultapi.KeyVersion{
+		PublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+	}
+
+	raw, err := json.Marshal(version)
+	require.NoError(t, err)
+
+	return func(rw http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(rw).Encode(vaultapi.KeyResponse{
+			Data: vaultapi.Key{
+				Name:          "acme",
+				Type:          keyType,
+				LatestVersion: 1,
+				Keys:          map[string]json.RawMessage{"1": raw},
+			},
+		})
+	}
+}
+
+func signHandler(t *testing.T, privateKey crypto.Signer) http.HandlerFunc {
+	t.Helper()
+
+	return func(rw http.ResponseWriter, req *http.Request) {
+		var request vaultapi.SignRequest
+
+		err := json.NewDecoder(req.Body).Decode(&request)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if !request.Prehashed || request.HashAlgorithm != "sha2-256" || request.KeyVersion != 1 {
+			http.Error(rw, "unexpected request", http.StatusBadRequest)
+			return
+		}
+
+		digest, err := base64.StdEncoding.DecodeString(request.Input)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		signature, err := privateKey.Sign(rand.Reader, digest, crypto.SHA256)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		_, _ = rw.Write([]byte(`{"data":{"signature":"vault:v1:` + base64.StdEncoding.EncodeToString(signature) + `"}}`))
+	}
+}
@@ -0,0 +1,221 @@
+// Package s3 implements a storage backend using AWS S3 (or an S3-compatible service).
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/go-acme/lego/v4/platform/config/env"
+	"github.com/go-acme/lego/v4/platform/storage"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "LEGO_STORAGE_S3_"
+
+	EnvBucket       = envNamespace + "BUCKET"
+	EnvPrefix       = envNamespace + "PREFIX"
+	EnvEndpoint     = envNamespace + "ENDPOINT"
+	EnvUsePathStyle = envNamespace + "USE_PATH_STYLE"
+)
+
+// Config is used to configure the creation of the Backend.
+type Config struct {
+	Bucket string
+	// Prefix the prefix of the object keys (ex: `lego/`).
+	Prefix string
+	// Endpoint the endpoint of an S3-compatible service (ex: MinIO).
+	Endpoint string
+	// UsePathStyle uses the path-style addressing (`<endpoint>/<bucket>/<key>`).
+	UsePathStyle bool
+}
+
+// NewDefaultConfig returns a default configuration for the Backend.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Prefix:       env.GetOrDefaultString(EnvPrefix, ""),
+		Endpoint:     env.GetOrDefaultString(EnvEndpoint, ""),
+		UsePathStyle: env.GetOrDefaultBool(EnvUsePathStyle, false),
+	}
+}
+
+type s3API interface {
+	s3.ListObjectsV2APIClient
+
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+}
+
+var _ storage.Backend = (*Backend)(nil)
+
+// Backend stores the files as the objects of an S3 bucket.
+type Backend struct {
+	config *Config
+	client s3API
+}
+
+// NewBackend returns a Backend instance configured for S3.
+// The bucket name must be passed in the environment variable `LEGO_STORAGE_S3_BUCKET`.
+// The AWS credentials and region are read from the default locations (environment variables, shared configuration, etc.).
+func NewBackend() (*Backend, error) {
+	values, err := env.Get(EnvBucket)
+	if err != nil {
+		return nil, fmt.Errorf("s3: %w", err)
+	}
+
+	config := NewDefaultConfig()
+	config.Bucket = values[EnvBucket]
+
+	return NewBackendConfig(config)
+}
+
+// NewBackendConfig return a Backend instance configured for S3.
+func NewBackendConfig(config *Config) (*Backend, error) {
+	if config == nil {
+		return nil, errors.New("s3: the configuration of the storage backend is nil")
+	}
+
+	if config.Bucket == "" {
+		return nil, errors.New("s3: bucket name missing")
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("s3: unable to create AWS config: %w", err)
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if config.Endpoint != "" {
+			o.BaseEndpoint = aws.String(config.Endpoint)
+		}
+
+		o.UsePathStyle = config.UsePathStyle
+	})
+
+	return &Backend{config: config, client: client}, nil
+}
+
+// Save creates or replaces the object of a key.
+func (b *Backend) Save(ctx context.Context, key string, data []byte) error {
+	err := storage.ValidateKey(key)
+	if err != nil {
+		return fmt.Errorf("s3: %w", err)
+	}
+
+	_, err = b.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(b.config.Bucket),
+		Key:    aws.String(b.objectKey(key)),
+		Body:   bytes.NewReader(data),
+	})
+	if err != nil {
+		return fmt.Errorf("s3: put object %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// Load returns the content of the object of a key.
+func (b *Backend) Load(ctx context.Context, key string) ([]byte, error) {
+	err := storage.ValidateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("s3: %w", err)
+	}
+
+	resp, err := b.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(b.config.Bucket),
+		Key:    aws.String(b.objectKey(key)),
+	})
+	if err != nil {
+		var errNoSuchKey *types.NoSuchKey
+		if errors.As(err, &errNoSuchKey) {
+			return nil, fmt.Errorf("s3: %s: %w", key, storage.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("s3: get object %s: %w", key, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("s3: read object %s: %w", key, err)
+	}
+
+	return data, nil
+}
+
+// List returns the keys of the objects inside a folder, sorted.
+func (b *Backend) List(ctx context.Context, folder string) ([]string, error) {
+	err := storage.ValidateFolder(folder)
+	if err != nil {
+		return nil, fmt.Errorf("s3: %w", err)
+	}
+
+	prefix := b.config.Prefix
+	if folder != "" {
+		prefix = b.objectKey(folder) + "/"
+	}
+
+	paginator := s3.NewListObjectsV2Paginator(b.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(b.config.Bucket),
+		Prefix: aws.String(prefix),
+	})
+
+	var keys []string
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("s3: list objects: %w", err)
+		}
+
+		for _, object := range page.Contents {
+			key := strings.TrimPrefix(aws.ToString(object.Key), b.config.Prefix)
+
+			// Ignores the objects not created by lego (ex: folder markers).
+			if storage.ValidateKey(key) != nil {
+				continue
+			}
+
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+
+	return keys, nil
+}
+
+// Delete deletes the object of a key.
+func (b *Backend) Delete(ctx context.Context, key string) error {
+	err := storage.ValidateKey(key)
+	if err != nil {
+		return fmt.Errorf("s3: %w", err)
+	}
+
+	_, err = b.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(b.config.Bucket),
+		Key:    aws.String(b.objectKey(key)),
+	})
+	if err != nil {
+		return fmt.Errorf("s3: delete object %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// objectKey returns the key of the object.
+// The prefix is used as is: `lego/` is a folder, `lego-` is a prefix of the object names.
+func (b *Backend) objectKey(key string) string {
+	return b.config.Prefix + key
+}
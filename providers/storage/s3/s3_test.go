@@ -0,0 +1,160 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/go-acme/lego/v4/platform/storage"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var envTest = tester.NewEnvTest(EnvBucket, EnvPrefix, EnvEndpoint, EnvUsePathStyle)
+
+func TestNewBackend(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			desc: "success",
+			envVars: map[string]string{
+				EnvBucket: "lego",
+			},
+		},
+		{
+			desc:     "missing bucket",
+			envVars:  map[string]string{},
+			expected: "s3: some credentials information are missing: LEGO_STORAGE_S3_BUCKET",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			backend, err := NewBackend()
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, backend)
+				require.NotNil(t, backend.client)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestBackend(t *testing.T) {
+	fake := &fakeS3{objects: map[string][]byte{
+		"other/object":   []byte("foo"),
+		"lego/":          nil,
+		"lego/orphan/..": []byte("foo"),
+	}}
+
+	backend := &Backend{
+		config: &Config{Bucket: "bucket", Prefix: "lego/"},
+		client: fake,
+	}
+
+	err := backend.Save(t.Context(), "certificates/example.com.crt", []byte("cert"))
+	require.NoError(t, err)
+
+	err = backend.Save(t.Context(), "accounts/example/account.json", []byte("{}"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte("cert"), fake.objects["lego/certificates/example.com.crt"])
+
+	data, err := backend.Load(t.Context(), "certificates/example.com.crt")
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte("cert"), data)
+
+	keys, err := backend.List(t.Context(), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"accounts/example/account.json", "certificates/example.com.crt"}, keys)
+
+	keys, err = backend.List(t.Context(), "accounts")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"accounts/example/account.json"}, keys)
+
+	err = backend.Delete(t.Context(), "certificates/example.com.crt")
+	require.NoError(t, err)
+
+	_, err = backend.Load(t.Context(), "certificates/example.com.crt")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	err = backend.Save(t.Context(), "../other/object", []byte("bar"))
+	require.Error(t, err)
+}
+
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) PutObject(_ context.Context, params *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	f.objects[aws.ToString(params.Key)] = data
+
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (f *fakeS3) GetObject(_ context.Context, params *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, ok := f.objects[aws.ToString(params.Key)]
+	if !ok {
+		return nil, &types.NoSuchKey{}
+	}
+
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(data))}, nil
+}
+
+func (f *fakeS3) DeleteObject(_ context.Context, params *s3.DeleteObjectInput, _ ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.objects, aws.ToString(params.Key))
+
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func (f *fakeS3) ListObjectsV2(_ context.Context, params *s3.ListObjectsV2Input, _ ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	output := &s3.ListObjectsV2Output{}
+
+	for key := range f.objects {
+		if strings.HasPrefix(key, aws.ToString(params.Prefix)) {
+			output.Contents = append(output.Contents, types.Object{Key: aws.String(key)})
+		}
+	}
+
+	return output, nil
+}
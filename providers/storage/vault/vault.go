@@ -0,0 +1,289 @@
+// Package vault implements a storage backend using the KV v2 secrets engine of HashiCorp Vault (or OpenBao).
+package vault
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/platform/config/env"
+	"github.com/go-acme/lego/v4/platform/storage"
+	"github.com/go-acme/lego/v4/providers/internal/vaultapi"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "VAULT_"
+
+	EnvAddress   = envNamespace + "ADDR"
+	EnvNamespace = envNamespace + "NAMESPACE"
+
+	EnvAuthMethod          = envNamespace + "AUTH_METHOD"
+	EnvAuthMount           = envNamespace + "AUTH_MOUNT"
+	EnvToken               = envNamespace + "TOKEN"
+	EnvAppRoleRoleID       = envNamespace + "APPROLE_ROLE_ID"
+	EnvAppRoleSecretID     = envNamespace + "APPROLE_SECRET_ID"
+	EnvKubernetesRole      = envNamespace + "KUBERNETES_ROLE"
+	EnvKubernetesTokenPath = envNamespace + "KUBERNETES_TOKEN_PATH"
+
+	EnvKVMount = envNamespace + "KV_MOUNT"
+	EnvKVPath  = envNamespace + "KV_PATH"
+
+	EnvHTTPTimeout = envNamespace + "HTTP_TIMEOUT"
+)
+
+const defaultKubernetesTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// contentField the field of the secrets containing the content of the files (base64).
+const contentField = "content"
+
+// Config is used to configure the creation of the Backend.
+type Config struct {
+	Address   string
+	Namespace string
+
+	// AuthMethod the auth method: token, approle, or kubernetes.
+	AuthMethod string
+	// AuthMount the mount path of the auth method (default: the name of the auth method).
+	AuthMount string
+
+	Token string
+
+	RoleID   string
+	SecretID string
+
+	KubernetesRole      string
+	KubernetesTokenPath string
+
+	// KVMount the mount path of the KV v2 secrets engine.
+	KVMount string
+	// KVPath the path of the files inside the secrets engine.
+	KVPath string
+
+	HTTPClient *http.Client
+}
+
+// NewDefaultConfig returns a default configuration for the Backend.
+func NewDefaultConfig() *Config {
+	return &Config{
+		AuthMethod:          env.GetOrDefaultString(EnvAuthMethod, vaultapi.AuthToken),
+		KubernetesTokenPath: env.GetOrDefaultString(EnvKubernetesTokenPath, defaultKubernetesTokenPath),
+		KVMount:             env.GetOrDefaultString(EnvKVMount, "secret"),
+		KVPath:              env.GetOrDefaultString(EnvKVPath, "lego"),
+		HTTPClient: &http.Client{
+			Timeout: env.GetOrDefaultSecond(EnvHTTPTimeout, 30*time.Second),
+		},
+	}
+}
+
+var _ storage.Backend = (*Backend)(nil)
+
+// Backend stores each file as a secret of a KV v2 secrets engine (`<mount>/<path>/<key>`).
+type Backend struct {
+	config *Config
+	client *vaultapi.Client
+}
+
+// NewBackend returns a Backend instance configured for Vault.
+// The configuration must be passed in the environment variables:
+// VAULT_ADDR, and the credentials of the auth method
+// (VAULT_TOKEN, VAULT_APPROLE_ROLE_ID and VAULT_APPROLE_SECRET_ID, or VAULT_KUBERNETES_ROLE).
+func NewBackend() (*Backend, error) {
+	values, err := env.Get(EnvAddress)
+	if err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+
+	config := NewDefaultConfig()
+	config.Address = values[EnvAddress]
+	config.Namespace = env.GetOrFile(EnvNamespace)
+	config.AuthMount = env.GetOrFile(EnvAuthMount)
+	config.Token = env.GetOrFile(EnvToken)
+	config.RoleID = env.GetOrFile(EnvAppRoleRoleID)
+	config.SecretID = env.GetOrFile(EnvAppRoleSecretID)
+	config.KubernetesRole = env.GetOrFile(EnvKubernetesRole)
+
+	return NewBackendConfig(config)
+}
+
+// NewBackendConfig return a Backend instance configured for Vault.
+// It authenticates with the auth method.
+func NewBackendConfig(config *Config) (*Backend, error) {
+	if config == nil {
+		return nil, errors.New("vault: the configuration of the storage backend is nil")
+	}
+
+	if config.KVMount == "" {
+		return nil, errors.New("vault: the KV mount path is required")
+	}
+
+	client, err := vaultapi.NewClient(config.Address, config.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+
+	if config.HTTPClient != nil {
+		client.HTTPClient = config.HTTPClient
+	}
+
+	b := &Backend{config: config, client: client}
+
+	err = b.login(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+
+	return b, nil
+}
+
+// Save creates a new version of the secret of a key.
+func (b *Backend) Save(ctx context.Context, key string, data []byte) error {
+	err := storage.ValidateKey(key)
+	if err != nil {
+		return fmt.Errorf("vault: %w", err)
+	}
+
+	err = b.do(ctx, func() error {
+		return b.client.WriteSecret(ctx, b.config.KVMount, b.secretPath(key),
+			map[string]string{contentField: base64.StdEncoding.EncodeToString(data)})
+	})
+	if err != nil {
+		return fmt.Errorf("vault: write secret %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// Load returns the content of the secret of a key.
+func (b *Backend) Load(ctx context.Context, key string) ([]byte, error) {
+	err := storage.ValidateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+
+	var secret map[string]string
+
+	err = b.do(ctx, func() error {
+		secret, err = b.client.ReadSecret(ctx, b.config.KVMount, b.secretPath(key))
+		return err
+	})
+	if err != nil {
+		if vaultapi.IsNotFound(err) {
+			return nil, fmt.Errorf("vault: %s: %w", key, storage.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("vault: read secret %s: %w", key, err)
+	}
+
+	content, ok := secret[contentField]
+	if !ok {
+		return nil, fmt.Errorf("vault: read secret %s: missing field %q", key, contentField)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return nil, fmt.Errorf("vault: read secret %s: %w", key, err)
+	}
+
+	return data, nil
+}
+
+// List returns the keys of the secrets inside a folder, sorted.
+// The folders are listed recursively.
+func (b *Backend) List(ctx context.Context, folder string) ([]string, error) {
+	err := storage.ValidateFolder(folder)
+	if err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+
+	var keys []string
+
+	folders := []string{folder}
+
+	for len(folders) > 0 {
+		current := folders[0]
+		folders = folders[1:]
+
+		var names []string
+
+		err = b.do(ctx, func() error {
+			names, err = b.client.ListSecrets(ctx, b.config.KVMount, b.secretPath(current))
+			return err
+		})
+		if err != nil {
+			if vaultapi.IsNotFound(err) {
+				// Empty folder.
+				continue
+			}
+
+			return nil, fmt.Errorf("vault: list secrets %s: %w", current, err)
+		}
+
+		for _, name := range names {
+			if sub, ok := strings.CutSuffix(name, "/"); ok {
+				folders = append(folders, path.Join(current, sub))
+				continue
+			}
+
+			keys = append(keys, path.Join(current, name))
+		}
+	}
+
+	slices.Sort(keys)
+
+	return keys, nil
+}
+
+// Delete deletes all the versions of the secret of a key.
+func (b *Backend) Delete(ctx context.Context, key string) error {
+	err := storage.ValidateKey(key)
+	if err != nil {
+		return fmt.Errorf("vault: %w", err)
+	}
+
+	err = b.do(ctx, func() error {
+		return b.client.DeleteSecret(ctx, b.config.KVMount, b.secretPath(key))
+	})
+	if err != nil && !vaultapi.IsNotFound(err) {
+		return fmt.Errorf("vault: delete secret %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// do calls the API, and authenticates again if the token has expired.
+func (b *Backend) do(ctx context.Context, fn func() error) error {
+	err := fn()
+	if err == nil || !vaultapi.IsPermissionDenied(err) || b.config.AuthMethod == vaultapi.AuthToken {
+		return err
+	}
+
+	err = b.login(ctx)
+	if err != nil {
+		return err
+	}
+
+	return fn()
+}
+
+func (b *Backend) login(ctx context.Context) error {
+	return b.client.Authenticate(ctx, vaultapi.Auth{
+		Method:              b.config.AuthMethod,
+		Mount:               b.config.AuthMount,
+		Token:               b.config.Token,
+		RoleID:              b.config.RoleID,
+		SecretID:            b.config.SecretID,
+		KubernetesRole:      b.config.KubernetesRole,
+		KubernetesTokenPath: b.config.KubernetesTokenPath,
+	})
+}
+
+func (b *Backend) secretPath(key string) string {
+	return path.Join(b.config.KVPath, key)
+}
@@ -0,0 +1,221 @@
+package vault
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-acme/lego/v4/platform/storage"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var envTest = tester.NewEnvTest(
+	EnvAddress,
+	EnvNamespace,
+	EnvAuthMethod,
+	EnvAuthMount,
+	EnvToken,
+	EnvAppRoleRoleID,
+	EnvAppRoleSecretID,
+	EnvKubernetesRole,
+	EnvKubernetesTokenPath,
+	EnvKVMount,
+	EnvKVPath,
+)
+
+func TestNewBackend(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			desc: "success",
+			envVars: map[string]string{
+				EnvAddress: "https://vault.example.com",
+				EnvToken:   "secret",
+			},
+		},
+		{
+			desc:     "missing configuration",
+			envVars:  map[string]string{},
+			expected: "vault: some credentials information are missing: VAULT_ADDR",
+		},
+		{
+			desc: "missing token",
+			envVars: map[string]string{
+				EnvAddress: "https://vault.example.com",
+			},
+			expected: "vault: the token is required",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			backend, err := NewBackend()
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, backend)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestBackend(t *testing.T) {
+	kv := &fakeKV{secrets: map[string]map[string]string{}}
+
+	server := httptest.NewServer(kv)
+	t.Cleanup(server.Close)
+
+	config := NewDefaultConfig()
+	config.Address = server.URL
+	config.Token = "secret"
+
+	backend, err := NewBackendConfig(config)
+	require.NoError(t, err)
+
+	err = backend.Save(t.Context(), "certificates/example.com.crt", []byte("cert"))
+	require.NoError(t, err)
+
+	err = backend.Save(t.Context(), "accounts/example/account.json", []byte("{}"))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"content": "Y2VydA=="}, kv.secrets["lego/certificates/example.com.crt"])
+
+	data, err := backend.Load(t.Context(), "certificates/example.com.crt")
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte("cert"), data)
+
+	keys, err := backend.List(t.Context(), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"accounts/example/account.json", "certificates/example.com.crt"}, keys)
+
+	keys, err = backend.List(t.Context(), "accounts")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"accounts/example/account.json"}, keys)
+
+	err = backend.Delete(t.Context(), "certificates/example.com.crt")
+	require.NoError(t, err)
+
+	_, err = backend.Load(t.Context(), "certificates/example.com.crt")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	err = backend.Delete(t.Context(), "certificates/example.com.crt")
+	require.NoError(t, err)
+}
+
+// fakeKV an in-memory KV v2 secrets engine mounted at `secret`.
+type fakeKV struct {
+	mu      sync.Mutex
+	secrets map[string]map[string]string
+}
+
+func (f *fakeKV) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if req.Header.Get("X-Vault-Token") != "secret" {
+		http.Error(rw, `{"errors":["permission denied"]}`, http.StatusForbidden)
+		return
+	}
+
+	if name, ok := strings.CutPrefix(req.URL.Path, "/v1/secret/data/"); ok {
+		f.serveData(rw, req, name)
+		return
+	}
+
+	if name, ok := strings.CutPrefix(req.URL.Path, "/v1/secret/metadata/"); ok {
+		f.serveMetadata(rw, req, name)
+		return
+	}
+
+	http.NotFound(rw, req)
+}
+
+func (f *fakeKV) serveData(rw http.ResponseWriter, req *http.Request, name string) {
+	switch req.Method {
+	case http.MethodGet:
+		secret, ok := f.secrets[name]
+		if !ok {
+			http.Error(rw, `{"errors":[]}`, http.StatusNotFound)
+			return
+		}
+
+		_ = json.NewEncoder(rw).Encode(map[string]any{"data": map[string]any{"data": secret}})
+
+	case http.MethodPost:
+		var body struct {
+			Data map[string]string `json:"data"`
+		}
+
+		err := json.NewDecoder(req.Body).Decode(&body)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		f.secrets[name] = body.Data
+
+		_, _ = rw.Write([]byte(`{"data":{"version":1}}`))
+
+	default:
+		http.Error(rw, "unsupported method", http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeKV) serveMetadata(rw http.ResponseWriter, req *http.Request, name string) {
+	switch {
+	case req.Method == http.MethodDelete:
+		delete(f.secrets, name)
+
+		rw.WriteHeader(http.StatusNoContent)
+
+	case req.Method == http.MethodGet && req.URL.Query().Get("list") == "true":
+		prefix := strings.TrimSuffix(name, "/") + "/"
+
+		var keys []string
+
+		for secretName := range f.secrets {
+			rest, ok := strings.CutPrefix(secretName, prefix)
+			if !ok {
+				continue
+			}
+
+			if before, _, found := strings.Cut(rest, "/"); found {
+				rest = before + "/"
+			}
+
+			if !slices.Contains(keys, rest) {
+				keys = append(keys, rest)
+			}
+		}
+
+		if len(keys) == 0 {
+			http.Error(rw, `{"errors":[]}`, http.StatusNotFound)
+			return
+		}
+
+		_ = json.NewEncoder(rw).Encode(map[string]any{"data": map[string]any{"keys": keys}})
+
+	default:
+		http.Error(rw, "unsupported method", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,180 @@
+// Package envoy implements a TLS provider for solving the TLS-ALPN-01 challenge using the secret discovery service (SDS) of Envoy.
+package envoy
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
+	"github.com/go-acme/lego/v4/platform/config/env"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "ENVOY_"
+
+	EnvSDSPath    = envNamespace + "SDS_PATH"
+	EnvSecretName = envNamespace + "SDS_SECRET_NAME"
+)
+
+const defaultSecretName = "acme-tls-alpn"
+
+// placeholderDomain the domain of the certificate of the secret when no challenge is presented.
+const placeholderDomain = "acme.invalid"
+
+const secretType = "type.googleapis.com/envoy.extensions.transport_sockets.tls.v3.Secret"
+
+// Config is used to configure the creation of the TLSProvider.
+type Config struct {
+	// SDSPath the path of the file watched by Envoy (`path_config_source` of the SDS secret).
+	SDSPath string
+	// SecretName the name of the SDS secret (`tls_certificate_sds_secret_configs` of the filter chain).
+	SecretName string
+}
+
+// NewDefaultConfig returns a default configuration for the TLSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		SecretName: env.GetOrDefaultString(EnvSecretName, defaultSecretName),
+	}
+}
+
+// TLSProvider implements ChallengeProvider for `tls-alpn-01` challenge.
+// The challenge certificate is written to the SDS file of a secret,
+// the secret must be used by a filter chain of the port 443 matching the `acme-tls/1` protocol.
+type TLSProvider struct {
+	config *Config
+}
+
+// NewTLSProvider returns a TLSProvider instance configured for Envoy.
+// The path of the SDS file must be passed in the environment variable ENVOY_SDS_PATH.
+func NewTLSProvider() (*TLSProvider, error) {
+	values, err := env.Get(EnvSDSPath)
+	if err != nil {
+		return nil, fmt.Errorf("envoy: %w", err)
+	}
+
+	config := NewDefaultConfig()
+	config.SDSPath = values[EnvSDSPath]
+
+	return NewTLSProviderConfig(config)
+}
+
+// NewTLSProviderConfig return a TLSProvider instance configured for Envoy.
+func NewTLSProviderConfig(config *Config) (*TLSProvider, error) {
+	if config == nil {
+		return nil, errors.New("envoy: the configuration of the TLS provider is nil")
+	}
+
+	if config.SDSPath == "" || config.SecretName == "" {
+		return nil, errors.New("envoy: the path of the SDS file and the name of the secret are required")
+	}
+
+	return &TLSProvider{config: config}, nil
+}
+
+// Present writes the challenge certificate to the SDS file.
+// The TLS-ALPN-01 challenges are solved one at a time: a single secret is enough.
+func (p *TLSProvider) Present(domain, token, keyAuth string) error {
+	certPEM, keyPEM, err := tlsalpn01.ChallengeBlocks(domain, keyAuth)
+	if err != nil {
+		return fmt.Errorf("envoy: %w", err)
+	}
+
+	defer certcrypto.Zeroize(keyPEM)
+
+	return p.writeSecret(certPEM, keyPEM)
+}
+
+// CleanUp replaces the challenge certificate with a placeholder certificate.
+// Envoy rejects an SDS file without the secret: the previous certificate would be kept.
+func (p *TLSProvider) CleanUp(domain, token, keyAuth string) error {
+	privateKey, err := certcrypto.GeneratePrivateKey(certcrypto.RSA2048)
+	if err != nil {
+		return fmt.Errorf("envoy: %w", err)
+	}
+
+	certPEM, err := certcrypto.GeneratePemCert(privateKey.(*rsa.PrivateKey), placeholderDomain, nil)
+	if err != nil {
+		return fmt.Errorf("envoy: %w", err)
+	}
+
+	keyPEM := certcrypto.PEMEncode(privateKey)
+
+	defer certcrypto.Zeroize(keyPEM)
+
+	return p.writeSecret(certPEM, keyPEM)
+}
+
+// writeSecret replaces the SDS file.
+// Envoy only reloads the file when it's moved: the file is written next to it, then renamed.
+func (p *TLSProvider) writeSecret(certPEM, keyPEM []byte) error {
+	data, err := json.MarshalIndent(discoveryResponse{
+		VersionInfo: strconv.FormatInt(time.Now().UnixNano(), 10),
+		Resources: []secret{{
+			Type: secretType,
+			Name: p.config.SecretName,
+			TLSCertificate: tlsCertificate{
+				CertificateChain: dataSource{InlineString: string(certPEM)},
+				PrivateKey:       dataSource{InlineString: string(keyPEM)},
+			},
+		}},
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("envoy: %w", err)
+	}
+
+	defer certcrypto.Zeroize(data)
+
+	tmp, err := os.CreateTemp(filepath.Dir(p.config.SDSPath), "."+filepath.Base(p.config.SDSPath)+".*")
+	if err != nil {
+		return fmt.Errorf("envoy: %w", err)
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("envoy: %w", err)
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("envoy: %w", err)
+	}
+
+	err = os.Rename(tmp.Name(), p.config.SDSPath)
+	if err != nil {
+		return fmt.Errorf("envoy: %w", err)
+	}
+
+	return nil
+}
+
+type discoveryResponse struct {
+	VersionInfo string   `json:"version_info"`
+	Resources   []secret `json:"resources"`
+}
+
+type secret struct {
+	Type           string         `json:"@type"`
+	Name           string         `json:"name"`
+	TLSCertificate tlsCertificate `json:"tls_certificate"`
+}
+
+type tlsCertificate struct {
+	CertificateChain dataSource `json:"certificate_chain"`
+	PrivateKey       dataSource `json:"private_key"`
+}
+
+type dataSource struct {
+	InlineString string `json:"inline_string"`
+}
@@ -0,0 +1,106 @@
+package envoy
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var envTest = tester.NewEnvTest(EnvSDSPath, EnvSecretName)
+
+func TestNewTLSProvider(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			desc:    "success",
+			envVars: map[string]string{EnvSDSPath: "/etc/envoy/sds/acme.json"},
+		},
+		{
+			desc:     "missing path",
+			envVars:  map[string]string{},
+			expected: "envoy: some credentials information are missing: ENVOY_SDS_PATH",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			p, err := NewTLSProvider()
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				assert.Equal(t, defaultSecretName, p.config.SecretName)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestTLSProvider(t *testing.T) {
+	config := NewDefaultConfig()
+	config.SDSPath = filepath.Join(t.TempDir(), "acme.json")
+
+	p, err := NewTLSProviderConfig(config)
+	require.NoError(t, err)
+
+	err = p.Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	cert := readSecret(t, config.SDSPath)
+	assert.Equal(t, []string{"example.com"}, cert.DNSNames)
+
+	err = p.CleanUp("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	cert = readSecret(t, config.SDSPath)
+	assert.Equal(t, []string{placeholderDomain}, cert.DNSNames)
+
+	// Only the SDS file is kept.
+	files, err := os.ReadDir(filepath.Dir(config.SDSPath))
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func readSecret(t *testing.T, path string) *x509.Certificate {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var response discoveryResponse
+
+	err = json.Unmarshal(data, &response)
+	require.NoError(t, err)
+
+	require.Len(t, response.Resources, 1)
+
+	s := response.Resources[0]
+
+	assert.Equal(t, secretType, s.Type)
+	assert.Equal(t, defaultSecretName, s.Name)
+
+	pair, err := tls.X509KeyPair([]byte(s.TLSCertificate.CertificateChain.InlineString), []byte(s.TLSCertificate.PrivateKey.InlineString))
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	require.NoError(t, err)
+
+	return cert
+}
@@ -0,0 +1,198 @@
+// Package haproxy implements a TLS provider for solving the TLS-ALPN-01 challenge using the runtime API of HAProxy.
+package haproxy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
+	"github.com/go-acme/lego/v4/platform/config/env"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "HAPROXY_"
+
+	EnvRuntimeAPI = envNamespace + "RUNTIME_API"
+	EnvCrtList    = envNamespace + "CRT_LIST"
+
+	EnvTimeout = envNamespace + "TIMEOUT"
+)
+
+// certPrefix the prefix of the names of the challenge certificates in the certificate store of HAProxy.
+const certPrefix = "lego-tls-alpn-"
+
+// Config is used to configure the creation of the TLSProvider.
+type Config struct {
+	// Address the address of the runtime API (the `stats socket` of HAProxy):
+	// a unix socket (`unix:/run/haproxy/admin.sock` or `/run/haproxy/admin.sock`), or a TCP address (`127.0.0.1:9999`).
+	Address string
+	// CrtList the path of the crt-list used by the `bind` line of the port 443.
+	CrtList string
+	// Timeout the timeout of the commands.
+	Timeout time.Duration
+}
+
+// NewDefaultConfig returns a default configuration for the TLSProvider.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Timeout: env.GetOrDefaultSecond(EnvTimeout, 10*time.Second),
+	}
+}
+
+// TLSProvider implements ChallengeProvider for `tls-alpn-01` challenge.
+// The challenge certificate is added to the certificate store of HAProxy,
+// and to the crt-list of the port 443 with the SNI of the domain and the `acme-tls/1` protocol.
+type TLSProvider struct {
+	config *Config
+}
+
+// NewTLSProvider returns a TLSProvider instance configured for HAProxy.
+// The configuration must be passed in the environment variables:
+// HAPROXY_RUNTIME_API, and HAPROXY_CRT_LIST.
+func NewTLSProvider() (*TLSProvider, error) {
+	values, err := env.Get(EnvRuntimeAPI, EnvCrtList)
+	if err != nil {
+		return nil, fmt.Errorf("haproxy: %w", err)
+	}
+
+	config := NewDefaultConfig()
+	config.Address = values[EnvRuntimeAPI]
+	config.CrtList = values[EnvCrtList]
+
+	return NewTLSProviderConfig(config)
+}
+
+// NewTLSProviderConfig return a TLSProvider instance configured for HAProxy.
+func NewTLSProviderConfig(config *Config) (*TLSProvider, error) {
+	if config == nil {
+		return nil, errors.New("haproxy: the configuration of the TLS provider is nil")
+	}
+
+	if config.Address == "" || config.CrtList == "" {
+		return nil, errors.New("haproxy: the address of the runtime API and the crt-list are required")
+	}
+
+	return &TLSProvider{config: config}, nil
+}
+
+// Present adds the challenge certificate to HAProxy:
+// the certificate is created in the certificate store, then added to the crt-list.
+func (p *TLSProvider) Present(domain, token, keyAuth string) error {
+	certPEM, keyPEM, err := tlsalpn01.ChallengeBlocks(domain, keyAuth)
+	if err != nil {
+		return fmt.Errorf("haproxy: %w", err)
+	}
+
+	defer certcrypto.Zeroize(keyPEM)
+
+	name := certName(domain)
+
+	err = p.execute("new ssl cert "+name, nil, "New empty certificate store")
+	if err != nil {
+		return err
+	}
+
+	err = p.addCertificate(name, domain, append(certPEM, keyPEM...))
+	if err != nil {
+		// The certificate store is not kept: the next attempt creates it again.
+		_ = p.execute("del ssl cert "+name, nil, "deleted")
+
+		return err
+	}
+
+	return nil
+}
+
+// CleanUp removes the challenge certificate from the crt-list, then from the certificate store.
+func (p *TLSProvider) CleanUp(domain, token, keyAuth string) error {
+	name := certName(domain)
+
+	err := p.execute(fmt.Sprintf("del ssl crt-list %s %s", p.config.CrtList, name), nil, "deleted")
+	if err != nil {
+		return err
+	}
+
+	return p.execute("del ssl cert "+name, nil, "deleted")
+}
+
+func (p *TLSProvider) addCertificate(name, domain string, payload []byte) error {
+	err := p.execute("set ssl cert "+name, payload, "Transaction created", "Transaction updated")
+	if err != nil {
+		return err
+	}
+
+	err = p.execute("commit ssl cert "+name, nil, "Success!")
+	if err != nil {
+		return err
+	}
+
+	// The entry is selected by the SNI of the domain, and the connection uses the `acme-tls/1` protocol.
+	entry := fmt.Sprintf("%s [alpn %s] %s", name, tlsalpn01.ACMETLS1Protocol, domain)
+
+	return p.execute("add ssl crt-list "+p.config.CrtList, []byte(entry), "Success!")
+}
+
+// execute sends a command to the runtime API.
+// The response must contain one of the expected messages: the runtime API has no status code.
+func (p *TLSProvider) execute(command string, payload []byte, expected ...string) error {
+	network, address := "tcp", p.config.Address
+	if after, ok := strings.CutPrefix(address, "unix:"); ok {
+		network, address = "unix", after
+	} else if strings.HasPrefix(address, "/") {
+		network = "unix"
+	}
+
+	conn, err := net.DialTimeout(network, address, p.config.Timeout)
+	if err != nil {
+		return fmt.Errorf("haproxy: %w", err)
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	err = conn.SetDeadline(time.Now().Add(p.config.Timeout))
+	if err != nil {
+		return fmt.Errorf("haproxy: %w", err)
+	}
+
+	request := command + "\n"
+	if payload != nil {
+		// The payload ends with an empty line.
+		request = command + " <<\n" + strings.TrimRight(string(payload), "\n") + "\n\n"
+	}
+
+	_, err = io.WriteString(conn, request)
+	if err != nil {
+		return fmt.Errorf("haproxy: %s: %w", commandName(command), err)
+	}
+
+	// The connection is closed by HAProxy after the response (non-interactive mode).
+	response, err := io.ReadAll(conn)
+	if err != nil {
+		return fmt.Errorf("haproxy: %s: %w", commandName(command), err)
+	}
+
+	for _, msg := range expected {
+		if strings.Contains(string(response), msg) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("haproxy: %s: %s", commandName(command), strings.TrimSpace(string(response)))
+}
+
+func certName(domain string) string {
+	return certPrefix + domain + ".pem"
+}
+
+// commandName returns the command without its arguments (ex: `new ssl cert`).
+func commandName(command string) string {
+	fields := strings.Fields(command)
+
+	return strings.Join(fields[:min(len(fields), 3)], " ")
+}
@@ -0,0 +1,207 @@
+package haproxy
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var envTest = tester.NewEnvTest(EnvRuntimeAPI, EnvCrtList, EnvTimeout)
+
+// runtimeAPIMock a fake runtime API: the responses are indexed by command name.
+type runtimeAPIMock struct {
+	listener  net.Listener
+	responses map[string]string
+
+	mu       sync.Mutex
+	commands []string
+	payloads map[string]string
+}
+
+func newRuntimeAPIMock(t *testing.T, responses map[string]string) *runtimeAPIMock {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	m := &runtimeAPIMock{listener: listener, responses: responses, payloads: map[string]string{}}
+
+	go m.serve()
+
+	return m
+}
+
+func (m *runtimeAPIMock) serve() {
+	for {
+		conn, err := m.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		m.handle(conn)
+	}
+}
+
+func (m *runtimeAPIMock) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	reader := bufio.NewReader(conn)
+
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return
+	}
+
+	command, hasPayload := strings.CutSuffix(strings.TrimSpace(line), " <<")
+
+	var payload []string
+
+	for hasPayload {
+		l, err := reader.ReadString('\n')
+		if err != nil || l == "\n" {
+			break
+		}
+
+		payload = append(payload, l)
+	}
+
+	name := commandName(command)
+
+	m.mu.Lock()
+	m.commands = append(m.commands, command)
+	m.payloads[name] = strings.Join(payload, "")
+	m.mu.Unlock()
+
+	_, _ = io.WriteString(conn, m.responses[name]+"\n")
+}
+
+func (m *runtimeAPIMock) calls() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.commands
+}
+
+func TestNewTLSProvider(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			desc: "success",
+			envVars: map[string]string{
+				EnvRuntimeAPI: "unix:/run/haproxy/admin.sock",
+				EnvCrtList:    "/etc/haproxy/crt-list.txt",
+			},
+		},
+		{
+			desc: "missing crt-list",
+			envVars: map[string]string{
+				EnvRuntimeAPI: "unix:/run/haproxy/admin.sock",
+			},
+			expected: "haproxy: some credentials information are missing: HAPROXY_CRT_LIST",
+		},
+		{
+			desc:     "missing configuration",
+			envVars:  map[string]string{},
+			expected: "haproxy: some credentials information are missing: HAPROXY_RUNTIME_API,HAPROXY_CRT_LIST",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			defer envTest.RestoreEnv()
+
+			envTest.ClearEnv()
+
+			envTest.Apply(test.envVars)
+
+			p, err := NewTLSProvider()
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, p)
+				assert.Equal(t, 10*time.Second, p.config.Timeout)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestTLSProvider(t *testing.T) {
+	server := newRuntimeAPIMock(t, map[string]string{
+		"new ssl cert":     "New empty certificate store 'lego-tls-alpn-example.com.pem'!",
+		"set ssl cert":     "Transaction created for certificate lego-tls-alpn-example.com.pem!",
+		"commit ssl cert":  "Committing lego-tls-alpn-example.com.pem\nSuccess!",
+		"add ssl crt-list": "Inserting certificate 'lego-tls-alpn-example.com.pem' in crt-list '/etc/haproxy/crt-list.txt'.\nSuccess!",
+		"del ssl crt-list": "Entry 'lego-tls-alpn-example.com.pem' deleted in crtlist '/etc/haproxy/crt-list.txt'!",
+		"del ssl cert":     "Certificate 'lego-tls-alpn-example.com.pem' deleted!",
+	})
+
+	config := NewDefaultConfig()
+	config.Address = server.listener.Addr().String()
+	config.CrtList = "/etc/haproxy/crt-list.txt"
+
+	p, err := NewTLSProviderConfig(config)
+	require.NoError(t, err)
+
+	err = p.Present("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	err = p.CleanUp("example.com", "token", "keyAuth")
+	require.NoError(t, err)
+
+	expected := []string{
+		"new ssl cert lego-tls-alpn-example.com.pem",
+		"set ssl cert lego-tls-alpn-example.com.pem",
+		"commit ssl cert lego-tls-alpn-example.com.pem",
+		"add ssl crt-list /etc/haproxy/crt-list.txt",
+		"del ssl crt-list /etc/haproxy/crt-list.txt lego-tls-alpn-example.com.pem",
+		"del ssl cert lego-tls-alpn-example.com.pem",
+	}
+
+	assert.Equal(t, expected, server.calls())
+
+	assert.Equal(t, "lego-tls-alpn-example.com.pem [alpn acme-tls/1] example.com\n", server.payloads["add ssl crt-list"])
+	assert.Contains(t, server.payloads["set ssl cert"], "-----BEGIN CERTIFICATE-----")
+	assert.Contains(t, server.payloads["set ssl cert"], "PRIVATE KEY-----")
+}
+
+func TestTLSProvider_Present_error(t *testing.T) {
+	server := newRuntimeAPIMock(t, map[string]string{
+		"new ssl cert": "New empty certificate store 'lego-tls-alpn-example.com.pem'!",
+		"set ssl cert": "unable to load the content of this file",
+		"del ssl cert": "Certificate 'lego-tls-alpn-example.com.pem' deleted!",
+	})
+
+	config := NewDefaultConfig()
+	config.Address = server.listener.Addr().String()
+	config.CrtList = "/etc/haproxy/crt-list.txt"
+
+	p, err := NewTLSProviderConfig(config)
+	require.NoError(t, err)
+
+	err = p.Present("example.com", "token", "keyAuth")
+	require.EqualError(t, err, "haproxy: set ssl cert: unable to load the content of this file")
+
+	// The certificate store is removed.
+	expected := []string{
+		"new ssl cert lego-tls-alpn-example.com.pem",
+		"set ssl cert lego-tls-alpn-example.com.pem",
+		"del ssl cert lego-tls-alpn-example.com.pem",
+	}
+
+	assert.Equal(t, expected, server.calls())
+}
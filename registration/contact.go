@@ -0,0 +1,92 @@
+package registration
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/go-acme/lego/v4/acme"
+	"golang.org/x/net/idna"
+)
+
+const mailTo = "mailto:"
+
+// mailtoContact returns the contact URI of an email address (RFC 6068).
+//
+// An internationalized email address (EAI, RFC 6531) is accepted:
+// the domain is converted to its ASCII form (A-labels),
+// and the non-ASCII characters of the local part are percent-encoded (UTF-8).
+// Not all CAs support the non-ASCII local parts (see contactError).
+func mailtoContact(email string) (string, error) {
+	// Only a bare address is accepted (without display name, angle brackets, or comments).
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Name != "" || strings.ContainsAny(email, "<>()") || strings.TrimSpace(email) != email {
+		return "", fmt.Errorf("acme: invalid email address %q", email)
+	}
+
+	i := strings.LastIndex(email, "@")
+	local, domain := email[:i], email[i+1:]
+
+	if !isASCII(domain) {
+		domain, err = idna.Lookup.ToASCII(domain)
+		if err != nil {
+			return "", fmt.Errorf("acme: invalid domain in the email address %q: %w", email, err)
+		}
+	}
+
+	return mailTo + escapeLocalPart(local) + "@" + domain, nil
+}
+
+// escapeLocalPart percent-encodes the non-ASCII characters,
+// and the characters with a special meaning in the URIs (RFC 6068, section 2).
+func escapeLocalPart(local string) string {
+	const hex = "0123456789ABCDEF"
+
+	var b strings.Builder
+
+	for i := range len(local) {
+		c := local[i]
+
+		if c < 0x80 && !strings.ContainsRune(`%/?#[] "`, rune(c)) {
+			b.WriteByte(c)
+			continue
+		}
+
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&0x0f])
+	}
+
+	return b.String()
+}
+
+// contactError explains the rejection of an email address with a non-ASCII local part,
+// when the CA doesn't support the internationalized email addresses.
+func contactError(email string, err error) error {
+	i := strings.LastIndex(email, "@")
+	if i < 0 || isASCII(email[:i]) {
+		return err
+	}
+
+	problem := &acme.ProblemDetails{}
+	if !errors.As(err, &problem) {
+		return err
+	}
+
+	if problem.Type != acme.InvalidContactErr && problem.Type != acme.UnsupportedContactErr {
+		return err
+	}
+
+	return fmt.Errorf("acme: the CA doesn't support the internationalized email address %q, use an email address with an ASCII local part: %w", email, err)
+}
+
+func isASCII(s string) bool {
+	for i := range len(s) {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+
+	return true
+}
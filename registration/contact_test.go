@@ -0,0 +1,157 @@
+package registration
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_mailtoContact(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		email    string
+		expected string
+		err      string
+	}{
+		{
+			desc:     "ASCII",
+			email:    "test@example.com",
+			expected: "mailto:test@example.com",
+		},
+		{
+			desc:     "ASCII with special characters",
+			email:    "a+b.c@example.com",
+			expected: "mailto:a+b.c@example.com",
+		},
+		{
+			desc:     "uppercase domain",
+			email:    "test@EXAMPLE.com",
+			expected: "mailto:test@EXAMPLE.com",
+		},
+		{
+			desc:     "non-ASCII domain",
+			email:    "test@bücher.example",
+			expected: "mailto:test@xn--bcher-kva.example",
+		},
+		{
+			desc:     "non-ASCII local part",
+			email:    "josé@example.com",
+			expected: "mailto:jos%C3%A9@example.com",
+		},
+		{
+			desc:     "non-ASCII local part and domain",
+			email:    "用户@例子.广告",
+			expected: "mailto:%E7%94%A8%E6%88%B7@xn--fsqu00a.xn--4rr70v",
+		},
+		{
+			desc:     "quoted local part",
+			email:    `"john doe"@example.com`,
+			expected: "mailto:%22john%20doe%22@example.com",
+		},
+		{
+			desc:  "display name",
+			email: "John <john@example.com>",
+			err:   `acme: invalid email address "John <john@example.com>"`,
+		},
+		{
+			desc:  "missing domain",
+			email: "john",
+			err:   `acme: invalid email address "john"`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			contact, err := mailtoContact(test.email)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, contact)
+		})
+	}
+}
+
+func Test_contactError(t *testing.T) {
+	unsupported := &acme.ProblemDetails{Type: acme.UnsupportedContactErr, Detail: "non-ASCII email", HTTPStatus: http.StatusBadRequest}
+
+	err := contactError("josé@example.com", unsupported)
+	require.ErrorIs(t, err, unsupported)
+	assert.Contains(t, err.Error(), `the CA doesn't support the internationalized email address "josé@example.com"`)
+
+	// ASCII local part.
+	err = contactError("test@bücher.example", unsupported)
+	assert.Equal(t, unsupported, err)
+
+	// Other errors.
+	other := errors.New("oops")
+	assert.Equal(t, other, contactError("josé@example.com", other))
+}
+
+func TestRegistrar_Register_internationalizedEmail(t *testing.T) {
+	var contacts []string
+
+	server := tester.MockACMEServer().
+		Route("POST /account",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				raw, err := io.ReadAll(req.Body)
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				jws, err := jose.ParseSigned(string(raw), []jose.SignatureAlgorithm{jose.RS256})
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				var account acme.Account
+
+				err = json.Unmarshal(jws.UnsafePayloadWithoutVerification(), &account)
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				contacts = account.Contact
+
+				rw.Header().Set("Content-Type", "application/problem+json")
+				rw.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(rw).Encode(acme.ProblemDetails{
+					Type:   acme.UnsupportedContactErr,
+					Detail: "contact email has non-ASCII characters",
+				})
+			})).
+		BuildHTTPS(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", key)
+	require.NoError(t, err)
+
+	registrar := NewRegistrar(core, mockUser{email: "josé@bücher.example", privatekey: key})
+
+	_, err = registrar.Register(RegisterOptions{TermsOfServiceAgreed: true})
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), `acme: the CA doesn't support the internationalized email address "josé@bücher.example"`)
+	assert.Equal(t, []string{"mailto:jos%C3%A9@xn--bcher-kva.example"}, contacts)
+}
@@ -1,16 +1,17 @@
 package registration
 
 import (
+	"crypto"
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/acme/api"
 	"github.com/go-acme/lego/v4/log"
 )
 
-const mailTo = "mailto:"
-
 // Resource represents all important information about a registration
 // of which the client needs to keep track itself.
 // WARNING: will be removed in the future (acme.ExtendedAccount), https://github.com/go-acme/lego/issues/855.
@@ -29,9 +30,21 @@ type RegisterEABOptions struct {
 	HmacEncoded          string
 }
 
+// Requirements the account requirements of a CA which are not enforced by the ACME protocol.
+type Requirements struct {
+	// CAName the name of the CA (used in the error messages).
+	CAName string
+	// Contact an email address is required.
+	Contact bool
+	// EAB an External Account Binding is required.
+	EAB bool
+}
+
 type Registrar struct {
 	core *api.Core
 	user User
+
+	requirements Requirements
 }
 
 func NewRegistrar(core *api.Core, user User) *Registrar {
@@ -41,24 +54,41 @@ func NewRegistrar(core *api.Core, user User) *Registrar {
 	}
 }
 
+// SetRequirements sets the account requirements of the CA:
+// the registration fails before contacting the CA if a requirement is not met.
+func (r *Registrar) SetRequirements(requirements Requirements) {
+	r.requirements = requirements
+}
+
 // Register the current account to the ACME server.
 func (r *Registrar) Register(options RegisterOptions) (*Resource, error) {
 	if r == nil || r.user == nil {
 		return nil, errors.New("acme: cannot register a nil client or user")
 	}
 
-	accMsg := acme.Account{
-		TermsOfServiceAgreed: options.TermsOfServiceAgreed,
-		Contact:              []string{},
+	if r.requirements.EAB {
+		return nil, fmt.Errorf("acme: %s requires an External Account Binding (EAB)", r.caName())
 	}
 
-	if r.user.GetEmail() != "" {
-		log.Infof("acme: Registering account for %s", r.user.GetEmail())
-		accMsg.Contact = []string{mailTo + r.user.GetEmail()}
+	err := r.checkContact()
+	if err != nil {
+		return nil, err
+	}
+
+	contacts, err := r.contacts()
+	if err != nil {
+		return nil, err
+	}
+
+	accMsg := acme.Account{
+		TermsOfServiceAgreed: options.TermsOfServiceAgreed,
+		Contact:              contacts,
 	}
 
 	account, err := r.core.Accounts.New(accMsg)
 	if err != nil {
+		err = contactError(r.user.GetEmail(), err)
+
 		// seems impossible
 		errorDetails := &acme.ProblemDetails{}
 		if !errors.As(err, &errorDetails) || errorDetails.HTTPStatus != http.StatusConflict {
@@ -71,18 +101,25 @@ func (r *Registrar) Register(options RegisterOptions) (*Resource, error) {
 
 // RegisterWithExternalAccountBinding Register the current account to the ACME server.
 func (r *Registrar) RegisterWithExternalAccountBinding(options RegisterEABOptions) (*Resource, error) {
-	accMsg := acme.Account{
-		TermsOfServiceAgreed: options.TermsOfServiceAgreed,
-		Contact:              []string{},
+	err := r.checkContact()
+	if err != nil {
+		return nil, err
+	}
+
+	contacts, err := r.contacts()
+	if err != nil {
+		return nil, err
 	}
 
-	if r.user.GetEmail() != "" {
-		log.Infof("acme: Registering account for %s", r.user.GetEmail())
-		accMsg.Contact = []string{mailTo + r.user.GetEmail()}
+	accMsg := acme.Account{
+		TermsOfServiceAgreed: options.TermsOfServiceAgreed,
+		Contact:              contacts,
 	}
 
 	account, err := r.core.Accounts.NewEAB(accMsg, options.Kid, options.HmacEncoded)
 	if err != nil {
+		err = contactError(r.user.GetEmail(), err)
+
 		// seems impossible
 		errorDetails := &acme.ProblemDetails{}
 		if !errors.As(err, &errorDetails) || errorDetails.HTTPStatus != http.StatusConflict {
@@ -123,26 +160,100 @@ func (r *Registrar) UpdateRegistration(options RegisterOptions) (*Resource, erro
 		return nil, errors.New("acme: cannot update a nil client or user")
 	}
 
+	contacts, err := r.contacts()
+	if err != nil {
+		return nil, err
+	}
+
 	accMsg := acme.Account{
 		TermsOfServiceAgreed: options.TermsOfServiceAgreed,
-		Contact:              []string{},
+		Contact:              contacts,
 	}
 
-	if r.user.GetEmail() != "" {
-		log.Infof("acme: Registering account for %s", r.user.GetEmail())
-		accMsg.Contact = []string{mailTo + r.user.GetEmail()}
+	accountURL := r.user.GetRegistration().URI
+
+	account, err := r.core.Accounts.Update(accountURL, accMsg)
+	if err != nil {
+		return nil, contactError(r.user.GetEmail(), err)
+	}
+
+	return &Resource{URI: accountURL, Body: account}, nil
+}
+
+// UpdateExternalAccountBinding binds the account to new External Account Binding credentials
+// (ex: the CA expires the EAB credentials).
+// RFC 8555 only defines the binding at the creation of an account: the CA must support the update of the binding.
+func (r *Registrar) UpdateExternalAccountBinding(options RegisterEABOptions) (*Resource, error) {
+	if r == nil || r.user == nil || r.user.GetRegistration() == nil {
+		return nil, errors.New("acme: cannot update the external account binding of a nil client or user")
+	}
+
+	if options.Kid == "" || options.HmacEncoded == "" {
+		return nil, errors.New("acme: the key ID and the HMAC key of the external account binding are required")
 	}
 
 	accountURL := r.user.GetRegistration().URI
 
-	account, err := r.core.Accounts.Update(accountURL, accMsg)
+	log.Infof("acme: Updating the external account binding of %s", accountURL)
+
+	account, err := r.core.Accounts.UpdateEAB(accountURL, options.Kid, options.HmacEncoded)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("acme: could not update the external account binding (the CA may not support it): %w", err)
 	}
 
 	return &Resource{URI: accountURL, Body: account}, nil
 }
 
+// ChangeAccountKey replaces the key of the account by a new key (key rollover).
+// The client uses the new key for the next requests:
+// the caller must store the new key, and the user must return it for the next clients.
+// https://www.rfc-editor.org/rfc/rfc8555.html#section-7.3.5
+func (r *Registrar) ChangeAccountKey(newKey crypto.PrivateKey) error {
+	if r == nil || r.user == nil || r.user.GetRegistration() == nil {
+		return errors.New("acme: cannot change the key of a nil client or user")
+	}
+
+	if newKey == nil {
+		return errors.New("acme: the new account key is nil")
+	}
+
+	accountURL := r.user.GetRegistration().URI
+
+	log.Infof("acme: Changing the key of the account %s", accountURL)
+
+	err := r.core.Accounts.ChangeKey(accountURL, newKey)
+	if err != nil {
+		return fmt.Errorf("acme: could not change the account key: %w", err)
+	}
+
+	return nil
+}
+
+// ListOrders returns the URLs of the orders of the account, as known by the ACME server.
+// The server may only list the pending orders.
+func (r *Registrar) ListOrders() ([]string, error) {
+	if r == nil || r.user == nil || r.user.GetRegistration() == nil {
+		return nil, errors.New("acme: cannot list the orders of a nil client or user")
+	}
+
+	ordersURL := r.user.GetRegistration().Body.Orders
+
+	if ordersURL == "" {
+		account, err := r.core.Accounts.Get(r.user.GetRegistration().URI)
+		if err != nil {
+			return nil, err
+		}
+
+		ordersURL = account.Orders
+	}
+
+	if ordersURL == "" {
+		return nil, errors.New("acme: the ACME server doesn't provide the list of the orders of the account")
+	}
+
+	return r.core.Accounts.ListOrders(ordersURL)
+}
+
 // DeleteRegistration deletes the client's user registration from the ACME server.
 func (r *Registrar) DeleteRegistration() error {
 	if r == nil || r.user == nil {
@@ -168,3 +279,52 @@ func (r *Registrar) ResolveAccountByKey() (*Resource, error) {
 
 	return &Resource{URI: account.Location, Body: account.Account}, nil
 }
+
+// ResolveAccountByKeyID looks up the account of the account key (like ResolveAccountByKey),
+// and checks that the account matches the account URL (also known as the key ID, `kid`).
+// An empty account URL accepts any account.
+func (r *Registrar) ResolveAccountByKeyID(accountURL string) (*Resource, error) {
+	reg, err := r.ResolveAccountByKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if accountURL != "" && strings.TrimSuffix(reg.URI, "/") != strings.TrimSuffix(accountURL, "/") {
+		return nil, fmt.Errorf("acme: the account key belongs to the account %s, not to %s", reg.URI, accountURL)
+	}
+
+	return reg, nil
+}
+
+func (r *Registrar) checkContact() error {
+	if r.requirements.Contact && r.user.GetEmail() == "" {
+		return fmt.Errorf("acme: %s requires an email address for the account", r.caName())
+	}
+
+	return nil
+}
+
+// contacts returns the contacts of the account: the "mailto:" URI of the email address of the user, if any.
+func (r *Registrar) contacts() ([]string, error) {
+	email := r.user.GetEmail()
+	if email == "" {
+		return []string{}, nil
+	}
+
+	log.Infof("acme: Registering account for %s", email)
+
+	contact, err := mailtoContact(email)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{contact}, nil
+}
+
+func (r *Registrar) caName() string {
+	if r.requirements.CAName == "" {
+		return "the CA"
+	}
+
+	return r.requirements.CAName
+}
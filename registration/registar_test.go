@@ -9,6 +9,7 @@ import (
 
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/platform/tester"
 	"github.com/go-acme/lego/v4/platform/tester/servermock"
 	"github.com/stretchr/testify/assert"
@@ -45,3 +46,203 @@ func TestRegistrar_ResolveAccountByKey(t *testing.T) {
 
 	assert.Equal(t, "valid", res.Body.Status, "Unexpected account status")
 }
+
+func TestRegistrar_ListOrders(t *testing.T) {
+	server := tester.MockACMEServer().
+		Route("POST /account",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				servermock.JSONEncode(acme.Account{
+					Status: "valid",
+					Orders: fmt.Sprintf("https://%s/orders", req.Context().Value(http.LocalAddrContextKey)),
+				}).ServeHTTP(rw, req)
+			})).
+		Route("POST /orders",
+			servermock.JSONEncode(acme.OrdersList{Orders: []string{"https://example.com/order/1"}})).
+		BuildHTTPS(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	user := mockUser{
+		email:      "test@test.com",
+		regres:     &Resource{URI: server.URL + "/account"},
+		privatekey: key,
+	}
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", key)
+	require.NoError(t, err)
+
+	registrar := NewRegistrar(core, user)
+
+	orders, err := registrar.ListOrders()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"https://example.com/order/1"}, orders)
+}
+
+func TestRegistrar_Register_requirements(t *testing.T) {
+	server := tester.MockACMEServer().
+		Route("POST /account",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Location",
+					fmt.Sprintf("http://%s/account", req.Context().Value(http.LocalAddrContextKey)))
+
+				servermock.JSONEncode(acme.Account{Status: "valid"}).ServeHTTP(rw, req)
+			})).
+		BuildHTTPS(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", key)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc         string
+		email        string
+		requirements Requirements
+		expected     string
+	}{
+		{
+			desc:  "no requirements",
+			email: "",
+		},
+		{
+			desc:         "contact required",
+			email:        "",
+			requirements: Requirements{CAName: "Example CA", Contact: true},
+			expected:     "acme: Example CA requires an email address for the account",
+		},
+		{
+			desc:         "contact provided",
+			email:        "test@test.com",
+			requirements: Requirements{CAName: "Example CA", Contact: true},
+		},
+		{
+			desc:         "EAB required",
+			email:        "test@test.com",
+			requirements: Requirements{EAB: true},
+			expected:     "acme: the CA requires an External Account Binding (EAB)",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			registrar := NewRegistrar(core, mockUser{email: test.email, privatekey: key})
+			registrar.SetRequirements(test.requirements)
+
+			res, err := registrar.Register(RegisterOptions{TermsOfServiceAgreed: true})
+			if test.expected != "" {
+				require.EqualError(t, err, test.expected)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Contains(t, res.URI, "/account")
+		})
+	}
+}
+
+func TestRegistrar_ResolveAccountByKeyID(t *testing.T) {
+	server := tester.MockACMEServer().
+		Route("/account",
+			http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Location",
+					fmt.Sprintf("http://%s/account/1", req.Context().Value(http.LocalAddrContextKey)))
+
+				servermock.JSONEncode(acme.Account{Status: "valid"}).ServeHTTP(rw, req)
+			})).
+		BuildHTTPS(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", key)
+	require.NoError(t, err)
+
+	registrar := NewRegistrar(core, mockUser{email: "test@test.com", regres: &Resource{}, privatekey: key})
+
+	res, err := registrar.ResolveAccountByKeyID("")
+	require.NoError(t, err)
+
+	accountURL := res.URI
+
+	res, err = registrar.ResolveAccountByKeyID(accountURL + "/")
+	require.NoError(t, err)
+
+	assert.Equal(t, accountURL, res.URI)
+	assert.Equal(t, "valid", res.Body.Status)
+
+	_, err = registrar.ResolveAccountByKeyID("https://ca.example.com/account/2")
+	require.EqualError(t, err, "acme: the account key belongs to the account "+accountURL+", not to https://ca.example.com/account/2")
+}
+
+func TestRegistrar_UpdateExternalAccountBinding(t *testing.T) {
+	server := tester.MockACMEServer().
+		Route("POST /account",
+			servermock.JSONEncode(acme.Account{Status: "valid"})).
+		BuildHTTPS(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	user := mockUser{
+		email:      "test@test.com",
+		regres:     &Resource{URI: server.URL + "/account"},
+		privatekey: key,
+	}
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", "", key)
+	require.NoError(t, err)
+
+	registrar := NewRegistrar(core, user)
+
+	_, err = registrar.UpdateExternalAccountBinding(RegisterEABOptions{Kid: "kid"})
+	require.EqualError(t, err, "acme: the key ID and the HMAC key of the external account binding are required")
+
+	res, err := registrar.UpdateExternalAccountBinding(RegisterEABOptions{
+		Kid:         "kid",
+		HmacEncoded: "nKTo9Hu8fpCqWPXx-25LVbZrJWxcHISsr4qHrRR0j5U=",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, server.URL+"/account", res.URI)
+	assert.Equal(t, "valid", res.Body.Status)
+}
+
+func TestRegistrar_ChangeAccountKey(t *testing.T) {
+	server := tester.MockACMEServer().
+		Route("POST /keyChange", servermock.Noop()).
+		BuildHTTPS(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	newKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	user := mockUser{
+		email:      "test@test.com",
+		regres:     &Resource{URI: server.URL + "/account"},
+		privatekey: key,
+	}
+
+	core, err := api.New(server.Client(), "lego-test", server.URL+"/dir", server.URL+"/account", key)
+	require.NoError(t, err)
+
+	registrar := NewRegistrar(core, user)
+
+	err = registrar.ChangeAccountKey(nil)
+	require.EqualError(t, err, "acme: the new account key is nil")
+
+	err = registrar.ChangeAccountKey(newKey)
+	require.NoError(t, err)
+
+	thumbprint, err := core.GetKeyThumbprint()
+	require.NoError(t, err)
+
+	expected, err := challenge.Thumbprint(newKey)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, thumbprint)
+}
@@ -0,0 +1,193 @@
+package storage
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/registration"
+)
+
+// Account the content of an account file.
+// It implements the registration.User interface.
+type Account struct {
+	Email        string                 `json:"email"`
+	Registration *registration.Resource `json:"registration"`
+
+	// The private key of the account (stored in a separate file).
+	Key crypto.PrivateKey `json:"-"`
+}
+
+// GetEmail returns the email address for the account.
+func (a *Account) GetEmail() string {
+	return a.Email
+}
+
+// GetPrivateKey returns the private account key.
+func (a *Account) GetPrivateKey() crypto.PrivateKey {
+	return a.Key
+}
+
+// GetRegistration returns the server registration.
+func (a *Account) GetRegistration() *registration.Resource {
+	return a.Registration
+}
+
+// AccountLocation the location of an account in the storage.
+type AccountLocation struct {
+	// Server the directory of the CA server (ex: `acme-v02.api.letsencrypt.org`, `localhost_14000`).
+	Server string
+	// UserID the email of the account, or a placeholder.
+	UserID string
+}
+
+// NewAccountLocation creates the location of an account from the URL of the directory of the CA server.
+func NewAccountLocation(serverURL, userID string) (AccountLocation, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return AccountLocation{}, err
+	}
+
+	return AccountLocation{
+		Server: strings.NewReplacer(":", "_", "/", string(os.PathSeparator)).Replace(u.Host),
+		UserID: userID,
+	}, nil
+}
+
+// Accounts the accounts' storage.
+//
+// RootPath:
+//
+//	./.lego/accounts/
+//	     │      └── root accounts directory
+//	     └── "path" option
+type Accounts struct {
+	RootPath string
+}
+
+// NewAccounts creates a new Accounts from the base path ("path" option).
+func NewAccounts(basePath string) *Accounts {
+	return &Accounts{RootPath: filepath.Join(basePath, AccountsDirName)}
+}
+
+// UserPath returns the directory of an account.
+//
+//	./.lego/accounts/localhost_14000/foo@example.com/
+//	     │      │             │             └── userID ("email" option)
+//	     │      │             └── CA server ("server" option)
+//	     │      └── root accounts directory
+//	     └── "path" option
+func (s *Accounts) UserPath(loc AccountLocation) string {
+	return filepath.Join(s.RootPath, loc.Server, loc.UserID)
+}
+
+// KeysPath returns the directory of the keys of an account.
+func (s *Accounts) KeysPath(loc AccountLocation) string {
+	return filepath.Join(s.UserPath(loc), KeysDirName)
+}
+
+// AccountFilePath returns the path of the account file.
+func (s *Accounts) AccountFilePath(loc AccountLocation) string {
+	return filepath.Join(s.UserPath(loc), AccountFileName)
+}
+
+// KeyFilePath returns the path of the private key of an account.
+func (s *Accounts) KeyFilePath(loc AccountLocation) string {
+	return filepath.Join(s.KeysPath(loc), loc.UserID+ExtKey)
+}
+
+// List returns the locations of the accounts.
+func (s *Accounts) List() ([]AccountLocation, error) {
+	matches, err := filepath.Glob(filepath.Join(s.RootPath, "*", "*", AccountFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	var locations []AccountLocation
+
+	for _, match := range matches {
+		userPath := filepath.Dir(match)
+
+		locations = append(locations, AccountLocation{
+			Server: filepath.Base(filepath.Dir(userPath)),
+			UserID: filepath.Base(userPath),
+		})
+	}
+
+	return locations, nil
+}
+
+// ReadAccount reads the account file.
+// The private key is not loaded (see ReadPrivateKey).
+func (s *Accounts) ReadAccount(loc AccountLocation) (*Account, error) {
+	raw, err := os.ReadFile(s.AccountFilePath(loc))
+	if err != nil {
+		return nil, err
+	}
+
+	var account Account
+
+	err = json.Unmarshal(raw, &account)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal the account %s: %w", loc.UserID, err)
+	}
+
+	return &account, nil
+}
+
+// SaveAccount writes the account file.
+func (s *Accounts) SaveAccount(loc AccountLocation, account *Account) error {
+	jsonBytes, err := json.MarshalIndent(account, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.AccountFilePath(loc), jsonBytes, FilePerm)
+}
+
+// ReadPrivateKey reads the private key of an account.
+func (s *Accounts) ReadPrivateKey(loc AccountLocation) (crypto.PrivateKey, error) {
+	keyBytes, err := os.ReadFile(s.KeyFilePath(loc))
+	if err != nil {
+		return nil, err
+	}
+
+	defer certcrypto.Zeroize(keyBytes)
+
+	return certcrypto.ParsePEMPrivateKey(keyBytes)
+}
+
+// ReadLastUsed reads the date of the last use of an account.
+// The accounts created before the tracking of the last use fall back to the modification date of the account file.
+func (s *Accounts) ReadLastUsed(loc AccountLocation) (time.Time, error) {
+	userPath := s.UserPath(loc)
+
+	raw, err := os.ReadFile(filepath.Join(userPath, AccountLastUsedFileName))
+	if err == nil {
+		return time.Parse(time.RFC3339, strings.TrimSpace(string(raw)))
+	}
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		return time.Time{}, err
+	}
+
+	info, err := os.Stat(filepath.Join(userPath, AccountFileName))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return info.ModTime(), nil
+}
+
+// SaveLastUsed writes the date of the last use of an account.
+func (s *Accounts) SaveLastUsed(loc AccountLocation, lastUsed time.Time) error {
+	return os.WriteFile(filepath.Join(s.UserPath(loc), AccountLastUsedFileName), []byte(lastUsed.UTC().Format(time.RFC3339)), FilePerm)
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/registration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAccountLocation(t *testing.T) {
+	location, err := NewAccountLocation("https://localhost:14000/dir", "foo@example.com")
+	require.NoError(t, err)
+
+	assert.Equal(t, AccountLocation{Server: "localhost_14000", UserID: "foo@example.com"}, location)
+
+	accounts := NewAccounts("lego")
+
+	assert.Equal(t, filepath.Join("lego", "accounts", "localhost_14000", "foo@example.com"), accounts.UserPath(location))
+	assert.Equal(t, filepath.Join("lego", "accounts", "localhost_14000", "foo@example.com", "account.json"), accounts.AccountFilePath(location))
+	assert.Equal(t, filepath.Join("lego", "accounts", "localhost_14000", "foo@example.com", "keys", "foo@example.com.key"), accounts.KeyFilePath(location))
+}
+
+func TestAccounts_SaveAccount(t *testing.T) {
+	accounts := NewAccounts(t.TempDir())
+
+	location := AccountLocation{Server: "localhost_14000", UserID: "foo@example.com"}
+
+	require.NoError(t, CreateNonExistingFolder(accounts.KeysPath(location)))
+
+	account := &Account{
+		Email: "foo@example.com",
+		Registration: &registration.Resource{
+			URI:  "https://localhost:14000/my-account/1",
+			Body: acme.Account{Status: acme.StatusValid},
+		},
+	}
+
+	err := accounts.SaveAccount(location, account)
+	require.NoError(t, err)
+
+	stored, err := accounts.ReadAccount(location)
+	require.NoError(t, err)
+
+	assert.Equal(t, account, stored)
+
+	locations, err := accounts.List()
+	require.NoError(t, err)
+
+	assert.Equal(t, []AccountLocation{location}, locations)
+}
+
+func TestAccounts_ReadLastUsed(t *testing.T) {
+	accounts := NewAccounts(t.TempDir())
+
+	location := AccountLocation{Server: "localhost_14000", UserID: "foo@example.com"}
+
+	require.NoError(t, CreateNonExistingFolder(accounts.UserPath(location)))
+
+	err := os.WriteFile(accounts.AccountFilePath(location), []byte("{}"), FilePerm)
+	require.NoError(t, err)
+
+	// Fallback to the modification date of the account file.
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, os.Chtimes(accounts.AccountFilePath(location), modTime, modTime))
+
+	lastUsed, err := accounts.ReadLastUsed(location)
+	require.NoError(t, err)
+
+	assert.True(t, modTime.Equal(lastUsed))
+
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, accounts.SaveLastUsed(location, now))
+
+	lastUsed, err = accounts.ReadLastUsed(location)
+	require.NoError(t, err)
+
+	assert.Equal(t, now, lastUsed)
+}
@@ -0,0 +1,211 @@
+package storage
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
+)
+
+// Certificates the certificates' storage.
+//
+// RootPath:
+//
+//	./.lego/certificates/
+//	     │      └── root certificates directory
+//	     └── "path" option
+//
+// ArchivePath:
+//
+//	./.lego/archives/
+//	     │      └── archived certificates directory
+//	     └── "path" option
+type Certificates struct {
+	RootPath    string
+	ArchivePath string
+}
+
+// NewCertificates creates a new Certificates from the base path ("path" option).
+func NewCertificates(basePath string) *Certificates {
+	return &Certificates{
+		RootPath:    filepath.Join(basePath, CertificatesDirName),
+		ArchivePath: filepath.Join(basePath, ArchivesDirName),
+	}
+}
+
+// FilePath returns the path of a file of a certificate.
+// The name is the domain of the certificate (ex: `*.example.com`), or the name of the certificate.
+func (s *Certificates) FilePath(name, extension string) (string, error) {
+	safe, err := SanitizedDomain(name)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(s.RootPath, safe+extension), nil
+}
+
+// Exists checks if a file of a certificate exists.
+func (s *Certificates) Exists(name, extension string) (bool, error) {
+	filePath, err := s.FilePath(name, extension)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = os.Stat(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ReadFile reads a file of a certificate.
+func (s *Certificates) ReadFile(name, extension string) ([]byte, error) {
+	filePath, err := s.FilePath(name, extension)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(filePath)
+}
+
+// WriteFile writes a file of a certificate.
+func (s *Certificates) WriteFile(name, extension string, data []byte) error {
+	filePath, err := s.FilePath(name, extension)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filePath, data, FilePerm)
+}
+
+// ReadCertificates reads the certificates of a file (the file may be a bundle or a single certificate).
+func (s *Certificates) ReadCertificates(name, extension string) ([]*x509.Certificate, error) {
+	content, err := s.ReadFile(name, extension)
+	if err != nil {
+		return nil, err
+	}
+
+	return certcrypto.ParsePEMBundle(content)
+}
+
+// ReadResource reads the resource (metadata) of a certificate.
+// The PEM fields of the resource are not filled.
+func (s *Certificates) ReadResource(name string) (*certificate.Resource, error) {
+	raw, err := s.ReadFile(name, ExtResource)
+	if err != nil {
+		return nil, err
+	}
+
+	var resource certificate.Resource
+
+	err = json.Unmarshal(raw, &resource)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal the resource of %s: %w", name, err)
+	}
+
+	return &resource, nil
+}
+
+// SaveResource writes the certificate, the issuer certificate, the private key, and the resource (metadata) of a certificate.
+func (s *Certificates) SaveResource(name string, certRes *certificate.Resource) error {
+	err := s.WriteFile(name, ExtCertificate, certRes.Certificate)
+	if err != nil {
+		return fmt.Errorf("save the certificate of %s: %w", name, err)
+	}
+
+	if certRes.IssuerCertificate != nil {
+		err = s.WriteFile(name, ExtIssuer, certRes.IssuerCertificate)
+		if err != nil {
+			return fmt.Errorf("save the issuer certificate of %s: %w", name, err)
+		}
+	}
+
+	if certRes.PrivateKey != nil {
+		err = s.WriteFile(name, ExtKey, certRes.PrivateKey)
+		if err != nil {
+			return fmt.Errorf("save the private key of %s: %w", name, err)
+		}
+	}
+
+	jsonBytes, err := json.MarshalIndent(certRes, "", "\t")
+	if err != nil {
+		return fmt.Errorf("marshal the resource of %s: %w", name, err)
+	}
+
+	err = s.WriteFile(name, ExtResource, jsonBytes)
+	if err != nil {
+		return fmt.Errorf("save the resource of %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// List returns the names of the certificates (the file names of the certificates without the extension).
+func (s *Certificates) List() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(s.RootPath, "*"+ExtCertificate))
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+
+	for _, match := range matches {
+		if strings.HasSuffix(match, ExtIssuer) {
+			continue
+		}
+
+		names = append(names, strings.TrimSuffix(filepath.Base(match), ExtCertificate))
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// Archive moves the files of a certificate to the archives directory.
+// The names of the archived files are prefixed by the date (ex: `1700000000.example.com.crt`).
+func (s *Certificates) Archive(name string, now time.Time) error {
+	safe, err := SanitizedDomain(name)
+	if err != nil {
+		return err
+	}
+
+	baseFilename := filepath.Join(s.RootPath, safe)
+
+	matches, err := filepath.Glob(baseFilename + ".*")
+	if err != nil {
+		return err
+	}
+
+	date := strconv.FormatInt(now.Unix(), 10)
+
+	for _, oldFile := range matches {
+		if strings.TrimSuffix(oldFile, filepath.Ext(oldFile)) != baseFilename && oldFile != baseFilename+ExtIssuer && oldFile != baseFilename+ExtPins {
+			continue
+		}
+
+		newFile := filepath.Join(s.ArchivePath, date+"."+filepath.Base(oldFile))
+
+		err = os.Rename(oldFile, newFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertificates_FilePath(t *testing.T) {
+	certificates := NewCertificates("lego")
+
+	testCases := []struct {
+		desc     string
+		name     string
+		expected string
+	}{
+		{
+			desc:     "domain",
+			name:     "example.com",
+			expected: filepath.Join("lego", "certificates", "example.com.crt"),
+		},
+		{
+			desc:     "wildcard",
+			name:     "*.example.com",
+			expected: filepath.Join("lego", "certificates", "_.example.com.crt"),
+		},
+		{
+			desc:     "IDN",
+			name:     "bücher.example",
+			expected: filepath.Join("lego", "certificates", "xn--bcher-kva.example.crt"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			filePath, err := certificates.FilePath(test.name, ExtCertificate)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, filePath)
+		})
+	}
+}
+
+func TestCertificates_SaveResource(t *testing.T) {
+	certificates := NewCertificates(t.TempDir())
+
+	require.NoError(t, CreateNonExistingFolder(certificates.RootPath))
+
+	resource := &certificate.Resource{
+		Domain:            "*.example.com",
+		CertURL:           "https://ca.example.com/cert/1",
+		Certificate:       []byte("cert"),
+		IssuerCertificate: []byte("issuer"),
+		PrivateKey:        []byte("key"),
+	}
+
+	err := certificates.SaveResource(resource.Domain, resource)
+	require.NoError(t, err)
+
+	for ext, expected := range map[string]string{ExtCertificate: "cert", ExtIssuer: "issuer", ExtKey: "key"} {
+		content, err := certificates.ReadFile(resource.Domain, ext)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, string(content))
+	}
+
+	exists, err := certificates.Exists(resource.Domain, ExtPFX)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	stored, err := certificates.ReadResource(resource.Domain)
+	require.NoError(t, err)
+
+	expected := &certificate.Resource{
+		Domain:  "*.example.com",
+		CertURL: "https://ca.example.com/cert/1",
+	}
+
+	assert.Equal(t, expected, stored)
+
+	names, err := certificates.List()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"_.example.com"}, names)
+}
+
+func TestCertificates_Archive(t *testing.T) {
+	certificates := NewCertificates(t.TempDir())
+
+	require.NoError(t, CreateNonExistingFolder(certificates.RootPath))
+	require.NoError(t, CreateNonExistingFolder(certificates.ArchivePath))
+
+	for _, name := range []string{"example.com", "example.com.example.org"} {
+		for _, ext := range []string{ExtCertificate, ExtIssuer, ExtKey, ExtPins, ExtResource} {
+			require.NoError(t, certificates.WriteFile(name, ext, []byte(name)))
+		}
+	}
+
+	err := certificates.Archive("example.com", time.Unix(1700000000, 0))
+	require.NoError(t, err)
+
+	archived, err := os.ReadDir(certificates.ArchivePath)
+	require.NoError(t, err)
+
+	var archivedNames []string
+	for _, entry := range archived {
+		archivedNames = append(archivedNames, entry.Name())
+	}
+
+	expected := []string{
+		"1700000000.example.com.crt",
+		"1700000000.example.com.issuer.crt",
+		"1700000000.example.com.json",
+		"1700000000.example.com.key",
+		"1700000000.example.com.pins.json",
+	}
+
+	assert.Equal(t, expected, archivedNames)
+
+	names, err := certificates.List()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"example.com.example.org"}, names)
+}
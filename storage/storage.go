@@ -0,0 +1,76 @@
+// Package storage implements the on-disk layout of the lego CLI (the "path" option).
+//
+//	./.lego/
+//	     ├── accounts/
+//	     │      └── acme-v02.api.letsencrypt.org/
+//	     │             └── foo@example.com/
+//	     │                    ├── account.json
+//	     │                    ├── last_used
+//	     │                    └── keys/
+//	     │                           └── foo@example.com.key
+//	     ├── archives/
+//	     │      └── 1700000000.example.com.crt
+//	     └── certificates/
+//	            ├── example.com.crt
+//	            ├── example.com.issuer.crt
+//	            ├── example.com.json
+//	            └── example.com.key
+//
+// The layout is a stable API: the tools built around the files of the CLI (dashboards, exporters, etc.)
+// should use this package instead of relying on the paths.
+package storage
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// Directory names.
+const (
+	AccountsDirName     = "accounts"
+	KeysDirName         = "keys"
+	CertificatesDirName = "certificates"
+	ArchivesDirName     = "archives"
+)
+
+// File names.
+const (
+	AccountFileName         = "account.json"
+	AccountLastUsedFileName = "last_used"
+)
+
+// Certificate file extensions.
+const (
+	ExtIssuer      = ".issuer.crt"
+	ExtCertificate = ".crt"
+	ExtKey         = ".key"
+	ExtPEM         = ".pem"
+	ExtPFX         = ".pfx"
+	ExtJKS         = ".jks"
+	ExtPins        = ".pins.json"
+	ExtResource    = ".json"
+)
+
+// FilePerm the permissions of the files.
+const FilePerm os.FileMode = 0o600
+
+// DirPerm the permissions of the directories.
+const DirPerm os.FileMode = 0o700
+
+// SanitizedDomain returns the name used by the files of a domain (ex: `*.example.com` -> `_.example.com`).
+func SanitizedDomain(domain string) (string, error) {
+	return idna.ToASCII(strings.NewReplacer(":", "-", "*", "_").Replace(domain))
+}
+
+// CreateNonExistingFolder creates a directory, and its parents, if it doesn't exist.
+func CreateNonExistingFolder(path string) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return os.MkdirAll(path, DirPerm)
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}